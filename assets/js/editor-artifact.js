@@ -608,26 +608,6 @@ import {
   if (github === null || githubReset === null) {
     throw new Error('A GitHub input is missing.');
   }
-  const relations = document.getElementById('artifact-editor-relations');
-  const relationsReset = document.getElementById(
-    'artifact-editor-relations-reset'
-  );
-  if (relations === null || relationsReset === null) {
-    throw new Error('A relations input is missing.');
-  }
-  const websites = document.getElementById('artifact-editor-websites');
-  const websitesReset = document.getElementById(
-    'artifact-editor-websites-reset'
-  );
-  if (websites === null || websitesReset === null) {
-    throw new Error('A websites input is missing.');
-  }
-  // on paste event for websites remove any http:// or https:// protcols
-  websites.addEventListener('paste', () => {
-    setTimeout(() => {
-      websites.value = websites.value.replace(/https?:\/\//, '');
-    }, 0);
-  });
 
   linksReset.addEventListener('click', () => {
     youtube.classList.remove('is-invalid', 'is-valid');
@@ -635,15 +615,11 @@ import {
     pouet.classList.remove('is-invalid', 'is-valid');
     colors16.classList.remove('is-invalid', 'is-valid');
     github.classList.remove('is-invalid', 'is-valid');
-    relations.classList.remove('is-invalid', 'is-valid');
-    websites.classList.remove('is-invalid', 'is-valid');
     youtube.value = youtubeReset.value;
     demozoo.value = demozooReset.value;
     pouet.value = pouetReset.value;
     colors16.value = colors16Reset.value;
     github.value = githubReset.value;
-    relations.value = relationsReset.value;
-    websites.value = websitesReset.value;
   });
   const demozooSanity = 450000,
     pouetSanity = 200000;
@@ -676,5 +652,4 @@ import {
     }, 0);
   });
   github.addEventListener('input', (e) => validateGitHub(e.target));
-  // relations and websites are optional
 })();
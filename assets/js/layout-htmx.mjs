@@ -116,8 +116,6 @@ export function htmxEvents() {
     afterUpdate(event, `artifact-editor-pouet`);
     afterUpdate(event, `artifact-editor-16colors`);
     afterUpdate(event, `artifact-editor-github`);
-    afterUpdate(event, `artifact-editor-relations`);
-    afterUpdate(event, `artifact-editor-websites`);
     afterLinks(event, `artifact-editor-links-reset`);
   });
 }
@@ -168,6 +168,37 @@ To enable quiet mode:
 
 	D2_QUIET=true
 
+# Native image pipeline
+
+The native image pipeline creates the previews and thumbnails of the artifacts using Go,
+in place of the ImageMagick, cwebp, gif2webp and optipng commands, which allows a minimal installation.
+As Go cannot encode WebP images, PNG is the output format of the native pipeline. The thumbnails and
+the previews of the lossless images are saved as PNG images, while the previews of the photos are saved
+as JPEG images when they are smaller. An uploaded WebP image is copied as the preview without a change,
+but a cropped or pixelated WebP preview is replaced by a PNG image.
+The text previews of the ANSI, BIN and XBin files are always rendered natively.
+
+To enable the native image pipeline:
+
+	D2_NATIVE_IMAGES=true
+
+# Download checksums
+
+The downloads include a strong ETag and the SHA-384 Digest and Repr-Digest headers of the file.
+Each download also has a checksum sidecar, by appending ".sha384" to the download URL.
+A manifest of the checksums of every public download is served at /sha384sums,
+along with an Ed25519 signature at /sha384sums.sig and the public key to verify it at /sha384sums.pub.
+
+The manifest is signed using the D2_SIGNING_KEY variable, a base64 encoded, 32 byte Ed25519 private key seed.
+If no key is provided or the key is invalid, the manifest is not signed
+and the signature and public key URLs are not found.
+
+The ETag of the manifest is its SHA-384 checksum, which also versions the signature.
+To fetch the signature that matches a fetched manifest, pass the ETag using
+either the If-Match header or the v query parameter, for example /sha384sums.sig?v=etag.
+
+	D2_SIGNING_KEY=someBase64SeedGoesHere
+
 # HTTP and HTTPS
 
 The web server will listen to all HTTP requests on port 1323 without configuration.
@@ -0,0 +1,146 @@
+package flags
+
+// Package file export.go contains the export command that writes the public catalogue to a portable file.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Defacto2/server/handler/app"
+	"github.com/Defacto2/server/internal/config"
+	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/export"
+	"github.com/Defacto2/server/internal/postgres"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	ErrCategory = errors.New("export category is not an artifacts category")
+	ErrStdout   = errors.New("export format cannot be written to stdout, use the output flag")
+)
+
+// Export command writes the public catalogue of artifacts to a JSON Lines, CSV or SQLite file.
+func Export(w io.Writer, c *config.Config) *cli.Command {
+	const format = "export command: %w"
+	//nolint:exhaustruct // External library struct with many optional fields
+	return &cli.Command{
+		Name:    "export",
+		Aliases: []string{"e"},
+		Usage:   "export the public catalogue of artifacts",
+		Description: "Export the public artifact records, including the releasers, tags and relations,\n" +
+			"to a JSON Lines, CSV or SQLite file that can be used offline or as a database backup.\n" +
+			"The JSON Lines and CSV formats are written to stdout unless an output file is given.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "format",
+				Aliases: []string{"f"},
+				Value:   string(export.JSONL),
+				Usage:   "file format of the export, " + strings.Join(export.Formats(), ", "),
+			},
+			&cli.StringFlag{
+				Name:    "output",
+				Aliases: []string{"o"},
+				Usage:   "path of the file to create or replace, or - for stdout",
+			},
+			&cli.StringFlag{
+				Name:    "category",
+				Aliases: []string{"c"},
+				Usage:   "only export the artifacts of the category URI, such as demoscene or text-amiga",
+			},
+			&cli.BoolFlag{
+				Name:  "checksums",
+				Usage: "include the SHA-384 checksums of the artifact downloads",
+			},
+			&cli.BoolFlag{
+				Name:  "assets",
+				Usage: "include the paths of the download, preview and thumbnail files",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			if err := exportCmd(ctx, w, c); err != nil {
+				return fmt.Errorf(format, err)
+			}
+			return nil
+		},
+	}
+}
+
+func exportCmd(ctx *cli.Context, w io.Writer, c *config.Config) error {
+	if c == nil {
+		return ErrNoConfig
+	}
+	format := export.Format(strings.ToLower(ctx.String("format")))
+	if !format.Valid() {
+		return fmt.Errorf("%w: %q", export.ErrFormat, format)
+	}
+	var mods []qm.QueryMod
+	if uri := ctx.String("category"); uri != "" {
+		expr, ok := app.Category(uri)
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrCategory, uri)
+		}
+		mods = append(mods, expr)
+	}
+	opt := export.Options{
+		Checksums: ctx.Bool("checksums"),
+		Assets:    ctx.Bool("assets"),
+		Download:  dir.Directory(c.AbsDownload),
+		Preview:   dir.Directory(c.AbsPreview),
+		Thumbnail: dir.Directory(c.AbsThumbnail),
+	}
+	name := ctx.String("output")
+	stdout := name == "" || name == "-"
+	if stdout && format == export.SQLite {
+		return ErrStdout
+	}
+	if w == nil {
+		w = os.Stdout
+	}
+	var file *os.File
+	if !stdout {
+		var err error
+		if file, err = os.Create(name); err != nil {
+			return fmt.Errorf("create: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+		w = file
+	}
+	var ew export.Writer
+	switch format {
+	case export.CSV:
+		ew = export.NewCSV(w, opt)
+	case export.SQLite:
+		ew = export.NewSQLite(file, opt)
+	default:
+		ew = export.NewJSONL(w)
+	}
+	db, err := postgres.Open()
+	if err != nil {
+		return fmt.Errorf("database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	start := time.Now()
+	count, err := export.Catalogue(context.Background(), db, ew, opt, mods...)
+	if err != nil {
+		return fmt.Errorf("%d records: %w", count, err)
+	}
+	if file != nil {
+		if err := file.Sync(); err != nil {
+			return fmt.Errorf("sync: %w", err)
+		}
+		name = file.Name()
+	} else {
+		name = "stdout"
+	}
+	// log writes to stderr, so it does not mix with an export to stdout
+	log.Printf("Exported %d artifacts as %s to %s in %s\n",
+		count, format, name, time.Since(start).Round(time.Millisecond))
+	return nil
+}
@@ -66,6 +66,8 @@ func App(w io.Writer, ver string, c *config.Config) *cli.App {
 			Config(w, c),
 			Address(w, c),
 			Fix(w, c),
+			Export(w, c),
+			Import(w, c),
 		},
 	}
 	return app
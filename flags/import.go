@@ -0,0 +1,101 @@
+package flags
+
+// Package file import.go contains the import command that adds a batch of artifacts from a directory.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/Defacto2/server/internal/command"
+	"github.com/Defacto2/server/internal/config"
+	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/ingest"
+	"github.com/Defacto2/server/internal/postgres"
+	"github.com/urfave/cli/v2"
+)
+
+// Import command adds a batch of artifacts from a directory using a manifest of their metadata.
+func Import(w io.Writer, c *config.Config) *cli.Command {
+	const format = "import command: %w"
+	//nolint:exhaustruct // External library struct with many optional fields
+	return &cli.Command{
+		Name:    "import",
+		Aliases: []string{"i"},
+		Usage:   "import a batch of artifacts from a directory",
+		Description: "Import the files of a directory as new artifacts that are waiting for approval,\n" +
+			"using a YAML, JSON or CSV manifest of the filename, title, releasers, date, platform,\n" +
+			"section and credits of each file. Files that already exist are skipped, and the\n" +
+			"progress file allows an interrupted import to be run again and resume.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:     "dir",
+				Aliases:  []string{"d"},
+				Usage:    "directory that contains the files to import",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:     "manifest",
+				Aliases:  []string{"m"},
+				Usage:    "path of the .yaml, .json or .csv manifest of the files",
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "progress",
+				Usage: "path of the progress file, the default is the manifest path with a .progress suffix",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "report the files that would be imported without making any changes",
+			},
+		},
+		Action: func(ctx *cli.Context) error {
+			if err := importCmd(ctx, w, c); err != nil {
+				return fmt.Errorf(format, err)
+			}
+			return nil
+		},
+	}
+}
+
+func importCmd(ctx *cli.Context, _ io.Writer, c *config.Config) error {
+	if c == nil {
+		return ErrNoConfig
+	}
+	name := ctx.String("manifest")
+	m, err := ingest.ReadManifest(name)
+	if err != nil {
+		return err
+	}
+	progress := ctx.String("progress")
+	if progress == "" {
+		progress = name + ".progress"
+	}
+	im := ingest.Import{
+		Dir: ctx.String("dir"),
+		Dirs: command.Dirs{
+			Download:  dir.Directory(c.AbsDownload),
+			Preview:   dir.Directory(c.AbsPreview),
+			Thumbnail: dir.Directory(c.AbsThumbnail),
+			Extra:     dir.Directory(c.AbsExtra),
+		},
+		Progress: progress,
+		DryRun:   ctx.Bool("dry-run"),
+	}
+	sl := stdoutput()
+	db, err := postgres.Open()
+	if err != nil {
+		return fmt.Errorf("database: %w", err)
+	}
+	defer func() { _ = db.Close() }()
+	start := time.Now()
+	report, err := im.Run(context.Background(), sl, db, m)
+	if err != nil {
+		return fmt.Errorf("%s: %w", report, err)
+	}
+	log.Printf("Import of %d manifest entries, %s in %s\n",
+		len(m), report, time.Since(start).Round(time.Millisecond))
+	return nil
+}
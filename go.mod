@@ -6,7 +6,6 @@ go 1.26.5 // latest: https://go.dev/dl/
 // INFO: Usage of the packages.
 // Defacto2/ named packages are all internal but kept out of the main application.
 // aarondl/ packages are for sqlboiler and is used for interacting with the database.
-// bengarrett/ansibump
 // bengarrett/bbs handles bbs color encoding text conversions.
 // bengarrett/sauce
 // caarlos0/env simplifies the handling of operating system environment variables.
@@ -26,6 +25,7 @@ go 1.26.5 // latest: https://go.dev/dl/
 // samber/slog-multi permits the writing of a single slog record to multiple writers.
 // subpop/go-ini parses ini settings syntax that is used by the jdos emulation.
 // urface/cli is used with the flags package for command line interactions.
+// yaml.v3 parses the yaml manifests of the import command.
 //
 require (
 	github.com/Defacto2/archive v1.1.8
@@ -34,9 +34,7 @@ require (
 	github.com/aarondl/null/v8 v8.1.3
 	github.com/aarondl/sqlboiler/v4 v4.19.7
 	github.com/aarondl/strmangle v0.0.9
-	github.com/bengarrett/ansibump v1.1.2
 	github.com/bengarrett/bbs v1.0.7
-	github.com/bengarrett/binbump v1.0.2
 	github.com/bengarrett/sauce v1.2.7
 	github.com/caarlos0/env/v11 v11.4.1
 	github.com/carlmjohnson/versioninfo v0.22.5
@@ -57,6 +55,7 @@ require (
 	golang.org/x/image v0.44.0
 	golang.org/x/text v0.40.0
 	google.golang.org/api v0.290.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 // Uncomment to use the local repository
@@ -64,9 +63,7 @@ require (
 //	replace github.com/Defacto2/helper => ../helper
 //	replace github.com/Defacto2/magicnumber => ../magicnumber
 //	replace github.com/Defacto2/releaser => ../releaser
-//	replace github.com/bengarrett/ansibump => ../ansibump
 //	replace github.com/bengarrett/sauce => ../sauce
-//	replace github.com/bengarrett/binbump => ../binbump
 
 // Lock down the markdown package for 'task doc' command compatibility
 replace rsc.io/markdown => rsc.io/markdown v0.0.0-20231214224604-88bb533a6020
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
-	"net/url"
 	"regexp"
 	"slices"
 	"sort"
@@ -27,9 +26,11 @@ import (
 	"github.com/Defacto2/server/handler/releaser"
 	"github.com/Defacto2/server/handler/site"
 	"github.com/Defacto2/server/handler/sixteen"
+	"github.com/Defacto2/server/internal/manifest"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/Defacto2/server/internal/postgres"
 	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/Defacto2/server/internal/sauces"
 	"github.com/Defacto2/server/internal/tags"
 	"github.com/Defacto2/server/model"
 	"github.com/Defacto2/server/model/html3"
@@ -76,6 +77,9 @@ type ArtifactAPI struct {
 	FileMeta      filemetaAPI   `json:"download"`
 	ArtMeta       artmetaAPI    `json:"meta"`
 	Relationships []relationAPI `json:"relationships"`
+	Relations     []graphAPI    `json:"relations,omitempty"`
+	Links         []linkAPI     `json:"links,omitempty"`
+	Keywords      []string      `json:"keywords,omitempty"`
 }
 
 // artifactAPI represents an artifact file summary for API responses.
@@ -122,6 +126,25 @@ type relationAPI struct {
 	Desc string `json:"description"`
 }
 
+// graphAPI represents a typed relation between two artifacts for API responses.
+// The direction is "to" when the relation is from the requested artifact to the other artifact,
+// or "from" when the other artifact is the source of the relation.
+type graphAPI struct {
+	Kind        string `json:"kind"`
+	Direction   string `json:"direction"`
+	Description string `json:"description"`
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	URLs        urlAPI `json:"urls"`
+}
+
+// linkAPI represents a typed external link of an artifact for API responses.
+type linkAPI struct {
+	Kind  string `json:"kind"`
+	Label string `json:"label"`
+	URL   string `json:"url"`
+}
+
 type releaserAPI struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
@@ -131,16 +154,20 @@ type releaserAPI struct {
 
 // EntityAPI represents a group, scener, or releaser for API responses.
 type EntityAPI struct {
-	ID       string    `json:"id"`
-	Name     string    `json:"name"`
-	Title    string    `json:"title"`
-	URLs     enityURLs `json:"urls"`
-	Stats    totalsAPI `json:"statistics"`
-	Websites any       `json:"websites,omitempty"`
-	Sixteen  string    `json:"sixteen,omitempty"`
-	Janeway  string    `json:"janeway,omitempty"`
-	Demozoo  string    `json:"demozoo,omitempty"`
-	Csdb     string    `json:"csdb,omitempty"`
+	ID       string      `json:"id"`
+	Name     string      `json:"name"`
+	Title    string      `json:"title"`
+	URLs     enityURLs   `json:"urls"`
+	Stats    totalsAPI   `json:"statistics"`
+	Websites any         `json:"websites,omitempty"`
+	Sixteen  string      `json:"sixteen,omitempty"`
+	Janeway  string      `json:"janeway,omitempty"`
+	Demozoo  string      `json:"demozoo,omitempty"`
+	Csdb     string      `json:"csdb,omitempty"`
+	Roles    []string    `json:"roles,omitempty"`
+	Aliases  []string    `json:"aliases,omitempty"`
+	Members  []GroupLink `json:"members,omitempty"`
+	Groups   []GroupLink `json:"released_with,omitempty"`
 }
 
 type artifactsAPI struct {
@@ -228,12 +255,21 @@ func ArtifactsNewAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *
 	return ArtifactAPIs(ctx, sl, c, db, "new-uploads")
 }
 
+// ArtifactsPopularAPI returns a list of all files ordered by "popular".
+func ArtifactsPopularAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	return ArtifactAPIs(ctx, sl, c, db, "popular")
+}
+
 // ArtifactAPIs returns a list of all files filtered by the provided uri string.
 func ArtifactAPIs(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, uri string) error {
 	const format = "artifacts api: %w"
 	if err := nils.Check(ctx, sl, c, db); err != nil {
 		return fmt.Errorf(format, err)
 	}
+	// the keyset pagination orders by the id key and cannot be used for the popularity
+	if keysetQuery(c) && uri != "popular" {
+		return artifactsKeyset(ctx, c, db, uri == "new-uploads")
+	}
 	const limit = apiLimit
 	page := 1
 	if s := c.QueryParam(pg); s != "" {
@@ -277,6 +313,231 @@ func ArtifactAPIs(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql
 	})
 }
 
+// keysetParams are the query parameters that switch the artifacts APIs from the page number
+// to the keyset, or cursor, pagination.
+var keysetParams = []string{ //nolint:gochecknoglobals
+	"cursor", "limit", "platform", "section", "category", "year", "releaser", "since", "keyword",
+}
+
+// keysetQuery returns true when any of the keyset pagination parameters are in the request.
+func keysetQuery(c *echo.Context) bool {
+	for _, name := range keysetParams {
+		if c.QueryParam(name) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// artifactsKeyset returns a filtered list of the artifacts using the keyset pagination.
+// The next value of the reply is the cursor parameter for the following page
+// and is empty when there are no more pages.
+func artifactsKeyset(ctx context.Context, c *echo.Context, db *sql.DB, descending bool) error {
+	k := model.Keyset{
+		Platform:   strings.ToLower(c.QueryParam("platform")),
+		Section:    strings.ToLower(c.QueryParam("section")),
+		Releaser:   c.QueryParam("releaser"),
+		Keywords:   keysetKeywords(c.QueryParam("keyword")),
+		Descending: descending,
+	}
+	if k.Section == "" {
+		k.Section = strings.ToLower(c.QueryParam("category"))
+	}
+	limit := apiLimit
+	if s := c.QueryParam("limit"); s != "" {
+		i, err := strconv.Atoi(s)
+		if err != nil || i < 1 || i > apiLimit {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				er: fmt.Sprintf("Invalid limit parameter, use a value between 1 and %d", apiLimit),
+			})
+		}
+		limit = i
+	}
+	var after int64
+	if s := c.QueryParam("cursor"); s != "" {
+		id := helper.DeobfuscateID(s)
+		if id < 1 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				er: "Invalid cursor parameter",
+			})
+		}
+		after = int64(id)
+	}
+	if s := c.QueryParam("year"); s != "" {
+		from, to, err := keysetYears(s)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				er: "Invalid year parameter. Use format: year or start-end",
+			})
+		}
+		k.YearFrom, k.YearTo = from, to
+	}
+	if s := c.QueryParam("since"); s != "" {
+		since, err := keysetSince(s)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				er: "Invalid since parameter. Use an RFC 3339 timestamp or a YYYY-MM-DD date",
+			})
+		}
+		k.Since = since
+	}
+	if err := k.Validate(); err != nil {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			er: err.Error(),
+		})
+	}
+	fs, next, err := k.Page(ctx, db, after, limit)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			er: "Failed to query files",
+		})
+	}
+	count, err := k.Count(ctx, db)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			er: "Failed to query count",
+		})
+	}
+	total, err := model.Count(ctx, db)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			er: "Failed to query count",
+		})
+	}
+	cursor := ""
+	if next > 0 {
+		cursor = helper.ObfuscateID(next)
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		af: artifactSummaries(fs),
+		"statistics": map[string]any{
+			"totalFiles":    total,
+			"matchingFiles": count,
+		},
+		"limit": limit,
+		"next":  cursor,
+	})
+}
+
+// keysetYears parses a single year or a start-end range of years.
+func keysetYears(s string) (int16, int16, error) {
+	start, end, found := strings.Cut(s, "-")
+	if !found {
+		end = start
+	}
+	from, err := strconv.ParseInt(strings.TrimSpace(start), 10, 16)
+	if err != nil {
+		return 0, 0, fmt.Errorf("keyset years %q: %w", s, err)
+	}
+	to, err := strconv.ParseInt(strings.TrimSpace(end), 10, 16)
+	if err != nil {
+		return 0, 0, fmt.Errorf("keyset years %q: %w", s, err)
+	}
+	return int16(from), int16(to), nil
+}
+
+// keysetKeywords splits a comma separated list of keyword slugs.
+func keysetKeywords(s string) []string {
+	var slugs []string
+	for slug := range strings.SplitSeq(s, ",") {
+		slug = strings.ToLower(strings.TrimSpace(slug))
+		if slug != "" {
+			slugs = append(slugs, slug)
+		}
+	}
+	return slugs
+}
+
+// keysetSince parses either an RFC 3339 timestamp or a date.
+func keysetSince(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.DateOnly, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("keyset since %q: %w", s, err)
+	}
+	return t, nil
+}
+
+type changeAPI struct {
+	Seq     int64     `json:"seq"`
+	Kind    string    `json:"kind"`
+	ID      string    `json:"id"`
+	UUID    string    `json:"uuid,omitempty"`
+	Title   string    `json:"title,omitempty"`
+	Changed time.Time `json:"changed"`
+	URLs    *urlAPI   `json:"urls,omitempty"`
+}
+
+// ChangesAPI returns the changes made to the artifacts that follow the after sequence number,
+// in the order they were made. The next value of the reply is the after parameter for the
+// following request, so mirrors can sync the changes without the need to crawl every artifact.
+func ChangesAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const format = "changes api: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	var after int64
+	if s := c.QueryParam("after"); s != "" {
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || i < 0 {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				er: "Invalid after parameter",
+			})
+		}
+		after = i
+	}
+	limit := apiLimit
+	if s := c.QueryParam("limit"); s != "" {
+		i, err := strconv.Atoi(s)
+		if err != nil || i < 1 || i > apiLimit {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				er: "Invalid limit parameter",
+			})
+		}
+		limit = i
+	}
+	var changes model.Changes
+	if err := changes.After(ctx, db, after, limit); err != nil {
+		sl.Error("changes api", slog.Int64("after", after), slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			er: "Failed to query changes",
+		})
+	}
+	next := after
+	results := make([]changeAPI, 0, len(changes))
+	for _, change := range changes {
+		if change == nil {
+			continue
+		}
+		next = change.Seq
+		id := helper.ObfuscateID(change.FileID)
+		result := changeAPI{
+			Seq:     change.Seq,
+			Kind:    change.Kind,
+			ID:      id,
+			UUID:    change.UUID.String,
+			Title:   change.Title.String,
+			Changed: change.Changed,
+		}
+		if kind := model.Change(change.Kind); kind != model.SoftDeleted && kind != model.HardDeleted {
+			result.URLs = &urlAPI{
+				API:       APIBase + "/artifact/" + id,
+				Download:  "/d/" + id,
+				HTML:      "/f/" + id,
+				Thumbnail: "",
+			}
+		}
+		results = append(results, result)
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"changes": results,
+		"limit":   limit,
+		"next":    next,
+	})
+}
+
 // FileAPI returns a single file by its obfuscated ID.
 func FileAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
 	const format = "file api: %w"
@@ -312,9 +573,335 @@ func FileAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB)
 	}
 
 	file := artifact(record)
+	rels, links, err := graph(ctx, db, record.ID)
+	if err != nil {
+		sl.Error("file api graph", slog.Int64("id", record.ID), slog.Any("error", err))
+	}
+	file.Relations, file.Links = rels, links
+	keywords, err := model.FileKeywords(ctx, db, record.ID)
+	if err != nil {
+		sl.Error("file api keywords", slog.Int64("id", record.ID), slog.Any("error", err))
+	}
+	for kw := range slices.Values(keywords) {
+		file.Keywords = append(file.Keywords, kw.Slug)
+	}
+	for rel := range slices.Values(rels) {
+		file.Relationships = append(file.Relationships, relationAPI{
+			Link: "https://defacto2.net" + rel.URLs.HTML + "/",
+			Desc: rel.Description,
+		})
+	}
+	for link := range slices.Values(links) {
+		file.Relationships = append(file.Relationships, relationAPI{
+			Link: link.URL,
+			Desc: link.Label,
+		})
+	}
 	return c.JSON(http.StatusOK, file)
 }
 
+// RelationsAPI returns the typed relations in both directions and the external links
+// of a single file by its obfuscated ID.
+func RelationsAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const format = "relations api: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	hash := c.Param("id")
+	fileID := helper.DeobfuscateID(hash)
+	if fileID == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			er: "Invalid file hash",
+		})
+	}
+	exists, err := models.Files(models.FileWhere.ID.EQ(int64(fileID))).Exists(ctx, db)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			er: "Failed to query file",
+		})
+	}
+	if !exists {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			er: "File not found",
+		})
+	}
+	rels, links, err := graph(ctx, db, int64(fileID))
+	if err != nil {
+		sl.Error("relations api", slog.Int64("id", int64(fileID)), slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			er: "Failed to query relations",
+		})
+	}
+	if rels == nil {
+		rels = []graphAPI{}
+	}
+	if links == nil {
+		links = []linkAPI{}
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"id":        hash,
+		"relations": rels,
+		"links":     links,
+	})
+}
+
+// ContentsAPI returns the archive manifest of a single file by its obfuscated ID,
+// which lists the files contained in the archive with their sizes, checksums and dates.
+func ContentsAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const format = "contents api: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	hash := c.Param("id")
+	fileID := helper.DeobfuscateID(hash)
+	if fileID == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			er: "Invalid file hash",
+		})
+	}
+	exists, err := models.Files(models.FileWhere.ID.EQ(int64(fileID))).Exists(ctx, db)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			er: "Failed to query file",
+		})
+	}
+	if !exists {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			er: "File not found",
+		})
+	}
+	members, err := model.Manifest(ctx, db, int64(fileID))
+	if err != nil {
+		sl.Error("contents api", slog.Int64("id", int64(fileID)), slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			er: "Failed to query contents",
+		})
+	}
+	if members == nil {
+		members = []manifest.Member{}
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"id":       hash,
+		"count":    len(members),
+		"contents": members,
+	})
+}
+
+// SaucesAPI returns the SAUCE metadata of a single file by its obfuscated ID,
+// which includes the records of the download and of the files contained in its archive.
+func SaucesAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const format = "sauces api: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	hash := c.Param("id")
+	fileID := helper.DeobfuscateID(hash)
+	if fileID == 0 {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			er: "Invalid file hash",
+		})
+	}
+	exists, err := models.Files(models.FileWhere.ID.EQ(int64(fileID))).Exists(ctx, db)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			er: "Failed to query file",
+		})
+	}
+	if !exists {
+		return c.JSON(http.StatusNotFound, map[string]string{
+			er: "File not found",
+		})
+	}
+	recs, err := model.Sauces(ctx, db, int64(fileID))
+	if err != nil {
+		sl.Error("sauces api", slog.Int64("id", int64(fileID)), slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			er: "Failed to query sauce",
+		})
+	}
+	if recs == nil {
+		recs = []sauces.Record{}
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"id":     hash,
+		"count":  len(recs),
+		"sauces": recs,
+	})
+}
+
+// graph returns the typed relations and external links of the artifact id,
+// the relations to the hidden artifacts are excluded.
+func graph(ctx context.Context, db *sql.DB, id int64) ([]graphAPI, []linkAPI, error) {
+	rels, err := model.Relations(ctx, db, id, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("graph relations: %w", err)
+	}
+	links, err := model.Links(ctx, db, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("graph links: %w", err)
+	}
+	return graphAPIs(rels...), linkAPIs(links...), nil
+}
+
+// graphAPIs transforms the typed relations to the API format.
+func graphAPIs(rels ...model.Relation) []graphAPI {
+	results := make([]graphAPI, 0, len(rels))
+	for rel := range slices.Values(rels) {
+		direction := "to"
+		if rel.Reverse {
+			direction = "from"
+		}
+		key := helper.ObfuscateID(rel.Other())
+		results = append(results, graphAPI{
+			Kind:        string(rel.Kind),
+			Direction:   direction,
+			Description: rel.Description(),
+			ID:          key,
+			Title:       rel.Title,
+			URLs: urlAPI{
+				API:       APIBase + "/artifact/" + key,
+				Download:  "/d/" + key,
+				HTML:      "/f/" + key,
+				Thumbnail: "",
+			},
+		})
+	}
+	return results
+}
+
+// linkAPIs transforms the typed external links to the API format.
+func linkAPIs(links ...model.Link) []linkAPI {
+	results := make([]linkAPI, 0, len(links))
+	for link := range slices.Values(links) {
+		results = append(results, linkAPI{
+			Kind:  string(link.Kind),
+			Label: link.Label,
+			URL:   link.URL,
+		})
+	}
+	return results
+}
+
+// keywordAPI represents a keyword of the editor curated vocabulary for API responses.
+type keywordAPI struct {
+	Slug  string `json:"slug"`
+	Name  string `json:"name"`
+	Info  string `json:"info,omitempty"`
+	Count int    `json:"count"`
+	URLs  struct {
+		API  string `json:"api"`
+		HTML string `json:"html"`
+	} `json:"urls"`
+}
+
+// KeywordsAPI returns the vocabulary of the keywords with the number of public artifacts tagged with each.
+// The artifacts of a keyword are listed using the keyword parameter of the artifacts API.
+func KeywordsAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const format = "keywords api: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	k := tags.K{}
+	if err := k.Build(ctx, db); err != nil {
+		sl.Error("keywords api", slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			er: "Failed to query keywords",
+		})
+	}
+	results := make([]keywordAPI, 0, len(k.List))
+	for kw := range slices.Values(k.List) {
+		x := keywordAPI{
+			Slug:  kw.URI,
+			Name:  kw.Name,
+			Info:  kw.Info,
+			Count: kw.Count,
+		}
+		x.URLs.API = APIBase + "/artifacts?keyword=" + kw.URI
+		x.URLs.HTML = "/keyword/" + kw.URI
+		results = append(results, x)
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"keywords": results,
+		"total":    len(results),
+	})
+}
+
+// textAPI represents a plain text search match for API responses.
+type textAPI struct {
+	ID       string  `json:"id"`
+	Title    string  `json:"title,omitempty"`
+	Filename string  `json:"filename"`
+	Name     string  `json:"name"`
+	Rank     float64 `json:"rank"`
+	Snippet  string  `json:"snippet"`
+	URLs     urlAPI  `json:"urls"`
+}
+
+// TextsAPI returns the public artifacts with text files, such as NFO, DIZ and README files,
+// that match the query search terms. Multiple terms are separated by commas.
+func TextsAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const format = "texts api: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	query := strings.TrimSpace(c.QueryParam("query"))
+	if query == "" {
+		return c.JSON(http.StatusBadRequest, map[string]string{
+			er: "Missing query parameter",
+		})
+	}
+	limit := textLimit
+	if s := c.QueryParam("limit"); s != "" {
+		i, err := strconv.Atoi(s)
+		if err != nil || i < 1 || i > model.Maximum {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				er: "Invalid limit parameter",
+			})
+		}
+		limit = i
+	}
+	terms := helper.SearchTerm(query)
+	var texts model.Texts
+	if err := texts.Search(ctx, db, limit, terms...); err != nil {
+		if errors.Is(err, model.ErrTrimmedTerms) {
+			return c.JSON(http.StatusBadRequest, map[string]string{
+				er: "Invalid query parameter",
+			})
+		}
+		sl.Error("texts api", slog.String("query", query), slog.Any("error", err))
+		return c.JSON(http.StatusInternalServerError, map[string]string{
+			er: "Failed to query texts",
+		})
+	}
+	results := make([]textAPI, 0, len(texts))
+	for _, text := range texts {
+		if text == nil {
+			continue
+		}
+		_, snip := TextSnippet(terms, text.Body)
+		id := helper.ObfuscateID(text.ID)
+		results = append(results, textAPI{
+			ID:       id,
+			Title:    text.RecordTitle.String,
+			Filename: text.Filename.String,
+			Name:     text.Name,
+			Rank:     text.Rank,
+			Snippet:  snip,
+			URLs: urlAPI{
+				API:       APIBase + "/artifact/" + id,
+				Download:  "/d/" + id,
+				HTML:      "/f/" + id,
+				Thumbnail: "/public/image/thumb/" + text.UUID.String,
+			},
+		})
+	}
+	return c.JSON(http.StatusOK, map[string]any{
+		"query":   query,
+		"results": results,
+		"limit":   limit,
+	})
+}
+
 // APIMarkup removes CSS classes and attributes from HTML for API responses.
 // Keeps semantic HTML tags but removes presentation-specific markup.
 func APIMarkup(src string) string {
@@ -1005,8 +1592,22 @@ func ScenerAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB
 		})
 	}
 
-	srs := model.Scener(name)
-	fs, err := srs.Where(ctx, db, name)
+	var ident Identity
+	var sum model.Summary
+	var fs models.FileSlice
+	var err error
+	title := releaser.Link(name)
+	s, slugs := scenerIdentity(ctx, sl, db, name)
+	if s == nil && len(slugs) == 1 && slugs[0] != name {
+		return c.Redirect(http.StatusMovedPermanently, APIBase+"/scener/"+slugs[0])
+	}
+	if s != nil {
+		title = s.Name
+		fs, err = s.Files(ctx, db)
+	} else {
+		srs := model.Scener(name)
+		fs, err = srs.Where(ctx, db, name)
+	}
 	if err != nil {
 		return c.JSON(http.StatusNotFound, map[string]string{
 			er: "Failed to fetch scener",
@@ -1017,14 +1618,23 @@ func ScenerAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB
 			er: "Scener not found",
 		})
 	}
-
-	sum := model.Summary{ //nolint:exhaustruct // Fields are set by ByReleaser method
+	if s != nil {
+		ident, err = identityDetails(ctx, db, s)
+		if err == nil {
+			err = s.Summary(ctx, db, &sum)
+		}
+	} else {
+		err = sum.ByScener(ctx, db, name)
 	}
-	if err := sum.ByScener(ctx, db, name); err != nil {
+	if err != nil {
 		return c.JSON(http.StatusInternalServerError, map[string]string{
 			er: "Failed to get scener statistics",
 		})
 	}
+	demozoo := ""
+	if ident.Demozoo > 0 {
+		demozoo = "https://demozoo.org/sceners/" + strconv.Itoa(ident.Demozoo) + "/"
+	}
 
 	result := make([]artifactAPI, 0, len(fs))
 	for _, f := range fs {
@@ -1034,7 +1644,7 @@ func ScenerAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB
 		"scener": EntityAPI{
 			ID:    simple.Hash(name),
 			Name:  name,
-			Title: releaser.Link(name),
+			Title: title,
 			URLs: struct {
 				API   string `json:"api"`
 				HTML3 string `json:"html3"`
@@ -1052,8 +1662,12 @@ func ScenerAPI(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB
 			Websites: nil,
 			Sixteen:  "",
 			Janeway:  "",
-			Demozoo:  "",
+			Demozoo:  demozoo,
 			Csdb:     "",
+			Roles:    ident.Roles,
+			Aliases:  ident.Aliases,
+			Members:  ident.Members,
+			Groups:   ident.Groups,
 		},
 		af: result,
 	})
@@ -1462,17 +2076,13 @@ func releasersAPI(art *models.File) []releaserAPI {
 	return pair
 }
 
+// relationshipsAPI returns the links to the artifact on other websites.
+// The relations and external links of the artifact are read from their tables by [FileAPI].
 func relationshipsAPI(art *models.File) []relationAPI {
 	if art == nil {
 		return nil
 	}
 	results := []relationAPI{}
-	if r := relationsAPI(art); len(r) > 0 {
-		results = append(results, r...)
-	}
-	if r := linksAPI(art); len(r) > 0 {
-		results = append(results, r...)
-	}
 	if s := filerecord.IdenficationDZ(art); s != "" {
 		r := relationAPI{
 			Link: "https://demozoo.org/productions/" + s + "/",
@@ -1511,84 +2121,6 @@ func relationshipsAPI(art *models.File) []relationAPI {
 	return results
 }
 
-// relationsAPI returns the list of relationships for the file record.
-func relationsAPI(art *models.File) []relationAPI {
-	if art == nil {
-		return nil
-	}
-	rels := art.ListRelations.String
-	if rels == "" {
-		return nil
-	}
-	links := strings.Split(rels, "|")
-	if len(links) == 0 {
-		return nil
-	}
-	const expected = 2
-	const route = "https://defacto2.net/f/"
-	results := []relationAPI{}
-	for link := range slices.Values(links) {
-		s := strings.Split(link, ";")
-		if len(s) != expected {
-			continue
-		}
-		name, href := s[0], s[1]
-		id := helper.DeObfuscate(href)
-		if invalidID := id == href; invalidID {
-			continue
-		}
-		if !strings.HasPrefix(href, route) {
-			href = route + href + "/"
-		}
-		result := relationAPI{
-			Link: href,
-			Desc: name,
-		}
-		results = append(results, result)
-	}
-	return results
-}
-
-// Websites returns the list of links for the file record.
-func linksAPI(art *models.File) []relationAPI {
-	if art == nil {
-		return nil
-	}
-	lls := art.ListLinks.String
-	if lls == "" {
-		return nil
-	}
-	links := strings.Split(lls, "|")
-	if len(links) == 0 {
-		return nil
-	}
-	results := []relationAPI{}
-	const expected = 2
-	for link := range slices.Values(links) {
-		s := strings.Split(link, ";")
-		if len(s) != expected {
-			continue
-		}
-		name, href := s[0], s[1]
-		// Generally a stored URL will not include the protocol,
-		// and will need to be prefixed with "https://".
-		// There are some exceptions for websites that refuse to
-		// implement HTTPS, such as http://textfiles.com.
-		if !strings.HasPrefix(href, "http") {
-			href = "https://" + href
-		}
-		if val, err := url.Parse(href); err != nil || val.Host == "" {
-			continue
-		}
-		result := relationAPI{
-			Link: href,
-			Desc: name,
-		}
-		results = append(results, result)
-	}
-	return results
-}
-
 func timedTimer(t null.Time) *time.Time {
 	var tt *time.Time
 	if t.Valid {
@@ -21,11 +21,13 @@ import (
 
 	"github.com/Defacto2/helper"
 	"github.com/Defacto2/server/handler/app/internal/filerecord"
+	"github.com/Defacto2/server/handler/app/internal/fileslice"
 	"github.com/Defacto2/server/handler/app/internal/simple"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/Defacto2/server/internal/tags"
 	"github.com/Defacto2/server/model"
 	"github.com/aarondl/null/v8"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
 	"github.com/bengarrett/bbs"
 	"github.com/labstack/echo/v5"
 	"golang.org/x/text/language"
@@ -111,9 +113,20 @@ func (c *Cache) Records(i int) {
 // the following would return:
 //
 //	"Writer and programmer attributions"
+//
+// The name can also be a comma separated list of the aliases of the scener, which match any of the aliases.
 func Attribute(write, code, art, music, name string) string {
 	name = strings.ToLower(name)
 	const sep = `,`
+	names := strings.Split(name, sep)
+	for i, s := range names {
+		names[i] = strings.TrimSpace(s)
+	}
+	credited := func(list []string) bool {
+		return slices.ContainsFunc(list, func(s string) bool {
+			return slices.Contains(names, strings.TrimSpace(s))
+		})
+	}
 	w, c, a, m := strings.Split(strings.ToLower(write), sep),
 		strings.Split(strings.ToLower(code), sep),
 		strings.Split(strings.ToLower(art), sep),
@@ -125,16 +138,16 @@ func Attribute(write, code, art, music, name string) string {
 		return ""
 	}
 	match := []string{}
-	if slices.Contains(w, name) {
+	if credited(w) {
 		match = append(match, "writer")
 	}
-	if slices.Contains(c, name) {
+	if credited(c) {
 		match = append(match, "programmer")
 	}
-	if slices.Contains(a, name) {
+	if credited(a) {
 		match = append(match, "artist")
 	}
-	if slices.Contains(m, name) {
+	if credited(m) {
 		match = append(match, "musician")
 	}
 	if len(match) == 0 {
@@ -269,6 +282,12 @@ func ByteFileS(name string, count, bytes any) template.HTML {
 	return template.HTML(s)
 }
 
+// Category returns the query mod expression of the artifacts category URI, such as "demoscene",
+// or false if the URI is not a category.
+func Category(uri string) (qm.QueryMod, bool) {
+	return fileslice.Expr(uri)
+}
+
 // Day returns a string representation of the day number, a value between 1 and 31.
 func Day(d any) string {
 	var s string
@@ -1102,6 +1121,7 @@ func YMDEdit(ctx context.Context, c *echo.Context, db *sql.DB) error {
 	if err != nil {
 		return fmt.Errorf(format, "begin tx", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	r, err := model.One(ctx, tx, true, f.ID)
 	if err != nil {
 		return fmt.Errorf(format, "model one", err)
@@ -151,6 +151,16 @@ func TestDescribe(t *testing.T) {
 	be.True(t, strings.Contains(h, "Dec, 1990"))
 }
 
+func TestCategory(t *testing.T) {
+	t.Parallel()
+	_, ok := app.Category("demoscene")
+	be.True(t, ok)
+	_, ok = app.Category("new-uploads")
+	be.True(t, !ok)
+	_, ok = app.Category("")
+	be.True(t, !ok)
+}
+
 func TestDay(t *testing.T) {
 	t.Parallel()
 	x := app.Day("")
@@ -221,8 +231,8 @@ func TestFuncMap(t *testing.T) {
 
 func TestLinkSamples(t *testing.T) {
 	t.Parallel()
-	x := app.LinkPreviews("1", "2", "3", "4", "5", "6", "7")
-	be.True(t, len(x) == 7)
+	x := app.LinkPreviews("1", "2", "3", "4", "5")
+	be.True(t, len(x) == 5)
 	be.True(t, strings.Contains(x[0], "youtube.com/watch?v=1"))
 	be.True(t, strings.Contains(x[1], "demozoo.org/productions/2"))
 }
@@ -359,6 +369,9 @@ func TestAttribute(t *testing.T) {
 	s = app.Attribute("another person,writer,ben",
 		"ben", "", "", "ben")
 	be.Equal(t, "Writer and programmer attributions", s)
+	s = app.Attribute("another person,the bear",
+		"ben", "", "", "ben,the bear")
+	be.Equal(t, "Writer and programmer attributions", s)
 }
 
 func TestBrief(t *testing.T) {
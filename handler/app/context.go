@@ -9,8 +9,10 @@ import (
 	"embed"
 	"errors"
 	"fmt"
+	"html"
 	"html/template"
 	"log/slog"
+	"maps"
 	"math"
 	"net/http"
 	"regexp"
@@ -27,16 +29,21 @@ import (
 	"github.com/Defacto2/server/handler/csdb"
 	"github.com/Defacto2/server/handler/demozoo"
 	"github.com/Defacto2/server/handler/download"
+	"github.com/Defacto2/server/handler/fulltext"
 	"github.com/Defacto2/server/handler/janeway"
 	"github.com/Defacto2/server/handler/pouet"
 	"github.com/Defacto2/server/handler/releaser"
 	"github.com/Defacto2/server/handler/releaser/initialism"
+	"github.com/Defacto2/server/handler/releaser/meta"
+	"github.com/Defacto2/server/handler/render"
 	"github.com/Defacto2/server/handler/sess"
 	"github.com/Defacto2/server/handler/site"
 	"github.com/Defacto2/server/handler/sixteen"
 	"github.com/Defacto2/server/handler/tidbit"
 	"github.com/Defacto2/server/internal/config"
 	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/encodings"
+	"github.com/Defacto2/server/internal/jobs"
 	"github.com/Defacto2/server/internal/logs"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/Defacto2/server/internal/postgres"
@@ -79,6 +86,7 @@ type Pagination struct {
 	TwoBelow  int    // TwoBelow is the page number two below the current page.
 	TwoAfter  int    // TwoAfter is the page number two after the current page.
 	RangeStep int    // RangeStep is the number of pages to skip in the pagination range.
+	Query     string // Query is the optional query string appended to the pagination links.
 }
 
 const (
@@ -179,12 +187,16 @@ func Artifacts(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB
 	if !fileslice.Valid(uri) {
 		return Artifacts404(sl, c, uri)
 	}
-	if page == "" {
-		return artifacts(ctx, sl, c, db, uri, 1)
+	p := 1
+	if page != "" {
+		var err error
+		p, err = strconv.Atoi(page)
+		if err != nil {
+			return Page404(sl, c, uri, page)
+		}
 	}
-	p, err := strconv.Atoi(page)
-	if err != nil {
-		return Page404(sl, c, uri, page)
+	if slug := strings.ToLower(strings.TrimSpace(c.QueryParam("keyword"))); slug != "" {
+		return keywordArtifacts(ctx, sl, c, db, uri, slug, p)
 	}
 	return artifacts(ctx, sl, c, db, uri, p)
 }
@@ -202,6 +214,9 @@ func artifacts(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB
 	data := emptyFiles(c)
 	data["title"] = title
 	data[canonical] = strings.Join([]string{files, uri}, "/")
+	if feedFiles(uri) {
+		data["feed"] = strings.Join([]string{string(FeedFiles), uri}, "/")
+	}
 	data["description"] = descr
 	data["logo"] = logo
 	data["h1"] = title
@@ -250,6 +265,129 @@ func artifacts(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB
 	return nil
 }
 
+// Keywords is the handler for the index page of the keywords, which are the secondary tags of the artifacts.
+func Keywords(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const title = "Keywords"
+	const name = "keywords"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("keywords context: %w", err)
+	}
+	k := tags.K{}
+	if err := k.Build(ctx, db); err != nil {
+		return DatabaseErr(sl, c, name, err)
+	}
+	data := empty(c)
+	data["description"] = "Keywords are the secondary tags that describe the artifacts beyond their platform and category."
+	data["h1"] = title
+	data["lead"] = "The secondary tags of the artifacts, such as the hardware they use or the size limits of an intro."
+	data["title"] = title
+	data["keywords"] = k.List
+	data[canonical] = "keywords"
+	err := c.Render(http.StatusOK, name, data)
+	if err != nil {
+		return InternalErr(sl, c, name, err)
+	}
+	return nil
+}
+
+// Keyword is the handler for the list and preview of the files tagged with the keyword slug.
+// The page is the page number of the results to display.
+func Keyword(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, slug, page string) error {
+	const format = "keyword context: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	p := 1
+	if page != "" {
+		var err error
+		p, err = strconv.Atoi(page)
+		if err != nil {
+			return Page404(sl, c, "keyword/"+slug, page)
+		}
+	}
+	return keywordArtifacts(ctx, sl, c, db, "", slug, p)
+}
+
+// keywordArtifacts is a helper function for the Artifacts and Keyword handlers that renders
+// the files of the artifacts category uri that are tagged with the keyword slug.
+// An empty uri renders the files of every category.
+func keywordArtifacts(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB,
+	uri, slug string, page int,
+) error {
+	const format = "keyword artifacts context: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	const name = "artifacts"
+	errs := fmt.Sprintf("keyword %q artifacts page %d for %q", slug, page, uri)
+	kw, err := model.OneKeyword(ctx, db, slug)
+	if errors.Is(err, sql.ErrNoRows) {
+		return Artifacts404(sl, c, "keyword/"+slug)
+	}
+	if err != nil {
+		return DatabaseErr(sl, c, errs, err)
+	}
+	mods, ok := fileslice.KeywordMods(uri, slug)
+	if !ok {
+		return Artifacts404(sl, c, uri)
+	}
+	data := emptyFiles(c)
+	data["title"] = kw.Name + " artifacts"
+	data["description"] = "The collection of artifacts tagged with the " + kw.Name + " keyword."
+	data["h1"] = kw.Name
+	data["lead"] = kw.Info
+	data["logo"] = kw.Name
+	data[canonical] = "keyword/" + slug
+	base, query := "/keyword/"+slug, ""
+	if uri != "" {
+		_, subhead, _ := fileslice.FileInfo(uri)
+		data["title"] = kw.Name + " " + strings.ToLower(subhead)
+		data[canonical] = strings.Join([]string{files, uri}, "/")
+		base, query = "/files/"+uri, "?keyword="+slug
+	}
+	data["noindex"] = uri != ""
+	r, err := fileslice.Keywords(ctx, db, uri, page, limit, slug)
+	if err != nil {
+		return DatabaseErr(sl, c, errs, err)
+	}
+	data[records] = r
+	m := model.Summary{}
+	if err := m.ByKeyword(ctx, db, mods...); err != nil {
+		return DatabaseErr(sl, c, errs, err)
+	}
+	sum := int(m.SumCount.Int64)
+	data["stats"] = map[string]string{
+		files: string(ByteFileS("file", m.SumCount.Int64, m.SumBytes.Int64)),
+		years: fmt.Sprintf("%d - %d", m.MinYear.Int16, m.MaxYear.Int16),
+	}
+	lastPage := math.Ceil(float64(sum) / float64(limit))
+	if len(r) == 0 {
+		if err = c.Render(http.StatusOK, name, data); err != nil {
+			return InternalErr(sl, c, errs, err)
+		}
+		return nil
+	}
+	if page > int(lastPage) {
+		return Page404(sl, c, base, strconv.Itoa(page))
+	}
+	const pages = 2
+	data["Pagination"] = Pagination{
+		TwoAfter:  page + pages,
+		NextPage:  page + 1,
+		CurrPage:  page,
+		PrevPage:  page - 1,
+		TwoBelow:  page - pages,
+		SumPages:  int(lastPage),
+		BaseURL:   base,
+		Query:     query,
+		RangeStep: steps(lastPage),
+	}
+	if err = c.Render(http.StatusOK, name, data); err != nil {
+		return InternalErr(sl, c, errs, err)
+	}
+	return nil
+}
+
 func artifactsDesc(uri, years string, sum int, data map[string]any) map[string]any {
 	data["noindex"] = true
 	switch fileslice.Match(uri) {
@@ -277,6 +415,10 @@ func artifactsDesc(uri, years string, sum int, data map[string]any) map[string]a
 	case fileslice.Newest:
 		data["description"] = "These are more recent artifacts held by the site."
 		data["title"] = "Recent artifacts"
+	case fileslice.Popular:
+		data["description"] = "These are the most downloaded, emulated and viewed artifacts of the month."
+		data["title"] = "Popular artifacts"
+		data["unknownYears"] = false
 	case fileslice.Sensenstahl:
 		data["title"] = "Sensenstahl artifacts"
 	case fileslice.WindowsPack:
@@ -554,6 +696,66 @@ func Checksum(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB,
 	return nil
 }
 
+// Sidecar is the handler for the SHA-384 checksum file that accompanies a file record download.
+func Sidecar(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, id string) error {
+	const format = "sidecar context: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	const uri = "d"
+	if err := download.SidecarSend(ctx, c, db, id); err != nil {
+		return DownloadErr(sl, c, uri, err)
+	}
+	return nil
+}
+
+// Sums is the handler for the manifest of the SHA-384 checksums of the file record downloads.
+func Sums(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, sums *download.Sums) error {
+	const format = "sums context: %w"
+	if err := nils.Check(ctx, sl, c, db, sums); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	if err := sums.HTTPSend(ctx, c, db); err != nil {
+		return InternalErr(sl, c, "sha384sums", err)
+	}
+	return nil
+}
+
+// SumsKey is the handler for the public key that verifies the signature of the manifest of checksums,
+// which is not found when signing is disabled.
+func SumsKey(sl *slog.Logger, c *echo.Context, sums *download.Sums) error {
+	const format = "sums key context: %w"
+	if err := nils.Check(sl, c, sums); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	if err := sums.HTTPKey(c); err != nil {
+		if errors.Is(err, download.ErrUnsigned) {
+			return StatusErr(sl, c, http.StatusNotFound, "sha384sums.pub")
+		}
+		return InternalErr(sl, c, "sha384sums.pub", err)
+	}
+	return nil
+}
+
+// SumsSignature is the handler for the signature of the manifest of checksums,
+// which is not found when signing is disabled.
+func SumsSignature(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, sums *download.Sums) error {
+	const format = "sums signature context: %w"
+	if err := nils.Check(ctx, sl, c, db, sums); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	if err := sums.HTTPSignature(ctx, c, db); err != nil {
+		switch {
+		case errors.Is(err, download.ErrUnsigned):
+			return StatusErr(sl, c, http.StatusNotFound, "sha384sums.sig")
+		case errors.Is(err, download.ErrVersion):
+			return StatusErr(sl, c, http.StatusPreconditionFailed, "sha384sums.sig")
+		}
+		return InternalErr(sl, c, "sha384sums.sig", err)
+	}
+	return nil
+}
+
 // Coder is the handler for the Coder sceners page.
 func Coder(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
 	const title = "Coder and programmers"
@@ -821,6 +1023,28 @@ func DownloadJsDos(
 	return nil
 }
 
+// DownloadJsDosBundle is the handler to download the js-dos v8 bundle of an MS-DOS file record.
+func DownloadJsDosBundle(
+	ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, extra, downl dir.Directory,
+) error {
+	const format = "download jsdos bundle context: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	b := download.Bundle{
+		Extra:    extra,
+		Download: downl,
+	}
+	const uri = "jsdos/bundle"
+	if err := b.HTTPSend(ctx, sl, c, db); err != nil {
+		if errors.Is(err, download.ErrStat) {
+			return FileMissingErr(sl, c, uri, err)
+		}
+		return DownloadErr(sl, c, uri, err)
+	}
+	return nil
+}
+
 // Download is the handler for the Download file record page.
 func Download(
 	ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, downl dir.Directory,
@@ -848,6 +1072,27 @@ func Download(
 	return nil
 }
 
+// DownloadMember is the handler to download a single file contained in the archive of a file record.
+func DownloadMember(
+	ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, downl dir.Directory,
+) error {
+	const format = "download member context: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	m := download.Member{
+		Dir: downl,
+	}
+	const uri = "d"
+	if err := m.HTTPSend(ctx, sl, c, db); err != nil {
+		if errors.Is(err, download.ErrStat) {
+			return FileMissingErr(sl, c, uri, err)
+		}
+		return DownloadErr(sl, c, uri, err)
+	}
+	return nil
+}
+
 // FTP is the handler for the FTP page.
 func FTP(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
 	const title = "FTP"
@@ -1320,6 +1565,30 @@ func Inline(
 	return nil
 }
 
+// Unicode is the handler for the text of a file artifact transcoded to UTF-8.
+// When reader is true, the screen reader friendly version of the text is sent.
+func Unicode(
+	ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, downl, extra dir.Directory, reader bool,
+) error {
+	const format = "unicode context: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	u := download.Unicode{
+		Download: downl,
+		Extra:    extra,
+		Reader:   reader,
+	}
+	const uri = "utf8"
+	if err := u.HTTPSend(ctx, sl, c, db); err != nil {
+		if errors.Is(err, render.ErrDownload) {
+			return FileMissingErr(sl, c, uri, err)
+		}
+		return DownloadErr(sl, c, uri, err)
+	}
+	return nil
+}
+
 // Interview is the handler for the People Interviews page.
 func Interview(sl *slog.Logger, c *echo.Context) error {
 	const title = "Interviews with former Sceners"
@@ -1552,6 +1821,12 @@ func PostDesc(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB,
 	data["unknownYears"] = false
 	data[records] = fs
 	data["stats"] = d
+	var texts model.Texts
+	if err := texts.Search(ctx, db, textLimit, terms...); err != nil {
+		sl.Warn("post desc", slog.String("task", "plain text search"),
+			slog.String("terms", s), slog.Any("error", err))
+	}
+	data["texts"] = TextMatches(terms, texts)
 	err := c.Render(http.StatusOK, name, data)
 	if err != nil {
 		return InternalErr(sl, c, errs, err)
@@ -1559,6 +1834,59 @@ func PostDesc(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB,
 	return nil
 }
 
+// textLimit is the maximum number of plain text matches to display in the search results.
+const textLimit = 50
+
+// TextMatch is a plain text search result with a highlighted snippet of the matching text.
+type TextMatch struct {
+	ID       string        // ID is the obfuscated artifact id.
+	Title    string        // Title of the artifact.
+	Filename string        // Filename of the artifact download.
+	Name     string        // Name of the matching text file.
+	Snip     template.HTML // Snip is the escaped snippet of text with the search terms marked.
+}
+
+// TextMatches returns the plain text search results with their highlighted snippets.
+func TextMatches(terms []string, texts model.Texts) []TextMatch {
+	matches := make([]TextMatch, 0, len(texts))
+	for _, text := range texts {
+		if text == nil {
+			continue
+		}
+		term, snip := TextSnippet(terms, text.Body)
+		htm := html.EscapeString(snip)
+		if term != "" {
+			htm = MarkAll(html.EscapeString(term), htm)
+		}
+		matches = append(matches, TextMatch{
+			ID:       helper.ObfuscateID(text.ID),
+			Title:    text.RecordTitle.String,
+			Filename: text.Filename.String,
+			Name:     text.Name,
+			Snip:     template.HTML(htm), //nolint:gosec
+		})
+	}
+	return matches
+}
+
+// TextSnippet returns the first of the terms found in the body with a snippet of the surrounding text.
+// If none of the terms are found, the term is empty and the snippet is the start of the body.
+func TextSnippet(terms []string, body string) (string, string) {
+	lower := strings.ToLower(body)
+	for _, term := range terms {
+		term = strings.TrimSpace(term)
+		if term == "" || !strings.Contains(lower, strings.ToLower(term)) {
+			continue
+		}
+		return term, fulltext.Snippet(term, body, fulltext.Window)
+	}
+	if len(terms) == 0 {
+		return "", ""
+	}
+	// an unmatched query returns the start of the body
+	return "", fulltext.Snippet(terms[0], body, fulltext.Window)
+}
+
 // PostFilename is the handler for the Search for filenames form post page.
 func PostFilename(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
 	return PostName(ctx, sl, c, db, Filenames)
@@ -1876,6 +2204,7 @@ func Releasers(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB
 	data := emptyFiles(c)
 	data["title"] = relname + " artifacts"
 	data[canonical] = strings.Join([]string{"g", uri}, "/")
+	data["feed"] = strings.Join([]string{string(FeedReleaser), uri}, "/")
 	data["h1"] = relname
 	altnames := initialism.Join(initialism.Path(uri))
 	data["lead"] = altnames
@@ -2058,26 +2387,54 @@ func Sceners(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB,
 	const name = "artifacts"
 	errs := fmt.Sprint("sceners page for,", uri)
 	s := releaser.Link(uri)
-	var ms model.Scener
-	fs, err := ms.Where(ctx, db, uri)
+	data := emptyFiles(c)
+	ident, slugs := scenerIdentity(ctx, sl, db, uri)
+	if ident == nil && len(slugs) == 1 && slugs[0] != uri {
+		return c.Redirect(http.StatusMovedPermanently, "/p/"+slugs[0])
+	}
+	var fs models.FileSlice
+	var err error
+	scener := s
+	if ident != nil {
+		s = ident.Name
+		scener = strings.Join(ident.Aliases, ",")
+		fs, err = ident.Files(ctx, db)
+	} else {
+		var ms model.Scener
+		fs, err = ms.Where(ctx, db, uri)
+	}
 	if err != nil {
 		return InternalErr(sl, c, errs, err)
 	}
 	if len(fs) == 0 {
 		return Scener404(sl, c, uri)
 	}
-	data := emptyFiles(c)
 	data[canonical] = strings.Join([]string{"p", uri}, "/")
+	data["feed"] = strings.Join([]string{string(FeedScener), uri}, "/")
 	data["title"] = s + attr
 	data["h1"] = s
 	data["lead"] = leadr + s + "."
 	data["logo"] = s
 	data["description"] = descr + s + "."
-	data["scener"] = s
+	data["scener"] = scener
 	data[records] = fs
-	d, err := scenerSum(ctx, db, uri)
-	if err != nil {
-		return InternalErr(sl, c, errs, err)
+	var d map[string]string
+	if ident != nil {
+		details, err := identityDetails(ctx, db, ident)
+		if err != nil {
+			return InternalErr(sl, c, errs, err)
+		}
+		data["identity"] = details
+		d, err = identitySum(ctx, db, ident)
+		if err != nil {
+			return InternalErr(sl, c, errs, err)
+		}
+	} else {
+		data["identities"] = slugs
+		d, err = scenerSum(ctx, db, uri)
+		if err != nil {
+			return InternalErr(sl, c, errs, err)
+		}
 	}
 	data["stats"] = d
 	err = c.Render(http.StatusOK, name, data)
@@ -2112,7 +2469,8 @@ func scenerSum(ctx context.Context, exec boil.ContextExecutor, uri string) (map[
 // SearchDesc is the handler for the Search for file descriptions page.
 func SearchDesc(sl *slog.Logger, c *echo.Context) error {
 	const title = "Game or app titles search"
-	const descr = "Use this search to uncover named applications, games, and descriptions of artifacts."
+	const descr = "Use this search to uncover named applications, games, descriptions, " +
+		"the text files of artifacts, and the authors and groups of the SAUCE metadata."
 	const format = "search desc context: %w"
 	if err := nils.Check(c, sl); err != nil {
 		return fmt.Errorf(format, err)
@@ -2402,6 +2760,286 @@ func Fixers(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) e
 	return nil
 }
 
+// Bulk is the handler for the bulk editor page, that applies a single edit
+// to a filtered set of artifacts.
+func Bulk(sl *slog.Logger, c *echo.Context) error {
+	const title = "Bulk editor"
+	const name = "bulk"
+	if err := nils.Check(sl, c); err != nil {
+		return fmt.Errorf("bulk context: %w", err)
+	}
+	data := empty(c)
+	data["description"] = "Defacto2 bulk editor tool."
+	data["h1"] = title
+	data["lead"] = "Select a set of artifacts using the filters, preview the changes, then apply an edit to them all."
+	data["title"] = title
+	data["limit"] = model.BulkLimit
+	data["platform"] = ""
+	data["section"] = ""
+	err := c.Render(http.StatusOK, name, data)
+	if err != nil {
+		return InternalErr(sl, c, name, err)
+	}
+	return nil
+}
+
+// Jobs is the handler for the background job queue page.
+func Jobs(sl *slog.Logger, c *echo.Context) error {
+	const title = "Job queue"
+	const name = "jobs"
+	if err := nils.Check(sl, c); err != nil {
+		return fmt.Errorf("jobs context: %w", err)
+	}
+	data := empty(c)
+	data["description"] = "Defacto2 background job queue."
+	data["h1"] = title
+	data["lead"] = "The background jobs that create the previews, thumbnails and repackaged archives of the artifacts."
+	data["title"] = title
+	data["attempts"] = jobs.Attempts
+	err := c.Render(http.StatusOK, name, data)
+	if err != nil {
+		return InternalErr(sl, c, name, err)
+	}
+	return nil
+}
+
+// ReleaserMetaEdit is the handler for the releaser metadata editor page.
+func ReleaserMetaEdit(sl *slog.Logger, c *echo.Context) error {
+	const title = "Releaser metadata"
+	const name = "releaser-meta"
+	if err := nils.Check(sl, c); err != nil {
+		return fmt.Errorf("releaser meta context: %w", err)
+	}
+	data := empty(c)
+	data["description"] = "Defacto2 releaser metadata editor."
+	data["h1"] = title
+	data["lead"] = "The metadata of the releasers that is shown on the releaser pages and returned by the API."
+	data["title"] = title
+	err := c.Render(http.StatusOK, name, data)
+	if err != nil {
+		return InternalErr(sl, c, name, err)
+	}
+	return nil
+}
+
+// ReleaserRenameEdit is the handler for the releaser rename and merge page.
+func ReleaserRenameEdit(sl *slog.Logger, c *echo.Context) error {
+	const title = "Releaser rename"
+	const name = "releaser-rename"
+	if err := nils.Check(sl, c); err != nil {
+		return fmt.Errorf("releaser rename context: %w", err)
+	}
+	type alias struct {
+		Alias  string
+		Target string
+	}
+	m := meta.Aliases()
+	aliases := make([]alias, 0, len(m))
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		aliases = append(aliases, alias{Alias: key, Target: m[key]})
+	}
+	data := empty(c)
+	data["description"] = "Defacto2 releaser rename and merge tool."
+	data["h1"] = title
+	data["lead"] = "Rename a releaser or merge two releasers, the former releaser links are redirected."
+	data["title"] = title
+	data["aliases"] = aliases
+	err := c.Render(http.StatusOK, name, data)
+	if err != nil {
+		return InternalErr(sl, c, name, err)
+	}
+	return nil
+}
+
+// ScenerIdentityEdit is the handler for the scener identity editor page.
+// The slug query parameter loads the scener identity into the editor form.
+func ScenerIdentityEdit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const title = "Scener identity"
+	const name = "scener-identity"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("scener identity context: %w", err)
+	}
+	slug := strings.ToLower(strings.TrimSpace(c.QueryParam("slug")))
+	s := model.Identity{Slug: slug}
+	if slug != "" {
+		ident, err := model.OneIdentity(ctx, db, slug)
+		switch {
+		case err == nil:
+			s = *ident
+		case !errors.Is(err, sql.ErrNoRows):
+			return InternalErr(sl, c, name, err)
+		}
+	}
+	data := empty(c)
+	data["description"] = "Defacto2 scener identity editor."
+	data["h1"] = title
+	data["lead"] = "The names, aliases and group memberships of the sceners who are credited for the artifacts."
+	data["title"] = title
+	data["scener"] = s
+	data["aliases"] = strings.Join(s.Aliases, ", ")
+	data["members"] = strings.Join(s.Members, ", ")
+	err := c.Render(http.StatusOK, name, data)
+	if err != nil {
+		return InternalErr(sl, c, name, err)
+	}
+	return nil
+}
+
+// KeywordsEdit is the handler for the keywords vocabulary editor page.
+// The slug query parameter loads the keyword into the editor form.
+func KeywordsEdit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const title = "Keywords"
+	const name = "keywords-edit"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("keywords edit context: %w", err)
+	}
+	ks, err := model.Keywords(ctx, db)
+	if err != nil {
+		return InternalErr(sl, c, name, err)
+	}
+	kw := model.Keyword{}
+	slug := strings.ToLower(strings.TrimSpace(c.QueryParam("slug")))
+	for k := range slices.Values(ks) {
+		if k.Slug == slug {
+			kw = k
+			break
+		}
+	}
+	data := empty(c)
+	data["description"] = "Defacto2 keywords vocabulary editor."
+	data["h1"] = title
+	data["lead"] = "The vocabulary of the keywords, the secondary tags that editors can use to tag the artifacts."
+	data["title"] = title
+	data["keyword"] = kw
+	data["keywords"] = ks
+	err = c.Render(http.StatusOK, name, data)
+	if err != nil {
+		return InternalErr(sl, c, name, err)
+	}
+	return nil
+}
+
+// Duplicates is the handler for the editor page of the clusters of duplicate artifacts.
+func Duplicates(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const title = "Duplicates"
+	const name = "duplicates"
+	const limit = 100
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("duplicates context: %w", err)
+	}
+	dupes, total, err := model.Duplicates(ctx, db, limit)
+	if err != nil {
+		return DatabaseErr(sl, c, name, err)
+	}
+	data := empty(c)
+	data["description"] = "Defacto2 duplicate artifacts finder."
+	data["h1"] = title
+	data["lead"] = "The clusters of public artifacts that share the same archived files, " +
+		"the same texts or a similar thumbnail, and so might be duplicates or repacks of each other."
+	data["title"] = title
+	data["duplicates"] = dupes
+	data["total"] = total
+	err = c.Render(http.StatusOK, name, data)
+	if err != nil {
+		return InternalErr(sl, c, name, err)
+	}
+	return nil
+}
+
+// Encodings is the handler for the editor report of the artifact texts with a low confidence
+// in the detection of their text encoding, that should be reviewed by an editor.
+func Encodings(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const title = "Text encodings"
+	const name = "encodings"
+	const limit = 100
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("encodings context: %w", err)
+	}
+	reviews, total, err := model.LowEncodings(ctx, db, limit)
+	if err != nil {
+		return DatabaseErr(sl, c, name, err)
+	}
+	data := empty(c)
+	data["description"] = "Defacto2 text encodings report."
+	data["h1"] = title
+	data["lead"] = "The artifact texts where the detection of the text encoding has a low confidence, " +
+		"and so might display with the wrong characters."
+	data["title"] = title
+	data["reviews"] = reviews
+	data["total"] = total
+	data["low"] = encodings.Low
+	err = c.Render(http.StatusOK, name, data)
+	if err != nil {
+		return InternalErr(sl, c, name, err)
+	}
+	return nil
+}
+
+// Trend is a day of the hits with the percentage of the busiest day,
+// used for the bars of the trends dashboard.
+type Trend struct {
+	model.Trend
+	Percent int64
+}
+
+// Trends is the handler for the editor dashboard of the artifact downloads, emulations and page views.
+// The days query parameter sets the period to either 7, 30 or 90 days.
+func Trends(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const title = "Trends"
+	const name = "trends"
+	const limit = 50
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("trends context: %w", err)
+	}
+	days := model.PopularDays
+	switch c.QueryParam("days") {
+	case "7":
+		days = 7
+	case "90":
+		days = 90
+	}
+	trends, err := model.Trends(ctx, db, days)
+	if err != nil {
+		return DatabaseErr(sl, c, name, err)
+	}
+	popular, err := model.Popular(ctx, db, days, limit)
+	if err != nil {
+		return DatabaseErr(sl, c, name, err)
+	}
+	busiest := int64(0)
+	var sum model.Trend
+	for _, t := range trends {
+		busiest = max(busiest, t.Total())
+		sum.Downloads += t.Downloads
+		sum.Emulations += t.Emulations
+		sum.Views += t.Views
+	}
+	rows := make([]Trend, 0, len(trends))
+	for _, t := range trends {
+		percent := int64(0)
+		if busiest > 0 {
+			const hundred = 100
+			percent = t.Total() * hundred / busiest
+		}
+		rows = append(rows, Trend{Trend: t, Percent: percent})
+	}
+	data := empty(c)
+	data["description"] = "Defacto2 artifact downloads, emulations and views."
+	data["h1"] = title
+	data["lead"] = fmt.Sprintf("The daily totals of the artifact downloads, emulations and page views "+
+		"over the last %d days, and the most popular artifacts of the period.", days)
+	data["title"] = title
+	data["days"] = days
+	data["trends"] = rows
+	data["popular"] = popular
+	data["sum"] = sum
+	err = c.Render(http.StatusOK, name, data)
+	if err != nil {
+		return InternalErr(sl, c, name, err)
+	}
+	return nil
+}
+
 // FixNumericSuffix handles the fixing of numeric suffixes in filenames.
 //
 //nolint:funlen // Complex handler with error handling and database operations
@@ -2467,13 +3105,30 @@ func FixNumericSuffix(ctx context.Context, sl *slog.Logger, c *echo.Context, db
 		return fmt.Errorf(format, ErrInvalidFilenamePattern, originalFilename)
 	}
 
-	// Update the filename in the database
+	// Update the filename in the database, along with its change and audit records
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin the filename change: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	old := *file
 	file.Filename = null.StringFrom(baseFilename)
-	_, err = file.Update(ctx, db, boil.Infer())
+	_, err = file.Update(ctx, tx, boil.Infer())
 	if err != nil {
 		const format = `%s: failed to update filename: %w`
 		return fmt.Errorf(format, format, err)
 	}
+	if err = model.RecordChange(ctx, tx, file.ID, model.Updated); err != nil {
+		return fmt.Errorf("failed to record the filename change: %w", err)
+	}
+	if err = model.RecordAudit(ctx, tx, &old, file); err != nil {
+		return fmt.Errorf("failed to audit the filename change: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit the filename change: %w", err)
+	}
 
 	// Return the updated file info as HTML to replace the list item
 	obfuscatedID = helper.ObfuscateID(file.ID)
@@ -2718,7 +3373,7 @@ func statsByURI(ctx context.Context, exec boil.ContextExecutor, uri string, m *m
 			return fmt.Errorf(format, "by unwanted", uri, err)
 		}
 	case fileslice.NewUploads, fileslice.NewUpdates, fileslice.Oldest,
-		fileslice.Newest, fileslice.Sensenstahl, fileslice.WindowsPack:
+		fileslice.Newest, fileslice.Popular, fileslice.Sensenstahl, fileslice.WindowsPack:
 		// For these cases, use the public artifacts method as fallback
 		if err := m.ByPublic(ctx, exec); err != nil {
 			return fmt.Errorf(format, "by public fallback", uri, err)
@@ -2740,6 +3395,7 @@ func emptyFiles(c *echo.Context) map[string]any {
 	data["janeway"] = 0
 	data["scener"] = ""
 	data["sixteen"] = ""
+	data["texts"] = []TextMatch{}
 	data["tidbits"] = ""
 	data["website"] = ""
 	data["unknownYears"] = true
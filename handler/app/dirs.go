@@ -28,18 +28,24 @@ import (
 	"github.com/Defacto2/magicnumber"
 	"github.com/Defacto2/server/handler/app/internal/filerecord"
 	"github.com/Defacto2/server/handler/app/internal/simple"
+	"github.com/Defacto2/server/handler/download"
+	"github.com/Defacto2/server/handler/jsdos"
 	"github.com/Defacto2/server/handler/readme"
 	"github.com/Defacto2/server/handler/render"
 	"github.com/Defacto2/server/handler/sess"
 	"github.com/Defacto2/server/internal/command"
 	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/encodings"
+	"github.com/Defacto2/server/internal/hits"
+	"github.com/Defacto2/server/internal/jobs"
 	"github.com/Defacto2/server/internal/logs"
+	"github.com/Defacto2/server/internal/manifest"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/Defacto2/server/internal/sauces"
 	"github.com/Defacto2/server/internal/tags"
 	"github.com/Defacto2/server/model"
 	"github.com/bengarrett/bbs"
-	"github.com/bengarrett/sauce"
 	"github.com/dustin/go-humanize"
 	"github.com/labstack/echo/v5"
 	_ "golang.org/x/image/webp" // webp format decoder
@@ -85,6 +91,7 @@ type Dirs struct {
 	Thumbnail dir.Directory // path to the file thumbnail directory
 	Extra     dir.Directory // path to the extra files directory
 	URI       string        // the URI of the file record
+	Queue     *jobs.Queue   // background job queue to create the assets, or nil to create them immediately
 }
 
 // Artifact is the handler for the of the file record.
@@ -109,9 +116,10 @@ func (dir Dirs) Artifact(
 	}
 	data := empty(c)
 	if !readonly && sess.Editor(c) {
-		data = dir.EditorContent(ctx, sl, c, art, maxArchiveItems, data)
+		data = dir.EditorContent(ctx, sl, c, db, art, maxArchiveItems, data)
 	}
 	data = classifyANSICheck(ctx, sl, db, art.ID, data)
+	data = contentEncoding(ctx, sl, db, art.ID, data)
 	// page metadata
 	uri := filerecord.DownloadID(art)
 	data["canonical"] = strings.Join([]string{"f", uri}, "/")
@@ -131,13 +139,14 @@ func (dir Dirs) Artifact(
 		data = dir.updateMagicNumber(ctx, sl, db, art.ID, art.UUID.String, platform, data)
 	}
 	data = dir.attributions(art, data)
-	data = dir.otherRelations(art, data)
-	data = jsdosEmulator(sl, art, data)
+	data = dir.otherRelations(ctx, sl, db, art, sess.Editor(c), data)
+	data = jsdosEmulator(sl, art, emulatorSettings(ctx, sl, db, art.ID, data), data)
 	// performance sanity check for everyone other than Editors
 	tooManyItems := len(art.FileZipContent.String) > maxZipContent
 	if !tooManyItems || sess.Editor(c) {
 		// NOTE: this can cause a performance hit for archives with 10,000+ items
 		data = content(art, maxArchiveItems, data)
+		data = contentManifest(ctx, sl, db, art.ID, maxArchiveItems, data)
 	} else if tooManyItems {
 		data["contentDesc"] = "contains many files"
 	}
@@ -147,10 +156,9 @@ func (dir Dirs) Artifact(
 	if skip := render.NoScreenshot(art, dir.Preview.Path()); skip {
 		data["noScreenshot"] = true
 	}
-	if unsupported := filerecord.UnsupportedFile(art); unsupported {
-		data = dir.findBinarySAUCE(art, data)
-	} else {
-		data, err = dir.textfiles(art, sizeLimitBytes, data)
+	if unsupported := filerecord.UnsupportedFile(art); !unsupported {
+		charset, _ := data["encoding"].(string)
+		data, err = dir.textfiles(art, charset, sizeLimitBytes, data)
 		if err != nil {
 			defer clear(data)
 			sl.Error("dirs artifact",
@@ -158,11 +166,13 @@ func (dir Dirs) Artifact(
 				slog.Int64("id", art.ID), slog.Any("error", err))
 		}
 	}
+	data = contentSauces(ctx, sl, db, art.ID, data)
 	err = c.Render(http.StatusOK, name, data)
 	defer clear(data)
 	if err != nil {
 		return InternalErr(sl, c, name, errorWithID(err, dir.URI, art.ID))
 	}
+	hits.Add(art.ID, hits.View)
 	return nil
 }
 
@@ -218,10 +228,10 @@ func plainText(modMagic any) bool {
 // These are the editable fields for the file record that are only visible to the editor
 // after they have logged in.
 func (dir Dirs) EditorContent(
-	ctx context.Context, sl *slog.Logger, c *echo.Context,
+	ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB,
 	art *models.File, maxItems int, data map[string]any,
 ) map[string]any {
-	if nils.Slog("dirs editor context check failed", ctx, sl, c, art) {
+	if nils.Slog("dirs editor context check failed", ctx, sl, c, db, art) {
 		return data
 	}
 	d := command.Dirs{
@@ -246,7 +256,7 @@ func (dir Dirs) EditorContent(
 	data["modMagicNumber"] = simple.MagicAsTitle(sl, abs)
 	data["modDBModify"] = filerecord.LastModificationDate(art)
 	data["modStatModify"], data["modStatSizeB"], data["modStatSizeF"] = simple.StatHumanize(abs)
-	data["modDecompress"] = filerecord.ListContent(ctx, sl, maxItems, art, d, abs)
+	data["modDecompress"] = dir.decompress(ctx, sl, db, art, d, maxItems, abs)
 	if sess.Editor(c) {
 		data["modDecompressLoc"] = simple.MkContent(sl, abs)
 	}
@@ -260,8 +270,6 @@ func (dir Dirs) EditorContent(
 	data["modReadmeSuggest"] = filerecord.Readme(art)
 	data["disableReadme"] = filerecord.DisableReadme(art)
 	data["modZipContent"] = filerecord.ZipContent(art)
-	data["modRelations"] = filerecord.RelationsStr(art)
-	data["modWebsites"] = filerecord.WebsitesStr(art)
 	data["modOS"] = filerecord.TagProgram(art)
 	data["modTag"] = filerecord.TagCategory(art)
 	data["alertURL"] = filerecord.AlertURL(art)
@@ -277,9 +285,29 @@ func (dir Dirs) EditorContent(
 	return data
 }
 
+// decompress returns the list of files contained in the archive of the artifact for the editor.
+// The stored manifest of the archive is used, unless the manifest is yet to be read
+// or the archive has texts to copy to the extra directory, in which case the archive is extracted.
+func (dir Dirs) decompress(
+	ctx context.Context, sl *slog.Logger, db *sql.DB,
+	art *models.File, d command.Dirs, maxItems int, abs string,
+) template.HTML {
+	members, err := model.Manifest(ctx, db, art.ID)
+	if err != nil {
+		sl.Error("dirs editor manifest", slog.Int64("id", art.ID), slog.Any("error", err))
+	}
+	if htm, ok := filerecord.ListManifest(maxItems, art, d, members...); ok {
+		return htm
+	}
+	return filerecord.ListContent(ctx, sl, maxItems, art, d, dir.Queue, abs)
+}
+
 // textfiles can append either a plain textfile, ansi encoded text file, or binary text file to the data map.
 // Also handled is any embedded SAUCE metadata, that will be shown as "embed" information on the artifact page.
-func (dir Dirs) textfiles(art *models.File, sizeLimit int64, data map[string]any) (map[string]any, error) {
+// The charset is the key of the text encoding chosen by an editor, or an empty string to detect the encoding.
+func (dir Dirs) textfiles(
+	art *models.File, charset string, sizeLimit int64, data map[string]any,
+) (map[string]any, error) {
 	// these are required by the template and must always be set
 	data["contentBinary"] = ""
 	data["readmeSAUCE"] = false
@@ -293,7 +321,7 @@ func (dir Dirs) textfiles(art *models.File, sizeLimit int64, data map[string]any
 	const format = "dirs textfiles: %s: %w"
 	// INFO: The "sync.Pool" method should not be used in this func. It can cause an unintended effect of
 	// text duplication rendering. In July 2025, after research, sync pooling is better used for small, fixed width data.
-	buf, ruf, rec, err := readme.PlainTextBuffers(art, sizeLimit, dir.Download, dir.Extra)
+	buf, ruf, rec, err := readme.PlainTextBuffers(art, charset, sizeLimit, dir.Download, dir.Extra)
 	if err != nil {
 		if errors.Is(err, render.ErrDownload) {
 			data["noDownload"] = true
@@ -328,7 +356,21 @@ func (dir Dirs) textfiles(art *models.File, sizeLimit int64, data map[string]any
 	if simple.RTF(b) {
 		b = simple.StripRTF(b)
 	}
-	d, err := simpleCharmapEncodings(art, data, b...)
+	// the encodings without a site font are displayed as UTF-8,
+	// while the UTF-16 texts were already decoded when they were read
+	if c, ok := encodings.Find(charset); ok && !c.Native() {
+		if !c.Wide() {
+			p, err := encodings.Decode(b, c.Key)
+			if err != nil {
+				return data, fmt.Errorf(format, "decode", err)
+			}
+			b = p
+		}
+		buf = bytes.NewBuffer(b)
+		ruf = bytes.NewBuffer(b)
+		charset = encodings.UTF8
+	}
+	d, err := simpleCharmapEncodings(art, charset, data, b...)
 	if err != nil {
 		return data, fmt.Errorf(format, "text", err)
 	}
@@ -396,33 +438,6 @@ func unicodeEncodings(buf, ruf *bytes.Buffer, data map[string]any) map[string]an
 	return data
 }
 
-// findBinarySAUCE is used to read the SAUCE metadata embed into data files such
-// as pictures and image files.
-func (dir Dirs) findBinarySAUCE(art *models.File, data map[string]any) map[string]any {
-	if art == nil {
-		return data
-	}
-	name := filepath.Join(dir.Download.Path(), art.UUID.String)
-	file, err := os.Open(name)
-	if err != nil {
-		return data
-	}
-	defer func() { _ = file.Close() }()
-	rec, err := sauce.Read(file)
-	if err != nil {
-		return data
-	}
-	if useSAUCE := rec.ID == "SAUCE"; !useSAUCE {
-		return data
-	}
-	data["readmeSAUCE"] = true
-	data["sauceTitle"] = rec.Title
-	data["sauceAuthor"] = rec.Author
-	data["sauceGroup"] = rec.Group
-	data["sauceDate"] = rec.Date.Time.Format("2006 Jan 02")
-	return data
-}
-
 // updateMagicNumber updates the magic number for the file record of the artifact.
 // It must be called after both the dir.filemetadata and dir.Editor functions.
 //
@@ -508,6 +523,13 @@ func (dir Dirs) makeAssets(ctx context.Context, sl *slog.Logger,
 	default:
 		return data
 	}
+	if dir.Queue != nil {
+		if _, err := dir.Queue.Enqueue(ctx, jobs.ReplacementZip, root, uid); err != nil && sl != nil {
+			sl.Error(msg, slog.String("job queue", "zip archive compressor"),
+				slog.String("uuid", uid), slog.Any("error", err))
+		}
+		return data
+	}
 	i, err := dir.makeReplacementZip(root, uid)
 	if err != nil {
 		if sl != nil {
@@ -561,6 +583,21 @@ func requireReplacementZip(name string) bool {
 	return false
 }
 
+// ReplacementZip repackages the extracted files of the root directory as a deflated zip archive
+// that is saved to the extra directory using the uid as the filename. It is the task of the
+// [jobs.ReplacementZip] background job.
+func (dir Dirs) ReplacementZip(_ context.Context, sl *slog.Logger, root, uid string) error {
+	i, err := dir.makeReplacementZip(root, uid)
+	if err != nil {
+		return err
+	}
+	if sl != nil {
+		sl.Info("replacement zip", slog.String("success", "extra deflated zipfile created"),
+			slog.String("uuid", uid), slog.Int64("bytes extracted", i))
+	}
+	return nil
+}
+
 func (dir Dirs) makeReplacementZip(root, uid string) (int64, error) {
 	const format = "dirs make replacement zip: %w"
 	basename := uid + ".zip"
@@ -602,7 +639,19 @@ func (dir Dirs) makeTextfileImgs(ctx context.Context, sl *slog.Logger,
 	}
 	amigaFont := strings.EqualFold(platform, tags.TextAmiga.String()) ||
 		strings.EqualFold(platform, tags.Console.String())
-	if err := dirs.TextImager(ctx, sl, name, uid, amigaFont); err != nil {
+	if dir.Queue != nil {
+		kind := jobs.TextImager
+		if amigaFont {
+			kind = jobs.AmigaTextImager
+		}
+		if _, err := dir.Queue.Enqueue(ctx, kind, name, uid); err != nil {
+			sl.Error(msg, slog.String("job queue", "text imager"),
+				slog.String("uuid", uid), slog.Any("error", err))
+		}
+		return data
+	}
+	charset, _ := data["encoding"].(string)
+	if err := dirs.CharsetImager(ctx, sl, name, uid, charset, amigaFont); err != nil {
 		sl.Error(msg, slog.String("text imager", "conversion error"),
 			slog.String("uuid", uid), slog.Any("error", err))
 	}
@@ -769,13 +818,36 @@ func (dir Dirs) filemetadata(art *models.File, data map[string]any) map[string]a
 	return data
 }
 
-// otherRelations returns the other relations and external links for the file record of the artifact.
-func (dir Dirs) otherRelations(art *models.File, data map[string]any) map[string]any {
+// otherRelations returns the other relations, external links and keywords for the file record of the artifact.
+// The relations include those from other artifacts that target this artifact, and the relations
+// to the hidden artifacts are only shown to the editors. When the artifact has no typed relations or links,
+// the list_relations and list_links columns of the file record are used, which are migrated on startup.
+func (dir Dirs) otherRelations(
+	ctx context.Context, sl *slog.Logger, db *sql.DB, art *models.File, editor bool, data map[string]any,
+) map[string]any {
 	if art == nil {
 		return data
 	}
 	data["relations"] = filerecord.Relations(art)
 	data[websites] = filerecord.Websites(art)
+	data["keywords"] = []model.Keyword{}
+	if !nils.Slog("dirs other relations", ctx, sl, db) {
+		if rels, err := model.Relations(ctx, db, art.ID, editor); err != nil {
+			sl.Error("dirs other relations", slog.Int64("id", art.ID), slog.Any("error", err))
+		} else if len(rels) > 0 {
+			data["relations"] = filerecord.RelationRows(rels...)
+		}
+		if links, err := model.Links(ctx, db, art.ID); err != nil {
+			sl.Error("dirs other links", slog.Int64("id", art.ID), slog.Any("error", err))
+		} else if len(links) > 0 {
+			data[websites] = filerecord.LinkRows(links...)
+		}
+		if keywords, err := model.FileKeywords(ctx, db, art.ID); err != nil {
+			sl.Error("dirs other keywords", slog.Int64("id", art.ID), slog.Any("error", err))
+		} else {
+			data["keywords"] = keywords
+		}
+	}
 	data["demozoo"] = filerecord.IdenficationDZ(art)
 	data["pouet"] = filerecord.IdenficationPouet(art)
 	data["sixteenColors"] = filerecord.Idenfication16C(art)
@@ -784,8 +856,29 @@ func (dir Dirs) otherRelations(art *models.File, data map[string]any) map[string
 	return data
 }
 
+// emulatorSettings returns the emulator settings chosen by an editor for the artifact id
+// and appends them, along with the options of the settings, to the data map for the editor form.
+func emulatorSettings(
+	ctx context.Context, sl *slog.Logger, db *sql.DB, id int64, data map[string]any,
+) jsdos.Settings {
+	data["modEmulateSettings"] = jsdos.Settings{}
+	data["modEmulateJoysticks"] = jsdos.Joysticks()
+	data["modEmulateLayouts"] = jsdos.Layouts()
+	if nils.Slog("dirs emulator settings", ctx, sl, db) {
+		return jsdos.Settings{}
+	}
+	s, err := model.EmulatorSettings(ctx, db, id)
+	if err != nil {
+		sl.Error("dirs emulator settings", slog.Int64("id", id), slog.Any("error", err))
+		return jsdos.Settings{}
+	}
+	data["modEmulateSettings"] = s
+	return s
+}
+
 // jsdosEmulator returns the js-dos emulator data for the file record of the artifact.
-func jsdosEmulator(sl *slog.Logger, art *models.File, data map[string]any,
+// The settings are the emulator settings chosen by an editor for the artifact.
+func jsdosEmulator(sl *slog.Logger, art *models.File, s jsdos.Settings, data map[string]any,
 ) map[string]any {
 	if nils.Slog("jsdos emulator", sl, art) {
 		return data
@@ -820,7 +913,7 @@ func jsdosEmulator(sl *slog.Logger, art *models.File, data map[string]any,
 		return data
 	}
 	data["jsdos6RunGuess"] = guess
-	cfg, err := model.JsDosConfig(art)
+	cfg, err := model.JsDosConfig(art, s)
 	if err != nil {
 		if sl != nil {
 			sl.Error("jsdos6 config",
@@ -878,8 +971,10 @@ func content(art *models.File, maxItems int, data map[string]any) map[string]any
 		items = items[:maxItems]
 	}
 	paths := slices.Compact(items)
-	data["content"] = paths  // This is displayed as "#	Filename or path"
-	data["contentDesc"] = "" // This is used by "Download info"
+	ext := strings.ToLower(filepath.Ext(art.Filename.String))
+	data["contentLinks"] = slices.Contains(download.Archives(), ext) // Links the files to their downloads
+	data["content"] = paths                                          // This is displayed as "#	Filename or path"
+	data["contentDesc"] = ""                                         // This is used by "Download info"
 	l := len(paths)
 	switch l {
 	case 0:
@@ -892,6 +987,101 @@ func content(art *models.File, maxItems int, data map[string]any) map[string]any
 	return data
 }
 
+// contentManifest returns the archive manifest of the artifact id, which replaces the list of paths
+// in the "view content" modal with the sizes, dates and signatures of the files.
+// The manifest is missing when the archive is yet to be read, in which case the list of paths is used.
+func contentManifest(
+	ctx context.Context, sl *slog.Logger, db *sql.DB, id int64, maxItems int, data map[string]any,
+) map[string]any {
+	data["manifest"] = []manifest.Member{}
+	if nils.Slog("dirs content manifest", ctx, sl, db) {
+		return data
+	}
+	members, err := model.Manifest(ctx, db, id)
+	if err != nil {
+		sl.Error("dirs content manifest", slog.Int64("id", id), slog.Any("error", err))
+		return data
+	}
+	if len(members) > maxItems {
+		members = members[:maxItems]
+	}
+	data["manifest"] = members
+	return data
+}
+
+// contentSauces returns the stored SAUCE metadata of the artifact id, which lists the records of the
+// files contained in the archive and the metadata suggested to the editor. When the readme has no SAUCE,
+// the record of the download is used for the "embed" information on the artifact page.
+func contentSauces(
+	ctx context.Context, sl *slog.Logger, db *sql.DB, id int64, data map[string]any,
+) map[string]any {
+	// the number of archive members with SAUCE metadata to list on the artifact page
+	const maxItems = 10
+	data["sauces"] = []sauces.Record{}
+	data["saucesCount"] = 0
+	if nils.Slog("dirs content sauces", ctx, sl, db) {
+		return data
+	}
+	recs, err := model.Sauces(ctx, db, id)
+	if err != nil {
+		sl.Error("dirs content sauces", slog.Int64("id", id), slog.Any("error", err))
+		return data
+	}
+	if s, ok := sauces.Suggest(recs...); ok {
+		data["sauceSuggest"] = s
+	}
+	members := make([]sauces.Record, 0, len(recs))
+	for _, rec := range recs {
+		if rec.Member != "" {
+			members = append(members, rec)
+			continue
+		}
+		if embed, _ := data["readmeSAUCE"].(bool); embed {
+			continue
+		}
+		data["readmeSAUCE"] = true
+		data["sauceTitle"] = rec.Title
+		data["sauceAuthor"] = rec.Author
+		data["sauceGroup"] = rec.Group
+		data["sauceDate"] = ""
+		if rec.Date.Valid {
+			data["sauceDate"] = rec.Date.Time.Format("2006 Jan 02")
+		}
+	}
+	data["saucesCount"] = len(members)
+	if len(members) > maxItems {
+		members = members[:maxItems]
+	}
+	data["sauces"] = members
+	return data
+}
+
+// contentEncoding returns the text encoding chosen by an editor for the artifact id,
+// and the encoding detected from its text with the list of encodings used by the editor.
+func contentEncoding(
+	ctx context.Context, sl *slog.Logger, db *sql.DB, id int64, data map[string]any,
+) map[string]any {
+	data["encoding"] = ""
+	data["encodings"] = encodings.List()
+	data["encodingDetected"] = encodings.Detection{}
+	if nils.Slog("dirs content encoding", ctx, sl, db) {
+		return data
+	}
+	key, err := model.Encoding(ctx, db, id)
+	if err != nil {
+		sl.Error("dirs content encoding", slog.Int64("id", id), slog.Any("error", err))
+		return data
+	}
+	data["encoding"] = key
+	detected, err := model.Detected(ctx, db, id)
+	if err != nil {
+		sl.Error("dirs content encoding", slog.Int64("id", id), slog.Any("error", err))
+		return data
+	}
+	data["encodingDetected"] = detected
+	return data
+}
+
 // errorWithID returns an error with the artifact ID appended to the error message.
 // The key string is expected any will always be displayed in the error message.
 // The id can be an integer or string value and should be the database numeric ID.
@@ -954,7 +1144,12 @@ func LockIn80Columns(year int16, src ...byte) bool {
 //
 // All text content, either CP437, ISO, or UTF-8, also goes through a normalization process,
 // to replace any "special" characters, such as non-breaking-spaces with standard spaces.
-func simpleCharmapEncodings(art *models.File, data map[string]any, b ...byte) (map[string]any, error) { //nolint:funlen
+//
+// The charset is the key of the text encoding chosen by an editor, which also selects the font,
+// or an empty string to determine the encoding.
+func simpleCharmapEncodings( //nolint:funlen
+	art *models.File, charset string, data map[string]any, b ...byte,
+) (map[string]any, error) {
 	if len(b) == 0 || art == nil || art.RetrotxtNoReadme.Int16 != 0 {
 		return data, nil
 	}
@@ -971,6 +1166,9 @@ func simpleCharmapEncodings(art *models.File, data map[string]any, b ...byte) (m
 	data["preClassCP437"] = "d-none " + fontname
 	platform := data["platform"]
 	topazFont := platform == "textamiga" || platform == "console"
+	if c, ok := encodings.Find(charset); ok {
+		topazFont = c.Topaz() || c.Key == encodings.Latin1
+	}
 	if topazFont {
 		data["topazCheck"] = chk
 		data["preClassLatin1"] = ""
@@ -987,7 +1185,7 @@ func simpleCharmapEncodings(art *models.File, data map[string]any, b ...byte) (m
 	if simple.RTF(b) {
 		b = simple.StripRTF(b)
 	}
-	textEncoding := render.Encoder(art, bytes.NewReader(b))
+	textEncoding := render.Charset(art, charset, bytes.NewReader(b))
 	switch textEncoding {
 	case charmap.ISO8859_1:
 		b = bytes.ReplaceAll(b, []byte{nbsp}, []byte{sp})
@@ -0,0 +1,188 @@
+package app
+
+// Package file feed.go contains the handlers for the Atom and RSS syndication feeds of the artifacts.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Defacto2/helper"
+	"github.com/Defacto2/server/handler/app/internal/filerecord"
+	"github.com/Defacto2/server/handler/app/internal/fileslice"
+	"github.com/Defacto2/server/handler/feed"
+	"github.com/Defacto2/server/handler/releaser"
+	"github.com/Defacto2/server/internal/config"
+	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/Defacto2/server/internal/tags"
+	"github.com/Defacto2/server/model"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+	"github.com/labstack/echo/v5"
+)
+
+// FeedKind is the kind of artifacts listing of a syndication feed.
+type FeedKind string
+
+const (
+	FeedFiles    FeedKind = "files" // FeedFiles is an artifacts category, such as "new-uploads" or "intro".
+	FeedReleaser FeedKind = "g"     // FeedReleaser is a releaser, such as "razor-1911".
+	FeedScener   FeedKind = "p"     // FeedScener is a scener, such as "jed".
+)
+
+// Feed is the handler for the Atom and RSS syndication feeds of the artifacts.
+// The format and the id of the listing are taken from the route parameters,
+// and the conditional GET headers of the request are honored.
+func (dir Dirs) Feed(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, kind FeedKind) error {
+	const msg = "feed handler"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	format := feed.Format(c.Param("format"))
+	if !format.Valid() {
+		return StatusErr(sl, c, http.StatusNotFound, c.Param("format"))
+	}
+	id := c.Param("id")
+	l, err := dir.listing(ctx, db, kind, id)
+	if err != nil {
+		sl.Error(msg, slog.String("kind", string(kind)), slog.String("id", id), slog.Any("error", err))
+		return StatusErr(sl, c, http.StatusInternalServerError, id)
+	}
+	if l == nil {
+		return StatusErr(sl, c, http.StatusNotFound, id)
+	}
+	l.Self = "/feed/" + string(format) + "/" + string(kind) + "/" + id
+	etag, modified := l.ETag(format), l.Updated()
+	h := c.Response().Header()
+	h.Set("ETag", etag)
+	if !modified.IsZero() {
+		h.Set("Last-Modified", modified.UTC().Format(http.TimeFormat))
+	}
+	if feed.NotModified(c.Request(), etag, modified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	b, err := l.Marshal(format)
+	if err != nil {
+		return InternalErr(sl, c, msg, err)
+	}
+	return c.Blob(http.StatusOK, format.MIME(), b)
+}
+
+// listing returns the feed listing of the kind and id, or nil if the listing does not exist.
+func (dir Dirs) listing(ctx context.Context, db *sql.DB, kind FeedKind, id string) (*feed.Listing, error) {
+	var (
+		fs  models.FileSlice
+		err error
+		l   feed.Listing
+	)
+	switch kind {
+	case FeedFiles:
+		if !feedFiles(id) {
+			return nil, nil
+		}
+		var mods []qm.QueryMod
+		if expr, ok := fileslice.Expr(id); ok {
+			mods = append(mods, expr)
+		}
+		updated := fileslice.Match(id) == fileslice.NewUpdates
+		logo, h1sub, _ := fileslice.FileInfo(id)
+		l.Title = "Defacto2 " + logo
+		l.Subtitle = "The most recent artifacts of " + h1sub + " on Defacto2."
+		l.Link = "/files/" + id
+		fs, err = model.Feed(ctx, db, model.FeedLimit, updated, mods...)
+	case FeedReleaser:
+		name := releaser.Link(id)
+		l.Title = "Defacto2 " + name
+		l.Subtitle = "The most recent artifacts released by " + name + " on Defacto2."
+		l.Link = "/g/" + id
+		fs, err = model.FeedReleaser(ctx, db, id, model.FeedLimit)
+	case FeedScener:
+		name := releaser.Link(id)
+		l.Title = "Defacto2 " + name
+		l.Subtitle = "The most recent artifacts attributed to " + name + " on Defacto2."
+		l.Link = "/p/" + id
+		fs, err = model.FeedScener(ctx, db, id, model.FeedLimit)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("listing %s %q: %w", kind, id, err)
+	}
+	if len(fs) == 0 && kind != FeedFiles {
+		return nil, nil
+	}
+	l.Items = make([]feed.Item, 0, len(fs))
+	for _, art := range fs {
+		if art == nil {
+			continue
+		}
+		l.Items = append(l.Items, FeedItem(art, dir.Thumbnail))
+	}
+	return &l, nil
+}
+
+// FeedItem returns the syndication feed item of the artifact.
+// The thumbnail directory is used to locate the thumbnail image that is used as the item enclosure.
+func FeedItem(art *models.File, thumbnail dir.Directory) feed.Item {
+	if art == nil {
+		return feed.Item{}
+	}
+	category, platform := filerecord.TagCategory(art), filerecord.TagProgram(art)
+	id := helper.ObfuscateID(art.ID)
+	item := feed.Item{
+		ID:        "tag:defacto2.net,2024:artifact/" + id,
+		Title:     filerecord.Description(art),
+		Link:      "/f/" + id,
+		Summary:   tags.Humanize(tags.TagByURI(platform), tags.TagByURI(category)),
+		Category:  category,
+		Published: art.Createdat.Time,
+		Updated:   art.Updatedat.Time,
+		Enclosure: feedThumb(filerecord.UnID(art), thumbnail),
+	}
+	if item.Updated.IsZero() {
+		item.Updated = item.Published
+	}
+	if item.Updated.IsZero() {
+		item.Updated = time.Unix(0, 0)
+	}
+	return item
+}
+
+// feedFiles returns true if the artifacts category URI has a syndication feed.
+func feedFiles(uri string) bool {
+	switch fileslice.Match(uri) { //nolint:exhaustive
+	case fileslice.NewUploads, fileslice.NewUpdates:
+		return true
+	}
+	_, ok := fileslice.Expr(uri)
+	return ok
+}
+
+// feedThumb returns the thumbnail enclosure of the unid or nil if there is no thumbnail image.
+func feedThumb(unid string, thumbnail dir.Directory) *feed.Enclosure {
+	if unid == "" || thumbnail == "" {
+		return nil
+	}
+	mimes := []struct{ ext, mime string }{
+		{".webp", "image/webp"},
+		{".png", "image/png"},
+	}
+	for _, m := range mimes {
+		st, err := os.Stat(thumbnail.Join(unid + m.ext))
+		if err != nil || st.IsDir() {
+			continue
+		}
+		return &feed.Enclosure{
+			URL:    strings.Join([]string{config.StaticThumb(), unid + m.ext}, "/"),
+			Type:   m.mime,
+			Length: st.Size(),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package app
+
+// Package file identity.go contains the functions for the scener identities,
+// which link the credited names of the artifacts to the people who use them.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Defacto2/helper"
+	"github.com/Defacto2/server/handler/releaser"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+	"github.com/aarondl/sqlboiler/v4/boil"
+)
+
+// Identity contains the details of a scener identity for the scener pages and the API.
+type Identity struct {
+	Name    string      // Name is the display name of the scener.
+	Aliases []string    // Aliases are the other names that the scener is credited with.
+	Demozoo int         // Demozoo is the Demozoo scener id, or 0.
+	Roles   []string    // Roles are the roles the scener is credited for.
+	Members []GroupLink // Members are the groups the scener was a member of.
+	Groups  []GroupLink // Groups are the groups the scener released artifacts with.
+}
+
+// GroupLink is a link to a releaser page.
+type GroupLink struct {
+	URI  string `json:"uri"`  // URI is the URL path slug of the releaser.
+	Name string `json:"name"` // Name is the display name of the releaser.
+}
+
+// ScenerIdentities creates the scener identities for the unknown credited names of the artifacts
+// and links the credits of the artifacts to the sceners.
+func ScenerIdentities(ctx context.Context, sl *slog.Logger, db *sql.DB) error {
+	const msg = "scener identities"
+	if err := nils.Check(ctx, sl, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	sync, err := model.SyncIdentities(ctx, db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if sync.Sceners > 0 || sync.Linked > 0 || sync.Removed > 0 {
+		sl.Info(msg, slog.Int64("new sceners", sync.Sceners),
+			slog.Int64("linked credits", sync.Linked), slog.Int64("removed credits", sync.Removed))
+	}
+	if sync.Clashes > 0 {
+		sl.Warn(msg, slog.String("clashes", "names that share the slug of another scener must be claimed"),
+			slog.Int64("names", sync.Clashes))
+	}
+	return nil
+}
+
+// scenerIdentity returns the scener identity of the URI slug.
+// When the slug is not an identity, the slugs of the identities that use it as an alias are returned.
+// A database problem is logged and returns nothing, so the scener page falls back to the credit columns.
+func scenerIdentity(ctx context.Context, sl *slog.Logger, exec boil.ContextExecutor, uri string) (
+	*model.Identity, []string,
+) {
+	const msg = "scener identity"
+	s, err := model.OneIdentity(ctx, exec, uri)
+	if err == nil {
+		return s, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		sl.Warn(msg, slog.String("uri", uri), slog.Any("error", err))
+		return nil, nil
+	}
+	slugs, err := model.IdentitySlugs(ctx, exec, uri)
+	if err != nil {
+		sl.Warn(msg, slog.String("uri", uri), slog.Any("error", err))
+		return nil, nil
+	}
+	return nil, slugs
+}
+
+// identityDetails returns the details of the scener identity, including the groups the scener released with.
+func identityDetails(ctx context.Context, exec boil.ContextExecutor, s *model.Identity) (Identity, error) {
+	const msg = "identity details"
+	if err := nils.Check(ctx, exec, s); err != nil {
+		return Identity{}, fmt.Errorf("%s: %w", msg, err)
+	}
+	ident := Identity{
+		Name:    s.Name,
+		Demozoo: s.Demozoo,
+	}
+	for _, alias := range s.Aliases {
+		if !strings.EqualFold(alias, s.Name) {
+			ident.Aliases = append(ident.Aliases, alias)
+		}
+	}
+	roles, err := s.Roles(ctx, exec)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%s: %w", msg, err)
+	}
+	for _, role := range roles {
+		ident.Roles = append(ident.Roles, string(role))
+	}
+	for _, slug := range s.Members {
+		ident.Members = append(ident.Members, GroupLink{URI: slug, Name: releaser.Link(slug)})
+	}
+	groups, err := s.Releasers(ctx, exec)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%s: %w", msg, err)
+	}
+	for _, group := range groups {
+		slug := releaser.Obfuscate(group)
+		ident.Groups = append(ident.Groups, GroupLink{URI: slug, Name: releaser.Link(slug)})
+	}
+	return ident, nil
+}
+
+// identitySum is a helper function for Sceners that returns the statistics of the scener identity.
+func identitySum(ctx context.Context, exec boil.ContextExecutor, s *model.Identity) (map[string]string, error) {
+	const format = "identity sum %q: %w"
+	if err := nils.Check(ctx, exec, s); err != nil {
+		return nil, fmt.Errorf(format, "", err)
+	}
+	var m model.Summary
+	if err := s.Summary(ctx, exec, &m); err != nil {
+		return nil, fmt.Errorf(format, s.Slug, err)
+	}
+	d := map[string]string{
+		files: string(ByteFileS("file", m.SumCount.Int64, m.SumBytes.Int64)),
+		years: helper.Years(m.MinYear.Int16, m.MaxYear.Int16),
+	}
+	return d, nil
+}
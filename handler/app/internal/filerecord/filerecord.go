@@ -12,6 +12,7 @@ import (
 	"math"
 	"net/url"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
 	"strconv"
@@ -29,7 +30,9 @@ import (
 	"github.com/Defacto2/server/internal/command"
 	"github.com/Defacto2/server/internal/dir"
 	"github.com/Defacto2/server/internal/extensions"
+	"github.com/Defacto2/server/internal/jobs"
 	"github.com/Defacto2/server/internal/logs"
+	"github.com/Defacto2/server/internal/manifest"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/Defacto2/server/internal/postgres/models"
 	"github.com/Defacto2/server/internal/tags"
@@ -155,7 +158,7 @@ func (m *ListEntry) column1() string {
 	case m.Images:
 		return buttonImage(m.UniqueID, m.name)
 	case m.Texts:
-		return buttonText(m.UniqueID, m.name, m.platform, m.Signature)
+		return buttonText(m.UniqueID, m.name, m.platform)
 	case useBinary:
 		return buttonTextBinary(m.UniqueID, m.name)
 	default:
@@ -194,16 +197,13 @@ func (m *ListEntry) column3() string {
 }
 
 // buttonText creates a link to "/editor/readme/URI/ID/FILENAME".
-func buttonText(id, filename, platform, sign string) string {
+func buttonText(id, filename, platform string) string {
 	uri := "preview"
 	t1 := tags.TextAmiga.String()
 	t2 := tags.Console.String()
 	if strings.EqualFold(platform, t1) || strings.EqualFold(platform, t2) {
-		if !strings.Contains(strings.ToLower(sign), "ansi") {
-			// ansilove does not color ANSI using "ced" or "workbench"
-			// instead, it renders the files as ASCII text files
-			uri = "preview-amiga"
-		}
+		// the amiga previews use the topaz font and palette for both the ANSI and ASCII texts
+		uri = "preview-amiga"
 	}
 	return buttonPreview(uri, id, filename)
 }
@@ -738,8 +738,12 @@ func (e *entry) parseMusicID3(path string) bool {
 // This should only ever be used by the admin editor mode qw it extracts the file archve
 // to a temporary directory, to allow its extracted content can be parsed to determine usability
 // using magicfile techniques and other metadata.
+//
+// The copies of the FILE_ID.DIZ and text files to the extra directory are queued as background
+// jobs, unless the queue is nil, in which case they are made before the function returns.
 func ListContent( //nolint:cyclop,gocognit,funlen
-	ctx context.Context, sl *slog.Logger, maxItems int, art *models.File, dirs command.Dirs, src string,
+	ctx context.Context, sl *slog.Logger, maxItems int, art *models.File, dirs command.Dirs, q *jobs.Queue,
+	src string,
 ) template.HTML {
 	if nils.Slog("filerecord list context", ctx, sl) {
 		return ""
@@ -872,6 +876,7 @@ func ListContent( //nolint:cyclop,gocognit,funlen
 	}
 	c := content{
 		dirs:          dirs,
+		queue:         q,
 		zeroByteFiles: zeroByteFiles,
 		dst:           tmpRoot,
 		src:           src,
@@ -881,8 +886,128 @@ func ListContent( //nolint:cyclop,gocognit,funlen
 	return c.renderContent(ctx, sl, &b, names...)
 }
 
+// ListManifest returns a list of the files contained in the stored archive file, using the
+// manifest of the archive that is saved in the database, so the archive is not extracted.
+// This is used to generate the HTML for the "Download content" section of the File editor.
+//
+// The false value is returned when there is no manifest, or when a FILE_ID.DIZ or text
+// in the archive has yet to be copied to the extra directory, as ListContent must extract
+// the archive to make the copies.
+func ListManifest(maxItems int, art *models.File, dirs command.Dirs, members ...manifest.Member) (template.HTML, bool) {
+	if art == nil || !art.UUID.Valid || len(members) == 0 {
+		return "", false
+	}
+	platform := strings.TrimSpace(strings.ToLower(art.Platform.String))
+	if !tags.IsPlatform(platform) {
+		return "", false
+	}
+	section := strings.TrimSpace(strings.ToLower(art.Section.String))
+	unid := art.UUID.String
+	byPath := make(map[string]manifest.Member, len(members))
+	paths := make([]string, 0, len(members))
+	for _, m := range members {
+		byPath[m.Path] = m
+		paths = append(paths, m.Path)
+	}
+	if len(paths) > maxItems {
+		paths = paths[:maxItems]
+	}
+	results := readme.SortList(false, strings.Join(paths, "\n"))
+	var b strings.Builder
+	entries, zeroByteFiles := 0, 0
+	names := []string{}
+	for _, rel := range results {
+		m, ok := byPath[rel]
+		if !ok {
+			continue
+		}
+		e := entry{zeros: zeroByteFiles} //nolint:exhaustruct
+		if e.ParseMember(m, platform) {
+			zeroByteFiles = e.zeros
+			continue
+		}
+		entries++
+		if e.text {
+			names = append(names, path.Base(rel))
+		}
+		le := listEntry(e, filepath.FromSlash(rel), unid)
+		b.WriteString(le.HTML(e.bytes, platform, section))
+		if entries > maxItems {
+			break
+		}
+	}
+	if files := len(members); files > maxItems {
+		more := fmt.Sprintf(`<div class="border-bottom row mb-1">skipped %d other files</div>`, files-maxItems)
+		b.WriteString(more)
+	}
+	if !copied(dirs.Extra, unid, helper.SortNames("/", names)...) {
+		return "", false
+	}
+	b.WriteString(skippedEmpty(zeroByteFiles))
+	return template.HTML(b.String()), true
+}
+
+// ParseMember parses the archive member of the manifest and returns true if it should be skipped.
+// Unlike ParseDirEntry, the member is not read, so the image and music details are not available.
+func (e *entry) ParseMember(m manifest.Member, platform string) bool {
+	const skipEntry = true
+	switch strings.ToLower(path.Ext(m.Path)) {
+	case bat, cmd, com, exe, ini:
+		return skipEntry
+	}
+	e.bytes = m.Size
+	if e.bytes <= 0 {
+		e.zeros++
+		return skipEntry
+	}
+	e.size = humanize.Bytes(uint64(e.bytes))
+	e.sign = signature(m.Signature)
+	platform = strings.TrimSpace(platform)
+	e.image = isImage(e.sign)
+	e.text = isText(e.sign)
+	e.bintext = isBinaryText(e.sign, platform)
+	e.program = isProgram(e.sign, platform)
+	return !skipEntry
+}
+
+// signature returns the magic number signature of the title that is stored in the manifest,
+// or the unknown signature when the title is not an image, text or program.
+func signature(title string) magicnumber.Signature {
+	for _, sigs := range [][]magicnumber.Signature{
+		magicnumber.Images(), magicnumber.Texts(), magicnumber.Programs(),
+	} {
+		for _, sign := range sigs {
+			if sign.Title() == title {
+				return sign
+			}
+		}
+	}
+	return magicnumber.Unknown
+}
+
+// copied returns true if the FILE_ID.DIZ and the text that renderContent would copy
+// from the named texts of the archive already exist in the extra directory.
+func copied(extra dir.Directory, unid string, names ...string) bool {
+	exists := func(name string) bool {
+		st, err := os.Stat(extra.Join(name))
+		return err == nil && st.Size() > 0
+	}
+	diz := indexDiz(names...)
+	if diz > -1 && !exists(unid+".diz") {
+		return false
+	}
+	const pair = 2
+	l := len(names)
+	text := (diz == -1 && l == 1) || (diz > -1 && l == pair)
+	if text && !exists(unid+".txt") {
+		return false
+	}
+	return true
+}
+
 type content struct {
 	dirs          command.Dirs
+	queue         *jobs.Queue
 	dst           string
 	src           string
 	unid          string
@@ -905,7 +1030,7 @@ func (c content) renderContent(
 			elms = names[diz]
 		}
 		srcDiz := filepath.Join(c.dst, elms)
-		if err := c.dirs.DizDeferred(sl, srcDiz, c.unid); err != nil {
+		if err := c.deferred(ctx, sl, jobs.DizDeferred, srcDiz); err != nil {
 			b.Reset()
 			return template.HTML(err.Error())
 		}
@@ -934,7 +1059,7 @@ func (c content) renderContent(
 		srcNFO = filepath.Join(c.dst, elms)
 	}
 	if srcNFO != "" {
-		if err := c.dirs.TextDeferred(ctx, sl, srcNFO, c.unid); err != nil {
+		if err := c.deferred(ctx, sl, jobs.TextDeferred, srcNFO); err != nil {
 			b.Reset()
 			return template.HTML(err.Error())
 		}
@@ -943,6 +1068,19 @@ func (c content) renderContent(
 	return template.HTML(b.String())
 }
 
+// deferred queues the job kind to copy the named source file to the extra directory,
+// or when there is no queue, the copy is made immediately.
+func (c content) deferred(ctx context.Context, sl *slog.Logger, kind jobs.Kind, src string) error {
+	if c.queue != nil {
+		_, err := c.queue.Enqueue(ctx, kind, src, c.unid)
+		return err
+	}
+	if kind == jobs.DizDeferred {
+		return c.dirs.DizDeferred(sl, src, c.unid)
+	}
+	return c.dirs.TextDeferred(ctx, sl, src, c.unid)
+}
+
 func indexDiz(names ...string) int {
 	for i, name := range names {
 		s := strings.TrimSpace(name)
@@ -1713,15 +1851,28 @@ func Relations(art *models.File) template.HTML {
 	return template.HTML(rows.String())
 }
 
-// RelationsStr returns the list of relationships for the file record as a string.
-func RelationsStr(art *models.File) string {
-	if art == nil {
-		return ""
-	}
-	if art.ListRelations.Valid {
-		return strings.TrimSpace(art.ListRelations.String)
+// RelationRows returns the typed relations of an artifact as table rows,
+// with each row describing the relation and linking to the other artifact.
+func RelationRows(rels ...model.Relation) template.HTML {
+	var rows strings.Builder
+	const route = `/f/`
+	const class = `fw-light text-secondary`
+	const format = `<tr><th scope="row"><small class="` + class + `">%s</small></th>` +
+		`<td><small><a class="text-truncate" href="%s">%s</a></small></td></tr>`
+	for r := range slices.Values(rels) {
+		other := r.Other()
+		if other < 1 {
+			continue
+		}
+		title := r.Title
+		if title == "" {
+			title = "Artifact " + strconv.FormatInt(other, 10)
+		}
+		href := route + helper.ObfuscateID(other)
+		fmt.Fprintf(&rows, format, template.HTMLEscapeString(r.Description()),
+			href, template.HTMLEscapeString(title))
 	}
-	return ""
+	return template.HTML(rows.String())
 }
 
 // ReleaserPair returns the pair of releaser names for the file record.
@@ -1869,15 +2020,37 @@ func Websites(art *models.File) template.HTML {
 	return template.HTML(rows.String())
 }
 
-// WebsitesStr returns the list of links for the file record as a string.
-func WebsitesStr(art *models.File) string {
-	if art == nil {
-		return ""
+// LinkRows returns the typed external links of an artifact as table rows.
+func LinkRows(links ...model.Link) template.HTML {
+	var rows strings.Builder
+	const class = `fw-light text-secondary`
+	const aclass = `link-offset-3 icon-link icon-link-hover`
+	const format = `<tr><th scope="row"><small class="` + class + `">%s</small></th>` +
+		`<td><small><a class="` + aclass + `" href="%s">%s %s</a></small></td></tr>`
+	for link := range slices.Values(links) {
+		href, err := model.LinkURL(link.URL)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&rows, format, linkTitle(link.Kind), template.HTMLEscapeString(href),
+			template.HTMLEscapeString(link.Label), LinkSVG())
 	}
-	if art.ListLinks.Valid {
-		return strings.TrimSpace(art.ListLinks.String)
+	return template.HTML(rows.String())
+}
+
+// linkTitle returns the table row header for the kind of external link.
+func linkTitle(kind model.LinkKind) string {
+	switch kind {
+	case model.LinkArticle:
+		return "Article"
+	case model.LinkVideo:
+		return "Video"
+	case model.LinkArchive:
+		return "Archived at"
+	case model.LinkWebsite:
+		return "Link to"
 	}
-	return ""
+	return "Link to"
 }
 
 // ZipContent returns the archive content of the file download, or an empty string if not an archive file.
@@ -14,7 +14,9 @@ import (
 	"github.com/Defacto2/server/internal/command"
 	"github.com/Defacto2/server/internal/dir"
 	"github.com/Defacto2/server/internal/logs"
+	"github.com/Defacto2/server/internal/manifest"
 	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/Defacto2/server/model"
 	"github.com/aarondl/null/v8"
 	"github.com/nalgeon/be"
 )
@@ -46,6 +48,29 @@ func TestWebsites(t *testing.T) {
 	be.Equal(t, s, "")
 }
 
+func TestLinkRows(t *testing.T) {
+	t.Parallel()
+	s := filerecord.LinkRows()
+	be.Equal(t, s, "")
+	s = filerecord.LinkRows(model.Link{Kind: model.LinkVideo, Label: "Demo <video>", URL: "example.com/v"})
+	be.True(t, strings.Contains(string(s), `href="https://example.com/v">Demo &lt;video&gt;`))
+	be.True(t, strings.Contains(string(s), `>Video</small>`))
+	s = filerecord.LinkRows(model.Link{Kind: model.LinkWebsite, Label: "Bad", URL: "ftp://example.com"})
+	be.Equal(t, s, "")
+}
+
+func TestRelationRows(t *testing.T) {
+	t.Parallel()
+	s := filerecord.RelationRows()
+	be.Equal(t, s, "")
+	s = filerecord.RelationRows(model.Relation{FileID: 1, TargetID: 2, Kind: model.RelationNFO, Title: "Intro"})
+	be.True(t, strings.Contains(string(s), `NFO for</small>`))
+	be.True(t, strings.Contains(string(s), `>Intro</a>`))
+	s = filerecord.RelationRows(model.Relation{FileID: 1, TargetID: 2, Kind: model.RelationNFO, Reverse: true})
+	be.True(t, strings.Contains(string(s), `NFO text</small>`))
+	be.True(t, strings.Contains(string(s), `>Artifact 1</a>`))
+}
+
 func TestUnsupportedFile(t *testing.T) {
 	t.Parallel()
 	x := models.File{}
@@ -332,34 +357,56 @@ func TestListContent(t *testing.T) {
 	x := models.File{}
 	dirs := command.Dirs{}
 	sl := slog.Default()
-	s := filerecord.ListContent(ctx, sl, -1, &x, dirs, "")
+	s := filerecord.ListContent(ctx, sl, -1, &x, dirs, nil, "")
 	find := strings.Contains(string(s), "no UUID")
 	be.True(t, find)
 
 	x.UUID = null.StringFrom(r0)
-	s = filerecord.ListContent(ctx, sl, -1, &x, dirs, "")
+	s = filerecord.ListContent(ctx, sl, -1, &x, dirs, nil, "")
 	find = strings.Contains(string(s), "invalid platform")
 	be.True(t, find)
 
 	x.Platform = null.StringFrom("dos")
-	s = filerecord.ListContent(ctx, sl, -1, &x, dirs, "")
+	s = filerecord.ListContent(ctx, sl, -1, &x, dirs, nil, "")
 	find = strings.Contains(string(s), "cannot stat file")
 	be.True(t, find)
 
 	src, err := filepath.Abs("testdata")
 	be.Err(t, err, nil)
-	s = filerecord.ListContent(ctx, sl, -1, &x, dirs, src)
+	s = filerecord.ListContent(ctx, sl, -1, &x, dirs, nil, src)
 	find = strings.Contains(string(s), "error, ")
 	be.True(t, find)
 
 	tmpDir := t.TempDir()
 	err = command.CopyFile(logs.Discard(), filepath.Join("testdata", "archive.zip"), filepath.Join(tmpDir, "archive.zip"))
 	be.Err(t, err, nil)
-	s = filerecord.ListContent(ctx, sl, -1, &x, dirs, tmpDir)
+	s = filerecord.ListContent(ctx, sl, -1, &x, dirs, nil, tmpDir)
 	find = strings.Contains(string(s), "error, ")
 	be.True(t, find)
 }
 
+func TestListManifest(t *testing.T) {
+	t.Parallel()
+	x := models.File{}
+	dirs := command.Dirs{}
+	_, ok := filerecord.ListManifest(-1, &x, dirs)
+	be.True(t, !ok)
+	x.UUID = null.StringFrom(r0)
+	x.Platform = null.StringFrom("dos")
+	_, ok = filerecord.ListManifest(10, &x, dirs)
+	be.True(t, !ok)
+	members := []manifest.Member{
+		{Path: "demo.exe", Size: 10},
+		{Path: "empty.dat", Size: 0},
+		{Path: "data/music.mod", Size: 100},
+	}
+	s, ok := filerecord.ListManifest(10, &x, dirs, members...)
+	be.True(t, ok)
+	be.True(t, strings.Contains(string(s), "music.mod"))
+	be.True(t, !strings.Contains(string(s), "demo.exe"))
+	be.True(t, strings.Contains(string(s), "skipped 1 empty"))
+}
+
 // TestListContentHappyPath tests ListContent with a valid archive to ensure
 // no excess blank lines are present (regression test for slice bounds bug).
 // Due to archive extraction complexity, this test verifies the bug would have manifested
@@ -385,7 +432,7 @@ func TestListContentHappyPath(t *testing.T) {
 
 	// Call ListContent - it may error due to extraction issues, but we verify
 	// the function handles the slice bounds correctly (doesn't crash or return nil)
-	result := filerecord.ListContent(ctx, sl, -1, &x, dirs, tmpDir)
+	result := filerecord.ListContent(ctx, sl, -1, &x, dirs, nil, tmpDir)
 
 	// The key test: result is not nil/empty (function executed)
 	// and doesn't have unexpected format issues from the slice bug
@@ -16,7 +16,9 @@ import (
 	"github.com/Defacto2/server/internal/postgres/models"
 	"github.com/Defacto2/server/internal/tags"
 	"github.com/Defacto2/server/model"
+	"github.com/Defacto2/server/model/querymod"
 	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
 )
 
 var (
@@ -84,6 +86,7 @@ const (
 	pcbPPE
 	pcbText
 	pdf
+	Popular
 	proof
 	restrict
 	script
@@ -153,6 +156,7 @@ func (u URI) String() string {
 		"pcboard-ppe",
 		"pcboard-text",
 		"pdf",
+		"popular",
 		"proof",
 		"restrict",
 		"script",
@@ -220,6 +224,10 @@ func FileInfo(uri string) (string, string, string) {
 		logo = "newest releases"
 		h1sub = "the newest releases"
 		lead = "These are the most recent file artifacts in the collection."
+	case Popular:
+		logo = "popular"
+		h1sub = "the most popular"
+		lead = "These are the most downloaded, emulated and viewed file artifacts of the last 30 days."
 	case Sensenstahl:
 		logo = "sensenstahl 🎁"
 		h1sub = "the bbstros for sensenstahl"
@@ -329,205 +337,126 @@ func Records(ctx context.Context, exec boil.ContextExecutor, uri string, page, l
 	case Newest:
 		r := model.Artifacts{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
 		return r.ByNewest(ctx, exec, page, limit)
+	case Popular:
+		r := model.Artifacts{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
+		return r.ByPopular(ctx, exec, page, limit)
+	// category matches
+	case Sensenstahl:
+		r := model.BBStro{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
+		return r.Sensenstahl(ctx, exec, page, limit)
+	}
+	expr, ok := Expr(uri)
+	if !ok {
+		const format = "artifacts category %s: %w"
+		return nil, fmt.Errorf(format, uri, ErrCategory)
 	}
-	return recordsZ(ctx, exec, uri, page, limit)
+	return model.CategoryFiles(ctx, exec, page, limit, expr)
 }
 
-func recordsZ(ctx context.Context, exec boil.ContextExecutor, uri string, page, limit int) (models.FileSlice, error) {
-	switch Match(uri) { //nolint:exhaustive
-	case advert:
-		r := model.Advert{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case announcement:
-		r := model.Announcement{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case ansi:
-		r := model.Ansi{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case ansiBrand:
-		r := model.AnsiBrand{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case ansiBBS:
-		r := model.AnsiBBS{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case ansiFTP:
-		r := model.AnsiFTP{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case ansiNfo:
-		r := model.AnsiNfo{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case ansiPack:
-		r := model.AnsiPack{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case bbs:
-		r := model.BBS{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case bbsImage:
-		r := model.BBSImage{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case bbstro:
-		r := model.BBStro{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case bbsText:
-		r := model.BBSText{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
+// Expr returns the query mod expression of the artifacts category URI,
+// or false when the URI is not a category, such as new-uploads.
+func Expr(uri string) (qm.QueryMod, bool) {
+	if expr, found := categories()[Match(uri)]; found {
+		return expr(), true
 	}
-	return records0(ctx, exec, uri, page, limit)
+	return nil, false
 }
 
-func records0(ctx context.Context, exec boil.ContextExecutor, uri string, page, limit int) (models.FileSlice, error) {
-	switch Match(uri) { //nolint:exhaustive
-	case database:
-		r := model.Database{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case demoscene:
-		r := model.Demoscene{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case drama:
-		r := model.Drama{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case ftp:
-		r := model.FTP{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case hack:
-		r := model.Hack{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case htm:
-		r := model.HTML{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case howTo:
-		r := model.HowTo{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case imageFile:
-		r := model.Image{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case imagePack:
-		r := model.ImagePack{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case installer:
-		r := model.Installer{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case intro:
-		r := model.Intro{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case linux:
-		r := model.Linux{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case java:
-		r := model.Java{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case jobAdvert:
-		r := model.JobAdvert{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	}
-	return records1(ctx, exec, uri, page, limit)
+// categories returns the query mod expressions of the artifacts category URIs,
+// which select both the category records and the category feeds.
+func categories() map[URI]func() qm.QueryMod {
+	return map[URI]func() qm.QueryMod{
+		advert:       querymod.AdvertExpr,
+		announcement: querymod.AnnouncementExpr,
+		ansi:         querymod.AnsiExpr,
+		ansiBrand:    querymod.AnsiBrandExpr,
+		ansiBBS:      querymod.AnsiBBSExpr,
+		ansiFTP:      querymod.AnsiFTPExpr,
+		ansiNfo:      querymod.AnsiNfoExpr,
+		ansiPack:     querymod.AnsiPackExpr,
+		bbs:          querymod.BBSExpr,
+		bbsImage:     querymod.BBSImageExpr,
+		bbstro:       querymod.BBStroExpr,
+		bbsText:      querymod.BBSTextExpr,
+		database:     querymod.DatabaseExpr,
+		demoscene:    querymod.DemoExpr,
+		drama:        querymod.DramaExpr,
+		ftp:          querymod.FTPExpr,
+		hack:         querymod.HackExpr,
+		htm:          querymod.HTMLExpr,
+		howTo:        querymod.HowToExpr,
+		imageFile:    querymod.ImageExpr,
+		imagePack:    querymod.ImagePackExpr,
+		installer:    querymod.InstallExpr,
+		intro:        querymod.IntroExpr,
+		linux:        querymod.LinuxExpr,
+		java:         querymod.JavaExpr,
+		jobAdvert:    querymod.JobAdvertExpr,
+		macos:        querymod.MacExpr,
+		msdosPack:    querymod.DosPackExpr,
+		music:        querymod.MusicExpr,
+		newsArticle:  querymod.NewsArticleExpr,
+		nfo:          querymod.NfoExpr,
+		nfoTool:      querymod.NfoToolExpr,
+		standards:    querymod.StandardExpr,
+		script:       querymod.ScriptExpr,
+		introMsdos:   querymod.IntroDOSExpr,
+		introWindows: querymod.IntroWindowsExpr,
+		magazine:     querymod.MagExpr,
+		msdos:        querymod.DOSExpr,
+		pcb:          querymod.PCBoardExpr,
+		pcbPPE:       querymod.PCBoardPPEExpr,
+		pcbText:      querymod.PCBoardTextExpr,
+		pdf:          querymod.PDFExpr,
+		proof:        querymod.ProofExpr,
+		restrict:     querymod.RestrictExpr,
+		takedown:     querymod.TakedownExpr,
+		text:         querymod.TextExpr,
+		textAmiga:    querymod.TextAmigaExpr,
+		textApple2:   querymod.AppleIIExpr,
+		textAtariST:  querymod.AtariSTExpr,
+		textPack:     querymod.TextPackExpr,
+		tool:         querymod.ToolExpr,
+		trialCrackme: querymod.TrialCrackmeExpr,
+		video:        querymod.VideoExpr,
+		windows:      querymod.WindowsExpr,
+		WindowsPack:  querymod.WindowsPackExpr,
+		console:      querymod.ConsoleExpr,
+	}
 }
 
-func records1(ctx context.Context, exec boil.ContextExecutor, uri string, page, limit int) (models.FileSlice, error) {
-	switch Match(uri) { //nolint:exhaustive
-	case macos:
-		r := model.Macos{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case msdosPack:
-		r := model.MsDosPack{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case music:
-		r := model.Music{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case newsArticle:
-		r := model.NewsArticle{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case nfo:
-		r := model.Nfo{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case nfoTool:
-		r := model.NfoTool{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case standards:
-		r := model.Standard{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case script:
-		r := model.Script{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case introMsdos:
-		r := model.IntroMsDos{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case introWindows:
-		r := model.IntroWindows{Cache: time.Time{}, Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case magazine:
-		r := model.Magazine{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case msdos:
-		r := model.MsDos{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case pcb:
-		r := model.PCBoard{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case pcbPPE:
-		r := model.PCBoardPPE{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case pcbText:
-		r := model.PCBoardText{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case pdf:
-		r := model.PDF{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	}
-	return records2(ctx, exec, uri, page, limit)
+// Keywords returns the records of the artifacts category URI that are tagged with all of the keyword slugs.
+// An empty URI returns the records of every category.
+func Keywords(ctx context.Context, exec boil.ContextExecutor, uri string, page, limit int, slugs ...string) (
+	models.FileSlice, error,
+) {
+	const format = "file slice keywords: %w"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf(format, err)
+	}
+	mods, ok := KeywordMods(uri, slugs...)
+	if !ok {
+		return nil, fmt.Errorf(format, ErrCategory)
+	}
+	fs, err := model.KeywordFiles(ctx, exec, page, limit, mods...)
+	if err != nil {
+		return nil, fmt.Errorf(format, err)
+	}
+	return fs, nil
 }
 
-func records2(ctx context.Context, exec boil.ContextExecutor, uri string, page, limit int) (models.FileSlice, error) {
-	switch Match(uri) { //nolint:exhaustive
-	case proof:
-		r := model.Proof{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case restrict:
-		r := model.Restrict{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case takedown:
-		r := model.Takedown{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case text:
-		r := model.Text{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case textAmiga:
-		r := model.TextAmiga{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case textApple2:
-		r := model.TextApple2{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case textAtariST:
-		r := model.TextAtariST{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case textPack:
-		r := model.TextPack{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case tool:
-		r := model.Tool{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case trialCrackme:
-		r := model.TrialCrackme{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case video:
-		r := model.Video{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case windows:
-		r := model.Windows{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case WindowsPack:
-		r := model.WindowsPack{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	case Sensenstahl:
-		r := model.BBStro{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.Sensenstahl(ctx, exec, page, limit)
-	case console:
-		r := model.Console{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-		return r.List(ctx, exec, page, limit)
-	default:
-		const format = "artifacts category %s: %w"
-		return nil, fmt.Errorf(format, uri, ErrCategory)
+// KeywordMods returns the query mods of the artifacts category URI and the keyword slugs,
+// or false when the URI is neither empty nor a category, such as new-uploads.
+func KeywordMods(uri string, slugs ...string) ([]qm.QueryMod, bool) {
+	mods := []qm.QueryMod{model.KeywordExpr(slugs...)}
+	if uri == "" {
+		return mods, true
+	}
+	expr, ok := Expr(uri)
+	if !ok {
+		return nil, false
 	}
+	return append(mods, expr), true
 }
 
 // Counter returns the statistics for the artifacts categories.
@@ -6,6 +6,8 @@ import (
 	"testing"
 
 	"github.com/Defacto2/server/handler/app/internal/fileslice"
+	"github.com/Defacto2/server/model/querymod"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
 	"github.com/nalgeon/be"
 )
 
@@ -22,6 +24,7 @@ func TestValid(t *testing.T) {
 	be.True(t, !fileslice.Valid("not-a-valid-uri"))
 	be.True(t, !fileslice.Valid("/files/newest"))
 	be.True(t, fileslice.Valid("newest"))
+	be.True(t, fileslice.Valid("popular"))
 	be.True(t, fileslice.Valid("windows-pack"))
 	be.True(t, fileslice.Valid("advert"))
 }
@@ -43,6 +46,103 @@ func TestRecordsSub(t *testing.T) {
 	}
 }
 
+func TestExpr(t *testing.T) {
+	t.Parallel()
+	expr, ok := fileslice.Expr("intro")
+	be.True(t, ok)
+	be.True(t, expr != nil)
+	_, ok = fileslice.Expr("windows-pack")
+	be.True(t, ok)
+	for _, uri := range Slices() {
+		_, ok = fileslice.Expr(uri.String())
+		be.True(t, !ok)
+	}
+	_, ok = fileslice.Expr("not-a-valid-uri")
+	be.True(t, !ok)
+}
+
+func TestExprCategories(t *testing.T) {
+	t.Parallel()
+	// the expressions of the per-category record lists that the category table replaced
+	tests := []struct {
+		uri  string
+		expr func() qm.QueryMod
+	}{
+		{"advert", querymod.AdvertExpr},
+		{"announcement", querymod.AnnouncementExpr},
+		{"ansi", querymod.AnsiExpr},
+		{"ansi-bbs", querymod.AnsiBBSExpr},
+		{"ansi-brand", querymod.AnsiBrandExpr},
+		{"ansi-ftp", querymod.AnsiFTPExpr},
+		{"ansi-nfo", querymod.AnsiNfoExpr},
+		{"ansi-pack", querymod.AnsiPackExpr},
+		{"bbs", querymod.BBSExpr},
+		{"bbs-image", querymod.BBSImageExpr},
+		{"bbs-text", querymod.BBSTextExpr},
+		{"bbstro", querymod.BBStroExpr},
+		{"console", querymod.ConsoleExpr},
+		{"database", querymod.DatabaseExpr},
+		{"demoscene", querymod.DemoExpr},
+		{"drama", querymod.DramaExpr},
+		{"ftp", querymod.FTPExpr},
+		{"hack", querymod.HackExpr},
+		{"how-to", querymod.HowToExpr},
+		{"html", querymod.HTMLExpr},
+		{"image", querymod.ImageExpr},
+		{"image-pack", querymod.ImagePackExpr},
+		{"installer", querymod.InstallExpr},
+		{"intro", querymod.IntroExpr},
+		{"intro-msdos", querymod.IntroDOSExpr},
+		{"intro-windows", querymod.IntroWindowsExpr},
+		{"java", querymod.JavaExpr},
+		{"job-advert", querymod.JobAdvertExpr},
+		{"linux", querymod.LinuxExpr},
+		{"macos", querymod.MacExpr},
+		{"magazine", querymod.MagExpr},
+		{"msdos", querymod.DOSExpr},
+		{"msdos-pack", querymod.DosPackExpr},
+		{"music", querymod.MusicExpr},
+		{"news-article", querymod.NewsArticleExpr},
+		{"nfo", querymod.NfoExpr},
+		{"nfo-tool", querymod.NfoToolExpr},
+		{"pcboard", querymod.PCBoardExpr},
+		{"pcboard-ppe", querymod.PCBoardPPEExpr},
+		{"pcboard-text", querymod.PCBoardTextExpr},
+		{"pdf", querymod.PDFExpr},
+		{"proof", querymod.ProofExpr},
+		{"restrict", querymod.RestrictExpr},
+		{"script", querymod.ScriptExpr},
+		{"standards", querymod.StandardExpr},
+		{"takedown", querymod.TakedownExpr},
+		{"text", querymod.TextExpr},
+		{"text-amiga", querymod.TextAmigaExpr},
+		{"text-apple2", querymod.AppleIIExpr},
+		{"text-atari-st", querymod.AtariSTExpr},
+		{"text-pack", querymod.TextPackExpr},
+		{"tool", querymod.ToolExpr},
+		{"trial-crackme", querymod.TrialCrackmeExpr},
+		{"video", querymod.VideoExpr},
+		{"windows", querymod.WindowsExpr},
+		{"windows-pack", querymod.WindowsPackExpr},
+	}
+	for _, tt := range tests {
+		t.Run(tt.uri, func(t *testing.T) {
+			t.Parallel()
+			expr, ok := fileslice.Expr(tt.uri)
+			be.True(t, ok)
+			be.Equal(t, expr, tt.expr())
+		})
+	}
+	// every uri is either a category or one of the other record slices
+	count := 0
+	for i := range fileslice.WindowsPack + 1 {
+		if _, ok := fileslice.Expr(i.String()); ok {
+			count++
+		}
+	}
+	be.Equal(t, count, len(tests))
+}
+
 func Slices() []fileslice.URI {
 	return []fileslice.URI{
 		fileslice.NewUploads,
@@ -52,6 +152,7 @@ func Slices() []fileslice.URI {
 		fileslice.Unwanted,
 		fileslice.Oldest,
 		fileslice.Newest,
+		fileslice.Popular,
 		fileslice.Sensenstahl,
 	}
 }
@@ -75,3 +176,17 @@ func TestCounter(t *testing.T) {
 	_, err := fileslice.Counter(context.TODO(), nil)
 	be.Err(t, err)
 }
+
+func TestKeywordMods(t *testing.T) {
+	t.Parallel()
+	mods, ok := fileslice.KeywordMods("", "sound-blaster")
+	be.True(t, ok)
+	be.Equal(t, len(mods), 1)
+	mods, ok = fileslice.KeywordMods("intro", "sound-blaster", "96k-intro")
+	be.True(t, ok)
+	be.Equal(t, len(mods), 2)
+	_, ok = fileslice.KeywordMods("new-uploads", "sound-blaster")
+	be.True(t, !ok)
+	_, err := fileslice.Keywords(t.Context(), nil, "", 1, 1, "sound-blaster")
+	be.Err(t, err)
+}
@@ -0,0 +1,31 @@
+package app
+
+// Package file relation.go contains the handler functions for the typed relations and links of the artifacts.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+)
+
+// ArtifactRelations migrates the pipe-delimited relations and links of the artifacts
+// into the typed artifact relations and links tables. It is run on startup and
+// does nothing once the artifacts are migrated.
+func ArtifactRelations(ctx context.Context, sl *slog.Logger, db *sql.DB) error {
+	const msg = "artifact relations"
+	if err := nils.Check(ctx, sl, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	i, err := model.MigrateRelations(ctx, db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if i > 0 {
+		sl.Info(msg, slog.Int64("migrated artifacts", i))
+	}
+	return nil
+}
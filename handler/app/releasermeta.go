@@ -0,0 +1,109 @@
+package app
+
+// Package file releasermeta.go contains the functions to seed and load the editor managed
+// metadata of the releasers, which replaces the lists that are compiled into the application.
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+
+	"github.com/Defacto2/server/handler/csdb"
+	"github.com/Defacto2/server/handler/demozoo"
+	"github.com/Defacto2/server/handler/janeway"
+	"github.com/Defacto2/server/handler/releaser/initialism"
+	"github.com/Defacto2/server/handler/releaser/meta"
+	"github.com/Defacto2/server/handler/releaser/name"
+	"github.com/Defacto2/server/handler/site"
+	"github.com/Defacto2/server/handler/sixteen"
+	"github.com/Defacto2/server/handler/tidbit"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+)
+
+// ReleaserSeeds returns the metadata of the releasers using the lists that are compiled into the application.
+// The metadata is sorted by the releaser slugs.
+func ReleaserSeeds() []meta.Releaser {
+	rels := map[string]meta.Releaser{}
+	update := func(slug string, fn func(r *meta.Releaser)) {
+		r := rels[slug]
+		r.Slug = slug
+		fn(&r)
+		rels[slug] = r
+	}
+	for slug, styled := range *name.Special() {
+		update(string(slug), func(r *meta.Releaser) { r.Name = styled })
+	}
+	for slug, inits := range *initialism.Initialisms() {
+		update(string(slug), func(r *meta.Releaser) { r.Initialisms = slices.Clone(inits) })
+	}
+	for slug, id := range csdb.Compiled() {
+		update(slug, func(r *meta.Releaser) { r.CSDb = int(id) })
+	}
+	for slug, id := range demozoo.Compiled() {
+		update(string(slug), func(r *meta.Releaser) { r.Demozoo = int(id) })
+	}
+	for slug, id := range janeway.Compiled() {
+		update(slug, func(r *meta.Releaser) { r.Janeway = int(id) })
+	}
+	for slug, tag := range sixteen.Compiled() {
+		update(slug, func(r *meta.Releaser) { r.Sixteen = string(tag) })
+	}
+	for slug, sites := range site.Compiled() {
+		update(string(slug), func(r *meta.Releaser) {
+			for _, s := range sites {
+				r.Websites = append(r.Websites, meta.Website{URL: s.URL, Name: s.Name, NotWorking: s.NotWorking})
+			}
+		})
+	}
+	for id, slugs := range tidbit.Compiled() {
+		for _, slug := range slugs {
+			update(string(slug), func(r *meta.Releaser) { r.Tidbits = append(r.Tidbits, int(id)) })
+		}
+	}
+	for slug, r := range rels {
+		slices.Sort(r.Tidbits)
+		rels[slug] = r
+	}
+	return slices.SortedFunc(maps.Values(rels), func(a, b meta.Releaser) int {
+		return cmp.Compare(a.Slug, b.Slug)
+	})
+}
+
+// ReleaserMeta loads the metadata and the aliases of the releasers from the database into the cache.
+// Unless seed is false, an empty releasers table is first seeded using the compiled lists.
+// If the database cannot be queried or the releasers table is empty, such as on a read-only server
+// that never seeds the table, the cache is not loaded and the compiled lists continue to be used.
+func ReleaserMeta(ctx context.Context, sl *slog.Logger, db *sql.DB, seed bool) error {
+	const msg = "releaser meta"
+	if err := nils.Check(ctx, sl, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if seed {
+		i, err := model.SeedReleaserMeta(ctx, db, ReleaserSeeds()...)
+		if err != nil {
+			return fmt.Errorf("%s: %w", msg, err)
+		}
+		if i > 0 {
+			sl.Info(msg, slog.Int64("seeded", i))
+		}
+	}
+	rels, err := model.ReleaserMetas(ctx, db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if len(rels) == 0 {
+		sl.Warn(msg, slog.String("cache", "the releasers table is empty, the compiled lists are used"))
+	}
+	meta.Load(rels...)
+	aliases, err := model.ReleaserAliases(ctx, db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	meta.LoadAliases(aliases)
+	return nil
+}
@@ -265,14 +265,22 @@ func (got *DemozooLink) Update(ctx context.Context, c *echo.Context, db *sql.DB)
 	if err != nil {
 		return fmt.Errorf(format, "begin tx", uid, err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := model.OneByUUID(ctx, tx, true, uid)
 	if err != nil {
 		return fmt.Errorf(format, "one record by", uid, err)
 	}
+	old := *f
 	got.updateValues(f)
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf(format, "infer", uid, err)
 	}
+	if err = model.RecordChange(ctx, tx, f.ID, model.Updated); err != nil {
+		return fmt.Errorf(format, "record change", uid, err)
+	}
+	if err = model.RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf(format, "record audit", uid, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(format, "tx commit", uid, err)
 	}
@@ -498,14 +506,22 @@ func (got *PouetLink) Update(ctx context.Context, c *echo.Context, db *sql.DB) e
 	if err != nil {
 		return fmt.Errorf(format, "begin tx", uid, err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := model.OneByUUID(ctx, tx, true, uid)
 	if err != nil {
 		return fmt.Errorf(format, "one record by", uid, err)
 	}
+	old := *f
 	got.updateValues(f)
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf(format, "infer", uid, err)
 	}
+	if err = model.RecordChange(ctx, tx, f.ID, model.Updated); err != nil {
+		return fmt.Errorf(format, "record change", uid, err)
+	}
+	if err = model.RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf(format, "record audit", uid, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(format, "tx commit", uid, err)
 	}
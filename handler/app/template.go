@@ -28,6 +28,7 @@ import (
 	"github.com/Defacto2/server/internal/dir"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/Defacto2/server/internal/tags"
+	"github.com/Defacto2/server/model"
 	"github.com/aarondl/null/v8"
 )
 
@@ -94,41 +95,51 @@ func (t *Templ) Pages() *Page {
 	// {{ template "abc" $myVar }}
 
 	return &Page{
-		"api-info":      "apiinfo.tmpl",
-		"apps":          "apps.tmpl",
-		"areacodes":     "areacodes.tmpl",
-		"artifact":      artifactTmpl,
-		"artifacts":     artifactsTmpl,
-		"bbs":           releaserTmpl,
-		"bbs-year":      releaseryearTmpl,
-		"brokentexts":   "brokentexts.tmpl",
-		"categories":    categoriesTmpl,
-		"configs":       "configurations.tmpl",
-		"coder":         scenerTmpl,
-		"compression":   "compression.tmpl",
-		"ftp":           releaserTmpl,
-		"fixers":        "fixers.tmpl",
-		"fixes":         "fixes.tmpl",
-		"history":       "history.tmpl",
-		"index":         "index.tmpl",
-		"interview":     "interview.tmpl",
-		"magazine":      releaseryearTmpl,
-		"magazine-az":   releaserTmpl,
-		"new":           "new.tmpl",
-		"releaser":      releaserTmpl,
-		"releaser-year": releaseryearTmpl,
-		"routes":        "routes.tmpl",
-		"scener":        scenerTmpl,
-		"searchhtmx":    "searchhtmx.tmpl",
-		"searchpost":    "searchpost.tmpl",
-		"signin":        "signin.tmpl",
-		"signout":       "signout.tmpl",
-		"status":        "status.tmpl",
-		"terms":         "terms.tmpl",
-		"thanks":        "thanks.tmpl",
-		"thescene":      "thescene.tmpl",
-		"titles":        "titles.tmpl",
-		websites:        websitesTmpl,
+		"api-info":        "apiinfo.tmpl",
+		"apps":            "apps.tmpl",
+		"areacodes":       "areacodes.tmpl",
+		"artifact":        artifactTmpl,
+		"artifacts":       artifactsTmpl,
+		"bbs":             releaserTmpl,
+		"bbs-year":        releaseryearTmpl,
+		"brokentexts":     "brokentexts.tmpl",
+		"bulk":            "bulk.tmpl",
+		"categories":      categoriesTmpl,
+		"configs":         "configurations.tmpl",
+		"duplicates":      "duplicates.tmpl",
+		"encodings":       "encodings.tmpl",
+		"coder":           scenerTmpl,
+		"compression":     "compression.tmpl",
+		"ftp":             releaserTmpl,
+		"fixers":          "fixers.tmpl",
+		"fixes":           "fixes.tmpl",
+		"history":         "history.tmpl",
+		"index":           "index.tmpl",
+		"jobs":            "jobs.tmpl",
+		"keywords":        "keywords.tmpl",
+		"keywords-edit":   "keywordsedit.tmpl",
+		"interview":       "interview.tmpl",
+		"magazine":        releaseryearTmpl,
+		"magazine-az":     releaserTmpl,
+		"new":             "new.tmpl",
+		"releaser":        releaserTmpl,
+		"releaser-meta":   "releasermeta.tmpl",
+		"releaser-rename": "releaserrename.tmpl",
+		"releaser-year":   releaseryearTmpl,
+		"routes":          "routes.tmpl",
+		"scener":          scenerTmpl,
+		"scener-identity": "sceneridentity.tmpl",
+		"searchhtmx":      "searchhtmx.tmpl",
+		"searchpost":      "searchpost.tmpl",
+		"signin":          "signin.tmpl",
+		"signout":         "signout.tmpl",
+		"status":          "status.tmpl",
+		"terms":           "terms.tmpl",
+		"thanks":          "thanks.tmpl",
+		"thescene":        "thescene.tmpl",
+		"titles":          "titles.tmpl",
+		"trends":          "trends.tmpl",
+		websites:          websitesTmpl,
 	}
 }
 
@@ -178,6 +189,7 @@ func (t *Templ) Funcs() template.FuncMap {
 		"linkScnr":           LinkScnr,
 		"linkScnrs":          LinkScnrs,
 		"linkSVG":            filerecord.LinkSVG,
+		"linkKinds":          model.LinkKinds,
 		"linkWiki":           LinkWiki,
 		"linkWikiTip":        LinkWikiTip,
 		"logoText":           LogoText,
@@ -187,6 +199,7 @@ func (t *Templ) Funcs() template.FuncMap {
 		"recordInfoOSTag":    TagWithOS,
 		"recordLinkPreviews": LinkPreviews,
 		"recordTagInfo":      TagBrief,
+		"relationKinds":      model.RelationKinds,
 		"safeBBS":            SafeBBS,
 		"safeDocument":       SafeDocument,
 		"safeHTML":           SafeHTML,
@@ -623,7 +636,7 @@ func recordReadme(b bool) template.HTML {
 }
 
 // LinkPreviews returns a slice of HTML formatted links for the artifact editor.
-func LinkPreviews(youtube, demozoo, pouet, colors16, github, rels, sites string) []string {
+func LinkPreviews(youtube, demozoo, pouet, colors16, github string) []string {
 	rel := func(url string) string {
 		return `<a href="https://` + url + `">` + url + closeAnchor
 	}
@@ -644,12 +657,6 @@ func LinkPreviews(youtube, demozoo, pouet, colors16, github, rels, sites string)
 	if github != "" {
 		links = append(links, rel("github.com/"+github))
 	}
-	if rels != "" {
-		links = append(links, strings.Split(string(simple.LinkRelations(rels)), "+")...)
-	}
-	if sites != "" {
-		links = append(links, strings.Split(string(simple.LinkSites(sites)), "+")...)
-	}
 	return links
 }
 
@@ -4,6 +4,8 @@
 //nolint:mnd
 package csdb
 
+import "github.com/Defacto2/server/handler/releaser/meta"
+
 // URI is the URL slug of the releaser.
 type URI string
 
@@ -42,5 +44,13 @@ var groups = Groups{ //nolint:gochecknoglobals
 // Find returns the csdb group id for the given releaser uri.
 // If the releaser is not found, an empty value is returned.
 func Find(uri string) GroupID {
+	if r, loaded := meta.Find(uri); loaded {
+		return GroupID(r.CSDb)
+	}
 	return groups[uri]
 }
+
+// Compiled returns the compiled list of the csdb group ids, which is used to seed the releasers metadata.
+func Compiled() Groups {
+	return groups
+}
@@ -24,6 +24,7 @@ import (
 
 	"github.com/Defacto2/helper"
 	"github.com/Defacto2/server/handler/releaser"
+	"github.com/Defacto2/server/handler/releaser/meta"
 	"github.com/Defacto2/server/internal/tags"
 )
 
@@ -696,6 +697,9 @@ var groups = Groups{
 // Find returns the Demozoo group ID for the given uri.
 // It returns 0 if the uri is not known.
 func Find(uri string) GroupID {
+	if r, loaded := meta.Find(uri); loaded {
+		return GroupID(r.Demozoo)
+	}
 	if group, exist := groups[URI(uri)]; exist {
 		return group
 	}
@@ -704,6 +708,20 @@ func Find(uri string) GroupID {
 
 // FindAll returns all groups with their Demozoo IDs.
 func FindAll() Groups {
+	if !meta.Loaded() {
+		return groups
+	}
+	all := Groups{}
+	for _, r := range meta.All() {
+		if r.Demozoo != 0 {
+			all[URI(r.Slug)] = GroupID(r.Demozoo)
+		}
+	}
+	return all
+}
+
+// Compiled returns the compiled list of the Demozoo IDs, which is used to seed the releasers metadata.
+func Compiled() Groups {
 	return groups
 }
 
@@ -0,0 +1,176 @@
+package download
+
+// Package file bundle.go serves the js-dos v8 bundles of the MS-DOS artifacts.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Defacto2/helper"
+	"github.com/Defacto2/server/handler/jsdos"
+	"github.com/Defacto2/server/handler/sess"
+	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/hits"
+	"github.com/Defacto2/server/internal/manifest"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+	"github.com/labstack/echo/v5"
+)
+
+var ErrEmulate = errors.New("artifact cannot be emulated")
+
+// Bundle configuration to serve the js-dos v8 bundle of an artifact.
+type Bundle struct {
+	Extra    dir.Directory // Extra is the absolute path to the extra directory.
+	Download dir.Directory // Download is the absolute path to the download directory.
+	Cache    string        // Cache is the directory used to store the extracted archives, an empty value uses the temp directory.
+}
+
+// HTTPSend serves the js-dos v8 bundle of an MS-DOS artifact and prompts for a save location.
+// The bundle is a zip archive containing the DOSBox configuration of the artifact and its program files,
+// either extracted from the re-archived zip file in the extra directory, the archive download,
+// or the program download. The extractions are limited by the MaxMembers and MaxExtract constants.
+// A served bundle is counted as an emulator hit and not as a download.
+func (b Bundle) HTTPSend(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "bundle http send"
+	const format = msg + " %s: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	key := c.Param("id")
+	art, err := model.OneFileByKey(ctx, db, key)
+	switch {
+	case err != nil && sess.Editor(c):
+		art, err = model.OneEditByKey(ctx, db, key)
+		if err != nil {
+			return fmt.Errorf(format, "one edit by key", err)
+		}
+	case err != nil:
+		return fmt.Errorf(format, "one file by key", err)
+	}
+	filename := art.Filename.String
+	if !strings.EqualFold(strings.TrimSpace(art.Platform.String), "dos") {
+		return fmt.Errorf("%s, %w: %s", msg, ErrEmulate, filename)
+	}
+	settings, err := model.EmulatorSettings(ctx, db, art.ID)
+	if err != nil {
+		return fmt.Errorf(format, "settings", err)
+	}
+	conf, err := model.JsDosConf(art, settings)
+	if err != nil {
+		return fmt.Errorf(format, "conf", err)
+	}
+	members, err := model.Manifest(ctx, db, art.ID)
+	if err != nil {
+		return fmt.Errorf(format, "manifest", err)
+	}
+	uid := strings.TrimSpace(art.UUID.String)
+	root, cleanup, err := b.programs(uid, filename, members)
+	if err != nil {
+		return fmt.Errorf(format, "programs", err)
+	}
+	defer cleanup()
+	// the root prevents the symbolic links within the archive from escaping the extraction
+	r, err := os.OpenRoot(root)
+	if err != nil {
+		return fmt.Errorf(format, "open root", err)
+	}
+	defer func() { _ = r.Close() }()
+	name := strings.TrimSuffix(filename, filepath.Ext(filename)) + jsdos.BundleExt
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, "application/zip")
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+	if err := jsdos.Bundle(c.Response(), conf, r.FS()); err != nil {
+		return fmt.Errorf(format, "bundle", err)
+	}
+	hits.Add(art.ID, hits.Emulate)
+	return nil
+}
+
+// programs returns the directory containing the program files of the artifact uid and
+// a func to call once the files are no longer used. The re-archived zip file in the extra directory
+// has priority over the download, which is either extracted or copied when it is a program.
+// The members are the stored manifest of the download that are used to limit the extraction of an archive.
+func (b Bundle) programs(uid, filename string, members []manifest.Member) (string, func(), error) {
+	none := func() {}
+	if zip := b.Extra.Join(uid + ".zip"); helper.Stat(zip) {
+		return unzipTemp(zip)
+	}
+	src := b.Download.Join(uid)
+	if !helper.Stat(src) {
+		return "", none, fmt.Errorf("%w: %s", ErrStat, filename)
+	}
+	ext := strings.ToLower(filepath.Ext(filename))
+	switch {
+	case zipped(filename):
+		return unzipTemp(src)
+	case slices.Contains(Archives(), ext):
+		m := Member{Dir: b.Download, Cache: b.Cache}
+		return m.extract(uid, src, filename, members)
+	case ext == ".com", ext == ".exe":
+	default:
+		return "", none, fmt.Errorf("%w: %s", ErrEmulate, filename)
+	}
+	tmp, cleanup, err := bundleTemp()
+	if err != nil {
+		return "", none, err
+	}
+	if err := copyFile(src, filepath.Join(tmp, filepath.Base(filename))); err != nil {
+		cleanup()
+		return "", none, err
+	}
+	return tmp, cleanup, nil
+}
+
+// unzipTemp extracts the src zip archive to a temporary directory and returns the directory
+// with a func to remove it.
+func unzipTemp(src string) (string, func(), error) {
+	tmp, cleanup, err := bundleTemp()
+	if err != nil {
+		return "", func() {}, err
+	}
+	extractions <- struct{}{}
+	defer func() { <-extractions }()
+	if err := unzip(src, tmp); err != nil {
+		cleanup()
+		return "", func() {}, fmt.Errorf("bundle: %w", err)
+	}
+	return tmp, cleanup, nil
+}
+
+func bundleTemp() (string, func(), error) {
+	tmp, err := os.MkdirTemp(helper.TmpDir(), "jsdos-bundle-*")
+	if err != nil {
+		return "", func() {}, fmt.Errorf("bundle temp: %w", err)
+	}
+	return tmp, func() { _ = os.RemoveAll(tmp) }, nil
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("bundle copy: %w", err)
+	}
+	defer func() { _ = in.Close() }()
+	out, err := os.Create(dst)
+	if err != nil {
+		return fmt.Errorf("bundle copy: %w", err)
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return fmt.Errorf("bundle copy: %w", err)
+	}
+	if err := out.Close(); err != nil {
+		return fmt.Errorf("bundle copy: %w", err)
+	}
+	return nil
+}
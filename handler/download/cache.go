@@ -0,0 +1,149 @@
+package download
+
+// Package file cache.go contains the locks and the pruning of the extracted archive cache.
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// CacheSize is the maximum number of bytes of the extracted archives kept in the cache.
+	CacheSize = 2 << 30
+	// CacheInterval is the duration between the prunes of the cache.
+	CacheInterval = 15 * time.Minute
+	// Extractions is the maximum number of archives that are extracted at the same time.
+	Extractions = 4
+)
+
+//nolint:gochecknoglobals
+var (
+	// extractions limits the number of archives that are extracted at the same time.
+	extractions = make(chan struct{}, Extractions)
+	// locks are the read and write locks of the extracted archives in the cache, keyed by their uid.
+	locks = struct {
+		sync.Mutex
+		m map[string]*uidLock
+	}{m: map[string]*uidLock{}}
+)
+
+// uidLock is held for reading while an extracted archive is served,
+// and for writing while the archive is extracted or removed from the cache.
+type uidLock struct {
+	sync.RWMutex
+	refs int
+}
+
+// acquire returns the lock of the uid, which must be returned by calling release.
+func acquire(uid string) *uidLock {
+	locks.Lock()
+	defer locks.Unlock()
+	l, ok := locks.m[uid]
+	if !ok {
+		l = &uidLock{}
+		locks.m[uid] = l
+	}
+	l.refs++
+	return l
+}
+
+// release returns the lock of the uid, which is removed once it is no longer in use.
+func release(uid string, l *uidLock) {
+	locks.Lock()
+	defer locks.Unlock()
+	l.refs--
+	if l.refs == 0 {
+		delete(locks.m, uid)
+	}
+}
+
+// PruneCache removes the expired and the least recently extracted archives from the cache directory
+// at every interval, until the context is done. An empty cache value uses the default temp directory.
+func PruneCache(ctx context.Context, cache string, interval time.Duration) {
+	if cache == "" {
+		cache = Member{}.cache()
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			prune(cache, CacheTTL, CacheSize)
+		}
+	}
+}
+
+// prune removes the extracted archives in the cache that are older than the ttl,
+// then removes the oldest archives until the cache is no larger than size bytes.
+// The archives that are being extracted or served are skipped.
+func prune(cache string, ttl time.Duration, size int64) {
+	entries, err := os.ReadDir(cache)
+	if err != nil {
+		return
+	}
+	type cached struct {
+		name string
+		mod  time.Time
+		size int64
+	}
+	dirs := make([]cached, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		dirs = append(dirs, cached{name: entry.Name(), mod: info.ModTime()})
+	}
+	slices.SortFunc(dirs, func(a, b cached) int {
+		return a.mod.Compare(b.mod)
+	})
+	var total int64
+	for i, d := range dirs {
+		dirs[i].size = dirSize(filepath.Join(cache, d.name))
+		total += dirs[i].size
+	}
+	for _, d := range dirs {
+		if total <= size && time.Since(d.mod) < ttl {
+			continue
+		}
+		if remove(cache, d.name) {
+			total -= d.size
+		}
+	}
+}
+
+// remove deletes the extracted archive uid from the cache, unless it is in use.
+func remove(cache, uid string) bool {
+	l := acquire(uid)
+	defer release(uid, l)
+	if !l.TryLock() {
+		return false
+	}
+	defer l.Unlock()
+	return os.RemoveAll(filepath.Join(cache, uid)) == nil
+}
+
+// dirSize returns the sum of the sizes of the regular files in the named directory.
+func dirSize(name string) int64 {
+	var sum int64
+	_ = filepath.WalkDir(name, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil //nolint:nilerr
+		}
+		if info, err := d.Info(); err == nil {
+			sum += info.Size()
+		}
+		return nil
+	})
+	return sum
+}
@@ -7,6 +7,8 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"mime"
+	"net/http"
 	"os"
 	"path/filepath"
 	"slices"
@@ -15,9 +17,12 @@ import (
 	"time"
 
 	"github.com/Defacto2/helper"
+	"github.com/Defacto2/server/handler/render"
 	"github.com/Defacto2/server/handler/sess"
 	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/encodings"
 	"github.com/Defacto2/server/internal/extensions"
+	"github.com/Defacto2/server/internal/hits"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/Defacto2/server/internal/postgres/models"
 	"github.com/Defacto2/server/internal/tags"
@@ -144,10 +149,24 @@ func (d Download) HTTPSend(ctx context.Context, sl *slog.Logger, c *echo.Context
 			slog.String("filename", art.Filename.String))
 		name = file
 	}
+	Integrity(c, art.FileIntegrityStrong.String)
 	if d.Inline {
 		text := tags.IsText(art.Platform.String)
 		ext := filepath.Ext(art.Filename.String)
-		return inline(c, text, file, name, ext)
+		charset := ""
+		if text {
+			if charset, err = model.Encoding(ctx, db, art.ID); err != nil {
+				sl.Warn(msg, slog.String("issue", "could not read the text encoding"),
+					slog.Int64("id", art.ID), slog.Any("error", err))
+			}
+		}
+		if err := inline(c, text, file, name, ext, charset); err != nil {
+			return err
+		}
+		if Complete(c) {
+			hits.Add(art.ID, hits.Download)
+		}
+		return nil
 	}
 	lastmod := LastModified(art)
 	if lastmod != "" {
@@ -163,10 +182,38 @@ func (d Download) HTTPSend(ctx context.Context, sl *slog.Logger, c *echo.Context
 	if err := c.Attachment(file, name); err != nil {
 		return fmt.Errorf(format, "attachment", err)
 	}
+	if Complete(c) {
+		hits.Add(art.ID, hits.Download)
+	}
 	return nil
 }
 
-func inline(c *echo.Context, text bool, file, name, ext string) error {
+// Complete returns true if the response served the file from its first byte, so it can be counted as a download.
+// The partial content responses of a resumed or segmented download are only counted for the range
+// that starts at the first byte, while the HEAD requests and the not modified responses are never counted.
+func Complete(c *echo.Context) bool {
+	if c == nil || c.Request().Method != http.MethodGet {
+		return false
+	}
+	resp, err := echo.UnwrapResponse(c.Response())
+	if err != nil {
+		return false
+	}
+	switch resp.Status {
+	case http.StatusOK:
+		return true
+	case http.StatusPartialContent:
+		const first = "bytes=0-"
+		return strings.HasPrefix(strings.TrimSpace(c.Request().Header.Get(headerRange)), first)
+	}
+	return false
+}
+
+// inline serves the file to display in the browser. The text files use the charset,
+// the key of the text encoding chosen by an editor, otherwise the text is served as either
+// UTF-8 or ISO-8859-1. The texts using the other encodings are served after being decoded to UTF-8,
+// as most browsers do not support the legacy code pages such as CP-437.
+func inline(c *echo.Context, text bool, file, name, ext, charset string) error {
 	const format = "http send %s: %w"
 	if err := nils.Check(c); err != nil {
 		return fmt.Errorf(format, "check", err)
@@ -183,6 +230,20 @@ func inline(c *echo.Context, text bool, file, name, ext string) error {
 		}
 		return nil
 	}
+	if cs, ok := encodings.Find(charset); ok {
+		switch cs.Key {
+		case encodings.UTF8:
+			c.Response().Header().Set(echo.HeaderContentType, "text/plain; charset=utf-8")
+		case encodings.Latin1, encodings.AmigaTopaz:
+			c.Response().Header().Set(echo.HeaderContentType, "text/plain; charset=iso-8859-1")
+		default:
+			return transcode(c, file, name, cs)
+		}
+		if err := c.Inline(file, name); err != nil {
+			return fmt.Errorf(format, "charset as inline", err)
+		}
+		return nil
+	}
 	modernText, err := helper.UTF8(file)
 	if err != nil {
 		return fmt.Errorf(format, "utf-8", err)
@@ -196,6 +257,77 @@ func inline(c *echo.Context, text bool, file, name, ext string) error {
 	return nil
 }
 
+// transcode serves the named text file decoded from the charset to UTF-8 to display in the browser.
+// The integrity headers are removed, as their digests are of the stored file and not of the decoded text.
+func transcode(c *echo.Context, file, name string, cs encodings.Charset) error {
+	const format = "http send transcode: %w"
+	f, err := os.Open(file)
+	if err != nil {
+		return fmt.Errorf(format, err)
+	}
+	defer func() { _ = f.Close() }()
+	header := c.Response().Header()
+	header.Del(headerETag)
+	header.Del(headerReprDigest)
+	header.Del(headerDigest)
+	disposition := mime.FormatMediaType("inline", map[string]string{"filename": name})
+	header.Set(echo.HeaderContentDisposition, disposition)
+	r := cs.Encoding().NewDecoder().Reader(f)
+	if err := c.Stream(http.StatusOK, "text/plain; charset=utf-8", r); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	return nil
+}
+
+// Unicode configuration.
+type Unicode struct {
+	Download dir.Directory // Download is the absolute path to the download directory.
+	Extra    dir.Directory // Extra is the absolute path to the extra directory.
+	Reader   bool          // Reader is true to serve the screen reader friendly version of the text.
+}
+
+// HTTPSend serves the text of the file artifact transcoded to UTF-8 to display in the browser,
+// so it can be read without the site fonts, such as in a terminal or by assistive technology.
+// The text is either the file download of a text artifact or the extracted readme of an artifact.
+func (u Unicode) HTTPSend(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "unicode http send"
+	const format = msg + " %s: %w"
+	const sizeLimit = 4 * 1024 * 1024
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, "check", err)
+	}
+	key := c.Param("id")
+	art, err := model.OneFileByKey(ctx, db, key)
+	switch {
+	case err != nil && sess.Editor(c):
+		art, err = model.OneEditByKey(ctx, db, key)
+		if err != nil {
+			return fmt.Errorf(format, "one edit by key", err)
+		}
+	case err != nil:
+		return fmt.Errorf(format, "one file by key", err)
+	}
+	charset, err := model.Encoding(ctx, db, art.ID)
+	if err != nil {
+		sl.Warn(msg, slog.String("issue", "could not read the text encoding"),
+			slog.Int64("id", art.ID), slog.Any("error", err))
+	}
+	p, err := render.Unicode(art, charset, sizeLimit, u.Download, u.Extra)
+	if err != nil {
+		return fmt.Errorf(format, "render", err)
+	}
+	if u.Reader {
+		p = render.Accessible(p)
+	}
+	name := strings.TrimSuffix(art.Filename.String, filepath.Ext(art.Filename.String)) + ".txt"
+	disposition := mime.FormatMediaType("inline", map[string]string{"filename": name})
+	c.Response().Header().Set(echo.HeaderContentDisposition, disposition)
+	if err := c.Blob(http.StatusOK, "text/plain; charset=utf-8", p); err != nil {
+		return fmt.Errorf(format, "blob", err)
+	}
+	return nil
+}
+
 // ExtraZip configuration.
 type ExtraZip struct {
 	Extra    dir.Directory // Extra is the absolute path to the extra directory.
@@ -230,9 +362,13 @@ func (e ExtraZip) HTTPSend(ctx context.Context, c *echo.Context, db *sql.DB) err
 	if !helper.Stat(file) {
 		name = art.Filename.String
 		file = e.Download.Join(uid)
+		Integrity(c, art.FileIntegrityStrong.String)
 	}
 	if err := c.Attachment(file, name); err != nil {
 		return fmt.Errorf(format, "attachment", err)
 	}
+	if Complete(c) {
+		hits.Add(art.ID, hits.Emulate)
+	}
 	return nil
 }
@@ -2,12 +2,18 @@ package download_test
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/Defacto2/server/handler/download"
+	"github.com/Defacto2/server/internal/manifest"
+	"github.com/Defacto2/server/model"
 	"github.com/labstack/echo/v5"
 	"github.com/nalgeon/be"
 )
@@ -34,9 +40,179 @@ func TestHTTPSend(t *testing.T) {
 	be.Err(t, err)
 }
 
+func TestComplete(t *testing.T) {
+	t.Parallel()
+	be.True(t, !download.Complete(nil))
+	tmp := t.TempDir()
+	name := filepath.Join(tmp, "file.txt")
+	be.Err(t, os.WriteFile(name, []byte("0123456789"), 0o644), nil)
+	const etag = `"abc"`
+	send := func(method string, header map[string]string) bool {
+		e := echo.New()
+		e.Filesystem = echo.NewDefaultFS(tmp)
+		req := httptest.NewRequestWithContext(context.Background(), method, "/", nil)
+		for k, v := range header {
+			req.Header.Set(k, v)
+		}
+		c := e.NewContext(req, httptest.NewRecorder())
+		c.Response().Header().Set("ETag", etag)
+		be.Err(t, c.Attachment(name, "file.txt"), nil)
+		return download.Complete(c)
+	}
+	be.True(t, send(http.MethodGet, nil))
+	be.True(t, send(http.MethodGet, map[string]string{"Range": "bytes=0-"}))
+	be.True(t, send(http.MethodGet, map[string]string{"Range": "bytes=0-4"}))
+	// a range that does not match the ETag is a complete download
+	be.True(t, send(http.MethodGet, map[string]string{"Range": "bytes=5-", "If-Range": `"xyz"`}))
+	be.True(t, !send(http.MethodGet, map[string]string{"Range": "bytes=5-"}))
+	be.True(t, !send(http.MethodGet, map[string]string{"Range": "bytes=5-", "If-Range": etag}))
+	be.True(t, !send(http.MethodGet, map[string]string{"If-None-Match": etag}))
+	be.True(t, !send(http.MethodHead, nil))
+}
+
 func TestEZHTTPSend(t *testing.T) {
 	t.Parallel()
 	ez := download.ExtraZip{}
 	err := ez.HTTPSend(context.TODO(), newContext(), nil)
 	be.Err(t, err)
 }
+
+func TestMemberHTTPSend(t *testing.T) {
+	t.Parallel()
+	m := download.Member{}
+	err := m.HTTPSend(context.TODO(), nil, newContext(), nil)
+	be.Err(t, err)
+}
+
+func TestMemberPath(t *testing.T) {
+	t.Parallel()
+	valid := map[string]string{
+		"readme.nfo":               "readme.nfo",
+		"docs/file_id.diz":         "docs/file_id.diz",
+		"docs%2Ffile_id.diz":       "docs/file_id.diz",
+		"my+demo.exe":              "my demo.exe",
+		`dos\setup.exe`:            "dos/setup.exe",
+		"..hidden":                 "..hidden",
+		"a%20long%20filename.txt":  "a long filename.txt",
+		"cr%C3%A9dits.txt":         "crédits.txt",
+		"release/info/release.nfo": "release/info/release.nfo",
+	}
+	for param, want := range valid {
+		name, err := download.MemberPath(param)
+		be.Err(t, err, nil)
+		be.Equal(t, name, want)
+	}
+	invalid := []string{
+		"", "docs/", "/etc/passwd", "..", "../secret.txt", "docs/../../secret.txt",
+		`..\secret.txt`, "./readme.nfo", "docs//readme.nfo", "%zz",
+	}
+	for _, param := range invalid {
+		_, err := download.MemberPath(param)
+		be.Err(t, err, download.ErrMember)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	t.Parallel()
+	err := download.Limits(nil)
+	be.Err(t, err, download.ErrLimit)
+	err = download.Limits([]manifest.Member{{Path: "file.txt", Size: 1}})
+	be.Err(t, err, nil)
+	err = download.Limits([]manifest.Member{{Path: "file.dat", Size: download.MaxExtract + 1}})
+	be.Err(t, err, download.ErrLimit)
+	err = download.Limits(make([]manifest.Member, download.MaxMembers+1))
+	be.Err(t, err, download.ErrLimit)
+}
+
+func TestContentType(t *testing.T) {
+	t.Parallel()
+	r := strings.NewReader("hello world")
+	be.Equal(t, download.ContentType("readme", r), "text/plain; charset=utf-8")
+	r = strings.NewReader("\xc9\xcd\xbb caf\x82")
+	be.Equal(t, download.ContentType("readme", r), "text/plain; charset=iso-8859-1")
+	be.Equal(t, download.ContentType("readme.txt", r), "text/plain; charset=iso-8859-1")
+	r = strings.NewReader("<html></html>")
+	be.Equal(t, download.ContentType("page.htm", r), "text/html; charset=utf-8")
+	r = strings.NewReader("\x89PNG\r\n\x1a\n")
+	be.Equal(t, download.ContentType("image", r), "image/png")
+}
+
+const sha384 = "72f8a29d75993487b7ad5ad3a17d2f65ed4c41be155adbda88258d0458fcfe29f55e2e31b0316f01d57f4427ca9e2422"
+
+func TestSHA384(t *testing.T) {
+	t.Parallel()
+	b, ok := download.SHA384(sha384)
+	be.True(t, ok)
+	be.Equal(t, len(b), 48)
+	_, ok = download.SHA384("")
+	be.True(t, !ok)
+	_, ok = download.SHA384("72f8a29d")
+	be.True(t, !ok)
+	_, ok = download.SHA384(strings.Repeat("z", 96))
+	be.True(t, !ok)
+}
+
+func TestIntegrity(t *testing.T) {
+	t.Parallel()
+	download.Integrity(nil, sha384)
+	c := newContext()
+	download.Integrity(c, "invalid")
+	be.Equal(t, c.Response().Header().Get("ETag"), "")
+	download.Integrity(c, sha384)
+	h := c.Response().Header()
+	be.Equal(t, h.Get("ETag"), `"`+sha384+`"`)
+	be.True(t, strings.HasPrefix(h.Get("Repr-Digest"), "sha-384=:"))
+	be.True(t, strings.HasPrefix(h.Get("Digest"), "SHA-384="))
+}
+
+func TestSidecarSend(t *testing.T) {
+	t.Parallel()
+	err := download.SidecarSend(context.TODO(), newContext(), nil, "")
+	be.Err(t, err)
+}
+
+func TestSums(t *testing.T) {
+	t.Parallel()
+	_, err := download.NewSums("invalid")
+	be.Err(t, err, download.ErrSeed)
+	seed := base64.StdEncoding.EncodeToString(make([]byte, ed25519.SeedSize))
+	a, err := download.NewSums(seed)
+	be.Err(t, err, nil)
+	b, err := download.NewSums(seed)
+	be.Err(t, err, nil)
+	be.Equal(t, a.PublicKey(), b.PublicKey())
+	be.True(t, a.Signing())
+	r, err := download.NewSums("")
+	be.Err(t, err, nil)
+	be.True(t, !r.Signing())
+	be.Equal(t, r.PublicKey(), "")
+	be.Err(t, r.HTTPKey(newContext()), download.ErrUnsigned)
+	_, err = a.Manifest(context.TODO(), nil)
+	be.Err(t, err)
+	_, ok := a.Lookup("")
+	be.True(t, !ok)
+	var nilSums *download.Sums
+	be.Equal(t, nilSums.PublicKey(), "")
+}
+
+func TestVersion(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, download.Version(nil), "")
+	c := newContext()
+	be.Equal(t, download.Version(c), "")
+	c.Request().Header.Set("If-Match", `W/"abc"`)
+	be.Equal(t, download.Version(c), "abc")
+	c.Request().Header.Set("If-Match", "*")
+	be.Equal(t, download.Version(c), "")
+}
+
+func TestSumsBody(t *testing.T) {
+	t.Parallel()
+	body := download.SumsBody(
+		model.Integrity{ID: 1, SHA384: sha384},
+		model.Integrity{ID: 2, SHA384: "invalid"},
+	)
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	be.Equal(t, len(lines), 1)
+	be.True(t, strings.HasPrefix(lines[0], sha384+"  d/"))
+}
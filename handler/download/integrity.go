@@ -0,0 +1,324 @@
+package download
+
+// Package file integrity.go publishes the SHA-384 checksums of the artifact downloads,
+// as HTTP headers, as sidecar files, and as a signed manifest that mirrors can use to verify bulk fetches.
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha512"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Defacto2/helper"
+	"github.com/Defacto2/server/handler/sess"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+	"github.com/labstack/echo/v5"
+)
+
+var (
+	ErrSeed     = errors.New("signing key must be a base64 encoded, 32 byte Ed25519 seed")
+	ErrUnsigned = errors.New("signing key is not set, the checksums are not signed")
+	ErrVersion  = errors.New("signature of the manifest version is unavailable")
+)
+
+const (
+	// SumsTTL is the duration the signed manifest of checksums is reused before it is rebuilt.
+	SumsTTL = time.Hour
+	// Sidecar is the filename extension of the checksum files that accompany the downloads.
+	Sidecar = ".sha384"
+
+	headerDigest     = "Digest"
+	headerETag       = "ETag"
+	headerIfMatch    = "If-Match"
+	headerIfNone     = "If-None-Match"
+	headerRange      = "Range"
+	headerReprDigest = "Repr-Digest"
+)
+
+// SHA384 returns the bytes of the hexadecimal, SHA-384 checksum,
+// or false if the checksum is not a valid SHA-384 value.
+func SHA384(sum string) ([]byte, bool) {
+	const size = 48
+	b, err := hex.DecodeString(strings.TrimSpace(sum))
+	if err != nil || len(b) != size {
+		return nil, false
+	}
+	return b, true
+}
+
+// Integrity sets the strong ETag and the SHA-384 digest headers of a download response
+// using the hexadecimal checksum of the file. Conditional If-None-Match and If-Range
+// requests are answered by the file server using the ETag.
+// Invalid checksums are ignored.
+func Integrity(c *echo.Context, sum string) {
+	if c == nil {
+		return
+	}
+	b, ok := SHA384(sum)
+	if !ok {
+		return
+	}
+	b64 := base64.StdEncoding.EncodeToString(b)
+	header := c.Response().Header()
+	header.Set(headerETag, `"`+hex.EncodeToString(b)+`"`)
+	header.Set(headerReprDigest, "sha-384=:"+b64+":")
+	header.Set(headerDigest, "SHA-384="+b64)
+}
+
+// SidecarSend serves the checksum sidecar of the requested file.
+// The response is a text file named after the download with a ".sha384" extension,
+// that contains the checksum and filename in the format used by `shasum --check`.
+// The id string is the key of the requested file, with or without the extension.
+func SidecarSend(ctx context.Context, c *echo.Context, db *sql.DB, id string) error {
+	const format = "download sidecar id %v: %w"
+	if err := nils.Check(ctx, c, db); err != nil {
+		return fmt.Errorf(format, id, err)
+	}
+	key := strings.TrimSuffix(id, Sidecar)
+	art, err := model.OneFileByKey(ctx, db, key)
+	if err != nil {
+		if errors.Is(err, model.ErrDB) && sess.Editor(c) {
+			art, err = model.OneEditByKey(ctx, db, key)
+		}
+		if err != nil {
+			return fmt.Errorf(format, id, err)
+		}
+	}
+	b, ok := SHA384(art.FileIntegrityStrong.String)
+	if !ok {
+		return fmt.Errorf(format, art.ID, ErrNone)
+	}
+	name := art.Filename.String
+	body := hex.EncodeToString(b) + "  " + name + "\n"
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": name + Sidecar}))
+	if err := c.Blob(http.StatusOK, echo.MIMETextPlainCharsetUTF8, []byte(body)); err != nil {
+		return fmt.Errorf(format, id, err)
+	}
+	return nil
+}
+
+// Sums is the manifest of the SHA-384 checksums of the public artifact downloads,
+// signed with an Ed25519 key so mirrors can verify their bulk fetches.
+//
+// Each line of the manifest uses the `shasum --check` format, with the checksum
+// followed by the download URL path, for example:
+//
+//	72f8a29d75993487b7ad5...  d/9b1c6
+//
+// Every build of the manifest is versioned by the SHA-384 checksum of its body,
+// which is served as the ETag of both the manifest and its signature.
+// The zero value serves the manifest without a signature.
+type Sums struct {
+	key  ed25519.PrivateKey
+	mu   sync.Mutex
+	curr Signed
+	prev Signed
+	made time.Time
+}
+
+// Signed is a build of the manifest of checksums.
+type Signed struct {
+	Body    []byte // Body is the manifest lines.
+	Sig     string // Sig is the base64 encoded signature of the body, or empty when signing is disabled.
+	Version string // Version is the hexadecimal, SHA-384 checksum of the body.
+}
+
+// NewSums returns the manifest of checksums that are signed with the seed.
+// The seed is a base64 encoded, 32 byte Ed25519 private key seed,
+// and an empty seed disables the signing of the manifest.
+func NewSums(seed string) (*Sums, error) {
+	seed = strings.TrimSpace(seed)
+	if seed == "" {
+		return &Sums{}, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(seed)
+	if err != nil || len(b) != ed25519.SeedSize {
+		return nil, ErrSeed
+	}
+	return &Sums{key: ed25519.NewKeyFromSeed(b)}, nil
+}
+
+// Signing reports whether the manifest is signed.
+func (s *Sums) Signing() bool {
+	return s != nil && s.key != nil
+}
+
+// PublicKey returns the base64 encoded, Ed25519 public key used to verify the signature,
+// or an empty string when signing is disabled.
+func (s *Sums) PublicKey() string {
+	if !s.Signing() {
+		return ""
+	}
+	pub, _ := s.key.Public().(ed25519.PublicKey)
+	return base64.StdEncoding.EncodeToString(pub)
+}
+
+// Manifest returns the current build of the manifest.
+// The manifest is rebuilt once it is older than the SumsTTL,
+// and the previous build is kept so its signature can still be requested by version.
+func (s *Sums) Manifest(ctx context.Context, db *sql.DB) (Signed, error) {
+	const msg = "sums manifest"
+	if s == nil {
+		return Signed{}, fmt.Errorf("%s: %w", msg, ErrUnsigned)
+	}
+	if err := nils.Check(ctx, db); err != nil {
+		return Signed{}, fmt.Errorf("%s: %w", msg, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.curr.Body != nil && time.Since(s.made) < SumsTTL {
+		return s.curr, nil
+	}
+	sums, err := model.Integrities(ctx, db)
+	if err != nil {
+		return Signed{}, fmt.Errorf("%s: %w", msg, err)
+	}
+	next := s.sign(SumsBody(sums...))
+	if next.Version != s.curr.Version {
+		s.prev = s.curr
+	}
+	s.curr = next
+	s.made = time.Now()
+	return s.curr, nil
+}
+
+// Lookup returns the build of the manifest with the version,
+// which must be either the current or the previous build.
+func (s *Sums) Lookup(version string) (Signed, bool) {
+	if s == nil || version == "" {
+		return Signed{}, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch version {
+	case s.curr.Version:
+		return s.curr, true
+	case s.prev.Version:
+		return s.prev, true
+	}
+	return Signed{}, false
+}
+
+func (s *Sums) sign(body []byte) Signed {
+	sum := sha512.Sum384(body)
+	signed := Signed{Body: body, Version: hex.EncodeToString(sum[:])}
+	if s.key != nil {
+		signed.Sig = base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, body))
+	}
+	return signed
+}
+
+// SumsBody returns the manifest lines of the checksums,
+// with any invalid checksums skipped.
+func SumsBody(sums ...model.Integrity) []byte {
+	var buf bytes.Buffer
+	for _, sum := range sums {
+		b, ok := SHA384(sum.SHA384)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&buf, "%x  d/%s\n", b, helper.ObfuscateID(sum.ID))
+	}
+	return buf.Bytes()
+}
+
+// HTTPSend serves the manifest of checksums as a text file,
+// with the version of the manifest as the ETag.
+func (s *Sums) HTTPSend(ctx context.Context, c *echo.Context, db *sql.DB) error {
+	const msg = "sums http send"
+	if err := nils.Check(ctx, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	m, err := s.Manifest(ctx, db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	etag := `"` + m.Version + `"`
+	c.Response().Header().Set(headerETag, etag)
+	if c.Request().Header.Get(headerIfNone) == etag {
+		return c.NoContent(http.StatusNotModified)
+	}
+	if err := c.Blob(http.StatusOK, echo.MIMETextPlainCharsetUTF8, m.Body); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return nil
+}
+
+// HTTPSignature serves the base64 encoded, Ed25519 signature of the manifest of checksums,
+// with the version of the signed manifest as the ETag.
+//
+// The version of the manifest can be requested with the ETag of a fetched manifest,
+// using either the "v" query parameter or the If-Match header,
+// otherwise the signature of the current manifest is served.
+// An ErrVersion is returned when the requested version is no longer available,
+// and an ErrUnsigned when signing is disabled.
+func (s *Sums) HTTPSignature(ctx context.Context, c *echo.Context, db *sql.DB) error {
+	const msg = "sums http signature"
+	if err := nils.Check(ctx, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if !s.Signing() {
+		return fmt.Errorf("%s: %w", msg, ErrUnsigned)
+	}
+	m, err := s.Manifest(ctx, db)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if version := Version(c); version != "" {
+		var ok bool
+		if m, ok = s.Lookup(version); !ok {
+			return fmt.Errorf("%s %q: %w", msg, version, ErrVersion)
+		}
+	}
+	c.Response().Header().Set(headerETag, `"`+m.Version+`"`)
+	if err := c.String(http.StatusOK, m.Sig+"\n"); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return nil
+}
+
+// Version returns the requested version of the manifest of checksums,
+// using the "v" query parameter or the entity tag of the If-Match header.
+func Version(c *echo.Context) string {
+	if c == nil {
+		return ""
+	}
+	if v := strings.TrimSpace(c.QueryParam("v")); v != "" {
+		return strings.Trim(v, `"`)
+	}
+	tag := strings.TrimSpace(c.Request().Header.Get(headerIfMatch))
+	tag = strings.TrimPrefix(tag, "W/")
+	if tag == "*" {
+		return ""
+	}
+	return strings.Trim(tag, `"`)
+}
+
+// HTTPKey serves the base64 encoded, Ed25519 public key used to verify the signature.
+// An ErrUnsigned is returned when signing is disabled.
+func (s *Sums) HTTPKey(c *echo.Context) error {
+	const msg = "sums http key"
+	if err := nils.Check(c); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	key := s.PublicKey()
+	if key == "" {
+		return fmt.Errorf("%s: %w", msg, ErrUnsigned)
+	}
+	if err := c.String(http.StatusOK, key+"\n"); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return nil
+}
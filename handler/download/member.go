@@ -0,0 +1,412 @@
+package download
+
+// Package file member.go serves the individual files contained within the artifact archives.
+
+import (
+	"archive/zip"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Defacto2/archive"
+	"github.com/Defacto2/helper"
+	"github.com/Defacto2/server/handler/sess"
+	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/manifest"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+	"github.com/labstack/echo/v5"
+)
+
+var (
+	ErrArchive = errors.New("artifact download is not a supported archive")
+	ErrLimit   = errors.New("archive exceeds the extraction limits")
+	ErrMember  = errors.New("archive member path is invalid")
+)
+
+const (
+	// CacheDir is the name of the temporary directory used to store the extracted archives.
+	CacheDir = "defacto2-members"
+	// CacheTTL is the duration an extracted archive is kept before it is removed from the cache.
+	CacheTTL = 24 * time.Hour
+	// MaxExtract is the maximum number of uncompressed bytes that are extracted from an archive.
+	MaxExtract = 512 << 20
+	// MaxMembers is the maximum number of files that are extracted from an archive.
+	MaxMembers = 5000
+)
+
+// Archives returns the filename extensions of the archives that support the member downloads.
+func Archives() []string {
+	return []string{".arc", ".arj", ".lha", ".lzh", ".rar", ".zip"}
+}
+
+// Member configuration to serve a single file contained in an archive download.
+type Member struct {
+	Dir   dir.Directory // Dir is the absolute path to the download directory.
+	Cache string        // Cache is the directory used to store the extracted archives, an empty value uses the temp directory.
+}
+
+// HTTPSend extracts and serves a single file from the archive of an artifact and prompts for a save location.
+// The download relies on the URL ID parameter to determine the artifact,
+// and the wildcard parameter to determine the path of the file within the archive.
+// The file must be listed in the stored manifest of the archive, and the extraction is limited
+// by the MaxMembers and MaxExtract constants.
+func (m Member) HTTPSend(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "member http send"
+	const format = msg + " %s: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	name, err := MemberPath(c.Param("*"))
+	if err != nil {
+		return fmt.Errorf(format, "path", err)
+	}
+	key := c.Param("id")
+	art, err := model.OneFileByKey(ctx, db, key)
+	switch {
+	case err != nil && sess.Editor(c):
+		art, err = model.OneEditByKey(ctx, db, key)
+		if err != nil {
+			return fmt.Errorf(format, "one edit by key", err)
+		}
+	case err != nil:
+		return fmt.Errorf(format, "one file by key", err)
+	}
+	filename := art.Filename.String
+	if !slices.Contains(Archives(), strings.ToLower(filepath.Ext(filename))) {
+		return fmt.Errorf("%s, %w: %s", msg, ErrArchive, filename)
+	}
+	uid := strings.TrimSpace(art.UUID.String)
+	src := m.Dir.Join(uid)
+	if !helper.Stat(src) {
+		sl.Warn(msg,
+			slog.String("issue", "could not find the file download"),
+			slog.String("path", src),
+			slog.Int64("id", art.ID),
+			slog.String("filename", filename))
+		return fmt.Errorf("%s, %w: %s", msg, ErrStat, filename)
+	}
+	// only the members of the stored manifest are extracted, which is also used to enforce the limits
+	members, err := model.Manifest(ctx, db, art.ID)
+	if err != nil {
+		return fmt.Errorf(format, "manifest", err)
+	}
+	if !slices.ContainsFunc(members, func(m manifest.Member) bool { return m.Path == name }) {
+		return fmt.Errorf("%s, %w: %s", msg, ErrNone, name)
+	}
+	root, done, err := m.member(uid, src, filename, name, members)
+	if err != nil {
+		return fmt.Errorf(format, "extract", err)
+	}
+	defer done()
+	// the root prevents the symbolic links within the archive from escaping the extraction
+	r, err := os.OpenRoot(root)
+	if err != nil {
+		return fmt.Errorf(format, "open root", err)
+	}
+	defer func() { _ = r.Close() }()
+	f, err := r.Open(filepath.FromSlash(name))
+	if err != nil {
+		return fmt.Errorf("%s, %w: %s", msg, ErrNone, name)
+	}
+	defer func() { _ = f.Close() }()
+	st, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf(format, "stat", err)
+	}
+	if !st.Mode().IsRegular() {
+		return fmt.Errorf("%s, %w: %s", msg, ErrNone, name)
+	}
+	base := path.Base(name)
+	header := c.Response().Header()
+	header.Set(echo.HeaderContentType, ContentType(base, f))
+	header.Set(echo.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": base}))
+	http.ServeContent(c.Response(), c.Request(), base, st.ModTime(), f)
+	return nil
+}
+
+// MemberPath returns the unescaped path of an archive member from the URL parameter.
+// It returns an error if the path is empty, absolute, or allows for any path traversal.
+func MemberPath(param string) (string, error) {
+	name, err := url.QueryUnescape(param)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrMember, err)
+	}
+	name = strings.ReplaceAll(name, `\`, "/")
+	switch {
+	case name == "", strings.HasSuffix(name, "/"):
+		return "", fmt.Errorf("%w: %q", ErrMember, name)
+	case path.IsAbs(name), filepath.IsAbs(name):
+		return "", fmt.Errorf("%w: %q", ErrMember, name)
+	case path.Clean(name) != name:
+		return "", fmt.Errorf("%w: %q", ErrMember, name)
+	case name == "..", strings.HasPrefix(name, "../"):
+		return "", fmt.Errorf("%w: %q", ErrMember, name)
+	}
+	return name, nil
+}
+
+// ContentType returns the MIME type of the named file using its extension,
+// otherwise the content is sniffed. Plain texts that are not UTF-8 are assumed
+// to use a legacy 8-bit character set. The reader is rewound before returning.
+func ContentType(name string, r io.ReadSeeker) string {
+	const sniff = 512
+	b := make([]byte, sniff)
+	n, _ := io.ReadFull(r, b)
+	_, _ = r.Seek(0, io.SeekStart)
+	b = b[:n]
+	ctype := mime.TypeByExtension(strings.ToLower(filepath.Ext(name)))
+	if ctype == "" {
+		ctype = http.DetectContentType(b)
+	}
+	if !strings.HasPrefix(ctype, "text/plain") {
+		return ctype
+	}
+	if n == sniff {
+		// the sample could end with an incomplete rune
+		for i := 0; i < utf8.UTFMax && len(b) > 0 && !utf8.Valid(b); i++ {
+			b = b[:len(b)-1]
+		}
+	}
+	if !utf8.Valid(b) {
+		return "text/plain; charset=iso-8859-1"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+func (m Member) cache() string {
+	if m.Cache != "" {
+		return m.Cache
+	}
+	return filepath.Join(helper.TmpDir(), CacheDir)
+}
+
+// member returns the directory containing the extracted name member of the src archive
+// and a func that must be called once the member is served.
+// Only the member is extracted from a zip archive, while the other formats extract the whole archive.
+// The extraction is kept in the cache and reused unless the src file has since been modified.
+func (m Member) member(uid, src, filename, name string, members []manifest.Member) (string, func(), error) {
+	if !zipped(filename) {
+		return m.extract(uid, src, filename, members)
+	}
+	st, err := os.Stat(src)
+	if err != nil {
+		return "", func() {}, fmt.Errorf("member extract: %w", err)
+	}
+	fresh := func(dst string) bool {
+		f, err := os.Stat(filepath.Join(dst, filepath.FromSlash(name)))
+		return err == nil && f.Mode().IsRegular() && newer(dst, st.ModTime())
+	}
+	return m.cached(uid, fresh, func(dst string) error {
+		if !newer(dst, st.ModTime()) {
+			_ = os.RemoveAll(dst)
+		}
+		if err := os.MkdirAll(dst, 0o755); err != nil {
+			return fmt.Errorf("member extract cache: %w", err)
+		}
+		if err := unzip(src, dst, name); err != nil {
+			return fmt.Errorf("member extract %q: %w", filename, err)
+		}
+		now := time.Now()
+		_ = os.Chtimes(dst, now, now)
+		return nil
+	})
+}
+
+// extract returns the directory containing the extracted src archive
+// and a func that must be called once the directory is no longer used.
+// The members are the stored manifest of the archive that are used to enforce the extraction limits.
+// The extraction is kept in the cache and reused unless the src file has since been modified.
+func (m Member) extract(uid, src, filename string, members []manifest.Member) (string, func(), error) {
+	st, err := os.Stat(src)
+	if err != nil {
+		return "", func() {}, fmt.Errorf("member extract: %w", err)
+	}
+	fresh := func(dst string) bool {
+		return newer(dst, st.ModTime())
+	}
+	return m.cached(uid, fresh, func(dst string) error {
+		if err := Limits(members); err != nil {
+			return fmt.Errorf("member extract %q: %w", filename, err)
+		}
+		if err := os.MkdirAll(m.cache(), 0o755); err != nil {
+			return fmt.Errorf("member extract cache: %w", err)
+		}
+		tmp, err := archive.ExtractSource(src, filename)
+		if err != nil {
+			return fmt.Errorf("member extract %q: %w", filename, err)
+		}
+		if size := dirSize(tmp); size > MaxExtract {
+			_ = os.RemoveAll(tmp)
+			return fmt.Errorf("member extract %q: %w: %d bytes", filename, ErrLimit, size)
+		}
+		_ = os.RemoveAll(dst)
+		if err := os.Rename(tmp, dst); err != nil {
+			_ = os.RemoveAll(tmp)
+			return fmt.Errorf("member extract move: %w", err)
+		}
+		now := time.Now()
+		_ = os.Chtimes(dst, now, now)
+		return nil
+	})
+}
+
+// cached returns the uid directory of the cache once it is fresh, otherwise it is first filled.
+// The directory is locked for reading until the returned func is called,
+// so it cannot be replaced or pruned while it is in use.
+func (m Member) cached(uid string, fresh func(dst string) bool, fill func(dst string) error) (string, func(), error) {
+	dst := filepath.Join(m.cache(), uid)
+	l := acquire(uid)
+	const attempts = 2
+	for range attempts {
+		l.RLock()
+		if fresh(dst) {
+			return dst, func() {
+				l.RUnlock()
+				release(uid, l)
+			}, nil
+		}
+		l.RUnlock()
+		if err := fillLocked(l, dst, fresh, fill); err != nil {
+			release(uid, l)
+			return "", func() {}, err
+		}
+	}
+	release(uid, l)
+	return "", func() {}, fmt.Errorf("member cache %s: %w", uid, ErrNone)
+}
+
+// fillLocked calls fill with the write lock held, unless the directory became fresh while waiting for the lock.
+// The number of fills that run at the same time is limited by the Extractions constant.
+func fillLocked(l *uidLock, dst string, fresh func(string) bool, fill func(string) error) error {
+	l.Lock()
+	defer l.Unlock()
+	if fresh(dst) {
+		return nil
+	}
+	extractions <- struct{}{}
+	defer func() { <-extractions }()
+	return fill(dst)
+}
+
+// newer returns true if the named directory exists and was modified after t.
+func newer(name string, t time.Time) bool {
+	st, err := os.Stat(name)
+	return err == nil && st.IsDir() && st.ModTime().After(t)
+}
+
+// zipped returns true if the filename uses the zip archive extension.
+func zipped(filename string) bool {
+	return strings.EqualFold(filepath.Ext(filename), ".zip")
+}
+
+// Limits returns an error if the files of the archive manifest exceed the MaxMembers or MaxExtract limits.
+// An empty manifest also returns an error, as the size of the archive is unknown.
+func Limits(members []manifest.Member) error {
+	if len(members) == 0 {
+		return fmt.Errorf("%w: the archive manifest is yet to be read", ErrLimit)
+	}
+	if len(members) > MaxMembers {
+		return fmt.Errorf("%w: %d files", ErrLimit, len(members))
+	}
+	var sum int64
+	for _, m := range members {
+		sum += m.Size
+	}
+	if sum > MaxExtract {
+		return fmt.Errorf("%w: %d bytes", ErrLimit, sum)
+	}
+	return nil
+}
+
+// unzip extracts the named files of the src zip archive to the dst directory,
+// or all the files when no names are given. The files are limited by the MaxMembers and MaxExtract constants.
+func unzip(src, dst string, names ...string) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return fmt.Errorf("unzip: %w", err)
+	}
+	defer func() { _ = r.Close() }()
+	root, err := os.OpenRoot(dst)
+	if err != nil {
+		return fmt.Errorf("unzip: %w", err)
+	}
+	defer func() { _ = root.Close() }()
+	files := make([]*zip.File, 0, len(r.File))
+	var sum uint64
+	for _, f := range r.File {
+		if !f.Mode().IsRegular() || (len(names) > 0 && !slices.Contains(names, f.Name)) {
+			continue
+		}
+		files = append(files, f)
+		sum += f.UncompressedSize64
+	}
+	switch {
+	case len(files) == 0:
+		return fmt.Errorf("unzip: %w: %s", ErrNone, strings.Join(names, ", "))
+	case len(files) > MaxMembers:
+		return fmt.Errorf("unzip: %w: %d files", ErrLimit, len(files))
+	case sum > MaxExtract:
+		return fmt.Errorf("unzip: %w: %d bytes", ErrLimit, sum)
+	}
+	var written int64
+	for _, f := range files {
+		n, err := unzipFile(root, f, MaxExtract-written)
+		if err != nil {
+			return fmt.Errorf("unzip %q: %w", f.Name, err)
+		}
+		written += n
+	}
+	return nil
+}
+
+// unzipFile extracts the zip file to the root directory and returns the number of bytes written,
+// which cannot exceed the limit as the uncompressed size stored in the archive could be incorrect.
+func unzipFile(root *os.Root, f *zip.File, limit int64) (int64, error) {
+	name, err := MemberPath(f.Name)
+	if err != nil {
+		return 0, err
+	}
+	if dir := path.Dir(name); dir != "." {
+		if err := root.MkdirAll(dir, 0o755); err != nil {
+			return 0, err
+		}
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = rc.Close() }()
+	w, err := root.Create(name)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(w, io.LimitReader(rc, limit+1))
+	if err != nil {
+		_ = w.Close()
+		return n, err
+	}
+	if err := w.Close(); err != nil {
+		return n, err
+	}
+	if n > limit {
+		return n, fmt.Errorf("%w: %d bytes", ErrLimit, n)
+	}
+	if mod := f.Modified; !mod.IsZero() {
+		_ = root.Chtimes(name, mod, mod)
+	}
+	return n, nil
+}
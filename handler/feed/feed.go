@@ -0,0 +1,416 @@
+// Package feed generates the Atom and RSS syndication feeds of the website.
+//
+// Useful links,
+//
+//   - [RFC 4287, The Atom Syndication Format]
+//   - [RSS 2.0 Specification]
+//   - [W3C Feed Validation Service]
+//
+// [RFC 4287, The Atom Syndication Format]: https://www.rfc-editor.org/rfc/rfc4287
+// [RSS 2.0 Specification]: https://www.rssboard.org/rss-specification
+// [W3C Feed Validation Service]: https://validator.w3.org/feed/
+package feed
+
+import (
+	"context"
+	"database/sql"
+	"encoding/xml"
+	"fmt"
+	"hash/fnv"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Defacto2/helper"
+	"github.com/Defacto2/server/handler/sitemap"
+	"github.com/Defacto2/server/model"
+)
+
+const (
+	AtomNS   = "http://www.w3.org/2005/Atom" // AtomNS is the XML namespace of an Atom feed.
+	MIMEAtom = "application/atom+xml"        // MIMEAtom is the media type of an Atom feed.
+	MIMERSS  = "application/rss+xml"         // MIMERSS is the media type of a RSS feed.
+
+	author = "Defacto2"
+	limit  = 100 // per-feed entry limit
+)
+
+// Format is the syndication format of a feed.
+type Format string
+
+const (
+	FormatAtom Format = "atom" // FormatAtom is the Atom 1.0 format.
+	FormatRSS  Format = "rss"  // FormatRSS is the RSS 2.0 format.
+)
+
+// Valid returns true if the format is a known syndication format.
+func (f Format) Valid() bool {
+	return f == FormatAtom || f == FormatRSS
+}
+
+// MIME returns the media type of the format.
+func (f Format) MIME() string {
+	if f == FormatRSS {
+		return MIMERSS
+	}
+	return MIMEAtom
+}
+
+// Listing is a format neutral syndication feed that can be rendered as either Atom or RSS.
+type Listing struct {
+	Title    string // Title of the feed.
+	Subtitle string // Subtitle or description of the feed.
+	Self     string // Self is the absolute path of the feed, such as "/feed/atom/files/new-uploads".
+	Link     string // Link is the absolute path of the web page of the feed, such as "/files/new-uploads".
+	Items    []Item // Items of the feed with the newest first.
+}
+
+// Item is an individual item of a Listing.
+type Item struct {
+	ID        string     // ID is the permanent and unique identifier of the item, such as a tag URI.
+	Title     string     // Title of the item.
+	Link      string     // Link is the absolute path of the web page of the item, it is optional.
+	Summary   string     // Summary of the item, it is optional.
+	Category  string     // Category is the optional term that classifies the item.
+	Published time.Time  // Published is the time the item was first made available, it is optional.
+	Updated   time.Time  // Updated is the last time the item was modified.
+	Enclosure *Enclosure // Enclosure is an optional media file of the item, such as a thumbnail.
+}
+
+// Enclosure is a media file of an item.
+type Enclosure struct {
+	URL    string // URL is the absolute path of the media file.
+	Type   string // Type is the media type of the file, such as "image/webp".
+	Length int64  // Length is the size of the file in bytes.
+}
+
+// Updated returns the most recent updated time of the items,
+// or the zero time if the listing has no items.
+func (l Listing) Updated() time.Time {
+	var t time.Time
+	for _, item := range l.Items {
+		if item.Updated.After(t) {
+			t = item.Updated
+		}
+	}
+	return t
+}
+
+// ETag returns a weak entity tag of the listing in the format,
+// that changes whenever an item is added, removed or updated.
+func (l Listing) ETag(f Format) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(string(f) + l.Self))
+	for _, item := range l.Items {
+		_, _ = h.Write([]byte(item.ID + strconv.FormatInt(item.Updated.UnixNano(), 10)))
+	}
+	return `W/"` + strconv.FormatUint(h.Sum64(), 16) + `"`
+}
+
+// Marshal returns the XML encoding of the listing in the format with the XML header.
+func (l Listing) Marshal(f Format) ([]byte, error) {
+	var v any = l.Atom()
+	if f == FormatRSS {
+		v = l.RSS()
+	}
+	b, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("feed marshal %s: %w", f, err)
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// NotModified returns true if the conditional request headers confirm the client holds
+// the current copy of the feed. The If-None-Match header takes precedence over If-Modified-Since.
+func NotModified(r *http.Request, etag string, modified time.Time) bool {
+	if r == nil {
+		return false
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" {
+		for tag := range strings.SplitSeq(match, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+				return true
+			}
+		}
+		return false
+	}
+	since, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+	if err != nil || modified.IsZero() {
+		return false
+	}
+	return !modified.Truncate(time.Second).After(since)
+}
+
+// Atom is an Atom 1.0 syndication feed.
+//
+// An example output:
+//
+// <feed xmlns="http://www.w3.org/2005/Atom">
+//
+//	<id>https://defacto2.net/api/v1/changes/atom</id>
+//	<title>Defacto2 artifact changes</title>
+//	<updated>2024-08-15T12:00:00Z</updated>
+//	<entry>
+//	  <id>tag:defacto2.net,2024:change/1</id>
+//	  <title>update: Razor 1911 intro</title>
+//	  <updated>2024-08-15T12:00:00Z</updated>
+//	</entry>
+//
+// </feed>
+//
+// See package documentation for links.
+type Atom struct {
+	XMLName  xml.Name `xml:"feed"`
+	XMLNS    string   `xml:"xmlns,attr"`
+	ID       string   `xml:"id"`
+	Title    string   `xml:"title"`
+	Subtitle string   `xml:"subtitle,omitempty"`
+	Updated  string   `xml:"updated"`
+	Links    []Link   `xml:"link"`
+	Author   Person   `xml:"author"`
+	Entries  []Entry  `xml:"entry"`
+}
+
+// Entry is an individual item of the Atom feed.
+type Entry struct {
+	ID         string     `xml:"id"`
+	Title      string     `xml:"title"`
+	Updated    string     `xml:"updated"`
+	Published  string     `xml:"published,omitempty"`
+	Links      []Link     `xml:"link"`
+	Categories []Category `xml:"category"`
+	Summary    string     `xml:"summary,omitempty"`
+}
+
+// Link is a reference from the feed or an entry to a web resource.
+type Link struct {
+	Rel    string `xml:"rel,attr,omitempty"`
+	Href   string `xml:"href,attr"`
+	Type   string `xml:"type,attr,omitempty"`
+	Length int64  `xml:"length,attr,omitempty"`
+}
+
+// Category is a term that classifies an entry.
+type Category struct {
+	Term string `xml:"term,attr"`
+}
+
+// Person is the author of the feed.
+type Person struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+// Marshal returns the XML encoding of the Atom feed with the XML header.
+func (a *Atom) Marshal() ([]byte, error) {
+	b, err := xml.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("atom marshal: %w", err)
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// Atom returns the listing as an Atom feed.
+func (l Listing) Atom() *Atom {
+	updated := l.Updated()
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+	a := &Atom{
+		XMLName:  xml.Name{Space: "", Local: "feed"},
+		XMLNS:    AtomNS,
+		ID:       sitemap.RootURL + l.Self,
+		Title:    l.Title,
+		Subtitle: l.Subtitle,
+		Updated:  updated.UTC().Format(time.RFC3339),
+		Links: []Link{
+			{Rel: "self", Href: sitemap.RootURL + l.Self, Type: MIMEAtom},
+			{Rel: "alternate", Href: sitemap.RootURL + l.Link, Type: "text/html"},
+		},
+		Author:  Person{Name: author, URI: sitemap.RootURL},
+		Entries: make([]Entry, 0, len(l.Items)),
+	}
+	for _, item := range l.Items {
+		e := Entry{
+			ID:      item.ID,
+			Title:   item.Title,
+			Updated: item.Updated.UTC().Format(time.RFC3339),
+			Summary: item.Summary,
+		}
+		if !item.Published.IsZero() {
+			e.Published = item.Published.UTC().Format(time.RFC3339)
+		}
+		if item.Link != "" {
+			e.Links = append(e.Links, Link{Rel: "alternate", Href: sitemap.RootURL + item.Link, Type: "text/html"})
+		}
+		if enc := item.Enclosure; enc != nil {
+			e.Links = append(e.Links, Link{
+				Rel: "enclosure", Href: sitemap.RootURL + enc.URL, Type: enc.Type, Length: enc.Length,
+			})
+		}
+		if item.Category != "" {
+			e.Categories = []Category{{Term: item.Category}}
+		}
+		a.Entries = append(a.Entries, e)
+	}
+	return a
+}
+
+// RSS is a RSS 2.0 syndication feed.
+//
+// An example output:
+//
+// <rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+//
+//	<channel>
+//	  <title>Defacto2 new uploads</title>
+//	  <link>https://defacto2.net/files/new-uploads</link>
+//	  <item>
+//	    <title>Razor 1911 intro</title>
+//	    <guid isPermaLink="false">tag:defacto2.net,2024:artifact/1</guid>
+//	  </item>
+//	</channel>
+//
+// </rss>
+//
+// See package documentation for links.
+type RSS struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	XMLNS   string     `xml:"xmlns:atom,attr"`
+	Channel RSSChannel `xml:"channel"`
+}
+
+// RSSChannel is the metadata and the items of the RSS feed.
+type RSSChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	AtomLink      RSSSelf   `xml:"atom:link"`
+	Items         []RSSItem `xml:"item"`
+}
+
+// RSSSelf is the Atom self link of the RSS feed, as recommended by the RSS Advisory Board.
+type RSSSelf struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+// RSSItem is an individual item of the RSS feed.
+type RSSItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link,omitempty"`
+	Description string        `xml:"description,omitempty"`
+	Category    string        `xml:"category,omitempty"`
+	GUID        RSSGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate,omitempty"`
+	Enclosure   *RSSEnclosure `xml:"enclosure,omitempty"`
+}
+
+// RSSGUID is the unique identifier of the RSS item.
+type RSSGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSSEnclosure is a media file of the RSS item.
+type RSSEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// RSS returns the listing as a RSS feed.
+// The pubDate of the items is their updated time, so readers notice the modified artifacts.
+func (l Listing) RSS() *RSS {
+	r := &RSS{
+		XMLName: xml.Name{Space: "", Local: "rss"},
+		Version: "2.0",
+		XMLNS:   AtomNS,
+		Channel: RSSChannel{
+			Title:       l.Title,
+			Link:        sitemap.RootURL + l.Link,
+			Description: l.Subtitle,
+			AtomLink:    RSSSelf{Href: sitemap.RootURL + l.Self, Rel: "self", Type: MIMERSS},
+			Items:       make([]RSSItem, 0, len(l.Items)),
+		},
+	}
+	if updated := l.Updated(); !updated.IsZero() {
+		r.Channel.LastBuildDate = updated.UTC().Format(time.RFC1123Z)
+	}
+	for _, item := range l.Items {
+		ri := RSSItem{
+			Title:       item.Title,
+			Description: item.Summary,
+			Category:    item.Category,
+			GUID:        RSSGUID{IsPermaLink: false, Value: item.ID},
+			PubDate:     item.Updated.UTC().Format(time.RFC1123Z),
+		}
+		if item.Link != "" {
+			ri.Link = sitemap.RootURL + item.Link
+		}
+		if enc := item.Enclosure; enc != nil {
+			ri.Enclosure = &RSSEnclosure{URL: sitemap.RootURL + enc.URL, Length: enc.Length, Type: enc.Type}
+		}
+		r.Channel.Items = append(r.Channel.Items, ri)
+	}
+	return r
+}
+
+// Changes generates the feed of the most recent changes made to the artifacts.
+// The self value is the absolute path of the feed, such as "/api/v1/changes/atom".
+func Changes(ctx context.Context, db *sql.DB, sl *slog.Logger, self string) *Atom {
+	const msg = "feed changes"
+	var changes model.Changes
+	if err := changes.Latest(ctx, db, limit); err != nil {
+		sl.Error(msg, slog.String("model", "could not obtain the latest changes"),
+			slog.Any("error", err))
+	}
+	return ChangeLog(changes, self)
+}
+
+// ChangeLog returns the feed of the logged changes.
+// The self value is the absolute path of the feed, such as "/api/v1/changes/atom".
+func ChangeLog(changes model.Changes, self string) *Atom {
+	l := Listing{
+		Title:    "Defacto2 artifact changes",
+		Subtitle: "The artifacts that have been added, edited, taken offline or deleted.",
+		Self:     self,
+		Link:     "/files/new-updates",
+		Items:    make([]Item, 0, len(changes)),
+	}
+	for _, change := range changes {
+		if change == nil {
+			continue
+		}
+		l.Items = append(l.Items, changeItem(change))
+	}
+	return l.Atom()
+}
+
+func changeItem(change *model.FileChange) Item {
+	title := change.Title.String
+	if title == "" {
+		title = change.UUID.String
+	}
+	item := Item{
+		ID:       "tag:defacto2.net,2024:change/" + strconv.FormatInt(change.Seq, 10),
+		Title:    change.Kind + ": " + title,
+		Category: change.Kind,
+		Updated:  change.Changed,
+	}
+	switch model.Change(change.Kind) {
+	case model.HardDeleted:
+		item.Summary = "The artifact has been permanently deleted."
+	case model.SoftDeleted:
+		item.Summary = "The artifact has been taken offline."
+	default:
+		item.Link = "/f/" + helper.ObfuscateID(change.FileID)
+	}
+	return item
+}
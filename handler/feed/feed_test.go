@@ -0,0 +1,107 @@
+package feed_test
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/handler/feed"
+	"github.com/Defacto2/server/handler/sitemap"
+	"github.com/Defacto2/server/model"
+	"github.com/aarondl/null/v8"
+	"github.com/nalgeon/be"
+)
+
+func TestChangeLog(t *testing.T) {
+	t.Parallel()
+	const self = "/api/v1/changes/atom"
+	changed := time.Date(2024, 8, 15, 12, 0, 0, 0, time.UTC)
+	changes := model.Changes{
+		{Seq: 2, FileID: 1, Kind: string(model.HardDeleted), Changed: changed, UUID: null.StringFrom("abc")},
+		nil,
+		{Seq: 1, FileID: 1, Kind: string(model.Created), Changed: changed, Title: null.StringFrom("intro")},
+	}
+	a := feed.ChangeLog(changes, self)
+	be.Equal(t, a.XMLNS, feed.AtomNS)
+	be.Equal(t, a.ID, sitemap.RootURL+self)
+	be.Equal(t, a.Updated, "2024-08-15T12:00:00Z")
+	be.Equal(t, len(a.Entries), 2)
+	be.Equal(t, a.Entries[0].Title, "hard-delete: abc")
+	be.Equal(t, len(a.Entries[0].Links), 0)
+	be.Equal(t, a.Entries[1].Title, "create: intro")
+	be.Equal(t, len(a.Entries[1].Links), 1)
+	be.Equal(t, a.Entries[1].ID, "tag:defacto2.net,2024:change/1")
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+	a := feed.ChangeLog(nil, "/api/v1/changes/atom")
+	b, err := a.Marshal()
+	be.Err(t, err, nil)
+	s := string(b)
+	be.True(t, strings.HasPrefix(s, xml.Header))
+	be.True(t, strings.Contains(s, `<feed xmlns="http://www.w3.org/2005/Atom">`))
+}
+
+func TestListing(t *testing.T) {
+	t.Parallel()
+	updated := time.Date(2024, 8, 15, 12, 0, 0, 0, time.UTC)
+	l := feed.Listing{
+		Title: "Defacto2 new uploads",
+		Self:  "/feed/rss/files/new-uploads",
+		Link:  "/files/new-uploads",
+		Items: []feed.Item{
+			{
+				ID: "tag:defacto2.net,2024:artifact/1", Title: "intro", Link: "/f/1", Updated: updated,
+				Enclosure: &feed.Enclosure{URL: "/public/image/thumb/abc.webp", Type: "image/webp", Length: 10},
+			},
+			{ID: "tag:defacto2.net,2024:artifact/2", Title: "demo", Updated: updated.Add(-time.Hour)},
+		},
+	}
+	be.Equal(t, l.Updated(), updated)
+	be.True(t, l.ETag(feed.FormatRSS) != l.ETag(feed.FormatAtom))
+
+	r := l.RSS()
+	be.Equal(t, r.Version, "2.0")
+	be.Equal(t, len(r.Channel.Items), 2)
+	be.Equal(t, r.Channel.Items[0].Link, sitemap.RootURL+"/f/1")
+	be.Equal(t, r.Channel.Items[0].Enclosure.Length, int64(10))
+	be.True(t, r.Channel.Items[1].Enclosure == nil)
+
+	a := l.Atom()
+	be.Equal(t, len(a.Entries), 2)
+	be.Equal(t, len(a.Entries[0].Links), 2)
+	be.Equal(t, a.Entries[0].Links[1].Rel, "enclosure")
+
+	b, err := l.Marshal(feed.FormatRSS)
+	be.Err(t, err, nil)
+	be.True(t, strings.Contains(string(b), `<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">`))
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+	be.True(t, feed.FormatAtom.Valid())
+	be.True(t, feed.FormatRSS.Valid())
+	be.True(t, !feed.Format("json").Valid())
+	be.Equal(t, feed.FormatRSS.MIME(), feed.MIMERSS)
+	be.Equal(t, feed.FormatAtom.MIME(), feed.MIMEAtom)
+}
+
+func TestNotModified(t *testing.T) {
+	t.Parallel()
+	modified := time.Date(2024, 8, 15, 12, 0, 0, 0, time.UTC)
+	const etag = `W/"abc"`
+	be.True(t, !feed.NotModified(nil, etag, modified))
+	r := httptest.NewRequest(http.MethodGet, "/feed/atom/files/new-uploads", nil)
+	be.True(t, !feed.NotModified(r, etag, modified))
+	r.Header.Set("If-Modified-Since", modified.Format(http.TimeFormat))
+	be.True(t, feed.NotModified(r, etag, modified))
+	be.True(t, !feed.NotModified(r, etag, modified.Add(time.Minute)))
+	r.Header.Set("If-None-Match", `W/"xyz"`)
+	be.True(t, !feed.NotModified(r, etag, modified))
+	r.Header.Set("If-None-Match", `"abc"`)
+	be.True(t, feed.NotModified(r, etag, modified))
+}
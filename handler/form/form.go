@@ -9,9 +9,7 @@ import (
 	"html/template"
 	"net/url"
 	"regexp"
-	"strconv"
 	"strings"
-	"time"
 
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/Defacto2/server/internal/tags"
@@ -147,55 +145,9 @@ func SanitizeGitHub(repo string) string {
 
 // ValidDate returns three boolean values that indicate if the year, month, and day are valid.
 // If any of the bool values are false, the date syntax is invalid and should not be used.
-//
-// The year must be between 1980 and the current year.
-// If the year is not in use, the month and day must not be in use.
-// And if the month is not in use, the day must not in use.
-//
-// A not in use value is either "0" or an empty string.
-func ValidDate(y, m, d string) (bool, bool, bool) { //nolint:cyclop
-	yok, mok, dok := true, true, true
-	current := time.Now().Year()
-
-	year, err := strconv.Atoi(y)
-	if err != nil {
-		yok = false
-	}
-	useYear := year != 0 && y != ""
-	validYear := year >= model.EpochYear && year <= current
-	if useYear && !validYear {
-		yok = false
-	}
-
-	month, err := strconv.Atoi(m)
-	if err != nil {
-		mok = false
-	}
-	useMonth := month != 0 && m != ""
-	const jan, dec = 1, 12
-	validMonth := month >= jan && month <= dec
-	if useMonth && !validMonth {
-		mok = false
-	}
-
-	day, err := strconv.Atoi(d)
-	if err != nil {
-		dok = false
-	}
-	useDay := day != 0 && d != ""
-	const first, last = 1, 31
-	validDay := day >= first && day <= last
-	if useDay && !validDay {
-		dok = false
-	}
-
-	if !useYear && (validMonth || validDay) {
-		yok = false
-	}
-	if !useMonth && validDay {
-		mok = false
-	}
-	return yok, mok, dok
+// See [model.ValidDate] for the rules.
+func ValidDate(y, m, d string) (bool, bool, bool) {
+	return model.ValidDate(y, m, d)
 }
 
 // ValidVT returns true if the link is a valid VirusTotal URL
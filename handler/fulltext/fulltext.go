@@ -3,8 +3,9 @@
 // the application.
 //
 // Currently, the index is built at startup and stored in RAM as the
-// number of files to catalog is low. However, if we were to expand
-// the indexing to file downloads a different approach is needed.
+// number of files to catalog is low. The far larger collection of
+// plain texts found with the file downloads are instead indexed by
+// the database, with this package only locating and cleaning the texts.
 package fulltext
 
 import (
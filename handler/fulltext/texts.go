@@ -0,0 +1,204 @@
+package fulltext
+
+// Package file texts.go indexes the plain texts, such as NFO, DIZ and README files,
+// that are either stored in the extra directory or found within the artifact archives.
+// Unlike the tidbits, the index is kept in the database so it survives a restart
+// and only the artifacts with new or modified files need to be indexed.
+
+import (
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Defacto2/archive"
+	"github.com/Defacto2/server/internal/encodings"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	MaxSize  = 1024 * 1024   // MaxSize in bytes of a text file that will be indexed.
+	NameDiz  = "file_id.diz" // NameDiz is the indexed name of the extra directory FILE_ID.DIZ copy.
+	NameText = "readme"      // NameText is the indexed name of the extra directory readme copy.
+)
+
+// ansiEscape matches the ANSI escape codes used to color and position the text.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+
+// Texts are the directories used to locate the plain texts of the artifacts.
+type Texts struct {
+	Download string // Download is the directory path for the file downloads.
+	Extra    string // Extra is the directory path for the extra files, or empty to skip them.
+}
+
+// Modified returns the most recent modification time of the artifact files that can
+// contain texts. A zero time is returned when there are no files.
+func (t *Texts) Modified(art *models.File) time.Time {
+	if art == nil || !art.UUID.Valid {
+		return time.Time{}
+	}
+	unid := art.UUID.String
+	names := []string{}
+	if t.Extra != "" {
+		names = append(names,
+			filepath.Join(t.Extra, unid+".txt"),
+			filepath.Join(t.Extra, unid+".diz"))
+	}
+	if Archived(art.FileZipContent.String) {
+		names = append(names, filepath.Join(t.Download, unid))
+	}
+	var last time.Time
+	for _, name := range names {
+		st, err := os.Stat(name)
+		if err != nil {
+			continue
+		}
+		if mod := st.ModTime(); mod.After(last) {
+			last = mod
+		}
+	}
+	// the database stores timestamps to the microsecond
+	return last.Truncate(time.Microsecond)
+}
+
+// Bodies returns the plain texts of the artifact keyed by their names.
+// Duplicate texts, such as an archived NFO with a copy in the extra directory, are only returned once.
+// The charset is the key of the text encoding chosen by an editor for the texts of the artifact,
+// or an empty string to use the default encodings.
+func (t *Texts) Bodies(art *models.File, charset string) map[string]string {
+	bodies := map[string]string{}
+	if art == nil || !art.UUID.Valid {
+		return bodies
+	}
+	unid := art.UUID.String
+	wide := false
+	if c, ok := encodings.Find(charset); ok {
+		wide = c.Wide()
+	}
+	add := func(name string, b []byte) {
+		s := PlainCharset(b, charset)
+		if s == "" {
+			return
+		}
+		for _, body := range bodies {
+			if body == s {
+				return
+			}
+		}
+		bodies[name] = s
+	}
+	if t.Extra != "" {
+		if b, err := readText(filepath.Join(t.Extra, unid+".diz"), wide); err == nil {
+			add(NameDiz, b)
+		}
+		if b, err := readText(filepath.Join(t.Extra, unid+".txt"), wide); err == nil {
+			add(NameText, b)
+		}
+	}
+	if !Archived(art.FileZipContent.String) {
+		return bodies
+	}
+	src := filepath.Join(t.Download, unid)
+	if _, err := os.Stat(src); err != nil {
+		return bodies
+	}
+	tmp, err := archive.ExtractSource(src, art.Filename.String)
+	if err != nil {
+		return bodies
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	_ = filepath.WalkDir(tmp, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !IsText(d.Name()) {
+			return nil //nolint:nilerr
+		}
+		b, err := readText(path, wide)
+		if err != nil {
+			return nil //nolint:nilerr
+		}
+		rel, err := filepath.Rel(tmp, path)
+		if err != nil {
+			rel = d.Name()
+		}
+		add(filepath.ToSlash(rel), b)
+		return nil
+	})
+	return bodies
+}
+
+// Archived returns true if the archive content list, as stored in the file_zip_content column,
+// contains any text files that can be indexed.
+func Archived(content string) bool {
+	for name := range strings.SplitSeq(content, "\n") {
+		if IsText(strings.TrimSpace(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsText returns true if the named file is a plain text that can be indexed,
+// such as an NFO, DIZ, README or TXT file.
+func IsText(name string) bool {
+	base := strings.ToLower(filepath.Base(filepath.ToSlash(name)))
+	if base == "" || base == "." {
+		return false
+	}
+	if strings.HasPrefix(base, "readme") || strings.HasPrefix(base, "read.me") {
+		return true
+	}
+	exts := []string{".1st", ".asc", ".diz", ".doc", ".me", ".nfo", ".now", ".txt"}
+	return slices.Contains(exts, filepath.Ext(base))
+}
+
+// Plain returns the text content of b as a single line of UTF-8 text,
+// intended for the full-text index and the search result snippets.
+// Text that is not valid UTF-8 is assumed to use the IBM PC, CP-437 encoding.
+// The ANSI escape codes, the box and line drawing characters and any repeated
+// white space are removed.
+func Plain(b []byte) string {
+	b = ansiEscape.ReplaceAll(b, nil)
+	if !utf8.Valid(b) {
+		if d, err := charmap.CodePage437.NewDecoder().Bytes(b); err == nil {
+			b = d
+		}
+	}
+	s := strings.Map(filter, string(b))
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// PlainCharset returns the text content of b decoded from the text encoding of the charset key,
+// as a single line of UTF-8 text. When the charset is empty or unknown, it returns Plain(b).
+func PlainCharset(b []byte, charset string) string {
+	d, err := encodings.Decode(b, charset)
+	if err != nil {
+		return Plain(b)
+	}
+	return Plain(d)
+}
+
+// readText reads the named file when it is a usable size and not a binary file.
+// The wide texts, such as UTF-16, are allowed to contain NUL bytes.
+func readText(name string, wide bool) ([]byte, error) {
+	st, err := os.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	if st.Size() == 0 || st.Size() > MaxSize {
+		return nil, ErrNoBody
+	}
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	const null = 0x00
+	if !wide && bytes.IndexByte(b, null) >= 0 {
+		return nil, ErrNoBody
+	}
+	return b, nil
+}
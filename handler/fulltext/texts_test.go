@@ -0,0 +1,66 @@
+package fulltext_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Defacto2/server/handler/fulltext"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/null/v8"
+	"github.com/nalgeon/be"
+)
+
+func TestIsText(t *testing.T) {
+	t.Parallel()
+	be.True(t, fulltext.IsText("FILE_ID.DIZ"))
+	be.True(t, fulltext.IsText("release.nfo"))
+	be.True(t, fulltext.IsText("docs/README"))
+	be.True(t, fulltext.IsText("Read.Me"))
+	be.True(t, !fulltext.IsText(""))
+	be.True(t, !fulltext.IsText("game.exe"))
+	be.True(t, !fulltext.IsText("nfo"))
+}
+
+func TestArchived(t *testing.T) {
+	t.Parallel()
+	be.True(t, !fulltext.Archived(""))
+	be.True(t, !fulltext.Archived("GAME.EXE\r\nGAME.DAT"))
+	be.True(t, fulltext.Archived("GAME.EXE\r\nRZR.NFO\r\nGAME.DAT"))
+}
+
+func TestPlain(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, fulltext.Plain(nil), "")
+	be.Equal(t, fulltext.Plain([]byte("  hello \r\n\r\n world  ")), "hello world")
+	be.Equal(t, fulltext.Plain([]byte("\x1b[1;33mcall\x1b[0m the bbs")), "call the bbs")
+	// CP-437 box drawing characters and a CP-437 e-acute
+	be.Equal(t, fulltext.Plain([]byte{0xc9, 0xcd, 0xbb, ' ', 'c', 'a', 'f', 0x82}), "café")
+}
+
+func TestPlainCharset(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, fulltext.PlainCharset([]byte{'c', 'a', 'f', 0x82}, ""), "café")
+	be.Equal(t, fulltext.PlainCharset([]byte{'c', 'a', 'f', 0xe9}, "iso-8859-1"), "café")
+	be.Equal(t, fulltext.PlainCharset([]byte{0xff, 0xfe, 'h', 0, 'i', 0}, "utf-16"), "hi")
+}
+
+func TestBodies(t *testing.T) {
+	t.Parallel()
+	const unid = "00000000-0000-0000-0000-000000000000"
+	tmp := t.TempDir()
+	err := os.WriteFile(filepath.Join(tmp, unid+".txt"), []byte("Courier: The Humble Guy"), 0o600)
+	be.Err(t, err, nil)
+	err = os.WriteFile(filepath.Join(tmp, unid+".diz"), []byte("Courier: The Humble Guy"), 0o600)
+	be.Err(t, err, nil)
+	ts := fulltext.Texts{Extra: tmp}
+	art := &models.File{UUID: null.StringFrom(unid)}
+	bodies := ts.Bodies(art, "")
+	be.Equal(t, len(bodies), 1)
+	be.Equal(t, bodies[fulltext.NameDiz], "Courier: The Humble Guy")
+	be.True(t, !ts.Modified(art).IsZero())
+
+	art = &models.File{UUID: null.StringFrom("missing")}
+	be.Equal(t, len(ts.Bodies(art, "")), 0)
+	be.True(t, ts.Modified(art).IsZero())
+}
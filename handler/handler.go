@@ -29,6 +29,7 @@ import (
 	"github.com/Defacto2/server/handler/htmx"
 	"github.com/Defacto2/server/internal/config"
 	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/jobs"
 	"github.com/Defacto2/server/internal/logs"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/labstack/echo-contrib/v5/pprof"
@@ -129,7 +130,9 @@ func (c *Configuration) Handler(ctx context.Context, sl *slog.Logger, db *sql.DB
 	)
 	// use middleware
 	if envConfig.Compression {
-		e.Use(middleware.Gzip())
+		e.Use(middleware.GzipWithConfig(middleware.GzipConfig{ //nolint:exhaustruct
+			Skipper: skipDownloads,
+		}))
 	}
 	if envConfig.ProdMode {
 		e.Use(middleware.Recover())
@@ -143,12 +146,17 @@ func (c *Configuration) Handler(ctx context.Context, sl *slog.Logger, db *sql.DB
 	// browser paths and routes
 	e = AppendEmbed(e, c.Public)
 	e = AppendMoved(e)
+	var queue *jobs.Queue
+	if !envConfig.ReadOnly {
+		queue = c.jobs(ctx, sl, db)
+	}
 	ch := configHtmx{
+		queue:    queue,
 		prodMode: prodMode,
 		download: dir.Directory(c.Environment.AbsDownload),
 	}
 	e = ch.append(ctx, sl, e, db)
-	e, err = c.AppendFiles(ctx, sl, e, db, c.Public)
+	e, err = c.AppendFiles(ctx, sl, e, db, c.Public, queue)
 	if err != nil {
 		logs.Fatal(ctx, sl, msg,
 			slog.String("file routes", "could not register the routes"),
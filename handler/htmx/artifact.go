@@ -7,6 +7,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"html"
 	"log/slog"
 	"net/http"
 	"net/url"
@@ -24,7 +25,9 @@ import (
 	"github.com/Defacto2/server/handler/releaser"
 	"github.com/Defacto2/server/internal/command"
 	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/jobs"
 	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/sauces"
 	"github.com/Defacto2/server/model"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v5"
@@ -118,9 +121,11 @@ func pageRefresh(c *echo.Context) *echo.Context {
 }
 
 // RecordThumb handles the htmx request for the thumbnail quality.
-func RecordThumb(ctx context.Context, sl *slog.Logger, c *echo.Context, thumb command.Thumb, dirs command.Dirs) error {
+func RecordThumb(
+	ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, thumb command.Thumb, dirs command.Dirs,
+) error {
 	const format = "artifact record thumb: %w"
-	if err := nils.Check(ctx, sl, c); err != nil {
+	if err := nils.Check(ctx, sl, c, db); err != nil {
 		return fmt.Errorf(format, err)
 	}
 	unid, err := UUID(c)
@@ -134,6 +139,9 @@ func RecordThumb(ctx context.Context, sl *slog.Logger, c *echo.Context, thumb co
 	if err != nil {
 		return badRequest(c, err)
 	}
+	if err := model.RecordAsset(ctx, db, unid, model.Thumbnail, thumb.String()); err != nil {
+		return badRequest(c, err)
+	}
 	c = pageRefresh(c)
 	return c.String(http.StatusOK,
 		`Thumb created, the browser will refresh.`)
@@ -141,10 +149,10 @@ func RecordThumb(ctx context.Context, sl *slog.Logger, c *echo.Context, thumb co
 
 // RecordThumbAlignment handles the htmx request for the thumbnail crop alignment.
 func RecordThumbAlignment(
-	ctx context.Context, sl *slog.Logger, c *echo.Context, align command.Align, dirs command.Dirs,
+	ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, align command.Align, dirs command.Dirs,
 ) error {
 	const format = "artifact record thumb alignment: %w"
-	if err := nils.Check(ctx, sl, c); err != nil {
+	if err := nils.Check(ctx, sl, c, db); err != nil {
 		return fmt.Errorf(format, err)
 	}
 	unid, err := UUID(c)
@@ -158,6 +166,9 @@ func RecordThumbAlignment(
 	if err != nil {
 		return badRequest(c, err)
 	}
+	if err := model.RecordAsset(ctx, db, unid, model.Thumbnail, "align "+align.String()); err != nil {
+		return badRequest(c, err)
+	}
 	c = pageRefresh(c)
 	return c.String(http.StatusOK,
 		`Thumb realigned, the browser will refresh.`)
@@ -165,10 +176,10 @@ func RecordThumbAlignment(
 
 // RecordImageCropper handles the htmx request for the preview image cropping.
 func RecordImageCropper(
-	ctx context.Context, sl *slog.Logger, c *echo.Context, crop command.Crop, dirs command.Dirs,
+	ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, crop command.Crop, dirs command.Dirs,
 ) error {
 	const format = "artifact record image cropper: %w"
-	if err := nils.Check(ctx, sl, c); err != nil {
+	if err := nils.Check(ctx, sl, c, db); err != nil {
 		return fmt.Errorf(format, err)
 	}
 	unid, err := UUID(c)
@@ -182,18 +193,22 @@ func RecordImageCropper(
 	if err != nil {
 		return badRequest(c, err)
 	}
+	if err := model.RecordAsset(ctx, db, unid, model.Preview, "crop "+crop.String()); err != nil {
+		return badRequest(c, err)
+	}
 	c = pageRefresh(c)
 	return c.String(http.StatusOK,
 		`Images cropped, the browser will refresh.`)
 }
 
 // recordFileProcessor is a helper function that handles the common file processing logic
-// for both image copying and binary text imaging operations.
-func recordFileProcessor(ctx context.Context, sl *slog.Logger, c *echo.Context, _ command.Dirs,
-	msg, emptyMsg, successMsg string,
-	processFunc func(context.Context, *slog.Logger, string, string) error,
+// for both image copying and binary text imaging operations. The processing is queued as
+// a background job and the returned htmx fragment polls for its completion.
+// The queued replacement of the preview and thumbnail images is logged to the audit trail.
+func recordFileProcessor(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, q *jobs.Queue,
+	msg, emptyMsg string, kind jobs.Kind,
 ) error {
-	if err := nils.Check(ctx, sl, c); err != nil {
+	if err := nils.Check(ctx, sl, c, db); err != nil {
 		return fmt.Errorf("%s: %w", msg, err)
 	}
 	unid, name, err := Path(c)
@@ -213,62 +228,46 @@ func recordFileProcessor(ctx context.Context, sl *slog.Logger, c *echo.Context,
 	if st.Size() == 0 {
 		return c.String(http.StatusOK, emptyMsg)
 	}
-	if err := processFunc(ctx, sl, src, unid); err != nil {
+	id, err := q.Enqueue(ctx, kind, src, unid)
+	if err != nil {
 		return badRequest(c, err)
 	}
-	c = pageRefresh(c)
-	return c.String(http.StatusOK, successMsg)
+	for _, asset := range []model.Asset{model.Preview, model.Thumbnail} {
+		if err := model.RecordAsset(ctx, db, unid, asset, string(kind)+" "+name); err != nil {
+			return badRequest(c, err)
+		}
+	}
+	return queued(c, id)
 }
 
 // RecordImageCopier handles the htmx request to use an image file artifact as a preview.
-func RecordImageCopier(ctx context.Context, sl *slog.Logger, c *echo.Context, dirs command.Dirs) error {
-	return recordFileProcessor(ctx, sl, c, dirs,
+func RecordImageCopier(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, q *jobs.Queue) error {
+	return recordFileProcessor(ctx, sl, c, db, q,
 		"record image copier",
 		"The file is empty and was not copied.",
-		"Images copied, the browser will refresh.",
-		dirs.PictureImager)
+		jobs.PictureImager)
 }
 
 // RecordBinTextImager handles the htmx request to use the text file artifact as a preview.
-func RecordBinTextImager(ctx context.Context, sl *slog.Logger, c *echo.Context, dirs command.Dirs) error {
-	return recordFileProcessor(ctx, sl, c, dirs,
+func RecordBinTextImager(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, q *jobs.Queue) error {
+	return recordFileProcessor(ctx, sl, c, db, q,
 		"record binary text readme imager",
 		"The file is empty and was not used.",
-		"Binary text imaged, the browser will refresh.",
-		dirs.BinTextImager)
+		jobs.BinTextImager)
 }
 
 // RecordReadmeImager handles the htmx request to use the text file artifact as a preview.
 func RecordReadmeImager(
-	ctx context.Context, sl *slog.Logger, c *echo.Context, amigaFont bool, dirs command.Dirs,
+	ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, amigaFont bool, q *jobs.Queue,
 ) error {
-	const format = "record readme imager: %w"
-	if err := nils.Check(ctx, sl, c); err != nil {
-		return fmt.Errorf(format, err)
-	}
-	unid, name, err := Path(c)
-	if err != nil {
-		return badRequest(c, err)
-	}
-	name = filepath.Clean(name)
-	tmp, err := helper.MkContent(unid)
-	if err != nil {
-		return badRequest(c, err)
-	}
-	src := filepath.Join(tmp, name)
-	st, err := os.Stat(src)
-	if err != nil {
-		return badRequest(c, err)
-	}
-	if st.Size() == 0 {
-		return c.String(http.StatusOK, "The file is empty and was not used.")
-	}
-	if err := dirs.TextImager(ctx, sl, src, unid, amigaFont); err != nil {
-		return badRequest(c, err)
+	kind := jobs.TextImager
+	if amigaFont {
+		kind = jobs.AmigaTextImager
 	}
-	c = pageRefresh(c)
-	return c.String(http.StatusOK,
-		`Text filed imaged, the browser will refresh.`)
+	return recordFileProcessor(ctx, sl, c, db, q,
+		"record readme imager",
+		"The file is empty and was not used.",
+		kind)
 }
 
 type Copy int
@@ -399,12 +398,31 @@ func RecordReadmeDisable(ctx context.Context, c *echo.Context, db *sql.DB) error
 	return c.String(http.StatusOK, successSpan)
 }
 
+// RecordEncoding handles the htmx request to override the text encoding that is used to display,
+// index and create the previews of the text files of the file artifact.
+// An empty encoding value restores the automatic detection of the encoding.
+func RecordEncoding(ctx context.Context, c *echo.Context, db *sql.DB) error {
+	const format = "record encoding: %w"
+	if err := nils.Check(ctx, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	key := c.FormValue("readme-encoding")
+	if err = model.UpdateEncoding(ctx, db, int64(id), key); err != nil {
+		return badRequest(c, err)
+	}
+	return c.String(http.StatusOK, successSpan)
+}
+
 // RecordImagePixelator handles the htmx request to pixelate both the preview and
 // thumbnails, if they are not suitable for a general audience. This also has an
 // added benefit of reducing the file sizes of both images and reducing page load.
-func RecordImagePixelator(ctx context.Context, c *echo.Context, directory ...dir.Directory) error {
+func RecordImagePixelator(ctx context.Context, c *echo.Context, db *sql.DB, directory ...dir.Directory) error {
 	const format = "record image pixelator: %w"
-	if err := nils.Check(ctx, c); err != nil {
+	if err := nils.Check(ctx, c, db); err != nil {
 		return fmt.Errorf(format, err)
 	}
 	unid, err := UUID(c)
@@ -415,6 +433,11 @@ func RecordImagePixelator(ctx context.Context, c *echo.Context, directory ...dir
 	if err := command.ImagesPixelate(ctx, unid, dirs...); err != nil {
 		return badRequest(c, err)
 	}
+	for _, asset := range []model.Asset{model.Preview, model.Thumbnail} {
+		if err := model.RecordAsset(ctx, db, unid, asset, "pixelate"); err != nil {
+			return badRequest(c, err)
+		}
+	}
 	// do not use pageRefresh as it returns an error
 	// c = pageRefresh(c)
 	return c.String(http.StatusOK,
@@ -422,26 +445,40 @@ func RecordImagePixelator(ctx context.Context, c *echo.Context, directory ...dir
 }
 
 // RecordImagesDeleter handles the request to remove the uuid named
-// image files from the directories provided.
-func RecordImagesDeleter(c *echo.Context, directories ...dir.Directory) error {
+// image files of the preview or thumbnail assets, which are logged to the audit trail.
+func RecordImagesDeleter(
+	ctx context.Context, c *echo.Context, db *sql.DB, dirs command.Dirs, assets ...model.Asset,
+) error {
 	const format = "record images deleter: %w"
-	if err := nils.Check(c); err != nil {
+	if err := nils.Check(ctx, c, db); err != nil {
 		return fmt.Errorf(format, err)
 	}
 	unid, err := UUID(c)
 	if err != nil {
 		return badRequest(c, err)
 	}
-	dirs := make([]string, len(directories))
-	for i, directory := range directories {
-		dirs[i] = directory.Path()
+	paths := make([]string, len(assets))
+	for i, asset := range assets {
+		switch asset {
+		case model.Preview:
+			paths[i] = dirs.Preview.Path()
+		case model.Thumbnail:
+			paths[i] = dirs.Thumbnail.Path()
+		default:
+			return badRequest(c, fmt.Errorf("%w: %q", model.ErrAsset, asset))
+		}
 	}
-	if err := command.ImagesDelete(unid, dirs...); err != nil {
+	if err := command.ImagesDelete(unid, paths...); err != nil {
 		if errors.Is(err, command.ErrNoImages) {
 			return c.String(http.StatusOK, err.Error())
 		}
 		return badRequest(c, err)
 	}
+	for _, asset := range assets {
+		if err := model.RecordAsset(ctx, db, unid, asset, "delete"); err != nil {
+			return badRequest(c, err)
+		}
+	}
 	// do not use pageRefresh as it returns an error
 	// c = pageRefresh(c)
 	return c.String(http.StatusOK, "Images are gone, please refresh the tab. "+
@@ -494,6 +531,60 @@ func extrasDeleter(c *echo.Context, ext string, extra dir.Directory) error {
 	return c.NoContent(http.StatusOK) //nolint:wrapcheck
 }
 
+// RecordHistory handles the request for the audit trail of the changes made to the file artifact.
+// The id parameter is the database id key of the record.
+func RecordHistory(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const format = "record history: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	return history(ctx, sl, c, db, int64(id))
+}
+
+// RecordRevert handles the patch submission to revert a single change of the audit trail.
+// The id parameter is the unique id of the audit entry and the refreshed audit trail is returned.
+func RecordRevert(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const format = "record revert: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	fileID, err := model.Revert(ctx, db, int64(id))
+	if err != nil {
+		return badRequest(c, err)
+	}
+	return history(ctx, sl, c, db, fileID)
+}
+
+func history(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, id int64) error {
+	const msg = "record history"
+	var audits model.Audits
+	if err := audits.History(ctx, db, id, model.AuditLimit); err != nil {
+		sl.Error(msg, slog.Int64("id", id), slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the audit trail query failed")
+	}
+	if len(audits) == 0 {
+		return c.HTML(http.StatusOK, "No changes have been logged for this artifact.")
+	}
+	err := c.Render(http.StatusOK, "audits", map[string]any{
+		"audits": audits,
+	})
+	if err != nil {
+		sl.Error(msg, slog.String("render", "could not render the htmx audits template"), slog.Any("error", err))
+		return c.String(http.StatusInternalServerError,
+			"cannot render the htmx audits template")
+	}
+	return nil
+}
+
 // RecordToggle handles the post submission for the file artifact record toggle.
 // The return value is either "online" or "offline" depending on the state.
 func RecordToggle(ctx context.Context, c *echo.Context, db *sql.DB, state bool) error {
@@ -831,6 +922,32 @@ func RecordDateIssuedReset(ctx context.Context, c *echo.Context, db *sql.DB, elm
 	return c.String(http.StatusOK, " "+checkMark)
 }
 
+// RecordSauce handles the post submission to use the stored SAUCE metadata of the file artifact
+// for the title, creators and date of release. The new creators are linked to the scener identities.
+func RecordSauce(ctx context.Context, c *echo.Context, db *sql.DB) error {
+	const format = "record sauce: %w"
+	if err := nils.Check(ctx, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	key := c.FormValue(editorKey)
+	id, err := strconv.Atoi(key)
+	if err != nil {
+		return badRequest(c, fmt.Errorf("%w: %w: %q", ErrKey, err, key))
+	}
+	recs, err := model.Sauces(ctx, db, int64(id))
+	if err != nil {
+		return badRequest(c, err)
+	}
+	suggest, ok := sauces.Suggest(recs...)
+	if !ok {
+		return badRequest(c, fmt.Errorf("%w: %d", ErrSauce, id))
+	}
+	if err := model.UpdateSauce(ctx, db, int64(id), suggest); err != nil {
+		return badRequest(c, err)
+	}
+	return creatorSaved(ctx, c, db, int64(id), " "+checkMark)
+}
+
 // RecordCreatorText handles the post submission for the file artifact creator text.
 func RecordCreatorText(ctx context.Context, c *echo.Context, db *sql.DB) error {
 	const format = "record creator text: %w"
@@ -847,7 +964,7 @@ func RecordCreatorText(ctx context.Context, c *echo.Context, db *sql.DB) error {
 	if err := model.UpdateCreatorText(ctx, db, int64(id), val); err != nil {
 		return badRequest(c, err)
 	}
-	return c.String(http.StatusOK, "Updated")
+	return creatorSaved(ctx, c, db, int64(id), "Updated")
 }
 
 // RecordCreatorIll handles the post submission for the file artifact creator illustrator.
@@ -866,7 +983,7 @@ func RecordCreatorIll(ctx context.Context, c *echo.Context, db *sql.DB) error {
 	if err := model.UpdateCreatorIll(ctx, db, int64(id), val); err != nil {
 		return badRequest(c, err)
 	}
-	return c.String(http.StatusOK, "Updated")
+	return creatorSaved(ctx, c, db, int64(id), "Updated")
 }
 
 // RecordCreatorProg handles the post submission for the file artifact creator programmer.
@@ -885,7 +1002,7 @@ func RecordCreatorProg(ctx context.Context, c *echo.Context, db *sql.DB) error {
 	if err := model.UpdateCreatorProg(ctx, db, int64(id), val); err != nil {
 		return badRequest(c, err)
 	}
-	return c.String(http.StatusOK, "Updated")
+	return creatorSaved(ctx, c, db, int64(id), "Updated")
 }
 
 // RecordCreatorAudio handles the post submission for the file artifact creator musician.
@@ -904,7 +1021,7 @@ func RecordCreatorAudio(ctx context.Context, c *echo.Context, db *sql.DB) error
 	if err := model.UpdateCreatorAudio(ctx, db, int64(id), val); err != nil {
 		return badRequest(c, err)
 	}
-	return c.String(http.StatusOK, "Updated")
+	return creatorSaved(ctx, c, db, int64(id), "Updated")
 }
 
 func creatorFix(s string) string {
@@ -948,7 +1065,16 @@ func RecordCreatorReset(ctx context.Context, c *echo.Context, db *sql.DB) error
 	if err := model.UpdateCreators(ctx, db, int64(id), text, ill, prog, audio); err != nil {
 		return badRequest(c, err)
 	}
-	return c.String(http.StatusOK, "Undo creators")
+	return creatorSaved(ctx, c, db, int64(id), "Undo creators")
+}
+
+// creatorSaved returns the msg response of a saved creator credit, after the credits of the artifact id
+// are linked to the scener identities, so the scener pages show the change.
+func creatorSaved(ctx context.Context, c *echo.Context, db *sql.DB, id int64, msg string) error {
+	if _, err := model.SyncArtifactIdentities(ctx, db, id); err != nil {
+		return c.String(http.StatusOK, msg+", but the scener credits could not be linked")
+	}
+	return c.String(http.StatusOK, msg)
 }
 
 // RecordYouTube handles the post submission for the file artifact YouTube watch video link.
@@ -1050,42 +1176,6 @@ func RecordGitHub(ctx context.Context, c *echo.Context, db *sql.DB) error {
 	return RecordLinks(c)
 }
 
-// RecordRelations handles the post submission for the file artifact releaser relationships.
-func RecordRelations(ctx context.Context, c *echo.Context, db *sql.DB) error {
-	const format = "record relations: %w"
-	if err := nils.Check(ctx, c, db); err != nil {
-		return fmt.Errorf(format, err)
-	}
-	key := c.FormValue(editorKey)
-	newRelations := c.FormValue("artifact-editor-relations")
-	id, err := strconv.Atoi(key)
-	if err != nil {
-		return badRequest(c, fmt.Errorf("%w: %w: %q", ErrKey, err, key))
-	}
-	if err := model.UpdateRelations(ctx, db, int64(id), newRelations); err != nil {
-		return badRequest(c, err)
-	}
-	return RecordLinks(c)
-}
-
-// RecordSites handles the post submission for the file artifact website links.
-func RecordSites(ctx context.Context, c *echo.Context, db *sql.DB) error {
-	const format = "record sites: %w"
-	if err := nils.Check(ctx, c, db); err != nil {
-		return fmt.Errorf(format, err)
-	}
-	key := c.FormValue(editorKey)
-	newSites := c.FormValue("artifact-editor-websites")
-	id, err := strconv.Atoi(key)
-	if err != nil {
-		return badRequest(c, fmt.Errorf("%w: %w: %q", ErrKey, err, key))
-	}
-	if err := model.UpdateSites(ctx, db, int64(id), newSites); err != nil {
-		return badRequest(c, err)
-	}
-	return RecordLinks(c)
-}
-
 // RecordLinks handles the post submission for a form submission to provide the
 // HTML formatted links for the "Links" section of the artifact editor.
 func RecordLinks(c *echo.Context) error {
@@ -1094,9 +1184,7 @@ func RecordLinks(c *echo.Context) error {
 	pouet := c.FormValue("artifact-editor-pouet")
 	colors16 := c.FormValue("artifact-editor-16colors")
 	github := c.FormValue("artifact-editor-github")
-	rels := c.FormValue("artifact-editor-relations")
-	sites := c.FormValue("artifact-editor-websites")
-	links := app.LinkPreviews(youtube, demozoo, pouet, colors16, github, rels, sites)
+	links := app.LinkPreviews(youtube, demozoo, pouet, colors16, github)
 	for i, link := range links {
 		links[i] = `<small><strong>Link to</strong></small> &nbsp; ` + link
 	}
@@ -1115,8 +1203,6 @@ func RecordLinksReset(ctx context.Context, c *echo.Context, db *sql.DB) error {
 	pouetVal := c.FormValue("artifact-editor-pouetval")
 	colors16 := c.FormValue("artifact-editor-16colorstval")
 	github := c.FormValue("artifact-editor-githubval")
-	rels := c.FormValue("artifact-editor-relationsval")
-	sites := c.FormValue("artifact-editor-websitesval")
 	const format = "record links reset %w: %q"
 	id, err := strconv.Atoi(key)
 	if err != nil {
@@ -1155,10 +1241,10 @@ func RecordLinksReset(ctx context.Context, c *echo.Context, db *sql.DB) error {
 	}
 
 	if err := model.UpdateLinks(ctx, db,
-		int64(id), youtube, colors16, github, rels, sites, demozooID, pouetID); err != nil {
+		int64(id), youtube, colors16, github, demozooID, pouetID); err != nil {
 		return badRequest(c, err)
 	}
-	links := app.LinkPreviews(youtube, demozooVal, pouetVal, colors16, github, rels, sites)
+	links := app.LinkPreviews(youtube, demozooVal, pouetVal, colors16, github)
 	for i, link := range links {
 		links[i] = `<small><strong>Link to</strong></small> &nbsp; ` + link
 	}
@@ -1251,6 +1337,40 @@ func RecordEmulateRunProgram(ctx context.Context, c *echo.Context, db *sql.DB) e
 		fmt.Sprintf(div, id, success, s))
 }
 
+// RecordEmulateSettings handles the patch submission for the additional DOSBox settings of a file artifact,
+// which are the cycles, joystick, keyboard layout, autoexec lines and the CD images to mount.
+// The invalid settings are returned as feedback and are not saved.
+func RecordEmulateSettings(ctx context.Context, c *echo.Context, db *sql.DB) error {
+	const format = "record emulate settings: %w"
+	if err := nils.Check(ctx, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	const feedback = `emulate-settings-feedback`
+	const invalid = `d-block invalid-feedback`
+	const success = `text-success`
+	const div = `<div id="%s" class="%s">%s</div>`
+	s := jsdos.Settings{
+		Cycles:   jsdos.Cycles(c.FormValue("emulate-cycles")),
+		Joystick: jsdos.Joystick(c.FormValue("emulate-joystick")),
+		Keyboard: jsdos.Layout(c.FormValue("emulate-keyboard")),
+		Autoexec: strings.Split(c.FormValue("emulate-autoexec"), "\n"),
+		CDImages: strings.Split(c.FormValue("emulate-cdimages"), "\n"),
+	}
+	if err := s.Normalize().Validate(); err != nil {
+		return c.String(http.StatusOK,
+			fmt.Sprintf(div, feedback, invalid, html.EscapeString(err.Error())))
+	}
+	if err := model.UpdateEmulatorSettings(ctx, db, int64(id), s); err != nil {
+		return badRequest(c, err)
+	}
+	return c.String(http.StatusOK,
+		fmt.Sprintf(div, feedback, success, `✓ Emulator settings saved`))
+}
+
 // RecordEmulateMachine handles the patch submission for the machine and graphic emulation for a file artifact.
 func RecordEmulateMachine(ctx context.Context, c *echo.Context, db *sql.DB) error {
 	const format = "record emulate machine: %w"
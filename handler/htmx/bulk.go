@@ -0,0 +1,110 @@
+package htmx
+
+// Package file bulk.go provides functions for handling the HTMX requests of the bulk editor.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+	"github.com/labstack/echo/v5"
+)
+
+// BulkEdit handles the post submission of the bulk editor form.
+// The operation is applied to every artifact that matches the filters,
+// unless the bulk-dry-run value is true, in which case the changes are only previewed.
+func BulkEdit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const format = "bulk edit: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	b, err := bulkForm(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	dryRun := c.FormValue("bulk-dry-run") != "false"
+	report, err := b.Apply(ctx, db, dryRun)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	if !dryRun && report.Op == model.BulkCreators {
+		ids := make([]int64, 0, len(report.Results))
+		for _, r := range report.Results {
+			ids = append(ids, r.ID)
+		}
+		if _, err := model.SyncArtifactIdentities(ctx, db, ids...); err != nil {
+			sl.Error("bulk edit", slog.String("credits", "could not link the scener credits"), slog.Any("error", err))
+		}
+	}
+	if !dryRun {
+		sl.Info("bulk edit",
+			slog.String("editor", model.EditorID(ctx)),
+			slog.String("op", string(report.Op)),
+			slog.Int("changed", report.Changed()))
+	}
+	err = c.Render(http.StatusOK, "bulk", map[string]any{
+		"report": report,
+	})
+	if err != nil {
+		sl.Error("bulk edit", slog.String("render", "could not render the htmx bulk template"), slog.Any("error", err))
+		return c.String(http.StatusInternalServerError,
+			"cannot render the htmx bulk template")
+	}
+	return nil
+}
+
+// bulkForm returns the bulk operation of the submitted bulk editor form.
+func bulkForm(c *echo.Context) (model.Bulk, error) {
+	year := func(name string) (int16, error) {
+		s := strings.TrimSpace(c.FormValue(name))
+		if s == "" {
+			return 0, nil
+		}
+		i, err := strconv.ParseInt(s, 10, 16)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", name, err)
+		}
+		return int16(i), nil
+	}
+	from, err := year("bulk-year-from")
+	if err != nil {
+		return model.Bulk{}, err
+	}
+	to, err := year("bulk-year-to")
+	if err != nil {
+		return model.Bulk{}, err
+	}
+	checked := func(name string) bool {
+		v, _ := strconv.ParseBool(c.FormValue(name))
+		return v
+	}
+	return model.Bulk{
+		Filter: model.Keyset{
+			Platform: strings.TrimSpace(c.FormValue("bulk-platform")),
+			Section:  strings.TrimSpace(c.FormValue("bulk-section")),
+			Releaser: strings.TrimSpace(c.FormValue("bulk-releaser")),
+			YearFrom: from,
+			YearTo:   to,
+		},
+		ForApproval: checked("bulk-for-approval"),
+		MagicErr:    checked("bulk-magic-err"),
+		Hidden:      checked("bulk-hidden"),
+		Op:          model.BulkOp(c.FormValue("bulk-op")),
+		Releasers:   c.FormValue("bulk-releasers"),
+		Platform:    c.FormValue("bulk-to-platform"),
+		Section:     c.FormValue("bulk-to-section"),
+		Year:        c.FormValue("bulk-year"),
+		Month:       c.FormValue("bulk-month"),
+		Day:         c.FormValue("bulk-day"),
+		Text:        c.FormValue("bulk-text"),
+		Ill:         c.FormValue("bulk-ill"),
+		Prog:        c.FormValue("bulk-prog"),
+		Audio:       c.FormValue("bulk-audio"),
+	}, nil
+}
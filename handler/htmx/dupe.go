@@ -0,0 +1,95 @@
+package htmx
+
+// Package file dupe.go contains the htmx handlers for the editor page of the duplicate artifacts.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Defacto2/server/internal/dupe"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+	"github.com/labstack/echo/v5"
+)
+
+// scanning is true while the fingerprints of the artifacts are being indexed.
+var scanning atomic.Bool
+
+// DuplicatesLink handles the post submission of a cluster of duplicate artifacts,
+// that saves a relation from each of the other artifacts to the first artifact of the cluster.
+// The artifact ids are a space separated list of the form value "duplicate-ids".
+func DuplicatesLink(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "duplicates link"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	ids, err := DuplicateIDs(c.FormValue("duplicate-ids"))
+	if err != nil {
+		return badRequest(c, err)
+	}
+	target := ids[0]
+	kind := model.RelationKind(c.FormValue("duplicate-kind"))
+	label := c.FormValue("duplicate-label")
+	for _, id := range ids[1:] {
+		r := model.Relation{FileID: id, TargetID: target, Kind: kind, Label: label}
+		if err := model.AddRelation(ctx, db, r); err != nil {
+			if errors.Is(err, model.ErrKey) || errors.Is(err, model.ErrRelation) {
+				return badRequest(c, err)
+			}
+			sl.Error(msg, slog.Int64("id", id), slog.Any("error", err))
+			return c.String(http.StatusServiceUnavailable,
+				"the relations could not be saved")
+		}
+	}
+	sl.Info(msg, slog.String("editor", model.EditorID(ctx)), slog.Int64("target", target),
+		slog.Int("artifacts", len(ids)))
+	return c.String(http.StatusOK, fmt.Sprintf("Linked %d artifacts to the artifact %d.", len(ids)-1, target))
+}
+
+// DuplicateIDs returns the artifact ids of the space separated list,
+// which must contain at least two ids.
+func DuplicateIDs(list string) ([]int64, error) {
+	fields := strings.Fields(list)
+	const pair = 2
+	if len(fields) < pair {
+		return nil, fmt.Errorf("%w: %q requires at least two artifacts", ErrKey, list)
+	}
+	ids := make([]int64, 0, len(fields))
+	for _, field := range fields {
+		id, err := strconv.ParseInt(field, 10, 64)
+		if err != nil || id < 1 {
+			return nil, fmt.Errorf("%w: %q", ErrKey, field)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// DuplicatesScan handles the post submission to fingerprint the new and modified artifacts
+// in the background. Only one scan can run at a time.
+func DuplicatesScan(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, src dupe.Sources) error {
+	const msg = "duplicates scan"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if !scanning.CompareAndSwap(false, true) {
+		return c.String(http.StatusOK, "A scan is already running, reload the page once it has finished.")
+	}
+	go func() {
+		defer scanning.Store(false)
+		indexed, skipped, err := model.IndexHashes(ctx, db, src)
+		if err != nil {
+			sl.Error(msg, slog.Any("error", err))
+			return
+		}
+		sl.Info(msg, slog.Int("artifacts", indexed), slog.Int("unmodified", skipped))
+	}()
+	return c.String(http.StatusOK, "The scan has started, reload the page once it has finished.")
+}
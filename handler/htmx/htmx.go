@@ -34,6 +34,7 @@ import (
 var (
 	ErrYMDFormat = errors.New("invalid ymd format")
 	ErrKey       = errors.New("numeric record key is invalid")
+	ErrSauce     = errors.New("artifact has no usable sauce metadata")
 )
 
 const (
@@ -264,6 +265,7 @@ func DeleteForever(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sq
 		return c.String(http.StatusServiceUnavailable,
 			"cannot begin a transaction")
 	}
+	defer func() { _ = tx.Rollback() }()
 	if err = model.DeleteOne(ctx, tx, key); err != nil {
 		defer func() {
 			rollback(sl, msg, key, tx)
@@ -16,6 +16,7 @@ import (
 	"github.com/Defacto2/server/handler/htmx"
 	"github.com/Defacto2/server/handler/pouet"
 	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/dupe"
 	"github.com/Defacto2/server/internal/logs"
 	"github.com/labstack/echo/v5"
 	"github.com/nalgeon/be"
@@ -120,7 +121,7 @@ func TestDataList(t *testing.T) {
 func TestTemplates(t *testing.T) {
 	t.Parallel()
 	x := htmx.Templates(embed.FS{})
-	be.True(t, len(x) == 3)
+	be.True(t, len(x) == 10)
 }
 
 func TestTemplateFuncMap(t *testing.T) {
@@ -153,11 +154,11 @@ func TestLookupSHA384(t *testing.T) {
 func TestTransfer(t *testing.T) {
 	t.Parallel()
 	ctx := context.TODO()
-	err := htmx.AdvancedSubmit(ctx, nil, newContext(), nil, "")
+	err := htmx.AdvancedSubmit(ctx, nil, newContext(), nil, "", nil)
 	be.Err(t, err)
 	wd, err := os.Getwd()
 	be.Err(t, err, nil)
-	err = htmx.AdvancedSubmit(ctx, nil, newContext(), nil, dir.Directory(wd))
+	err = htmx.AdvancedSubmit(ctx, nil, newContext(), nil, dir.Directory(wd), nil)
 	be.Err(t, err)
 }
 
@@ -171,11 +172,11 @@ func TestProdSubmit(t *testing.T) {
 func TestUploadPreview(t *testing.T) {
 	t.Parallel()
 	ctx := context.TODO()
-	err := htmx.UploadPreview(ctx, logs.Discard(), newContext(), "", "")
+	err := htmx.UploadPreview(ctx, logs.Discard(), newContext(), nil, "", "")
 	be.Err(t, err, nil)
 	wd, err := os.Getwd()
 	be.Err(t, err, nil)
-	err = htmx.UploadPreview(ctx, logs.Discard(), newContext(), dir.Directory(wd), dir.Directory(wd))
+	err = htmx.UploadPreview(ctx, logs.Discard(), newContext(), nil, dir.Directory(wd), dir.Directory(wd))
 	be.Err(t, err, nil)
 }
 
@@ -183,10 +184,122 @@ func TestUploadReplacement(t *testing.T) {
 	t.Parallel()
 	ctx := context.TODO()
 	d := logs.Discard()
-	err := htmx.UploadReplacement(ctx, d, newContext(), nil, "", "")
+	err := htmx.UploadReplacement(ctx, d, newContext(), nil, "", "", nil)
 	be.Err(t, err)
 	wd, err := os.Getwd()
 	be.Err(t, err, nil)
-	err = htmx.UploadReplacement(ctx, d, newContext(), nil, dir.Directory(wd), "")
+	err = htmx.UploadReplacement(ctx, d, newContext(), nil, dir.Directory(wd), "", nil)
 	be.Err(t, err)
 }
+
+func TestReleaserMetaValues(t *testing.T) {
+	t.Parallel()
+	_, err := htmx.ReleaserMetaValues("bad slug!", "", "", "", "", "", "", "", "")
+	be.Err(t, err)
+	_, err = htmx.ReleaserMetaValues("defacto2", "", "", "abc", "", "", "", "", "")
+	be.Err(t, err)
+	_, err = htmx.ReleaserMetaValues("defacto2", "", "", "", "", "", "", "ftp://example.com", "")
+	be.Err(t, err)
+	r, err := htmx.ReleaserMetaValues(" Defacto2 ", " Defacto2 ", "DF2, D2,", "", "10000", "", "defacto2",
+		"https://defacto2.net | Defacto2\n\nwww.defacto2.com\n", "3, 1")
+	be.Err(t, err, nil)
+	be.Equal(t, r.Slug, "defacto2")
+	be.Equal(t, r.Name, "Defacto2")
+	be.Equal(t, r.Initialisms, []string{"DF2", "D2"})
+	be.Equal(t, r.Demozoo, 10000)
+	be.Equal(t, r.Sixteen, "defacto2")
+	be.Equal(t, r.Tidbits, []int{3, 1})
+	be.Equal(t, len(r.Websites), 2)
+	be.Equal(t, r.Websites[0].Name, "Defacto2")
+	be.True(t, !r.Websites[0].NotWorking)
+	be.True(t, r.Websites[1].NotWorking)
+}
+
+func TestRenameSlug(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, htmx.RenameSlug(" acid-productions "), "acid-productions")
+	be.Equal(t, htmx.RenameSlug("ACiD Productions"), "acid-productions")
+	be.Equal(t, htmx.RenameSlug("Razor 1911 Demo & Skillion"), "razor-1911-demo-ampersand-skillion")
+}
+
+func TestScenerIdentityValues(t *testing.T) {
+	t.Parallel()
+	_, err := htmx.ScenerIdentityValues("bad slug!", "Jed", "", "", "")
+	be.Err(t, err)
+	_, err = htmx.ScenerIdentityValues("jed", "Jed", "abc", "", "")
+	be.Err(t, err)
+	_, err = htmx.ScenerIdentityValues("jed", "", "", "", "")
+	be.Err(t, err)
+	s, err := htmx.ScenerIdentityValues(" Jed ", " Jed ", "1234", "jed, jedi,", "ACiD Productions, defacto2")
+	be.Err(t, err, nil)
+	be.Equal(t, s.Slug, "jed")
+	be.Equal(t, s.Name, "Jed")
+	be.Equal(t, s.Demozoo, 1234)
+	be.Equal(t, s.Aliases, []string{"JED", "JEDI"})
+	be.Equal(t, s.Members, []string{"acid-productions", "defacto2"})
+	s, err = htmx.ScenerIdentityValues("jed", "Jed", "", "", "")
+	be.Err(t, err, nil)
+	be.Equal(t, s.Aliases, []string{"JED"})
+}
+
+func TestScenerIdentity(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+	be.Err(t, htmx.ScenerIdentitySave(ctx, nil, newContext(), nil))
+	be.Err(t, htmx.ScenerIdentityDelete(ctx, nil, newContext(), nil))
+	be.Err(t, htmx.ScenerCreditsClaim(ctx, nil, newContext(), nil))
+	be.Err(t, htmx.ScenerIdentitySync(ctx, nil, newContext(), nil))
+}
+
+func TestRecordGraph(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+	be.Err(t, htmx.RecordGraph(ctx, nil, newContext(), nil))
+	be.Err(t, htmx.RecordRelationAdd(ctx, nil, newContext(), nil))
+	be.Err(t, htmx.RecordRelationDelete(ctx, nil, newContext(), nil))
+	be.Err(t, htmx.RecordLinkAdd(ctx, nil, newContext(), nil))
+	be.Err(t, htmx.RecordLinkDelete(ctx, nil, newContext(), nil))
+}
+
+func TestKeywordValues(t *testing.T) {
+	t.Parallel()
+	k := htmx.KeywordValues("", " Sound Blaster ", " Uses the Creative Labs sound card. ")
+	be.Equal(t, k.Slug, "sound-blaster")
+	be.Equal(t, k.Name, "Sound Blaster")
+	be.Equal(t, k.Info, "Uses the Creative Labs sound card.")
+	be.Err(t, k.Valid(), nil)
+	k = htmx.KeywordValues("SB", "Sound Blaster", "")
+	be.Equal(t, k.Slug, "sb")
+	k = htmx.KeywordValues("", "", "")
+	be.Err(t, k.Valid())
+}
+
+func TestRecordKeywords(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+	be.Err(t, htmx.KeywordSave(ctx, nil, newContext(), nil))
+	be.Err(t, htmx.KeywordDelete(ctx, nil, newContext(), nil))
+	be.Err(t, htmx.RecordKeywords(ctx, nil, newContext(), nil))
+	be.Err(t, htmx.RecordKeywordAdd(ctx, nil, newContext(), nil))
+	be.Err(t, htmx.RecordKeywordDelete(ctx, nil, newContext(), nil))
+}
+
+func TestDuplicateIDs(t *testing.T) {
+	t.Parallel()
+	_, err := htmx.DuplicateIDs("")
+	be.Err(t, err, htmx.ErrKey)
+	_, err = htmx.DuplicateIDs("12")
+	be.Err(t, err, htmx.ErrKey)
+	_, err = htmx.DuplicateIDs("12 abc")
+	be.Err(t, err, htmx.ErrKey)
+	ids, err := htmx.DuplicateIDs(" 12  345 6 ")
+	be.Err(t, err, nil)
+	be.Equal(t, ids, []int64{12, 345, 6})
+}
+
+func TestDuplicates(t *testing.T) {
+	t.Parallel()
+	ctx := context.TODO()
+	be.Err(t, htmx.DuplicatesLink(ctx, nil, newContext(), nil))
+	be.Err(t, htmx.DuplicatesScan(ctx, nil, newContext(), nil, dupe.Sources{}))
+}
@@ -0,0 +1,170 @@
+package htmx
+
+// Package file identity.go contains the htmx handlers for the editor of the scener identities.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Defacto2/server/handler/releaser/name"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+	"github.com/labstack/echo/v5"
+)
+
+// ScenerIdentitySave handles the post submission of the scener identity editor form.
+// The credits of the artifacts that use the aliases are linked to the scener straight away.
+func ScenerIdentitySave(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "scener identity save"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	s, err := ScenerIdentityValues(
+		c.FormValue("scener-slug"),
+		c.FormValue("scener-name"),
+		c.FormValue("scener-demozoo"),
+		c.FormValue("scener-aliases"),
+		c.FormValue("scener-members"))
+	if err != nil {
+		return badRequest(c, err)
+	}
+	if _, err := model.SaveIdentity(ctx, db, s); err != nil {
+		if errors.Is(err, model.ErrSlug) || errors.Is(err, model.ErrIdentity) {
+			return badRequest(c, err)
+		}
+		sl.Error(msg, slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the scener identity could not be saved")
+	}
+	sync, err := model.SyncIdentities(ctx, db)
+	if err != nil {
+		sl.Error(msg, slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the scener identity was saved but the credits could not be linked")
+	}
+	sl.Info(msg, slog.String("editor", model.EditorID(ctx)), slog.String("scener", s.Slug))
+	return c.String(http.StatusOK,
+		fmt.Sprintf("The scener identity was saved, %d credits were linked.", sync.Linked))
+}
+
+// ScenerIdentityDelete handles the htmx request to remove the scener identity of the slug.
+// It is used to remove a scener after its aliases were given to another scener.
+func ScenerIdentityDelete(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "scener identity delete"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	slug := c.Param("slug")
+	if !name.Path(slug).Valid() {
+		return badRequest(c, fmt.Errorf("%w: %q", model.ErrSlug, slug))
+	}
+	if err := model.DeleteIdentity(ctx, db, slug); err != nil {
+		sl.Error(msg, slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the scener identity could not be removed")
+	}
+	sl.Info(msg, slog.String("editor", model.EditorID(ctx)), slog.String("scener", slug))
+	return c.String(http.StatusOK, "The scener identity of "+slug+" was removed.")
+}
+
+// ScenerIdentityValues returns the scener identity of the editor form values.
+// The aliases are a comma separated list of the credited names, and the members are
+// a comma separated list of the slugs of the groups that the scener was a member of.
+func ScenerIdentityValues(slug, styled, demozoo, aliases, members string) (model.Identity, error) {
+	s := model.Identity{
+		Slug: strings.ToLower(strings.TrimSpace(slug)),
+		Name: strings.TrimSpace(styled),
+	}
+	if demozoo = strings.TrimSpace(demozoo); demozoo != "" {
+		i, err := strconv.Atoi(demozoo)
+		if err != nil || i < 0 {
+			return model.Identity{}, fmt.Errorf("%w: the demozoo id %q is not a positive number", ErrKey, demozoo)
+		}
+		s.Demozoo = i
+	}
+	for alias := range strings.SplitSeq(aliases, ",") {
+		if alias = strings.TrimSpace(alias); alias != "" {
+			s.Aliases = append(s.Aliases, strings.ToUpper(alias))
+		}
+	}
+	if s.Name != "" && len(s.Aliases) == 0 {
+		s.Aliases = append(s.Aliases, strings.ToUpper(s.Name))
+	}
+	for member := range strings.SplitSeq(members, ",") {
+		if member = strings.ToLower(strings.TrimSpace(member)); member != "" {
+			s.Members = append(s.Members, RenameSlug(member))
+		}
+	}
+	if err := s.Valid(); err != nil {
+		return model.Identity{}, err
+	}
+	return s, nil
+}
+
+// ScenerCreditsClaim handles the post submission of the artifact ids that are claimed by a scener.
+// It is used to tell apart the artifacts of two sceners that share the same alias.
+func ScenerCreditsClaim(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "scener credits claim"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	slug := strings.ToLower(strings.TrimSpace(c.FormValue("scener-slug")))
+	var ids []int64
+	for s := range strings.SplitSeq(c.FormValue("scener-claim-ids"), ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || id < 1 {
+			return badRequest(c, fmt.Errorf("%w: the artifact id %q is not a positive number", ErrKey, s))
+		}
+		ids = append(ids, id)
+	}
+	s, err := model.OneIdentity(ctx, db, slug)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return badRequest(c, fmt.Errorf("%w: %q is not a scener identity", model.ErrIdentity, slug))
+		}
+		sl.Error(msg, slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the scener identity could not be found")
+	}
+	i, err := model.ClaimCredits(ctx, db, s.ID, ids...)
+	if err != nil {
+		if errors.Is(err, model.ErrKey) {
+			return badRequest(c, err)
+		}
+		sl.Error(msg, slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the credits could not be claimed")
+	}
+	sl.Info(msg, slog.String("editor", model.EditorID(ctx)), slog.String("scener", slug),
+		slog.Int64("claimed", i))
+	return c.String(http.StatusOK, fmt.Sprintf("%d credits were claimed by %s.", i, s.Name))
+}
+
+// ScenerIdentitySync handles the htmx request to create the scener identities of the unknown
+// credited names and to link the credits of the artifacts to the sceners.
+func ScenerIdentitySync(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "scener identity sync"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	sync, err := model.SyncIdentities(ctx, db)
+	if err != nil {
+		sl.Error(msg, slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the scener identities could not be synchronized")
+	}
+	sl.Info(msg, slog.String("editor", model.EditorID(ctx)))
+	return c.String(http.StatusOK,
+		fmt.Sprintf("%d new sceners, %d credits were linked and %d credits were removed, "+
+			"%d names share the slug of another scener and must be claimed.",
+			sync.Sceners, sync.Linked, sync.Removed, sync.Clashes))
+}
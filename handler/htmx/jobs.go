@@ -0,0 +1,119 @@
+package htmx
+
+// Package file jobs.go contains the htmx handlers for the queue of background jobs.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
+	"net/http"
+
+	"github.com/Defacto2/server/internal/jobs"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+	"github.com/labstack/echo/v5"
+)
+
+// queued returns the htmx fragment of a queued job that polls for the completion of the job.
+func queued(c *echo.Context, id int64) error {
+	const format = `<span hx-get="/editor/jobs/poll/%d" hx-trigger="every 2s" hx-swap="outerHTML">` +
+		`Job %d is queued, please wait.</span>`
+	return c.HTML(http.StatusOK, fmt.Sprintf(format, id, id))
+}
+
+// JobPoll handles the htmx poll of a queued job.
+// While the job is unfinished the poll is returned, otherwise the browser is refreshed
+// when the job is done, or the reason of the failure is returned.
+func JobPoll(ctx context.Context, c *echo.Context, db *sql.DB) error {
+	const format = "job poll: %w"
+	if err := nils.Check(ctx, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	j, err := model.OneJob(ctx, db, int64(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.String(http.StatusNotFound, "The job does not exist.")
+		}
+		return c.String(http.StatusServiceUnavailable, "The job query failed.")
+	}
+	switch j.Status {
+	case model.JobDone:
+		c = pageRefresh(c)
+		return c.String(http.StatusOK, "Job completed, the browser will refresh.")
+	case model.JobFailed:
+		return c.HTML(http.StatusOK, `<span class="text-danger">Job failed: `+
+			html.EscapeString(j.LastError.String)+`</span>`)
+	case model.JobCancelled:
+		return c.String(http.StatusOK, "Job was cancelled.")
+	case model.JobPending, model.JobRunning:
+	}
+	if j.Attempts > 1 || j.LastError.Valid {
+		const format = `<span hx-get="/editor/jobs/poll/%d" hx-trigger="every 2s" hx-swap="outerHTML">` +
+			`Job %d is %s, attempt %d, the previous attempt failed: %s</span>`
+		return c.HTML(http.StatusOK, fmt.Sprintf(format, j.ID, j.ID, j.Status, j.Attempts,
+			html.EscapeString(j.LastError.String)))
+	}
+	return queued(c, j.ID)
+}
+
+// JobList handles the htmx request for the pending, running and failed jobs of the queue.
+func JobList(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "job list"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	var list model.Jobs
+	if err := list.Queue(ctx, db); err != nil {
+		sl.Error(msg, slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the job queue query failed")
+	}
+	err := c.Render(http.StatusOK, "jobs", map[string]any{
+		"jobs": list,
+	})
+	if err != nil {
+		sl.Error(msg, slog.String("render", "could not render the htmx jobs template"), slog.Any("error", err))
+		return c.String(http.StatusInternalServerError,
+			"cannot render the htmx jobs template")
+	}
+	return nil
+}
+
+// JobCancel handles the htmx request to cancel a pending or running job, the refreshed list of jobs is returned.
+func JobCancel(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, q *jobs.Queue) error {
+	const format = "job cancel: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	if err := q.Cancel(ctx, int64(id)); err != nil {
+		return badRequest(c, err)
+	}
+	return JobList(ctx, sl, c, db)
+}
+
+// JobRequeue handles the htmx request to run a failed or cancelled job again, the refreshed list of jobs is returned.
+func JobRequeue(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, q *jobs.Queue) error {
+	const format = "job requeue: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	if err := q.Requeue(ctx, int64(id)); err != nil {
+		return badRequest(c, err)
+	}
+	return JobList(ctx, sl, c, db)
+}
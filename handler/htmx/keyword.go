@@ -0,0 +1,159 @@
+package htmx
+
+// Package file keyword.go contains the htmx handlers for the editor of the keywords vocabulary
+// and of the keywords that tag an artifact.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+	"github.com/labstack/echo/v5"
+)
+
+// KeywordSave handles the post submission of the keywords vocabulary editor form.
+// The slug is created from the name when it is left empty.
+func KeywordSave(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "keyword save"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	k := KeywordValues(
+		c.FormValue("keyword-slug"),
+		c.FormValue("keyword-name"),
+		c.FormValue("keyword-info"))
+	if err := k.Valid(); err != nil {
+		return badRequest(c, err)
+	}
+	if err := model.SaveKeyword(ctx, db, k); err != nil {
+		sl.Error(msg, slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the keyword could not be saved")
+	}
+	sl.Info(msg, slog.String("editor", model.EditorID(ctx)), slog.String("keyword", k.Slug))
+	return c.String(http.StatusOK, "The keyword "+k.Slug+" was saved.")
+}
+
+// KeywordDelete handles the htmx request to remove the keyword of the slug from the vocabulary
+// and from all the artifacts that are tagged with it.
+func KeywordDelete(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "keyword delete"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	slug := c.Param("slug")
+	if slug == "" || model.KeywordSlug(slug) != slug {
+		return badRequest(c, fmt.Errorf("%w: %q", model.ErrKeyword, slug))
+	}
+	if err := model.DeleteKeyword(ctx, db, slug); err != nil {
+		sl.Error(msg, slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the keyword could not be removed")
+	}
+	sl.Info(msg, slog.String("editor", model.EditorID(ctx)), slog.String("keyword", slug))
+	return c.String(http.StatusOK, "The keyword "+slug+" was removed.")
+}
+
+// KeywordValues returns the keyword of the editor form values.
+func KeywordValues(slug, name, info string) model.Keyword {
+	k := model.Keyword{
+		Slug: strings.ToLower(strings.TrimSpace(slug)),
+		Name: strings.TrimSpace(name),
+		Info: strings.TrimSpace(info),
+	}
+	if k.Slug == "" {
+		k.Slug = model.KeywordSlug(k.Name)
+	}
+	return k
+}
+
+// RecordKeywords handles the htmx request to list the keywords of an artifact.
+func RecordKeywords(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const format = "record keywords: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	return keywords(ctx, sl, c, db, int64(id))
+}
+
+// RecordKeywordAdd handles the post submission of a keyword to tag the artifact.
+// The keyword is either the slug or the name of a keyword in the vocabulary.
+func RecordKeywordAdd(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "record keyword add"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	slug := model.KeywordSlug(c.FormValue("artifact-editor-keyword"))
+	if slug == "" {
+		return badRequest(c, fmt.Errorf("%w: the keyword is empty", model.ErrKeyword))
+	}
+	if err := model.AddFileKeyword(ctx, db, int64(id), slug); err != nil {
+		if errors.Is(err, model.ErrKey) || errors.Is(err, model.ErrKeyword) {
+			return badRequest(c, err)
+		}
+		sl.Error(msg, slog.Int("id", id), slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the keyword could not be added")
+	}
+	return keywords(ctx, sl, c, db, int64(id))
+}
+
+// RecordKeywordDelete handles the htmx request to remove a keyword from the artifact.
+func RecordKeywordDelete(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "record keyword delete"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	slug := c.Param("slug")
+	if err := model.RemoveFileKeyword(ctx, db, int64(id), slug); err != nil {
+		sl.Error(msg, slog.Int("id", id), slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the keyword could not be removed")
+	}
+	return keywords(ctx, sl, c, db, int64(id))
+}
+
+func keywords(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, id int64) error {
+	const msg = "record keywords"
+	tagged, err := model.FileKeywords(ctx, db, id)
+	if err != nil {
+		sl.Error(msg, slog.Int64("id", id), slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the keywords query failed")
+	}
+	vocabulary, err := model.Keywords(ctx, db)
+	if err != nil {
+		sl.Error(msg, slog.Int64("id", id), slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the keywords vocabulary query failed")
+	}
+	err = c.Render(http.StatusOK, "keywords", map[string]any{
+		"id":         id,
+		"keywords":   tagged,
+		"vocabulary": vocabulary,
+	})
+	if err != nil {
+		sl.Error(msg, slog.String("render", "could not render the htmx keywords template"), slog.Any("error", err))
+		return c.String(http.StatusInternalServerError,
+			"cannot render the htmx keywords template")
+	}
+	return nil
+}
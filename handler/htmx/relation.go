@@ -0,0 +1,165 @@
+package htmx
+
+// Package file relation.go contains the htmx handlers for the editor of the artifact relations and links.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+	"github.com/labstack/echo/v5"
+)
+
+// RecordGraph handles the htmx request to list the relations and external links of an artifact.
+func RecordGraph(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const format = "record graph: %w"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf(format, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	return graph(ctx, sl, c, db, int64(id))
+}
+
+// RecordRelationAdd handles the post submission of a new relation from the artifact to a target artifact.
+// The target is either the obfuscated key or the URL of the target artifact page.
+func RecordRelationAdd(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "record relation add"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	target := c.FormValue("artifact-editor-relation-target")
+	r := model.Relation{
+		FileID:   int64(id),
+		TargetID: model.RelationKey(target),
+		Kind:     model.RelationKind(c.FormValue("artifact-editor-relation-kind")),
+		Label:    c.FormValue("artifact-editor-relation-label"),
+	}
+	if r.TargetID < 1 {
+		return badRequest(c, fmt.Errorf("%w: %q is not an artifact key or URL", ErrKey, target))
+	}
+	if err := model.AddRelation(ctx, db, r); err != nil {
+		if errors.Is(err, model.ErrKey) || errors.Is(err, model.ErrRelation) {
+			return badRequest(c, err)
+		}
+		sl.Error(msg, slog.Int64("id", r.FileID), slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the relation could not be saved")
+	}
+	return graph(ctx, sl, c, db, r.FileID)
+}
+
+// RecordRelationDelete handles the htmx request to remove a relation from or to the artifact.
+func RecordRelationDelete(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "record relation delete"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	id, rid, err := graphIDs(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	if err := model.DeleteRelation(ctx, db, id, rid); err != nil {
+		sl.Error(msg, slog.Int64("id", id), slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the relation could not be removed")
+	}
+	return graph(ctx, sl, c, db, id)
+}
+
+// RecordLinkAdd handles the post submission of a new external link of the artifact.
+func RecordLinkAdd(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "record link add"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	id, err := ID(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	l := model.Link{
+		FileID: int64(id),
+		Kind:   model.LinkKind(c.FormValue("artifact-editor-link-kind")),
+		Label:  c.FormValue("artifact-editor-link-label"),
+		URL:    c.FormValue("artifact-editor-link-url"),
+	}
+	if err := model.AddLink(ctx, db, l); err != nil {
+		if errors.Is(err, model.ErrKey) || errors.Is(err, model.ErrLink) {
+			return badRequest(c, err)
+		}
+		sl.Error(msg, slog.Int64("id", l.FileID), slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the link could not be saved")
+	}
+	return graph(ctx, sl, c, db, l.FileID)
+}
+
+// RecordLinkDelete handles the htmx request to remove an external link of the artifact.
+func RecordLinkDelete(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "record link delete"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	id, rid, err := graphIDs(c)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	if err := model.DeleteLink(ctx, db, id, rid); err != nil {
+		sl.Error(msg, slog.Int64("id", id), slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the link could not be removed")
+	}
+	return graph(ctx, sl, c, db, id)
+}
+
+// graphIDs returns the artifact id and the relation or link id of the path parameters.
+func graphIDs(c *echo.Context) (int64, int64, error) {
+	id, err := ID(c)
+	if err != nil {
+		return 0, 0, err
+	}
+	rid, err := echo.PathParam[int64](c, "rid")
+	if err != nil {
+		const format = `%w: "%w"`
+		return 0, 0, fmt.Errorf(format, ErrKey, err)
+	}
+	return int64(id), rid, nil
+}
+
+func graph(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, id int64) error {
+	const msg = "record graph"
+	rels, err := model.Relations(ctx, db, id, true)
+	if err != nil {
+		sl.Error(msg, slog.Int64("id", id), slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the relations query failed")
+	}
+	links, err := model.Links(ctx, db, id)
+	if err != nil {
+		sl.Error(msg, slog.Int64("id", id), slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the links query failed")
+	}
+	err = c.Render(http.StatusOK, "graph", map[string]any{
+		"id":        id,
+		"relations": rels,
+		"links":     links,
+	})
+	if err != nil {
+		sl.Error(msg, slog.String("render", "could not render the htmx graph template"), slog.Any("error", err))
+		return c.String(http.StatusInternalServerError,
+			"cannot render the htmx graph template")
+	}
+	return nil
+}
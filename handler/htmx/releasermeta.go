@@ -0,0 +1,227 @@
+package htmx
+
+// Package file releasermeta.go contains the htmx handlers for the editor managed metadata of the releasers.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Defacto2/server/handler/releaser/meta"
+	"github.com/Defacto2/server/handler/releaser/name"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+	"github.com/labstack/echo/v5"
+)
+
+// ReleaserMetaLimit is the maximum number of releasers returned by the listing of the metadata.
+const ReleaserMetaLimit = 100
+
+// ReleaserMetaList handles the htmx request for the metadata of the releasers.
+// The releasers are filtered by the query value, which matches the slugs, names and initialisms.
+func ReleaserMetaList(sl *slog.Logger, c *echo.Context) error {
+	const msg = "releaser meta list"
+	if err := nils.Check(sl, c); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if !meta.Loaded() {
+		return c.String(http.StatusServiceUnavailable,
+			"the releasers metadata is not loaded, the compiled lists are in use")
+	}
+	query := strings.ToLower(strings.TrimSpace(c.QueryParam("releaser-meta-query")))
+	all := meta.All()
+	rels := make([]meta.Releaser, 0, ReleaserMetaLimit)
+	for _, r := range all {
+		if len(rels) == ReleaserMetaLimit {
+			break
+		}
+		if query == "" || releaserMetaMatch(r, query) {
+			rels = append(rels, r)
+		}
+	}
+	err := c.Render(http.StatusOK, "releasermetas", map[string]any{
+		"releasers": rels,
+		"total":     len(all),
+		"limit":     ReleaserMetaLimit,
+	})
+	if err != nil {
+		sl.Error(msg, slog.String("render", "could not render the htmx releasermetas template"), slog.Any("error", err))
+		return c.String(http.StatusInternalServerError,
+			"cannot render the htmx releasermetas template")
+	}
+	return nil
+}
+
+// releaserMetaMatch returns true if the lowercase query is found in the slug, name or initialisms of the releaser.
+func releaserMetaMatch(r meta.Releaser, query string) bool {
+	if strings.Contains(r.Slug, query) || strings.Contains(strings.ToLower(r.Name), query) {
+		return true
+	}
+	for _, s := range r.Initialisms {
+		if strings.Contains(strings.ToLower(s), query) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleaserMetaForm handles the htmx request for the editor form of the releaser slug metadata.
+// A slug without any metadata returns an empty form.
+func ReleaserMetaForm(sl *slog.Logger, c *echo.Context) error {
+	const msg = "releaser meta form"
+	if err := nils.Check(sl, c); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	slug := strings.ToLower(strings.TrimSpace(c.Param("slug")))
+	if slug == "" {
+		slug = strings.ToLower(strings.TrimSpace(c.QueryParam("releaser-meta-slug")))
+	}
+	if !name.Path(slug).Valid() {
+		return badRequest(c, fmt.Errorf("%w: %q", model.ErrSlug, slug))
+	}
+	r, _ := meta.Find(slug)
+	return releaserMetaRender(sl, c, r, "")
+}
+
+// ReleaserMetaSave handles the post submission of the releaser metadata editor form.
+// The metadata is saved to the database and then replaces the metadata in the cache.
+func ReleaserMetaSave(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "releaser meta save"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	r, err := ReleaserMetaValues(
+		c.FormValue("releaser-meta-slug"),
+		c.FormValue("releaser-meta-name"),
+		c.FormValue("releaser-meta-initialisms"),
+		c.FormValue("releaser-meta-csdb"),
+		c.FormValue("releaser-meta-demozoo"),
+		c.FormValue("releaser-meta-janeway"),
+		c.FormValue("releaser-meta-sixteen"),
+		c.FormValue("releaser-meta-websites"),
+		c.FormValue("releaser-meta-tidbits"))
+	if err != nil {
+		return badRequest(c, err)
+	}
+	if err := model.UpsertReleaserMeta(ctx, db, r); err != nil {
+		sl.Error(msg, slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the releaser metadata could not be saved")
+	}
+	meta.Set(r)
+	sl.Info(msg, slog.String("editor", model.EditorID(ctx)), slog.String("releaser", r.Slug))
+	return releaserMetaRender(sl, c, r, "The metadata was saved.")
+}
+
+// ReleaserMetaDelete handles the htmx request to remove the metadata of the releaser slug.
+func ReleaserMetaDelete(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "releaser meta delete"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	slug := c.Param("slug")
+	if !name.Path(slug).Valid() {
+		return badRequest(c, fmt.Errorf("%w: %q", model.ErrSlug, slug))
+	}
+	if err := model.DeleteReleaserMeta(ctx, db, slug); err != nil {
+		sl.Error(msg, slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the releaser metadata could not be removed")
+	}
+	meta.Delete(slug)
+	sl.Info(msg, slog.String("editor", model.EditorID(ctx)), slog.String("releaser", slug))
+	return c.String(http.StatusOK, "The metadata of "+slug+" was removed.")
+}
+
+// releaserMetaRender renders the editor form of the releaser metadata with an optional status message.
+func releaserMetaRender(sl *slog.Logger, c *echo.Context, r meta.Releaser, status string) error {
+	const msg = "releaser meta"
+	sites := make([]string, 0, len(r.Websites))
+	for _, w := range r.Websites {
+		if w.Name == "" {
+			sites = append(sites, w.URL)
+			continue
+		}
+		sites = append(sites, w.URL+" | "+w.Name)
+	}
+	tidbits := make([]string, 0, len(r.Tidbits))
+	for _, id := range r.Tidbits {
+		tidbits = append(tidbits, strconv.Itoa(id))
+	}
+	err := c.Render(http.StatusOK, "releasermeta", map[string]any{
+		"releaser":    r,
+		"initialisms": strings.Join(r.Initialisms, ", "),
+		"websites":    strings.Join(sites, "\n"),
+		"tidbits":     strings.Join(tidbits, ", "),
+		"status":      status,
+	})
+	if err != nil {
+		sl.Error(msg, slog.String("render", "could not render the htmx releasermeta template"), slog.Any("error", err))
+		return c.String(http.StatusInternalServerError,
+			"cannot render the htmx releasermeta template")
+	}
+	return nil
+}
+
+// ReleaserMetaValues returns the releaser metadata of the editor form values.
+// The initialisms and tidbits are comma separated lists, while the websites are
+// listed one per line using a URL, an optional pipe separator and the name of the site.
+// A website URL without a protocol is a site that is no longer working.
+func ReleaserMetaValues(slug, styled, inits, csdb, demozoo, janeway, sixteen, sites, tidbits string) (
+	meta.Releaser, error,
+) {
+	r := meta.Releaser{
+		Slug:    strings.ToLower(strings.TrimSpace(slug)),
+		Name:    strings.TrimSpace(styled),
+		Sixteen: strings.TrimSpace(sixteen),
+	}
+	if !name.Path(r.Slug).Valid() {
+		return meta.Releaser{}, fmt.Errorf("%w: %q", model.ErrSlug, r.Slug)
+	}
+	for s := range strings.SplitSeq(inits, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			r.Initialisms = append(r.Initialisms, s)
+		}
+	}
+	var err error
+	number := func(field, s string) int {
+		s = strings.TrimSpace(s)
+		if s == "" || err != nil {
+			return 0
+		}
+		i, e := strconv.Atoi(s)
+		if e != nil || i < 0 {
+			err = fmt.Errorf("%w: the %s id %q is not a positive number", ErrKey, field, s)
+			return 0
+		}
+		return i
+	}
+	r.CSDb = number("csdb", csdb)
+	r.Demozoo = number("demozoo", demozoo)
+	r.Janeway = number("janeway", janeway)
+	for s := range strings.SplitSeq(tidbits, ",") {
+		if id := number("tidbit", s); id > 0 {
+			r.Tidbits = append(r.Tidbits, id)
+		}
+	}
+	if err != nil {
+		return meta.Releaser{}, err
+	}
+	for line := range strings.SplitSeq(sites, "\n") {
+		url, named, _ := strings.Cut(line, "|")
+		url, named = strings.TrimSpace(url), strings.TrimSpace(named)
+		if url == "" {
+			continue
+		}
+		working := strings.HasPrefix(url, "https://") || strings.HasPrefix(url, "http://")
+		if !working && strings.Contains(url, "://") {
+			return meta.Releaser{}, fmt.Errorf("%w: the website %q uses an unknown protocol", ErrKey, url)
+		}
+		r.Websites = append(r.Websites, meta.Website{URL: url, Name: named, NotWorking: !working})
+	}
+	return r, nil
+}
@@ -0,0 +1,99 @@
+package htmx
+
+// Package file rename.go contains the htmx handlers for the releaser rename and merge operation.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Defacto2/server/handler/releaser"
+	"github.com/Defacto2/server/handler/releaser/meta"
+	"github.com/Defacto2/server/handler/releaser/name"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+	"github.com/labstack/echo/v5"
+)
+
+// ReleaserRename handles the post submission of the releaser rename form.
+// The rename is applied to every artifact of the source releaser, unless the rename-dry-run value
+// is true, in which case the changes are only previewed. Once applied, the source releaser is
+// redirected to the target releaser and the cache of the releaser metadata is reloaded.
+func ReleaserRename(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "releaser rename"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	rename := model.ReleaserRename{
+		From: RenameSlug(c.FormValue("rename-from")),
+		To:   RenameSlug(c.FormValue("rename-to")),
+	}
+	dryRun := c.FormValue("rename-dry-run") != "false"
+	report, err := rename.Apply(ctx, db, dryRun)
+	if err != nil {
+		return badRequest(c, err)
+	}
+	if !dryRun {
+		meta.SetAlias(rename.From, rename.To)
+		if meta.Loaded() {
+			rels, err := model.ReleaserMetas(ctx, db)
+			if err != nil {
+				sl.Error(msg, slog.String("cache", "could not reload the releaser metadata"), slog.Any("error", err))
+			} else {
+				meta.Load(rels...)
+			}
+		}
+		sl.Info(msg,
+			slog.String("editor", model.EditorID(ctx)),
+			slog.String("from", rename.From),
+			slog.String("to", rename.To),
+			slog.Int("changed", report.Changed()))
+	}
+	err = c.Render(http.StatusOK, "bulk", map[string]any{
+		"report": report,
+	})
+	if err != nil {
+		sl.Error(msg, slog.String("render", "could not render the htmx bulk template"), slog.Any("error", err))
+		return c.String(http.StatusInternalServerError,
+			"cannot render the htmx bulk template")
+	}
+	return nil
+}
+
+// RenameSlug returns the URL path slug of the releaser value,
+// which is either a slug or the name of the releaser.
+//
+// Example:
+//
+//	RenameSlug("acid-productions") = "acid-productions"
+//	RenameSlug("ACiD Productions") = "acid-productions"
+func RenameSlug(s string) string {
+	s = strings.TrimSpace(s)
+	if name.Path(s).Valid() {
+		return s
+	}
+	return releaser.Obfuscate(s)
+}
+
+// ReleaserAliasDelete handles the htmx request to remove a releaser alias, so it no longer redirects.
+func ReleaserAliasDelete(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB) error {
+	const msg = "releaser alias delete"
+	if err := nils.Check(ctx, sl, c, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	alias := c.Param("slug")
+	if !name.Path(alias).Valid() {
+		return badRequest(c, fmt.Errorf("%w: %q", model.ErrSlug, alias))
+	}
+	if err := model.DeleteReleaserAlias(ctx, db, alias); err != nil {
+		sl.Error(msg, slog.Any("error", err))
+		return c.String(http.StatusServiceUnavailable,
+			"the releaser alias could not be removed")
+	}
+	meta.DeleteAlias(alias)
+	sl.Info(msg, slog.String("editor", model.EditorID(ctx)), slog.String("alias", alias))
+	return c.String(http.StatusOK, "")
+}
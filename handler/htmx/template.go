@@ -38,9 +38,72 @@ func Templates(fs embed.FS) map[string]*template.Template {
 	t["searchids"] = ids(fs)
 	t["searchreleasers"] = releasers(fs)
 	t["datalistreleasers"] = datalistReleasers(fs)
+	t["audits"] = auditTrail(fs)
+	t["graph"] = artifactGraph(fs)
+	t["keywords"] = artifactKeywords(fs)
+	t["bulk"] = bulkReport(fs)
+	t["jobs"] = jobList(fs)
+	t["releasermeta"] = releaserMeta(fs)
+	t["releasermetas"] = releaserMetas(fs)
 	return t
 }
 
+func auditTrail(fs embed.FS) *template.Template {
+	if emptyFS(fs) {
+		return nil
+	}
+	return template.Must(template.New("").Funcs(TemplateFuncMap()).ParseFS(fs,
+		GlobTo("layout.tmpl"), GlobTo("audits.tmpl")))
+}
+
+func artifactGraph(fs embed.FS) *template.Template {
+	if emptyFS(fs) {
+		return nil
+	}
+	return template.Must(template.New("").Funcs(TemplateFuncMap()).ParseFS(fs,
+		GlobTo("layout.tmpl"), GlobTo("graph.tmpl")))
+}
+
+func artifactKeywords(fs embed.FS) *template.Template {
+	if emptyFS(fs) {
+		return nil
+	}
+	return template.Must(template.New("").Funcs(TemplateFuncMap()).ParseFS(fs,
+		GlobTo("layout.tmpl"), GlobTo("keywords.tmpl")))
+}
+
+func bulkReport(fs embed.FS) *template.Template {
+	if emptyFS(fs) {
+		return nil
+	}
+	return template.Must(template.New("").Funcs(TemplateFuncMap()).ParseFS(fs,
+		GlobTo("layout.tmpl"), GlobTo("bulk.tmpl")))
+}
+
+func jobList(fs embed.FS) *template.Template {
+	if emptyFS(fs) {
+		return nil
+	}
+	return template.Must(template.New("").Funcs(TemplateFuncMap()).ParseFS(fs,
+		GlobTo("layout.tmpl"), GlobTo("jobs.tmpl")))
+}
+
+func releaserMeta(fs embed.FS) *template.Template {
+	if emptyFS(fs) {
+		return nil
+	}
+	return template.Must(template.New("").Funcs(TemplateFuncMap()).ParseFS(fs,
+		GlobTo("layout.tmpl"), GlobTo("releasermeta.tmpl")))
+}
+
+func releaserMetas(fs embed.FS) *template.Template {
+	if emptyFS(fs) {
+		return nil
+	}
+	return template.Must(template.New("").Funcs(TemplateFuncMap()).ParseFS(fs,
+		GlobTo("layout.tmpl"), GlobTo("releasermetas.tmpl")))
+}
+
 func ids(fs embed.FS) *template.Template {
 	if emptyFS(fs) {
 		return nil
@@ -32,6 +32,7 @@ import (
 	"github.com/Defacto2/server/handler/sess"
 	"github.com/Defacto2/server/internal/command"
 	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/jobs"
 	"github.com/Defacto2/server/internal/logs"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/Defacto2/server/internal/tags"
@@ -107,42 +108,54 @@ func LookupSHA384(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql
 }
 
 // ImageSubmit is a handler for the /uploader/image route.
-func ImageSubmit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, download dir.Directory) error {
+func ImageSubmit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, download dir.Directory,
+	q *jobs.Queue,
+) error {
 	const key = "uploader-image"
 	c.Set(key+"-operating-system", tags.Image.String())
-	return transfer(ctx, sl, c, db, key, download)
+	return transfer(ctx, sl, c, db, key, download, q)
 }
 
 // IntroSubmit is a handler for the /uploader/intro route.
-func IntroSubmit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, download dir.Directory) error {
+func IntroSubmit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, download dir.Directory,
+	q *jobs.Queue,
+) error {
 	const key = "uploader-intro"
 	c.Set(key+"-category", tags.Intro.String())
-	return transfer(ctx, sl, c, db, key, download)
+	return transfer(ctx, sl, c, db, key, download, q)
 }
 
 // MagazineSubmit is a handler for the /uploader/magazine route.
-func MagazineSubmit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, download dir.Directory) error {
+func MagazineSubmit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, download dir.Directory,
+	q *jobs.Queue,
+) error {
 	const key = "uploader-magazine"
 	c.Set(key+"-category", tags.Mag.String())
-	return transfer(ctx, sl, c, db, key, download)
+	return transfer(ctx, sl, c, db, key, download, q)
 }
 
 // TextSubmit is a handler for the /uploader/text route.
-func TextSubmit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, download dir.Directory) error {
+func TextSubmit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, download dir.Directory,
+	q *jobs.Queue,
+) error {
 	const key = "uploader-text"
-	return transfer(ctx, sl, c, db, key, download)
+	return transfer(ctx, sl, c, db, key, download, q)
 }
 
 // TrainerSubmit is a handler for the /uploader/trainer route.
-func TrainerSubmit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, download dir.Directory) error {
+func TrainerSubmit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, download dir.Directory,
+	q *jobs.Queue,
+) error {
 	const key = "uploader-trainer"
-	return transfer(ctx, sl, c, db, key, download)
+	return transfer(ctx, sl, c, db, key, download, q)
 }
 
 // AdvancedSubmit is a handler for the /uploader/advanced route.
-func AdvancedSubmit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, download dir.Directory) error {
+func AdvancedSubmit(ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, download dir.Directory,
+	q *jobs.Queue,
+) error {
 	const key = "uploader-advanced"
-	return transfer(ctx, sl, c, db, key, download)
+	return transfer(ctx, sl, c, db, key, download, q)
 }
 
 func uploader(err error) string {
@@ -157,6 +170,7 @@ func uploader(err error) string {
 // the function will not log any debug information.
 func transfer( //nolint:funlen
 	ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, key string, download dir.Directory,
+	q *jobs.Queue,
 ) error {
 	const msg = "transfer file handler"
 	if err := nils.Check(ctx, sl, c, db); err != nil {
@@ -188,6 +202,7 @@ func transfer( //nolint:funlen
 	if err != nil {
 		return c.HTML(http.StatusInternalServerError, "The database transaction could not begin")
 	}
+	defer func() { _ = tx.Rollback() }()
 	exist, err := model.SHA384Exists(ctx, tx, checksum)
 	if err != nil {
 		return checkExist(sl, c, err)
@@ -227,10 +242,29 @@ func transfer( //nolint:funlen
 	} else if id == 0 {
 		return nil
 	}
+	// the metadata is read once the upload is duplicated to the download directory
+	defer metadata(ctx, sl, q, uid.String())
 	defer Duplicate(sl, uid, dst, download)
 	return success(c, msg, file.Filename, id)
 }
 
+// metadata queues the job that reads the metadata of the download of the artifact unid,
+// such as the archive manifest, the SAUCE and the texts for the full-text search.
+// Problems are logged, as the metadata is read again on the next server startup.
+func metadata(ctx context.Context, sl *slog.Logger, q *jobs.Queue, unid string) {
+	if _, err := q.Enqueue(ctx, jobs.Metadata, "", unid); err != nil {
+		sl.Error("htmx transfer metadata", slog.String("uuid", unid), slog.Any("error", err))
+	}
+}
+
+// syncCredits links the credits of the artifact id to the scener identities.
+// Problems are logged, as the identities can be synchronized by an editor.
+func syncCredits(ctx context.Context, sl *slog.Logger, db *sql.DB, id int64) {
+	if _, err := model.SyncArtifactIdentities(ctx, db, id); err != nil {
+		sl.Error("htmx transfer credits", slog.Int64("id", id), slog.Any("error", err))
+	}
+}
+
 func success(c *echo.Context, msg, filename string, id int64,
 ) error {
 	if err := nils.Check(c); err != nil {
@@ -467,6 +501,7 @@ func (prod Submission) Submit( //nolint:funlen
 			slog.String("problem", "the database transaction could not start"), slog.Any("error", err))
 		return c.String(http.StatusServiceUnavailable, "error, the database transaction could not begin")
 	}
+	defer func() { _ = tx.Rollback() }()
 	var exist bool
 	switch prod {
 	case Demozoo:
@@ -529,6 +564,7 @@ func (prod Submission) Submit( //nolint:funlen
 			return c.String(http.StatusServiceUnavailable, html)
 		}
 	}
+	syncCredits(ctx, sl, db, key)
 	sl.Info(msg,
 		slog.String("success", "the production has been submitted"),
 		slog.String("remote", name), slog.Int("new record id", id))
@@ -561,7 +597,7 @@ func sanitizeID(c *echo.Context, name, prod string) (int, error) {
 }
 
 func UploadPreview( //nolint:funlen
-	ctx context.Context, sl *slog.Logger, c *echo.Context, preview, thumbnail dir.Directory,
+	ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB, preview, thumbnail dir.Directory,
 ) error {
 	const msg = "htmx upload preview"
 	if err := nils.Check(ctx, sl, c); err != nil {
@@ -623,6 +659,9 @@ func UploadPreview( //nolint:funlen
 			return c.HTML(http.StatusBadRequest,
 				err.Error()+s)
 		}
+		if err := model.RecordAsset(ctx, db, upload.unid, model.Preview, "upload "+file.Filename); err != nil {
+			return badRequest(c, err)
+		}
 		return reloader(c, file.Filename)
 	}
 	if texters(magic) {
@@ -632,6 +671,9 @@ func UploadPreview( //nolint:funlen
 		if err != nil {
 			return badRequest(c, err)
 		}
+		if err := model.RecordAsset(ctx, db, upload.unid, model.Preview, "upload "+file.Filename); err != nil {
+			return badRequest(c, err)
+		}
 		return reloader(c, file.Filename)
 	}
 	return c.HTML(http.StatusBadRequest, "The chosen file is not a valid image or text file")
@@ -662,7 +704,7 @@ func reloader(c *echo.Context, filename string) error {
 // and updates the existing artifact record with the new file information.
 func UploadReplacement( //nolint:funlen
 	ctx context.Context, sl *slog.Logger, c *echo.Context, db *sql.DB,
-	download, extra dir.Directory,
+	download, extra dir.Directory, q *jobs.Queue,
 ) error {
 	const msg = "htmx upload replacement"
 	if err := nils.Check(ctx, sl, c, db); err != nil {
@@ -728,6 +770,7 @@ func UploadReplacement( //nolint:funlen
 	if err != nil {
 		return c.HTML(http.StatusInternalServerError, "The database transaction could not begin")
 	}
+	defer func() { _ = tx.Rollback() }()
 	if err := fu.Update(ctx, tx, upload.id); err != nil {
 		return badRequest(c, fmt.Errorf("file upload update, %w: %w", ErrFormUpdate, err))
 	}
@@ -741,6 +784,7 @@ func UploadReplacement( //nolint:funlen
 	if err := tx.Commit(); err != nil {
 		return c.HTML(http.StatusInternalServerError, "The database commit failed")
 	}
+	metadata(ctx, sl, q, upload.unid)
 	repack := filepath.Join(extra.Path(), upload.unid+".zip")
 	repack = filepath.Clean(repack)
 	defer func() {
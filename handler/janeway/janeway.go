@@ -2,6 +2,8 @@
 // [website]: https://janeway.exotica.org.uk/
 package janeway
 
+import "github.com/Defacto2/server/handler/releaser/meta"
+
 // URI is the URL slug of the releaser.
 type URI string
 
@@ -70,5 +72,13 @@ var groups = Groups{
 // Find returns the janeway author/group id for the given releaser uri.
 // If the releaser is not found, an empty value is returned.
 func Find(uri string) GroupID {
+	if r, loaded := meta.Find(uri); loaded {
+		return GroupID(r.Janeway)
+	}
 	return groups[uri]
 }
+
+// Compiled returns the compiled list of the janeway ids, which is used to seed the releasers metadata.
+func Compiled() Groups {
+	return groups
+}
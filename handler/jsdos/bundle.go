@@ -0,0 +1,95 @@
+package jsdos
+
+// Package file bundle.go contains the creation of the .jsdos bundles used by the js-dos v8 emulator.
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+
+	"github.com/subpop/go-ini"
+)
+
+const (
+	// BundleExt is the filename extension of a js-dos v8 bundle.
+	BundleExt = ".jsdos"
+	// ConfName is the path of the DOSBox configuration within a js-dos v8 bundle.
+	ConfName = ".jsdos/dosbox.conf"
+)
+
+// Conf returns the DOSBox configuration of a js-dos v8 bundle,
+// which is the configuration j followed by the [autoexec] section of the lines.
+func Conf(j Jsdos, lines ...string) ([]byte, error) {
+	b, err := ini.Marshal(j)
+	if err != nil {
+		return nil, fmt.Errorf("jsdos conf ini marshal: %w", err)
+	}
+	buf := bytes.NewBuffer(b)
+	buf.WriteString("\n[autoexec]\n")
+	for line := range slices.Values(lines) {
+		buf.WriteString(line)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// Bundle writes a js-dos v8 bundle to w, which is a zip archive that contains the conf
+// DOSBox configuration and all the program files of the fsys file system.
+// Any existing configuration within the program files is replaced.
+func Bundle(w io.Writer, conf []byte, fsys fs.FS) error {
+	const msg = "jsdos bundle"
+	zw := zip.NewWriter(w)
+	f, err := zw.Create(ConfName)
+	if err != nil {
+		return fmt.Errorf("%s create conf: %w", msg, err)
+	}
+	if _, err := f.Write(conf); err != nil {
+		return fmt.Errorf("%s write conf: %w", msg, err)
+	}
+	err = fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() || strings.EqualFold(name, ConfName) {
+			return nil
+		}
+		return bundleFile(zw, fsys, name, d)
+	})
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if err := zw.Close(); err != nil {
+		return fmt.Errorf("%s close: %w", msg, err)
+	}
+	return nil
+}
+
+func bundleFile(zw *zip.Writer, fsys fs.FS, name string, d fs.DirEntry) error {
+	info, err := d.Info()
+	if err != nil {
+		return err
+	}
+	header, err := zip.FileInfoHeader(info)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	header.Method = zip.Deflate
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	src, err := fsys.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = src.Close() }()
+	if _, err := io.Copy(w, src); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
@@ -1,4 +1,4 @@
-// Package jsdos configures the js-dos v6.22 emulator.
+// Package jsdos configures the js-dos v6.22 emulator and creates the bundles for the js-dos v8 emulator.
 package jsdos
 
 import (
@@ -29,6 +29,7 @@ type Jsdos struct {
 	GUS       `ini:"gus"`
 	Speaker   `ini:"speaker"`
 	DOS       `ini:"dos"`
+	Gameport  `ini:"joystick,omitempty"`
 }
 
 // Dosbox is the [dosbox] section of the configuration file.
@@ -87,6 +88,14 @@ type DOS struct {
 	XMS string `ini:"xms"` // XMS is the Extended Memory used for programs that require more than 1 MB of memory.
 	EMS string `ini:"ems"` // EMS is the Expanded Memory used for programs that require more than 1 MB of memory.
 	UMB string `ini:"umb"` // UMB is the Upper Memory Blocks used for programs that require more than 640 KB of memory.
+	// Keyboard is the keyboard layout code, such as "uk" or "gr", used by the DOS keyboard driver.
+	Keyboard Layout `ini:"keyboardlayout,omitempty"`
+}
+
+// Gameport is the [joystick] section of the configuration file.
+type Gameport struct {
+	Stick Joystick `ini:"joysticktype,omitempty"` // Stick is the type of joystick to emulate.
+	Timed string   `ini:"timed,omitempty"`        // Timed enables the timed intervals for the axis of the joystick.
 }
 
 type Platform string // Platform is the machine dosbox tries to emulate.
@@ -1,9 +1,11 @@
 package jsdos_test
 
 import (
+	"archive/zip"
 	"bytes"
 	"slices"
 	"testing"
+	"testing/fstest"
 
 	"github.com/Defacto2/server/handler/jsdos"
 	"github.com/nalgeon/be"
@@ -249,3 +251,80 @@ func TestValid(t *testing.T) {
 	be.True(t, !jsdos.Valid(".TXT"))
 	be.True(t, !jsdos.Valid(".HIDDEN"))
 }
+
+func TestSettings(t *testing.T) {
+	t.Parallel()
+	s := jsdos.Settings{}
+	be.True(t, s.Empty())
+	be.Err(t, s.Validate(), nil)
+	s = jsdos.Settings{
+		Cycles:   " 3000 ",
+		Joystick: "FCS",
+		Keyboard: " UK",
+		Autoexec: []string{"", " SET BLASTER=A220 I7 D1 "},
+		CDImages: []string{`CD\GAME.ISO`, " "},
+	}.Normalize()
+	be.Equal(t, s.Cycles, "fixed 3000")
+	be.Equal(t, s.Joystick, jsdos.Thrustmaster)
+	be.Equal(t, s.Keyboard, "uk")
+	be.Equal(t, s.Autoexec, []string{"SET BLASTER=A220 I7 D1"})
+	be.Equal(t, s.CDImages, []string{"CD/GAME.ISO"})
+	be.Err(t, s.Validate(), nil)
+	be.Err(t, jsdos.Settings{Cycles: "fixed 10"}.Validate(), jsdos.ErrCycles)
+	be.Err(t, jsdos.Settings{Cycles: "fast"}.Validate(), jsdos.ErrCycles)
+	be.Err(t, jsdos.Settings{Joystick: "wheel"}.Validate(), jsdos.ErrJoystick)
+	be.Err(t, jsdos.Settings{Keyboard: "xx"}.Validate(), jsdos.ErrLayout)
+	be.Err(t, jsdos.Settings{Autoexec: []string{"[cpu]"}}.Validate(), jsdos.ErrAutoexec)
+	be.Err(t, jsdos.Settings{CDImages: []string{"../game.iso"}}.Validate(), jsdos.ErrCDImage)
+	be.Err(t, jsdos.Settings{CDImages: []string{"game.zip"}}.Validate(), jsdos.ErrCDImage)
+}
+
+func TestAutoexec(t *testing.T) {
+	t.Parallel()
+	lines, err := jsdos.Autoexec(jsdos.Settings{}, "")
+	be.Err(t, err, nil)
+	be.Equal(t, lines, []string{"mount c .", "c:"})
+	s := jsdos.Settings{Autoexec: []string{"SET ULTRASND=240,3,3,5,5"}, CDImages: []string{"game.iso"}}
+	lines, err = jsdos.Autoexec(s, "TYPE README && APP.EXE")
+	be.Err(t, err, nil)
+	be.Equal(t, lines, []string{
+		"mount c .", "c:", `imgmount d "game.iso" -t iso`,
+		"SET ULTRASND=240,3,3,5,5", "TYPE README", "APP.EXE",
+	})
+	// run commands must not rewrite the configuration
+	for _, run := range []string{"APP.EXE\n[cpu]\ncycles=max", "[sdl] && APP.EXE", "APP.EXE && DEL\x00"} {
+		_, err = jsdos.Autoexec(s, run)
+		be.Err(t, err, jsdos.ErrAutoexec)
+	}
+}
+
+func TestConf(t *testing.T) {
+	t.Parallel()
+	cfg := jsdos.Jsdos{}
+	cfg.Apply(jsdos.Settings{Cycles: "max", Joystick: jsdos.TwoAxis, Keyboard: "fr"})
+	b, err := jsdos.Conf(cfg, "mount c .", "c:")
+	be.Err(t, err, nil)
+	wants := []string{"cycles=max", "joysticktype=2axis", "keyboardlayout=fr", "[autoexec]\nmount c .\nc:\n"}
+	for v := range slices.Values(wants) {
+		be.True(t, bytes.Contains(b, []byte(v)))
+	}
+}
+
+func TestBundle(t *testing.T) {
+	t.Parallel()
+	fsys := fstest.MapFS{
+		"APP.EXE":            {Data: []byte("MZ")},
+		"DATA/LEVEL.DAT":     {Data: []byte("data")},
+		".jsdos/dosbox.conf": {Data: []byte("[cpu]")},
+	}
+	var buf bytes.Buffer
+	err := jsdos.Bundle(&buf, []byte("[autoexec]\n"), fsys)
+	be.Err(t, err, nil)
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	be.Err(t, err, nil)
+	names := []string{}
+	for f := range slices.Values(r.File) {
+		names = append(names, f.Name)
+	}
+	be.Equal(t, names, []string{jsdos.ConfName, "APP.EXE", "DATA/LEVEL.DAT"})
+}
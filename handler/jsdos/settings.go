@@ -0,0 +1,220 @@
+package jsdos
+
+// Package file settings.go contains the per-artifact emulator settings and their validation.
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrAutoexec = errors.New("autoexec line is invalid")
+	ErrCDImage  = errors.New("cd image path is invalid")
+	ErrCycles   = errors.New("cycles value is invalid")
+	ErrJoystick = errors.New("joystick type is invalid")
+	ErrLayout   = errors.New("keyboard layout is invalid")
+)
+
+const (
+	// MaxAutoexec is the maximum number of autoexec lines of an artifact.
+	MaxAutoexec = 20
+	// MaxCDImages is the maximum number of CD images that can be mounted, using the drives D: to G:.
+	MaxCDImages = 4
+	// MaxCycles is the maximum fixed number of cycles, which is faster than any browser can emulate.
+	MaxCycles = 200000
+	// MinCycles is the minimum fixed number of cycles, which is slower than the original IBM PC.
+	MinCycles = 100
+	// lineLength is the maximum length of a command line in MS-DOS.
+	lineLength = 127
+)
+
+type Joystick string // Joystick is the type of joystick to emulate using the gameport.
+
+const (
+	NoJoystick   Joystick = none      // NoJoystick disables the joystick emulation.
+	AutoJoystick Joystick = auto      // AutoJoystick chooses the emulation based on the connected joysticks.
+	TwoAxis      Joystick = "2axis"   // TwoAxis supports two joysticks with two axes and two buttons.
+	FourAxis     Joystick = "4axis"   // FourAxis supports one joystick with four axes and four buttons.
+	FourAxis2    Joystick = "4axis_2" // FourAxis2 supports one joystick with four axes using the second joystick.
+	Thrustmaster Joystick = "fcs"     // Thrustmaster emulates the Thrustmaster Flight Control System.
+	CHFlight     Joystick = "ch"      // CHFlight emulates the CH Products Flightstick.
+)
+
+// Joysticks returns the joystick types in their display order.
+func Joysticks() []Joystick {
+	return []Joystick{AutoJoystick, NoJoystick, TwoAxis, FourAxis, FourAxis2, Thrustmaster, CHFlight}
+}
+
+type Layout string // Layout is the keyboard layout code of the DOS keyboard driver.
+
+// Layouts returns the keyboard layout codes supported by DOSBox, with the default "auto" layout first.
+func Layouts() []Layout {
+	return []Layout{
+		auto, "us", "uk", "fr", "gr", "it", "sp", "nl", "be", "sf", "sg", "dk", "no", "sv", "su",
+		"pl", "cz", "sk", "hu", "ru", "ur", "bl", "br", "pt", "la", "tr", "gk", "he", "yu", "hr",
+	}
+}
+
+// Settings are the additional emulator settings of an artifact, stored as JSON.
+// An empty value uses the emulator default or the hardware settings of the artifact.
+type Settings struct {
+	Cycles   Cycles   `json:"cycles,omitempty"`   // Cycles is the emulation speed, either "auto", "max", or a fixed number.
+	Joystick Joystick `json:"joystick,omitempty"` // Joystick is the type of joystick to emulate.
+	Keyboard Layout   `json:"keyboard,omitempty"` // Keyboard is the keyboard layout code.
+	Autoexec []string `json:"autoexec,omitempty"` // Autoexec are the commands to run before the program.
+	CDImages []string `json:"cdimages,omitempty"` // CDImages are the paths of the ISO or CUE images to mount as CD drives.
+}
+
+// Empty returns true if the settings do not change any of the emulator defaults.
+func (s Settings) Empty() bool {
+	return s.Cycles == "" && s.Joystick == "" && s.Keyboard == "" &&
+		len(s.Autoexec) == 0 && len(s.CDImages) == 0
+}
+
+// Normalize returns the settings with the white space and letter case tidied,
+// and the blank autoexec lines and CD image paths removed.
+// A fixed number of cycles is given the "fixed" prefix.
+func (s Settings) Normalize() Settings {
+	cycles := strings.Join(strings.Fields(strings.ToLower(string(s.Cycles))), " ")
+	if _, err := strconv.Atoi(cycles); err == nil {
+		cycles = "fixed " + cycles
+	}
+	n := Settings{
+		Cycles:   Cycles(cycles),
+		Joystick: Joystick(strings.ToLower(strings.TrimSpace(string(s.Joystick)))),
+		Keyboard: Layout(strings.ToLower(strings.TrimSpace(string(s.Keyboard)))),
+	}
+	for line := range slices.Values(s.Autoexec) {
+		if line = strings.TrimSpace(line); line != "" {
+			n.Autoexec = append(n.Autoexec, line)
+		}
+	}
+	for name := range slices.Values(s.CDImages) {
+		if name = strings.TrimSpace(strings.ReplaceAll(name, "\\", "/")); name != "" {
+			n.CDImages = append(n.CDImages, name)
+		}
+	}
+	return n
+}
+
+// Validate returns an error if any of the normalized settings cannot be used by the emulator.
+func (s Settings) Validate() error {
+	if err := s.validCycles(); err != nil {
+		return err
+	}
+	if s.Joystick != "" && !slices.Contains(Joysticks(), s.Joystick) {
+		return fmt.Errorf("%w: %q", ErrJoystick, s.Joystick)
+	}
+	if s.Keyboard != "" && !slices.Contains(Layouts(), s.Keyboard) {
+		return fmt.Errorf("%w: %q", ErrLayout, s.Keyboard)
+	}
+	if len(s.Autoexec) > MaxAutoexec {
+		return fmt.Errorf("%w: more than %d lines", ErrAutoexec, MaxAutoexec)
+	}
+	for line := range slices.Values(s.Autoexec) {
+		if !validLine(line) {
+			return fmt.Errorf("%w: %q", ErrAutoexec, line)
+		}
+	}
+	if len(s.CDImages) > MaxCDImages {
+		return fmt.Errorf("%w: more than %d images", ErrCDImage, MaxCDImages)
+	}
+	for name := range slices.Values(s.CDImages) {
+		if !validImage(name) {
+			return fmt.Errorf("%w: %q", ErrCDImage, name)
+		}
+	}
+	return nil
+}
+
+func (s Settings) validCycles() error {
+	switch s.Cycles {
+	case "", AutoCycles, Max:
+		return nil
+	}
+	value, ok := strings.CutPrefix(string(s.Cycles), "fixed ")
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrCycles, s.Cycles)
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil || i < MinCycles || i > MaxCycles {
+		return fmt.Errorf("%w: %q", ErrCycles, s.Cycles)
+	}
+	return nil
+}
+
+// validLine returns true if the autoexec line is a printable ASCII command
+// that fits the MS-DOS command line and cannot start a new configuration section.
+func validLine(line string) bool {
+	if line == "" || len(line) > lineLength || strings.HasPrefix(line, "[") {
+		return false
+	}
+	for _, r := range line {
+		if r < ' ' || r > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// validImage returns true if the name is a relative path to an ISO or CUE image
+// within the program files, that does not allow for any path traversal.
+func validImage(name string) bool {
+	if name == "" || path.IsAbs(name) || path.Clean(name) != name || strings.HasPrefix(name, "../") {
+		return false
+	}
+	switch strings.ToLower(path.Ext(name)) {
+	case ".iso", ".cue":
+	default:
+		return false
+	}
+	return validLine(name) && !strings.ContainsAny(name, `"`)
+}
+
+// Apply sets the emulation speed, joystick and keyboard layout of the settings.
+// The empty settings keep the existing configuration.
+func (j *Jsdos) Apply(s Settings) {
+	if s.Cycles != "" {
+		j.Cycles = s.Cycles
+	}
+	if s.Joystick != "" {
+		j.Stick = s.Joystick
+		j.Timed = yes
+		if s.Joystick == NoJoystick {
+			j.Timed = ""
+		}
+	}
+	if s.Keyboard != "" {
+		j.Keyboard = s.Keyboard
+	}
+}
+
+// Autoexec returns the lines of the [autoexec] section of a js-dos v8 bundle configuration.
+// The program files are mounted as the C: drive and the CD images as the D: drive onward,
+// then the autoexec lines of the settings are run, followed by the run commands.
+// Multiple run commands are separated by the '&&' operator.
+// An error is returned if a run command is not a valid autoexec line,
+// as it could otherwise add new lines or sections to the configuration.
+func Autoexec(s Settings, run string) ([]string, error) {
+	lines := []string{"mount c .", "c:"}
+	for i, name := range s.CDImages {
+		drive := string(rune('d' + i))
+		lines = append(lines, fmt.Sprintf("imgmount %s \"%s\" -t iso", drive, name))
+	}
+	lines = append(lines, s.Autoexec...)
+	for cmd := range strings.SplitSeq(run, "&&") {
+		cmd = strings.TrimSpace(cmd)
+		if cmd == "" {
+			continue
+		}
+		if !validLine(cmd) {
+			return nil, fmt.Errorf("%w: %q", ErrAutoexec, cmd)
+		}
+		lines = append(lines, cmd)
+	}
+	return lines, nil
+}
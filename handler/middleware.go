@@ -48,6 +48,13 @@ func skipPaths(e *echo.Context) bool {
 	return false
 }
 
+// skipDownloads skips the compression of the file downloads and the inline views of the files,
+// which are mostly compressed archives, so the ETag and digest headers match the files that are sent.
+func skipDownloads(e *echo.Context) bool {
+	p := e.Request().URL.Path
+	return strings.HasPrefix(p, "/d/") || strings.HasPrefix(p, "/v/")
+}
+
 // NoCrawl middleware adds a `X-Robots-Tag` header to the response.
 // The header contains the noindex and nofollow values that tell search engine
 // crawlers to not index or crawl the page or asset.
@@ -222,6 +229,7 @@ func (c *Configuration) RequestLoggerConfig(sl *slog.Logger) middleware.RequestL
 // CacheMiddleware sets appropriate Cache-Control headers for API responses.
 func CacheMiddleware() echo.MiddlewareFunc {
 	const (
+		age1min    = "60"
 		age5min    = "300"
 		age30min   = "1800"
 		age1hour   = "3600"
@@ -232,6 +240,8 @@ func CacheMiddleware() echo.MiddlewareFunc {
 			path := c.Request().URL.Path
 			// Set Cache-Control header based on endpoint
 			switch {
+			case strings.Contains(path, "/changes"):
+				c.Response().Header().Set("Cache-Control", "public, max-age="+age1min)
 			case strings.Contains(path, "/categories"), strings.Contains(path, "/platforms"):
 				c.Response().Header().Set("Cache-Control", "public, max-age="+age24hours)
 			case strings.Contains(path, "/artifacts"), strings.Contains(path, "/artifacts/new"):
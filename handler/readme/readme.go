@@ -20,12 +20,11 @@ import (
 	"github.com/Defacto2/magicnumber"
 	"github.com/Defacto2/server/handler/render"
 	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/encodings"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/Defacto2/server/internal/postgres/models"
-	"github.com/bengarrett/ansibump"
-	"github.com/bengarrett/binbump"
+	"github.com/Defacto2/server/internal/textmode"
 	"github.com/bengarrett/sauce"
-	"golang.org/x/text/encoding/charmap"
 )
 
 // Suggest returns a suggested readme file name for the record.
@@ -157,11 +156,13 @@ func candidate() []string {
 //
 // The CP1252 and ISO-8859-1 Buffer may also include a FILE_ID.DIZ prefixed metadata.
 // However, the UTF-8 Buffer does get the FILE_ID.DIZ prefix.
+//
+// The charset is the key of the text encoding chosen by an editor, or an empty string to detect it.
 func PlainTextBuffers(
-	art *models.File, sizeLimit int64, download, extra dir.Directory,
+	art *models.File, charset string, sizeLimit int64, download, extra dir.Directory,
 ) (*bytes.Buffer, *bytes.Buffer, sauce.Record, error) {
 	sl := slog.Default()
-	return PlainTextBuffersW(sl, art, sizeLimit, download, extra)
+	return PlainTextBuffersW(sl, art, charset, sizeLimit, download, extra)
 }
 
 // PlainTextBuffersW returns the content of the readme file or the text of the file download.
@@ -170,8 +171,10 @@ func PlainTextBuffers(
 //
 // The CP1252 and ISO-8859-1 Buffer may also include a FILE_ID.DIZ prefixed metadata.
 // However, the UTF-8 Buffer does get the FILE_ID.DIZ prefix.
+//
+// The charset is the key of the text encoding chosen by an editor, or an empty string to detect it.
 func PlainTextBuffersW( //nolint:funlen
-	sl *slog.Logger, art *models.File, sizeLimit int64, download, extra dir.Directory,
+	sl *slog.Logger, art *models.File, charset string, sizeLimit int64, download, extra dir.Directory,
 ) (*bytes.Buffer, *bytes.Buffer, sauce.Record, error) {
 	const msg = "readme pool"
 	const format = msg + " %s: %w"
@@ -186,7 +189,7 @@ func PlainTextBuffersW( //nolint:funlen
 	// This might be useful if we want to force Go to not use the garbage collector.
 	// buf.Reset() diz.Reset() ruf.Reset()
 	err1 := render.DescriptorText(diz, art, extra)
-	err2 := render.InformationText(buf, ruf, art, sizeLimit, download, extra)
+	err2 := render.InformationText(buf, ruf, art, charset, sizeLimit, download, extra)
 	err3 := render.HelperText(hlp, art, extra)
 	var errs error
 	if err1 != nil {
@@ -240,7 +243,7 @@ func PlainTextBuffersW( //nolint:funlen
 	} else if match {
 		platform := strings.TrimSpace(strings.ToLower(art.Platform.String))
 		sl.Info(msg+" returned ansi texts", slog.String("platform", platform))
-		return ansiTexts(buf, diz, hlp, ruf, platform, sr, errs)
+		return ansiTexts(buf, diz, hlp, ruf, platform, charset, sr, errs)
 	}
 	// binary texts can also cause false positives
 	if binaryText := sign == magicnumber.Unknown; binaryText {
@@ -343,39 +346,27 @@ func plainTexts(
 
 func ansiTexts(
 	buf, diz, hlp, ruf *bytes.Buffer,
-	platform string, sr sauce.Record, errs error) (
+	platform, charset string, sr sauce.Record, errs error) (
 	*bytes.Buffer, *bytes.Buffer, sauce.Record, error,
 ) {
 	if err := nils.Check(buf, diz, hlp, ruf); err != nil {
 		return nil, nil, sr, fmt.Errorf("ansi texts: %w", err)
 	}
-	width := 80
-	ti1 := sr.Info.Info1
-	if ti1.Info == "character width" && ti1.Value > 0 {
-		width = int(ti1.Value)
-	}
-	charset := charmap.CodePage437
-	pal := ansibump.CGA16
-	amiparser := false
-	if platform == "textamiga" {
-		charset = charmap.ISO8859_1
-		pal = ansibump.DP2
-		amiparser = true
-	}
-	cust := ansibump.Customizer{
-		Width:       width,
-		AmigaParser: amiparser,
-		Strict:      false,
-		Color:       pal,
-		CharSet:     charset,
-	}
-	ansi, err := cust.Buffer(
-		bytes.NewReader(buf.Bytes()),
-	)
+	opts := textmode.Options{Amiga: platform == "textamiga"} //nolint:exhaustruct
+	if c, ok := encodings.Find(charset); ok {
+		opts.Amiga = c.Topaz()
+		opts.Charmap = c.Charmap()
+	}
+	screen, err := textmode.ANSI(buf.Bytes(), opts)
 	if err != nil {
 		errs = errors.Join(errs, err)
 		return nil, nil, sr, errs
 	}
+	ansi := new(bytes.Buffer)
+	if err := screen.HTML(ansi); err != nil {
+		errs = errors.Join(errs, err)
+		return nil, nil, sr, errs
+	}
 	// for now we reset all other buffers
 	buf.Reset()
 	diz.Reset()
@@ -392,21 +383,23 @@ func binaryTexts(
 	if err := nils.Check(buf, diz, hlp, ruf); err != nil {
 		return nil, nil, sr, fmt.Errorf("binary texts: %w", err)
 	}
-	width := 0 // use default
-	maxRows := 0
-	pal := binbump.StandardCGA
+	opts := textmode.Options{} //nolint:exhaustruct
 	if y := int(year); helper.Year(y) && y < 1992 {
-		width = 80
-		maxRows = 25
-		pal = binbump.RevisedCGA
+		pal := textmode.RevisedCGA()
+		opts.Width = 80
+		opts.MaxRows = 25
+		opts.Palette = &pal
 	}
-	binbuf, err := binbump.Buffer(
-		bytes.NewReader(buf.Bytes()), width, maxRows, pal, nil,
-	)
+	screen, err := textmode.Bin(buf.Bytes(), opts)
 	if err != nil {
 		errs = errors.Join(errs, err)
 		return nil, nil, sr, errs
 	}
+	binbuf := new(bytes.Buffer)
+	if err := screen.HTML(binbuf); err != nil {
+		errs = errors.Join(errs, err)
+		return nil, nil, sr, errs
+	}
 	// for now we reset all other buffers
 	buf.Reset()
 	diz.Reset()
@@ -11,9 +11,9 @@ import (
 
 func TestPanics(t *testing.T) {
 	var x dir.Directory
-	_, _, _, err := readme.PlainTextBuffers(nil, -1, x, x)
+	_, _, _, err := readme.PlainTextBuffers(nil, "", -1, x, x)
 	be.Err(t, err)
-	_, _, _, err = readme.PlainTextBuffersW(nil, nil, -1, x, x)
+	_, _, _, err = readme.PlainTextBuffersW(nil, nil, "", -1, x, x)
 	be.Err(t, err)
 }
 
@@ -11,6 +11,8 @@ import (
 	"maps"
 	"slices"
 	"strings"
+
+	"github.com/Defacto2/server/handler/releaser/meta"
 )
 
 // A Path is the partial URL path of the releaser.
@@ -1623,6 +1625,9 @@ func Initialisms() *List { //nolint:maintidx,funlen
 //	Initialism("the-firm") = []string{"FiRM, FRM"}
 //	Initialism("defacto2") = []string{"DF2"}
 func Initialism(path Path) []string {
+	if r, loaded := meta.Find(string(path)); loaded {
+		return r.Initialisms
+	}
 	initialism := *Initialisms()
 	return initialism[path]
 }
@@ -1635,6 +1640,9 @@ func Initialism(path Path) []string {
 //	IsInitialism("defacto2") = true
 //	IsInitialism("some-random-bbs") = false
 func IsInitialism(path Path) bool {
+	if r, loaded := meta.Find(string(path)); loaded {
+		return len(r.Initialisms) > 0
+	}
 	initialism := *Initialisms()
 	_, match := initialism[path]
 	return match
@@ -1659,6 +1667,16 @@ func Join(path Path) string {
 // Match returns the list of initialisms that match the given string.
 func Match(s string) []Path {
 	var partials []Path
+	if meta.Loaded() {
+		for r := range slices.Values(meta.All()) {
+			if slices.ContainsFunc(r.Initialisms, func(value string) bool {
+				return strings.EqualFold(value, s)
+			}) {
+				partials = append(partials, Path(r.Slug))
+			}
+		}
+		return partials
+	}
 	for partial, values := range maps.All(*Initialisms()) {
 		for value := range slices.Values(values) {
 			if strings.EqualFold(value, s) {
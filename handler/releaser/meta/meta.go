@@ -0,0 +1,192 @@
+// Package meta provides the in-memory cache of the releaser metadata that is stored in the
+// releasers database table, such as the styled names, initialisms, websites and the ids of
+// the releasers on other Scene websites.
+//
+// Until the cache is loaded, the lookups of the releaser packages use their compiled lists.
+// Once loaded, the cache replaces the compiled lists so the editor changes apply without a redeploy.
+//
+// The cache also holds the aliases of the renamed and merged releasers, that redirect the former slugs.
+package meta
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Website is a historical or current website of the releaser.
+type Website struct {
+	URL        string `json:"url"`                  // URL of the website, a not working site excludes the protocol.
+	Name       string `json:"name,omitempty"`       // Name of the website.
+	NotWorking bool   `json:"notWorking,omitempty"` // NotWorking sites are not hyperlinked.
+}
+
+// Releaser is the metadata of a releaser.
+type Releaser struct {
+	Slug        string    `json:"slug"`                  // Slug is the partial URL path of the releaser.
+	Name        string    `json:"name,omitempty"`        // Name is the styled name, or empty to use the humanized slug.
+	Initialisms []string  `json:"initialisms,omitempty"` // Initialisms are the alternative spellings, acronyms and initialisms.
+	CSDb        int       `json:"csdb,omitempty"`        // CSDb is the C-64 Scene Database group id.
+	Demozoo     int       `json:"demozoo,omitempty"`     // Demozoo is the Demozoo group id.
+	Janeway     int       `json:"janeway,omitempty"`     // Janeway is the Janeway Amiga Scene author or group id.
+	Sixteen     string    `json:"sixteen,omitempty"`     // Sixteen is the partial URL path of the 16colors group tag.
+	Websites    []Website `json:"websites,omitempty"`    // Websites are the websites of the releaser.
+	Tidbits     []int     `json:"tidbits,omitempty"`     // Tidbits are the ids of the historical tidbits.
+}
+
+// Empty returns true if the releaser has no metadata other than the slug.
+func (r Releaser) Empty() bool {
+	return r.Name == "" && len(r.Initialisms) == 0 && r.CSDb == 0 && r.Demozoo == 0 &&
+		r.Janeway == 0 && r.Sixteen == "" && len(r.Websites) == 0 && len(r.Tidbits) == 0
+}
+
+//nolint:gochecknoglobals
+var cache = struct {
+	sync.RWMutex
+	loaded  bool
+	rels    map[string]Releaser
+	aliases map[string]string
+}{}
+
+// Load replaces the cache with the releasers.
+// An empty list of releasers, such as from an unseeded table, unloads the cache
+// so the lookups continue to use the compiled lists.
+func Load(rels ...Releaser) {
+	m := make(map[string]Releaser, len(rels))
+	for _, r := range rels {
+		m[r.Slug] = r
+	}
+	cache.Lock()
+	defer cache.Unlock()
+	if len(m) == 0 {
+		cache.rels = nil
+		cache.loaded = false
+		return
+	}
+	cache.rels = m
+	cache.loaded = true
+}
+
+// Reset empties the cache, so the lookups return to using the compiled lists.
+func Reset() {
+	cache.Lock()
+	defer cache.Unlock()
+	cache.rels = nil
+	cache.aliases = nil
+	cache.loaded = false
+}
+
+// Loaded returns true if the cache has been loaded.
+func Loaded() bool {
+	cache.RLock()
+	defer cache.RUnlock()
+	return cache.loaded
+}
+
+// Set adds or replaces the releaser in a loaded cache.
+func Set(r Releaser) {
+	cache.Lock()
+	defer cache.Unlock()
+	if !cache.loaded {
+		return
+	}
+	cache.rels[r.Slug] = r
+}
+
+// Delete removes the releaser slug from a loaded cache.
+func Delete(slug string) {
+	cache.Lock()
+	defer cache.Unlock()
+	delete(cache.rels, slug)
+}
+
+// Find returns the metadata of the releaser slug, and true if the cache is loaded.
+// A false value means the cache is not loaded and the compiled lists should be used.
+// A releaser that has no metadata is returned with only the slug.
+func Find(slug string) (Releaser, bool) {
+	cache.RLock()
+	defer cache.RUnlock()
+	if !cache.loaded {
+		return Releaser{}, false
+	}
+	if r, ok := cache.rels[slug]; ok {
+		return r, true
+	}
+	return Releaser{Slug: slug}, true
+}
+
+// All returns the releasers of a loaded cache sorted by their slugs.
+func All() []Releaser {
+	cache.RLock()
+	defer cache.RUnlock()
+	return slices.SortedFunc(maps.Values(cache.rels), func(a, b Releaser) int {
+		return cmp.Compare(a.Slug, b.Slug)
+	})
+}
+
+// Tidbit returns the sorted slugs of the releasers that share the tidbit id, and true if the cache is loaded.
+func Tidbit(id int) ([]string, bool) {
+	cache.RLock()
+	defer cache.RUnlock()
+	if !cache.loaded {
+		return nil, false
+	}
+	slugs := []string{}
+	for slug, r := range cache.rels {
+		if slices.Contains(r.Tidbits, id) {
+			slugs = append(slugs, slug)
+		}
+	}
+	slices.Sort(slugs)
+	return slugs, true
+}
+
+// LoadAliases replaces the aliases of the renamed and merged releasers,
+// which is a map of the former releaser slugs to their target slugs.
+func LoadAliases(aliases map[string]string) {
+	m := maps.Clone(aliases)
+	cache.Lock()
+	defer cache.Unlock()
+	cache.aliases = m
+}
+
+// Alias returns the target slug of a renamed or merged releaser slug, and true if the slug is an alias.
+func Alias(slug string) (string, bool) {
+	cache.RLock()
+	defer cache.RUnlock()
+	target, ok := cache.aliases[slug]
+	return target, ok
+}
+
+// Aliases returns a copy of the aliases of the renamed and merged releasers.
+func Aliases() map[string]string {
+	cache.RLock()
+	defer cache.RUnlock()
+	return maps.Clone(cache.aliases)
+}
+
+// SetAlias redirects the releaser alias slug to the target slug.
+// Any existing aliases of the alias slug are redirected to the target slug,
+// and the target slug stops being an alias, so the aliases never chain or loop.
+func SetAlias(alias, target string) {
+	cache.Lock()
+	defer cache.Unlock()
+	if cache.aliases == nil {
+		cache.aliases = map[string]string{}
+	}
+	for key, val := range cache.aliases {
+		if val == alias {
+			cache.aliases[key] = target
+		}
+	}
+	delete(cache.aliases, target)
+	cache.aliases[alias] = target
+}
+
+// DeleteAlias removes the releaser alias slug.
+func DeleteAlias(alias string) {
+	cache.Lock()
+	defer cache.Unlock()
+	delete(cache.aliases, alias)
+}
@@ -0,0 +1,81 @@
+package meta_test
+
+import (
+	"testing"
+
+	"github.com/Defacto2/server/handler/releaser/meta"
+	"github.com/nalgeon/be"
+)
+
+// TestCache is not run in parallel, as the cache is shared by the package.
+func TestCache(t *testing.T) {
+	defer meta.Reset()
+	meta.Reset()
+	be.True(t, !meta.Loaded())
+	_, loaded := meta.Find("defacto2")
+	be.True(t, !loaded)
+	meta.Set(meta.Releaser{Slug: "defacto2"})
+	be.True(t, !meta.Loaded())
+	meta.Load()
+	be.True(t, !meta.Loaded())
+
+	meta.Load(
+		meta.Releaser{Slug: "defacto2", Name: "Defacto2", Initialisms: []string{"DF2"}, Tidbits: []int{1}},
+		meta.Releaser{Slug: "acid-productions", Name: "ACiD Productions", Tidbits: []int{1, 2}},
+	)
+	be.True(t, meta.Loaded())
+	r, loaded := meta.Find("defacto2")
+	be.True(t, loaded)
+	be.Equal(t, r.Name, "Defacto2")
+	r, loaded = meta.Find("razor-1911")
+	be.True(t, loaded)
+	be.True(t, r.Empty())
+	be.Equal(t, r.Slug, "razor-1911")
+
+	all := meta.All()
+	be.Equal(t, len(all), 2)
+	be.Equal(t, all[0].Slug, "acid-productions")
+	slugs, _ := meta.Tidbit(1)
+	be.Equal(t, slugs, []string{"acid-productions", "defacto2"})
+	slugs, _ = meta.Tidbit(3)
+	be.Equal(t, len(slugs), 0)
+
+	meta.Set(meta.Releaser{Slug: "razor-1911", Name: "Razor 1911"})
+	r, _ = meta.Find("razor-1911")
+	be.Equal(t, r.Name, "Razor 1911")
+	meta.Delete("razor-1911")
+	r, _ = meta.Find("razor-1911")
+	be.True(t, r.Empty())
+}
+
+// TestAliases is not run in parallel, as the cache is shared by the package.
+func TestAliases(t *testing.T) {
+	defer meta.Reset()
+	meta.Reset()
+	_, ok := meta.Alias("acid")
+	be.True(t, !ok)
+	meta.SetAlias("acid", "ansi-creators-in-demand")
+	meta.SetAlias("ansi-creators-in-demand", "acid-productions")
+	target, ok := meta.Alias("acid")
+	be.True(t, ok)
+	be.Equal(t, target, "acid-productions")
+	be.Equal(t, len(meta.Aliases()), 2)
+	// a renamed target stops being an alias
+	meta.SetAlias("acid-productions", "ansi-creators-in-demand")
+	_, ok = meta.Alias("ansi-creators-in-demand")
+	be.True(t, !ok)
+	target, _ = meta.Alias("acid")
+	be.Equal(t, target, "ansi-creators-in-demand")
+	meta.DeleteAlias("acid")
+	_, ok = meta.Alias("acid")
+	be.True(t, !ok)
+	meta.LoadAliases(map[string]string{"ice": "insane-creators-enterprise"})
+	be.Equal(t, meta.Aliases(), map[string]string{"ice": "insane-creators-enterprise"})
+}
+
+func TestEmpty(t *testing.T) {
+	t.Parallel()
+	be.True(t, meta.Releaser{Slug: "defacto2"}.Empty())
+	be.True(t, !meta.Releaser{Slug: "defacto2", Demozoo: 1}.Empty())
+	be.True(t, !meta.Releaser{Websites: []meta.Website{{URL: "example.com"}}}.Empty())
+}
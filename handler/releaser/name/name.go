@@ -10,6 +10,8 @@ import (
 	"regexp"
 	"slices"
 	"strings"
+
+	"github.com/Defacto2/server/handler/releaser/meta"
 )
 
 var ErrInvalidPath = errors.New("the path contains invalid characters")
@@ -332,6 +334,7 @@ func Uppercase() []string { //nolint:funlen
 
 // String returns the well-known styled name of the releaser if it exists in the
 // names, lowercase or uppercase lists. Otherwise it returns an empty string.
+// Once the releasers metadata is loaded, the styled name of the metadata is returned instead.
 //
 // Example:
 //
@@ -339,6 +342,9 @@ func Uppercase() []string { //nolint:funlen
 //	name.Path("razor-1911").String() = "" // unlisted
 func (path Path) String() string {
 	p := Path(strings.ToLower(string(path)))
+	if r, loaded := meta.Find(string(p)); loaded {
+		return r.Name
+	}
 	if _, match := specials[p]; match {
 		return specials[p]
 	}
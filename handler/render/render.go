@@ -13,6 +13,7 @@ import (
 	"github.com/Defacto2/helper"
 	"github.com/Defacto2/magicnumber"
 	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/encodings"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/Defacto2/server/internal/postgres/models"
 	"golang.org/x/text/encoding"
@@ -23,6 +24,8 @@ import (
 var (
 	ErrDownload = errors.New("cannot stat the downloaded file")
 	ErrFilename = errors.New("file model filename is empty")
+	ErrSize     = errors.New("text is too long")
+	ErrText     = errors.New("artifact has no text to display")
 	ErrUUID     = errors.New("file model uuid is empty")
 )
 
@@ -56,11 +59,25 @@ func Encoder(art *models.File, r io.Reader) encoding.Encoding { //nolint:nolintl
 	return guess
 }
 
+// Charset returns the encoding for the model file entry using the key of the text encoding
+// chosen by an editor. When the key is empty or unknown, the encoding is determined by the
+// Encoder using the platform, section or the byte content.
+func Charset(art *models.File, key string, r io.Reader) encoding.Encoding { //nolint:nolintlint,ireturn
+	if c, ok := encodings.Find(key); ok {
+		return c.Encoding()
+	}
+	return Encoder(art, r)
+}
+
 // InformationText writes the content of either the file download or an extracted text file to the buffers.
 // The text is intended to be used as a readme, preview or an in-browser viewer.
+// The charset is the key of the text encoding chosen by an editor, which is used to decode
+// the UTF-16 texts that otherwise cannot be displayed, or an empty string.
 //
 // Both the buf buffer and the ruf rune buffer are reset before writing.
-func InformationText(buf, ruf *bytes.Buffer, art *models.File, sizeLimit int64, download, extra dir.Directory) error {
+func InformationText(buf, ruf *bytes.Buffer, art *models.File, charset string,
+	sizeLimit int64, download, extra dir.Directory,
+) error {
 	const msg = "render information text"
 	const format = msg + ": %w"
 	if err := nils.Check(buf, ruf, art); err != nil {
@@ -93,6 +110,12 @@ func InformationText(buf, ruf *bytes.Buffer, art *models.File, sizeLimit int64,
 	if err != nil {
 		return fmt.Errorf("information text copy %w: %q", err, name)
 	}
+	if c, ok := encodings.Find(charset); ok && c.Wide() {
+		if p, err := encodings.Decode(buf.Bytes(), c.Key); err == nil {
+			buf.Reset()
+			buf.Write(p)
+		}
+	}
 
 	b := buf.Bytes()
 	r := bytes.NewReader(b)
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Defacto2/helper"
 	"github.com/Defacto2/server/handler/render"
+	"github.com/Defacto2/server/internal/dir"
 	"github.com/Defacto2/server/internal/postgres/models"
 	"github.com/aarondl/null/v8"
 	"github.com/nalgeon/be"
@@ -114,3 +115,39 @@ func TestTrimEOFs(t *testing.T) {
 	got = render.TrimEOFs(s)
 	be.Equal(t, got, wants)
 }
+
+func TestUnicode(t *testing.T) {
+	t.Parallel()
+	_, err := render.Unicode(nil, "", 0, "", "")
+	be.Err(t, err)
+	const unid = "00000000-0000-0000-0000-000000000000"
+	tmp := t.TempDir()
+	text := []byte{0xc9, 0xcd, 0xbb, '\r', '\n', 'h', 'i', 0x8a, 0x1a, 0x1a}
+	err = os.WriteFile(filepath.Join(tmp, unid), text, 0o600)
+	be.Err(t, err, nil)
+	art := &models.File{
+		UUID:     null.StringFrom(unid),
+		Filename: null.StringFrom("hi.nfo"),
+		Platform: null.StringFrom("text"),
+	}
+	dl := dir.Directory(tmp)
+	b, err := render.Unicode(art, "", 1024, dl, dl)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "╔═╗\nhi")
+	b, err = render.Unicode(art, "iso-8859-1", 1024, dl, dl)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "ÉÍ»\nhi")
+	_, err = render.Unicode(art, "", 1, dl, dl)
+	be.Err(t, err, render.ErrSize)
+	art.Platform = null.StringFrom("dos")
+	_, err = render.Unicode(art, "", 1024, dl, dl)
+	be.Err(t, err, render.ErrText)
+}
+
+func TestAccessible(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, string(render.Accessible(nil)), "")
+	s := "╔══════════╗\n║ \x1b[1mDEFACTO2\x1b[0m ║\n╚══════════╝\n\n\n" +
+		"  -=< greetings to >=-\n░▒▓█▓▒░\n*.*.*.*\nAll ░ the ░ crews.\n\n"
+	be.Equal(t, string(render.Accessible([]byte(s))), "DEFACTO2\n\ngreetings to\n\nAll the crews.\n")
+}
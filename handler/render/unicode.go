@@ -0,0 +1,136 @@
+package render
+
+// Package file unicode.go contains the UTF-8 transcoding of the texts to read without the site fonts.
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/Defacto2/magicnumber"
+	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/encodings"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/Defacto2/server/internal/textmode"
+)
+
+// Unicode returns the content of either the file download or an extracted text file as UTF-8 text,
+// which can be read without the site fonts, such as in a terminal or by assistive technology.
+// Any SAUCE metadata and the DOS end-of-file markers are removed.
+//
+// The charset is the key of the text encoding chosen by an editor. When it is empty, the encoding
+// is determined by the platform and section, otherwise it is detected from the byte content.
+func Unicode(art *models.File, charset string, sizeLimit int64, download, extra dir.Directory) ([]byte, error) {
+	const msg = "render unicode"
+	if err := nils.Check(art); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	var buf bytes.Buffer
+	name, err := infoFilename(&buf, art, download, extra)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	if name == "" {
+		return nil, fmt.Errorf("%s: %w", msg, ErrText)
+	}
+	st, err := os.Stat(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	if st.Size() > sizeLimit {
+		return nil, fmt.Errorf("%s: %w: %d bytes", msg, ErrSize, st.Size())
+	}
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	if sign, _ := magicnumber.Archive(bytes.NewReader(b)); sign != magicnumber.Unknown {
+		return nil, fmt.Errorf("%s: %w", msg, ErrText)
+	}
+	b, _ = textmode.Split(b)
+	b = TrimEOFs(b)
+	const eof = 0x1a
+	b = bytes.TrimRight(b, string(rune(eof)))
+	key := charsetKey(art, charset, b)
+	p, err := encodings.Transcode(b, key)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	return p, nil
+}
+
+// charsetKey returns the key of the text encoding of the text b of the artifact.
+func charsetKey(art *models.File, charset string, b []byte) string {
+	if c, ok := encodings.Find(charset); ok {
+		return c.Key
+	}
+	if key, ok := encodings.Rule(art.Platform.String, art.Section.String); ok {
+		return key
+	}
+	magic := strings.ToLower(strings.TrimSpace(art.FileMagicType.String))
+	if strings.Contains(magic, "utf-8") {
+		return encodings.UTF8
+	}
+	if d := encodings.Detect(b); d.Key != "" {
+		return d.Key
+	}
+	return encodings.CP437
+}
+
+// ansiEscape matches the ANSI escape sequences used for the colors and the cursor movements.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// Accessible returns the UTF-8 text b as a version that is friendly to screen readers.
+// The ANSI escape sequences are removed, and the decoration characters such as the box drawing,
+// blocks and symbols are removed from the ends of the lines and replaced with a space within them.
+// The lines of pure decoration are collapsed, along with the blank lines, into a single blank line.
+func Accessible(b []byte) []byte {
+	b = ansiEscape.ReplaceAll(b, nil)
+	edge := func(r rune) bool {
+		return unicode.IsSpace(r) || ornament(r) || strings.ContainsRune(`|#*=~_-+<>/\^`, r)
+	}
+	lines := strings.Split(string(b), "\n")
+	out := make([]string, 0, len(lines))
+	blank := true
+	for _, line := range lines {
+		line = strings.TrimFunc(line, edge)
+		if strings.IndexFunc(line, func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsDigit(r)
+		}) == -1 {
+			if !blank {
+				out = append(out, "")
+				blank = true
+			}
+			continue
+		}
+		line = strings.Map(func(r rune) rune {
+			if ornament(r) {
+				return ' '
+			}
+			return r
+		}, line)
+		out = append(out, strings.Join(strings.Fields(line), " "))
+		blank = false
+	}
+	if blank && len(out) > 0 {
+		out = out[:len(out)-1]
+	}
+	if len(out) == 0 {
+		return []byte{}
+	}
+	return []byte(strings.Join(out, "\n") + "\n")
+}
+
+// ornament returns true if the rune is a decoration character, such as
+// the box drawing, block elements, geometric shapes and other symbols.
+func ornament(r rune) bool {
+	const boxDrawing, geometricShapes = 0x2500, 0x25ff
+	if r >= boxDrawing && r <= geometricShapes {
+		return true
+	}
+	return unicode.Is(unicode.So, r)
+}
@@ -9,16 +9,22 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Defacto2/helper"
 	"github.com/Defacto2/server/handler/app"
+	"github.com/Defacto2/server/handler/download"
+	"github.com/Defacto2/server/handler/feed"
 	"github.com/Defacto2/server/handler/htmx"
 	"github.com/Defacto2/server/handler/releaser"
+	"github.com/Defacto2/server/handler/releaser/meta"
 	"github.com/Defacto2/server/handler/sitemap"
+	"github.com/Defacto2/server/internal/command"
 	"github.com/Defacto2/server/internal/config"
 	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/jobs"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/gorilla/sessions"
 	"github.com/labstack/echo-contrib/v5/session"
@@ -28,7 +34,9 @@ import (
 const code = http.StatusMovedPermanently
 
 // AppendFiles defines the file locations and routes for the web server.
+// The queue is the background job queue of the artifact assets, or nil in read-only mode.
 func (c *Configuration) AppendFiles(ctx context.Context, sl *slog.Logger, e *echo.Echo, db *sql.DB, public embed.FS,
+	queue *jobs.Queue,
 ) (*echo.Echo, error) {
 	const format = "files routes: %w"
 	if err := nils.Check(ctx, sl, e, db, public); err != nil {
@@ -38,12 +46,21 @@ func (c *Configuration) AppendFiles(ctx context.Context, sl *slog.Logger, e *ech
 		return nil, fmt.Errorf(format, nils.ErrEmbedFS)
 	}
 	app.Caching.Records(c.RecordCount)
-	dirs := app.Dirs{
-		Download:  dir.Directory(c.Environment.AbsDownload),
-		Preview:   dir.Directory(c.Environment.AbsPreview),
-		Thumbnail: dir.Directory(c.Environment.AbsThumbnail),
-		Extra:     dir.Directory(c.Environment.AbsExtra),
-		URI:       "", // URI is set later from route parameter
+	dirs := c.dirs()
+	dirs.Queue = queue
+	if err := app.ReleaserMeta(ctx, sl, db, !c.Environment.ReadOnly.Bool()); err != nil {
+		sl.Warn("releaser meta", slog.String("problem", "the compiled releaser lists will be used"),
+			slog.Any("error", err))
+	}
+	if !c.Environment.ReadOnly {
+		if err := app.ScenerIdentities(ctx, sl, db); err != nil {
+			sl.Warn("scener identities", slog.String("problem", "the scener pages will use the credit columns"),
+				slog.Any("error", err))
+		}
+		if err := app.ArtifactRelations(ctx, sl, db); err != nil {
+			sl.Warn("artifact relations", slog.String("problem", "the artifact pages will use the list columns"),
+				slog.Any("error", err))
+		}
 	}
 	nonce, err := c.nonce(e)
 	if err != nil {
@@ -63,6 +80,40 @@ func (c *Configuration) AppendFiles(ctx context.Context, sl *slog.Logger, e *ech
 	return e, nil
 }
 
+// dirs returns the directories of the artifact assets.
+func (c *Configuration) dirs() app.Dirs {
+	return app.Dirs{
+		Download:  dir.Directory(c.Environment.AbsDownload),
+		Preview:   dir.Directory(c.Environment.AbsPreview),
+		Thumbnail: dir.Directory(c.Environment.AbsThumbnail),
+		Extra:     dir.Directory(c.Environment.AbsExtra),
+		URI:       "", // URI is set later from route parameter
+		Queue:     nil,
+	}
+}
+
+// jobs returns the background job queue of the artifact assets and starts its workers,
+// which run until the context is cancelled.
+func (c *Configuration) jobs(ctx context.Context, sl *slog.Logger, db *sql.DB) *jobs.Queue {
+	dirs := c.dirs()
+	q := jobs.New(db, sl)
+	q.Commands(command.Dirs{
+		Download:  dirs.Download,
+		Preview:   dirs.Preview,
+		Thumbnail: dirs.Thumbnail,
+		Extra:     dirs.Extra,
+	})
+	q.Register(jobs.ReplacementZip, dirs.ReplacementZip)
+	q.Metadata(dirs.Download.Path(), dirs.Extra.Path())
+	go func() {
+		if err := q.Run(ctx); err != nil {
+			sl.Error("job queue", slog.String("problem", "the workers could not start"),
+				slog.Any("error", err))
+		}
+	}()
+	return q
+}
+
 // nonce configures and returns the session key for the cookie store.
 // If the read mode is enabled then an empty session key is returned.
 func (c *Configuration) nonce(e *echo.Echo) (string, error) {
@@ -249,16 +300,36 @@ func (c *Configuration) api(
 	apiGroup.GET("/sites", func(c *echo.Context) error { return app.SitesAPI(ctx, sl, c, db) })
 	apiGroup.GET("/boards", func(c *echo.Context) error { return app.BoardsAPI(ctx, sl, c, db) })
 	apiGroup.GET("/magazines", func(c *echo.Context) error { return app.MagazinesAPI(ctx, sl, c, db) })
-	apiGroup.GET("/releaser/:name", func(c *echo.Context) error { return app.ReleaserAPI(ctx, sl, c, db) })
+	apiGroup.GET("/releaser/:name", func(c *echo.Context) error {
+		if target, alias := meta.Alias(c.Param("name")); alias {
+			return c.Redirect(http.StatusMovedPermanently, app.APIBase+"/releaser/"+target)
+		}
+		return app.ReleaserAPI(ctx, sl, c, db)
+	})
 	apiGroup.GET("/artifacts", func(c *echo.Context) error { return app.ArtifactsAPI(ctx, sl, c, db) })
 	apiGroup.GET("/artifacts/new", func(c *echo.Context) error { return app.ArtifactsNewAPI(ctx, sl, c, db) })
+	apiGroup.GET("/artifacts/popular", func(c *echo.Context) error { return app.ArtifactsPopularAPI(ctx, sl, c, db) })
 	apiGroup.GET("/artifact/:id", func(c *echo.Context) error { return app.FileAPI(ctx, sl, c, db) })
+	apiGroup.GET("/artifact/:id/relations", func(c *echo.Context) error { return app.RelationsAPI(ctx, sl, c, db) })
+	apiGroup.GET("/artifact/:id/contents", func(c *echo.Context) error { return app.ContentsAPI(ctx, sl, c, db) })
+	apiGroup.GET("/artifact/:id/sauce", func(c *echo.Context) error { return app.SaucesAPI(ctx, sl, c, db) })
+	apiGroup.GET("/keywords", func(c *echo.Context) error { return app.KeywordsAPI(ctx, sl, c, db) })
 	apiGroup.GET("/sceners", func(c *echo.Context) error { return app.ScenersAPI(ctx, sl, c, db) })
 	apiGroup.GET("/sceners/artist", func(c *echo.Context) error { return app.ArtistsAPI(ctx, sl, c, db) })
 	apiGroup.GET("/sceners/coder", func(c *echo.Context) error { return app.CodersAPI(ctx, sl, c, db) })
 	apiGroup.GET("/sceners/musician", func(c *echo.Context) error { return app.MusiciansAPI(ctx, sl, c, db) })
 	apiGroup.GET("/sceners/writer", func(c *echo.Context) error { return app.WritersAPI(ctx, sl, c, db) })
 	apiGroup.GET("/scener/:name", func(c *echo.Context) error { return app.ScenerAPI(ctx, sl, c, db) })
+	apiGroup.GET("/search/texts", func(c *echo.Context) error { return app.TextsAPI(ctx, sl, c, db) })
+	apiGroup.GET("/changes", func(c *echo.Context) error { return app.ChangesAPI(ctx, sl, c, db) })
+	apiGroup.GET("/changes/atom", func(c *echo.Context) error {
+		a := feed.Changes(ctx, db, sl, app.APIBase+"/changes/atom")
+		b, err := a.Marshal()
+		if err != nil {
+			return fmt.Errorf("changes atom: %w", err)
+		}
+		return c.Blob(http.StatusOK, feed.MIMEAtom, b)
+	})
 
 	return e
 }
@@ -283,11 +354,23 @@ func (c *Configuration) website( //nolint:funlen
 	}
 	releaser := func(ec *echo.Context) error {
 		uri := ec.Param("id")
+		if target, alias := meta.Alias(uri); alias {
+			return ec.Redirect(http.StatusMovedPermanently, "/g/"+target)
+		}
 		if unwanted := ec.QueryString(); unwanted != "" {
 			return ec.Redirect(http.StatusMovedPermanently, "/g/"+uri)
 		}
 		return app.Releasers(ctx, sl, ec, db, uri, c.Public)
 	}
+	sums, err := download.NewSums(c.Environment.SigningKey.String())
+	switch {
+	case err != nil:
+		sl.Error(msg, slog.String("issue", "the signing key is invalid, signing of the checksums is disabled"),
+			slog.Any("error", err))
+		sums = &download.Sums{}
+	case !sums.Signing():
+		sl.Warn(msg, slog.String("issue", "no signing key is set, signing of the checksums is disabled"))
+	}
 	scener := func(ec *echo.Context) error {
 		uri := ec.Param("id")
 		if unwanted := ec.QueryString(); unwanted != "" {
@@ -345,8 +428,14 @@ func (c *Configuration) website( //nolint:funlen
 	})
 	s.GET("/compression", func(c *echo.Context) error { return app.Compression(sl, c) })
 	s.GET(Downloader, func(ec *echo.Context) error {
+		if id := ec.Param("id"); strings.HasSuffix(id, download.Sidecar) {
+			return app.Sidecar(ctx, sl, ec, db, id)
+		}
 		return app.Download(ctx, sl, ec, db, dir.Directory(c.Environment.AbsDownload))
 	})
+	s.GET(Downloader+"/*", func(ec *echo.Context) error {
+		return app.DownloadMember(ctx, sl, ec, db, dir.Directory(c.Environment.AbsDownload))
+	})
 	s.GET("/f/:id", artifact)
 	s.GET("/file/stats", func(ec *echo.Context) error {
 		return app.Categories(ctx, sl, ec, db, true)
@@ -372,6 +461,15 @@ func (c *Configuration) website( //nolint:funlen
 	s.GET("/ftp", func(c *echo.Context) error {
 		return app.FTP(ctx, sl, c, db)
 	})
+	s.GET("/feed/:format/files/:id", func(ec *echo.Context) error {
+		return dirs.Feed(ctx, sl, ec, db, app.FeedFiles)
+	})
+	s.GET("/feed/:format/g/:id", func(ec *echo.Context) error {
+		return dirs.Feed(ctx, sl, ec, db, app.FeedReleaser)
+	})
+	s.GET("/feed/:format/p/:id", func(ec *echo.Context) error {
+		return dirs.Feed(ctx, sl, ec, db, app.FeedScener)
+	})
 	s.GET("/g/:id", releaser)
 	s.GET("/history", func(c *echo.Context) error { return app.History(sl, c) })
 	s.GET("/interview", func(c *echo.Context) error { return app.Interview(sl, c) })
@@ -380,6 +478,20 @@ func (c *Configuration) website( //nolint:funlen
 			dir.Directory(c.Environment.AbsExtra),
 			dir.Directory(c.Environment.AbsDownload))
 	})
+	s.GET("/jsdos/bundle/:id", func(ec *echo.Context) error {
+		return app.DownloadJsDosBundle(ctx, sl, ec, db,
+			dir.Directory(c.Environment.AbsExtra),
+			dir.Directory(c.Environment.AbsDownload))
+	})
+	s.GET("/keywords", func(c *echo.Context) error {
+		return app.Keywords(ctx, sl, c, db)
+	})
+	s.GET("/keyword/:id", func(ec *echo.Context) error {
+		return app.Keyword(ctx, sl, ec, db, ec.Param("id"), "1")
+	})
+	s.GET("/keyword/:id/:page", func(ec *echo.Context) error {
+		return app.Keyword(ctx, sl, ec, db, ec.Param("id"), ec.Param("page"))
+	})
 	s.GET("/magazine", func(c *echo.Context) error {
 		return app.Magazine(ctx, sl, c, db)
 	})
@@ -412,6 +524,15 @@ func (c *Configuration) website( //nolint:funlen
 	s.GET("/scener", func(c *echo.Context) error {
 		return app.Scener(ctx, sl, c, db)
 	})
+	s.GET("/sha384sums", func(ec *echo.Context) error {
+		return app.Sums(ctx, sl, ec, db, sums)
+	})
+	s.GET("/sha384sums.pub", func(ec *echo.Context) error {
+		return app.SumsKey(sl, ec, sums)
+	})
+	s.GET("/sha384sums.sig", func(ec *echo.Context) error {
+		return app.SumsSignature(ctx, sl, ec, db, sums)
+	})
 	s.GET("/sum/:id", func(ec *echo.Context) error {
 		return app.Checksum(ctx, sl, ec, db, ec.Param("id"))
 	})
@@ -431,6 +552,11 @@ func (c *Configuration) website( //nolint:funlen
 	s.GET("/v/:id", func(ec *echo.Context) error {
 		return app.Inline(ctx, sl, ec, db, dir.Directory(c.Environment.AbsDownload))
 	})
+	s.GET("/utf8/:id", func(ec *echo.Context) error {
+		reader, _ := strconv.ParseBool(ec.QueryParam("reader"))
+		return app.Unicode(ctx, sl, ec, db,
+			dir.Directory(c.Environment.AbsDownload), dir.Directory(c.Environment.AbsExtra), reader)
+	})
 	return e
 }
 
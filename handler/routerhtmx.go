@@ -9,6 +9,7 @@ import (
 
 	"github.com/Defacto2/server/handler/htmx"
 	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/jobs"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/labstack/echo/v5"
 	"github.com/labstack/echo/v5/middleware"
@@ -20,6 +21,7 @@ import (
 const rateLimit = 2
 
 type configHtmx struct {
+	queue    *jobs.Queue // queue is the background job queue, or nil in read-only mode.
 	prodMode bool
 	download dir.Directory
 }
@@ -78,27 +80,27 @@ func (h configHtmx) append(ctx context.Context, sl *slog.Logger, e *echo.Echo, d
 	})
 	// htmx/uploader/advanced
 	upload.POST("/advanced", func(c *echo.Context) error {
-		return htmx.AdvancedSubmit(ctx, sl, c, db, h.download)
+		return htmx.AdvancedSubmit(ctx, sl, c, db, h.download, h.queue)
 	})
 	// htmx/uploader/image
 	upload.POST("/image", func(c *echo.Context) error {
-		return htmx.ImageSubmit(ctx, sl, c, db, h.download)
+		return htmx.ImageSubmit(ctx, sl, c, db, h.download, h.queue)
 	})
 	// htmx/uploader/intro
 	upload.POST("/intro", func(c *echo.Context) error {
-		return htmx.IntroSubmit(ctx, sl, c, db, h.download)
+		return htmx.IntroSubmit(ctx, sl, c, db, h.download, h.queue)
 	})
 	// htmx/uploader/magazine
 	upload.POST("/magazine", func(c *echo.Context) error {
-		return htmx.MagazineSubmit(ctx, sl, c, db, h.download)
+		return htmx.MagazineSubmit(ctx, sl, c, db, h.download, h.queue)
 	})
 	// htmx/uploader/text
 	upload.POST("/text", func(c *echo.Context) error {
-		return htmx.TextSubmit(ctx, sl, c, db, h.download)
+		return htmx.TextSubmit(ctx, sl, c, db, h.download, h.queue)
 	})
 	// htmx/uploader/trainer
 	upload.POST("/trainer", func(c *echo.Context) error {
-		return htmx.TrainerSubmit(ctx, sl, c, db, h.download)
+		return htmx.TrainerSubmit(ctx, sl, c, db, h.download, h.queue)
 	})
 	return e
 }
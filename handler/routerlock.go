@@ -8,8 +8,11 @@ import (
 
 	"github.com/Defacto2/server/handler/app"
 	"github.com/Defacto2/server/handler/htmx"
+	"github.com/Defacto2/server/handler/sess"
 	"github.com/Defacto2/server/internal/command"
+	"github.com/Defacto2/server/internal/dupe"
 	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
 	"github.com/labstack/echo/v5"
 )
 
@@ -30,6 +33,12 @@ const (
 	double  = 2
 )
 
+// audit returns a copy of the context that carries the Google account ID of the signed in editor,
+// which is logged to the audit trail with every change made to an artifact.
+func audit(ctx context.Context, c *echo.Context) context.Context {
+	return model.WithEditor(ctx, sess.Sub(c))
+}
+
 func (c *Configuration) lock(ctx context.Context, sl *slog.Logger, e *echo.Echo, db *sql.DB, dirs app.Dirs) *echo.Echo {
 	const format = "configuration router lock: %w"
 	if err := nils.Check(ctx, sl, e, db); err != nil {
@@ -66,7 +75,7 @@ func fixers(ctx context.Context, sl *slog.Logger, g *echo.Group, db *sql.DB) {
 		return app.Fixers(ctx, sl, c, db)
 	}
 	fixID := func(c *echo.Context) error {
-		return app.FixNumericSuffix(ctx, sl, c, db)
+		return app.FixNumericSuffix(audit(ctx, c), sl, c, db)
 	}
 	g.GET("/fixers", fixers)
 	g.POST("/fixers/fix/:id", fixID)
@@ -102,19 +111,19 @@ func creator(ctx context.Context, g *echo.Group, db *sql.DB) {
 	}
 	creator := g.Group("/creator")
 	creator.PATCH("/text", func(c *echo.Context) error {
-		return htmx.RecordCreatorText(ctx, c, db)
+		return htmx.RecordCreatorText(audit(ctx, c), c, db)
 	})
 	creator.PATCH("/ill", func(c *echo.Context) error {
-		return htmx.RecordCreatorIll(ctx, c, db)
+		return htmx.RecordCreatorIll(audit(ctx, c), c, db)
 	})
 	creator.PATCH("/prog", func(c *echo.Context) error {
-		return htmx.RecordCreatorProg(ctx, c, db)
+		return htmx.RecordCreatorProg(audit(ctx, c), c, db)
 	})
 	creator.PATCH("/audio", func(c *echo.Context) error {
-		return htmx.RecordCreatorAudio(ctx, c, db)
+		return htmx.RecordCreatorAudio(audit(ctx, c), c, db)
 	})
 	creator.PATCH("/reset", func(c *echo.Context) error {
-		return htmx.RecordCreatorReset(ctx, c, db)
+		return htmx.RecordCreatorReset(audit(ctx, c), c, db)
 	})
 }
 
@@ -124,13 +133,13 @@ func date(ctx context.Context, g *echo.Group, db *sql.DB) {
 	}
 	date := g.Group("/date")
 	date.PATCH("", func(c *echo.Context) error {
-		return htmx.RecordDateIssued(ctx, c, db)
+		return htmx.RecordDateIssued(audit(ctx, c), c, db)
 	})
 	date.PATCH("/reset", func(ec *echo.Context) error {
-		return htmx.RecordDateIssuedReset(ctx, ec, db, "artifact-editor-date-resetter")
+		return htmx.RecordDateIssuedReset(audit(ctx, ec), ec, db, "artifact-editor-date-resetter")
 	})
 	date.PATCH("/lastmod", func(ec *echo.Context) error {
-		return htmx.RecordDateIssuedReset(ctx, ec, db, "artifact-editor-date-lastmodder")
+		return htmx.RecordDateIssuedReset(audit(ctx, ec), ec, db, "artifact-editor-date-lastmodder")
 	})
 }
 
@@ -138,100 +147,186 @@ func editor(ctx context.Context, sl *slog.Logger, g *echo.Group, db *sql.DB, dir
 	if err := nils.Check(ctx, sl, g, db); err != nil {
 		panic(fmt.Errorf("%w for editor router", err))
 	}
+	g.POST("/bulk", func(c *echo.Context) error {
+		return htmx.BulkEdit(audit(ctx, c), sl, c, db)
+	})
 	g.DELETE("/delete/forever/:key", func(c *echo.Context) error {
-		return htmx.DeleteForever(ctx, sl, c, db, c.Param("key"))
+		return htmx.DeleteForever(audit(ctx, c), sl, c, db, c.Param("key"))
 	})
 	g.PATCH("/16colors", func(c *echo.Context) error {
-		return htmx.Record16Colors(ctx, c, db)
+		return htmx.Record16Colors(audit(ctx, c), c, db)
 	})
 	g.PATCH("/classifications", func(c *echo.Context) error {
-		return htmx.RecordClassification(ctx, sl, c, db)
+		return htmx.RecordClassification(audit(ctx, c), sl, c, db)
 	})
 	g.PATCH("/comment", func(c *echo.Context) error {
-		return htmx.RecordComment(ctx, c, db)
+		return htmx.RecordComment(audit(ctx, c), c, db)
 	})
 	g.PATCH("/comment/reset", func(c *echo.Context) error {
-		return htmx.RecordCommentReset(ctx, c, db)
+		return htmx.RecordCommentReset(audit(ctx, c), c, db)
 	})
 	g.PATCH("/demozoo", func(c *echo.Context) error {
-		return htmx.RecordDemozoo(ctx, c, db)
+		return htmx.RecordDemozoo(audit(ctx, c), c, db)
 	})
 	g.PATCH("/filename", func(c *echo.Context) error {
-		return htmx.RecordFilename(ctx, c, db)
+		return htmx.RecordFilename(audit(ctx, c), c, db)
 	})
 	g.PATCH("/filename/reset", func(c *echo.Context) error {
-		return htmx.RecordFilenameReset(ctx, c, db)
+		return htmx.RecordFilenameReset(audit(ctx, c), c, db)
 	})
 	g.PATCH("/github", func(c *echo.Context) error {
-		return htmx.RecordGitHub(ctx, c, db)
+		return htmx.RecordGitHub(audit(ctx, c), c, db)
 	})
 	g.PATCH("/links", htmx.RecordLinks)
 	g.PATCH("/links/reset", func(c *echo.Context) error {
-		return htmx.RecordLinksReset(ctx, c, db)
+		return htmx.RecordLinksReset(audit(ctx, c), c, db)
 	})
 	g.PATCH("/platform", func(c *echo.Context) error {
-		return app.PlatformEdit(ctx, sl, c, db)
+		return app.PlatformEdit(audit(ctx, c), sl, c, db)
 	})
 	g.PATCH("/platform+tag", app.PlatformTagInfo)
 	g.PATCH("/pouet", func(c *echo.Context) error {
-		return htmx.RecordPouet(ctx, c, db)
-	})
-	g.PATCH("/relations", func(c *echo.Context) error {
-		return htmx.RecordRelations(ctx, c, db)
+		return htmx.RecordPouet(audit(ctx, c), c, db)
 	})
 	g.PATCH("/releasers", func(c *echo.Context) error {
-		return htmx.RecordReleasers(ctx, c, db)
+		return htmx.RecordReleasers(audit(ctx, c), c, db)
 	})
 	g.PATCH("/releasers/reset", func(c *echo.Context) error {
-		return htmx.RecordReleasersReset(ctx, c, db)
+		return htmx.RecordReleasersReset(audit(ctx, c), c, db)
+	})
+	g.PATCH("/revert/:id", func(c *echo.Context) error {
+		return htmx.RecordRevert(audit(ctx, c), sl, c, db)
 	})
-	g.PATCH("/sites", func(c *echo.Context) error {
-		return htmx.RecordSites(ctx, c, db)
+	g.PATCH("/sauce", func(c *echo.Context) error {
+		return htmx.RecordSauce(audit(ctx, c), c, db)
 	})
 	g.PATCH("/tag", func(c *echo.Context) error {
-		return app.TagEdit(ctx, sl, c, db)
+		return app.TagEdit(audit(ctx, c), sl, c, db)
 	})
 	g.PATCH("/tag/info", app.TagInfo)
 	g.PATCH("/title", func(c *echo.Context) error {
-		return htmx.RecordTitle(ctx, c, db)
+		return htmx.RecordTitle(audit(ctx, c), c, db)
 	})
 	g.PATCH("/title/reset", func(c *echo.Context) error {
-		return htmx.RecordTitleReset(ctx, c, db)
+		return htmx.RecordTitleReset(audit(ctx, c), c, db)
 	})
 	g.PATCH("/virustotal", func(c *echo.Context) error {
-		return htmx.RecordVirusTotal(ctx, c, db)
+		return htmx.RecordVirusTotal(audit(ctx, c), c, db)
 	})
 	g.PATCH("/ymd", func(c *echo.Context) error {
-		return app.YMDEdit(ctx, c, db)
+		return app.YMDEdit(audit(ctx, c), c, db)
 	})
 	g.PATCH("/youtube", func(c *echo.Context) error {
-		return htmx.RecordYouTube(ctx, c, db)
+		return htmx.RecordYouTube(audit(ctx, c), c, db)
+	})
+
+	gph := g.Group("/graph/:id")
+	gph.POST("/relation", func(c *echo.Context) error {
+		return htmx.RecordRelationAdd(audit(ctx, c), sl, c, db)
+	})
+	gph.DELETE("/relation/:rid", func(c *echo.Context) error {
+		return htmx.RecordRelationDelete(audit(ctx, c), sl, c, db)
+	})
+	gph.POST("/link", func(c *echo.Context) error {
+		return htmx.RecordLinkAdd(audit(ctx, c), sl, c, db)
+	})
+	gph.DELETE("/link/:rid", func(c *echo.Context) error {
+		return htmx.RecordLinkDelete(audit(ctx, c), sl, c, db)
+	})
+
+	kw := g.Group("/keyword/:id")
+	kw.POST("", func(c *echo.Context) error {
+		return htmx.RecordKeywordAdd(audit(ctx, c), sl, c, db)
+	})
+	kw.DELETE("/:slug", func(c *echo.Context) error {
+		return htmx.RecordKeywordDelete(audit(ctx, c), sl, c, db)
+	})
+
+	kws := g.Group("/keywords")
+	kws.POST("", func(c *echo.Context) error {
+		return htmx.KeywordSave(audit(ctx, c), sl, c, db)
+	})
+	kws.DELETE("/:slug", func(c *echo.Context) error {
+		return htmx.KeywordDelete(audit(ctx, c), sl, c, db)
+	})
+
+	dup := g.Group("/duplicates")
+	dup.POST("/link", func(c *echo.Context) error {
+		return htmx.DuplicatesLink(audit(ctx, c), sl, c, db)
+	})
+	dup.POST("/scan", func(c *echo.Context) error {
+		return htmx.DuplicatesScan(ctx, sl, c, db, dupe.Sources{
+			Download:  dirs.Download.Path(),
+			Extra:     dirs.Extra.Path(),
+			Thumbnail: dirs.Thumbnail.Path(),
+		})
+	})
+
+	job := g.Group("/jobs")
+	job.PATCH("/cancel/:id", func(c *echo.Context) error {
+		return htmx.JobCancel(ctx, sl, c, db, dirs.Queue)
+	})
+	job.PATCH("/requeue/:id", func(c *echo.Context) error {
+		return htmx.JobRequeue(ctx, sl, c, db, dirs.Queue)
+	})
+
+	rel := g.Group("/releaser-meta")
+	rel.POST("", func(c *echo.Context) error {
+		return htmx.ReleaserMetaSave(audit(ctx, c), sl, c, db)
+	})
+	rel.DELETE("/:slug", func(c *echo.Context) error {
+		return htmx.ReleaserMetaDelete(audit(ctx, c), sl, c, db)
+	})
+
+	ren := g.Group("/releaser-rename")
+	ren.POST("", func(c *echo.Context) error {
+		return htmx.ReleaserRename(audit(ctx, c), sl, c, db)
+	})
+	ren.DELETE("/alias/:slug", func(c *echo.Context) error {
+		return htmx.ReleaserAliasDelete(audit(ctx, c), sl, c, db)
+	})
+
+	scn := g.Group("/scener-identity")
+	scn.POST("", func(c *echo.Context) error {
+		return htmx.ScenerIdentitySave(audit(ctx, c), sl, c, db)
+	})
+	scn.POST("/claim", func(c *echo.Context) error {
+		return htmx.ScenerCreditsClaim(audit(ctx, c), sl, c, db)
+	})
+	scn.POST("/sync", func(c *echo.Context) error {
+		return htmx.ScenerIdentitySync(audit(ctx, c), sl, c, db)
+	})
+	scn.DELETE("/:slug", func(c *echo.Context) error {
+		return htmx.ScenerIdentityDelete(audit(ctx, c), sl, c, db)
 	})
 
 	emu := g.Group("/emulate")
 	emu.PATCH("/broken/:id", func(c *echo.Context) error {
-		return htmx.RecordEmulateBroken(ctx, c, db)
+		return htmx.RecordEmulateBroken(audit(ctx, c), c, db)
 	})
 	emu.PATCH("/runprogram/:id", func(c *echo.Context) error {
-		return htmx.RecordEmulateRunProgram(ctx, c, db)
+		return htmx.RecordEmulateRunProgram(audit(ctx, c), c, db)
 	})
 	emu.PATCH("/machine/:id", func(c *echo.Context) error {
-		return htmx.RecordEmulateMachine(ctx, c, db)
+		return htmx.RecordEmulateMachine(audit(ctx, c), c, db)
 	})
 	emu.PATCH("/cpu/:id", func(c *echo.Context) error {
-		return htmx.RecordEmulateCPU(ctx, c, db)
+		return htmx.RecordEmulateCPU(audit(ctx, c), c, db)
 	})
 	emu.PATCH("/sfx/:id", func(c *echo.Context) error {
-		return htmx.RecordEmulateSFX(ctx, c, db)
+		return htmx.RecordEmulateSFX(audit(ctx, c), c, db)
 	})
 	emu.PATCH("/umb/:id", func(c *echo.Context) error {
-		return htmx.RecordEmulateUMB(ctx, c, db)
+		return htmx.RecordEmulateUMB(audit(ctx, c), c, db)
 	})
 	emu.PATCH("/ems/:id", func(c *echo.Context) error {
-		return htmx.RecordEmulateEMS(ctx, c, db)
+		return htmx.RecordEmulateEMS(audit(ctx, c), c, db)
 	})
 	emu.PATCH("/xms/:id", func(c *echo.Context) error {
-		return htmx.RecordEmulateXMS(ctx, c, db)
+		return htmx.RecordEmulateXMS(audit(ctx, c), c, db)
+	})
+	emu.PATCH("/settings/:id", func(c *echo.Context) error {
+		return htmx.RecordEmulateSettings(audit(ctx, c), c, db)
 	})
 
 	// these POSTs should only be used for editor, htmx file uploads,
@@ -239,13 +334,13 @@ func editor(ctx context.Context, sl *slog.Logger, g *echo.Group, db *sql.DB, dir
 	upload := g.Group("/upload")
 	// /upload/file
 	upload.POST("/file", func(c *echo.Context) error {
-		return htmx.UploadReplacement(ctx, sl, c, db, dirs.Download, dirs.Extra)
+		return htmx.UploadReplacement(audit(ctx, c), sl, c, db, dirs.Download, dirs.Extra, dirs.Queue)
 	})
 	// /upload/preview
 	upload.POST("/preview", func(c *echo.Context) error { //nolint:contextcheck
 		ctx, cancel := context.WithTimeout(context.Background(), timeout*double)
 		defer cancel()
-		return htmx.UploadPreview(ctx, sl, c, dirs.Preview, dirs.Thumbnail)
+		return htmx.UploadPreview(audit(ctx, c), sl, c, db, dirs.Preview, dirs.Thumbnail)
 	})
 	paths := command.Dirs{
 		Download:  dirs.Download,
@@ -271,7 +366,10 @@ func editor(ctx context.Context, sl *slog.Logger, g *echo.Group, db *sql.DB, dir
 	})
 	readme := g.Group("/readme")
 	readme.PATCH("/disable/:id", func(c *echo.Context) error {
-		return htmx.RecordReadmeDisable(ctx, c, db)
+		return htmx.RecordReadmeDisable(audit(ctx, c), c, db)
+	})
+	readme.PATCH("/encoding/:id", func(c *echo.Context) error {
+		return htmx.RecordEncoding(audit(ctx, c), c, db)
 	})
 	// /editor/readme/copy
 	readme.PATCH("/copy/:unid/:path", func(c *echo.Context) error {
@@ -279,15 +377,15 @@ func editor(ctx context.Context, sl *slog.Logger, g *echo.Group, db *sql.DB, dir
 	})
 	// /editor/readme/preview
 	readme.PATCH("/preview/:unid/:path", func(c *echo.Context) error {
-		return htmx.RecordReadmeImager(ctx, sl, c, false, paths)
+		return htmx.RecordReadmeImager(audit(ctx, c), sl, c, db, false, dirs.Queue)
 	})
 	// /editor/readme/preview-amiga
 	readme.PATCH("/preview-amiga/:unid/:path", func(c *echo.Context) error {
-		return htmx.RecordReadmeImager(ctx, sl, c, true, paths)
+		return htmx.RecordReadmeImager(audit(ctx, c), sl, c, db, true, dirs.Queue)
 	})
 	// /editor/readme/preview-binary
 	readme.PATCH("/preview-binary/:unid/:path", func(c *echo.Context) error {
-		return htmx.RecordBinTextImager(ctx, sl, c, paths)
+		return htmx.RecordBinTextImager(audit(ctx, c), sl, c, db, dirs.Queue)
 	})
 	readme.DELETE("/:unid", func(c *echo.Context) error {
 		return htmx.RecordReadmeDeleter(c, dirs.Extra)
@@ -295,62 +393,62 @@ func editor(ctx context.Context, sl *slog.Logger, g *echo.Group, db *sql.DB, dir
 	pre := g.Group("/preview")
 	// /editor/preview/copy
 	pre.PATCH("/copy/:unid/:path", func(c *echo.Context) error {
-		return htmx.RecordImageCopier(ctx, sl, c, paths)
+		return htmx.RecordImageCopier(audit(ctx, c), sl, c, db, dirs.Queue)
 	})
 	pre.PATCH("/crop11/:unid", func(c *echo.Context) error {
-		return htmx.RecordImageCropper(ctx, sl, c, command.SquareTop, paths)
+		return htmx.RecordImageCropper(audit(ctx, c), sl, c, db, command.SquareTop, paths)
 	})
 	pre.PATCH("/crop43/:unid", func(c *echo.Context) error {
-		return htmx.RecordImageCropper(ctx, sl, c, command.FourThree, paths)
+		return htmx.RecordImageCropper(audit(ctx, c), sl, c, db, command.FourThree, paths)
 	})
 	pre.PATCH("/crop12/:unid", func(c *echo.Context) error {
-		return htmx.RecordImageCropper(ctx, sl, c, command.OneTwo, paths)
+		return htmx.RecordImageCropper(audit(ctx, c), sl, c, db, command.OneTwo, paths)
 	})
 	pre.PATCH("/remove/:unid", func(c *echo.Context) error {
-		return htmx.RecordImagesDeleter(c, dirs.Preview)
+		return htmx.RecordImagesDeleter(audit(ctx, c), c, db, paths, model.Preview)
 	})
 
 	thumb := g.Group("/thumbnail")
 	thumb.PATCH("/copy/:unid/:path", func(c *echo.Context) error {
-		return htmx.RecordImageCopier(ctx, sl, c, paths)
+		return htmx.RecordImageCopier(audit(ctx, c), sl, c, db, dirs.Queue)
 	})
 	thumb.PATCH("/top/:unid", func(c *echo.Context) error {
-		return htmx.RecordThumbAlignment(ctx, sl, c, command.Top, paths)
+		return htmx.RecordThumbAlignment(audit(ctx, c), sl, c, db, command.Top, paths)
 	})
 	thumb.PATCH("/middle/:unid", func(c *echo.Context) error {
-		return htmx.RecordThumbAlignment(ctx, sl, c, command.Middle, paths)
+		return htmx.RecordThumbAlignment(audit(ctx, c), sl, c, db, command.Middle, paths)
 	})
 	thumb.PATCH("/bottom/:unid", func(c *echo.Context) error {
-		return htmx.RecordThumbAlignment(ctx, sl, c, command.Bottom, paths)
+		return htmx.RecordThumbAlignment(audit(ctx, c), sl, c, db, command.Bottom, paths)
 	})
 	thumb.PATCH("/left/:unid", func(c *echo.Context) error {
-		return htmx.RecordThumbAlignment(ctx, sl, c, command.Left, paths)
+		return htmx.RecordThumbAlignment(audit(ctx, c), sl, c, db, command.Left, paths)
 	})
 	thumb.PATCH("/right/:unid", func(c *echo.Context) error {
-		return htmx.RecordThumbAlignment(ctx, sl, c, command.Right, paths)
+		return htmx.RecordThumbAlignment(audit(ctx, c), sl, c, db, command.Right, paths)
 	})
 	thumb.PATCH("/pixel/:unid", func(c *echo.Context) error { //nolint:contextcheck
 		ctx, cancel := context.WithTimeout(context.Background(), timeout*double)
 		defer cancel()
-		return htmx.RecordThumb(ctx, sl, c, command.Pixel, paths)
+		return htmx.RecordThumb(audit(ctx, c), sl, c, db, command.Pixel, paths)
 	})
 	thumb.PATCH("/photo/:unid", func(c *echo.Context) error { //nolint:contextcheck
 		ctx, cancel := context.WithTimeout(context.Background(), timeout*double)
 		defer cancel()
-		return htmx.RecordThumb(ctx, sl, c, command.Photo, paths)
+		return htmx.RecordThumb(audit(ctx, c), sl, c, db, command.Photo, paths)
 	})
 	thumb.PATCH("/remove/:unid", func(c *echo.Context) error {
-		return htmx.RecordImagesDeleter(c, dirs.Thumbnail)
+		return htmx.RecordImagesDeleter(audit(ctx, c), c, db, paths, model.Thumbnail)
 	})
 
 	imgs := g.Group("/images")
 	imgs.PATCH("/pixelate/:unid", func(c *echo.Context) error { //nolint:contextcheck
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
 		defer cancel()
-		return htmx.RecordImagePixelator(ctx, c, dirs.Preview, dirs.Thumbnail)
+		return htmx.RecordImagePixelator(audit(ctx, c), c, db, dirs.Preview, dirs.Thumbnail)
 	})
 	imgs.PATCH("/remove/:unid", func(c *echo.Context) error {
-		return htmx.RecordImagesDeleter(c, dirs.Preview, dirs.Thumbnail)
+		return htmx.RecordImagesDeleter(audit(ctx, c), c, db, paths, model.Preview, model.Thumbnail)
 	})
 }
 
@@ -358,6 +456,10 @@ func get(ctx context.Context, sl *slog.Logger, g *echo.Group, db *sql.DB, dirs a
 	if err := nils.Check(ctx, sl, g, db); err != nil {
 		panic(fmt.Errorf("%w for get router", err))
 	}
+	g.GET("/bulk",
+		func(ec *echo.Context) error {
+			return app.Bulk(sl, ec)
+		})
 	g.GET("/deletions",
 		func(ec *echo.Context) error {
 			return app.Deletions(ctx, sl, ec, db, "1")
@@ -366,6 +468,70 @@ func get(ctx context.Context, sl *slog.Logger, g *echo.Group, db *sql.DB, dirs a
 		func(ec *echo.Context) error {
 			return app.GetDemozooParam(ctx, sl, ec, db, dirs.Download)
 		})
+	g.GET("/jobs",
+		func(ec *echo.Context) error {
+			return app.Jobs(sl, ec)
+		})
+	g.GET("/jobs/list",
+		func(ec *echo.Context) error {
+			return htmx.JobList(ctx, sl, ec, db)
+		})
+	g.GET("/jobs/poll/:id",
+		func(ec *echo.Context) error {
+			return htmx.JobPoll(ctx, ec, db)
+		})
+	g.GET("/releaser-meta",
+		func(ec *echo.Context) error {
+			return app.ReleaserMetaEdit(sl, ec)
+		})
+	g.GET("/releaser-meta/list",
+		func(ec *echo.Context) error {
+			return htmx.ReleaserMetaList(sl, ec)
+		})
+	g.GET("/releaser-meta/edit",
+		func(ec *echo.Context) error {
+			return htmx.ReleaserMetaForm(sl, ec)
+		})
+	g.GET("/releaser-meta/edit/:slug",
+		func(ec *echo.Context) error {
+			return htmx.ReleaserMetaForm(sl, ec)
+		})
+	g.GET("/releaser-rename",
+		func(ec *echo.Context) error {
+			return app.ReleaserRenameEdit(sl, ec)
+		})
+	g.GET("/scener-identity",
+		func(ec *echo.Context) error {
+			return app.ScenerIdentityEdit(ctx, sl, ec, db)
+		})
+	g.GET("/keywords",
+		func(ec *echo.Context) error {
+			return app.KeywordsEdit(ctx, sl, ec, db)
+		})
+	g.GET("/duplicates",
+		func(ec *echo.Context) error {
+			return app.Duplicates(ctx, sl, ec, db)
+		})
+	g.GET("/encodings",
+		func(ec *echo.Context) error {
+			return app.Encodings(ctx, sl, ec, db)
+		})
+	g.GET("/trends",
+		func(ec *echo.Context) error {
+			return app.Trends(ctx, sl, ec, db)
+		})
+	g.GET("/keyword/:id",
+		func(ec *echo.Context) error {
+			return htmx.RecordKeywords(ctx, sl, ec, db)
+		})
+	g.GET("/graph/:id",
+		func(ec *echo.Context) error {
+			return htmx.RecordGraph(ctx, sl, ec, db)
+		})
+	g.GET("/history/:id",
+		func(ec *echo.Context) error {
+			return htmx.RecordHistory(ctx, sl, ec, db)
+		})
 	g.GET("/for-approval",
 		func(ec *echo.Context) error {
 			return app.ForApproval(ctx, sl, ec, db, "1")
@@ -382,13 +548,13 @@ func online(ctx context.Context, g *echo.Group, db *sql.DB) {
 	}
 	online := g.Group("/online")
 	online.PATCH("/true", func(ec *echo.Context) error {
-		return htmx.RecordToggle(ctx, ec, db, true)
+		return htmx.RecordToggle(audit(ctx, ec), ec, db, true)
 	})
 	online.PATCH("/false", func(ec *echo.Context) error {
-		return htmx.RecordToggle(ctx, ec, db, false)
+		return htmx.RecordToggle(audit(ctx, ec), ec, db, false)
 	})
 	online.GET("/true/:id", func(ec *echo.Context) error {
-		return htmx.RecordToggleByID(ctx, ec, db, ec.Param("id"), true)
+		return htmx.RecordToggleByID(audit(ctx, ec), ec, db, ec.Param("id"), true)
 	})
 }
 
@@ -21,3 +21,13 @@ func Editor(c *echo.Context) bool {
 	}
 	return false
 }
+
+// Sub returns the unique Google account ID of the signed in user, or an empty string.
+func Sub(c *echo.Context) string {
+	sess, err := session.Get(Name, c)
+	if err != nil {
+		return ""
+	}
+	id, _ := sess.Values["sub"].(string)
+	return id
+}
@@ -1,7 +1,11 @@
 // Package site proves links and titles for recommended websites.
 package site
 
-import "sort"
+import (
+	"sort"
+
+	"github.com/Defacto2/server/handler/releaser/meta"
+)
 
 const (
 	inqAD     = "INQ ad"
@@ -1298,6 +1302,12 @@ var websites = Groups{
 // It returns an empty string if the uri is not known.
 func Find(uri string) []Website {
 	sites, groupExists := websites[URI(uri)]
+	if r, loaded := meta.Find(uri); loaded {
+		sites, groupExists = make([]Website, len(r.Websites)), len(r.Websites) > 0
+		for i, w := range r.Websites {
+			sites[i] = Website{URL: w.URL, Name: w.Name, NotWorking: w.NotWorking}
+		}
+	}
 	if !groupExists {
 		return []Website{}
 	}
@@ -1307,3 +1317,8 @@ func Find(uri string) []Website {
 	})
 	return sites
 }
+
+// Compiled returns the compiled list of the websites, which is used to seed the releasers metadata.
+func Compiled() Groups {
+	return websites
+}
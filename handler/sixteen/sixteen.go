@@ -2,6 +2,8 @@
 // [16colors]: https://16colo.rs
 package sixteen
 
+import "github.com/Defacto2/server/handler/releaser/meta"
+
 // URI is the URL slug of the releaser.
 type URI string
 
@@ -43,5 +45,13 @@ var groups = Groups{
 // Find returns the 16colors group tag for the given releaser uri.
 // If the releaser is not found, an empty string is returned.
 func Find(uri string) GroupTag {
+	if r, loaded := meta.Find(uri); loaded {
+		return GroupTag(r.Sixteen)
+	}
 	return groups[uri]
 }
+
+// Compiled returns the compiled list of the 16colors group tags, which is used to seed the releasers metadata.
+func Compiled() Groups {
+	return groups
+}
@@ -11,6 +11,7 @@ import (
 	"strings"
 
 	"github.com/Defacto2/server/handler/releaser"
+	"github.com/Defacto2/server/handler/releaser/meta"
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/gomarkdown/markdown"
 	"github.com/gomarkdown/markdown/html"
@@ -570,6 +571,16 @@ func (id ID) String(sl *slog.Logger, fs embed.FS) string {
 
 // URI returns the URIs of the tidbit.
 func (id ID) URI() []URI {
+	if slugs, loaded := meta.Tidbit(int(id)); loaded {
+		if len(slugs) == 0 {
+			return nil
+		}
+		uris := make([]URI, len(slugs))
+		for i, slug := range slugs {
+			uris[i] = URI(slug)
+		}
+		return uris
+	}
 	if x := groups[id]; x != nil {
 		return x
 	}
@@ -612,6 +623,12 @@ func (id ID) URL(uri string) template.HTML {
 // The ID can also be used to get the URIs of the tidbit.
 func Find(uri string) []ID {
 	ids := []ID{}
+	if r, loaded := meta.Find(uri); loaded {
+		for _, id := range r.Tidbits {
+			ids = append(ids, ID(id))
+		}
+		return ids
+	}
 	for id, uris := range groups {
 		for val := range slices.Values(uris) {
 			if val == URI(uri) {
@@ -627,6 +644,9 @@ func Find(uri string) []ID {
 // The ID returned can be used in a string conversion to get the description.
 // The ID can also be used to get the URIs of the tidbit.
 func Missing(uri string) bool {
+	if r, loaded := meta.Find(uri); loaded {
+		return len(r.Tidbits) == 0
+	}
 	for _, uris := range groups {
 		for val := range slices.Values(uris) {
 			if val == URI(uri) {
@@ -636,3 +656,8 @@ func Missing(uri string) bool {
 	}
 	return true
 }
+
+// Compiled returns the compiled list of the tidbits, which is used to seed the releasers metadata.
+func Compiled() Tibits {
+	return groups
+}
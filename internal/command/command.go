@@ -58,20 +58,19 @@ type Dirs struct {
 // "UNRAR 6.24 freeware, Copyright (c) 1993-2023 Alexander Roshal".
 
 const (
-	Arc      = "arc"      // Arc is the arc decompression command.
-	Arj      = "arj"      // Arj is the arj decompression command.
-	Ansilove = "ansilove" // Ansilove is the ansilove text to image command.
-	Cwebp    = "cwebp"    // Cwebp is the Google create webp command.
-	Gwebp    = "gif2webp" // Gwebp is the Google gif to webp command.
-	HWZip    = "hwzip"    // Hwzip the zip decompression command for files using obsolete methods.
-	Lha      = "lha"      // Lha is the lha/lzh decompression command.
-	Magick   = "magick"   // Magick is the ImageMagick v7+ command.
-	Optipng  = "optipng"  // Optipng is the PNG optimizer command.
-	Tar      = "tar"      // Tar is the tar decompression command.
-	Unrar    = "unrar"    // Unrar is the rar decompression command.
-	Unzip    = "unzip"    // Unzip is the zip decompression command.
-	Zip7     = "7zz"      // Zip7 is the 7-Zip decompression command.
-	ZipInfo  = "zipinfo"  // ZipInfo is the zip information command.
+	Arc     = "arc"      // Arc is the arc decompression command.
+	Arj     = "arj"      // Arj is the arj decompression command.
+	Cwebp   = "cwebp"    // Cwebp is the Google create webp command.
+	Gwebp   = "gif2webp" // Gwebp is the Google gif to webp command.
+	HWZip   = "hwzip"    // Hwzip the zip decompression command for files using obsolete methods.
+	Lha     = "lha"      // Lha is the lha/lzh decompression command.
+	Magick  = "magick"   // Magick is the ImageMagick v7+ command.
+	Optipng = "optipng"  // Optipng is the PNG optimizer command.
+	Tar     = "tar"      // Tar is the tar decompression command.
+	Unrar   = "unrar"    // Unrar is the rar decompression command.
+	Unzip   = "unzip"    // Unzip is the zip decompression command.
+	Zip7    = "7zz"      // Zip7 is the 7-Zip decompression command.
+	ZipInfo = "zipinfo"  // ZipInfo is the zip information command.
 )
 
 // Lookups returns a list of the execute command names used by the application.
@@ -79,7 +78,6 @@ func Lookups() []string {
 	return []string{
 		Arc,
 		Arj,
-		Ansilove,
 		Cwebp,
 		Gwebp,
 		HWZip,
@@ -99,7 +97,6 @@ func Infos() []string {
 	return []string{
 		"archive utility ver 5+",
 		"arj32 ver 3+",
-		"ansilove/c ver 4+",
 		"Google WebP ver 1+",
 		"Google GIF to WebP ver 1+",
 		"HWZip ver 2+",
@@ -1,8 +1,8 @@
 package command
 
 // Package file images.go contains the image conversion functions for
-// converting images to PNG and WebP formats using ANSILOVE, ImageMagick
-// and other command-line tools.
+// converting images to PNG and WebP formats using the native text mode renderer,
+// ImageMagick and other command-line tools.
 
 import (
 	"bufio"
@@ -19,15 +19,18 @@ import (
 	"github.com/Defacto2/helper"
 	"github.com/Defacto2/magicnumber"
 	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/encodings"
 	"github.com/Defacto2/server/internal/logs"
 	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/textmode"
 	"golang.org/x/sync/errgroup"
 )
 
 const (
-	AnsiCap = 350000    // ANSICap is the maximum file size in bytes for an ANSI encoded text file.
-	X400    = "400x400" // X400 returns args 400 x 400 pixel image size
-	argCap  = 2         // argCap is the fixed buffer size for command arguments (source + destination)
+	AnsiCap      = 350000    // ANSICap is the maximum file size in bytes for an ANSI encoded text file.
+	TextmodeRows = 1000      // TextmodeRows is the maximum number of rows of text rendered for a preview.
+	X400         = "400x400" // X400 returns args 400 x 400 pixel image size
+	argCap       = 2         // argCap is the fixed buffer size for command arguments (source + destination)
 )
 
 const (
@@ -113,7 +116,12 @@ func ImagesPixelate(ctx context.Context, unid string, dirs ...string) error {
 				}
 				return fmt.Errorf(format, "stat image", name, err)
 			}
-
+			if Native() {
+				if err := pixelateFile(name); err != nil {
+					return fmt.Errorf(format, "native", name, err)
+				}
+				continue
+			}
 			flags := Args{}
 			flags.Pixelate()
 			// construct ordered command arguments: [input, flags..., output]
@@ -138,6 +146,17 @@ const (
 	Photo              // Photographs or images with gradients
 )
 
+// String returns the name of the thumbnail type.
+func (thumb Thumb) String() string {
+	switch thumb {
+	case Pixel:
+		return "pixel"
+	case Photo:
+		return "photo"
+	}
+	return ""
+}
+
 // Thumbs creates a thumbnail image from the corresponding preview image based on the thumb type.
 func (dir Dirs) Thumbs(ctx context.Context, sl *slog.Logger, unid string, thumb Thumb) error {
 	const format = "thumb creator %s: %w"
@@ -192,6 +211,23 @@ const (
 	Right               // Right uses the right alignment of the preview image
 )
 
+// String returns the name of the alignment.
+func (align Align) String() string {
+	switch align {
+	case Top:
+		return "top"
+	case Middle:
+		return "middle"
+	case Bottom:
+		return "bottom"
+	case Left:
+		return "left"
+	case Right:
+		return "right"
+	}
+	return ""
+}
+
 // Thumbs generates a cropped thumbnail from the source preview image using the specified alignment.
 func (align Align) Thumbs(ctx context.Context, sl *slog.Logger, unid string, preview, thumbnail dir.Directory) error {
 	const msg = "thumbs re-alignment"
@@ -242,6 +278,12 @@ func (align Align) Thumbs(ctx context.Context, sl *slog.Logger, unid string, pre
 		if _, err := os.Stat(src); err != nil {
 			continue
 		}
+		if Native() {
+			if err := align.alignFile(src, thumbnail.Join(unid+png)); err != nil {
+				return fmt.Errorf(format, "native", err)
+			}
+			return nil
+		}
 		tmpDst := filepath.Join(tmpDir, unid+ext)
 		// construct command arguments: [src, args..., tmpDst]
 		const first = 2
@@ -271,6 +313,19 @@ const (
 	OneTwo                // OneTwo crops the top of the image using args 1:2 ratio
 )
 
+// String returns the ratio of the crop.
+func (crop Crop) String() string {
+	switch crop {
+	case SquareTop:
+		return "1:1"
+	case FourThree:
+		return "4:3"
+	case OneTwo:
+		return "1:2"
+	}
+	return ""
+}
+
 // Images crops the preview image based on the crop position and ratio of the image.
 func (crop Crop) Images(ctx context.Context, sl *slog.Logger, unid string, preview dir.Directory) error {
 	const format = "crop images %s: %w"
@@ -313,6 +368,12 @@ func (crop Crop) Images(ctx context.Context, sl *slog.Logger, unid string, previ
 			continue
 		}
 		imagesNotFound = false
+		if Native() {
+			if err := crop.cropFile(src); err != nil {
+				return fmt.Errorf(format, "native", err)
+			}
+			continue
+		}
 		arg := make([]string, 1, argCap+len(args))
 		arg[0] = src
 		arg = append(arg, args...)
@@ -383,6 +444,9 @@ func (dir Dirs) PictureImager(ctx context.Context, sl *slog.Logger, src, unid st
 	default:
 		return fmt.Errorf(format, magic.Title(), ErrUnknownImg)
 	}
+	if Native() && magic == PCX {
+		return fmt.Errorf(format, magic.Title(), ErrNative)
+	}
 
 	// remove existing thumbnails
 	if err := ImagesDelete(unid, dir.Preview.Path(), dir.Thumbnail.Path()); err != nil && !errors.Is(err, ErrNoImages) {
@@ -545,8 +609,9 @@ func cropText(sl *slog.Logger, maxColumns, maxRows int, src, dst string) error {
 	return nil
 }
 
-// BinTextImager converts a binary text source file into a PNG preview using Ansilove,
-// then processes the generated PNG through the standard text imager pipeline.
+// BinTextImager converts a binary text source file, either BIN or XBin, into a PNG preview
+// using the native text mode renderer, then processes the generated PNG through the
+// standard text imager pipeline.
 func (dir Dirs) BinTextImager(ctx context.Context, sl *slog.Logger, src, unid string) error {
 	const msg = "binary text imager"
 	const format = msg + " %s: %w"
@@ -555,38 +620,54 @@ func (dir Dirs) BinTextImager(ctx context.Context, sl *slog.Logger, src, unid st
 	}
 
 	srcPath := filepath.Clean(src)
-	st, err := os.Stat(srcPath)
+	b, err := os.ReadFile(srcPath)
 	if err != nil {
-		return fmt.Errorf(format, "stat source", err)
+		return fmt.Errorf(format, "read source", err)
 	}
-	if st.Size() == 0 {
+	if len(b) == 0 {
 		return fmt.Errorf(format, "zero size: "+srcPath, ErrIsEmpty)
 	}
-
-	// create an isolated, safe temporary file
-	tmpFile, err := os.CreateTemp("", "ansilove-*.png")
+	opts := textmode.Options{MaxRows: TextmodeRows} //nolint:exhaustruct
+	var screen *textmode.Screen
+	if textmode.IsXBin(b) {
+		screen, err = textmode.XBin(b, opts)
+	} else {
+		screen, err = textmode.Bin(b, opts)
+	}
 	if err != nil {
-		return fmt.Errorf(format, "create temp", err)
+		return fmt.Errorf(format, "textmode", err)
 	}
-	tmp := tmpFile.Name()
-	_ = tmpFile.Close()
-	defer func() {
-		if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
-			sl.Error(msg+" could not remove temporary file", slog.String("file", tmp), slog.Any("error", err))
-		}
-	}()
-
-	// command line arguments: [src, "-o", dst]
-	ansiloveArgs := []string{srcPath, "-o", tmp}
-	if err := Run(ctx, sl, Ansilove, ansiloveArgs...); err != nil {
-		return fmt.Errorf(format, "run ansilove", err)
+	tmp, err := textmodePNG(screen)
+	if err != nil {
+		return fmt.Errorf(format, "textmode png", err)
 	}
-	if err := dir.optimizeAnsilove(ctx, sl, unid, tmp); err != nil {
-		return fmt.Errorf(format, "optimize ansilove", err)
+	if err := dir.optimizePreview(ctx, sl, unid, tmp); err != nil {
+		return fmt.Errorf(format, "optimize preview", err)
 	}
 	return nil
 }
 
+// textmodePNG saves the text mode screen as an isolated, temporary PNG image and returns its path.
+func textmodePNG(screen *textmode.Screen) (string, error) {
+	const format = "textmode png %s: %w"
+	tmpFile, err := os.CreateTemp("", "textmode-*.png")
+	if err != nil {
+		return "", fmt.Errorf(format, "create temp", err)
+	}
+	name := tmpFile.Name()
+	const scale = 1
+	if err := screen.PNG(tmpFile, scale); err != nil {
+		_ = tmpFile.Close()
+		_ = os.Remove(name)
+		return "", fmt.Errorf(format, "encode", err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		_ = os.Remove(name)
+		return "", fmt.Errorf(format, "close", err)
+	}
+	return name, nil
+}
+
 // TextImager generates two images based on the text file provided by the src path.
 // The provided unid must be a valid universal unique identifier.
 //
@@ -622,6 +703,38 @@ func (dir Dirs) TextImager(ctx context.Context, sl *slog.Logger, src, unid strin
 	return dir.textImager(ctx, sl, src, cfg)
 }
 
+// CharsetImager generates the two images of the text file provided by the src path, using the key
+// of the text encoding chosen by an editor. The text is re-encoded to the character set of the font,
+// which is the Commodore Amiga Topaz font for the Amiga encoding, otherwise the IBM VGA font is used.
+//
+// When the key is empty or unknown, the text is used as is by the TextImager with the amigaFont choice.
+func (dir Dirs) CharsetImager(ctx context.Context, sl *slog.Logger, src, unid, key string, amigaFont bool) error {
+	const msg = "dirs charset imager"
+	const format = msg + " %s: %w"
+	c, ok := encodings.Find(key)
+	if !ok {
+		return dir.TextImager(ctx, sl, src, unid, amigaFont)
+	}
+	b, err := os.ReadFile(filepath.Clean(src))
+	if err != nil {
+		return fmt.Errorf(format, "read source", err)
+	}
+	tmp, err := os.CreateTemp("", "charset-*.txt")
+	if err != nil {
+		return fmt.Errorf(format, "create temp", err)
+	}
+	name := tmp.Name()
+	defer func() { _ = os.Remove(name) }()
+	if _, err := tmp.Write(encodings.Glyphs(b, c.Key)); err != nil {
+		_ = tmp.Close()
+		return fmt.Errorf(format, "write temp", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf(format, "close temp", err)
+	}
+	return dir.TextImager(ctx, sl, name, unid, c.Topaz())
+}
+
 type imagerCfg struct {
 	maxColumns int
 	maxRows    int
@@ -661,54 +774,48 @@ func (dir Dirs) textImager(ctx context.Context, sl *slog.Logger, src string, cfg
 		return fmt.Errorf(format, "zero size: "+srcPath, ErrIsEmpty)
 	}
 
-	// create an isolated temporary PNG destination
-	tmpFile, err := os.CreateTemp("", "ansilove-dos-*.png")
+	b, err := os.ReadFile(srcPath)
 	if err != nil {
-		return fmt.Errorf(format, "create temp", err)
+		return fmt.Errorf(format, "read cropped", err)
 	}
-	tmp := tmpFile.Name()
-	_ = tmpFile.Close()
-	defer func() {
-		if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
-			sl.Error(msg+" could not remove temporary file", slog.String("file", tmp), slog.Any("error", err))
-		}
-	}()
-
-	// command arguments: [srcPath, flags..., "-o", tmp]
-	args := Args{}
-	if amigaFont {
-		args.AnsiAmiga()
-	} else {
-		args.AnsiMsDos()
+	// the msdos texts use the 80x50 font with iCE colors,
+	// while the amiga texts use the topaz font
+	opts := textmode.Options{ //nolint:exhaustruct
+		MaxRows: TextmodeRows,
+		ICE:     !amigaFont,
+		Amiga:   amigaFont,
 	}
-	const first = 3
-	ansiloveArgs := make([]string, 0, first+len(args))
-	ansiloveArgs = append(ansiloveArgs, srcPath)
-	ansiloveArgs = append(ansiloveArgs, args...)
-	ansiloveArgs = append(ansiloveArgs, "-o", tmp)
-	if err := Run(ctx, sl, Ansilove, ansiloveArgs...); err != nil {
-		return fmt.Errorf(format, "run ansilove", err)
+	if !amigaFont {
+		opts.Font = textmode.VGA50()
+	}
+	screen, err := textmode.ANSI(b, opts)
+	if err != nil {
+		return fmt.Errorf(format, "textmode", err)
+	}
+	tmp, err := textmodePNG(screen)
+	if err != nil {
+		return fmt.Errorf(format, "textmode png", err)
 	}
-	if err := dir.optimizeAnsilove(ctx, sl, unid, tmp); err != nil {
-		return fmt.Errorf(format, "optimize ansilove", err)
+	if err := dir.optimizePreview(ctx, sl, unid, tmp); err != nil {
+		return fmt.Errorf(format, "optimize preview", err)
 	}
 	return nil
 }
 
-// optimizeAnsilove concurrently generates an optimized PNG preview, a WebP preview,
-// and the thumbnail from an temporary, unoptimized ANSILOVE generated image.
-func (dir Dirs) optimizeAnsilove(ctx context.Context, sl *slog.Logger, unid, tmp string) error {
-	const msg = "optimize ansilove conversion"
+// optimizePreview concurrently generates an optimized PNG preview, a WebP preview,
+// and the thumbnail from a temporary, unoptimized PNG image, which is removed afterwards.
+func (dir Dirs) optimizePreview(ctx context.Context, sl *slog.Logger, unid, tmp string) error {
+	const msg = "optimize preview conversion"
 	const format = msg + "%s: %w"
-	if err := nils.Check(ctx, sl); err != nil {
-		return fmt.Errorf(format, check, err)
-	}
-
+	// the temporary file is removed even when the arguments are invalid
 	defer func() {
-		if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) {
+		if err := os.Remove(tmp); err != nil && !os.IsNotExist(err) && sl != nil {
 			sl.Error(msg+" could not remove temporary file", slog.String("file", tmp), slog.Any("error", err))
 		}
 	}()
+	if err := nils.Check(ctx, sl); err != nil {
+		return fmt.Errorf(format, check, err)
+	}
 
 	// remove existing preview & thumbnail images
 	if err := ImagesDelete(unid, dir.Preview.Path(), dir.Thumbnail.Path()); err != nil && !errors.Is(err, ErrNoImages) {
@@ -776,23 +883,33 @@ func (dir Dirs) previewPixels(ctx context.Context, sl *slog.Logger, src, unid st
 	tmpName := filepath.Base(src) + png
 	tmpPath := filepath.Join(tmpDir, tmpName)
 	// command flags: [src, flags..., tmpPath]
-	args := Args{}
-	args.PortablePixel()
-	const first = 2
-	magickArgs := make([]string, 0, first+len(args))
-	magickArgs = append(magickArgs, src)
-	magickArgs = append(magickArgs, args...)
-	magickArgs = append(magickArgs, tmpPath)
-	if err := RunQuiet(ctx, Magick, magickArgs...); err != nil {
-		return fmt.Errorf(format, "run magick", err)
+	if Native() {
+		img, err := decodeImage(src)
+		if err != nil {
+			return fmt.Errorf(format, "native decode", err)
+		}
+		if err := writeImage(tmpPath, img); err != nil {
+			return fmt.Errorf(format, "native png", err)
+		}
+	} else {
+		args := Args{}
+		args.PortablePixel()
+		const first = 2
+		magickArgs := make([]string, 0, first+len(args))
+		magickArgs = append(magickArgs, src)
+		magickArgs = append(magickArgs, args...)
+		magickArgs = append(magickArgs, tmpPath)
+		if err := RunQuiet(ctx, Magick, magickArgs...); err != nil {
+			return fmt.Errorf(format, "run magick", err)
+		}
 	}
 
 	dst := filepath.Join(dir.Preview.Path(), unid+png)
 	if err := CopyFile(sl, tmpPath, dst); err != nil {
 		return fmt.Errorf(format, "copyfile png preview", err)
 	}
-	if err := dir.optimizeAnsilove(ctx, sl, unid, tmpPath); err != nil {
-		return fmt.Errorf(format, "optimize ansilove", err)
+	if err := dir.optimizePreview(ctx, sl, unid, tmpPath); err != nil {
+		return fmt.Errorf(format, "optimize preview", err)
 	}
 	return nil
 }
@@ -808,6 +925,9 @@ func (dir Dirs) previewPhoto(ctx context.Context, sl *slog.Logger, src, unid str
 	if err := nils.Check(ctx, sl); err != nil {
 		return fmt.Errorf(format, check, err)
 	}
+	if Native() {
+		return dir.nativePhoto(ctx, sl, src, unid)
+	}
 
 	tmpDir, err := os.MkdirTemp(helper.TmpDir(), "previewphoto-*")
 	if err != nil {
@@ -878,6 +998,9 @@ func (dir Dirs) previewGif(ctx context.Context, sl *slog.Logger, src, unid strin
 	if err := nils.Check(ctx, sl); err != nil {
 		return fmt.Errorf(format, "", err)
 	}
+	if Native() {
+		return dir.nativePreview(ctx, sl, src, unid)
+	}
 
 	src = filepath.Clean(src)
 	tmpFile, err := os.CreateTemp("", "preview-gif-*.webp")
@@ -961,6 +1084,9 @@ func (dir Dirs) previewWebP(ctx context.Context, sl *slog.Logger, src, unid stri
 	if err := nils.Check(ctx, sl); err != nil {
 		return fmt.Errorf(format, check, err)
 	}
+	if Native() {
+		return dir.nativeWebP(ctx, sl, src, unid, makeThumb)
+	}
 
 	src = filepath.Clean(src)
 	tmpFile, err := os.CreateTemp("", "preview-webp-*.webp")
@@ -1000,7 +1126,7 @@ func (dir Dirs) previewWebP(ctx context.Context, sl *slog.Logger, src, unid stri
 	return nil
 }
 
-// ansilove may find -extent and -extract useful
+// the previews may find -extent and -extract useful
 // https://imagemagick.org/script/command-line-options.php#extent
 
 // Args is args slice of strings that represents the command line arguments.
@@ -1095,62 +1221,6 @@ func (args *Args) OneTwo() {
 	*args = append(*args, extent...)
 }
 
-// AnsiAmiga appends the command line arguments for the [ansilove command]
-// to transform an Commodore Amiga ANSI text file into args PNG image.
-//
-// [ansilove command]: https://github.com/ansilove/ansilove
-func (args *Args) AnsiAmiga() {
-	type font string // amiga font
-	const (
-		microknight font = "microknight+"
-		mosoul      font = "mosoul"
-		potnoodle   font = "pot-noodle"
-		topaz       font = "topaz+"
-		topaz500    font = "topaz500+"
-	)
-	type mode string // rendering mode
-	const (
-		ced         mode = "ced"         // black on gray
-		transparent mode = "transparent" // transparent background
-		workbench   mode = "workbench"   // Amiga Workbench colors
-	)
-	// Output font.
-	f := []string{"-f", string(topaz)}
-	*args = append(*args, f...)
-	// Rendering mode set to Amiga palette.
-	m := []string{"-m", string(workbench)}
-	*args = append(*args, m...)
-	// Use SAUCE record for render options.
-	const s = "-S"
-	*args = append(*args, s)
-}
-
-// AnsiMsDos appends the command line arguments for the [ansilove command] to
-// transform an ANSI text file into args PNG image.
-//
-// [ansilove command]: https://github.com/ansilove/ansilove
-func (args *Args) AnsiMsDos() {
-	type font string // pc font
-	const (
-		f80x25   font = "80x25" // vga 80x25
-		f80x50   font = "80x50" // vga 80x50 (hires)
-		spleen   font = "spleen"
-		terminus font = "terminus"
-	)
-	// DOS aspect ratio.
-	const d = "-d"
-	*args = append(*args, d)
-	// Output font.
-	f := []string{"-f", string(f80x50)}
-	*args = append(*args, f...)
-	// Use iCE colors.
-	const i = "-i"
-	*args = append(*args, i)
-	// Use SAUCE record for render options.
-	const s = "-S"
-	*args = append(*args, s)
-}
-
 // JpegPhoto appends the command line arguments for the convert command to
 // transform an image into args JPEG image.
 func (args *Args) JpegPhoto() {
@@ -1319,6 +1389,16 @@ func (dir Dirs) makeThumb(ctx context.Context, sl *slog.Logger, cfg thumb) error
 	if unid == "" {
 		return fmt.Errorf(format, "unid", ErrValue)
 	}
+	if Native() {
+		img, err := decodeImage(cfg.src)
+		if err != nil {
+			return fmt.Errorf(format, "native decode", err)
+		}
+		if err := dir.nativeThumb(img, unid); err != nil {
+			return fmt.Errorf(format, "native", err)
+		}
+		return nil
+	}
 
 	tmpFile, err := os.CreateTemp("", cfg.pattern)
 	if err != nil {
@@ -1366,7 +1446,8 @@ func (dir Dirs) makeThumb(ctx context.Context, sl *slog.Logger, cfg thumb) error
 	return nil
 }
 
-// OptimizePNG optimizes the src PNG image in-place using optipng.
+// OptimizePNG optimizes the src PNG image in-place using optipng,
+// or the native image pipeline when it is used.
 // It is safe to call within a deferred function.
 func OptimizePNG(ctx context.Context, src string) error {
 	const format = "optimize png using optipng %s: %w"
@@ -1379,6 +1460,12 @@ func OptimizePNG(ctx context.Context, src string) error {
 	} else if st.Size() < minPNG {
 		return fmt.Errorf(format, src, ErrIsEmpty)
 	}
+	if Native() {
+		if err := optimizePNG(src); err != nil {
+			return fmt.Errorf(format, src, err)
+		}
+		return nil
+	}
 	args := Args{}
 	// command args: [flags..., src]
 	cmdArgs := make([]string, 0, len(args)+1)
@@ -3,6 +3,9 @@ package command_test
 import (
 	"context"
 	"fmt"
+	"image"
+	"image/color"
+	"image/png"
 	"log/slog"
 	"os"
 	"path"
@@ -165,6 +168,16 @@ func TestDirsThumbs(t *testing.T) {
 	be.Err(t, err)
 }
 
+func TestStringers(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, "pixel", command.Pixel.String())
+	be.Equal(t, "photo", command.Photo.String())
+	be.Equal(t, "middle", command.Middle.String())
+	be.Equal(t, "right", command.Right.String())
+	be.Equal(t, "4:3", command.FourThree.String())
+	be.Equal(t, "", command.Crop(-1).String())
+}
+
 func TestAlignThumbs(t *testing.T) {
 	t.Parallel()
 	unid, path := setupTestDir(t)
@@ -376,6 +389,23 @@ func TestCropText(t *testing.T) {
 	be.Equal(t, dstSt.Size(), wants)
 }
 
+// previewPNG returns the decoded preview image.
+func previewPNG(t *testing.T, name string) image.Image {
+	t.Helper()
+	f, err := os.Open(name)
+	be.Err(t, err, nil)
+	defer f.Close()
+	img, err := png.Decode(f)
+	be.Err(t, err, nil)
+	return img
+}
+
+// rgba returns the color of the image pixel.
+func rgba(img image.Image, x, y int) color.RGBA {
+	c, _ := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+	return c
+}
+
 func TestTextImagerVgaFont(t *testing.T) {
 	t.Parallel()
 
@@ -401,16 +431,16 @@ func TestTextImagerVgaFont(t *testing.T) {
 	be.Err(t, err, nil)
 	// check for the preview image
 	name := filepath.Join(prevdir, unid+".png")
-	pst, err := os.Stat(name)
-	be.Err(t, err, nil)
-	const pstSize = 2421
-	be.Equal(t, pst.Size(), pstSize)
+	img := previewPNG(t, name)
+	// 80 columns and 11 rows of the cropped text using the 8x8 VGA font
+	be.Equal(t, img.Bounds().Dx(), 640)
+	be.Equal(t, img.Bounds().Dy(), 88)
+	be.Equal(t, rgba(img, 121, 0), color.RGBA{R: 0xaa, G: 0xaa, B: 0xaa, A: 0xff}) // CGA light gray
 	// check for the thumbnail
 	name = filepath.Join(thumbdir, unid+".webp")
 	tst, err := os.Stat(name)
 	be.Err(t, err, nil)
-	const tstSize = 2784
-	be.Equal(t, tst.Size(), tstSize)
+	be.True(t, tst.Size() > 0)
 }
 
 func TestTextImagerAmigaFont(t *testing.T) {
@@ -438,16 +468,16 @@ func TestTextImagerAmigaFont(t *testing.T) {
 	be.Err(t, err, nil)
 	// check for the preview image
 	name := filepath.Join(prevdir, unid+".png")
-	pst, err := os.Stat(name)
-	be.Err(t, err, nil)
-	const pstSize = 1232
-	be.Equal(t, pst.Size(), pstSize)
+	img := previewPNG(t, name)
+	// 80 columns and 11 rows of the cropped text using the 8x16 Topaz font
+	be.Equal(t, img.Bounds().Dx(), 640)
+	be.Equal(t, img.Bounds().Dy(), 176)
+	be.Equal(t, rgba(img, 51, 0), color.RGBA{R: 0x74, G: 0x74, B: 0x74, A: 0xff}) // Amiga gray
 	// check for the thumbnail
 	name = filepath.Join(thumbdir, unid+".webp")
 	tst, err := os.Stat(name)
 	be.Err(t, err, nil)
-	const tstSize = 2738
-	be.Equal(t, tst.Size(), tstSize)
+	be.True(t, tst.Size() > 0)
 }
 
 func TestOptimizePNG(t *testing.T) {
@@ -497,16 +527,16 @@ func TestTextDeferred(t *testing.T) {
 
 	// check for the preview
 	name := filepath.Join(prevdir, unid+".png")
-	pst, err := os.Stat(name)
-	be.Err(t, err, nil)
-	const pstSize = 2421
-	be.Equal(t, pst.Size(), pstSize)
+	img := previewPNG(t, name)
+	// 80 columns and 11 rows of the cropped text using the 8x8 VGA font
+	be.Equal(t, img.Bounds().Dx(), 640)
+	be.Equal(t, img.Bounds().Dy(), 88)
+	be.Equal(t, rgba(img, 121, 0), color.RGBA{R: 0xaa, G: 0xaa, B: 0xaa, A: 0xff}) // CGA light gray
 	// check for the thumbnail
 	name = filepath.Join(thumbdir, unid+".webp")
 	tst, err := os.Stat(name)
 	be.Err(t, err, nil)
-	const tstSize = 2784
-	be.Equal(t, tst.Size(), tstSize)
+	be.True(t, tst.Size() > 0)
 	// confirm the text was copied to the extra directory
 	name = filepath.Join(extradir, unid+".txt")
 	est, err := os.Stat(name)
@@ -608,16 +638,6 @@ func TestArgs(t *testing.T) {
 	find = strings.Contains(s, "North")
 	be.True(t, find)
 	a = command.Args{}
-	a.AnsiAmiga()
-	s = fmt.Sprintf("%+v", a)
-	find = strings.Contains(s, "topaz+")
-	be.True(t, find)
-	a = command.Args{}
-	a.AnsiMsDos()
-	s = fmt.Sprintf("%+v", a)
-	find = strings.Contains(s, "80x50")
-	be.True(t, find)
-	a = command.Args{}
 	a.JpegPhoto()
 	s = fmt.Sprintf("%+v", a)
 	find = strings.Contains(s, "75")
@@ -0,0 +1,456 @@
+package command
+
+// Package file native.go contains the pure Go image functions that replace the
+// ImageMagick, cwebp, gif2webp and optipng commands when the native image pipeline is used.
+//
+// The Go libraries can decode but not encode WebP images, so the native previews
+// and thumbnails are saved as PNG or JPEG images, which the website uses as fallbacks.
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	imggif "image/gif"
+	imgjpeg "image/jpeg"
+	imgpng "image/png"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/Defacto2/server/internal/nils"
+	_ "golang.org/x/image/bmp" // bmp format decoder
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/tiff" // tiff format decoder
+	_ "golang.org/x/image/webp" // webp format decoder
+)
+
+const (
+	thumbSize   = 400 // thumbSize is the width and height in pixels of a thumbnail.
+	jpegQuality = 75  // jpegQuality matches the quality of the JpegPhoto args.
+	pixelScale  = 20  // pixelScale is the 5% downscale of the Pixelate args.
+	maxColors   = 256 // maxColors is the number of colors of a paletted PNG image.
+)
+
+var ErrNative = errors.New("image format is not supported by the native image pipeline")
+
+//nolint:gochecknoglobals
+var native atomic.Bool
+
+// UseNative sets the use of the native, pure Go image pipeline in place of the
+// ImageMagick, cwebp, gif2webp and optipng commands.
+func UseNative(b bool) {
+	native.Store(b)
+}
+
+// Native returns true if the native, pure Go image pipeline is used.
+func Native() bool {
+	return native.Load()
+}
+
+// Imagers returns the execute command names that are not required by the native image pipeline.
+func Imagers() []string {
+	return []string{Cwebp, Gwebp, Magick, Optipng}
+}
+
+// Trim removes the edges of the image that match the color of the top-left pixel,
+// which replicates the ImageMagick -trim option. An image of a single color is returned as is.
+func Trim(img image.Image) image.Image {
+	b := img.Bounds()
+	if b.Empty() {
+		return img
+	}
+	bg := nrgba(img.At(b.Min.X, b.Min.Y))
+	area := image.Rectangle{}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if nrgba(img.At(x, y)) == bg {
+				continue
+			}
+			area = area.Union(image.Rect(x, y, x+1, y+1))
+		}
+	}
+	if area.Empty() {
+		return img
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, area.Dx(), area.Dy()))
+	draw.Draw(dst, dst.Bounds(), img, area.Min, draw.Src)
+	return dst
+}
+
+// Extent places the image onto a canvas of the width and height using the alignment,
+// which replicates the ImageMagick -gravity and -extent options. The parts of the image
+// that are outside of the canvas are cropped, and the uncovered canvas uses the bg color.
+//
+// Top and Bottom center the image horizontally, Left and Right center the image vertically.
+func (align Align) Extent(img image.Image, width, height int, bg color.Color) *image.RGBA {
+	b := img.Bounds()
+	x, y := (width-b.Dx())/2, (height-b.Dy())/2
+	switch align {
+	case Top:
+		y = 0
+	case Bottom:
+		y = height - b.Dy()
+	case Left:
+		x = 0
+	case Right:
+		x = width - b.Dx()
+	case Middle:
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	draw.Draw(dst, image.Rect(x, y, x+b.Dx(), y+b.Dy()), img, b.Min, draw.Over)
+	return dst
+}
+
+// Ratio returns the width and height ratio of the crop.
+func (crop Crop) Ratio() (int, int) {
+	switch crop {
+	case SquareTop:
+		return 1, 1
+	case FourThree:
+		return 4, 3
+	case OneTwo:
+		return 1, 2
+	}
+	return 0, 0
+}
+
+// Extent crops the image to the largest area of the crop ratio using the top alignment.
+func (crop Crop) Extent(img image.Image) image.Image {
+	rw, rh := crop.Ratio()
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if rw == 0 || rh == 0 || w == 0 || h == 0 {
+		return img
+	}
+	cw, ch := w, w*rh/rw
+	if ch > h {
+		cw, ch = h*rw/rh, h
+	}
+	return Top.Extent(img, max(cw, 1), max(ch, 1), color.White)
+}
+
+// Thumbnail resizes the image to fit within a 400x400 pixel square that is centered on
+// a gray background, which replicates the Thumbnail args.
+func Thumbnail(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	w, h := thumbSize, thumbSize
+	switch {
+	case b.Empty():
+	case b.Dx() > b.Dy():
+		h = max(b.Dy()*thumbSize/b.Dx(), 1)
+	default:
+		w = max(b.Dx()*thumbSize/b.Dy(), 1)
+	}
+	scaled := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.BiLinear.Scale(scaled, scaled.Bounds(), img, b, draw.Over, nil)
+	const gray = 0x99
+	return Middle.Extent(scaled, thumbSize, thumbSize, color.Gray{Y: gray})
+}
+
+// Pixelate downscales the image to 5% of its size and then upscales it back to the
+// original size, which replicates the Pixelate args.
+func Pixelate(img image.Image) *image.RGBA {
+	b := img.Bounds()
+	small := image.NewRGBA(image.Rect(0, 0,
+		max(b.Dx()/pixelScale, 1), max(b.Dy()/pixelScale, 1)))
+	draw.BiLinear.Scale(small, small.Bounds(), img, b, draw.Src, nil)
+	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.NearestNeighbor.Scale(dst, dst.Bounds(), small, small.Bounds(), draw.Src, nil)
+	return dst
+}
+
+// EncodePNG writes the image to w as a PNG image using the best compression.
+// Images that use 256 or fewer colors are saved with a color palette,
+// which is how the optipng command reduces the size of most pixel art and text images.
+func EncodePNG(w io.Writer, img image.Image) error {
+	enc := imgpng.Encoder{CompressionLevel: imgpng.BestCompression} //nolint:exhaustruct
+	if p := paletted(img); p != nil {
+		return enc.Encode(w, p)
+	}
+	return enc.Encode(w, img)
+}
+
+// paletted returns the image as a paletted image, or nil if it uses more than 256 colors.
+func paletted(img image.Image) *image.Paletted {
+	if p, ok := img.(*image.Paletted); ok {
+		return p
+	}
+	b := img.Bounds()
+	index := make(map[color.NRGBA]uint8, maxColors)
+	pal := make(color.Palette, 0, maxColors)
+	dst := image.NewPaletted(b, nil)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := nrgba(img.At(x, y))
+			i, ok := index[c]
+			if !ok {
+				if len(pal) == maxColors {
+					return nil
+				}
+				i = uint8(len(pal))
+				index[c] = i
+				pal = append(pal, c)
+			}
+			dst.SetColorIndex(x, y, i)
+		}
+	}
+	dst.Palette = pal
+	return dst
+}
+
+func nrgba(c color.Color) color.NRGBA {
+	n, _ := color.NRGBAModel.Convert(c).(color.NRGBA)
+	return n
+}
+
+// decodeImage returns the decoded image of the named file.
+// Only the first frame of an animated GIF image is decoded.
+func decodeImage(name string) (image.Image, error) {
+	r, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = r.Close() }()
+	img, _, err := image.Decode(r)
+	if err != nil {
+		if errors.Is(err, image.ErrFormat) {
+			return nil, fmt.Errorf("%w: %s", ErrNative, filepath.Base(name))
+		}
+		return nil, fmt.Errorf("decode %s: %w", filepath.Base(name), err)
+	}
+	return img, nil
+}
+
+// encodeImage returns the image encoded in the format of the named file extension.
+func encodeImage(name string, img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch strings.ToLower(filepath.Ext(name)) {
+	case png:
+		err = EncodePNG(&buf, img)
+	case jpg, jpeg:
+		err = imgjpeg.Encode(&buf, img, &imgjpeg.Options{Quality: jpegQuality})
+	case gif:
+		err = imggif.Encode(&buf, img, nil)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrNative, filepath.Base(name))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("encode %s: %w", filepath.Base(name), err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeImage saves the image to the named file in the format of the file extension.
+func writeImage(name string, img image.Image) error {
+	b, err := encodeImage(name, img)
+	if err != nil {
+		return err
+	}
+	const perm = 0o644
+	return os.WriteFile(name, b, perm)
+}
+
+// nativeName returns the named file with a PNG extension when the
+// native image pipeline cannot save the file extension, such as WebP.
+func nativeName(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case png, jpg, jpeg, gif:
+		return name
+	}
+	return strings.TrimSuffix(name, filepath.Ext(name)) + png
+}
+
+// rewriteImage applies fn to the named image file and saves the result in place.
+// A file in a format that cannot be saved, such as WebP, is replaced by a PNG image.
+func rewriteImage(name string, fn func(image.Image) image.Image) error {
+	img, err := decodeImage(name)
+	if err != nil {
+		return err
+	}
+	dst := nativeName(name)
+	if err := writeImage(dst, fn(img)); err != nil {
+		return err
+	}
+	if dst != name {
+		return os.Remove(name)
+	}
+	return nil
+}
+
+// pixelateFile pixelates the named image file in place.
+func pixelateFile(name string) error {
+	return rewriteImage(name, func(img image.Image) image.Image {
+		return Pixelate(img)
+	})
+}
+
+// cropFile crops the named image file in place.
+func (crop Crop) cropFile(name string) error {
+	return rewriteImage(name, crop.Extent)
+}
+
+// alignFile saves a trimmed, 400x400 pixel thumbnail of the src image to the dst PNG file.
+func (align Align) alignFile(src, dst string) error {
+	img, err := decodeImage(src)
+	if err != nil {
+		return err
+	}
+	return replaceImage(dst, align.Extent(Trim(img), thumbSize, thumbSize, color.White))
+}
+
+// optimizePNG replaces the src PNG image with a smaller, recompressed PNG image.
+func optimizePNG(src string) error {
+	img, err := decodeImage(src)
+	if err != nil {
+		return err
+	}
+	st, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := EncodePNG(&buf, img); err != nil {
+		return err
+	}
+	if int64(buf.Len()) >= st.Size() {
+		return nil
+	}
+	const perm = 0o644
+	return os.WriteFile(src, buf.Bytes(), perm)
+}
+
+// replaceImage saves the image to the named file and then removes the images of the
+// same name that use the other file extensions. Otherwise a stale WebP or AVIF image,
+// which the website prefers over a PNG or JPEG image, would be shown in place of the new image.
+func replaceImage(name string, img image.Image) error {
+	if err := writeImage(name, img); err != nil {
+		return err
+	}
+	return removeSiblings(name)
+}
+
+// removeSiblings removes the images of the same name as the named file that use the other file extensions.
+func removeSiblings(name string) error {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for _, x := range ImagesExt() {
+		if strings.EqualFold(x, ext) {
+			continue
+		}
+		if err := os.Remove(base + x); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	return nil
+}
+
+// nativeThumb saves a 400x400 pixel PNG thumbnail of the img to the thumbnail directory,
+// replacing any existing thumbnail.
+func (dir Dirs) nativeThumb(img image.Image, unid string) error {
+	if unid == "" {
+		return fmt.Errorf("native thumb unid: %w", ErrValue)
+	}
+	return replaceImage(dir.Thumbnail.Join(unid+png), Thumbnail(img))
+}
+
+// nativePreview saves the src image as a PNG preview, replacing any existing preview, and creates a thumbnail.
+// It is used for the GIF, BMP and other lossless images, but the animation of
+// a GIF image is not kept.
+func (dir Dirs) nativePreview(ctx context.Context, sl *slog.Logger, src, unid string) error {
+	const format = "native preview %s: %w"
+	if err := nils.Check(ctx, sl); err != nil {
+		return fmt.Errorf(format, check, err)
+	}
+	img, err := decodeImage(src)
+	if err != nil {
+		return fmt.Errorf(format, "decode", err)
+	}
+	if err := replaceImage(dir.Preview.Join(unid+png), img); err != nil {
+		return fmt.Errorf(format, "png", err)
+	}
+	if err := dir.nativeThumb(img, unid); err != nil {
+		return fmt.Errorf(format, "thumbnail", err)
+	}
+	return nil
+}
+
+// nativePhoto saves the src image as either a JPEG or a PNG preview, whichever is
+// smaller, replacing any existing preview, and creates a thumbnail.
+func (dir Dirs) nativePhoto(ctx context.Context, sl *slog.Logger, src, unid string) error {
+	const format = "native photo %s: %w"
+	if err := nils.Check(ctx, sl); err != nil {
+		return fmt.Errorf(format, check, err)
+	}
+	img, err := decodeImage(src)
+	if err != nil {
+		return fmt.Errorf(format, "decode", err)
+	}
+	name := dir.Preview.Join(unid + jpg)
+	b, err := encodeImage(name, img)
+	if err != nil {
+		return fmt.Errorf(format, "jpeg", err)
+	}
+	var buf bytes.Buffer
+	if err := EncodePNG(&buf, img); err != nil {
+		return fmt.Errorf(format, "png", err)
+	}
+	if buf.Len() < len(b) {
+		name, b = dir.Preview.Join(unid+png), buf.Bytes()
+	}
+	const perm = 0o644
+	if err := os.WriteFile(name, b, perm); err != nil {
+		return fmt.Errorf(format, "write", err)
+	}
+	if err := removeSiblings(name); err != nil {
+		return fmt.Errorf(format, "remove", err)
+	}
+	if err := dir.nativeThumb(img, unid); err != nil {
+		return fmt.Errorf(format, "thumbnail", err)
+	}
+	return nil
+}
+
+// nativeWebP copies a src WebP image to the preview directory, as WebP images cannot be
+// encoded by the native image pipeline, and optionally creates a thumbnail.
+// Any other src image is skipped, as it is expected to also have a PNG preview.
+func (dir Dirs) nativeWebP(ctx context.Context, sl *slog.Logger, src, unid string, makeThumb bool) error {
+	const format = "native webp %s: %w"
+	if err := nils.Check(ctx, sl); err != nil {
+		return fmt.Errorf(format, check, err)
+	}
+	if isWebP(src) {
+		if err := CopyFile(sl, src, dir.Preview.Join(unid+webp)); err != nil {
+			return fmt.Errorf(format, "copy preview", err)
+		}
+	}
+	if !makeThumb {
+		return nil
+	}
+	img, err := decodeImage(src)
+	if err != nil {
+		return fmt.Errorf(format, "decode", err)
+	}
+	if err := dir.nativeThumb(img, unid); err != nil {
+		return fmt.Errorf(format, "thumbnail", err)
+	}
+	return nil
+}
+
+// isWebP returns true if the named file is a WebP image.
+func isWebP(name string) bool {
+	r, err := os.Open(name)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = r.Close() }()
+	_, kind, err := image.DecodeConfig(r)
+	return err == nil && kind == "webp"
+}
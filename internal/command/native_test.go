@@ -0,0 +1,157 @@
+package command_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Defacto2/server/internal/command"
+	"github.com/Defacto2/server/internal/dir"
+	"github.com/nalgeon/be"
+)
+
+// box returns a white image of the width and height with a black rectangle at r.
+func box(width, height int, r image.Rectangle) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			c := color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+			if image.Pt(x, y).In(r) {
+				c = color.RGBA{A: 0xff}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestTrim(t *testing.T) {
+	t.Parallel()
+	img := command.Trim(box(100, 50, image.Rect(10, 20, 30, 25)))
+	be.Equal(t, img.Bounds().Dx(), 20)
+	be.Equal(t, img.Bounds().Dy(), 5)
+	blank := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	be.Equal(t, command.Trim(blank).Bounds(), blank.Bounds())
+}
+
+func TestAlignExtent(t *testing.T) {
+	t.Parallel()
+	src := box(10, 10, image.Rect(0, 0, 10, 10))
+	black := color.RGBA{A: 0xff}
+	img := command.Top.Extent(src, 20, 20, color.White)
+	be.Equal(t, img.Bounds().Dx(), 20)
+	be.Equal(t, img.RGBAAt(5, 0), black)
+	be.Equal(t, img.RGBAAt(5, 15).R, uint8(0xff))
+	img = command.Right.Extent(src, 20, 20, color.White)
+	be.Equal(t, img.RGBAAt(15, 5), black)
+	be.Equal(t, img.RGBAAt(5, 5).R, uint8(0xff))
+	// larger images are cropped
+	img = command.Bottom.Extent(box(10, 40, image.Rect(0, 30, 10, 40)), 10, 10, color.White)
+	be.Equal(t, img.RGBAAt(5, 5), black)
+}
+
+func TestCropExtent(t *testing.T) {
+	t.Parallel()
+	img := command.SquareTop.Extent(box(100, 300, image.Rect(0, 0, 1, 1)))
+	be.Equal(t, img.Bounds().Dx(), 100)
+	be.Equal(t, img.Bounds().Dy(), 100)
+	img = command.FourThree.Extent(box(400, 600, image.Rect(0, 0, 1, 1)))
+	be.Equal(t, img.Bounds().Dy(), 300)
+	img = command.OneTwo.Extent(box(400, 400, image.Rect(0, 0, 1, 1)))
+	be.Equal(t, img.Bounds().Dx(), 200)
+	be.Equal(t, img.Bounds().Dy(), 400)
+	w, h := command.Crop(-1).Ratio()
+	be.Equal(t, w+h, 0)
+}
+
+func TestThumbnail(t *testing.T) {
+	t.Parallel()
+	img := command.Thumbnail(box(800, 200, image.Rect(0, 0, 800, 200)))
+	be.Equal(t, img.Bounds().Dx(), 400)
+	be.Equal(t, img.Bounds().Dy(), 400)
+	// the 800x200 image is resized to 400x100 and centered on the gray background
+	be.Equal(t, img.RGBAAt(200, 10), color.RGBA{R: 0x99, G: 0x99, B: 0x99, A: 0xff})
+	be.Equal(t, img.RGBAAt(200, 200), color.RGBA{A: 0xff})
+}
+
+func TestPixelateImage(t *testing.T) {
+	t.Parallel()
+	img := command.Pixelate(box(200, 100, image.Rect(0, 0, 100, 100)))
+	be.Equal(t, img.Bounds().Dx(), 200)
+	be.Equal(t, img.Bounds().Dy(), 100)
+	be.Equal(t, img.RGBAAt(1, 1), img.RGBAAt(19, 19))
+}
+
+func TestEncodePNG(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	be.Err(t, command.EncodePNG(&buf, box(64, 64, image.Rect(8, 8, 16, 16))), nil)
+	img, err := png.Decode(&buf)
+	be.Err(t, err, nil)
+	_, ok := img.(*image.Paletted)
+	be.True(t, ok)
+}
+
+// TestNative is not run in parallel, as it toggles the use of the native image pipeline.
+func TestNative(t *testing.T) {
+	command.UseNative(true)
+	defer command.UseNative(false)
+	be.True(t, command.Native())
+
+	prevdir, thumbdir := t.TempDir(), t.TempDir()
+	dirs := command.Dirs{
+		Preview:   dir.Directory(prevdir),
+		Thumbnail: dir.Directory(thumbdir),
+	}
+	sl := slog.Default()
+	for _, name := range []string{"TEST.PNG", "TEST.JPG", "TEST.GIF", "TEST.WEBP", "TEST.BMP"} {
+		src, err := filepath.Abs(filepath.Join("testdata", name))
+		be.Err(t, err, nil)
+		err = dirs.PictureImager(t.Context(), sl, src, name)
+		be.Err(t, err, nil)
+		_, err = os.Stat(filepath.Join(thumbdir, name+".png"))
+		be.Err(t, err, nil)
+	}
+	// a regenerated preview and thumbnail replace the stale webp and avif images
+	for _, name := range []string{"TEST.PNG", "TEST.JPG"} {
+		stale := []string{
+			filepath.Join(prevdir, name+".webp"), filepath.Join(prevdir, name+".avif"),
+			filepath.Join(thumbdir, name+".webp"), filepath.Join(thumbdir, name+".avif"),
+		}
+		for _, path := range stale {
+			be.Err(t, os.WriteFile(path, []byte("stale"), 0o644), nil)
+		}
+		src, err := filepath.Abs(filepath.Join("testdata", name))
+		be.Err(t, err, nil)
+		err = dirs.PictureImager(t.Context(), sl, src, name)
+		be.Err(t, err, nil)
+		for _, path := range stale {
+			_, err = os.Stat(path)
+			be.Err(t, err, os.ErrNotExist)
+		}
+	}
+	src, err := filepath.Abs(filepath.Join("testdata", "TEST.PCX"))
+	be.Err(t, err, nil)
+	err = dirs.PictureImager(t.Context(), sl, src, "TEST.PCX")
+	be.Err(t, err, command.ErrNative)
+
+	const unid = "TEST.WEBP"
+	_, err = os.Stat(filepath.Join(prevdir, unid+".webp"))
+	be.Err(t, err, nil)
+	err = command.Middle.Thumbs(t.Context(), sl, unid, dirs.Preview, dirs.Thumbnail)
+	be.Err(t, err, nil)
+	err = command.FourThree.Images(t.Context(), sl, unid, dirs.Preview)
+	be.Err(t, err, nil)
+	// the cropped webp preview is replaced by a png preview
+	_, err = os.Stat(filepath.Join(prevdir, unid+".webp"))
+	be.Err(t, err, os.ErrNotExist)
+	err = command.ImagesPixelate(t.Context(), unid, prevdir, thumbdir)
+	be.Err(t, err, nil)
+	_, err = os.Stat(filepath.Join(prevdir, unid+".png"))
+	be.Err(t, err, nil)
+}
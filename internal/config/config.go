@@ -48,12 +48,14 @@ var formatMap = map[string]string{
 	"LogAll":         "Log all HTTP requests",
 	"MaxProcs":       "Maximum CPU processes",
 	"MatchHost":      "Match hostname, domain or IP address",
+	"NativeImages":   "Native image pipeline",
 	"NoCrawl":        "Disallow search engine crawling",
 	"ProdMode":       "Production mode",
 	"Quiet":          "Quiet mode",
 	"ReadOnly":       "Read-only mode",
 	"SessionKey":     "Session encryption key",
 	"SessionMaxAge":  "Maximum age of a session for the web administration",
+	"SigningKey":     "Checksums signing key",
 	"TLSCert":        "TLS certificate, file path",
 	"TLSHost":        "TLS hostname",
 	"TLSKey":         "TLS key, file path",
@@ -70,7 +72,8 @@ type Config struct { //nolint:recvcheck
 	AbsExtra       Absextra   `env:"D2_DIR_EXTRA" help:"The directory path that holds extra assets of the UUID named files that are generated by the application"`
 	AbsOrphaned    Absorphan  `env:"D2_DIR_ORPHANED" help:"The directory path that holds the UUID named files that are not linked to any database records"`
 	DatabaseURL    Connection `env:"D2_DATABASE_URL" help:"Provide the URL of the database to which to connect"`
-	SessionKey     Sessionkey `env:"D2_SESSION_KEY,unset" help:"Use a fixed session key for the cookie store, which can be left blank to generate a random key"`
+	SessionKey     Sessionkey `env:"D2_SESSION_KEY,unset" help:"Use a fixed session key for the cookie store, which can be left blank to disable the signatures"`
+	SigningKey     Signkey    `env:"D2_SIGNING_KEY,unset" help:"A base64 encoded, Ed25519 private key seed to sign the checksums of the downloads, which can be left blank to disable the signatures"`
 	GoogleClientID Googleauth `env:"D2_GOOGLE_CLIENT_ID,unset" help:"The Google OAuth2 client ID"`
 	GoogleIDs      Googleids  `env:"D2_GOOGLE_IDS,unset" help:"Create a comma-separated list of Google account IDs to permit access to the editor mode"`
 	MatchHost      Matchhost  `env:"D2_MATCH_HOST" help:"Limits connections to the specific host or domain name; leave blank to permit connections from anywhere"`
@@ -87,6 +90,7 @@ type Config struct { //nolint:recvcheck
 	ReadOnly       Toggle     `env:"D2_READ_ONLY" help:"Use the read-only mode to turn off all POST, PUT, and DELETE requests and any related user interface"`
 	NoCrawl        Toggle     `env:"D2_NO_CRAWL" help:"Tell search engines to not crawl any of website pages or assets"`
 	LogAll         Toggle     `env:"D2_LOG_ALL" help:"Log all HTTP and HTTPS client requests including those with 200 OK responses"`
+	NativeImages   Toggle     `env:"D2_NATIVE_IMAGES" help:"Create the previews and thumbnails using the built-in image pipeline, in place of the ImageMagick, cwebp, gif2webp and optipng commands, the images are saved as PNG or JPEG"`
 }
 
 // Format returns a human readable description of the named configuration identifier.
@@ -85,6 +85,12 @@ func TestRepair(t *testing.T) {
 	be.Err(t, err)
 	err = c.MagicNumbers(t.Context(), nil, nil)
 	be.Err(t, err)
+	err = c.Manifests(t.Context(), nil, nil)
+	be.Err(t, err)
+	err = c.Sauces(t.Context(), nil, nil)
+	be.Err(t, err)
+	err = c.Encodings(t.Context(), nil, nil)
+	be.Err(t, err)
 	err = c.Previews(t.Context(), nil, nil)
 	be.Err(t, err)
 	sl := logs.Discard()
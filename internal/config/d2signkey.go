@@ -0,0 +1,39 @@
+package config
+
+import (
+	"encoding/base64"
+	"log/slog"
+	"strings"
+)
+
+type Signkey string
+
+func (s Signkey) Issue() string {
+	if s == "" {
+		return ""
+	}
+	const seedSize = 32
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(s)))
+	if err != nil || len(b) != seedSize {
+		return "The key must be a base64 encoded, 32 byte Ed25519 seed, the checksums will not be signed"
+	}
+	return ""
+}
+
+func (s Signkey) LogValue() slog.Value {
+	if s == "" {
+		return slog.StringValue("")
+	}
+	return slog.StringValue(hide)
+}
+
+func (s Signkey) Help() string {
+	if s == "" {
+		return "The checksums of the downloads will not be signed, so mirrors cannot verify their bulk fetches"
+	}
+	return ""
+}
+
+func (s Signkey) String() string {
+	return string(s)
+}
@@ -8,6 +8,7 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"time"
 
 	"github.com/Defacto2/helper"
@@ -313,6 +314,7 @@ func repairDatabase(ctx context.Context, sl *slog.Logger, db *sql.DB) error {
 	if err != nil {
 		return fmt.Errorf("%s could not begin a transaction: %w", msg, err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	if err := fix.Artifacts.Run(ctx, sl, db, tx); err != nil {
 		if err := tx.Rollback(); err != nil {
 			sl.Error(msg, slog.Any("error", err))
@@ -362,6 +364,9 @@ func cmdChecks(sl *slog.Logger) {
 	infos := command.Infos()
 	var attrs []slog.Attr
 	for i, name := range lookups {
+		if command.Native() && slices.Contains(command.Imagers(), name) {
+			continue
+		}
 		if err := command.LookCmd(name); err != nil {
 			attrs = append(attrs, slog.String(name, infos[i]))
 		}
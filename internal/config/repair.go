@@ -5,6 +5,7 @@ package config
 import (
 	"bufio"
 	"context"
+	"database/sql"
 	"fmt"
 	"io"
 	"io/fs"
@@ -413,9 +414,9 @@ func unknownAsset(sl *slog.Logger, oldpath, name, uid string, orphaned dir.Direc
 
 // RepairAssets on startup check the file system directories for any invalid or unknown files.
 // If any are found, they are removed without warning.
-func (c *Config) RepairAssets(ctx context.Context, sl *slog.Logger, exec boil.ContextExecutor) error {
+func (c *Config) RepairAssets(ctx context.Context, sl *slog.Logger, db *sql.DB) error {
 	const msg = "repair"
-	if err := nils.Check(ctx, sl, exec); err != nil {
+	if err := nils.Check(ctx, sl, db); err != nil {
 		return fmt.Errorf("%s: %w", msg, err)
 	}
 	backup := dir.Directory(c.AbsOrphaned)
@@ -436,21 +437,99 @@ func (c *Config) RepairAssets(ctx context.Context, sl *slog.Logger, exec boil.Co
 	if err := DownloadDir(sl, src, backup, extra); err != nil {
 		return fmt.Errorf("%s the download directory: %w", msg, err)
 	}
-	if err := c.Assets(ctx, sl, exec); err != nil {
+	if err := c.Assets(ctx, sl, db); err != nil {
 		return fmt.Errorf("%s: %w", msg, err)
 	}
-	if err := c.Archives(ctx, sl, exec); err != nil {
+	if err := c.Archives(ctx, sl, db); err != nil {
 		return fmt.Errorf("%s the archives: %w", msg, err)
 	}
-	if err := c.Previews(ctx, sl, exec); err != nil {
+	if err := c.Previews(ctx, sl, db); err != nil {
 		return fmt.Errorf("%s the previews: %w", msg, err)
 	}
-	if err := c.MagicNumbers(ctx, sl, exec); err != nil {
+	if err := c.MagicNumbers(ctx, sl, db); err != nil {
 		return fmt.Errorf("%s the magics: %w", msg, err)
 	}
-	if err := c.TextFiles(ctx, sl, exec); err != nil {
+	if err := c.TextFiles(ctx, sl, db); err != nil {
 		return fmt.Errorf("%s the textfiles: %w", msg, err)
 	}
+	if err := c.Manifests(ctx, sl, db); err != nil {
+		return fmt.Errorf("%s the manifests: %w", msg, err)
+	}
+	if err := c.Sauces(ctx, sl, db); err != nil {
+		return fmt.Errorf("%s the sauces: %w", msg, err)
+	}
+	if err := c.Encodings(ctx, sl, db); err != nil {
+		return fmt.Errorf("%s the encodings: %w", msg, err)
+	}
+	return nil
+}
+
+// Manifests reads and saves the archive manifests of the artifacts that are missing one,
+// or of those with a download that was modified after the manifest was read.
+func (c *Config) Manifests(ctx context.Context, sl *slog.Logger, db *sql.DB) error {
+	const msg = "manifests"
+	if err := nils.Check(ctx, sl, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	tick := time.Now()
+	indexed, skipped, err := model.IndexManifests(ctx, db, string(c.AbsDownload))
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if indexed == 0 {
+		return nil
+	}
+	sl.Info(msg,
+		slog.String("success", ""),
+		slog.Int("archives read", indexed),
+		slog.Int("unmodified", skipped),
+		slog.Duration("time", time.Since(tick).Round(time.Millisecond)))
+	return nil
+}
+
+// Sauces reads and saves the SAUCE metadata of the artifact downloads that are new,
+// or of those with a download that was modified after the metadata was read.
+func (c *Config) Sauces(ctx context.Context, sl *slog.Logger, exec boil.ContextExecutor) error {
+	const msg = "sauces"
+	if err := nils.Check(ctx, sl, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	tick := time.Now()
+	indexed, skipped, err := model.IndexSauces(ctx, exec, string(c.AbsDownload))
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if indexed == 0 {
+		return nil
+	}
+	sl.Info(msg,
+		slog.String("success", ""),
+		slog.Int("records read", indexed),
+		slog.Int("unmodified", skipped),
+		slog.Duration("time", time.Since(tick).Round(time.Millisecond)))
+	return nil
+}
+
+// Encodings detects and saves the text encodings of the artifact texts that are new,
+// or of those with a text that was modified after the encoding was detected.
+func (c *Config) Encodings(ctx context.Context, sl *slog.Logger, exec boil.ContextExecutor) error {
+	const msg = "encodings"
+	if err := nils.Check(ctx, sl, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	tick := time.Now()
+	indexed, skipped, err := model.IndexEncodings(ctx, exec, string(c.AbsDownload), string(c.AbsExtra))
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if indexed == 0 {
+		return nil
+	}
+	sl.Info(msg,
+		slog.String("success", ""),
+		slog.Int("texts detected", indexed),
+		slog.Int("unmodified", skipped),
+		slog.Duration("time", time.Since(tick).Round(time.Millisecond)))
 	return nil
 }
 
@@ -565,14 +644,14 @@ func FileID(r io.Reader) bool {
 // MagicNumbers checks the magic numbers of the artifacts and replaces any missing or
 // legacy values with the current method of detection. Previous detection methods were
 // done using the `file` command line utility, which is a bit to verbose for our needs.
-func (c *Config) MagicNumbers(ctx context.Context, sl *slog.Logger, exec boil.ContextExecutor) error {
+func (c *Config) MagicNumbers(ctx context.Context, sl *slog.Logger, db *sql.DB) error {
 	const msg = "magic numbers"
-	if err := nils.Check(ctx, sl, exec); err != nil {
+	if err := nils.Check(ctx, sl, db); err != nil {
 		return fmt.Errorf("%s: %w", msg, err)
 	}
 	tick := time.Now()
 	r := model.Artifacts{Bytes: 0, Count: 0, MinYear: 0, MaxYear: 0}
-	magics, err := r.ByMagicErr(ctx, exec, false)
+	magics, err := r.ByMagicErr(ctx, db, false)
 	if err != nil {
 		return fmt.Errorf("%s: %w", msg, err)
 	}
@@ -592,7 +671,7 @@ func (c *Config) MagicNumbers(ctx context.Context, sl *slog.Logger, exec boil.Co
 		}
 		magic := magicnumber.Find(r)
 		count++
-		_ = model.UpdateMagic(ctx, exec, val.ID, magic.Title())
+		_ = model.UpdateMagic(ctx, db, val.ID, magic.Title())
 		_ = r.Close()
 	}
 	if count == 0 {
@@ -0,0 +1,449 @@
+// Package dupe finds the duplicate and near duplicate artifacts of the collection.
+// Unlike the SHA-384 checksum of an upload, which only matches identical downloads,
+// the artifacts are compared using three fingerprints:
+//
+//   - the hashes of the files within the archives, to find the repacks of a release.
+//   - the hashes of the normalized NFO, DIZ and README texts, which ignore the white space and BBS adverts.
+//   - the perceptual hashes of the thumbnails, to find the visually similar images.
+package dupe
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"io/fs"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	_ "image/gif"  // gif format decoder
+	_ "image/jpeg" // jpeg format decoder
+	_ "image/png"  // png format decoder
+
+	"github.com/Defacto2/archive"
+	"github.com/Defacto2/server/handler/fulltext"
+	"github.com/Defacto2/server/internal/postgres/models"
+	_ "golang.org/x/image/webp" // webp format decoder
+)
+
+var ErrSize = errors.New("file is too small to fingerprint")
+
+// Kind is the type of fingerprint of an artifact.
+type Kind string
+
+const (
+	Member Kind = "member" // Member is the SHA-384 hash of a file, either within an archive or the download.
+	Text   Kind = "text"   // Text is the SHA-384 hash of a normalized NFO, DIZ or README text.
+	Image  Kind = "image"  // Image is the 64-bit perceptual difference hash of the thumbnail.
+)
+
+// Kinds returns all the kinds of fingerprints.
+func Kinds() []Kind {
+	return []Kind{Member, Text, Image}
+}
+
+// Description returns the reason shown to the editors of two artifacts that share the fingerprint kind.
+func (k Kind) Description() string {
+	switch k {
+	case Member:
+		return "Same archived files"
+	case Text:
+		return "Same text"
+	case Image:
+		return "Similar image"
+	}
+	return ""
+}
+
+const (
+	MinSize     = 64 // MinSize in bytes of a file that is fingerprinted, smaller files are too common to compare.
+	MinText     = 80 // MinText is the minimum number of characters of a normalized text that is fingerprinted.
+	MaxDistance = 3  // MaxDistance is the number of differing bits of two similar perceptual hashes.
+)
+
+// Hash is a fingerprint of an artifact.
+type Hash struct {
+	Kind  Kind   // Kind of fingerprint.
+	Value string // Value is the hexadecimal hash.
+	Name  string // Name is the path of the file within the archive, or the name of the image or text.
+}
+
+// Sources are the directories used to locate the files of the artifacts.
+type Sources struct {
+	Download  string // Download is the directory path for the file downloads.
+	Extra     string // Extra is the directory path for the extra files.
+	Thumbnail string // Thumbnail is the directory path for the thumbnail images.
+}
+
+// Modified returns the most recent modification time of the artifact files that are fingerprinted.
+// A zero time is returned when there are no files.
+func (s *Sources) Modified(art *models.File) time.Time {
+	if art == nil || !art.UUID.Valid {
+		return time.Time{}
+	}
+	unid := art.UUID.String
+	names := []string{
+		filepath.Join(s.Download, unid),
+		filepath.Join(s.Extra, unid+".txt"),
+		filepath.Join(s.Extra, unid+".diz"),
+	}
+	if name := s.thumbnail(unid); name != "" {
+		names = append(names, name)
+	}
+	var last time.Time
+	for _, name := range names {
+		st, err := os.Stat(name)
+		if err != nil {
+			continue
+		}
+		if mod := st.ModTime(); mod.After(last) {
+			last = mod
+		}
+	}
+	// the database stores timestamps to the microsecond
+	return last.Truncate(time.Microsecond)
+}
+
+// Hashes returns the fingerprints of the artifact. The archived files are extracted
+// to a temporary directory, which is removed once the files are hashed.
+// Duplicate fingerprints, such as an archived NFO with a copy in the extra directory, are only returned once.
+func (s *Sources) Hashes(art *models.File) []Hash {
+	hashes := []Hash{}
+	if art == nil || !art.UUID.Valid {
+		return hashes
+	}
+	unid := art.UUID.String
+	add := func(h Hash) {
+		for _, x := range hashes {
+			if x.Kind == h.Kind && x.Value == h.Value {
+				return
+			}
+		}
+		hashes = append(hashes, h)
+	}
+	file := func(path, name string) {
+		if fulltext.IsText(name) {
+			if b, err := readText(path); err == nil {
+				if value, ok := TextHash(b); ok {
+					add(Hash{Kind: Text, Value: value, Name: name})
+					return
+				}
+			}
+		}
+		if value, err := FileHash(path); err == nil {
+			add(Hash{Kind: Member, Value: value, Name: name})
+		}
+	}
+	for _, ext := range []string{".diz", ".txt"} {
+		if b, err := readText(filepath.Join(s.Extra, unid+ext)); err == nil {
+			if value, ok := TextHash(b); ok {
+				add(Hash{Kind: Text, Value: value, Name: unid + ext})
+			}
+		}
+	}
+	if name := s.thumbnail(unid); name != "" {
+		if value, err := ImageHash(name); err == nil && value != 0 {
+			add(Hash{Kind: Image, Value: HexHash(value), Name: filepath.Base(name)})
+		}
+	}
+	src := filepath.Join(s.Download, unid)
+	if _, err := os.Stat(src); err != nil {
+		return hashes
+	}
+	if strings.TrimSpace(art.FileZipContent.String) == "" {
+		file(src, art.Filename.String)
+		return hashes
+	}
+	tmp, err := archive.ExtractSource(src, art.Filename.String)
+	if err != nil {
+		file(src, art.Filename.String)
+		return hashes
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	_ = filepath.WalkDir(tmp, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil //nolint:nilerr
+		}
+		rel, err := filepath.Rel(tmp, path)
+		if err != nil {
+			rel = d.Name()
+		}
+		file(path, filepath.ToSlash(rel))
+		return nil
+	})
+	return hashes
+}
+
+// thumbnail returns the named thumbnail image of the unid or an empty string if there is none.
+func (s *Sources) thumbnail(unid string) string {
+	for _, ext := range []string{".webp", ".png", ".jpg", ".gif"} {
+		name := filepath.Join(s.Thumbnail, unid+ext)
+		if st, err := os.Stat(name); err == nil && !st.IsDir() {
+			return name
+		}
+	}
+	return ""
+}
+
+// readText reads the named text file when it is not too large to normalize.
+func readText(name string) ([]byte, error) {
+	st, err := os.Stat(name)
+	if err != nil {
+		return nil, fmt.Errorf("dupe read text: %w", err)
+	}
+	if st.Size() > fulltext.MaxSize {
+		return nil, fmt.Errorf("dupe read text %q: %w", name, fulltext.ErrNoBody)
+	}
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("dupe read text: %w", err)
+	}
+	return b, nil
+}
+
+// FileHash returns the SHA-384 hexadecimal hash of the named file.
+// An error is returned if the file is smaller than the [MinSize].
+func FileHash(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", fmt.Errorf("dupe file hash: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	st, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("dupe file hash: %w", err)
+	}
+	if st.Size() < MinSize {
+		return "", fmt.Errorf("dupe file hash %q: %w", name, ErrSize)
+	}
+	h := sha512.New384()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("dupe file hash: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// advert matches the lines of a text that are likely part of a BBS advert,
+// such as the board name, the phone number, the sysop or the modem speed.
+var advert = regexp.MustCompile(`(?i)\b(bbs|sysop|co-?sysop|nodes?|baud|usr|courier|hst|v\.?32|v\.?34|` +
+	`whq|ehq|chq|dist(ribution)?\s+site|uploaded\s+by|downloaded\s+from|call)\b|` +
+	`\(?\d{3}\)?[ .-]\d{3}[ .-]\d{4}`)
+
+// Normalize returns the plain text of b without the lines of any BBS advert and without any white space,
+// so the texts that only differ by their layout or an added advert share the same normalized text.
+func Normalize(b []byte) string {
+	var sb strings.Builder
+	lines := bytes.FieldsFunc(b, func(r rune) bool {
+		return r == '\n' || r == '\r'
+	})
+	for _, line := range lines {
+		s := fulltext.Plain(line)
+		if s == "" || advert.MatchString(s) {
+			continue
+		}
+		for _, r := range strings.ToLower(s) {
+			if r == ' ' {
+				continue
+			}
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// TextHash returns the SHA-384 hexadecimal hash of the normalized text of b.
+// It returns false when the normalized text is shorter than the [MinText].
+func TextHash(b []byte) (string, bool) {
+	s := Normalize(b)
+	if len([]rune(s)) < MinText {
+		return "", false
+	}
+	sum := sha512.Sum384([]byte(s))
+	return hex.EncodeToString(sum[:]), true
+}
+
+// ImageHash returns the perceptual difference hash of the named image.
+func ImageHash(name string) (uint64, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return 0, fmt.Errorf("dupe image hash: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return 0, fmt.Errorf("dupe image hash %q: %w", name, err)
+	}
+	return DHash(img), nil
+}
+
+// DHash returns the 64-bit difference hash of the image, which is the image reduced to a grid of
+// 9 by 8 gray cells with each bit set when a cell is brighter than the cell to its right.
+// Resized or recompressed copies of an image have the same or a similar hash.
+// A blank image of a single color returns zero.
+func DHash(img image.Image) uint64 {
+	const cols, rows = 9, 8
+	b := img.Bounds()
+	if b.Dx() < cols || b.Dy() < rows {
+		return 0
+	}
+	var grid [rows][cols]uint64
+	for y := range rows {
+		y0 := b.Min.Y + y*b.Dy()/rows
+		y1 := b.Min.Y + (y+1)*b.Dy()/rows
+		for x := range cols {
+			x0 := b.Min.X + x*b.Dx()/cols
+			x1 := b.Min.X + (x+1)*b.Dx()/cols
+			var sum, n uint64
+			for py := y0; py < y1; py++ {
+				for px := x0; px < x1; px++ {
+					r, g, b, _ := img.At(px, py).RGBA()
+					// the luma of the ITU-R BT.601 conversion
+					sum += (299*uint64(r) + 587*uint64(g) + 114*uint64(b)) / 1000
+					n++
+				}
+			}
+			if n > 0 {
+				grid[y][x] = sum / n
+			}
+		}
+	}
+	var hash uint64
+	for y := range rows {
+		for x := range cols - 1 {
+			hash <<= 1
+			if grid[y][x] > grid[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// HexHash returns the perceptual hash as a 16 character hexadecimal value.
+func HexHash(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+// ParseHash returns the perceptual hash of the hexadecimal value.
+func ParseHash(s string) (uint64, error) {
+	hash, err := strconv.ParseUint(s, 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("dupe parse hash: %w", err)
+	}
+	return hash, nil
+}
+
+// Distance returns the number of differing bits of the two perceptual hashes.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// Pair is two artifacts that share fingerprints of the kind.
+type Pair struct {
+	A      int64 `boil:"a"`      // A is the id of the artifact with the lower id.
+	B      int64 `boil:"b"`      // B is the id of the artifact with the higher id.
+	Kind   Kind  `boil:"kind"`   // Kind of the shared fingerprints.
+	Shared int   `boil:"shared"` // Shared is the number of shared fingerprints, or the differing bits of the images.
+}
+
+// Similar returns the pairs of artifacts with perceptual hashes that differ by no more than the [MaxDistance].
+// The hashes are keyed by the artifact id.
+//
+// As the 64-bit hashes are split into four 16-bit segments, two similar hashes must have at least
+// one identical segment, so only the hashes that share a segment are compared.
+func Similar(hashes map[int64]uint64) []Pair {
+	const segments, width = MaxDistance + 1, 16
+	buckets := make([]map[uint64][]int64, segments)
+	for i := range buckets {
+		buckets[i] = make(map[uint64][]int64)
+	}
+	ids := make([]int64, 0, len(hashes))
+	for id := range hashes {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		for i := range segments {
+			key := hashes[id] >> (i * width) & 0xffff
+			buckets[i][key] = append(buckets[i][key], id)
+		}
+	}
+	seen := make(map[[2]int64]bool)
+	pairs := []Pair{}
+	for _, bucket := range buckets {
+		for _, group := range bucket {
+			for i, a := range group {
+				for _, b := range group[i+1:] {
+					key := [2]int64{a, b}
+					if seen[key] {
+						continue
+					}
+					seen[key] = true
+					d := Distance(hashes[a], hashes[b])
+					if d > MaxDistance {
+						continue
+					}
+					pairs = append(pairs, Pair{A: a, B: b, Kind: Image, Shared: d})
+				}
+			}
+		}
+	}
+	slices.SortFunc(pairs, func(x, y Pair) int {
+		if x.A != y.A {
+			return int(x.A - y.A)
+		}
+		return int(x.B - y.B)
+	})
+	return pairs
+}
+
+// Clusters groups the paired artifacts into the clusters of candidate duplicates.
+// Each cluster is sorted by the artifact id, and the clusters are sorted by their first id.
+func Clusters(pairs ...Pair) [][]int64 {
+	parent := make(map[int64]int64)
+	var find func(id int64) int64
+	find = func(id int64) int64 {
+		p, ok := parent[id]
+		if !ok || p == id {
+			parent[id] = id
+			return id
+		}
+		root := find(p)
+		parent[id] = root
+		return root
+	}
+	for _, p := range pairs {
+		a, b := find(p.A), find(p.B)
+		if a == b {
+			continue
+		}
+		if a < b {
+			parent[b] = a
+			continue
+		}
+		parent[a] = b
+	}
+	groups := make(map[int64][]int64)
+	for id := range parent {
+		root := find(id)
+		groups[root] = append(groups[root], id)
+	}
+	clusters := make([][]int64, 0, len(groups))
+	for _, group := range groups {
+		slices.Sort(group)
+		clusters = append(clusters, group)
+	}
+	slices.SortFunc(clusters, func(x, y []int64) int {
+		return int(x[0] - y[0])
+	})
+	return clusters
+}
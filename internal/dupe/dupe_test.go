@@ -0,0 +1,131 @@
+package dupe_test
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Defacto2/server/internal/dupe"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/null/v8"
+	"github.com/nalgeon/be"
+)
+
+const nfo = "Razor 1911 proudly presents\r\n" +
+	"the latest release of the best game of the year\r\n" +
+	"supplied by an anonymous friend and cracked by the team\r\n"
+
+func TestNormalize(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, dupe.Normalize(nil), "")
+	be.Equal(t, dupe.Normalize([]byte("  Hello \r\n\r\n  World  ")), "helloworld")
+	advert := "Hello\r\nCall The Dark Realm BBS\r\n(212) 555-0100\r\nWorld"
+	be.Equal(t, dupe.Normalize([]byte(advert)), "helloworld")
+}
+
+func TestTextHash(t *testing.T) {
+	t.Parallel()
+	_, ok := dupe.TextHash([]byte("too short"))
+	be.True(t, !ok)
+	a, ok := dupe.TextHash([]byte(nfo))
+	be.True(t, ok)
+	be.Equal(t, len(a), 96)
+	relaid := "   " + strings.ReplaceAll(nfo, " ", "  ") + "\r\nSysop: Anonymous\r\n"
+	b, ok := dupe.TextHash([]byte(relaid))
+	be.True(t, ok)
+	be.Equal(t, a, b)
+	c, _ := dupe.TextHash([]byte(nfo + "greetings to everyone"))
+	be.True(t, a != c)
+}
+
+func gradient(w, h int, reverse bool) image.Image {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			v := uint8(x * 255 / w)
+			if reverse {
+				v = 255 - v
+			}
+			img.SetGray(x, y, color.Gray{Y: v})
+		}
+	}
+	return img
+}
+
+func TestDHash(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, dupe.DHash(image.NewGray(image.Rect(0, 0, 4, 4))), uint64(0))
+	be.Equal(t, dupe.DHash(image.NewGray(image.Rect(0, 0, 90, 80))), uint64(0))
+	small, large := dupe.DHash(gradient(90, 80, true)), dupe.DHash(gradient(400, 300, true))
+	be.True(t, small != 0)
+	be.True(t, dupe.Distance(small, large) <= dupe.MaxDistance)
+	be.True(t, dupe.Distance(small, dupe.DHash(gradient(90, 80, false))) > dupe.MaxDistance)
+}
+
+func TestParseHash(t *testing.T) {
+	t.Parallel()
+	const hash = uint64(0xf0f0_0000_0000_0001)
+	s := dupe.HexHash(hash)
+	be.Equal(t, s, "f0f0000000000001")
+	v, err := dupe.ParseHash(s)
+	be.Err(t, err, nil)
+	be.Equal(t, v, hash)
+	_, err = dupe.ParseHash("xyz")
+	be.Err(t, err)
+}
+
+func TestSimilar(t *testing.T) {
+	t.Parallel()
+	pairs := dupe.Similar(nil)
+	be.Equal(t, len(pairs), 0)
+	hashes := map[int64]uint64{
+		1: 0xffff_0000_ffff_0000,
+		2: 0xffff_0000_ffff_0007, // 3 bits from 1
+		3: 0xffff_0000_ffff_000f, // 4 bits from 1
+		4: 0x0000_ffff_0000_ffff,
+	}
+	pairs = dupe.Similar(hashes)
+	be.Equal(t, len(pairs), 2)
+	be.Equal(t, pairs[0], dupe.Pair{A: 1, B: 2, Kind: dupe.Image, Shared: 3})
+	be.Equal(t, pairs[1], dupe.Pair{A: 2, B: 3, Kind: dupe.Image, Shared: 1})
+}
+
+func TestClusters(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, len(dupe.Clusters()), 0)
+	clusters := dupe.Clusters(
+		dupe.Pair{A: 5, B: 9},
+		dupe.Pair{A: 1, B: 3},
+		dupe.Pair{A: 3, B: 9},
+		dupe.Pair{A: 20, B: 21},
+	)
+	be.Equal(t, clusters, [][]int64{{1, 3, 5, 9}, {20, 21}})
+}
+
+func TestHashes(t *testing.T) {
+	t.Parallel()
+	const unid = "00000000-0000-0000-0000-000000000000"
+	download, extra := t.TempDir(), t.TempDir()
+	err := os.WriteFile(filepath.Join(download, unid), []byte(strings.Repeat("binary", 20)), 0o600)
+	be.Err(t, err, nil)
+	err = os.WriteFile(filepath.Join(extra, unid+".diz"), []byte(nfo), 0o600)
+	be.Err(t, err, nil)
+	err = os.WriteFile(filepath.Join(extra, unid+".txt"), []byte("\r\n"+nfo), 0o600)
+	be.Err(t, err, nil)
+	src := dupe.Sources{Download: download, Extra: extra, Thumbnail: t.TempDir()}
+	art := &models.File{UUID: null.StringFrom(unid), Filename: null.StringFrom("game.exe")}
+	hashes := src.Hashes(art)
+	be.Equal(t, len(hashes), 2)
+	be.Equal(t, hashes[0].Kind, dupe.Text)
+	be.Equal(t, hashes[1].Kind, dupe.Member)
+	be.Equal(t, hashes[1].Name, "game.exe")
+	be.True(t, !src.Modified(art).IsZero())
+
+	art = &models.File{UUID: null.StringFrom("missing")}
+	be.Equal(t, len(src.Hashes(art)), 0)
+	be.True(t, src.Modified(art).IsZero())
+	be.Equal(t, len(src.Hashes(nil)), 0)
+}
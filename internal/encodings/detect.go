@@ -0,0 +1,238 @@
+package encodings
+
+// Package file detect.go contains the detection of the likely text encoding of a text.
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+const (
+	// Low is the confidence percentage below which a detection should be reviewed by an editor.
+	Low = 60
+	// Sample is the maximum number of bytes of a text that are used for the detection.
+	Sample = 64 * 1024
+	// evidence is the minimum score of the detected encoding for a full confidence.
+	evidence = 16
+)
+
+// Detection is the likely text encoding of a text.
+type Detection struct {
+	Key        string // Key of the detected text encoding.
+	Confidence int    // Confidence percentage of the detection, from 0 to 100.
+}
+
+// Low returns true if the detection should be reviewed by an editor.
+func (d Detection) Low() bool {
+	return d.Confidence < Low
+}
+
+// Detect returns the likely text encoding of the text b and the confidence of the detection.
+// Only the CP-437, CP-866, ISO-8859-1, Windows 1251 and 1252, Shift JIS and the Unicode encodings
+// are detected, as the other encodings share too many characters to be told apart.
+//
+// A text without any byte order mark, NUL or 8-bit bytes is reported as CP-437 with a full confidence,
+// as it displays the same using any of the encodings. An empty text returns an empty detection.
+func Detect(b []byte) Detection {
+	if len(b) > Sample {
+		b = b[:Sample]
+	}
+	if len(b) == 0 {
+		return Detection{}
+	}
+	const full = 100
+	switch {
+	case bytes.HasPrefix(b, []byte{0xef, 0xbb, 0xbf}):
+		return Detection{Key: UTF8, Confidence: full}
+	case bytes.HasPrefix(b, []byte{0xff, 0xfe}), bytes.HasPrefix(b, []byte{0xfe, 0xff}):
+		return Detection{Key: UTF16, Confidence: full}
+	}
+	if d, ok := wide(b); ok {
+		return d
+	}
+	high := 0
+	for _, x := range b {
+		if x >= utf8.RuneSelf {
+			high++
+		}
+	}
+	if high == 0 {
+		return Detection{Key: CP437, Confidence: full}
+	}
+	if utf8.Valid(b) {
+		const few = 4
+		if utf8.RuneCount(b) <= len(b)-few {
+			return Detection{Key: UTF8, Confidence: full}
+		}
+		return Detection{Key: UTF8, Confidence: 80}
+	}
+	return best(score(b))
+}
+
+// wide returns the UTF-16 detection of a text without a byte order mark,
+// where most of the even or odd bytes are NUL.
+func wide(b []byte) (Detection, bool) {
+	const minimum = 4
+	if len(b) < minimum {
+		return Detection{}, false
+	}
+	even, odd := 0, 0
+	for i, x := range b {
+		if x != 0 {
+			continue
+		}
+		if i%2 == 0 {
+			even++
+			continue
+		}
+		odd++
+	}
+	half := len(b) / 2
+	switch {
+	case odd*3 > half && even*10 < half:
+		return Detection{Key: UTF16, Confidence: 90}, true
+	case even*3 > half && odd*10 < half:
+		// big-endian text without a byte order mark is decoded as little-endian
+		return Detection{Key: UTF16, Confidence: 40}, true
+	}
+	return Detection{}, false
+}
+
+// scores are the points of the evidence found for each of the detected single and multi-byte encodings.
+type scores struct {
+	cp437, cp866, latin1, win1251, win1252, sjis int
+}
+
+// score returns the points of the evidence found in the text for each encoding.
+func score(b []byte) scores { //nolint:cyclop,funlen
+	var s scores
+	letter := func(i int) bool {
+		if i < 0 || i >= len(b) {
+			return false
+		}
+		x := b[i] | 0x20
+		return x >= 'a' && x <= 'z'
+	}
+	// word returns true if the 8-bit byte is part of a run of varied 8-bit bytes,
+	// as the letters of non-Latin alphabets form words, while the box drawing
+	// characters are mostly repeated.
+	word := func(i int) bool {
+		prev := i > 0 && b[i-1] >= utf8.RuneSelf && b[i-1] != b[i]
+		next := i+1 < len(b) && b[i+1] >= utf8.RuneSelf && b[i+1] != b[i]
+		return prev || next
+	}
+	for i, x := range b {
+		if x < utf8.RuneSelf {
+			continue
+		}
+		adjacent := letter(i-1) || letter(i+1)
+		switch {
+		case x >= 0xb0 && x <= 0xdf:
+			// box drawing, shading and block characters
+			s.cp437 += 2
+		case x <= 0xa5 && adjacent:
+			// accented letters
+			s.cp437++
+		case x == 0xf9, x == 0xfa, x == 0xfe:
+			// bullets and middle dots
+			s.cp437++
+		}
+		switch {
+		case x >= 0xc0 && x != 0xd7 && x != 0xf7 && adjacent && !word(i):
+			s.latin1 += 2
+		case x >= 0xa0 && x <= 0xbf:
+			// symbols such as the copyright, degree and guillemets
+			s.latin1++
+		}
+		switch x {
+		case 0x80, 0x85, 0x91, 0x92, 0x93, 0x94, 0x96, 0x97, 0x99:
+			// euro, ellipsis, curly quotes, dashes and trademark
+			s.win1252 += 2
+		}
+		if word(i) {
+			switch {
+			case x >= 0xe0:
+				// lowercase cyrillic letters
+				s.win1251 += 2
+			case x >= 0xc0:
+				s.win1251++
+			}
+			switch {
+			case (x >= 0xa0 && x <= 0xaf) || (x >= 0xe0 && x <= 0xef):
+				// lowercase cyrillic letters
+				s.cp866 += 2
+			case x <= 0x9f:
+				s.cp866++
+			}
+		}
+	}
+	s.sjis = shiftJIS(b)
+	return s
+}
+
+// shiftJIS returns the points of the double-byte characters of a Shift JIS text,
+// or zero if the text has a byte that cannot be used by the encoding.
+func shiftJIS(b []byte) int {
+	points := 0
+	for i := 0; i < len(b); i++ {
+		x := b[i]
+		switch {
+		case x < utf8.RuneSelf:
+			continue
+		case x >= 0xa1 && x <= 0xdf:
+			// half-width katakana
+			continue
+		case (x >= 0x81 && x <= 0x9f) || (x >= 0xe0 && x <= 0xef):
+			if i+1 >= len(b) {
+				return 0
+			}
+			y := b[i+1]
+			if y < 0x40 || y == 0x7f || y > 0xfc {
+				return 0
+			}
+			if y >= utf8.RuneSelf {
+				points += 2
+			}
+			i++
+		default:
+			return 0
+		}
+	}
+	return points
+}
+
+// best returns the detection of the encoding with the most points, where the confidence
+// is its share of all the points, which is reduced when there is little evidence.
+// Ties are given to CP-437 as it is the most common encoding of the texts.
+func best(s scores) Detection {
+	latin, latinKey := s.latin1, Latin1
+	if s.win1252 > 0 {
+		latin, latinKey = s.latin1+s.win1252, Windows
+	}
+	candidates := []struct {
+		key    string
+		points int
+	}{
+		{CP437, s.cp437},
+		{latinKey, latin},
+		{CP866, s.cp866},
+		{Cyrillic, s.win1251},
+		{ShiftJIS, s.sjis},
+	}
+	total, top := 0, 0
+	for i, c := range candidates {
+		total += c.points
+		if c.points > candidates[top].points {
+			top = i
+		}
+	}
+	winner := candidates[top]
+	if total == 0 {
+		return Detection{Key: CP437, Confidence: 0}
+	}
+	confidence := winner.points * 100 / total
+	if winner.points < evidence {
+		confidence = confidence * winner.points / evidence
+	}
+	return Detection{Key: winner.key, Confidence: confidence}
+}
@@ -0,0 +1,202 @@
+// Package encodings names the text encodings, or character sets, that an artifact text can use,
+// so an editor can override the encoding that is otherwise determined from the platform and the
+// byte content of the text. It also detects the likely encoding of a text with a confidence score,
+// to report the texts that should be reviewed.
+//
+// The DOS texts of the Scene mostly use IBM CP-437, while the Amiga texts use ISO-8859-1 with the
+// Topaz font. The other encodings are less common and are often only found in the texts of the
+// European, Russian and Japanese groups.
+package encodings
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+var ErrCharset = errors.New("text encoding is unknown")
+
+// Keys of the text encodings that are stored for the artifacts.
+const (
+	CP437      = "cp437"        // CP437 is the IBM PC, MS-DOS code page 437.
+	CP850      = "cp850"        // CP850 is the MS-DOS Latin-1 code page 850.
+	CP866      = "cp866"        // CP866 is the MS-DOS Cyrillic code page 866.
+	Latin1     = "iso-8859-1"   // Latin1 is ISO-8859-1, the Western European character set.
+	Windows    = "windows-1252" // Windows is the Windows Western European code page 1252.
+	Cyrillic   = "windows-1251" // Cyrillic is the Windows Cyrillic code page 1251.
+	ShiftJIS   = "shift_jis"    // ShiftJIS is the Japanese Shift JIS multi-byte encoding.
+	UTF8       = "utf-8"        // UTF8 is the Unicode UTF-8 encoding.
+	UTF16      = "utf-16"       // UTF16 is the little-endian Unicode UTF-16 encoding, unless the text has a byte order mark.
+	AmigaTopaz = "amiga-topaz"  // AmigaTopaz is ISO-8859-1 displayed using the Commodore Amiga Topaz font.
+)
+
+// Charset is a text encoding that can be used by an artifact.
+type Charset struct {
+	Key  string // Key is the stored and submitted value of the encoding.
+	Name string // Name is the description of the encoding.
+	enc  encoding.Encoding
+}
+
+// Encoding returns the encoding to decode the text.
+func (c Charset) Encoding() encoding.Encoding { //nolint:ireturn
+	return c.enc
+}
+
+// Charmap returns the single-byte character map of the encoding,
+// or nil when the encoding uses multiple bytes for a character.
+func (c Charset) Charmap() *charmap.Charmap {
+	cm, _ := c.enc.(*charmap.Charmap)
+	return cm
+}
+
+// Topaz returns true if the text is displayed using the Amiga Topaz font.
+func (c Charset) Topaz() bool {
+	return c.Key == AmigaTopaz
+}
+
+// Wide returns true if the encoding uses the NUL byte within its characters,
+// and so the text must be decoded before it can be treated as a plain text.
+func (c Charset) Wide() bool {
+	return c.Key == UTF16
+}
+
+// Native returns true if the text can be displayed without decoding,
+// as the site has fonts for the CP-437, ISO-8859-1 and Unicode character sets.
+func (c Charset) Native() bool {
+	switch c.Key {
+	case CP437, Latin1, AmigaTopaz, UTF8:
+		return true
+	}
+	return false
+}
+
+// List returns the text encodings in their display order.
+func List() []Charset {
+	return []Charset{
+		{CP437, "IBM PC, MS-DOS (CP-437)", charmap.CodePage437},
+		{CP850, "MS-DOS Latin-1 (CP-850)", charmap.CodePage850},
+		{CP866, "MS-DOS Cyrillic (CP-866)", charmap.CodePage866},
+		{AmigaTopaz, "Commodore Amiga Topaz (ISO-8859-1)", charmap.ISO8859_1},
+		{Latin1, "ISO-8859-1 Western European", charmap.ISO8859_1},
+		{"iso-8859-2", "ISO-8859-2 Central European", charmap.ISO8859_2},
+		{"iso-8859-3", "ISO-8859-3 South European", charmap.ISO8859_3},
+		{"iso-8859-4", "ISO-8859-4 North European", charmap.ISO8859_4},
+		{"iso-8859-5", "ISO-8859-5 Cyrillic", charmap.ISO8859_5},
+		{"iso-8859-6", "ISO-8859-6 Arabic", charmap.ISO8859_6},
+		{"iso-8859-7", "ISO-8859-7 Greek", charmap.ISO8859_7},
+		{"iso-8859-8", "ISO-8859-8 Hebrew", charmap.ISO8859_8},
+		{"iso-8859-9", "ISO-8859-9 Turkish", charmap.ISO8859_9},
+		{"iso-8859-10", "ISO-8859-10 Nordic", charmap.ISO8859_10},
+		{"iso-8859-13", "ISO-8859-13 Baltic", charmap.ISO8859_13},
+		{"iso-8859-14", "ISO-8859-14 Celtic", charmap.ISO8859_14},
+		{"iso-8859-15", "ISO-8859-15 Western European with Euro", charmap.ISO8859_15},
+		{"iso-8859-16", "ISO-8859-16 South-Eastern European", charmap.ISO8859_16},
+		{"windows-1250", "Windows Central European (1250)", charmap.Windows1250},
+		{Cyrillic, "Windows Cyrillic (1251)", charmap.Windows1251},
+		{Windows, "Windows Western European (1252)", charmap.Windows1252},
+		{"windows-1253", "Windows Greek (1253)", charmap.Windows1253},
+		{"windows-1254", "Windows Turkish (1254)", charmap.Windows1254},
+		{"windows-1255", "Windows Hebrew (1255)", charmap.Windows1255},
+		{"windows-1256", "Windows Arabic (1256)", charmap.Windows1256},
+		{"windows-1257", "Windows Baltic (1257)", charmap.Windows1257},
+		{"windows-1258", "Windows Vietnamese (1258)", charmap.Windows1258},
+		{ShiftJIS, "Japanese Shift JIS", japanese.ShiftJIS},
+		{UTF8, "Unicode UTF-8", unicode.UTF8},
+		{UTF16, "Unicode UTF-16", unicode.UTF16(unicode.LittleEndian, unicode.UseBOM)},
+	}
+}
+
+// Find returns the text encoding of the key.
+// It returns false when the key is empty or unknown.
+func Find(key string) (Charset, bool) {
+	key = strings.ToLower(strings.TrimSpace(key))
+	for _, c := range List() {
+		if c.Key == key {
+			return c, true
+		}
+	}
+	return Charset{}, false
+}
+
+// Valid returns true if the key is empty, which is the automatic detection of the encoding,
+// or the key of a known text encoding.
+func Valid(key string) bool {
+	if strings.TrimSpace(key) == "" {
+		return true
+	}
+	_, ok := Find(key)
+	return ok
+}
+
+// Decode returns the text b decoded from the encoding of the key to UTF-8.
+// Any byte order mark is removed.
+func Decode(b []byte, key string) ([]byte, error) {
+	c, ok := Find(key)
+	if !ok {
+		return nil, fmt.Errorf("decode %q: %w", key, ErrCharset)
+	}
+	p, err := c.enc.NewDecoder().Bytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("decode %q: %w", key, err)
+	}
+	return bytes.TrimPrefix(p, []byte("\ufeff")), nil
+}
+
+// Glyphs returns the text b of the encoding of the key re-encoded to the character set of the
+// bitmap font used to draw the text, either CP-437 or ISO-8859-1 for the Amiga Topaz font.
+// The characters that are missing from the font are replaced with a question mark.
+// The text is returned unmodified when the encoding already uses the character set of the font.
+func Glyphs(b []byte, key string) []byte {
+	c, ok := Find(key)
+	if !ok {
+		return b
+	}
+	font := charmap.CodePage437
+	if c.Topaz() {
+		font = charmap.ISO8859_1
+	}
+	if c.enc == font {
+		return b
+	}
+	p, err := Decode(b, key)
+	if err != nil {
+		return b
+	}
+	buf := make([]byte, 0, len(p))
+	for len(p) > 0 {
+		r, size := utf8.DecodeRune(p)
+		p = p[size:]
+		if r < utf8.RuneSelf {
+			buf = append(buf, byte(r))
+			continue
+		}
+		if x, ok := font.EncodeRune(r); ok {
+			buf = append(buf, x)
+			continue
+		}
+		buf = append(buf, '?')
+	}
+	return buf
+}
+
+// Rule returns the key of the text encoding that is always used by the platform or section
+// of an artifact. It returns false when the encoding must be detected from the text.
+func Rule(platform, section string) (string, bool) {
+	platform = strings.ToLower(strings.TrimSpace(platform))
+	section = strings.ToLower(strings.TrimSpace(section))
+	if platform == "textamiga" {
+		return AmigaTopaz, true
+	}
+	switch section {
+	case "appleii", "atarist":
+		return Latin1, true
+	}
+	return "", false
+}
@@ -0,0 +1,151 @@
+package encodings_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Defacto2/server/internal/encodings"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+)
+
+func TestFind(t *testing.T) {
+	t.Parallel()
+	_, ok := encodings.Find("")
+	be.True(t, !ok)
+	_, ok = encodings.Find("ebcdic")
+	be.True(t, !ok)
+	c, ok := encodings.Find(" CP437 ")
+	be.True(t, ok)
+	be.Equal(t, c.Key, encodings.CP437)
+	be.True(t, c.Native())
+	be.True(t, c.Charmap() == charmap.CodePage437)
+	c, ok = encodings.Find(encodings.ShiftJIS)
+	be.True(t, ok)
+	be.True(t, c.Charmap() == nil)
+	be.True(t, !c.Native())
+	c, _ = encodings.Find(encodings.UTF16)
+	be.True(t, c.Wide())
+	c, _ = encodings.Find(encodings.AmigaTopaz)
+	be.True(t, c.Topaz())
+	be.True(t, encodings.Valid(""))
+	be.True(t, encodings.Valid("windows-1250"))
+	be.True(t, !encodings.Valid("iso-8859-12"))
+	for _, c := range encodings.List() {
+		be.True(t, c.Name != "")
+		be.True(t, c.Encoding() != nil)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+	_, err := encodings.Decode([]byte("x"), "ebcdic")
+	be.Err(t, err, encodings.ErrCharset)
+	b, err := encodings.Decode([]byte{0xc9, 0xcd, 0xbb}, encodings.CP437)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "╔═╗")
+	b, err = encodings.Decode([]byte{0xaf, 0xe0, 0xa8}, encodings.CP866)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "при")
+	b, err = encodings.Decode([]byte{0xff, 0xfe, 'h', 0, 'i', 0}, encodings.UTF16)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "hi")
+}
+
+func TestGlyphs(t *testing.T) {
+	t.Parallel()
+	src := []byte{0xc9, 0xcd}
+	be.Equal(t, encodings.Glyphs(src, encodings.CP437), src)
+	be.Equal(t, encodings.Glyphs(src, "unknown"), src)
+	// the cyrillic box drawing characters share the cp437 values
+	b := encodings.Glyphs([]byte{0xc9, 0xaf, '!'}, encodings.CP866)
+	be.Equal(t, b, []byte{0xc9, '?', '!'})
+	b = encodings.Glyphs([]byte("\x1b[0m╔é"), encodings.UTF8)
+	be.Equal(t, b, []byte{0x1b, '[', '0', 'm', 0xc9, 0x82})
+	b = encodings.Glyphs([]byte("é"), encodings.AmigaTopaz)
+	be.Equal(t, b, []byte("é"))
+}
+
+func TestRule(t *testing.T) {
+	t.Parallel()
+	key, ok := encodings.Rule("TextAmiga", "")
+	be.True(t, ok)
+	be.Equal(t, key, encodings.AmigaTopaz)
+	key, ok = encodings.Rule("text", "atarist")
+	be.True(t, ok)
+	be.Equal(t, key, encodings.Latin1)
+	_, ok = encodings.Rule("text", "magazine")
+	be.True(t, !ok)
+}
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, encodings.Detect(nil), encodings.Detection{})
+	d := encodings.Detect([]byte("plain ascii text"))
+	be.Equal(t, d, encodings.Detection{Key: encodings.CP437, Confidence: 100})
+	be.True(t, !d.Low())
+
+	d = encodings.Detect([]byte("Crème brûlée, déjà vu, naïve café résumé"))
+	be.Equal(t, d.Key, encodings.UTF8)
+
+	d = encodings.Detect([]byte{0xff, 0xfe, 'a', 0})
+	be.Equal(t, d.Key, encodings.UTF16)
+	d = encodings.Detect([]byte{'a', 0, 'b', 0, 'c', 0, 'd', 0})
+	be.Equal(t, d.Key, encodings.UTF16)
+
+	art := strings.Repeat("  ╔══════╗ ░▒▓█ DEFACTO2 █▓▒░ ╚══════╝\r\n", 4)
+	b, err := charmap.CodePage437.NewEncoder().Bytes([]byte(art))
+	be.Err(t, err, nil)
+	d = encodings.Detect(b)
+	be.Equal(t, d.Key, encodings.CP437)
+	be.True(t, !d.Low())
+
+	const french = "Le café était déjà fermé, la crème brûlée était à la maison. Très bien, à bientôt!\n"
+	b, err = charmap.ISO8859_1.NewEncoder().Bytes([]byte(strings.Repeat(french, 2)))
+	be.Err(t, err, nil)
+	d = encodings.Detect(b)
+	be.Equal(t, d.Key, encodings.Latin1)
+	be.True(t, !d.Low())
+
+	const quotes = "“Greetings” to all — the crew’s new release… enjoy it ™\n"
+	b, err = charmap.Windows1252.NewEncoder().Bytes([]byte(strings.Repeat(quotes, 3)))
+	be.Err(t, err, nil)
+	d = encodings.Detect(b)
+	be.Equal(t, d.Key, encodings.Windows)
+
+	const russian = "привет всем нашим друзьям, это новый релиз нашей группы\n"
+	b, err = charmap.CodePage866.NewEncoder().Bytes([]byte(strings.Repeat(russian, 2)))
+	be.Err(t, err, nil)
+	d = encodings.Detect(b)
+	be.Equal(t, d.Key, encodings.CP866)
+	b, err = charmap.Windows1251.NewEncoder().Bytes([]byte(strings.Repeat(russian, 2)))
+	be.Err(t, err, nil)
+	d = encodings.Detect(b)
+	be.Equal(t, d.Key, encodings.Cyrillic)
+
+	const japan = "こんにちは、新しいリリースです。グループの皆さんによろしく。\n"
+	b, err = japanese.ShiftJIS.NewEncoder().Bytes([]byte(strings.Repeat(japan, 2)))
+	be.Err(t, err, nil)
+	d = encodings.Detect(b)
+	be.Equal(t, d.Key, encodings.ShiftJIS)
+
+	// a single accented letter is too little evidence
+	d = encodings.Detect([]byte("caf\x82"))
+	be.True(t, d.Low())
+}
+
+func TestTranscode(t *testing.T) {
+	t.Parallel()
+	_, err := encodings.Transcode([]byte("x"), "ebcdic")
+	be.Err(t, err, encodings.ErrCharset)
+	b, err := encodings.Transcode([]byte{0xc9, 0xcd, 0xbb, '\r', '\n', 0x01, 0x03, 0x10, 0x7f, 0x00}, encodings.CP437)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "╔═╗\n☺♥►⌂ ")
+	b, err = encodings.Transcode([]byte("\x1b[1;33mhi\x1b"), encodings.CP437)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "\x1b[1;33mhi←")
+	b, err = encodings.Transcode([]byte{'c', 'a', 'f', 0xe9, 0x01}, encodings.AmigaTopaz)
+	be.Err(t, err, nil)
+	be.Equal(t, string(b), "café\x01")
+}
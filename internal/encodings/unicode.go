@@ -0,0 +1,68 @@
+package encodings
+
+// Package file unicode.go contains the transcoding of the texts to UTF-8 for use outside of the site fonts.
+
+import (
+	"bytes"
+	"strings"
+)
+
+// glyphs are the Unicode characters drawn by the IBM PC for the control codes of the DOS code pages.
+// The tab, line feed and carriage return are not included as they are used for the layout of a text.
+var glyphs = map[rune]rune{ //nolint:gochecknoglobals
+	0x00: ' ', 0x01: '☺', 0x02: '☻', 0x03: '♥', 0x04: '♦', 0x05: '♣', 0x06: '♠', 0x07: '•',
+	0x08: '◘', 0x0b: '♂', 0x0c: '♀', 0x0e: '♫', 0x0f: '☼',
+	0x10: '►', 0x11: '◄', 0x12: '↕', 0x13: '‼', 0x14: '¶', 0x15: '§', 0x16: '▬', 0x17: '↨',
+	0x18: '↑', 0x19: '↓', 0x1a: '→', 0x1b: '←', 0x1c: '∟', 0x1d: '↔', 0x1e: '▲', 0x1f: '▼',
+	0x7f: '⌂',
+}
+
+// Dos returns true if the encoding is a MS-DOS code page, where the control codes
+// are displayed as glyphs such as the smiley faces, arrows and card suits.
+func (c Charset) Dos() bool {
+	switch c.Key {
+	case CP437, CP850, CP866:
+		return true
+	}
+	return false
+}
+
+// Transcode returns the text b of the encoding of the key as UTF-8 text that can be read
+// without the site fonts, such as in a terminal, a Markdown document or by assistive technology.
+//
+// The CP-437 box drawing and block characters are replaced with their Unicode equivalents, and the
+// control codes of the DOS code pages are replaced with the glyphs that are drawn by the IBM PC.
+// The escape control is kept when it starts an ANSI escape sequence, so the colors of an ANSI text
+// still display in a terminal. The line endings are converted to line feeds.
+func Transcode(b []byte, key string) ([]byte, error) {
+	c, ok := Find(key)
+	if !ok {
+		return nil, ErrCharset
+	}
+	p, err := Decode(b, c.Key)
+	if err != nil {
+		return nil, err
+	}
+	p = bytes.ReplaceAll(p, []byte("\r\n"), []byte("\n"))
+	const esc, csi = 0x1b, '['
+	s := []rune(string(p))
+	var sb strings.Builder
+	sb.Grow(len(p))
+	for i, r := range s {
+		switch {
+		case r == 0x00:
+			sb.WriteRune(' ')
+		case r == esc && i+1 < len(s) && s[i+1] == csi:
+			sb.WriteRune(r)
+		case c.Dos():
+			if g, ok := glyphs[r]; ok {
+				sb.WriteRune(g)
+				continue
+			}
+			sb.WriteRune(r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return []byte(sb.String()), nil
+}
@@ -0,0 +1,397 @@
+// Package export writes the public catalogue of artifacts to portable files,
+// such as JSON Lines, CSV and SQLite, that can be used offline without the
+// PostgreSQL database or as a database independent backup.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Defacto2/helper"
+	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/Defacto2/server/internal/tags"
+	"github.com/Defacto2/server/model"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+)
+
+var (
+	ErrFormat = errors.New("export format is invalid")
+	ErrOrder  = errors.New("export records must be in ascending id order")
+	ErrWriter = errors.New("export writer is closed")
+)
+
+// Format is the file format of the catalogue export.
+type Format string
+
+const (
+	JSONL  Format = "jsonl"  // JSONL is JSON Lines, a JSON object per line.
+	CSV    Format = "csv"    // CSV is comma-separated values with a header row.
+	SQLite Format = "sqlite" // SQLite is a SQLite 3 database file with an artifacts table.
+)
+
+// Formats returns the names of the export formats.
+func Formats() []string {
+	return []string{string(JSONL), string(CSV), string(SQLite)}
+}
+
+// Valid returns true if the format is known.
+func (f Format) Valid() bool {
+	return slices.Contains(Formats(), string(f))
+}
+
+// Options are the optional columns of the catalogue export.
+type Options struct {
+	Checksums bool          // Checksums includes the SHA-384 hash of the artifact download.
+	Assets    bool          // Assets includes the paths of the download, preview and thumbnail files.
+	Download  dir.Directory // Download is the directory of the UUID named artifact downloads.
+	Preview   dir.Directory // Preview is the directory of the UUID named preview images.
+	Thumbnail dir.Directory // Thumbnail is the directory of the UUID named thumbnail images.
+}
+
+// Link is a named URL of a related artifact or website.
+type Link struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// Record is a public artifact of the catalogue export.
+type Record struct {
+	ID            int64    `json:"id"`
+	UUID          string   `json:"uuid"`
+	URL           string   `json:"url"`
+	Title         string   `json:"title,omitempty"`
+	Filename      string   `json:"filename,omitempty"`
+	Filesize      int64    `json:"filesize,omitempty"`
+	MagicType     string   `json:"magic_type,omitempty"`
+	Platform      string   `json:"platform,omitempty"`
+	Section       string   `json:"section,omitempty"`
+	Tags          string   `json:"tags,omitempty"`
+	Releasers     []string `json:"releasers,omitempty"`
+	Year          int16    `json:"year,omitempty"`
+	Month         int16    `json:"month,omitempty"`
+	Day           int16    `json:"day,omitempty"`
+	Writers       string   `json:"writers,omitempty"`
+	Artists       string   `json:"artists,omitempty"`
+	Programmers   string   `json:"programmers,omitempty"`
+	Musicians     string   `json:"musicians,omitempty"`
+	Comment       string   `json:"comment,omitempty"`
+	Relations     []Link   `json:"relations,omitempty"`
+	Links         []Link   `json:"links,omitempty"`
+	Demozoo       int64    `json:"demozoo,omitempty"`
+	Pouet         int64    `json:"pouet,omitempty"`
+	SixteenColors string   `json:"sixteen_colors,omitempty"`
+	YouTube       string   `json:"youtube,omitempty"`
+	GitHub        string   `json:"github,omitempty"`
+	LastModified  string   `json:"last_modified,omitempty"`
+	Created       string   `json:"created,omitempty"`
+	Updated       string   `json:"updated,omitempty"`
+	SHA384        string   `json:"sha384,omitempty"`
+	Download      string   `json:"download,omitempty"`
+	Preview       string   `json:"preview,omitempty"`
+	Thumbnail     string   `json:"thumbnail,omitempty"`
+}
+
+// NewRecord returns the catalogue record of the file record.
+// The checksum and the asset paths are only included when requested by the options.
+func NewRecord(f *models.File, opt Options) Record {
+	if f == nil {
+		return Record{}
+	}
+	r := Record{
+		ID:            f.ID,
+		UUID:          f.UUID.String,
+		URL:           "https://defacto2.net/f/" + helper.ObfuscateID(f.ID),
+		Title:         strings.TrimSpace(f.RecordTitle.String),
+		Filename:      f.Filename.String,
+		Filesize:      f.Filesize.Int64,
+		MagicType:     f.FileMagicType.String,
+		Platform:      f.Platform.String,
+		Section:       f.Section.String,
+		Year:          f.DateIssuedYear.Int16,
+		Month:         f.DateIssuedMonth.Int16,
+		Day:           f.DateIssuedDay.Int16,
+		Writers:       f.CreditText.String,
+		Artists:       f.CreditIllustration.String,
+		Programmers:   f.CreditProgram.String,
+		Musicians:     f.CreditAudio.String,
+		Comment:       f.Comment.String,
+		Relations:     relations(f.ListRelations.String),
+		Links:         links(f.ListLinks.String),
+		Demozoo:       f.WebIDDemozoo.Int64,
+		Pouet:         f.WebIDPouet.Int64,
+		SixteenColors: f.WebID16colors.String,
+		YouTube:       f.WebIDYoutube.String,
+		GitHub:        f.WebIDGithub.String,
+		LastModified:  timestamp(f.FileLastModified.Time, f.FileLastModified.Valid),
+		Created:       timestamp(f.Createdat.Time, f.Createdat.Valid),
+		Updated:       timestamp(f.Updatedat.Time, f.Updatedat.Valid),
+	}
+	if r.Platform != "" || r.Section != "" {
+		r.Tags = tags.Humanize(tags.TagByURI(r.Platform), tags.TagByURI(r.Section))
+	}
+	for _, name := range []string{f.GroupBrandFor.String, f.GroupBrandBy.String} {
+		if s := strings.TrimSpace(name); s != "" {
+			r.Releasers = append(r.Releasers, s)
+		}
+	}
+	if opt.Checksums {
+		r.SHA384 = f.FileIntegrityStrong.String
+	}
+	if opt.Assets && r.UUID != "" {
+		unid := strings.ToLower(r.UUID)
+		r.Download = asset(opt.Download, unid, "")
+		r.Preview = asset(opt.Preview, unid, ".webp", ".png", ".jpg", ".avif")
+		r.Thumbnail = asset(opt.Thumbnail, unid, ".webp", ".png")
+	}
+	return r
+}
+
+// Kind is the SQL storage class of a column.
+type Kind int
+
+const (
+	Integer Kind = iota // Integer is a signed 64-bit integer column.
+	Text                // Text is a UTF-8 text column.
+)
+
+// Field is a named column value of the record.
+// The value is either an int64, a string or nil for an empty value.
+type Field struct {
+	Name  string
+	Kind  Kind
+	Value any
+}
+
+// Fields returns the columns and the values of the record in the order of the export.
+// The values of the optional columns are only included when requested by the options.
+// Empty strings and zero numbers, other than the id, are returned as nil values.
+func (r Record) Fields(opt Options) []Field {
+	integer := func(name string, i int64) Field {
+		if i == 0 {
+			return Field{Name: name, Kind: Integer, Value: nil}
+		}
+		return Field{Name: name, Kind: Integer, Value: i}
+	}
+	text := func(name, s string) Field {
+		if s == "" {
+			return Field{Name: name, Kind: Text, Value: nil}
+		}
+		return Field{Name: name, Kind: Text, Value: s}
+	}
+	fields := []Field{
+		{Name: "id", Kind: Integer, Value: r.ID},
+		text("uuid", r.UUID),
+		text("url", r.URL),
+		text("title", r.Title),
+		text("filename", r.Filename),
+		integer("filesize", r.Filesize),
+		text("magic_type", r.MagicType),
+		text("platform", r.Platform),
+		text("section", r.Section),
+		text("tags", r.Tags),
+		text("releasers", strings.Join(r.Releasers, "+")),
+		integer("year", int64(r.Year)),
+		integer("month", int64(r.Month)),
+		integer("day", int64(r.Day)),
+		text("writers", r.Writers),
+		text("artists", r.Artists),
+		text("programmers", r.Programmers),
+		text("musicians", r.Musicians),
+		text("comment", r.Comment),
+		text("relations", joinLinks(r.Relations)),
+		text("links", joinLinks(r.Links)),
+		integer("demozoo", r.Demozoo),
+		integer("pouet", r.Pouet),
+		text("sixteen_colors", r.SixteenColors),
+		text("youtube", r.YouTube),
+		text("github", r.GitHub),
+		text("last_modified", r.LastModified),
+		text("created", r.Created),
+		text("updated", r.Updated),
+	}
+	if opt.Checksums {
+		fields = append(fields, text("sha384", r.SHA384))
+	}
+	if opt.Assets {
+		fields = append(fields,
+			text("download", r.Download),
+			text("preview", r.Preview),
+			text("thumbnail", r.Thumbnail))
+	}
+	return fields
+}
+
+// Columns returns the column names of the export.
+func Columns(opt Options) []string {
+	fields := Record{}.Fields(opt)
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Writer writes the catalogue records to a file format.
+type Writer interface {
+	// Write the record, the records must be written in ascending id order.
+	Write(r *Record) error
+	// Close flushes any buffered data and completes the file, but it does not close the underlying writer.
+	Close() error
+}
+
+// Catalogue streams the public artifacts that match the query mods from the database to the writer,
+// and returns the number of written records. The writer is closed when all records are written.
+func Catalogue(ctx context.Context, exec boil.ContextExecutor, w Writer, opt Options, mods ...qm.QueryMod) (
+	int, error,
+) {
+	const msg = "export catalogue"
+	if err := nils.Check(ctx, exec, w); err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	rels, err := model.RelationMap(ctx, exec)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	links, err := model.LinkMap(ctx, exec)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	count, err := model.Export(ctx, exec, func(f *models.File) error {
+		r := NewRecord(f, opt)
+		r.Graph(rels[f.ID], links[f.ID])
+		return w.Write(&r)
+	}, mods...)
+	if err != nil {
+		return count, fmt.Errorf("%s: %w", msg, err)
+	}
+	if err := w.Close(); err != nil {
+		return count, fmt.Errorf("%s close: %w", msg, err)
+	}
+	return count, nil
+}
+
+// Graph appends the relations to other artifacts and the external links of the artifact
+// that are stored in the relation tables, to any links of the legacy list columns.
+// A legacy relation or link that is also stored in the tables is only listed once,
+// where a legacy relation is the same as a stored relation of the other kind to the same artifact.
+func (r *Record) Graph(rels []model.Relation, links []model.Link) {
+	const route = "https://defacto2.net/f/"
+	type key struct {
+		target int64
+		kind   model.RelationKind
+	}
+	seen := make(map[key]bool, len(r.Relations)+len(rels))
+	for _, l := range r.Relations {
+		seen[key{target: model.RelationKey(l.URL), kind: model.RelationOther}] = true
+	}
+	for _, rel := range rels {
+		k := key{target: rel.TargetID, kind: rel.Kind}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		r.Relations = append(r.Relations, Link{Name: rel.Description(), URL: route + helper.ObfuscateID(rel.TargetID)})
+	}
+	urls := make(map[string]bool, len(r.Links)+len(links))
+	for _, l := range r.Links {
+		urls[l.URL] = true
+	}
+	for _, l := range links {
+		if urls[l.URL] {
+			continue
+		}
+		urls[l.URL] = true
+		r.Links = append(r.Links, Link{Name: l.Label, URL: l.URL})
+	}
+}
+
+// asset returns the path of the first named unid file with an extension that exists in the directory,
+// or an empty string if there is no file.
+func asset(d dir.Directory, unid string, exts ...string) string {
+	if d == "" {
+		return ""
+	}
+	if len(exts) == 0 {
+		exts = []string{""}
+	}
+	for _, ext := range exts {
+		name := d.Join(unid + ext)
+		if st, err := os.Stat(name); err == nil && !st.IsDir() {
+			return name
+		}
+	}
+	return ""
+}
+
+// relations returns the links of the list of related artifacts,
+// which are stored as pipe separated, name and obfuscated id pairs, "name;id|name;id".
+func relations(s string) []Link {
+	const route = "https://defacto2.net/f/"
+	var results []Link
+	for _, pair := range split(s) {
+		name, href := pair[0], pair[1]
+		if helper.DeObfuscate(href) == href {
+			continue
+		}
+		results = append(results, Link{Name: name, URL: route + href})
+	}
+	return results
+}
+
+// links returns the links of the list of websites,
+// which are stored as pipe separated, name and URL pairs, "name;url|name;url".
+// A stored URL usually does not include the protocol, so "https://" is used.
+func links(s string) []Link {
+	var results []Link
+	for _, pair := range split(s) {
+		name, href := pair[0], pair[1]
+		if !strings.HasPrefix(href, "http") {
+			href = "https://" + href
+		}
+		if u, err := url.Parse(href); err != nil || u.Host == "" {
+			continue
+		}
+		results = append(results, Link{Name: name, URL: href})
+	}
+	return results
+}
+
+// split returns the name and value pairs of a pipe separated list, "name;value|name;value".
+func split(s string) [][2]string {
+	const expected = 2
+	var pairs [][2]string
+	for item := range strings.SplitSeq(s, "|") {
+		x := strings.Split(strings.TrimSpace(item), ";")
+		if len(x) != expected || x[0] == "" || x[1] == "" {
+			continue
+		}
+		pairs = append(pairs, [2]string{x[0], x[1]})
+	}
+	return pairs
+}
+
+// joinLinks returns the links as a pipe separated list, "name;url|name;url".
+func joinLinks(links []Link) string {
+	s := make([]string, len(links))
+	for i, l := range links {
+		s[i] = l.Name + ";" + l.URL
+	}
+	return strings.Join(s, "|")
+}
+
+// timestamp returns the time as a RFC 3339 string in UTC, or an empty string if the time is not valid.
+func timestamp(t time.Time, valid bool) string {
+	if !valid || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
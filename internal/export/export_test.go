@@ -0,0 +1,217 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/internal/dir"
+	"github.com/Defacto2/server/internal/export"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/Defacto2/server/model"
+	"github.com/aarondl/null/v8"
+	"github.com/nalgeon/be"
+)
+
+func file() *models.File {
+	return &models.File{
+		ID:                  1,
+		UUID:                null.StringFrom("00000000-0000-0000-0000-000000000001"),
+		RecordTitle:         null.StringFrom(" The first release "),
+		Filename:            null.StringFrom("first.zip"),
+		Filesize:            null.Int64From(1024),
+		GroupBrandFor:       null.StringFrom("RAZOR 1911"),
+		GroupBrandBy:        null.StringFrom("SKID ROW"),
+		Platform:            null.StringFrom("dos"),
+		Section:             null.StringFrom("demo"),
+		DateIssuedYear:      null.Int16From(1990),
+		ListRelations:       null.StringFrom("NFO;9b1c6|invalid"),
+		ListLinks:           null.StringFrom("Website;example.com|Archive;http://example.org/x"),
+		FileIntegrityStrong: null.StringFrom("abcdef"),
+		Createdat:           null.TimeFrom(time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC)),
+	}
+}
+
+// buffer is an in-memory io.WriterAt.
+type buffer struct {
+	b []byte
+}
+
+func (w *buffer) WriteAt(p []byte, off int64) (int, error) {
+	if end := int(off) + len(p); end > len(w.b) {
+		w.b = append(w.b, make([]byte, end-len(w.b))...)
+	}
+	return copy(w.b[off:], p), nil
+}
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, len(export.Formats()), 3)
+	be.True(t, export.JSONL.Valid())
+	be.True(t, export.SQLite.Valid())
+	be.True(t, !export.Format("xml").Valid())
+}
+
+func TestNewRecord(t *testing.T) {
+	t.Parallel()
+	r := export.NewRecord(nil, export.Options{})
+	be.Equal(t, r.ID, int64(0))
+	r = export.NewRecord(file(), export.Options{})
+	be.Equal(t, r.ID, int64(1))
+	be.Equal(t, r.Title, "The first release")
+	be.Equal(t, r.Releasers, []string{"RAZOR 1911", "SKID ROW"})
+	be.True(t, r.Tags != "")
+	be.True(t, strings.HasPrefix(r.URL, "https://defacto2.net/f/"))
+	be.Equal(t, len(r.Links), 2)
+	be.Equal(t, r.Links[0].URL, "https://example.com")
+	be.Equal(t, r.Links[1].URL, "http://example.org/x")
+	be.Equal(t, r.Created, "2000-01-02T03:04:05Z")
+	be.Equal(t, r.Updated, "")
+	be.Equal(t, r.SHA384, "")
+	r = export.NewRecord(file(), export.Options{Checksums: true})
+	be.Equal(t, r.SHA384, "abcdef")
+}
+
+func TestGraph(t *testing.T) {
+	t.Parallel()
+	r := export.NewRecord(file(), export.Options{})
+	before := len(r.Relations)
+	r.Graph([]model.Relation{{FileID: 1, TargetID: 2, Kind: model.RelationNFO}},
+		[]model.Link{{FileID: 1, Kind: model.LinkVideo, Label: "Video", URL: "https://example.com/v"}})
+	be.Equal(t, len(r.Relations), before+1)
+	be.Equal(t, r.Relations[before].Name, "NFO for")
+	be.True(t, strings.HasPrefix(r.Relations[before].URL, "https://defacto2.net/f/"))
+	be.Equal(t, r.Links[len(r.Links)-1], export.Link{Name: "Video", URL: "https://example.com/v"})
+
+	// the unmigrated legacy values that are also in the relation tables are not repeated
+	r = export.NewRecord(file(), export.Options{})
+	rels, urls := len(r.Relations), len(r.Links)
+	be.True(t, rels > 0)
+	target := model.RelationKey(r.Relations[0].URL)
+	r.Graph([]model.Relation{
+		{FileID: 1, TargetID: target, Kind: model.RelationOther, Label: "NFO"},
+		{FileID: 1, TargetID: target, Kind: model.RelationOther, Label: "NFO"},
+	}, []model.Link{{FileID: 1, Kind: model.LinkArchive, Label: "Archive", URL: r.Links[urls-1].URL}})
+	be.Equal(t, len(r.Relations), rels)
+	be.Equal(t, len(r.Links), urls)
+	r.Graph([]model.Relation{{FileID: 1, TargetID: target, Kind: model.RelationNFO}}, nil)
+	be.Equal(t, len(r.Relations), rels+1)
+}
+
+func TestNewRecordAssets(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	f := file()
+	err := os.WriteFile(filepath.Join(tmp, f.UUID.String+".png"), []byte("x"), 0o600)
+	be.Err(t, err, nil)
+	opt := export.Options{Assets: true, Preview: dir.Directory(tmp), Thumbnail: dir.Directory(tmp)}
+	r := export.NewRecord(f, opt)
+	be.Equal(t, r.Download, "")
+	be.Equal(t, r.Preview, filepath.Join(tmp, f.UUID.String+".png"))
+	be.Equal(t, r.Thumbnail, r.Preview)
+}
+
+func TestColumns(t *testing.T) {
+	t.Parallel()
+	cols := export.Columns(export.Options{})
+	be.Equal(t, cols[0], "id")
+	all := export.Columns(export.Options{Checksums: true, Assets: true})
+	be.Equal(t, len(all), len(cols)+4)
+	r := export.NewRecord(file(), export.Options{})
+	fields := r.Fields(export.Options{})
+	be.Equal(t, len(fields), len(cols))
+	for _, f := range fields {
+		switch f.Name {
+		case "id":
+			be.Equal(t, f.Value, any(int64(1)))
+		case "month", "comment":
+			be.Equal(t, f.Value, nil)
+		case "releasers":
+			be.Equal(t, f.Value, any("RAZOR 1911+SKID ROW"))
+		}
+	}
+}
+
+func TestJSONL(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	w := export.NewJSONL(&buf)
+	r := export.NewRecord(file(), export.Options{})
+	be.Err(t, w.Write(&r), nil)
+	r.ID = 2
+	be.Err(t, w.Write(&r), nil)
+	be.Err(t, w.Close(), nil)
+	be.Err(t, w.Write(&r), export.ErrWriter)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	be.Equal(t, len(lines), 2)
+	var got export.Record
+	be.Err(t, json.Unmarshal([]byte(lines[1]), &got), nil)
+	be.Equal(t, got.ID, int64(2))
+	be.Equal(t, got.Releasers, r.Releasers)
+}
+
+func TestCSV(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	opt := export.Options{Checksums: true}
+	w := export.NewCSV(&buf, opt)
+	r := export.NewRecord(file(), opt)
+	be.Err(t, w.Write(&r), nil)
+	be.Err(t, w.Close(), nil)
+	rows, err := csv.NewReader(&buf).ReadAll()
+	be.Err(t, err, nil)
+	be.Equal(t, len(rows), 2)
+	be.Equal(t, rows[0], export.Columns(opt))
+	be.Equal(t, rows[1][0], "1")
+	be.Equal(t, rows[1][len(rows[1])-1], "abcdef")
+
+	buf.Reset()
+	w = export.NewCSV(&buf, opt)
+	be.Err(t, w.Close(), nil)
+	be.Equal(t, strings.Count(buf.String(), "\n"), 1)
+}
+
+func TestSQLite(t *testing.T) {
+	t.Parallel()
+	const pageSize = 4096
+	var buf buffer
+	w := export.NewSQLite(&buf, export.Options{})
+	r := export.NewRecord(file(), export.Options{})
+	for i := range 500 {
+		r.ID = int64(i + 1)
+		r.Comment = strings.Repeat("x", i*20)
+		be.Err(t, w.Write(&r), nil)
+	}
+	be.Err(t, w.Write(&r), export.ErrOrder)
+	be.Err(t, w.Close(), nil)
+	be.Err(t, w.Write(&r), export.ErrWriter)
+	be.True(t, bytes.HasPrefix(buf.b, []byte("SQLite format 3\x00")))
+	be.Equal(t, binary.BigEndian.Uint16(buf.b[16:]), uint16(pageSize))
+	pages := binary.BigEndian.Uint32(buf.b[28:])
+	be.Equal(t, len(buf.b), int(pages)*pageSize)
+	be.True(t, bytes.Contains(buf.b[:pageSize], []byte("CREATE TABLE "+export.SQLiteTable)))
+}
+
+func TestSQLiteEmpty(t *testing.T) {
+	t.Parallel()
+	const pageSize = 4096
+	var buf buffer
+	w := export.NewSQLite(&buf, export.Options{})
+	be.Err(t, w.Close(), nil)
+	be.Equal(t, len(buf.b), 2*pageSize)
+	be.Equal(t, binary.BigEndian.Uint32(buf.b[28:]), uint32(2))
+}
+
+func TestCatalogue(t *testing.T) {
+	t.Parallel()
+	var buf bytes.Buffer
+	n, err := export.Catalogue(t.Context(), nil, export.NewJSONL(&buf), export.Options{})
+	be.Err(t, err)
+	be.Equal(t, n, 0)
+}
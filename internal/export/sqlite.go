@@ -0,0 +1,388 @@
+package export
+
+// Package file sqlite.go contains a minimal writer of the SQLite 3 database file format,
+// that saves the catalogue to a single artifacts table without the need of a SQLite driver or cgo.
+// The table b-tree is built bottom up while the records are streamed, so only the page numbers
+// of the leaf pages are kept in memory.
+//
+// The file format is documented at https://www.sqlite.org/fileformat2.html
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	// SQLiteTable is the name of the table in the SQLite database.
+	SQLiteTable = "artifacts"
+
+	pageSize     = 4096
+	leafHeader   = 8  // leafHeader is the size of a b-tree leaf page header.
+	branchHeader = 12 // branchHeader is the size of a b-tree interior page header.
+	fileHeader   = 100
+	leafTable    = 0x0d // leafTable is the page type of a table b-tree leaf page.
+	branchTable  = 0x05 // branchTable is the page type of a table b-tree interior page.
+	maxLocal     = pageSize - 35
+	minLocal     = (pageSize-12)*32/255 - 23
+	overflowData = pageSize - 4
+	maxBranches  = (pageSize - branchHeader) / (4 + 9 + 2) // cell of a page number, a rowid and a pointer
+	sqliteVers   = 3045000                                 // version number of the SQLite library of the file format
+)
+
+// NewSQLite returns a writer that saves the records to a new SQLite 3 database file.
+// The database has a single artifacts table, with the id as the integer primary key,
+// and the columns of the export where the empty values are NULL.
+//
+// The writer uses random access, so w would usually be an empty, new [os.File].
+// The records must be written in ascending id order.
+func NewSQLite(w io.WriterAt, opt Options) Writer {
+	return &sqliteWriter{w: w, opt: opt, next: 2}
+}
+
+type sqliteWriter struct {
+	w       io.WriterAt
+	opt     Options
+	next    uint32   // next is the number of the next unused page, page 1 is saved on close.
+	cells   [][]byte // cells of the leaf page that is being filled.
+	used    int      // used is the number of bytes of the cells.
+	last    int64    // last is the rowid of the last record.
+	written bool     // written is true when a record has been written.
+	leaves  []branch // leaves are the saved leaf pages.
+	closed  bool
+}
+
+// branch is a child page of the table b-tree and the largest rowid that it contains.
+type branch struct {
+	page uint32
+	key  int64
+}
+
+func (s *sqliteWriter) Write(r *Record) error {
+	if s.closed {
+		return ErrWriter
+	}
+	if r == nil {
+		return nil
+	}
+	if s.written && r.ID <= s.last {
+		return fmt.Errorf("sqlite write %d: %w", r.ID, ErrOrder)
+	}
+	fields := r.Fields(s.opt)
+	values := make([]any, len(fields))
+	for i, f := range fields {
+		values[i] = f.Value
+	}
+	// the id is the integer primary key, which is an alias of the rowid and is stored as null
+	values[0] = nil
+	if err := s.add(r.ID, record(values)); err != nil {
+		return fmt.Errorf("sqlite write %d: %w", r.ID, err)
+	}
+	s.last = r.ID
+	s.written = true
+	return nil
+}
+
+func (s *sqliteWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if len(s.cells) > 0 || len(s.leaves) == 0 {
+		if err := s.flush(); err != nil {
+			return err
+		}
+	}
+	root, err := s.tree(s.leaves)
+	if err != nil {
+		return err
+	}
+	cols := Record{}.Fields(s.opt)
+	defs := make([]string, len(cols))
+	for i, col := range cols {
+		switch {
+		case i == 0:
+			defs[i] = col.Name + " INTEGER PRIMARY KEY"
+		case col.Kind == Integer:
+			defs[i] = col.Name + " INTEGER"
+		default:
+			defs[i] = col.Name + " TEXT"
+		}
+	}
+	sql := "CREATE TABLE " + SQLiteTable + " (" + strings.Join(defs, ", ") + ")"
+	schema := record([]any{"table", SQLiteTable, SQLiteTable, int64(root), sql})
+	page := make([]byte, pageSize)
+	s.header(page)
+	cell := cell(1, schema, 0)
+	if fileHeader+leafHeader+2+len(cell) > pageSize {
+		return fmt.Errorf("sqlite schema is too large: %d bytes", len(cell))
+	}
+	leaf(page[fileHeader:], pageSize-fileHeader, [][]byte{cell})
+	if _, err := s.w.WriteAt(page, 0); err != nil {
+		return fmt.Errorf("sqlite write page 1: %w", err)
+	}
+	return nil
+}
+
+// header writes the database file header to the first page.
+func (s *sqliteWriter) header(page []byte) {
+	copy(page, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(page[16:], pageSize)
+	// legacy file format write and read versions
+	page[18], page[19] = 1, 1
+	// maximum and minimum embedded payload fractions, and the leaf payload fraction
+	page[21], page[22], page[23] = 64, 32, 32
+	binary.BigEndian.PutUint32(page[24:], 1)        // file change counter
+	binary.BigEndian.PutUint32(page[28:], s.next-1) // size of the database file in pages
+	binary.BigEndian.PutUint32(page[40:], 1)        // schema cookie
+	binary.BigEndian.PutUint32(page[44:], 4)        // schema format number
+	binary.BigEndian.PutUint32(page[56:], 1)        // text encoding, UTF-8
+	binary.BigEndian.PutUint32(page[92:], 1)        // version valid for the change counter
+	binary.BigEndian.PutUint32(page[96:], sqliteVers)
+}
+
+// alloc returns the number of an unused page.
+func (s *sqliteWriter) alloc() uint32 {
+	n := s.next
+	s.next++
+	return n
+}
+
+// writePage saves the page to the page number of the database file.
+func (s *sqliteWriter) writePage(n uint32, page []byte) error {
+	if _, err := s.w.WriteAt(page, int64(n-1)*pageSize); err != nil {
+		return fmt.Errorf("sqlite write page %d: %w", n, err)
+	}
+	return nil
+}
+
+// add the record payload to the leaf page, the payload that does not fit is saved to overflow pages.
+func (s *sqliteWriter) add(rowid int64, payload []byte) error {
+	local := localSize(len(payload))
+	var first uint32
+	if local < len(payload) {
+		var err error
+		if first, err = s.overflow(payload[local:]); err != nil {
+			return err
+		}
+	}
+	c := cell(rowid, payload[:local], len(payload))
+	if first > 0 {
+		c = binary.BigEndian.AppendUint32(c, first)
+	}
+	const pointer = 2
+	if leafHeader+pointer*(len(s.cells)+1)+s.used+len(c) > pageSize {
+		if err := s.flush(); err != nil {
+			return err
+		}
+	}
+	s.cells = append(s.cells, c)
+	s.used += len(c)
+	return nil
+}
+
+// overflow saves the data to a linked list of overflow pages and returns the number of the first page.
+func (s *sqliteWriter) overflow(data []byte) (uint32, error) {
+	first := s.alloc()
+	n := first
+	for len(data) > 0 {
+		size := min(len(data), overflowData)
+		var next uint32
+		if size < len(data) {
+			next = s.alloc()
+		}
+		page := make([]byte, pageSize)
+		binary.BigEndian.PutUint32(page, next)
+		copy(page[4:], data[:size])
+		if err := s.writePage(n, page); err != nil {
+			return 0, err
+		}
+		data = data[size:]
+		n = next
+	}
+	return first, nil
+}
+
+// flush saves the cells to a new leaf page.
+func (s *sqliteWriter) flush() error {
+	n := s.alloc()
+	page := make([]byte, pageSize)
+	leaf(page, pageSize, s.cells)
+	if err := s.writePage(n, page); err != nil {
+		return err
+	}
+	s.leaves = append(s.leaves, branch{page: n, key: s.last})
+	s.cells = s.cells[:0]
+	s.used = 0
+	return nil
+}
+
+// tree saves the interior pages of the table b-tree above the child pages and returns the root page number.
+func (s *sqliteWriter) tree(children []branch) (uint32, error) {
+	for len(children) > 1 {
+		groups := make([][]branch, 0, len(children)/maxBranches+1)
+		for start := 0; start < len(children); start += maxBranches {
+			groups = append(groups, children[start:min(start+maxBranches, len(children))])
+		}
+		// an interior page requires at least one cell plus the right-most child
+		if n := len(groups); n > 1 && len(groups[n-1]) == 1 {
+			prev := groups[n-2]
+			groups[n-2] = prev[:len(prev)-1]
+			groups[n-1] = append([]branch{prev[len(prev)-1]}, groups[n-1]...)
+		}
+		parents := make([]branch, 0, len(groups))
+		for _, group := range groups {
+			n := s.alloc()
+			page := make([]byte, pageSize)
+			interior(page, group)
+			if err := s.writePage(n, page); err != nil {
+				return 0, err
+			}
+			parents = append(parents, branch{page: n, key: group[len(group)-1].key})
+		}
+		children = parents
+	}
+	return children[0].page, nil
+}
+
+// leaf writes the cells to a table b-tree leaf page of the size.
+// The cell content is placed at the end of the page and the cell pointers follow the page header.
+func leaf(page []byte, size int, cells [][]byte) {
+	page[0] = leafTable
+	binary.BigEndian.PutUint16(page[3:], uint16(len(cells)))
+	offset := size
+	for i, c := range cells {
+		offset -= len(c)
+		copy(page[offset:], c)
+		binary.BigEndian.PutUint16(page[leafHeader+2*i:], uint16(pageSize-size+offset))
+	}
+	binary.BigEndian.PutUint16(page[5:], uint16(pageSize-size+offset))
+}
+
+// interior writes the child pages to a table b-tree interior page.
+// The last child is the right-most pointer and the other children are cells of the page number and the largest rowid.
+func interior(page []byte, children []branch) {
+	page[0] = branchTable
+	cells := children[:len(children)-1]
+	binary.BigEndian.PutUint16(page[3:], uint16(len(cells)))
+	binary.BigEndian.PutUint32(page[8:], children[len(children)-1].page)
+	offset := pageSize
+	for i, child := range cells {
+		c := binary.BigEndian.AppendUint32(nil, child.page)
+		c = appendVarint(c, uint64(child.key))
+		offset -= len(c)
+		copy(page[offset:], c)
+		binary.BigEndian.PutUint16(page[branchHeader+2*i:], uint16(offset))
+	}
+	binary.BigEndian.PutUint16(page[5:], uint16(offset))
+}
+
+// cell returns a table b-tree leaf cell of the rowid and the local payload.
+// The size is the total size of the payload, including any bytes saved to overflow pages.
+func cell(rowid int64, local []byte, size int) []byte {
+	size = max(size, len(local))
+	c := appendVarint(nil, uint64(size))
+	c = appendVarint(c, uint64(rowid))
+	return append(c, local...)
+}
+
+// localSize returns the number of payload bytes that are stored on a table b-tree leaf page,
+// while the remainder of the payload is stored in overflow pages.
+func localSize(payload int) int {
+	if payload <= maxLocal {
+		return payload
+	}
+	k := minLocal + (payload-minLocal)%overflowData
+	if k <= maxLocal {
+		return k
+	}
+	return minLocal
+}
+
+// record returns the SQLite record format of the values, which are either int64, string or nil.
+func record(values []any) []byte {
+	types := make([]byte, 0, len(values))
+	body := make([]byte, 0, len(values)*8)
+	for _, v := range values {
+		switch val := v.(type) {
+		case int64:
+			t, b := integer(val)
+			types = appendVarint(types, t)
+			body = append(body, b...)
+		case string:
+			types = appendVarint(types, uint64(len(val))*2+13)
+			body = append(body, val...)
+		default:
+			types = appendVarint(types, 0)
+		}
+	}
+	// the header size includes the varint of the header size
+	size := len(types) + 1
+	for varintLen(uint64(size)) != size-len(types) {
+		size = len(types) + varintLen(uint64(size))
+	}
+	out := appendVarint(make([]byte, 0, size+len(body)), uint64(size))
+	out = append(out, types...)
+	return append(out, body...)
+}
+
+// integer returns the serial type and the big-endian bytes of the smallest integer storage of the value.
+func integer(v int64) (uint64, []byte) {
+	const zero, one = 8, 9
+	switch {
+	case v == 0:
+		return zero, nil
+	case v == 1:
+		return one, nil
+	}
+	sizes := []struct {
+		serial uint64
+		bytes  int
+	}{{1, 1}, {2, 2}, {3, 3}, {4, 4}, {5, 6}, {6, 8}}
+	for _, s := range sizes {
+		bits := uint(s.bytes*8 - 1)
+		if s.bytes < 8 && (v < -(1<<bits) || v >= 1<<bits) {
+			continue
+		}
+		buf := binary.BigEndian.AppendUint64(nil, uint64(v))
+		return s.serial, buf[8-s.bytes:]
+	}
+	return 0, nil
+}
+
+// appendVarint appends the SQLite variable-length integer encoding of the value,
+// which is big-endian using 7 bits per byte, where the optional ninth byte uses all 8 bits.
+func appendVarint(b []byte, v uint64) []byte {
+	const nine = 0x00ffffffffffffff
+	if v > nine {
+		var buf [9]byte
+		buf[8] = byte(v)
+		v >>= 8
+		for i := 7; i >= 0; i-- {
+			buf[i] = byte(v&0x7f) | 0x80
+			v >>= 7
+		}
+		return append(b, buf[:]...)
+	}
+	var buf [8]byte
+	n := 0
+	for {
+		buf[n] = byte(v&0x7f) | 0x80
+		n++
+		v >>= 7
+		if v == 0 {
+			break
+		}
+	}
+	buf[0] &= 0x7f
+	for i := n - 1; i >= 0; i-- {
+		b = append(b, buf[i])
+	}
+	return b
+}
+
+// varintLen returns the number of bytes of the variable-length integer encoding of the value.
+func varintLen(v uint64) int {
+	return len(appendVarint(nil, v))
+}
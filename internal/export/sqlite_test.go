@@ -0,0 +1,248 @@
+package export_test
+
+import (
+	"encoding/binary"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/Defacto2/server/internal/export"
+	"github.com/nalgeon/be"
+)
+
+// sqliteReader is a minimal reader of the SQLite 3 database file format that is independent
+// of the writer, to confirm the table b-tree, the overflow pages and the records can be read back.
+type sqliteReader struct {
+	b    []byte
+	size int
+}
+
+// row is a record of a table b-tree with its rowid.
+type row struct {
+	id     int64
+	values []any
+}
+
+func newReader(t *testing.T, b []byte) sqliteReader {
+	t.Helper()
+	be.True(t, len(b) >= 100)
+	size := int(binary.BigEndian.Uint16(b[16:]))
+	be.True(t, size >= 512)
+	be.Equal(t, len(b)%size, 0)
+	return sqliteReader{b: b, size: size}
+}
+
+func (r sqliteReader) page(n uint32) []byte {
+	return r.b[int(n-1)*r.size : int(n)*r.size]
+}
+
+// rows returns the records of the table b-tree with the root page, in the order of the tree.
+func (r sqliteReader) rows(t *testing.T, root uint32) []row {
+	t.Helper()
+	p := r.page(root)
+	off := 0
+	if root == 1 {
+		off = 100
+	}
+	cells := int(binary.BigEndian.Uint16(p[off+3:]))
+	var rows []row
+	switch p[off] {
+	case 0x05:
+		for i := range cells {
+			ptr := binary.BigEndian.Uint16(p[off+12+2*i:])
+			child := binary.BigEndian.Uint32(p[ptr:])
+			key, _ := varint(p[ptr+4:])
+			kids := r.rows(t, child)
+			be.True(t, len(kids) > 0)
+			be.True(t, kids[len(kids)-1].id <= int64(key))
+			rows = append(rows, kids...)
+		}
+		rows = append(rows, r.rows(t, binary.BigEndian.Uint32(p[off+8:]))...)
+	case 0x0d:
+		for i := range cells {
+			ptr := int(binary.BigEndian.Uint16(p[off+8+2*i:]))
+			size, n := varint(p[ptr:])
+			ptr += n
+			id, n := varint(p[ptr:])
+			ptr += n
+			local := r.local(int(size))
+			payload := slices.Clone(p[ptr : ptr+local])
+			if local < int(size) {
+				next := binary.BigEndian.Uint32(p[ptr+local:])
+				for next != 0 {
+					o := r.page(next)
+					need := min(int(size)-len(payload), r.size-4)
+					payload = append(payload, o[4:4+need]...)
+					next = binary.BigEndian.Uint32(o)
+				}
+			}
+			be.Equal(t, len(payload), int(size))
+			rows = append(rows, row{id: int64(id), values: values(payload)})
+		}
+	default:
+		t.Fatalf("page %d has an unknown type %#x", root, p[off])
+	}
+	return rows
+}
+
+// local returns the number of payload bytes that are stored on a table b-tree leaf page.
+func (r sqliteReader) local(payload int) int {
+	u := r.size
+	x := u - 35
+	if payload <= x {
+		return payload
+	}
+	m := (u-12)*32/255 - 23
+	k := m + (payload-m)%(u-4)
+	if k <= x {
+		return k
+	}
+	return m
+}
+
+// varint returns the SQLite variable-length integer and the number of bytes read.
+func varint(b []byte) (uint64, int) {
+	var v uint64
+	for i := range 8 {
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v<<8 | uint64(b[8]), 9
+}
+
+// values returns the values of the record, which are either int64, string or nil.
+func values(rec []byte) []any {
+	size, n := varint(rec)
+	var types []uint64
+	for pos := n; pos < int(size); {
+		typ, n := varint(rec[pos:])
+		types = append(types, typ)
+		pos += n
+	}
+	body := rec[size:]
+	vals := make([]any, 0, len(types))
+	for _, typ := range types {
+		switch {
+		case typ == 0:
+			vals = append(vals, nil)
+		case typ == 8:
+			vals = append(vals, int64(0))
+		case typ == 9:
+			vals = append(vals, int64(1))
+		case typ >= 1 && typ <= 6:
+			width := []int{0, 1, 2, 3, 4, 6, 8}[typ]
+			var v int64
+			for i := range width {
+				v = v<<8 | int64(body[i])
+			}
+			if shift := uint(64 - 8*width); width < 8 {
+				v = v << shift >> shift
+			}
+			vals = append(vals, v)
+			body = body[width:]
+		case typ >= 13 && typ%2 == 1:
+			l := int(typ-13) / 2
+			vals = append(vals, string(body[:l]))
+			body = body[l:]
+		default:
+			l := int(typ-12) / 2
+			vals = append(vals, body[:l])
+			body = body[l:]
+		}
+	}
+	return vals
+}
+
+func TestSQLiteRead(t *testing.T) {
+	t.Parallel()
+	const count = 1200
+	opt := export.Options{}
+	var buf buffer
+	w := export.NewSQLite(&buf, opt)
+	r := export.NewRecord(file(), opt)
+	for i := range count {
+		r.ID = int64(i*3 + 1)
+		r.Comment = strings.Repeat("x", i*10)
+		be.Err(t, w.Write(&r), nil)
+	}
+	be.Err(t, w.Close(), nil)
+
+	db := newReader(t, buf.b)
+	be.Equal(t, int(binary.BigEndian.Uint32(buf.b[28:])), len(buf.b)/db.size)
+	schema := db.rows(t, 1)
+	be.Equal(t, len(schema), 1)
+	be.Equal(t, len(schema[0].values), 5)
+	be.Equal(t, schema[0].values[0], any("table"))
+	be.Equal(t, schema[0].values[1], any(export.SQLiteTable))
+	sql, _ := schema[0].values[4].(string)
+	be.True(t, strings.HasPrefix(sql, "CREATE TABLE "+export.SQLiteTable+" (id INTEGER PRIMARY KEY"))
+	root, _ := schema[0].values[3].(int64)
+	be.True(t, root > 1)
+
+	cols := export.Columns(opt)
+	comment := slices.Index(cols, "comment")
+	be.True(t, comment > 0)
+	fields := r.Fields(opt)
+	rows := db.rows(t, uint32(root))
+	be.Equal(t, len(rows), count)
+	for i, row := range rows {
+		be.Equal(t, row.id, int64(i*3+1))
+		be.Equal(t, len(row.values), len(cols))
+		be.Equal(t, row.values[0], nil)
+		if i == 0 {
+			be.Equal(t, row.values[comment], nil)
+		} else {
+			be.Equal(t, row.values[comment], any(strings.Repeat("x", i*10)))
+		}
+		for j, f := range fields {
+			if j == 0 || j == comment {
+				continue
+			}
+			be.Equal(t, row.values[j], f.Value)
+		}
+	}
+}
+
+// TestSQLiteIntegrity opens the database with the sqlite3 command, so the file is confirmed
+// by the SQLite library and not only by the reader of this test. It is skipped without sqlite3.
+func TestSQLiteIntegrity(t *testing.T) {
+	t.Parallel()
+	sqlite3, err := exec.LookPath("sqlite3")
+	if err != nil {
+		t.Skip("sqlite3 command is not installed")
+	}
+	const count = 20000
+	opt := export.Options{}
+	name := filepath.Join(t.TempDir(), "export.sqlite")
+	f, err := os.Create(name)
+	be.Err(t, err, nil)
+	w := export.NewSQLite(f, opt)
+	r := export.NewRecord(file(), opt)
+	sum := 0
+	for i := range count {
+		r.ID = int64(i*3 + 1)
+		// the comments of a few thousand bytes use overflow pages, including those of more than a page
+		r.Comment = strings.Repeat("x", (i*7)%9000)
+		sum += len(r.Comment)
+		be.Err(t, w.Write(&r), nil)
+	}
+	be.Err(t, w.Close(), nil)
+	be.Err(t, f.Close(), nil)
+
+	query := func(sql string) string {
+		t.Helper()
+		out, err := exec.CommandContext(t.Context(), sqlite3, "-readonly", name, sql).CombinedOutput()
+		be.Err(t, err, nil)
+		return strings.TrimSpace(string(out))
+	}
+	be.Equal(t, query("PRAGMA integrity_check;"), "ok")
+	want := strconv.Itoa(count) + "|" + strconv.Itoa((count-1)*3+1) + "|" + strconv.Itoa(sum)
+	be.Equal(t, query("SELECT count(*), max(id), sum(length(comment)) FROM "+export.SQLiteTable+";"), want)
+	be.Equal(t, query("SELECT filename FROM "+export.SQLiteTable+" WHERE id = 4;"), "first.zip")
+}
@@ -0,0 +1,116 @@
+package export
+
+// Package file text.go contains the JSON Lines and CSV writers of the catalogue.
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// NewJSONL returns a writer that writes each record as a JSON object on a single line.
+func NewJSONL(w io.Writer) Writer {
+	buf := bufio.NewWriter(w)
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	return &jsonlWriter{buf: buf, enc: enc}
+}
+
+type jsonlWriter struct {
+	buf    *bufio.Writer
+	enc    *json.Encoder
+	closed bool
+}
+
+func (j *jsonlWriter) Write(r *Record) error {
+	if j.closed {
+		return ErrWriter
+	}
+	if r == nil {
+		return nil
+	}
+	if err := j.enc.Encode(r); err != nil {
+		return fmt.Errorf("jsonl write %d: %w", r.ID, err)
+	}
+	return nil
+}
+
+func (j *jsonlWriter) Close() error {
+	if j.closed {
+		return nil
+	}
+	j.closed = true
+	if err := j.buf.Flush(); err != nil {
+		return fmt.Errorf("jsonl flush: %w", err)
+	}
+	return nil
+}
+
+// NewCSV returns a writer that writes the records as comma-separated values, with a header row of the
+// [Columns]. The releasers are separated by a + (plus) and the relations and links are written as
+// pipe separated, name and URL pairs, "name;url|name;url".
+func NewCSV(w io.Writer, opt Options) Writer {
+	return &csvWriter{w: csv.NewWriter(w), opt: opt}
+}
+
+type csvWriter struct {
+	w      *csv.Writer
+	opt    Options
+	header bool
+	closed bool
+}
+
+func (c *csvWriter) Write(r *Record) error {
+	if c.closed {
+		return ErrWriter
+	}
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	if r == nil {
+		return nil
+	}
+	fields := r.Fields(c.opt)
+	row := make([]string, len(fields))
+	for i, f := range fields {
+		switch val := f.Value.(type) {
+		case int64:
+			row[i] = strconv.FormatInt(val, 10)
+		case string:
+			row[i] = val
+		}
+	}
+	if err := c.w.Write(row); err != nil {
+		return fmt.Errorf("csv write %d: %w", r.ID, err)
+	}
+	return nil
+}
+
+func (c *csvWriter) writeHeader() error {
+	if c.header {
+		return nil
+	}
+	c.header = true
+	if err := c.w.Write(Columns(c.opt)); err != nil {
+		return fmt.Errorf("csv header: %w", err)
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	if c.closed {
+		return nil
+	}
+	if err := c.writeHeader(); err != nil {
+		return err
+	}
+	c.closed = true
+	c.w.Flush()
+	if err := c.w.Error(); err != nil {
+		return fmt.Errorf("csv flush: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+// Package hits counts the downloads, emulations and views of the artifacts.
+// The counts are aggregates held in memory that are periodically flushed to the database
+// as daily totals, so no IP addresses or other details of the visitors are ever stored.
+package hits
+
+import (
+	"context"
+	"maps"
+	"sync"
+	"time"
+)
+
+// Kind of hit.
+type Kind string
+
+const (
+	Download Kind = "download" // Download is an artifact download.
+	Emulate  Kind = "emulate"  // Emulate is an artifact run in the browser emulator or fetched as a js-dos bundle.
+	View     Kind = "view"     // View is an artifact page view.
+)
+
+// Kinds returns all the kinds of hits.
+func Kinds() []Kind {
+	return []Kind{Download, Emulate, View}
+}
+
+// Valid returns true if the kind of hit is known.
+func (k Kind) Valid() bool {
+	switch k {
+	case Download, Emulate, View:
+		return true
+	}
+	return false
+}
+
+// Key is the artifact id and the kind of hit.
+type Key struct {
+	ID   int64 // ID is the artifact id.
+	Kind Kind  // Kind of hit.
+}
+
+// Counter holds the hits that are yet to be flushed.
+// It is safe for concurrent use.
+type Counter struct {
+	mu     sync.Mutex
+	counts map[Key]int64
+}
+
+// Flush saves the counts of the hits and should return an error if the save fails.
+type Flush func(ctx context.Context, day time.Time, counts map[Key]int64) error
+
+// New returns an empty counter.
+func New() *Counter {
+	return &Counter{counts: make(map[Key]int64)}
+}
+
+// Default is the counter used by the package functions.
+var Default = New() //nolint:gochecknoglobals
+
+// Add a hit to the default counter.
+func Add(id int64, kind Kind) {
+	Default.Add(id, kind)
+}
+
+// Add a hit of the kind to the artifact id.
+// Invalid ids and unknown kinds are ignored.
+func (c *Counter) Add(id int64, kind Kind) {
+	if c == nil || id < 1 || !kind.Valid() {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[Key]int64)
+	}
+	c.counts[Key{ID: id, Kind: kind}]++
+}
+
+// Len returns the number of the artifact and kind pairs that are yet to be flushed.
+func (c *Counter) Len() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.counts)
+}
+
+// Drain returns the counts of the hits and resets the counter.
+func (c *Counter) Drain() map[Key]int64 {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := c.counts
+	c.counts = make(map[Key]int64)
+	return counts
+}
+
+// Merge adds the counts back to the counter, which is used to keep the hits of a failed flush.
+func (c *Counter) Merge(counts map[Key]int64) {
+	if c == nil || len(counts) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.counts == nil {
+		c.counts = make(map[Key]int64, len(counts))
+	}
+	for key, n := range counts {
+		c.counts[key] += n
+	}
+}
+
+// Flush drains the counter and saves the hits using the fn function for the current UTC day.
+// On failure the hits are returned to the counter, to be saved with the next flush.
+func (c *Counter) Flush(ctx context.Context, fn Flush) error {
+	if c == nil || fn == nil {
+		return nil
+	}
+	counts := c.Drain()
+	if len(counts) == 0 {
+		return nil
+	}
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+	if err := fn(ctx, day, maps.Clone(counts)); err != nil {
+		c.Merge(counts)
+		return err
+	}
+	return nil
+}
+
+// Run flushes the counter using the fn function at every interval, until the context is done.
+// A final flush is made using a background context when the context is done.
+// Any flush errors are passed to the report function, which can be nil.
+func (c *Counter) Run(ctx context.Context, interval time.Duration, fn Flush, report func(error)) {
+	if c == nil || fn == nil || interval <= 0 {
+		return
+	}
+	flush := func(ctx context.Context) {
+		if err := c.Flush(ctx, fn); err != nil && report != nil {
+			report(err)
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			const final = 10 * time.Second
+			fctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), final)
+			flush(fctx)
+			cancel()
+			return
+		case <-ticker.C:
+			flush(ctx)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package hits_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/internal/hits"
+	"github.com/nalgeon/be"
+)
+
+func TestKind(t *testing.T) {
+	t.Parallel()
+	for _, k := range hits.Kinds() {
+		be.True(t, k.Valid())
+	}
+	be.True(t, !hits.Kind("").Valid())
+	be.True(t, !hits.Kind("visit").Valid())
+}
+
+func TestAdd(t *testing.T) {
+	t.Parallel()
+	c := hits.New()
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() { c.Add(1, hits.Download) })
+	}
+	wg.Wait()
+	c.Add(1, hits.View)
+	c.Add(0, hits.View)
+	c.Add(2, "unknown")
+	be.Equal(t, c.Len(), 2)
+	counts := c.Drain()
+	be.Equal(t, counts[hits.Key{ID: 1, Kind: hits.Download}], int64(100))
+	be.Equal(t, counts[hits.Key{ID: 1, Kind: hits.View}], int64(1))
+	be.Equal(t, c.Len(), 0)
+
+	var nilCounter *hits.Counter
+	nilCounter.Add(1, hits.View)
+	be.Equal(t, nilCounter.Len(), 0)
+}
+
+func TestFlush(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	c := hits.New()
+	c.Add(1, hits.Emulate)
+	errFlush := errors.New("flush failed")
+	err := c.Flush(ctx, func(context.Context, time.Time, map[hits.Key]int64) error {
+		return errFlush
+	})
+	be.Err(t, err, errFlush)
+	be.Equal(t, c.Len(), 1)
+	c.Add(1, hits.Emulate)
+	var saved map[hits.Key]int64
+	var day time.Time
+	err = c.Flush(ctx, func(_ context.Context, d time.Time, counts map[hits.Key]int64) error {
+		saved, day = counts, d
+		return nil
+	})
+	be.Err(t, err, nil)
+	be.Equal(t, saved[hits.Key{ID: 1, Kind: hits.Emulate}], int64(2))
+	be.Equal(t, day.Hour(), 0)
+	be.Equal(t, c.Len(), 0)
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	c := hits.New()
+	c.Add(1, hits.View)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	n := int64(0)
+	c.Run(ctx, time.Hour, func(_ context.Context, _ time.Time, counts map[hits.Key]int64) error {
+		for _, v := range counts {
+			n += v
+		}
+		return nil
+	}, nil)
+	be.Equal(t, n, int64(1))
+}
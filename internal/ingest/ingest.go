@@ -0,0 +1,300 @@
+package ingest
+
+// Package file ingest.go contains the import of the manifest entries into the database and download directory.
+
+import (
+	"context"
+	"crypto/sha512"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Defacto2/archive"
+	"github.com/Defacto2/helper"
+	"github.com/Defacto2/magicnumber"
+	"github.com/Defacto2/server/internal/command"
+	"github.com/Defacto2/server/internal/logs"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/tags"
+	"github.com/Defacto2/server/model"
+	"github.com/Defacto2/server/model/fix"
+	"github.com/google/uuid"
+)
+
+var ErrDir = errors.New("import directory is not a directory")
+
+// Key is the form value prefix of the imported uploads.
+const Key = "import"
+
+// Import is a batch import of the files in a directory.
+type Import struct {
+	Dir      string       // Dir is the directory that contains the files of the manifest.
+	Dirs     command.Dirs // Dirs are the download, preview and thumbnail directories of the server.
+	Progress string       // Progress is the optional named file that records the completed entries.
+	DryRun   bool         // DryRun reports the files that would be imported without making any changes.
+}
+
+// Report is the summary of an import.
+type Report struct {
+	Imported   int // Imported is the number of new file records waiting for approval.
+	Duplicates int // Duplicates is the number of files skipped as they already exist.
+	Resumed    int // Resumed is the number of entries completed by a previous import.
+	Failed     int // Failed is the number of entries that could not be imported.
+	DryRun     int // DryRun is the number of files that would be imported.
+}
+
+func (r Report) String() string {
+	return fmt.Sprintf("%d imported, %d duplicates, %d resumed, %d failed, %d dry-run",
+		r.Imported, r.Duplicates, r.Resumed, r.Failed, r.DryRun)
+}
+
+// Run imports the files of the manifest as new uploads that are waiting for approval.
+// Each file is hashed and skipped when the SHA-384 checksum already exists in the database
+// or earlier in the manifest. Otherwise, the file is saved as a new record, copied to the
+// download directory using the UUID of the record as the filename, and when the file is an
+// image or text, the preview and thumbnail images are created. A record of a file that cannot
+// be copied is deleted, so the file is not treated as a duplicate when the import is run again.
+//
+// Entries that fail are logged and counted, but do not stop the import. Completed entries
+// are appended to the progress file, so an interrupted import can be run again and resume.
+// A database error stops the import.
+func (im Import) Run(ctx context.Context, sl *slog.Logger, db *sql.DB, m Manifest) (Report, error) {
+	const msg = "import run"
+	var report Report
+	if sl == nil {
+		sl = logs.Discard()
+	}
+	if err := nils.Check(ctx, db); err != nil {
+		return report, fmt.Errorf("%s: %w", msg, err)
+	}
+	if st, err := os.Stat(im.Dir); err != nil {
+		return report, fmt.Errorf("%s: %w", msg, err)
+	} else if !st.IsDir() {
+		return report, fmt.Errorf("%s: %w: %s", msg, ErrDir, im.Dir)
+	}
+	if !im.DryRun {
+		if err := im.Dirs.Download.Check(sl); err != nil {
+			return report, fmt.Errorf("%s download: %w", msg, err)
+		}
+	}
+	done, err := ReadProgress(im.Progress)
+	if err != nil {
+		return report, fmt.Errorf("%s: %w", msg, err)
+	}
+	progress := &progressFile{}
+	if !im.DryRun {
+		if progress, err = openProgress(im.Progress); err != nil {
+			return report, fmt.Errorf("%s: %w", msg, err)
+		}
+	}
+	defer func() { _ = progress.close() }()
+	seen := make(map[string]string, len(m))
+	for _, e := range m {
+		name := strings.TrimSpace(e.Filename)
+		if _, ok := done[name]; ok {
+			report.Resumed++
+			continue
+		}
+		if err := e.Validate(); err != nil {
+			sl.Warn(msg, slog.String("skip", "invalid manifest entry"), slog.Any("error", err))
+			report.Failed++
+			continue
+		}
+		path := filepath.Join(im.Dir, name)
+		sum, err := Checksum(path)
+		if err != nil {
+			sl.Warn(msg, slog.String("skip", "could not hash the file"),
+				slog.String("filename", name), slog.Any("error", err))
+			report.Failed++
+			continue
+		}
+		hexsum := hex.EncodeToString(sum)
+		pr := Progress{Filename: name, SHA384: hexsum, Status: Duplicate}
+		if first, ok := seen[hexsum]; ok {
+			sl.Info(msg, slog.String("duplicate", "file is a copy of another manifest entry"),
+				slog.String("filename", name), slog.String("copy of", first))
+			report.Duplicates++
+			if err := im.save(progress, pr); err != nil {
+				return report, fmt.Errorf("%s: %w", msg, err)
+			}
+			continue
+		}
+		seen[hexsum] = name
+		exist, err := model.SHA384Exists(ctx, db, sum)
+		if err != nil {
+			return report, fmt.Errorf("%s sha384 exists: %w", msg, err)
+		}
+		if exist {
+			sl.Info(msg, slog.String("duplicate", "file already exists in the database"),
+				slog.String("filename", name))
+			report.Duplicates++
+			if err := im.save(progress, pr); err != nil {
+				return report, fmt.Errorf("%s: %w", msg, err)
+			}
+			continue
+		}
+		if im.DryRun {
+			sl.Info(msg, slog.String("dry-run", "file would be imported"), slog.String("filename", name))
+			report.DryRun++
+			continue
+		}
+		id, uid, err := im.insert(ctx, sl, db, e, path, hexsum)
+		if err != nil {
+			sl.Error(msg, slog.String("problem", "could not insert the file record"),
+				slog.String("filename", name), slog.Any("error", err))
+			report.Failed++
+			continue
+		}
+		if err := duplicate(path, im.Dirs.Download.Join(uid.String())); err != nil {
+			sl.Error(msg, slog.String("problem", "could not copy the file to the download directory"),
+				slog.String("filename", name), slog.Int64("id", id), slog.Any("error", err))
+			report.Failed++
+			// remove the record without a download, otherwise the next run skips the file as a duplicate
+			if err := remove(ctx, db, id); err != nil {
+				return report, fmt.Errorf("%s delete record %d without a download: %w", msg, id, err)
+			}
+			continue
+		}
+		im.previews(ctx, sl, db, e, path, uid.String())
+		pr.Status, pr.ID, pr.UUID = Imported, id, uid.String()
+		if err := im.save(progress, pr); err != nil {
+			return report, fmt.Errorf("%s: %w", msg, err)
+		}
+		sl.Info(msg, slog.String("imported", name), slog.Int64("id", id), slog.String("uuid", uid.String()))
+		report.Imported++
+	}
+	return report, nil
+}
+
+// duplicate copies the named file to the dst path, any partial copy is removed on an error.
+func duplicate(name, dst string) error {
+	if _, err := helper.Duplicate(name, dst); err != nil {
+		_ = os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
+func (im Import) save(p *progressFile, pr Progress) error {
+	if im.DryRun {
+		return nil
+	}
+	pr.Time = time.Now().UTC()
+	return p.save(pr)
+}
+
+// insert the file record using the manifest entry and the details of the named file.
+// The record is saved by [model.InsertUpload] which commits the transaction.
+func (im Import) insert(ctx context.Context, sl *slog.Logger, db *sql.DB, e Entry, path, hexsum string,
+) (int64, uuid.UUID, error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		return 0, uuid.UUID{}, fmt.Errorf("stat: %w", err)
+	}
+	filename := filepath.Base(path)
+	content, err := archive.List(path, filename)
+	if err != nil {
+		sl.Info("import archive list caused an error",
+			slog.String("filename", filename), slog.Any("error", err))
+	}
+	values := e.Values(Key)
+	values.Set(Key+"-integrity", hexsum)
+	values.Set(Key+"-size", strconv.FormatInt(st.Size(), 10))
+	values.Set(Key+"-content", strings.Join(content, "\n"))
+	values.Set(Key+"-readme", archive.Readme(filename, content...))
+	values.Set(Key+"-lastmodified", strconv.FormatInt(st.ModTime().UnixMilli(), 10))
+	insert := func() (int64, uuid.UUID, error) {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return 0, uuid.UUID{}, fmt.Errorf("begin tx: %w", err)
+		}
+		defer func() { _ = tx.Rollback() }()
+		return model.InsertUpload(ctx, tx, values, Key)
+	}
+	id, uid, err := insert()
+	if err != nil {
+		// resync the files table sequence if the insert failed and try again
+		if err := fix.SyncFilesIDSeq(db); err != nil {
+			return 0, uuid.UUID{}, fmt.Errorf("sync files id sequence: %w", err)
+		}
+		return insert()
+	}
+	return id, uid, nil
+}
+
+// remove permanently deletes the file record id in a transaction,
+// so the logged change of the deletion is only kept when the record is deleted.
+func remove(ctx context.Context, db *sql.DB, id int64) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := model.DeleteOne(ctx, tx, id); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("tx commit: %w", err)
+	}
+	return nil
+}
+
+// previews creates the preview and thumbnail images when the named file is an image or text.
+// The imported record does not require the images, so any problems are only logged.
+func (im Import) previews(ctx context.Context, sl *slog.Logger, db *sql.DB, e Entry, path, unid string) {
+	const msg = "import previews"
+	if im.Dirs.Preview == "" || im.Dirs.Thumbnail == "" {
+		return
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		sl.Warn(msg, slog.String("filename", path), slog.Any("error", err))
+		return
+	}
+	sign := magicnumber.Find(f)
+	_ = f.Close()
+	switch {
+	case slices.Contains(magicnumber.Images(), sign):
+		err = im.Dirs.PictureImager(ctx, sl, path, unid)
+	case slices.Contains(magicnumber.Texts(), sign):
+		platform := strings.TrimSpace(e.Platform)
+		amigaFont := strings.EqualFold(platform, tags.TextAmiga.String()) ||
+			strings.EqualFold(platform, tags.Console.String())
+		err = im.Dirs.TextImager(ctx, sl, path, unid, amigaFont)
+	default:
+		return
+	}
+	if err != nil {
+		sl.Warn(msg, slog.String("problem", "could not create the preview images"),
+			slog.String("filename", path), slog.Any("error", err))
+		return
+	}
+	if err := model.RecordAsset(ctx, db, unid, model.Preview, "import "+filepath.Base(path)); err != nil {
+		sl.Warn(msg, slog.String("problem", "could not record the preview asset"), slog.Any("error", err))
+	}
+}
+
+// Checksum returns the SHA-384 checksum of the named file.
+func Checksum(name string) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("checksum: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	hasher := sha512.New384()
+	const size = 4 * 1024
+	buf := make([]byte, size)
+	if _, err := io.CopyBuffer(hasher, f, buf); err != nil {
+		return nil, fmt.Errorf("checksum: %w", err)
+	}
+	return hasher.Sum(nil), nil
+}
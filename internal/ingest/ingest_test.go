@@ -0,0 +1,127 @@
+package ingest_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Defacto2/server/internal/ingest"
+	"github.com/nalgeon/be"
+)
+
+const yamlManifest = `
+- filename: first.zip
+  title: The first release
+  releasers: Razor 1911+Skid Row
+  date: 1990-05
+  platform: dos
+  section: demo
+- filename: docs/second.nfo
+  writers: a writer
+`
+
+func TestEntryValidate(t *testing.T) {
+	t.Parallel()
+	be.Err(t, ingest.Entry{}.Validate(), ingest.ErrFilename)
+	be.Err(t, ingest.Entry{Filename: "../etc/passwd"}.Validate(), ingest.ErrLocal)
+	be.Err(t, ingest.Entry{Filename: "/etc/passwd"}.Validate(), ingest.ErrLocal)
+	be.Err(t, ingest.Entry{Filename: "dir/file.zip"}.Validate(), nil)
+}
+
+func TestEntryValues(t *testing.T) {
+	t.Parallel()
+	e := ingest.Entry{
+		Filename:  "dir/file.zip",
+		Releasers: "Razor 1911 + Skid Row",
+		Date:      "1990-05-01",
+		Platform:  " DOS ",
+	}
+	v := e.Values("x")
+	be.Equal(t, v.Get("x-filename"), "file.zip")
+	be.Equal(t, v.Get("x-releaser1"), "Razor 1911")
+	be.Equal(t, v.Get("x-releaser2"), "Skid Row")
+	be.Equal(t, v.Get("x-year"), "1990")
+	be.Equal(t, v.Get("x-month"), "05")
+	be.Equal(t, v.Get("x-day"), "01")
+	be.Equal(t, v.Get("x-operating-system"), "dos")
+	v = ingest.Entry{Filename: "file.zip", Date: "1990"}.Values("x")
+	be.Equal(t, v.Get("x-year"), "1990")
+	be.Equal(t, v.Get("x-month"), "")
+	be.Equal(t, v.Get("x-releaser2"), "")
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+	m, err := ingest.ParseYAML(strings.NewReader(yamlManifest))
+	be.Err(t, err, nil)
+	be.Equal(t, len(m), 2)
+	be.Equal(t, m[0].Releasers, "Razor 1911+Skid Row")
+	be.Equal(t, m[1].Writers, "a writer")
+
+	m, err = ingest.ParseJSON(strings.NewReader(`[{"filename":"a.zip","title":"A"}]`))
+	be.Err(t, err, nil)
+	be.Equal(t, m, ingest.Manifest{{Filename: "a.zip", Title: "A"}})
+	_, err = ingest.ParseJSON(strings.NewReader(`{`))
+	be.Err(t, err)
+
+	m, err = ingest.ParseCSV(strings.NewReader("Title,filename,unknown\nA,a.zip,x\n,b.zip\n"))
+	be.Err(t, err, nil)
+	be.Equal(t, m, ingest.Manifest{{Filename: "a.zip", Title: "A"}, {Filename: "b.zip"}})
+	_, err = ingest.ParseCSV(strings.NewReader("title\nA\n"))
+	be.Err(t, err, ingest.ErrColumn)
+	m, err = ingest.ParseCSV(strings.NewReader(""))
+	be.Err(t, err, nil)
+	be.Equal(t, len(m), 0)
+}
+
+func TestReadManifest(t *testing.T) {
+	t.Parallel()
+	tmp := t.TempDir()
+	_, err := ingest.ReadManifest(filepath.Join(tmp, "manifest.txt"))
+	be.Err(t, err, ingest.ErrManifest)
+	name := filepath.Join(tmp, "manifest.yml")
+	be.Err(t, os.WriteFile(name, []byte(yamlManifest), 0o600), nil)
+	m, err := ingest.ReadManifest(name)
+	be.Err(t, err, nil)
+	be.Equal(t, len(m), 2)
+}
+
+func TestReadProgress(t *testing.T) {
+	t.Parallel()
+	done, err := ingest.ReadProgress("")
+	be.Err(t, err, nil)
+	be.Equal(t, len(done), 0)
+	name := filepath.Join(t.TempDir(), "manifest.progress")
+	done, err = ingest.ReadProgress(name)
+	be.Err(t, err, nil)
+	be.Equal(t, len(done), 0)
+	const lines = `{"filename":"a.zip","sha384":"ab","status":"imported","id":1}` + "\n" +
+		`{"filename":"b.zip","sha384":"cd","status":"duplicate"}` + "\n" +
+		`{"filename":"c.zip","sha`
+	be.Err(t, os.WriteFile(name, []byte(lines), 0o600), nil)
+	done, err = ingest.ReadProgress(name)
+	be.Err(t, err, nil)
+	be.Equal(t, len(done), 2)
+	be.Equal(t, done["a.zip"].ID, int64(1))
+	be.Equal(t, done["b.zip"].Status, ingest.Duplicate)
+}
+
+func TestChecksum(t *testing.T) {
+	t.Parallel()
+	name := filepath.Join(t.TempDir(), "file.txt")
+	be.Err(t, os.WriteFile(name, []byte("abc"), 0o600), nil)
+	sum, err := ingest.Checksum(name)
+	be.Err(t, err, nil)
+	be.Equal(t, len(sum), 48)
+	_, err = ingest.Checksum(name + ".missing")
+	be.Err(t, err)
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+	im := ingest.Import{Dir: t.TempDir(), DryRun: true}
+	r, err := im.Run(t.Context(), nil, nil, ingest.Manifest{{Filename: "a.zip"}})
+	be.Err(t, err)
+	be.Equal(t, r, ingest.Report{})
+}
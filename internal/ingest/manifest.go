@@ -0,0 +1,172 @@
+// Package ingest imports a batch of artifact files from a directory, using a manifest
+// of the metadata for each file, as new uploads that are waiting for approval.
+package ingest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	ErrColumn   = errors.New("manifest csv requires a filename column")
+	ErrFilename = errors.New("manifest entry requires a filename")
+	ErrLocal    = errors.New("manifest filename must be a local path within the directory")
+	ErrManifest = errors.New("manifest format is not supported, use a .json, .yaml, .yml or .csv file")
+)
+
+// Entry is the metadata of a file in the manifest.
+// The filename is required and is the path of the file relative to the import directory.
+type Entry struct {
+	Filename    string `json:"filename"              yaml:"filename"`
+	Title       string `json:"title,omitempty"       yaml:"title,omitempty"`
+	Releasers   string `json:"releasers,omitempty"   yaml:"releasers,omitempty"`   // Releasers are separated by a + (plus).
+	Date        string `json:"date,omitempty"        yaml:"date,omitempty"`        // Date is the YYYY-MM-DD date of publication.
+	Platform    string `json:"platform,omitempty"    yaml:"platform,omitempty"`    // Platform is the platform tag URI, such as "dos".
+	Section     string `json:"section,omitempty"     yaml:"section,omitempty"`     // Section is the section tag URI, such as "demo".
+	Writers     string `json:"writers,omitempty"     yaml:"writers,omitempty"`     // Writers are the comma separated writer credits.
+	Artists     string `json:"artists,omitempty"     yaml:"artists,omitempty"`     // Artists are the comma separated artist credits.
+	Programmers string `json:"programmers,omitempty" yaml:"programmers,omitempty"` // Programmers are the programmer credits.
+	Musicians   string `json:"musicians,omitempty"   yaml:"musicians,omitempty"`   // Musicians are the composer credits.
+}
+
+// Validate the entry filename, which must be a local path that does not escape the import directory.
+func (e Entry) Validate() error {
+	name := strings.TrimSpace(e.Filename)
+	if name == "" {
+		return ErrFilename
+	}
+	if !filepath.IsLocal(name) {
+		return fmt.Errorf("%w: %q", ErrLocal, name)
+	}
+	return nil
+}
+
+// Values returns the entry as the form values of a file upload, where each name is prefixed with the key.
+// The values are validated and saved by [model.InsertUpload], the same as the uploads of the web forms.
+func (e Entry) Values(key string) url.Values {
+	rels := strings.SplitN(e.Releasers, "+", 2)
+	rels = append(rels, "")
+	ymd := strings.SplitN(strings.TrimSpace(e.Date), "-", 3)
+	ymd = append(ymd, "", "")
+	values := url.Values{}
+	values.Set(key+"-filename", filepath.Base(strings.TrimSpace(e.Filename)))
+	values.Set(key+"-title", e.Title)
+	values.Set(key+"-releaser1", strings.TrimSpace(rels[0]))
+	values.Set(key+"-releaser2", strings.TrimSpace(rels[1]))
+	values.Set(key+"-year", ymd[0])
+	values.Set(key+"-month", ymd[1])
+	values.Set(key+"-day", ymd[2])
+	values.Set(key+"-operating-system", strings.ToLower(strings.TrimSpace(e.Platform)))
+	values.Set(key+"-category", strings.ToLower(strings.TrimSpace(e.Section)))
+	values.Set(key+"-credittext", e.Writers)
+	values.Set(key+"-creditill", e.Artists)
+	values.Set(key+"-creditprog", e.Programmers)
+	values.Set(key+"-creditaudio", e.Musicians)
+	return values
+}
+
+// Manifest is the list of files to import.
+type Manifest []Entry
+
+// ReadManifest opens and parses the named manifest file, the format is determined by the file extension.
+func ReadManifest(name string) (Manifest, error) {
+	var parse func(io.Reader) (Manifest, error)
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		parse = ParseJSON
+	case ".yaml", ".yml":
+		parse = ParseYAML
+	case ".csv":
+		parse = ParseCSV
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrManifest, filepath.Base(name))
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	m, err := parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("read manifest %s: %w", filepath.Base(name), err)
+	}
+	return m, nil
+}
+
+// ParseJSON parses a manifest that is a JSON array of entries.
+func ParseJSON(r io.Reader) (Manifest, error) {
+	var m Manifest
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("parse json: %w", err)
+	}
+	return m, nil
+}
+
+// ParseYAML parses a manifest that is a YAML sequence of entries.
+func ParseYAML(r io.Reader) (Manifest, error) {
+	var m Manifest
+	if err := yaml.NewDecoder(r).Decode(&m); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("parse yaml: %w", err)
+	}
+	return m, nil
+}
+
+// ParseCSV parses a manifest of comma-separated values, where the first row is a header of the column names.
+// The column names are the same as the JSON names of the entry, and unknown columns are ignored.
+func ParseCSV(r io.Reader) (Manifest, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse csv header: %w", err)
+	}
+	cols := make(map[string]int, len(header))
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := cols["filename"]; !ok {
+		return nil, ErrColumn
+	}
+	var m Manifest
+	for {
+		row, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse csv: %w", err)
+		}
+		get := func(name string) string {
+			if i, ok := cols[name]; ok && i < len(row) {
+				return row[i]
+			}
+			return ""
+		}
+		m = append(m, Entry{
+			Filename:    get("filename"),
+			Title:       get("title"),
+			Releasers:   get("releasers"),
+			Date:        get("date"),
+			Platform:    get("platform"),
+			Section:     get("section"),
+			Writers:     get("writers"),
+			Artists:     get("artists"),
+			Programmers: get("programmers"),
+			Musicians:   get("musicians"),
+		})
+	}
+	return m, nil
+}
@@ -0,0 +1,108 @@
+package ingest
+
+// Package file progress.go contains the progress file that allows an interrupted import to be resumed.
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// Status is the outcome of a manifest entry.
+type Status string
+
+const (
+	Imported  Status = "imported"  // Imported is a new file record that is waiting for approval.
+	Duplicate Status = "duplicate" // Duplicate is a file that already exists in the database or the batch.
+	Resumed   Status = "resumed"   // Resumed is an entry that was completed by a previous import.
+	Failed    Status = "failed"    // Failed is an entry that could not be imported.
+	DryRun    Status = "dry-run"   // DryRun is an entry that would be imported.
+)
+
+// Progress is a completed manifest entry that is saved as a line of the progress file.
+type Progress struct {
+	Filename string    `json:"filename"`
+	SHA384   string    `json:"sha384"`
+	Status   Status    `json:"status"`
+	ID       int64     `json:"id,omitempty"`
+	UUID     string    `json:"uuid,omitempty"`
+	Time     time.Time `json:"time"`
+}
+
+// ReadProgress returns the completed entries of the named progress file, mapped by the filename.
+// A progress file that does not exist is not an error and returns an empty map.
+func ReadProgress(name string) (map[string]Progress, error) {
+	done := make(map[string]Progress)
+	if name == "" {
+		return done, nil
+	}
+	f, err := os.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read progress: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var p Progress
+		if err := json.Unmarshal(scanner.Bytes(), &p); err != nil {
+			// an interrupted write can leave a partial last line
+			continue
+		}
+		if p.Filename != "" {
+			done[p.Filename] = p
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read progress: %w", err)
+	}
+	return done, nil
+}
+
+// progressFile appends the completed entries to the progress file.
+type progressFile struct {
+	f *os.File
+}
+
+func openProgress(name string) (*progressFile, error) {
+	if name == "" {
+		return &progressFile{}, nil
+	}
+	const perm = 0o644
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, perm)
+	if err != nil {
+		return nil, fmt.Errorf("open progress: %w", err)
+	}
+	return &progressFile{f: f}, nil
+}
+
+// save appends the completed entry and syncs the file, so it survives an interruption of the import.
+func (p *progressFile) save(pr Progress) error {
+	if p.f == nil {
+		return nil
+	}
+	b, err := json.Marshal(pr)
+	if err != nil {
+		return fmt.Errorf("save progress: %w", err)
+	}
+	if _, err := p.f.Write(append(b, '\n')); err != nil {
+		return fmt.Errorf("save progress: %w", err)
+	}
+	if err := p.f.Sync(); err != nil {
+		return fmt.Errorf("save progress: %w", err)
+	}
+	return nil
+}
+
+func (p *progressFile) close() error {
+	if p.f == nil {
+		return nil
+	}
+	return p.f.Close()
+}
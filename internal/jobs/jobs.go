@@ -0,0 +1,353 @@
+// Package jobs runs the background jobs that create the assets of the artifacts,
+// such as the preview images of texts and the repackaged archives. The jobs are
+// saved to the database, so they persist between restarts, and are run by a bounded
+// pool of workers that retry the failed jobs with an exponential backoff.
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Defacto2/archive"
+	"github.com/Defacto2/server/handler/fulltext"
+	"github.com/Defacto2/server/internal/command"
+	"github.com/Defacto2/server/internal/logs"
+	"github.com/Defacto2/server/internal/manifest"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/model"
+)
+
+var (
+	ErrKind    = errors.New("job kind is not registered")
+	ErrNoQueue = errors.New("job queue is nil")
+)
+
+// Kind is the name of a task that can be queued.
+type Kind string
+
+const (
+	PictureImager   Kind = "picture-imager"    // PictureImager creates the preview and thumbnail of an image.
+	TextImager      Kind = "text-imager"       // TextImager creates the preview and thumbnail of a text.
+	AmigaTextImager Kind = "amiga-text-imager" // AmigaTextImager creates the images of a text using an Amiga font.
+	BinTextImager   Kind = "bintext-imager"    // BinTextImager creates the images of a binary text.
+	TextDeferred    Kind = "text-deferred"     // TextDeferred creates a thumbnail and copies the text to the extras.
+	DizDeferred     Kind = "diz-deferred"      // DizDeferred copies a FILE_ID.DIZ to the extras.
+	ReplacementZip  Kind = "replacement-zip"   // ReplacementZip repackages an archive as a deflated zip.
+	Metadata        Kind = "metadata"          // Metadata reads the manifest, SAUCE, texts and encoding of a download.
+)
+
+// Func is the task of a job kind, the src is the named file of the job and the unid is
+// the universal unique id of the artifact.
+type Func func(ctx context.Context, sl *slog.Logger, src, unid string) error
+
+const (
+	// Workers is the default number of jobs that can run at the same time.
+	Workers = 2
+	// Attempts is the default number of times a job is run before it is marked as failed.
+	Attempts = 4
+	// Timeout is the maximum duration of a single attempt of a job.
+	Timeout = 10 * command.CmdTimeout
+	// Poll is the duration between the checks of the database for jobs that are ready to run.
+	Poll = 5 * time.Second
+)
+
+// Backoff returns the delay before the retry of a job that has failed the number of attempts.
+// The delay doubles with each attempt, starting at 30 seconds, up to a maximum of an hour.
+func Backoff(attempts int) time.Duration {
+	const base, limit = 30 * time.Second, time.Hour
+	d := base
+	for range max(attempts-1, 0) {
+		d *= 2
+		if d >= limit {
+			return limit
+		}
+	}
+	return d
+}
+
+// Queue is the database backed job queue and its pool of workers.
+type Queue struct {
+	db       *sql.DB
+	sl       *slog.Logger
+	mu       sync.Mutex
+	funcs    map[Kind]Func
+	running  map[int64]context.CancelFunc
+	wake     chan struct{}
+	Workers  int // Workers is the number of jobs that can run at the same time.
+	Attempts int // Attempts is the number of times a job is run before it is marked as failed.
+}
+
+// New returns a job queue using the database and the logger.
+// The tasks of the job kinds must be registered before the queue is run.
+func New(db *sql.DB, sl *slog.Logger) *Queue {
+	if sl == nil {
+		sl = logs.Discard()
+	}
+	return &Queue{
+		db:       db,
+		sl:       sl,
+		funcs:    make(map[Kind]Func),
+		running:  make(map[int64]context.CancelFunc),
+		wake:     make(chan struct{}, 1),
+		Workers:  Workers,
+		Attempts: Attempts,
+	}
+}
+
+// Register the task of the job kind, replacing any existing task.
+func (q *Queue) Register(kind Kind, fn Func) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.funcs[kind] = fn
+}
+
+// charset returns the key of the text encoding chosen by an editor for the artifact unid,
+// or an empty string when the encoding is detected or cannot be found.
+func (q *Queue) charset(ctx context.Context, unid string) string {
+	if q.db == nil {
+		return ""
+	}
+	key, err := model.EncodingByUUID(ctx, q.db, unid)
+	if err != nil {
+		q.sl.Error("job charset", slog.String("uuid", unid), slog.Any("error", err))
+		return ""
+	}
+	return key
+}
+
+// Commands registers the tasks of the job kinds that use the command directories.
+func (q *Queue) Commands(dirs command.Dirs) {
+	q.Register(PictureImager, dirs.PictureImager)
+	q.Register(TextImager, func(ctx context.Context, sl *slog.Logger, src, unid string) error {
+		return dirs.CharsetImager(ctx, sl, src, unid, q.charset(ctx, unid), false)
+	})
+	q.Register(AmigaTextImager, func(ctx context.Context, sl *slog.Logger, src, unid string) error {
+		return dirs.CharsetImager(ctx, sl, src, unid, q.charset(ctx, unid), true)
+	})
+	q.Register(BinTextImager, dirs.BinTextImager)
+	q.Register(TextDeferred, dirs.TextDeferred)
+	q.Register(DizDeferred, func(_ context.Context, sl *slog.Logger, src, unid string) error {
+		return dirs.DizDeferred(sl, src, unid)
+	})
+}
+
+// Metadata registers the task that reads the metadata of a new or a replaced artifact download,
+// which is the archive manifest, the SAUCE, the plain texts for the full-text search and the text encoding.
+// The credits of the artifact are also linked to the scener identities.
+// The download and extra are the directory paths of the downloads and the extra files.
+func (q *Queue) Metadata(download, extra string) {
+	q.Register(Metadata, func(ctx context.Context, _ *slog.Logger, _, unid string) error {
+		return metadata(ctx, q.db, download, extra, unid)
+	})
+}
+
+// metadata reads and saves the metadata of the download of the artifact unid.
+// Every step is run and the errors of those that fail are returned together,
+// as the steps are safe to repeat when the job is retried.
+func metadata(ctx context.Context, db *sql.DB, download, extra, unid string) error {
+	const msg = "metadata"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	art, err := model.OneByUUID(ctx, db, true, unid)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	src := filepath.Join(download, unid)
+	var errs []error
+	if strings.TrimSpace(art.FileZipContent.String) != "" {
+		err := model.SaveManifest(ctx, db, art.ID, src, art.Filename.String)
+		if err != nil && !errors.Is(err, manifest.ErrEmpty) && !errors.Is(err, archive.ErrNotArchive) {
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs,
+		model.SaveSauce(ctx, db, art.ID, src),
+		model.IndexText(ctx, db, fulltext.Texts{Download: download, Extra: extra}, art.ID),
+		model.DetectEncoding(ctx, db, art.ID, download, extra))
+	if _, err := model.SyncArtifactIdentities(ctx, db, art.ID); err != nil {
+		errs = append(errs, err)
+	}
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return nil
+}
+
+func (q *Queue) task(kind Kind) (Func, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	fn, ok := q.funcs[kind]
+	return fn, ok
+}
+
+// Enqueue adds a job to the queue and wakes an idle worker.
+// A job of the same kind and artifact that is pending or running is not queued twice,
+// instead the id of the existing job is returned.
+func (q *Queue) Enqueue(ctx context.Context, kind Kind, src, unid string) (int64, error) {
+	const msg = "jobs enqueue"
+	if q == nil {
+		return 0, fmt.Errorf("%s: %w", msg, ErrNoQueue)
+	}
+	if _, ok := q.task(kind); !ok {
+		return 0, fmt.Errorf("%s: %w: %q", msg, ErrKind, kind)
+	}
+	id, err := model.EnqueueJob(ctx, q.db, string(kind), unid, src)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	q.notify()
+	return id, nil
+}
+
+// Cancel the job id, a running job has its context cancelled.
+func (q *Queue) Cancel(ctx context.Context, id int64) error {
+	const msg = "jobs cancel"
+	if q == nil {
+		return fmt.Errorf("%s: %w", msg, ErrNoQueue)
+	}
+	if err := model.CancelJob(ctx, q.db, id); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if cancel, ok := q.running[id]; ok {
+		cancel()
+	}
+	return nil
+}
+
+// Requeue the failed or cancelled job id so it is run again.
+func (q *Queue) Requeue(ctx context.Context, id int64) error {
+	const msg = "jobs requeue"
+	if q == nil {
+		return fmt.Errorf("%s: %w", msg, ErrNoQueue)
+	}
+	if err := model.RequeueJob(ctx, q.db, id); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	q.notify()
+	return nil
+}
+
+func (q *Queue) notify() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run the pool of workers until the context is cancelled.
+// The jobs that were running when the server was previously stopped are returned to the queue.
+func (q *Queue) Run(ctx context.Context) error {
+	const msg = "jobs run"
+	if q == nil {
+		return fmt.Errorf("%s: %w", msg, ErrNoQueue)
+	}
+	if err := nils.Check(ctx, q.db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if i, err := model.ResetJobs(ctx, q.db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	} else if i > 0 {
+		q.sl.Info(msg, slog.Int64("interrupted jobs requeued", i))
+	}
+	var wg sync.WaitGroup
+	for range max(q.Workers, 1) {
+		wg.Go(func() {
+			q.work(ctx)
+		})
+	}
+	wg.Wait()
+	return nil
+}
+
+// work claims and runs the jobs until there are none ready, then waits for
+// a new job to be queued or the poll duration to elapse.
+func (q *Queue) work(ctx context.Context) {
+	ticker := time.NewTicker(Poll)
+	defer ticker.Stop()
+	for {
+		for q.next(ctx) {
+			if ctx.Err() != nil {
+				return
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-q.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// next claims and runs a job and returns true, or returns false when there are no jobs ready to run.
+func (q *Queue) next(ctx context.Context) bool {
+	const msg = "jobs worker"
+	j, err := model.ClaimJob(ctx, q.db)
+	if err != nil {
+		if ctx.Err() == nil {
+			q.sl.Error(msg, slog.String("problem", "could not claim a job"), slog.Any("error", err))
+		}
+		return false
+	}
+	if j == nil {
+		return false
+	}
+	err = q.run(ctx, j)
+	// use a new context to save the result, as the worker context is cancelled on shutdown
+	sctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), command.CmdTimeout)
+	defer cancel()
+	switch {
+	case err == nil:
+		err = model.FinishJob(sctx, q.db, j.ID)
+	case errors.Is(err, context.Canceled) && ctx.Err() != nil:
+		// the server is shutting down, so the job is requeued on the next startup
+		return false
+	case errors.Is(err, context.Canceled):
+		// the job was cancelled by an editor and the status is already saved
+		q.sl.Info(msg, slog.Int64("cancelled job", j.ID), slog.String("kind", j.Kind))
+		return true
+	case errors.Is(err, ErrKind) || j.Attempts >= q.Attempts:
+		q.sl.Error(msg, slog.String("problem", "job failed"), slog.Int64("job", j.ID),
+			slog.String("kind", j.Kind), slog.String("uuid", j.UUID),
+			slog.Int("attempts", j.Attempts), slog.Any("error", err))
+		err = model.FailJob(sctx, q.db, j.ID, err)
+	default:
+		q.sl.Warn(msg, slog.String("problem", "job will be retried"), slog.Int64("job", j.ID),
+			slog.String("kind", j.Kind), slog.Int("attempts", j.Attempts), slog.Any("error", err))
+		err = model.RetryJob(sctx, q.db, j.ID, err, time.Now().Add(Backoff(j.Attempts)))
+	}
+	if err != nil && !errors.Is(err, model.ErrJob) {
+		q.sl.Error(msg, slog.String("problem", "could not save the job status"),
+			slog.Int64("job", j.ID), slog.Any("error", err))
+	}
+	return true
+}
+
+// run the task of the job with a timeout and a cancel func that is used by [Queue.Cancel].
+func (q *Queue) run(ctx context.Context, j *model.Job) error {
+	fn, ok := q.task(Kind(j.Kind))
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrKind, j.Kind)
+	}
+	ctx, cancel := context.WithTimeout(ctx, Timeout)
+	defer cancel()
+	q.mu.Lock()
+	q.running[j.ID] = cancel
+	q.mu.Unlock()
+	defer func() {
+		q.mu.Lock()
+		delete(q.running, j.ID)
+		q.mu.Unlock()
+	}()
+	return fn(ctx, q.sl, j.Src, j.UUID)
+}
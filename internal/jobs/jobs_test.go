@@ -0,0 +1,55 @@
+package jobs_test
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/internal/command"
+	"github.com/Defacto2/server/internal/jobs"
+	"github.com/nalgeon/be"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, jobs.Backoff(0), 30*time.Second)
+	be.Equal(t, jobs.Backoff(1), 30*time.Second)
+	be.Equal(t, jobs.Backoff(2), time.Minute)
+	be.Equal(t, jobs.Backoff(3), 2*time.Minute)
+	be.Equal(t, jobs.Backoff(100), time.Hour)
+}
+
+func TestNilQueue(t *testing.T) {
+	t.Parallel()
+	var q *jobs.Queue
+	ctx := t.Context()
+	_, err := q.Enqueue(ctx, jobs.TextImager, "", "uuid")
+	be.Err(t, err, jobs.ErrNoQueue)
+	be.Err(t, q.Cancel(ctx, 1), jobs.ErrNoQueue)
+	be.Err(t, q.Requeue(ctx, 1), jobs.ErrNoQueue)
+	be.Err(t, q.Run(ctx), jobs.ErrNoQueue)
+}
+
+func TestEnqueue(t *testing.T) {
+	t.Parallel()
+	q := jobs.New(nil, nil)
+	ctx := t.Context()
+	_, err := q.Enqueue(ctx, jobs.ReplacementZip, "", "uuid")
+	be.Err(t, err, jobs.ErrKind)
+	q.Register(jobs.ReplacementZip, func(context.Context, *slog.Logger, string, string) error {
+		return nil
+	})
+	q.Commands(command.Dirs{})
+	// the kind is registered, but there is no database
+	_, err = q.Enqueue(ctx, jobs.ReplacementZip, "", "uuid")
+	be.Err(t, err)
+	_, err = q.Enqueue(ctx, jobs.TextImager, "", "uuid")
+	be.Err(t, err)
+	_, err = q.Enqueue(ctx, jobs.Metadata, "", "uuid")
+	be.Err(t, err, jobs.ErrKind)
+	q.Metadata("", "")
+	_, err = q.Enqueue(ctx, jobs.Metadata, "", "uuid")
+	be.Err(t, err)
+	be.Err(t, q.Run(ctx))
+}
@@ -0,0 +1,190 @@
+// Package manifest reads the structured list of the files contained in an archive,
+// which replaces the plain list of names stored in the file_zip_content column.
+// Each file or member of the archive includes its size, checksums, modification time,
+// the detected file signature, and for the texts, the detected character encoding.
+package manifest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Defacto2/archive"
+	"github.com/Defacto2/magicnumber"
+)
+
+var ErrEmpty = errors.New("archive contains no files")
+
+// Encodings of the text members.
+const (
+	ASCII = "ASCII"   // ASCII is a 7-bit text.
+	CP437 = "CP-437"  // CP437 is an 8-bit IBM PC text, the encoding of most of the scene texts.
+	UTF8  = "UTF-8"   // UTF8 is a Unicode text.
+	UTF16 = "UTF-16"  // UTF16 is a Unicode text using 16-bit units.
+	UTF32 = "UTF-32"  // UTF32 is a Unicode text using 32-bit units.
+	Other = "unknown" // Other is a text that could not be detected.
+)
+
+// Member is a file contained in an archive.
+type Member struct {
+	Path      string    `boil:"path"      json:"path"`               // Path of the file within the archive, using forward slashes.
+	Size      int64     `boil:"size"      json:"size"`               // Size of the file in bytes.
+	CRC32     string    `boil:"crc32"     json:"crc32"`              // CRC32 is the IEEE checksum of the file as a hexadecimal value.
+	SHA256    string    `boil:"sha256"    json:"sha256"`             // SHA256 is the hash of the file as a hexadecimal value.
+	Modified  time.Time `boil:"modified"  json:"modified"`           // Modified is the last modification time of the file.
+	Signature string    `boil:"signature" json:"signature"`          // Signature is the title of the detected magic number.
+	Encoding  string    `boil:"encoding"  json:"encoding,omitempty"` // Encoding is the character encoding of a text, or empty for other files.
+}
+
+// Read extracts the src archive file to a temporary directory and returns the manifest of the contained files.
+// The filename is the original name of the archive and is used to determine the archive format.
+// The temporary directory is removed before returning.
+func Read(src, filename string) ([]Member, error) {
+	var members []Member
+	err := Extract(src, filename, func(root string) error {
+		var err error
+		members, err = Dir(root)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// Extract extracts the src archive file to a temporary directory and calls fn with the directory,
+// so the contained files can be read more than once without extracting the archive again.
+// The filename is the original name of the archive and is used to determine the archive format.
+// The temporary directory is removed before returning.
+func Extract(src, filename string, fn func(root string) error) error {
+	tmp, err := archive.ExtractSource(src, filename)
+	if err != nil {
+		return fmt.Errorf("manifest read %q: %w", filename, err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+	if err := fn(tmp); err != nil {
+		return fmt.Errorf("manifest read %q: %w", filename, err)
+	}
+	return nil
+}
+
+// Dir returns the manifest of the files in the named directory, sorted by their path.
+func Dir(root string) ([]Member, error) {
+	members := []Member{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return fmt.Errorf("manifest path: %w", err)
+		}
+		m, err := File(path)
+		if err != nil {
+			return err
+		}
+		m.Path = filepath.ToSlash(rel)
+		members = append(members, m)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("manifest dir: %w", err)
+	}
+	if len(members) == 0 {
+		return nil, ErrEmpty
+	}
+	slices.SortFunc(members, func(a, b Member) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return members, nil
+}
+
+// File returns the manifest of the named file, the path is the base name of the file.
+func File(name string) (Member, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return Member{}, fmt.Errorf("manifest file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	st, err := f.Stat()
+	if err != nil {
+		return Member{}, fmt.Errorf("manifest file: %w", err)
+	}
+	crc, sum := crc32.NewIEEE(), sha256.New()
+	if _, err := io.Copy(io.MultiWriter(crc, sum), f); err != nil {
+		return Member{}, fmt.Errorf("manifest file %q: %w", name, err)
+	}
+	sign := magicnumber.Find(f)
+	m := Member{
+		Path:      filepath.Base(name),
+		Size:      st.Size(),
+		CRC32:     hex.EncodeToString(crc.Sum(nil)),
+		SHA256:    hex.EncodeToString(sum.Sum(nil)),
+		Modified:  st.ModTime().UTC().Truncate(time.Second),
+		Signature: sign.Title(),
+	}
+	if IsText(sign) {
+		m.Encoding = Encoding(sign, f)
+	}
+	return m, nil
+}
+
+// IsText returns true if the signature is a text.
+func IsText(sign magicnumber.Signature) bool {
+	return slices.Contains(magicnumber.Texts(), sign) ||
+		sign == magicnumber.PlainText || sign == magicnumber.ANSIEscapeText
+}
+
+// Encoding returns the detected character encoding of the text signature.
+// The 8-bit texts that are not valid UTF-8 are assumed to use the IBM PC, CP-437 encoding.
+func Encoding(sign magicnumber.Signature, r io.ReaderAt) string {
+	switch sign {
+	case magicnumber.UTF16Text:
+		return UTF16
+	case magicnumber.UTF32Text:
+		return UTF32
+	}
+	const sample = 64 * 1024
+	b := make([]byte, sample)
+	n, err := r.ReadAt(b, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return Other
+	}
+	if n == sample {
+		// the sample could end with an incomplete rune
+		for i := n - 1; i >= 0 && i >= n-utf8.UTFMax; i-- {
+			if utf8.RuneStart(b[i]) {
+				if !utf8.FullRune(b[i:n]) {
+					n = i
+				}
+				break
+			}
+		}
+	}
+	return Detect(b[:n])
+}
+
+// Detect returns the character encoding of the text.
+func Detect(b []byte) string {
+	if !utf8.Valid(b) {
+		return CP437
+	}
+	if bytes.IndexFunc(b, func(r rune) bool { return r >= utf8.RuneSelf }) == -1 {
+		return ASCII
+	}
+	return UTF8
+}
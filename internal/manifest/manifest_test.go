@@ -0,0 +1,63 @@
+package manifest_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Defacto2/server/internal/manifest"
+	"github.com/nalgeon/be"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, manifest.Detect(nil), manifest.ASCII)
+	be.Equal(t, manifest.Detect([]byte("hello world")), manifest.ASCII)
+	be.Equal(t, manifest.Detect([]byte("café")), manifest.UTF8)
+	// CP-437 box drawing characters and a CP-437 e-acute
+	be.Equal(t, manifest.Detect([]byte{0xc9, 0xcd, 0xbb, ' ', 'c', 'a', 'f', 0x82}), manifest.CP437)
+}
+
+func TestEncoding(t *testing.T) {
+	t.Parallel()
+	const sample = 64 * 1024
+	// a UTF-8 text where the sample ends in the middle of a rune
+	s := "é" + strings.Repeat("a", sample-3) + "é"
+	be.Equal(t, manifest.Encoding(0, strings.NewReader(s)), manifest.UTF8)
+}
+
+func TestFile(t *testing.T) {
+	t.Parallel()
+	name := filepath.Join(t.TempDir(), "hello.txt")
+	err := os.WriteFile(name, []byte("hello world"), 0o600)
+	be.Err(t, err, nil)
+	m, err := manifest.File(name)
+	be.Err(t, err, nil)
+	be.Equal(t, m.Path, "hello.txt")
+	be.Equal(t, m.Size, int64(11))
+	be.Equal(t, m.CRC32, "0d4a1185")
+	be.Equal(t, m.SHA256, "b94d27b9934d3e08a52e52d7da7dabfac484efe37a5380ee9088f7ace2efcde9")
+	be.True(t, !m.Modified.IsZero())
+
+	_, err = manifest.File(filepath.Join(t.TempDir(), "missing"))
+	be.Err(t, err)
+}
+
+func TestDir(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	_, err := manifest.Dir(root)
+	be.Err(t, err, manifest.ErrEmpty)
+	err = os.MkdirAll(filepath.Join(root, "docs"), 0o700)
+	be.Err(t, err, nil)
+	err = os.WriteFile(filepath.Join(root, "docs", "readme.txt"), []byte("read me"), 0o600)
+	be.Err(t, err, nil)
+	err = os.WriteFile(filepath.Join(root, "a.exe"), []byte("MZ"), 0o600)
+	be.Err(t, err, nil)
+	ms, err := manifest.Dir(root)
+	be.Err(t, err, nil)
+	be.Equal(t, len(ms), 2)
+	be.Equal(t, ms[0].Path, "a.exe")
+	be.Equal(t, ms[1].Path, "docs/readme.txt")
+}
@@ -0,0 +1,280 @@
+package postgres
+
+// Package file schema.go contains the statements to create the supplementary tables
+// that are not generated by the SQLBoiler tool. Unlike the files table, these tables
+// are created by the web application on startup and so every statement must be safe
+// to run repeatedly.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Defacto2/server/internal/nils"
+)
+
+const (
+	// CreateTexts is a SQL statement to create the table of plain texts,
+	// such as NFO, DIZ and README files, that are extracted from the artifacts.
+	CreateTexts SQL = "CREATE TABLE IF NOT EXISTS file_texts (" +
+		"id BIGSERIAL PRIMARY KEY, " +
+		"file_id BIGINT NOT NULL REFERENCES files (id) ON DELETE CASCADE, " +
+		"name TEXT NOT NULL, " +
+		"body TEXT NOT NULL, " +
+		"body_tsv TSVECTOR GENERATED ALWAYS AS (to_tsvector('simple', body)) STORED, " +
+		"modified TIMESTAMPTZ NOT NULL, " +
+		"UNIQUE (file_id, name));"
+	// CreateTextsIdx is a SQL statement to create the full-text search index of the plain texts.
+	CreateTextsIdx SQL = "CREATE INDEX IF NOT EXISTS file_texts_tsv_idx ON file_texts USING GIN (body_tsv);"
+	// CreateChanges is a SQL statement to create the log of the changes made to the files table.
+	// The file_id has no foreign key so the log retains the records that are permanently deleted.
+	CreateChanges SQL = "CREATE TABLE IF NOT EXISTS file_changes (" +
+		"seq BIGSERIAL PRIMARY KEY, " +
+		"file_id BIGINT NOT NULL, " +
+		"uuid TEXT, " +
+		"kind TEXT NOT NULL, " +
+		"changed TIMESTAMPTZ NOT NULL DEFAULT now());"
+	// CreateChangesIdx is a SQL statement to create the index of the changes by their file id.
+	CreateChangesIdx SQL = "CREATE INDEX IF NOT EXISTS file_changes_file_id_idx ON file_changes (file_id);"
+	// CreateAudits is a SQL statement to create the audit trail of the editor changes made to the files table.
+	// The editor is the Google account ID of the signed in editor, or null for the automated changes.
+	// The file_id has no foreign key so the trail retains the history of the records that are permanently deleted.
+	// The created flag is true for the entries that log the values of a new record and so have nothing to revert.
+	CreateAudits SQL = "CREATE TABLE IF NOT EXISTS file_audits (" +
+		"id BIGSERIAL PRIMARY KEY, " +
+		"file_id BIGINT NOT NULL, " +
+		"editor TEXT, " +
+		"field TEXT NOT NULL, " +
+		"old_value TEXT, " +
+		"new_value TEXT, " +
+		"changed TIMESTAMPTZ NOT NULL DEFAULT now(), " +
+		"created BOOLEAN NOT NULL DEFAULT false);"
+	// CreateAuditsIdx is a SQL statement to create the index of the audit trail by their file id.
+	CreateAuditsIdx SQL = "CREATE INDEX IF NOT EXISTS file_audits_file_id_idx ON file_audits (file_id, id DESC);"
+	// CreateJobs is a SQL statement to create the queue of the background jobs that create the artifact assets.
+	// The uuid is the universal unique id of the artifact and the src is the named file to process.
+	CreateJobs SQL = "CREATE TABLE IF NOT EXISTS file_jobs (" +
+		"id BIGSERIAL PRIMARY KEY, " +
+		"kind TEXT NOT NULL, " +
+		"uuid TEXT NOT NULL, " +
+		"src TEXT NOT NULL DEFAULT '', " +
+		"status TEXT NOT NULL DEFAULT 'pending', " +
+		"attempts INTEGER NOT NULL DEFAULT 0, " +
+		"last_error TEXT, " +
+		"run_after TIMESTAMPTZ NOT NULL DEFAULT now(), " +
+		"created TIMESTAMPTZ NOT NULL DEFAULT now(), " +
+		"updated TIMESTAMPTZ NOT NULL DEFAULT now());"
+	// CreateJobsIdx is a SQL statement to create the index of the queued jobs, that also prevents
+	// a duplicate job of the same kind and artifact from being queued while another is unfinished.
+	CreateJobsIdx SQL = "CREATE UNIQUE INDEX IF NOT EXISTS file_jobs_queued_idx ON file_jobs (kind, uuid) " +
+		"WHERE status IN ('pending', 'running');"
+	// CreateReleasers is a SQL statement to create the table of the editor managed releaser metadata.
+	// The slug is the partial URL path of the releaser, while the lists are stored as JSON arrays.
+	CreateReleasers SQL = "CREATE TABLE IF NOT EXISTS releasers (" +
+		"slug TEXT PRIMARY KEY, " +
+		"name TEXT NOT NULL DEFAULT '', " +
+		"initialisms JSONB NOT NULL DEFAULT '[]', " +
+		"csdb INTEGER NOT NULL DEFAULT 0, " +
+		"demozoo INTEGER NOT NULL DEFAULT 0, " +
+		"janeway INTEGER NOT NULL DEFAULT 0, " +
+		"sixteen TEXT NOT NULL DEFAULT '', " +
+		"websites JSONB NOT NULL DEFAULT '[]', " +
+		"tidbits JSONB NOT NULL DEFAULT '[]', " +
+		"updated TIMESTAMPTZ NOT NULL DEFAULT now());"
+	// CreateAliases is a SQL statement to create the table of the renamed and merged releasers.
+	// The alias is the slug of the former releaser that is redirected to the slug of the target releaser.
+	CreateAliases SQL = "CREATE TABLE IF NOT EXISTS releaser_aliases (" +
+		"alias TEXT PRIMARY KEY, " +
+		"target TEXT NOT NULL, " +
+		"created TIMESTAMPTZ NOT NULL DEFAULT now());"
+	// CreateSceners is a SQL statement to create the table of the scener identities.
+	// The slug is the partial URL path of the scener, which tells apart two people using the same handle.
+	CreateSceners SQL = "CREATE TABLE IF NOT EXISTS sceners (" +
+		"id BIGSERIAL PRIMARY KEY, " +
+		"slug TEXT NOT NULL UNIQUE, " +
+		"name TEXT NOT NULL, " +
+		"demozoo INTEGER NOT NULL DEFAULT 0, " +
+		"created TIMESTAMPTZ NOT NULL DEFAULT now(), " +
+		"updated TIMESTAMPTZ NOT NULL DEFAULT now());"
+	// CreateScenerAliases is a SQL statement to create the table of the handles used by the sceners.
+	// The alias is an uppercased name as found in the credit columns of the files table.
+	CreateScenerAliases SQL = "CREATE TABLE IF NOT EXISTS scener_aliases (" +
+		"alias TEXT NOT NULL, " +
+		"scener_id BIGINT NOT NULL REFERENCES sceners (id) ON DELETE CASCADE, " +
+		"PRIMARY KEY (alias, scener_id));"
+	// CreateScenerCredits is a SQL statement to create the table that links the sceners to the artifacts by role.
+	CreateScenerCredits SQL = "CREATE TABLE IF NOT EXISTS scener_credits (" +
+		"scener_id BIGINT NOT NULL REFERENCES sceners (id) ON DELETE CASCADE, " +
+		"file_id BIGINT NOT NULL REFERENCES files (id) ON DELETE CASCADE, " +
+		"role TEXT NOT NULL, " +
+		"PRIMARY KEY (scener_id, file_id, role));"
+	// CreateScenerCreditsIdx is a SQL statement to create the index of the scener credits by their file id.
+	CreateScenerCreditsIdx SQL = "CREATE INDEX IF NOT EXISTS scener_credits_file_id_idx ON scener_credits (file_id);"
+	// CreateScenerMembers is a SQL statement to create the table of the groups that the sceners were members of.
+	// The releaser is the partial URL path of the group.
+	CreateScenerMembers SQL = "CREATE TABLE IF NOT EXISTS scener_members (" +
+		"scener_id BIGINT NOT NULL REFERENCES sceners (id) ON DELETE CASCADE, " +
+		"releaser TEXT NOT NULL, " +
+		"PRIMARY KEY (scener_id, releaser));"
+	// CreateRelations is a SQL statement to create the table of the typed relations between the artifacts.
+	// The relation is an edge from the artifact file_id to the target artifact, such as an NFO for a release.
+	CreateRelations SQL = "CREATE TABLE IF NOT EXISTS artifact_relations (" +
+		"id BIGSERIAL PRIMARY KEY, " +
+		"file_id BIGINT NOT NULL REFERENCES files (id) ON DELETE CASCADE, " +
+		"target_id BIGINT NOT NULL REFERENCES files (id) ON DELETE CASCADE, " +
+		"kind TEXT NOT NULL, " +
+		"label TEXT NOT NULL DEFAULT '', " +
+		"created TIMESTAMPTZ NOT NULL DEFAULT now(), " +
+		"UNIQUE (file_id, target_id, kind), " +
+		"CHECK (file_id <> target_id));"
+	// CreateRelationsIdx is a SQL statement to create the index of the relations by their target artifact.
+	CreateRelationsIdx SQL = "CREATE INDEX IF NOT EXISTS artifact_relations_target_id_idx " +
+		"ON artifact_relations (target_id);"
+	// CreateLinks is a SQL statement to create the table of the typed external links of the artifacts.
+	CreateLinks SQL = "CREATE TABLE IF NOT EXISTS artifact_links (" +
+		"id BIGSERIAL PRIMARY KEY, " +
+		"file_id BIGINT NOT NULL REFERENCES files (id) ON DELETE CASCADE, " +
+		"kind TEXT NOT NULL, " +
+		"label TEXT NOT NULL, " +
+		"url TEXT NOT NULL, " +
+		"created TIMESTAMPTZ NOT NULL DEFAULT now(), " +
+		"UNIQUE (file_id, url));"
+	// CreateKeywords is a SQL statement to create the editor curated vocabulary of the secondary tags,
+	// such as "sound-blaster" or "96k-intro", that supplement the platform and section tags of the artifacts.
+	CreateKeywords SQL = "CREATE TABLE IF NOT EXISTS keywords (" +
+		"slug TEXT PRIMARY KEY, " +
+		"name TEXT NOT NULL, " +
+		"info TEXT NOT NULL DEFAULT '', " +
+		"created TIMESTAMPTZ NOT NULL DEFAULT now());"
+	// CreateFileKeywords is a SQL statement to create the table that tags the artifacts with the keywords.
+	CreateFileKeywords SQL = "CREATE TABLE IF NOT EXISTS file_keywords (" +
+		"file_id BIGINT NOT NULL REFERENCES files (id) ON DELETE CASCADE, " +
+		"keyword TEXT NOT NULL REFERENCES keywords (slug) ON DELETE CASCADE ON UPDATE CASCADE, " +
+		"PRIMARY KEY (file_id, keyword));"
+	// CreateFileKeywordsIdx is a SQL statement to create the index of the tagged artifacts by their keyword.
+	CreateFileKeywordsIdx SQL = "CREATE INDEX IF NOT EXISTS file_keywords_keyword_idx ON file_keywords (keyword);"
+	// CreateHashes is a SQL statement to create the table of the fingerprints used to find the duplicate artifacts.
+	// The kind is either a "member" file hash, a normalized "text" hash or a perceptual "image" hash of the thumbnail.
+	CreateHashes SQL = "CREATE TABLE IF NOT EXISTS file_hashes (" +
+		"file_id BIGINT NOT NULL REFERENCES files (id) ON DELETE CASCADE, " +
+		"kind TEXT NOT NULL, " +
+		"hash TEXT NOT NULL, " +
+		"name TEXT NOT NULL DEFAULT '', " +
+		"modified TIMESTAMPTZ NOT NULL, " +
+		"PRIMARY KEY (file_id, kind, hash));"
+	// CreateHashesIdx is a SQL statement to create the index of the fingerprints by their kind and hash.
+	CreateHashesIdx SQL = "CREATE INDEX IF NOT EXISTS file_hashes_kind_hash_idx ON file_hashes (kind, hash);"
+	// CreateMembers is a SQL statement to create the table of the archive manifests, the files contained in the artifacts.
+	// The modified time is that of the member, while the stamp is the last modified time of the artifact download.
+	// The sauced flag is true for the manifests that were read with the SAUCE metadata of the members.
+	CreateMembers SQL = "CREATE TABLE IF NOT EXISTS file_members (" +
+		"file_id BIGINT NOT NULL REFERENCES files (id) ON DELETE CASCADE, " +
+		"path TEXT NOT NULL, " +
+		"size BIGINT NOT NULL, " +
+		"crc32 TEXT NOT NULL, " +
+		"sha256 TEXT NOT NULL, " +
+		"modified TIMESTAMPTZ NOT NULL, " +
+		"signature TEXT NOT NULL DEFAULT '', " +
+		"encoding TEXT NOT NULL DEFAULT '', " +
+		"stamp TIMESTAMPTZ NOT NULL, " +
+		"sauced BOOLEAN NOT NULL DEFAULT false, " +
+		"PRIMARY KEY (file_id, path));"
+	// CreateMembersIdx is a SQL statement to create the index of the archive members by their hash.
+	CreateMembersIdx SQL = "CREATE INDEX IF NOT EXISTS file_members_sha256_idx ON file_members (sha256);"
+	// CreateHits is a SQL statement to create the table of the daily totals of the artifact
+	// downloads, emulations and page views. The totals are aggregates and no visitor details are stored.
+	CreateHits SQL = "CREATE TABLE IF NOT EXISTS file_hits (" +
+		"file_id BIGINT NOT NULL REFERENCES files (id) ON DELETE CASCADE, " +
+		"day DATE NOT NULL, " +
+		"kind TEXT NOT NULL, " +
+		"count BIGINT NOT NULL DEFAULT 0, " +
+		"PRIMARY KEY (file_id, day, kind));"
+	// CreateHitsIdx is a SQL statement to create the index of the daily totals by their day.
+	CreateHitsIdx SQL = "CREATE INDEX IF NOT EXISTS file_hits_day_idx ON file_hits (day, kind);"
+	// CreateSauces is a SQL statement to create the table of the SAUCE metadata of the artifact downloads
+	// and of the files contained in their archives. The member is empty for the metadata of the download,
+	// while the stamp is the last modified time of the artifact download.
+	CreateSauces SQL = "CREATE TABLE IF NOT EXISTS file_sauces (" +
+		"file_id BIGINT NOT NULL REFERENCES files (id) ON DELETE CASCADE, " +
+		"member TEXT NOT NULL DEFAULT '', " +
+		"title TEXT NOT NULL DEFAULT '', " +
+		"author TEXT NOT NULL DEFAULT '', " +
+		"grp TEXT NOT NULL DEFAULT '', " +
+		"date DATE, " +
+		"data_type SMALLINT NOT NULL DEFAULT 0, " +
+		"file_type SMALLINT NOT NULL DEFAULT 0, " +
+		"type_name TEXT NOT NULL DEFAULT '', " +
+		"width INTEGER NOT NULL DEFAULT 0, " +
+		"lines INTEGER NOT NULL DEFAULT 0, " +
+		"flags SMALLINT NOT NULL DEFAULT 0, " +
+		"font TEXT NOT NULL DEFAULT '', " +
+		"comments TEXT NOT NULL DEFAULT '', " +
+		"stamp TIMESTAMPTZ NOT NULL, " +
+		"PRIMARY KEY (file_id, member));"
+	// CreateEncodings is a SQL statement to create the table of the text encodings of the artifacts.
+	// The encoding is the override chosen by an editor, or empty to use the detected encoding,
+	// while the detected encoding, its confidence percentage and the stamp, the last modified time
+	// of the detected text, are updated by the detection walks.
+	CreateEncodings SQL = "CREATE TABLE IF NOT EXISTS file_encodings (" +
+		"file_id BIGINT PRIMARY KEY REFERENCES files (id) ON DELETE CASCADE, " +
+		"encoding TEXT NOT NULL DEFAULT '', " +
+		"detected TEXT NOT NULL DEFAULT '', " +
+		"confidence SMALLINT NOT NULL DEFAULT 0, " +
+		"stamp TIMESTAMPTZ);"
+	// CreateEmulators is a SQL statement to create the table of the emulator settings of the artifacts.
+	// The settings are the JSON object of the additional DOSBox options chosen by an editor.
+	CreateEmulators SQL = "CREATE TABLE IF NOT EXISTS file_emulators (" +
+		"file_id BIGINT PRIMARY KEY REFERENCES files (id) ON DELETE CASCADE, " +
+		"settings JSONB NOT NULL DEFAULT '{}');"
+)
+
+// Schema returns the statements used to create the supplementary tables and indexes.
+// The statements are returned in the order they must be run.
+func Schema() []SQL {
+	return []SQL{
+		CreateTexts,
+		CreateTextsIdx,
+		CreateChanges,
+		CreateChangesIdx,
+		CreateAudits,
+		CreateAuditsIdx,
+		CreateJobs,
+		CreateJobsIdx,
+		CreateReleasers,
+		CreateAliases,
+		CreateSceners,
+		CreateScenerAliases,
+		CreateScenerCredits,
+		CreateScenerCreditsIdx,
+		CreateScenerMembers,
+		CreateRelations,
+		CreateRelationsIdx,
+		CreateLinks,
+		CreateKeywords,
+		CreateFileKeywords,
+		CreateFileKeywordsIdx,
+		CreateHashes,
+		CreateHashesIdx,
+		CreateMembers,
+		CreateMembersIdx,
+		CreateHits,
+		CreateHitsIdx,
+		CreateSauces,
+		CreateEncodings,
+		CreateEmulators,
+	}
+}
+
+// Create the supplementary tables and indexes when they do not exist.
+func Create(ctx context.Context, db *sql.DB) error {
+	const msg = "postgres create schema"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	for _, stmt := range Schema() {
+		if _, err := db.ExecContext(ctx, string(stmt)); err != nil {
+			return fmt.Errorf("%s: %w", msg, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package postgres_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Defacto2/server/internal/postgres"
+	"github.com/nalgeon/be"
+)
+
+// TestSchema confirms every supplementary statement is safe to run repeatedly.
+func TestSchema(t *testing.T) {
+	stmts := postgres.Schema()
+	be.True(t, len(stmts) > 0)
+	for _, stmt := range stmts {
+		s := string(stmt)
+		be.True(t, strings.Contains(s, "IF NOT EXISTS"))
+		be.True(t, strings.HasSuffix(s, ";"))
+	}
+}
+
+func TestCreate_NilDB(t *testing.T) {
+	err := postgres.Create(t.Context(), nil)
+	be.Err(t, err)
+}
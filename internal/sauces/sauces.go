@@ -0,0 +1,217 @@
+// Package sauces reads the SAUCE metadata of the artifact downloads and of the files
+// contained in their archives, so the records can be stored and searched instead of
+// only being decoded while rendering a page.
+//
+// SAUCE, the Standard Architecture for Universal Comment Extensions, is a 128 byte record
+// with an optional block of comments that is appended to the end of a file. It is mostly
+// found in the ANSI, ASCII and BIN texts created by the art groups of the 1990s.
+package sauces
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/aarondl/null/v8"
+	"github.com/bengarrett/sauce"
+)
+
+// ID is the identifier of a SAUCE record.
+const ID = "SAUCE"
+
+// Data types of the SAUCE records.
+const (
+	None       = 0 // None is a record without a data type.
+	Character  = 1 // Character is the data type of the ASCII, ANSI and other character based texts.
+	Bitmap     = 2 // Bitmap is the data type of the pixel images.
+	Vector     = 3 // Vector is the data type of the vector images.
+	Audio      = 4 // Audio is the data type of the music and sound files.
+	BinaryText = 5 // BinaryText is the data type of the BIN texts.
+	XBin       = 6 // XBin is the data type of the eXtended BIN texts.
+	Archive    = 7 // Archive is the data type of the file archives.
+	Executable = 8 // Executable is the data type of the programs.
+)
+
+// File types of the Character data type that are written texts rather than art.
+const (
+	ascii  = 0 // ascii is a plain text.
+	source = 7 // source is a program source code.
+)
+
+// tail is the maximum number of bytes of a SAUCE record with its comment block of 255 lines.
+const tail = 128 + 5 + 64*255
+
+// Record is the SAUCE metadata of an artifact download or of a file contained in its archive.
+type Record struct {
+	Member   string    `boil:"member"    json:"member"`    // Member is the path of the file within the archive, or empty for the download.
+	Title    string    `boil:"title"     json:"title"`     // Title of the work.
+	Author   string    `boil:"author"    json:"author"`    // Author is the name or handle of the creator.
+	Group    string    `boil:"grp"       json:"group"`     // Group is the name of the group or company of the creator.
+	Date     null.Time `boil:"date"      json:"date"`      // Date is the creation date, or null when it is missing or invalid.
+	DataType int16     `boil:"data_type" json:"data_type"` // DataType is the SAUCE data type value.
+	FileType int16     `boil:"file_type" json:"file_type"` // FileType is the SAUCE file type value of the data type.
+	TypeName string    `boil:"type_name" json:"type_name"` // TypeName is the description of the file type.
+	Width    int       `boil:"width"     json:"width"`     // Width in characters of a text, or zero when unknown.
+	Lines    int       `boil:"lines"     json:"lines"`     // Lines is the number of lines of a text, or zero when unknown.
+	Flags    int16     `boil:"flags"     json:"flags"`     // Flags are the iCE colors, letter spacing and aspect ratio bits.
+	Font     string    `boil:"font"      json:"font"`      // Font is the name of the font used by a text.
+	Comments string    `boil:"comments"  json:"comments"`  // Comments are the lines of the comment block joined with newlines.
+}
+
+// New returns the record of the decoded SAUCE metadata.
+// It returns false when the metadata is not a SAUCE record.
+func New(rec sauce.Record) (Record, bool) {
+	if rec.ID != ID {
+		return Record{}, false
+	}
+	r := Record{
+		Title:    strings.TrimSpace(rec.Title),
+		Author:   strings.TrimSpace(rec.Author),
+		Group:    strings.TrimSpace(rec.Group),
+		DataType: int16(rec.Data.Type), //nolint:gosec
+		FileType: int16(rec.File.Type), //nolint:gosec
+		TypeName: strings.TrimSpace(rec.File.Name),
+		Flags:    int16(rec.Info.Flags.Decimal), //nolint:gosec
+		Font:     strings.TrimSpace(rec.Info.Font),
+	}
+	if t := rec.Date.Time; valid(t) {
+		r.Date = null.TimeFrom(t)
+	}
+	switch r.DataType {
+	case Character, XBin:
+		r.Width = int(rec.Info.Info1.Value)
+		r.Lines = int(rec.Info.Info2.Value)
+	case BinaryText:
+		// the file type of a binary text is half of its width
+		r.Width = int(r.FileType) * 2
+	}
+	comments := make([]string, 0, len(rec.Comnt.Comment))
+	for _, line := range rec.Comnt.Comment {
+		comments = append(comments, strings.TrimRight(line, " \x00"))
+	}
+	r.Comments = strings.TrimSpace(strings.Join(comments, "\n"))
+	return r, true
+}
+
+// valid returns true if the time is a plausible creation date,
+// as the unused dates are often stored as zeros or spaces.
+func valid(t time.Time) bool {
+	const epoch = 1980
+	return t.Year() >= epoch && !t.After(time.Now())
+}
+
+// Decode returns the record of the SAUCE metadata at the end of the bytes.
+// It returns false when the bytes have no SAUCE metadata.
+func Decode(b []byte) (Record, bool) {
+	if !sauce.Contains(b) {
+		return Record{}, false
+	}
+	return New(sauce.Decode(b))
+}
+
+// File returns the record of the SAUCE metadata of the named file.
+// Only the end of the file is read, and false is returned when the file has no SAUCE metadata.
+func File(name string) (Record, bool, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return Record{}, false, fmt.Errorf("sauces file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	st, err := f.Stat()
+	if err != nil {
+		return Record{}, false, fmt.Errorf("sauces file: %w", err)
+	}
+	offset := max(st.Size()-tail, 0)
+	b, err := io.ReadAll(io.NewSectionReader(f, offset, st.Size()-offset))
+	if err != nil {
+		return Record{}, false, fmt.Errorf("sauces file %q: %w", name, err)
+	}
+	rec, ok := Decode(b)
+	return rec, ok, nil
+}
+
+// Dir returns the records of the files in the named directory that have SAUCE metadata,
+// sorted by their member path.
+func Dir(root string) ([]Record, error) {
+	recs := []Record{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rec, ok, err := File(path)
+		if err != nil || !ok {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return fmt.Errorf("sauces path: %w", err)
+		}
+		rec.Member = filepath.ToSlash(rel)
+		recs = append(recs, rec)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("sauces dir: %w", err)
+	}
+	slices.SortFunc(recs, func(a, b Record) int {
+		return strings.Compare(a.Member, b.Member)
+	})
+	return recs, nil
+}
+
+// Writer returns true if the author of the record wrote a text,
+// rather than drew the art or created the other types of data.
+func (r Record) Writer() bool {
+	return r.DataType == Character && (r.FileType == ascii || r.FileType == source)
+}
+
+// Suggestion is the artifact metadata suggested by a SAUCE record.
+type Suggestion struct {
+	Member  string // Member is the path of the archive file of the record, or empty for the download.
+	Title   string // Title of the work.
+	Creator string // Creator is the author of the work.
+	Writer  bool   // Writer is true when the creator is a writer, otherwise they are an artist.
+	Year    int16  // Year of the creation date, or zero when unknown.
+	Month   int16  // Month of the creation date, or zero when unknown.
+	Day     int16  // Day of the creation date, or zero when unknown.
+}
+
+// Suggest returns the artifact metadata suggested by the records, using the record of the download
+// or otherwise the first record of a member with a title, author or date.
+// It returns false when none of the records can be used.
+func Suggest(recs ...Record) (Suggestion, bool) {
+	i := slices.IndexFunc(recs, func(r Record) bool {
+		return r.Member == "" && r.usable()
+	})
+	if i < 0 {
+		i = slices.IndexFunc(recs, Record.usable)
+	}
+	if i < 0 {
+		return Suggestion{}, false
+	}
+	r := recs[i]
+	s := Suggestion{
+		Member:  r.Member,
+		Title:   r.Title,
+		Creator: r.Author,
+		Writer:  r.Writer(),
+	}
+	if r.Date.Valid {
+		t := r.Date.Time
+		s.Year, s.Month, s.Day = int16(t.Year()), int16(t.Month()), int16(t.Day()) //nolint:gosec
+	}
+	return s, true
+}
+
+// usable returns true if the record has a title, author or date.
+func (r Record) usable() bool {
+	return r.Title != "" || r.Author != "" || r.Date.Valid
+}
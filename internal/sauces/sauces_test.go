@@ -0,0 +1,124 @@
+package sauces_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/internal/sauces"
+	"github.com/aarondl/null/v8"
+	"github.com/bengarrett/sauce"
+	"github.com/nalgeon/be"
+)
+
+// field returns the string padded with spaces to the length of a SAUCE field.
+func field(s string, n int) []byte {
+	b := bytes.Repeat([]byte(" "), n)
+	copy(b, s)
+	return b
+}
+
+// record returns the text with an appended SAUCE record of an 80 column ANSI text and its comment.
+func record(text string) []byte {
+	b := []byte(text + "\x1a")
+	b = append(b, "COMNT"...)
+	b = append(b, field("greets to all", 64)...)
+	b = append(b, "SAUCE00"...)
+	b = append(b, field("Mirror", 35)...)
+	b = append(b, field("Lord Jazz", 20)...)
+	b = append(b, field("ACiD Productions", 20)...)
+	b = append(b, "19960512"...)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(text))) //nolint:gosec
+	b = append(b, sauces.Character, 1)
+	b = binary.LittleEndian.AppendUint16(b, 80)
+	b = binary.LittleEndian.AppendUint16(b, 25)
+	b = append(b, 0, 0, 0, 0)
+	b = append(b, 1, 0x01)
+	return append(b, field("IBM VGA", 22)...)
+}
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	_, ok := sauces.New(sauce.Record{})
+	be.True(t, !ok)
+	var rec sauce.Record
+	rec.ID = sauces.ID
+	rec.Title = " Title "
+	rec.Data.Type = sauces.BinaryText
+	rec.File.Type = 80
+	rec.Comnt.Comment = []string{"one  ", "two"}
+	r, ok := sauces.New(rec)
+	be.True(t, ok)
+	be.Equal(t, r.Title, "Title")
+	be.Equal(t, r.Width, 160)
+	be.Equal(t, r.Comments, "one\ntwo")
+	be.True(t, !r.Date.Valid)
+	be.True(t, !r.Writer())
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+	_, ok := sauces.Decode([]byte("no sauce"))
+	be.True(t, !ok)
+	r, ok := sauces.Decode(record("hello"))
+	be.True(t, ok)
+	be.Equal(t, r.Title, "Mirror")
+	be.Equal(t, r.Author, "Lord Jazz")
+	be.Equal(t, r.Group, "ACiD Productions")
+	be.Equal(t, r.Date.Time.Format(time.DateOnly), "1996-05-12")
+	be.Equal(t, r.DataType, int16(sauces.Character))
+	be.Equal(t, r.Width, 80)
+	be.Equal(t, r.Lines, 25)
+	be.Equal(t, r.Flags, int16(1))
+	be.Equal(t, r.Font, "IBM VGA")
+	be.Equal(t, r.Comments, "greets to all")
+}
+
+func TestDir(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	recs, err := sauces.Dir(root)
+	be.Err(t, err, nil)
+	be.Equal(t, len(recs), 0)
+	err = os.MkdirAll(filepath.Join(root, "art"), 0o700)
+	be.Err(t, err, nil)
+	err = os.WriteFile(filepath.Join(root, "art", "mirror.ans"), record("art"), 0o600)
+	be.Err(t, err, nil)
+	err = os.WriteFile(filepath.Join(root, "readme.txt"), []byte("read me"), 0o600)
+	be.Err(t, err, nil)
+	recs, err = sauces.Dir(root)
+	be.Err(t, err, nil)
+	be.Equal(t, len(recs), 1)
+	be.Equal(t, recs[0].Member, "art/mirror.ans")
+
+	_, _, err = sauces.File(filepath.Join(root, "missing"))
+	be.Err(t, err)
+}
+
+func TestSuggest(t *testing.T) {
+	t.Parallel()
+	_, ok := sauces.Suggest()
+	be.True(t, !ok)
+	_, ok = sauces.Suggest(sauces.Record{Member: "blank.ans"})
+	be.True(t, !ok)
+	date := time.Date(1996, 5, 12, 0, 0, 0, 0, time.UTC)
+	recs := []sauces.Record{
+		{Member: "a.txt", Title: "Member", Author: "Writer", DataType: sauces.Character},
+		{Title: "Download", Author: "Artist", Date: null.TimeFrom(date), DataType: sauces.Character, FileType: 1},
+	}
+	s, ok := sauces.Suggest(recs...)
+	be.True(t, ok)
+	be.Equal(t, s.Title, "Download")
+	be.Equal(t, s.Creator, "Artist")
+	be.True(t, !s.Writer)
+	be.Equal(t, s.Year, int16(1996))
+	be.Equal(t, s.Month, int16(5))
+	be.Equal(t, s.Day, int16(12))
+	s, ok = sauces.Suggest(recs[0])
+	be.True(t, ok)
+	be.Equal(t, s.Member, "a.txt")
+	be.True(t, s.Writer)
+}
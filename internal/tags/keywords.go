@@ -0,0 +1,75 @@
+package tags
+
+// Package file keywords.go contains the statistics of the keywords, which are the editor curated
+// secondary tags that supplement the single platform and section tags of an artifact.
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+)
+
+// K is a lockable collection of keywords, to stop potential race conditions
+// when writing to the map containing the tagdata list.
+type K struct {
+	List []TagData
+	Mu   sync.RWMutex
+}
+
+// ByURI returns the data of the keyword slug.
+// It requires the database to be connected to build the keywords if they have not already been.
+func (k *K) ByURI(slug string) (TagData, error) {
+	const format = "keywords by uri %w"
+	k.Mu.RLock()
+	defer k.Mu.RUnlock()
+	if k.List == nil {
+		return TagData{}, fmt.Errorf(format, ErrNoTags)
+	}
+	for val := range slices.Values(k.List) {
+		if val.URI == slug {
+			return val, nil
+		}
+	}
+	return TagData{}, nil
+}
+
+// Build the keywords and collect the statistical data sourced from the database.
+// Unlike the tags, the keywords vocabulary is stored in the database and the count
+// is the number of public artifacts tagged with the keyword.
+func (k *K) Build(ctx context.Context, exec boil.ContextExecutor) error {
+	const msg = "keywords builder"
+	const format = msg + " %s: %w"
+	if InvalidExec(exec) {
+		return fmt.Errorf(format, "", ErrNoDB)
+	}
+	const query = "SELECT k.slug, k.name, k.info, COUNT(f.id) AS count FROM keywords k " +
+		"LEFT JOIN file_keywords fk ON fk.keyword = k.slug " +
+		"LEFT JOIN files f ON f.id = fk.file_id AND f.deletedat IS NULL " +
+		"GROUP BY k.slug ORDER BY k.name"
+	var rows []struct {
+		Slug  string `boil:"slug"`
+		Name  string `boil:"name"`
+		Info  string `boil:"info"`
+		Count int64  `boil:"count"`
+	}
+	if err := queries.Raw(query).Bind(ctx, exec, &rows); err != nil {
+		return fmt.Errorf(format, "counter", err)
+	}
+	list := make([]TagData, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, TagData{
+			URI:   row.Slug,
+			Name:  row.Name,
+			Info:  row.Info,
+			Count: int(row.Count),
+		})
+	}
+	k.Mu.Lock()
+	k.List = list
+	k.Mu.Unlock()
+	return nil
+}
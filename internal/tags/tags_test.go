@@ -290,3 +290,17 @@ func TestPlatformCounts(t *testing.T) {
 	}
 	be.Equal(t, platforms, platformCount)
 }
+
+func TestKBuild(t *testing.T) {
+	t.Parallel()
+	k := tags.K{}
+	err := k.Build(t.Context(), nil)
+	be.Err(t, err)
+	x, err := k.ByURI("sound-blaster")
+	be.Err(t, err)
+	be.Equal(t, x, tags.TagData{})
+	k.List = []tags.TagData{{URI: "sound-blaster", Name: "Sound Blaster", Count: 3}}
+	x, err = k.ByURI("sound-blaster")
+	be.Err(t, err, nil)
+	be.Equal(t, x.Count, 3)
+}
@@ -0,0 +1,408 @@
+package textmode
+
+// Package file ansi.go contains the parser of the ANSI escape sequences and the BBS color codes.
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	"github.com/bengarrett/bbs"
+)
+
+const (
+	tab   = 0x09 // tab is the horizontal tab control.
+	lf    = 0x0a // lf is the line feed control.
+	cr    = 0x0d // cr is the carriage return control.
+	sub   = 0x1a // sub is the MS-DOS end-of-file marker.
+	esc   = 0x1b // esc is the escape control that begins the ANSI sequences.
+	csi   = 0x9b // csi is the single byte control sequence introducer used by the Amiga.
+	tabs  = 8    // tabs is the column interval of the tab stops.
+	seqMx = 32   // seqMx is the maximum length of a control sequence.
+)
+
+// ansiColor is the palette index of the ANSI color values 0 to 7,
+// as the ANSI colors use a different order to the IBM PC attributes.
+var ansiColor = [8]uint8{0, 4, 2, 6, 1, 5, 3, 7} //nolint:gochecknoglobals
+
+// celerity returns the palette index of the Celerity BBS color code.
+func celerity(code byte) (uint8, bool) {
+	const codes = "kbgcrmywdBGCRMYW"
+	i := bytes.IndexByte([]byte(codes), code)
+	if i < 0 {
+		return 0, false
+	}
+	return uint8(i), true //nolint:gosec
+}
+
+// hexNibble returns the value of the hexadecimal digit.
+func hexNibble(c byte) (uint8, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0', true
+	case c >= 'A' && c <= 'F':
+		return c - 'A' + 10, true
+	case c >= 'a' && c <= 'f':
+		return c - 'a' + 10, true
+	}
+	return 0, false
+}
+
+// ANSI renders the text that uses ANSI escape sequences or BBS color codes,
+// which also works for plain texts. Any SAUCE metadata is used and then removed.
+func ANSI(b []byte, opts Options) (*Screen, error) {
+	data, rec := Split(b)
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, ErrEmpty
+	}
+	p := parser{
+		s:       newScreen(opts, rec, Columns),
+		maxRows: opts.MaxRows,
+		amiga:   opts.Amiga,
+		fixed:   opts.Width > 0 || sauceWidth(rec) > 0,
+		codes:   bbs.Find(bytes.NewReader(data)),
+	}
+	p.reset()
+	p.parse(data)
+	if p.s.Height() == 0 {
+		return nil, fmt.Errorf("ansi: %w", ErrEmpty)
+	}
+	return p.s, nil
+}
+
+// parser is the state of the ANSI parser.
+type parser struct {
+	s       *Screen
+	x, y    int     // x and y are the cursor column and row.
+	saveX   int     // saveX is the saved cursor column.
+	saveY   int     // saveY is the saved cursor row.
+	fore    uint8   // fore is the foreground palette index.
+	back    uint8   // back is the background palette index.
+	bold    bool    // bold uses the bright foreground colors.
+	blink   bool    // blink uses the bright background colors with iCE colors.
+	inverse bool    // inverse swaps the foreground and background colors.
+	swap    bool    // swap applies the Celerity color codes to the background.
+	amiga   bool    // amiga uses the single byte control sequence introducer.
+	fixed   bool    // fixed is true when the width cannot be changed by the text.
+	maxRows int     // maxRows is the maximum number of rows to keep.
+	codes   bbs.BBS // codes is the BBS color code format of the text.
+}
+
+// reset the colors to the defaults.
+func (p *parser) reset() {
+	const lightGray = 7
+	p.fore, p.back = lightGray, 0
+	p.bold, p.blink, p.inverse = false, false, false
+}
+
+// cell returns the cell using the current colors.
+func (p *parser) cell(char byte) Cell {
+	c := Cell{Char: uint16(char), Fore: p.fore, Back: p.back}
+	if p.bold {
+		c.Fore |= 8
+	}
+	if p.blink {
+		if p.s.ICE {
+			c.Back |= 8
+		} else {
+			c.Blink = true
+		}
+	}
+	if p.inverse {
+		c.Fore, c.Back = c.Back, c.Fore
+		if !p.s.ICE {
+			c.Back &= 0x07
+		}
+	}
+	return c
+}
+
+// put displays the character at the cursor and then moves the cursor forward.
+func (p *parser) put(char byte) {
+	if row, ok := p.s.row(p.y, p.maxRows); ok && p.x >= 0 && p.x < len(row) {
+		row[p.x] = p.cell(char)
+	}
+	p.x++
+	if p.x >= p.s.Width {
+		p.x = 0
+		p.y++
+	}
+}
+
+// erase blanks the cells of the row from the first to the last columns, using the background color.
+func (p *parser) erase(y, first, last int) {
+	if y < 0 || y >= len(p.s.Rows) || p.s.Rows[y] == nil {
+		return
+	}
+	row := p.s.Rows[y]
+	c := p.cell(' ')
+	c.Fore = blank().Fore
+	for x := max(first, 0); x <= last && x < len(row); x++ {
+		row[x] = c
+	}
+}
+
+// clear the screen and move the cursor home.
+func (p *parser) clear() {
+	p.s.Rows = nil
+	p.x, p.y = 0, 0
+}
+
+func (p *parser) parse(b []byte) { //nolint:cyclop
+	for i := 0; i < len(b); i++ {
+		c := b[i]
+		switch {
+		case c == sub:
+			return
+		case c == cr:
+			p.x = 0
+		case c == lf:
+			p.x = 0
+			p.y++
+		case c == tab:
+			p.x = min((p.x/tabs+1)*tabs, p.s.Width-1)
+		case c == esc && i+1 < len(b) && b[i+1] == '[':
+			i += 1 + p.sequence(b[i+2:])
+		case c == esc:
+			continue
+		case c == csi && p.amiga:
+			i += p.sequence(b[i+1:])
+		case c == '@' && (p.codes == bbs.PCBoard || p.codes == bbs.Wildcat):
+			n := p.atCode(b[i:])
+			if n == 0 {
+				p.put(c)
+				continue
+			}
+			i += n - 1
+		case c == '|' && p.codes == bbs.Celerity && i+1 < len(b):
+			if !p.celerity(b[i+1]) {
+				p.put(c)
+				continue
+			}
+			i++
+		default:
+			p.put(c)
+		}
+		if p.y >= MaxHeight || (p.maxRows > 0 && p.y >= p.maxRows) {
+			return
+		}
+	}
+}
+
+// atCode applies the PCBoard @X or the Wildcat! @@ color code or the @CLS@ clear screen
+// at the start of the bytes and returns the number of bytes used, or zero if there is no code.
+func (p *parser) atCode(b []byte) int {
+	const cls = "@CLS@"
+	if bytes.HasPrefix(b, []byte(cls)) {
+		p.clear()
+		return len(cls)
+	}
+	const size = 4
+	if len(b) < size {
+		return 0
+	}
+	var hi, lo byte
+	switch p.codes { //nolint:exhaustive
+	case bbs.PCBoard:
+		if b[1] != 'X' && b[1] != 'x' {
+			return 0
+		}
+		hi, lo = b[2], b[3]
+	case bbs.Wildcat:
+		if b[3] != '@' {
+			return 0
+		}
+		hi, lo = b[1], b[2]
+	default:
+		return 0
+	}
+	bg, ok1 := hexNibble(hi)
+	fg, ok2 := hexNibble(lo)
+	if !ok1 || !ok2 {
+		return 0
+	}
+	p.reset()
+	p.fore, p.back = fg, bg&0x07
+	p.blink = bg&0x08 != 0
+	return size
+}
+
+// celerity applies the Celerity color code and returns false if the code is unknown.
+func (p *parser) celerity(code byte) bool {
+	if code == 'S' {
+		p.swap = !p.swap
+		return true
+	}
+	n, ok := celerity(code)
+	if !ok {
+		return false
+	}
+	p.bold = false
+	if p.swap {
+		p.back, p.blink = n&0x07, n&0x08 != 0
+		return true
+	}
+	p.fore = n
+	return true
+}
+
+// sequence applies the control sequence that follows the introducer
+// and returns the number of bytes used.
+func (p *parser) sequence(b []byte) int {
+	private := false
+	for i := 0; i < len(b) && i < seqMx; i++ {
+		c := b[i]
+		switch {
+		case c == '?' || c == '=' || c == '>':
+			private = true
+		case c >= '0' && c <= '9', c == ';':
+		case c >= 0x20 && c <= 0x2f:
+			// intermediate bytes, such as the space of the Amiga "ESC[ p" sequence
+		case c >= 0x40 && c <= 0x7e:
+			p.control(c, params(b[:i]), private)
+			return i + 1
+		default:
+			return i
+		}
+	}
+	return 0
+}
+
+// params returns the numeric parameters of a control sequence,
+// with any missing values returned as -1.
+func params(b []byte) []int {
+	b = bytes.TrimLeft(b, "?=>")
+	if i := bytes.IndexFunc(b, func(r rune) bool { return r >= 0x20 && r <= 0x2f }); i >= 0 {
+		b = b[:i]
+	}
+	if len(b) == 0 {
+		return nil
+	}
+	fields := bytes.Split(b, []byte(";"))
+	vals := make([]int, len(fields))
+	for i, f := range fields {
+		n, err := strconv.Atoi(string(f))
+		if err != nil {
+			n = -1
+		}
+		vals[i] = n
+	}
+	return vals
+}
+
+// param returns the value of the parameter at the index or the default value.
+func param(vals []int, index, def int) int {
+	if index >= len(vals) || vals[index] < 0 {
+		return def
+	}
+	return vals[index]
+}
+
+// control applies the final byte of a control sequence.
+func (p *parser) control(final byte, vals []int, private bool) { //nolint:cyclop,funlen
+	if private {
+		p.mode(final, vals)
+		return
+	}
+	// the counts are clamped so a hostile value cannot overflow the cursor position
+	n := min(max(param(vals, 0, 1), 1), MaxHeight)
+	switch final {
+	case 'A': // cursor up
+		p.y = max(p.y-n, 0)
+	case 'B': // cursor down
+		p.y += n
+	case 'C': // cursor forward
+		p.x = min(p.x+n, p.s.Width-1)
+	case 'D': // cursor back
+		p.x = max(p.x-n, 0)
+	case 'E': // cursor next line
+		p.x, p.y = 0, p.y+n
+	case 'F': // cursor previous line
+		p.x, p.y = 0, max(p.y-n, 0)
+	case 'G': // cursor horizontal absolute
+		p.x = min(n-1, p.s.Width-1)
+	case 'H', 'f': // cursor position
+		p.y = min(max(param(vals, 0, 1), 1), MaxHeight) - 1
+		p.x = min(max(param(vals, 1, 1), 1), p.s.Width) - 1
+	case 'J': // erase in display
+		switch param(vals, 0, 0) {
+		case 0:
+			p.erase(p.y, p.x, p.s.Width-1)
+			if p.y+1 < len(p.s.Rows) {
+				p.s.Rows = p.s.Rows[:p.y+1]
+			}
+		case 1:
+			for y := range p.y {
+				p.erase(y, 0, p.s.Width-1)
+			}
+			p.erase(p.y, 0, p.x)
+		default:
+			p.clear()
+		}
+	case 'K': // erase in line
+		switch param(vals, 0, 0) {
+		case 0:
+			p.erase(p.y, p.x, p.s.Width-1)
+		case 1:
+			p.erase(p.y, 0, p.x)
+		default:
+			p.erase(p.y, 0, p.s.Width-1)
+		}
+	case 'm': // select graphic rendition
+		p.sgr(vals)
+	case 's': // save cursor position
+		p.saveX, p.saveY = p.x, p.y
+	case 'u': // restore cursor position
+		p.x, p.y = p.saveX, p.saveY
+	}
+}
+
+// mode applies the private mode sequences, of which only the 132 column mode is used.
+func (p *parser) mode(final byte, vals []int) {
+	const columns132 = 3
+	if final != 'h' || param(vals, 0, 0) != columns132 || p.fixed {
+		return
+	}
+	const wide = 132
+	p.s.Width = wide
+	p.clear()
+}
+
+// sgr applies the select graphic rendition parameters.
+func (p *parser) sgr(vals []int) { //nolint:cyclop
+	if len(vals) == 0 {
+		p.reset()
+		return
+	}
+	for _, v := range vals {
+		switch {
+		case v <= 0:
+			p.reset()
+		case v == 1:
+			p.bold = true
+		case v == 2 || v == 22:
+			p.bold = false
+		case v == 5 || v == 6:
+			p.blink = true
+		case v == 25:
+			p.blink = false
+		case v == 7:
+			p.inverse = true
+		case v == 27:
+			p.inverse = false
+		case v >= 30 && v <= 37:
+			p.fore = ansiColor[v-30]
+		case v == 39:
+			p.fore = blank().Fore
+		case v >= 40 && v <= 47:
+			p.back = ansiColor[v-40]
+		case v == 49:
+			p.back = 0
+		case v >= 90 && v <= 97:
+			p.fore = ansiColor[v-90] | 0x08
+		case v >= 100 && v <= 107:
+			p.back = ansiColor[v-100]
+			p.blink = true
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package textmode
+
+// Package file bin.go contains the parsers of the BIN and XBin screen buffer formats.
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+)
+
+// XBin header flags.
+const (
+	xbinPalette  = 0x01 // xbinPalette is the flag for an embedded palette.
+	xbinFont     = 0x02 // xbinFont is the flag for an embedded font.
+	xbinCompress = 0x04 // xbinCompress is the flag for the compressed image data.
+	xbinNonBlink = 0x08 // xbinNonBlink is the flag for iCE colors.
+	xbin512      = 0x10 // xbin512 is the flag for a 512 character font.
+)
+
+// XBinID is the magic identifier of the XBin format.
+const XBinID = "XBIN\x1a"
+
+// IsXBin returns true if the bytes begin with the XBin identifier.
+func IsXBin(b []byte) bool {
+	return bytes.HasPrefix(b, []byte(XBinID))
+}
+
+// attribute returns the cell of the character and the IBM PC text mode attribute byte,
+// where the lower nibble is the foreground and the upper nibble is the background.
+func attribute(char uint16, attr byte, ice bool) Cell {
+	c := Cell{Char: char, Fore: attr & 0x0f, Back: attr >> 4}
+	if !ice {
+		c.Blink = c.Back&0x08 != 0
+		c.Back &= 0x07
+	}
+	return c
+}
+
+// Bin renders the BIN text, which is a dump of the character and attribute pairs of the screen buffer.
+// Any SAUCE metadata is used and then removed.
+func Bin(b []byte, opts Options) (*Screen, error) {
+	data, rec := Split(b)
+	if len(data) < 2 {
+		return nil, ErrEmpty
+	}
+	s := newScreen(opts, rec, BinWidth)
+	x, y := 0, 0
+	for i := 0; i+1 < len(data); i += 2 {
+		row, ok := s.row(y, opts.MaxRows)
+		if !ok {
+			break
+		}
+		row[x] = attribute(uint16(data[i]), data[i+1], s.ICE)
+		x++
+		if x >= s.Width {
+			x = 0
+			y++
+		}
+	}
+	return s, nil
+}
+
+// XBin renders the eXtended BIN text, which can include its own font and palette
+// and use compressed screen data. The SAUCE width and font are ignored by this format.
+func XBin(b []byte, opts Options) (*Screen, error) { //nolint:cyclop,funlen
+	const msg = "xbin"
+	data, _ := Split(b)
+	const headerLen = 11
+	if len(data) < headerLen || !IsXBin(data) {
+		return nil, fmt.Errorf("%s: %w", msg, ErrXBin)
+	}
+	width := int(binary.LittleEndian.Uint16(data[5:7]))
+	height := int(binary.LittleEndian.Uint16(data[7:9]))
+	fontSize, flags := int(data[9]), data[10]
+	if width < 1 || width > MaxWidth || height < 1 {
+		return nil, fmt.Errorf("%s %dx%d: %w", msg, width, height, ErrXBin)
+	}
+	data = data[headerLen:]
+	// the width is fixed by the header
+	opts.Width = width
+	s := newScreen(opts, nil, width)
+	s.ICE = s.ICE || flags&xbinNonBlink != 0
+	s.Nine = false
+	if flags&xbinPalette != 0 {
+		const size = 48
+		if len(data) < size {
+			return nil, fmt.Errorf("%s palette: %w", msg, ErrXBin)
+		}
+		pal, err := NewPalette(data[:size])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", msg, err)
+		}
+		s.Palette = pal
+		data = data[size:]
+	}
+	chars := glyphs
+	if flags&xbin512 != 0 {
+		chars *= 2
+	}
+	if flags&xbinFont != 0 {
+		size := fontSize * chars
+		if len(data) < size {
+			return nil, fmt.Errorf("%s font: %w", msg, ErrXBin)
+		}
+		font, err := NewFont("XBin", fontSize, data[:size])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", msg, err)
+		}
+		if opts.Font == nil {
+			s.Font = font
+		}
+		data = data[size:]
+	}
+	pairs := data
+	if flags&xbinCompress != 0 {
+		pairs = uncompress(data, width*min(height, MaxHeight))
+	}
+	x, y := 0, 0
+	for i := 0; i+1 < len(pairs) && y < height; i += 2 {
+		row, ok := s.row(y, opts.MaxRows)
+		if !ok {
+			break
+		}
+		char, attr := uint16(pairs[i]), pairs[i+1]
+		if chars > glyphs {
+			// the bright foreground bit selects the second set of 256 characters
+			char |= uint16(attr&0x08) << 5
+			attr &^= 0x08
+		}
+		row[x] = attribute(char, attr, s.ICE)
+		x++
+		if x >= width {
+			x = 0
+			y++
+		}
+	}
+	if s.Height() == 0 {
+		return nil, fmt.Errorf("%s: %w", msg, ErrEmpty)
+	}
+	return s, nil
+}
+
+// uncompress returns the character and attribute pairs of the XBin run-length compressed data.
+// Each run begins with a byte, where the upper 2 bits are the type of compression
+// and the lower 6 bits are the run length minus one.
+func uncompress(b []byte, cells int) []byte {
+	const (
+		none   = 0 // none is a run of uncompressed pairs.
+		chars  = 1 // chars is a run of a repeated character with unique attributes.
+		attrs  = 2 // attrs is a run of a repeated attribute with unique characters.
+		both   = 3 // both is a run of a repeated character and attribute pair.
+		length = 0x3f
+	)
+	out := make([]byte, 0, 2*cells)
+	for i := 0; i < len(b) && len(out) < 2*cells; {
+		kind, n := b[i]>>6, int(b[i]&length)+1
+		i++
+		switch kind {
+		case none:
+			for ; n > 0 && i+1 < len(b); n-- {
+				out = append(out, b[i], b[i+1])
+				i += 2
+			}
+		case chars:
+			if i >= len(b) {
+				return out
+			}
+			char := b[i]
+			i++
+			for ; n > 0 && i < len(b); n-- {
+				out = append(out, char, b[i])
+				i++
+			}
+		case attrs:
+			if i >= len(b) {
+				return out
+			}
+			attr := b[i]
+			i++
+			for ; n > 0 && i < len(b); n-- {
+				out = append(out, b[i], attr)
+				i++
+			}
+		case both:
+			if i+1 >= len(b) {
+				return out
+			}
+			char, attr := b[i], b[i+1]
+			i += 2
+			for ; n > 0; n-- {
+				out = append(out, char, attr)
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,100 @@
+package textmode
+
+// Package file font.go contains the bitmap fonts used to render the text mode images.
+//
+// The embedded fonts are raw, 8 pixel wide bitmaps of 256 glyphs that were rasterized
+// from the TrueType fonts found in the public/font directory. The IBM fonts are from
+// VileR's Ultimate Oldschool PC Font Pack and the Amiga font is dMG's Topaz Plus.
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+//nolint:gochecknoglobals
+var (
+	//go:embed font/ibm-vga-8x16.f16
+	vga8x16 []byte
+	//go:embed font/ibm-ega-8x8.f08
+	ega8x8 []byte
+	//go:embed font/topaz-plus-8x16.f16
+	topaz8x16 []byte
+)
+
+const (
+	glyphWidth = 8   // glyphWidth is the pixel width of all the bitmap glyphs.
+	glyphs     = 256 // glyphs is the number of glyphs in a standard font.
+	maxHeight  = 32  // maxHeight is the largest pixel height of a glyph.
+)
+
+// Font is a bitmap font of 8 pixel wide glyphs, where each byte is a row of pixels
+// with the most significant bit being the leftmost pixel.
+type Font struct {
+	Name   string // Name of the font.
+	Height int    // Height of the glyphs in pixels.
+	Glyphs []byte // Glyphs is the bitmap data, using Height bytes for each of the 256 or 512 glyphs.
+	Latin1 bool   // Latin1 is true when the glyphs use ISO-8859-1 instead of the IBM Code Page 437.
+}
+
+// NewFont returns a font of the glyphs bitmap data, which must contain 256 or 512 glyphs
+// of a pixel height between 1 and 32.
+func NewFont(name string, height int, b []byte) (*Font, error) {
+	if height < 1 || height > maxHeight {
+		return nil, fmt.Errorf("new font %q height %d: %w", name, height, ErrFont)
+	}
+	if n := len(b); n != glyphs*height && n != 2*glyphs*height {
+		return nil, fmt.Errorf("new font %q of %d bytes: %w", name, n, ErrFont)
+	}
+	return &Font{Name: name, Height: height, Glyphs: b}, nil
+}
+
+// VGA returns the IBM VGA 8x16 font that was used by MS-DOS in 80x25 text mode.
+func VGA() *Font {
+	const height = 16
+	return &Font{Name: "IBM VGA", Height: height, Glyphs: vga8x16}
+}
+
+// VGA50 returns the IBM EGA and VGA 8x8 font that was used by MS-DOS in 80x43 and 80x50 text modes.
+func VGA50() *Font {
+	const height = 8
+	return &Font{Name: "IBM VGA50", Height: height, Glyphs: ega8x8}
+}
+
+// Topaz returns the Commodore Amiga Topaz 8x16 font, that uses the ISO-8859-1 character set.
+func Topaz() *Font {
+	const height = 16
+	return &Font{Name: "Amiga Topaz 2+", Height: height, Glyphs: topaz8x16, Latin1: true}
+}
+
+// FontByName returns the font that best matches the SAUCE font name,
+// such as "IBM VGA", "IBM VGA50" or "Amiga Topaz 1+".
+// Unknown names return the VGA font.
+func FontByName(name string) *Font {
+	s := strings.ToLower(strings.TrimSpace(name))
+	switch {
+	case strings.HasPrefix(s, "amiga"):
+		return Topaz()
+	case strings.HasSuffix(s, "43"), strings.HasSuffix(s, "50"):
+		return VGA50()
+	}
+	return VGA()
+}
+
+// Len returns the number of glyphs in the font.
+func (f *Font) Len() int {
+	if f == nil || f.Height < 1 {
+		return 0
+	}
+	return len(f.Glyphs) / f.Height
+}
+
+// glyph returns the bitmap rows of the character,
+// with characters that are out of range using the first glyph.
+func (f *Font) glyph(char uint16) []byte {
+	i := int(char)
+	if i >= f.Len() {
+		i = 0
+	}
+	return f.Glyphs[i*f.Height : (i+1)*f.Height]
+}
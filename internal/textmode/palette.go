@@ -0,0 +1,81 @@
+package textmode
+
+// Package file palette.go contains the 16 color palettes used by the text modes.
+
+import (
+	"fmt"
+	"image/color"
+)
+
+// Palette is the 16 colors of a text mode using the IBM PC attribute order,
+// of black, blue, green, cyan, red, magenta, brown and light gray,
+// followed by the bright variants of the colors.
+type Palette [16]color.RGBA
+
+// CGA returns the IBM Color Graphics Adapter palette that is also used by EGA and VGA text modes.
+func CGA() Palette {
+	return Palette{
+		rgb(0x000000), rgb(0x0000aa), rgb(0x00aa00), rgb(0x00aaaa),
+		rgb(0xaa0000), rgb(0xaa00aa), rgb(0xaa5500), rgb(0xaaaaaa),
+		rgb(0x555555), rgb(0x5555ff), rgb(0x55ff55), rgb(0x55ffff),
+		rgb(0xff5555), rgb(0xff55ff), rgb(0xffff55), rgb(0xffffff),
+	}
+}
+
+// RevisedCGA returns the CGA palette as measured on the revised IBM 5153 Color Display,
+// which has darker normal colors and the tinted bright colors seen by the early PC users.
+func RevisedCGA() Palette {
+	return Palette{
+		rgb(0x000000), rgb(0x0000c4), rgb(0x00c400), rgb(0x00c4c4),
+		rgb(0xc40000), rgb(0xc400c4), rgb(0xc47e00), rgb(0xc4c4c4),
+		rgb(0x4e4e4e), rgb(0x4e4edc), rgb(0x4edc4e), rgb(0x4ef3f3),
+		rgb(0xdc4e4e), rgb(0xf34ef3), rgb(0xf3f34e), rgb(0xffffff),
+	}
+}
+
+// Amiga returns the Deluxe Paint II palette that mimics the CGA colors on the Commodore Amiga.
+func Amiga() Palette {
+	return Palette{
+		rgb(0x000000), rgb(0x0000fc), rgb(0x008800), rgb(0x00a8fc),
+		rgb(0xa80000), rgb(0xcc0088), rgb(0xa85420), rgb(0x747474),
+		rgb(0x646464), rgb(0x0074cc), rgb(0x88fc00), rgb(0x00dcdc),
+		rgb(0xec0000), rgb(0xcc00ec), rgb(0xfcec00), rgb(0xfcfcfc),
+	}
+}
+
+// NewPalette returns a palette of 16 colors from the 48 bytes of 6-bit,
+// red, green and blue VGA DAC values that are used by the XBin format.
+func NewPalette(b []byte) (Palette, error) {
+	const size, maxDAC = 48, 63
+	var p Palette
+	if len(b) != size {
+		return p, fmt.Errorf("new palette of %d bytes: %w", len(b), ErrPalette)
+	}
+	dac := func(v byte) uint8 {
+		v = min(v, maxDAC)
+		return v<<2 | v>>4
+	}
+	for i := range p {
+		p[i] = color.RGBA{R: dac(b[i*3]), G: dac(b[i*3+1]), B: dac(b[i*3+2]), A: 0xff}
+	}
+	return p, nil
+}
+
+// Hex returns the CSS hexadecimal value of the palette color index.
+func (p Palette) Hex(i uint8) string {
+	c := p[i&0x0f]
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+// colors returns the palette as a color palette for the use with paletted images.
+func (p Palette) colors() color.Palette {
+	pal := make(color.Palette, len(p))
+	for i, c := range p {
+		pal[i] = c
+	}
+	return pal
+}
+
+func rgb(v uint32) color.RGBA {
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff} //nolint:gosec
+}
@@ -0,0 +1,205 @@
+package textmode
+
+// Package file render.go contains the HTML and PNG image outputs of a screen.
+
+import (
+	"fmt"
+	"html"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+// lowGlyphs are the Unicode runes of the CP437 glyphs that share the values of the ASCII control codes.
+var lowGlyphs = []rune(" ☺☻♥♦♣♠•◘○◙♂♀♪♫☼►◄↕‼¶§▬↨↑↓→←∟↔▲▼") //nolint:gochecknoglobals
+
+// Rune returns the Unicode rune of the character using the character set of the font,
+// or the 8-bit characters using the character set of the charmap when it is set.
+func (s *Screen) Rune(char uint16) rune {
+	const ctrl, del = 0x20, 0x7f
+	b := byte(char) //nolint:gosec
+	if s.Charmap != nil && b > del {
+		if r := s.Charmap.DecodeByte(b); !unicode.IsControl(r) {
+			return r
+		}
+		return ' '
+	}
+	if s.Font != nil && s.Font.Latin1 {
+		if b < ctrl || (b >= del && b < 0xa0) {
+			return ' '
+		}
+		return charmap.ISO8859_1.DecodeByte(b)
+	}
+	switch {
+	case b < ctrl:
+		return lowGlyphs[b]
+	case b == del:
+		return '⌂'
+	}
+	return charmap.CodePage437.DecodeByte(b)
+}
+
+// HTML writes the screen as a div element containing the rows of text,
+// with each run of the same colors wrapped in a span element using a style attribute.
+// The default colors are set by the div, and the trailing blanks of each row are removed.
+func (s *Screen) HTML(w io.Writer) error {
+	const msg = "textmode html"
+	if s == nil {
+		return fmt.Errorf("%s: %w", msg, ErrEmpty)
+	}
+	def := blank()
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<div style="color:%s;background-color:%s;">`,
+		s.Palette.Hex(def.Fore), s.Palette.Hex(def.Back))
+	for y, row := range s.Rows {
+		if y > 0 {
+			sb.WriteByte('\n')
+		}
+		row = trimRow(row, s.ICE)
+		for x := 0; x < len(row); {
+			fore, back := s.colors(row[x])
+			end := x + 1
+			for end < len(row) {
+				f, b := s.colors(row[end])
+				if f != fore || b != back {
+					break
+				}
+				end++
+			}
+			var text strings.Builder
+			for _, c := range row[x:end] {
+				text.WriteRune(s.Rune(c.Char))
+			}
+			sb.WriteString(`<span style="color:` + s.Palette.Hex(fore) + ";")
+			if back != def.Back {
+				sb.WriteString("background-color:" + s.Palette.Hex(back) + ";")
+			}
+			sb.WriteString(`">` + html.EscapeString(text.String()) + "</span>")
+			x = end
+		}
+	}
+	sb.WriteString("</div>")
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return nil
+}
+
+// colors returns the palette indexes of the displayed foreground and background of the cell.
+func (s *Screen) colors(c Cell) (uint8, uint8) {
+	back := c.Back
+	if !s.ICE {
+		back &= 0x07
+	}
+	return c.Fore & 0x0f, back & 0x0f
+}
+
+// trimRow returns the row without the trailing, blank cells that use the default background.
+func trimRow(row []Cell, ice bool) []Cell {
+	const null, space, nbsp = 0x00, 0x20, 0xff
+	for i := len(row) - 1; i >= 0; i-- {
+		c := row[i]
+		back := c.Back
+		if !ice {
+			back &= 0x07
+		}
+		if back != 0 {
+			return row[:i+1]
+		}
+		switch c.Char {
+		case null, space, nbsp:
+			continue
+		}
+		return row[:i+1]
+	}
+	return nil
+}
+
+// CellSize returns the pixel width and height of a character.
+func (s *Screen) CellSize() (int, int) {
+	const nine = 9
+	if s.Nine {
+		return nine, s.Font.Height
+	}
+	return glyphWidth, s.Font.Height
+}
+
+// Image returns the screen as a paletted image using the scale of 1 or 2.
+// The rows that do not fit within the MaxPixels height of the image are not drawn.
+func (s *Screen) Image(scale int) (*image.Paletted, error) {
+	const msg = "textmode image"
+	if s == nil || s.Font == nil || s.Height() == 0 {
+		return nil, fmt.Errorf("%s: %w", msg, ErrEmpty)
+	}
+	if scale != 1 && scale != 2 {
+		return nil, fmt.Errorf("%s %d: %w", msg, scale, ErrScale)
+	}
+	cw, ch := s.CellSize()
+	// the height of a huge text or font would otherwise allocate gigabytes of pixels
+	rows := min(s.Height(), max(MaxPixels/(ch*scale), 1))
+	width := min(s.Width, MaxWidth)
+	rect := image.Rect(0, 0, width*cw*scale, rows*ch*scale)
+	img := image.NewPaletted(rect, s.Palette.colors())
+	const boxFirst, boxLast = 0xc0, 0xdf
+	for y, row := range s.Rows[:rows] {
+		for x := range width {
+			c := blank()
+			if row != nil && x < len(row) {
+				c = row[x]
+			}
+			fore, back := s.colors(c)
+			glyph := s.Font.glyph(c.Char)
+			// the ninth column repeats the eighth column of the box drawing characters
+			box := s.Nine && c.Char >= boxFirst && c.Char <= boxLast
+			for gy, bits := range glyph {
+				for gx := range cw {
+					var on bool
+					switch {
+					case gx < glyphWidth:
+						on = bits&(0x80>>gx) != 0
+					case box:
+						on = bits&0x01 != 0
+					}
+					idx := back
+					if on {
+						idx = fore
+					}
+					px, py := (x*cw+gx)*scale, (y*ch+gy)*scale
+					for sy := range scale {
+						off := img.PixOffset(px, py+sy)
+						for sx := range scale {
+							img.Pix[off+sx] = idx
+						}
+					}
+				}
+			}
+		}
+	}
+	return img, nil
+}
+
+// PNG writes the screen as a PNG image using the scale of 1 or 2.
+func (s *Screen) PNG(w io.Writer, scale int) error {
+	img, err := s.Image(scale)
+	if err != nil {
+		return err
+	}
+	enc := png.Encoder{CompressionLevel: png.BestCompression}
+	if err := enc.Encode(w, img); err != nil {
+		return fmt.Errorf("textmode png: %w", err)
+	}
+	return nil
+}
+
+// Decode renders the text using the XBin format when it has the XBin identifier,
+// otherwise the text is rendered as ANSI.
+func Decode(b []byte, opts Options) (*Screen, error) {
+	if IsXBin(b) {
+		return XBin(b, opts)
+	}
+	return ANSI(b, opts)
+}
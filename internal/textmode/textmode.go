@@ -0,0 +1,202 @@
+// Package textmode renders the text mode art of ANSI, BIN and XBin files as HTML or PNG images.
+//
+// The ANSI parser handles the colors, including iCE colors, and the cursor movements
+// of the escape sequences, as well as the PCBoard, Wildcat! and Celerity BBS color codes.
+// The BIN and XBin formats are the screen buffer dumps of the character and attribute pairs,
+// with XBin also offering embedded fonts and palettes.
+// Any SAUCE metadata is used for the width, the iCE colors, the letter spacing and the font.
+package textmode
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/bengarrett/sauce"
+	"golang.org/x/text/encoding/charmap"
+)
+
+var (
+	ErrEmpty   = errors.New("text is empty")
+	ErrFont    = errors.New("font bitmap is invalid")
+	ErrPalette = errors.New("palette must be 48 bytes of 6-bit values")
+	ErrScale   = errors.New("image scale must be 1 or 2")
+	ErrXBin    = errors.New("xbin header is invalid")
+)
+
+const (
+	Columns   = 80    // Columns is the default width of an ANSI text.
+	BinWidth  = 160   // BinWidth is the default width of a BIN text.
+	MaxWidth  = 1024  // MaxWidth is the maximum width in columns of a text.
+	MaxHeight = 10000 // MaxHeight is the maximum height in rows of a text.
+	MaxPixels = 10000 // MaxPixels is the maximum height in pixels of an image.
+)
+
+// SAUCE data types and flags used by the renderer.
+const (
+	sauceCharacter  = 1    // sauceCharacter is the data type of ASCII and ANSI texts.
+	sauceBinaryText = 5    // sauceBinaryText is the data type of BIN texts.
+	sauceNonBlink   = 0x01 // sauceNonBlink is the flag for iCE colors.
+	sauceSpacing    = 0x06 // sauceSpacing is the mask of the letter spacing flags.
+	sauceNinePixel  = 0x04 // sauceNinePixel is the 9 pixel letter spacing flag value.
+)
+
+// Cell is a character of the screen with its colors.
+type Cell struct {
+	Char  uint16 // Char is the glyph index of the font, which can exceed 255 with 512 character fonts.
+	Fore  uint8  // Fore is the foreground color index of the palette.
+	Back  uint8  // Back is the background color index of the palette.
+	Blink bool   // Blink is true for blinking characters, which is only used without iCE colors.
+}
+
+// blank is the empty cell of a screen.
+func blank() Cell {
+	const space, lightGray = 0x20, 7
+	return Cell{Char: space, Fore: lightGray}
+}
+
+// Options for the rendering of a text.
+type Options struct {
+	// Width is the number of columns of the text.
+	// When zero, the SAUCE width or the default width of the format is used.
+	Width int
+	// MaxRows is the maximum number of rows to keep, when zero all rows are kept.
+	MaxRows int
+	// ICE uses the blink attribute for the bright background colors.
+	// iCE colors are also enabled by the SAUCE non-blink flag.
+	ICE bool
+	// Amiga renders the text using the Topaz font, the Amiga palette and the ISO-8859-1 character set.
+	Amiga bool
+	// Palette replaces the palette of the text,
+	// when nil the CGA palette or the Amiga palette is used.
+	// An XBin text with an embedded palette always uses its own palette.
+	Palette *Palette
+	// Font replaces the font of the text,
+	// when nil the font is chosen by the SAUCE font name.
+	Font *Font
+	// Charmap replaces the character set of the font for the 8-bit characters of the HTML output,
+	// when nil the character set of the font is used. The images always use the glyphs of the font.
+	Charmap *charmap.Charmap
+}
+
+// Screen is the rendered text mode of a text.
+type Screen struct {
+	Rows    [][]Cell         // Rows of cells, where a nil row is blank.
+	Width   int              // Width is the number of columns of the screen.
+	Font    *Font            // Font used to render the screen.
+	Palette Palette          // Palette of 16 colors.
+	ICE     bool             // ICE is true when the blink attribute is used for bright backgrounds.
+	Nine    bool             // Nine is true when the glyphs use a 9 pixel letter spacing.
+	Charmap *charmap.Charmap // Charmap is the character set of the HTML output, or nil to use the font.
+}
+
+// Height returns the number of rows of the screen.
+func (s *Screen) Height() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.Rows)
+}
+
+// row returns the cells of the row y, growing the screen when required.
+// False is returned when the row exceeds the maximum height.
+func (s *Screen) row(y, maxRows int) ([]Cell, bool) {
+	if y < 0 || y >= MaxHeight || (maxRows > 0 && y >= maxRows) {
+		return nil, false
+	}
+	for len(s.Rows) <= y {
+		s.Rows = append(s.Rows, nil)
+	}
+	if s.Rows[y] == nil {
+		cells := make([]Cell, s.Width)
+		for i := range cells {
+			cells[i] = blank()
+		}
+		s.Rows[y] = cells
+	}
+	return s.Rows[y], true
+}
+
+// newScreen returns an empty screen using the options and the SAUCE record.
+// The width is the default width of the format, used when neither the options nor SAUCE set it.
+func newScreen(opts Options, rec *sauce.Record, width int) *Screen {
+	s := &Screen{Palette: CGA(), ICE: opts.ICE, Charmap: opts.Charmap}
+	if w := sauceWidth(rec); w > 0 {
+		width = w
+	}
+	if opts.Width > 0 {
+		width = opts.Width
+	}
+	s.Width = min(max(width, 1), MaxWidth)
+	fontname := ""
+	if rec != nil {
+		flags := uint(rec.Info.Flags.Decimal)
+		if flags&sauceNonBlink != 0 {
+			s.ICE = true
+		}
+		if flags&sauceSpacing == sauceNinePixel {
+			s.Nine = true
+		}
+		fontname = rec.Info.Font
+	}
+	switch {
+	case opts.Font != nil:
+		s.Font = opts.Font
+	case opts.Amiga:
+		s.Font = Topaz()
+	default:
+		s.Font = FontByName(fontname)
+	}
+	if opts.Amiga {
+		s.Palette = Amiga()
+		s.Nine = false
+	}
+	if opts.Palette != nil {
+		s.Palette = *opts.Palette
+	}
+	if s.Font.Latin1 {
+		s.Nine = false
+	}
+	return s
+}
+
+// sauceWidth returns the character width of the SAUCE record, or zero if it is unknown.
+func sauceWidth(rec *sauce.Record) int {
+	if rec == nil {
+		return 0
+	}
+	switch uint(rec.Data.Type) {
+	case sauceCharacter:
+		return int(rec.Info.Info1.Value)
+	case sauceBinaryText:
+		// the file type of a binary text is half of its width
+		return int(rec.File.Type) * 2 //nolint:gosec
+	}
+	return 0
+}
+
+// Split returns the text without any SAUCE metadata and the decoded SAUCE record,
+// which is nil when the text has no SAUCE.
+func Split(b []byte) ([]byte, *sauce.Record) {
+	if !sauce.Contains(b) {
+		return b, nil
+	}
+	rec := sauce.Decode(b)
+	data := b
+	if i := bytes.LastIndex(data, []byte("SAUCE00")); i >= 0 {
+		data = data[:i]
+	}
+	const comntID, comntMax = "COMNT", 5 + 64*255
+	if i := bytes.LastIndex(data, []byte(comntID)); i >= 0 && len(data)-i <= comntMax {
+		data = data[:i]
+	}
+	if size := int(rec.FileSize); size > 0 && size <= len(data) { //nolint:gosec
+		return data[:size], &rec
+	}
+	// the end-of-file marker is only removed from an odd length,
+	// as it could be the attribute of the last character of a binary text
+	const eof = 0x1a
+	if n := len(data); n%2 == 1 && data[n-1] == eof {
+		data = data[:n-1]
+	}
+	return data, &rec
+}
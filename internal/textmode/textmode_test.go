@@ -0,0 +1,293 @@
+package textmode_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/Defacto2/server/internal/textmode"
+	"github.com/nalgeon/be"
+	"golang.org/x/text/encoding/charmap"
+)
+
+func TestANSI(t *testing.T) {
+	t.Parallel()
+	_, err := textmode.ANSI(nil, textmode.Options{})
+	be.True(t, errors.Is(err, textmode.ErrEmpty))
+
+	const src = "\x1b[1;33;44mAB\x1b[0m\r\n\x1b[5Cx\x1b[1Ay\x1b[31;5mz"
+	s, err := textmode.ANSI([]byte(src), textmode.Options{})
+	be.Err(t, err, nil)
+	be.Equal(t, s.Width, textmode.Columns)
+	be.Equal(t, s.Height(), 2)
+	a := s.Rows[0][0]
+	be.Equal(t, a.Char, uint16('A'))
+	be.Equal(t, a.Fore, uint8(14)) // bright yellow
+	be.Equal(t, a.Back, uint8(1))  // blue
+	x := s.Rows[1][5]
+	be.Equal(t, x.Char, uint16('x'))
+	be.Equal(t, x.Fore, uint8(7))
+	y := s.Rows[0][6]
+	be.Equal(t, y.Char, uint16('y'))
+	z := s.Rows[0][7]
+	be.Equal(t, z.Fore, uint8(4)) // red
+	be.True(t, z.Blink)
+
+	// iCE colors use the blink attribute for bright backgrounds
+	s, err = textmode.ANSI([]byte("\x1b[5;42mi"), textmode.Options{ICE: true})
+	be.Err(t, err, nil)
+	be.Equal(t, s.Rows[0][0].Back, uint8(10))
+	be.True(t, !s.Rows[0][0].Blink)
+
+	// text wraps at the width and stops at the end-of-file marker
+	s, err = textmode.ANSI([]byte("abcdef\x1aghi"), textmode.Options{Width: 4, MaxRows: 10})
+	be.Err(t, err, nil)
+	be.Equal(t, s.Height(), 2)
+	be.Equal(t, s.Rows[1][1].Char, uint16('f'))
+
+	// 132 column mode
+	s, err = textmode.ANSI([]byte("\x1b[?3hwide"), textmode.Options{})
+	be.Err(t, err, nil)
+	be.Equal(t, s.Width, 132)
+}
+
+func TestANSIOverflow(t *testing.T) {
+	t.Parallel()
+	// huge cursor movements must not wrap the cursor to a negative position
+	for _, src := range []string{
+		"\x1b[5C\x1b[9223372036854775807Cx",
+		"\x1b[1B\x1b[9223372036854775807B\x1b[K",
+		"x\x1b[9223372036854775807E\x1b[2Ky",
+		"\x1b[9223372036854775807;9223372036854775807Hz",
+		"\x1b[9223372036854775807D\x1b[9223372036854775807A\x1b[1Kq",
+	} {
+		s, err := textmode.ANSI([]byte(src), textmode.Options{})
+		if err != nil {
+			be.True(t, errors.Is(err, textmode.ErrEmpty))
+			continue
+		}
+		be.True(t, s.Height() <= textmode.MaxHeight)
+	}
+	s, err := textmode.ANSI([]byte("\x1b[5C\x1b[9223372036854775807Cx"), textmode.Options{})
+	be.Err(t, err, nil)
+	be.Equal(t, s.Rows[0][textmode.Columns-1].Char, uint16('x'))
+}
+
+func TestBBSCodes(t *testing.T) {
+	t.Parallel()
+	s, err := textmode.ANSI([]byte("@X1Fpcb@X07."), textmode.Options{})
+	be.Err(t, err, nil)
+	c := s.Rows[0][0]
+	be.Equal(t, c.Char, uint16('p'))
+	be.Equal(t, c.Fore, uint8(15))
+	be.Equal(t, c.Back, uint8(1))
+	be.Equal(t, s.Rows[0][3].Char, uint16('.'))
+
+	s, err = textmode.ANSI([]byte("@4E@wildcat"), textmode.Options{})
+	be.Err(t, err, nil)
+	c = s.Rows[0][0]
+	be.Equal(t, c.Char, uint16('w'))
+	be.Equal(t, c.Fore, uint8(14))
+	be.Equal(t, c.Back, uint8(4))
+
+	s, err = textmode.ANSI([]byte("|Y|S|bcel"), textmode.Options{})
+	be.Err(t, err, nil)
+	c = s.Rows[0][0]
+	be.Equal(t, c.Char, uint16('c'))
+	be.Equal(t, c.Fore, uint8(14))
+	be.Equal(t, c.Back, uint8(1))
+}
+
+func TestBin(t *testing.T) {
+	t.Parallel()
+	_, err := textmode.Bin([]byte{'a'}, textmode.Options{})
+	be.True(t, errors.Is(err, textmode.ErrEmpty))
+
+	src := []byte{'a', 0x1f, 'b', 0x9c, 'c', 0x07}
+	s, err := textmode.Bin(src, textmode.Options{Width: 2})
+	be.Err(t, err, nil)
+	be.Equal(t, s.Height(), 2)
+	be.Equal(t, s.Rows[0][0].Fore, uint8(15))
+	be.Equal(t, s.Rows[0][0].Back, uint8(1))
+	be.True(t, s.Rows[0][1].Blink)
+	be.Equal(t, s.Rows[0][1].Back, uint8(1))
+	be.Equal(t, s.Rows[1][0].Char, uint16('c'))
+
+	s, err = textmode.Bin(src, textmode.Options{Width: 2, ICE: true})
+	be.Err(t, err, nil)
+	be.Equal(t, s.Rows[0][1].Back, uint8(9))
+
+	// the early BIN texts use the revised CGA palette
+	pal := textmode.RevisedCGA()
+	s, err = textmode.Bin([]byte{' ', 0x60}, textmode.Options{Width: 80, MaxRows: 25, Palette: &pal})
+	be.Err(t, err, nil)
+	img, err := s.Image(1)
+	be.Err(t, err, nil)
+	be.Equal(t, img.At(0, 0), color.Color(color.RGBA{R: 0xc4, G: 0x7e, B: 0x00, A: 0xff})) // brown background
+	be.Equal(t, s.Palette.Hex(6), "#c47e00")
+}
+
+func xbin(width, height, fontSize int, flags byte, data ...byte) []byte {
+	b := []byte(textmode.XBinID)
+	b = binary.LittleEndian.AppendUint16(b, uint16(width))
+	b = binary.LittleEndian.AppendUint16(b, uint16(height))
+	b = append(b, byte(fontSize), flags)
+	return append(b, data...)
+}
+
+func TestXBin(t *testing.T) {
+	t.Parallel()
+	_, err := textmode.XBin([]byte("XBIN"), textmode.Options{})
+	be.True(t, errors.Is(err, textmode.ErrXBin))
+
+	// a palette with compressed data of a repeated pair and a run of unique characters
+	pal := make([]byte, 48)
+	pal[3], pal[4], pal[5] = 63, 0, 0 // color 1 is bright red
+	data := append(pal, 0xc1, 'x', 0x1f, 0x81, 0x07, 'y', 'z')
+	s, err := textmode.XBin(xbin(2, 2, 16, 0x01|0x04|0x08, data...), textmode.Options{})
+	be.Err(t, err, nil)
+	be.True(t, s.ICE)
+	be.Equal(t, s.Width, 2)
+	be.Equal(t, s.Height(), 2)
+	be.Equal(t, s.Rows[0][1].Char, uint16('x'))
+	be.Equal(t, s.Rows[1][1].Char, uint16('z'))
+	be.Equal(t, s.Rows[1][1].Fore, uint8(7))
+	be.Equal(t, s.Palette.Hex(1), "#ff0000")
+
+	// an embedded 8x8 font
+	font := bytes.Repeat([]byte{0xff}, 8*256)
+	s, err = textmode.XBin(xbin(1, 1, 8, 0x02, append(font, 'a', 0x07)...), textmode.Options{})
+	be.Err(t, err, nil)
+	be.Equal(t, s.Font.Height, 8)
+	w, h := s.CellSize()
+	be.Equal(t, w, 8)
+	be.Equal(t, h, 8)
+}
+
+func TestImage(t *testing.T) {
+	t.Parallel()
+	s, err := textmode.ANSI([]byte("\x1b[44mhi\r\nthere"), textmode.Options{Width: 10})
+	be.Err(t, err, nil)
+	_, err = s.Image(3)
+	be.True(t, errors.Is(err, textmode.ErrScale))
+	img, err := s.Image(1)
+	be.Err(t, err, nil)
+	be.Equal(t, img.Bounds().Dx(), 80)
+	be.Equal(t, img.Bounds().Dy(), 32)
+	be.Equal(t, img.ColorIndexAt(0, 0), uint8(1)) // blue background
+
+	var buf bytes.Buffer
+	be.Err(t, s.PNG(&buf, 2), nil)
+	cfg, err := png.DecodeConfig(&buf)
+	be.Err(t, err, nil)
+	be.Equal(t, cfg.Width, 160)
+	be.Equal(t, cfg.Height, 64)
+
+	s.Nine = true
+	img, err = s.Image(1)
+	be.Err(t, err, nil)
+	be.Equal(t, img.Bounds().Dx(), 90)
+
+	s, err = textmode.ANSI([]byte("amiga"), textmode.Options{Amiga: true})
+	be.Err(t, err, nil)
+	be.True(t, s.Font.Latin1)
+	be.Equal(t, s.Palette, textmode.Amiga())
+}
+
+func TestImageOversize(t *testing.T) {
+	t.Parallel()
+	// an xbin of the maximum width with a huge height and a tall 8x32 font
+	const width, rows = textmode.MaxWidth, 200
+	data := bytes.Repeat([]byte{0xff}, 32*256)
+	data = append(data, bytes.Repeat([]byte{0xff, 'x', 0x07}, width*rows/64)...)
+	s, err := textmode.XBin(xbin(width, 0xffff, 32, 0x02|0x04, data...), textmode.Options{})
+	be.Err(t, err, nil)
+	be.Equal(t, s.Height(), rows)
+	img, err := s.Image(2)
+	be.Err(t, err, nil)
+	be.Equal(t, img.Bounds().Dx(), width*8*2)
+	be.True(t, img.Bounds().Dy() <= textmode.MaxPixels)
+	be.Equal(t, img.Bounds().Dy(), textmode.MaxPixels/64*64)
+}
+
+func TestHTML(t *testing.T) {
+	t.Parallel()
+	s, err := textmode.ANSI([]byte("\x1b[1;31m<\xdb>\x1b[0m   \r\nok"), textmode.Options{})
+	be.Err(t, err, nil)
+	var sb strings.Builder
+	be.Err(t, s.HTML(&sb), nil)
+	const want = `<div style="color:#aaaaaa;background-color:#000000;">` +
+		`<span style="color:#ff5555;">&lt;█&gt;</span>` + "\n" +
+		`<span style="color:#aaaaaa;">ok</span></div>`
+	be.Equal(t, sb.String(), want)
+}
+
+func TestFontByName(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, textmode.FontByName("").Name, textmode.VGA().Name)
+	be.Equal(t, textmode.FontByName("IBM VGA50").Height, 8)
+	be.True(t, textmode.FontByName("Amiga Topaz 1+").Latin1)
+	be.Equal(t, textmode.VGA().Len(), 256)
+	_, err := textmode.NewFont("bad", 16, make([]byte, 100))
+	be.True(t, errors.Is(err, textmode.ErrFont))
+}
+
+func TestCharmap(t *testing.T) {
+	t.Parallel()
+	s, err := textmode.ANSI([]byte{0xaf, 0xe0, 0xa8, 0xc4}, textmode.Options{Charmap: charmap.CodePage866})
+	be.Err(t, err, nil)
+	be.Equal(t, s.Rune(s.Rows[0][0].Char), 'п')
+	be.Equal(t, s.Rune(s.Rows[0][3].Char), '─')
+	be.Equal(t, s.Rune('a'), 'a')
+}
+
+// go test -fuzz=FuzzANSI -fuzztime=30s
+//
+
+func FuzzANSI(f *testing.F) {
+	f.Add([]byte("\x1b[1;33;44mAB\x1b[0m\r\n\x1b[5Cx\x1b[1Ay"), 0, false)
+	f.Add([]byte("\x1b[5C\x1b[9223372036854775807Cx"), 0, false)
+	f.Add([]byte("\x1b[1B\x1b[9223372036854775807B\x1b[K"), 0, false)
+	f.Add([]byte("\x1b[?3h@X1Fpcb|Y|S|bcel\x9b1m"), 4, true)
+
+	f.Fuzz(func(t *testing.T, b []byte, width int, amiga bool) {
+		s, err := textmode.ANSI(b, textmode.Options{Width: width, MaxRows: 100, Amiga: amiga})
+		if err != nil {
+			return
+		}
+		if s.Height() > 100 {
+			t.Errorf("ANSI kept %d rows, more than the maximum", s.Height())
+		}
+		var sb strings.Builder
+		if err := s.HTML(&sb); err != nil {
+			t.Errorf("HTML failed: %v", err)
+		}
+	})
+}
+
+// go test -fuzz=FuzzXBin -fuzztime=30s
+//
+
+func FuzzXBin(f *testing.F) {
+	f.Add(xbin(2, 2, 16, 0x04|0x08, 0xc1, 'x', 0x1f, 0x81, 0x07, 'y', 'z'))
+	f.Add(xbin(1, 1, 8, 0x02|0x10, append(bytes.Repeat([]byte{0xff}, 8*512), 'a', 0x0f)...))
+	f.Add(xbin(1024, 65535, 0, 0x04, 0xff, 0xff, 0xff))
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		s, err := textmode.XBin(b, textmode.Options{MaxRows: 100})
+		if err != nil {
+			return
+		}
+		if s.Height() > 100 {
+			t.Errorf("XBin kept %d rows, more than the maximum", s.Height())
+		}
+		var sb strings.Builder
+		if err := s.HTML(&sb); err != nil {
+			t.Errorf("HTML failed: %v", err)
+		}
+	})
+}
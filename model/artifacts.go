@@ -146,8 +146,16 @@ func (f *Artifacts) ByMagicErr(ctx context.Context, exec boil.ContextExecutor, b
 		ORDER BY "file_magic_type"
 		LIMIT 500;
 	*/
-	equals := []string{"data", "tar archive", "Microsoft ASF"}
-	ilikes := []string{
+	return models.Files(
+		qm.Select(models.FileColumns.UUID, models.FileColumns.ID, models.FileColumns.FileMagicType),
+		MagicErrExpr(binaryData),
+		qm.WithDeleted(),
+	).All(ctx, exec)
+}
+
+// magicErrLikes returns the case-insensitive patterns of the generic or outdated magic numbers.
+func magicErrLikes() []string {
+	return []string{
 		"application/%", "Zip archive data%", "ARC archive data%", "ARJ archive data%", "RAR archive data%",
 		"7-zip archive data%", "gzip compressed data%", "ASCII text%", "HTML document%", "Pascal source%", "ISO-8859 text%",
 		"JPEG image data%", "GIF image data%", "PNG image data%", "PDF document%", "RIFF (little-endian) data%",
@@ -161,8 +169,14 @@ func (f *Artifacts) ByMagicErr(ctx context.Context, exec boil.ContextExecutor, b
 		"Rich Text Format data%", "SMTP mail%", "SysEx File%", "UTF-8 Unicode%", "core file (Xenix)%", "diff output,%",
 		"news or mail,%", "news, ASCII text%", "saved news,%", "ID tags data%", "VISX image file%",
 	}
+}
+
+// MagicErrExpr returns the query expression of the file records that require new magic numbers.
+// When binaryData is true, the records with a generic "Binary data" magic number are also matched.
+func MagicErrExpr(binaryData bool) qm.QueryMod {
+	equals := []string{"data", "tar archive", "Microsoft ASF"}
+	ilikes := magicErrLikes()
 	mods := []qm.QueryMod{
-		qm.Select(models.FileColumns.UUID, models.FileColumns.ID, models.FileColumns.FileMagicType),
 		models.FileWhere.FileMagicType.IsNull(),
 	}
 	for s := range slices.Values(equals) {
@@ -177,8 +191,7 @@ func (f *Artifacts) ByMagicErr(ctx context.Context, exec boil.ContextExecutor, b
 		mods = append(mods,
 			qm.Or2(models.FileWhere.FileMagicType.EQ(null.StringFrom("Binary data"))))
 	}
-	mods = append(mods, qm.WithDeleted())
-	return models.Files(mods...).All(ctx, exec)
+	return qm.Expr(mods...)
 }
 
 // ByTextPlatform returns all of the file records that are text based, either text or textamiga.
@@ -215,7 +228,8 @@ func (f *Artifacts) ByUnwanted(ctx context.Context, exec boil.ContextExecutor, o
 }
 
 // Description returns a list of files that match the search terms.
-// The search terms are matched against the record_title column.
+// The search terms are matched against the record_title and comment columns,
+// and against the author and group names of the stored SAUCE metadata.
 // The results are ordered by the filename column in ascending order.
 func (f *Artifacts) Description(ctx context.Context, sl *slog.Logger, exec boil.ContextExecutor, terms []string) (
 	models.FileSlice, error,
@@ -233,14 +247,17 @@ func (f *Artifacts) Description(ctx context.Context, sl *slog.Logger, exec boil.
 	const clauseT = "to_tsvector(record_title) @@ websearch_to_tsquery(?)"
 	const clauseC = "to_tsvector(comment) @@ websearch_to_tsquery(?)"
 	for i, term := range terms {
+		name := "%" + term + "%"
 		term = fmt.Sprintf("'%s'", term) // the single quotes are required for terms containing spaces
 		if i == 0 {
 			mods = append(mods, qm.Where(clauseT, term))
 			mods = append(mods, qm.Or(clauseC, term))
+			mods = append(mods, qm.Or(ClauseSauce, name, name))
 			continue
 		}
 		mods = append(mods, qm.Or(clauseT, term))
 		mods = append(mods, qm.Or(clauseC, term))
+		mods = append(mods, qm.Or(ClauseSauce, name, name))
 	}
 	mods = append(mods, qm.Limit(Maximum))
 	sl.Debug(msg,
@@ -0,0 +1,387 @@
+package model
+
+// Package file audit.go contains the database queries for the audit trail of the editor changes
+// made to the file records. Every change to a column is logged with the Google account ID of the
+// editor plus the old and new values, so any single change can be reviewed and reverted.
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/null/v8"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+)
+
+var (
+	ErrAsset  = errors.New("asset name is invalid")
+	ErrRevert = errors.New("audit entry cannot be reverted")
+)
+
+// Asset is the name of an artifact asset that is not stored in the file record,
+// such as the preview and thumbnail images or the relations to the other artifacts.
+type Asset string
+
+const (
+	Preview   Asset = "preview"   // Preview is the preview image or photo of the artifact.
+	Thumbnail Asset = "thumbnail" // Thumbnail is the square thumbnail image of the artifact.
+	Related   Asset = "relation"  // Related is a typed relation from the artifact to a target artifact.
+	External  Asset = "link"      // External is a typed external link of the artifact.
+	Record    Asset = "record"    // Record is the file record itself, such as its permanent deletion.
+)
+
+// Valid returns true if the asset is a known name.
+func (a Asset) Valid() bool {
+	switch a {
+	case Preview, Thumbnail, Related, External, Record:
+		return true
+	}
+	return false
+}
+
+// AuditLimit is the maximum number of audit entries returned by the history of an artifact.
+const AuditLimit = 100
+
+// Audit is a logged change of a single column of a file record,
+// or an operation that modified an asset of the artifact.
+type Audit struct {
+	ID       int64       `boil:"id"`        // ID is the unique id of the audit entry.
+	FileID   int64       `boil:"file_id"`   // FileID is the id of the file record.
+	Editor   null.String `boil:"editor"`    // Editor is the Google account ID of the editor.
+	Field    string      `boil:"field"`     // Field is the column name or the asset name.
+	OldValue null.String `boil:"old_value"` // OldValue is the value prior to the change, or null.
+	NewValue null.String `boil:"new_value"` // NewValue is the value after the change, or null.
+	Changed  time.Time   `boil:"changed"`   // Changed is the time of the change.
+	Created  bool        `boil:"created"`   // Created is true when the entry logs a value of a new record.
+}
+
+// Revertible returns true if the audit entry is a change to a file record column that can be reverted.
+// Asset operations, such as image crops, change files on disk and cannot be reverted,
+// while the values of a new record have no prior value to restore.
+func (a Audit) Revertible() bool {
+	if a.Created {
+		return false
+	}
+	_, ok := revertField(a.Field)
+	return ok
+}
+
+// Audits is a collection of audit entries.
+type Audits []*Audit
+
+// editorKey is the context key of the Google account ID of the signed in editor.
+type editorKey struct{}
+
+// WithEditor returns a copy of the context that carries the Google account ID of the signed in editor.
+// The ID is saved with every audit entry that is recorded using the returned context.
+func WithEditor(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, editorKey{}, id)
+}
+
+// EditorID returns the Google account ID of the editor carried by the context,
+// or an empty string if the context has no editor.
+func EditorID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(editorKey{}).(string)
+	return id
+}
+
+// auditColumn is a column of the files table and the struct field index of the file model.
+type auditColumn struct {
+	name  string
+	index int
+}
+
+// auditColumns returns the columns of the files table that are logged by the audit trail.
+// The primary key and the timestamps that are managed by the database are excluded,
+// while the deletedby column is a placeholder that always follows the deletedat column.
+func auditColumns() []auditColumn {
+	t := reflect.TypeFor[models.File]()
+	cols := make([]auditColumn, 0, t.NumField())
+	for i := range t.NumField() {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("boil"), ",")
+		switch name {
+		case "", "-",
+			models.FileColumns.ID,
+			models.FileColumns.Createdat,
+			models.FileColumns.Updatedat,
+			models.FileColumns.Deletedby:
+			continue
+		}
+		cols = append(cols, auditColumn{name: name, index: i})
+	}
+	return cols
+}
+
+// auditField returns the struct field index of the named column that is logged by the audit trail.
+func auditField(name string) (int, bool) {
+	for _, col := range auditColumns() {
+		if col.name == name {
+			return col.index, true
+		}
+	}
+	return -1, false
+}
+
+// revertField returns the struct field index of the named column that can be reverted by the audit trail.
+// The columns that identify the artifact file or confirm its integrity are logged but never reverted,
+// as restoring an old value would break the download, the previews and the duplicate detection.
+func revertField(name string) (int, bool) {
+	switch name {
+	case models.FileColumns.UUID,
+		models.FileColumns.Filename,
+		models.FileColumns.Filesize,
+		models.FileColumns.FileIntegrityStrong,
+		models.FileColumns.FileZipContent:
+		return -1, false
+	}
+	return auditField(name)
+}
+
+// AuditText returns the text representation of the value that is saved to the audit trail.
+// A SQL null value is returned as an invalid null string.
+func AuditText(v any) null.String {
+	if valuer, ok := v.(driver.Valuer); ok {
+		x, err := valuer.Value()
+		if err != nil {
+			return null.String{}
+		}
+		v = x
+	}
+	switch val := v.(type) {
+	case nil:
+		return null.String{}
+	case string:
+		return null.StringFrom(val)
+	case []byte:
+		return null.StringFrom(string(val))
+	case int64:
+		return null.StringFrom(strconv.FormatInt(val, 10))
+	case time.Time:
+		return null.StringFrom(val.Format(time.RFC3339Nano))
+	default:
+		return null.StringFrom(fmt.Sprint(val))
+	}
+}
+
+// auditSet sets the struct field value of the file model from the text representation of the audit trail.
+func auditSet(field reflect.Value, s null.String) error {
+	if !s.Valid {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	u, ok := field.Addr().Interface().(encoding.TextUnmarshaler)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrColumn, field.Type())
+	}
+	if err := u.UnmarshalText([]byte(s.String)); err != nil {
+		return fmt.Errorf("audit set %q: %w", s.String, err)
+	}
+	if str, ok := field.Addr().Interface().(*null.String); ok {
+		// an empty string is a valid value that is not null
+		str.Valid = true
+	}
+	return nil
+}
+
+// Diff is a change to a single column of a file record.
+type Diff struct {
+	Field string      // Field is the column name.
+	Old   null.String // Old is the value prior to the change, or null.
+	New   null.String // New is the value after the change, or null.
+}
+
+// Diffs returns the columns that differ between the old and the new copies of the file record.
+// The columns that are not logged by the audit trail, such as the timestamps, are ignored.
+func Diffs(old, f *models.File) []Diff {
+	if old == nil || f == nil {
+		return nil
+	}
+	var diffs []Diff
+	before, after := reflect.ValueOf(old).Elem(), reflect.ValueOf(f).Elem()
+	for _, col := range auditColumns() {
+		ov := AuditText(before.Field(col.index).Interface())
+		nv := AuditText(after.Field(col.index).Interface())
+		if ov == nv {
+			continue
+		}
+		diffs = append(diffs, Diff{Field: col.name, Old: ov, New: nv})
+	}
+	return diffs
+}
+
+// RecordAudit logs the columns that differ between the old and the new copies of the file record.
+// The Google account ID of the editor is taken from the context, see [WithEditor].
+// It should be called with the transaction that modifies the record, after the update and prior to the commit.
+func RecordAudit(ctx context.Context, exec boil.ContextExecutor, old, f *models.File) error {
+	const msg = "record audit"
+	if err := nils.Check(ctx, exec, old, f); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return recordDiffs(ctx, exec, old, f, false)
+}
+
+// RecordCreate logs the columns that were saved to the new file record.
+// The entries are marked as created, so they are kept as the history of the record but cannot be reverted.
+// It should be called with the transaction that inserts the record, after the insert and prior to the commit.
+func RecordCreate(ctx context.Context, exec boil.ContextExecutor, f *models.File) error {
+	const msg = "record create"
+	if err := nils.Check(ctx, exec, f); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return recordDiffs(ctx, exec, &models.File{}, f, true)
+}
+
+// recordDiffs logs the columns that differ between the old and the new copies of the file record.
+func recordDiffs(ctx context.Context, exec boil.ContextExecutor, old, f *models.File, created bool) error {
+	const msg = "record audit"
+	if f.ID < 1 {
+		return fmt.Errorf("%s: %w: %d", msg, ErrKey, f.ID)
+	}
+	editor := null.NewString(EditorID(ctx), EditorID(ctx) != "")
+	const insert = "INSERT INTO file_audits (file_id, editor, field, old_value, new_value, created) " +
+		"VALUES ($1, $2, $3, $4, $5, $6)"
+	for _, diff := range Diffs(old, f) {
+		if _, err := exec.ExecContext(ctx, insert, f.ID, editor, diff.Field, diff.Old, diff.New, created); err != nil {
+			return fmt.Errorf("%s %d %s: %w", msg, f.ID, diff.Field, err)
+		}
+	}
+	return nil
+}
+
+// RecordAsset logs an operation that modified the asset of the artifact, such as a crop of the preview image.
+// The unid is the universal unique id of the file record and the op is a short description of the operation.
+// The Google account ID of the editor is taken from the context, see [WithEditor].
+func RecordAsset(ctx context.Context, exec boil.ContextExecutor, unid string, asset Asset, op string) error {
+	const msg = "record asset"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if !asset.Valid() {
+		return fmt.Errorf("%s: %w: %q", msg, ErrAsset, asset)
+	}
+	editor := null.NewString(EditorID(ctx), EditorID(ctx) != "")
+	const insert = "INSERT INTO file_audits (file_id, editor, field, new_value) " +
+		"SELECT id, $2, $3, $4 FROM files WHERE uuid = $1"
+	if _, err := exec.ExecContext(ctx, insert, unid, editor, string(asset), op); err != nil {
+		return fmt.Errorf("%s %s %s: %w", msg, unid, asset, err)
+	}
+	return nil
+}
+
+// RecordEdit logs an edit of the asset of the file record id, such as a removed external link.
+// The before and after values describe the asset prior to and after the edit, and either can be null.
+// The Google account ID of the editor is taken from the context, see [WithEditor].
+func RecordEdit(ctx context.Context, exec boil.ContextExecutor, id int64, asset Asset, before, after null.String) error {
+	const msg = "record edit"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if id < 1 {
+		return fmt.Errorf("%s: %w: %d", msg, ErrKey, id)
+	}
+	if !asset.Valid() {
+		return fmt.Errorf("%s: %w: %q", msg, ErrAsset, asset)
+	}
+	if before == after {
+		return nil
+	}
+	editor := null.NewString(EditorID(ctx), EditorID(ctx) != "")
+	const insert = "INSERT INTO file_audits (file_id, editor, field, old_value, new_value) " +
+		"VALUES ($1, $2, $3, $4, $5)"
+	if _, err := exec.ExecContext(ctx, insert, id, editor, string(asset), before, after); err != nil {
+		return fmt.Errorf("%s %d %s: %w", msg, id, asset, err)
+	}
+	return nil
+}
+
+const auditSelect = "SELECT id, file_id, editor, field, old_value, new_value, changed, created FROM file_audits "
+
+// History saves the audit entries of the file record id, with the most recent changes first.
+func (a *Audits) History(ctx context.Context, exec boil.ContextExecutor, id int64, limit int) error {
+	nils.BoilExecCrash(exec)
+	if limit < 1 || limit > AuditLimit {
+		limit = AuditLimit
+	}
+	const query = auditSelect + "WHERE file_id = $1 ORDER BY id DESC LIMIT $2"
+	return queries.Raw(query, id, limit).Bind(ctx, exec, a)
+}
+
+// OneAudit returns the audit entry of the id.
+func OneAudit(ctx context.Context, exec boil.ContextExecutor, id int64) (*Audit, error) {
+	nils.BoilExecCrash(exec)
+	var a Audit
+	const query = auditSelect + "WHERE id = $1"
+	if err := queries.Raw(query, id).Bind(ctx, exec, &a); err != nil {
+		return nil, fmt.Errorf("one audit %d: %w", id, err)
+	}
+	return &a, nil
+}
+
+// Revert the single change of the audit entry id by restoring the old value of the column.
+// The revert is itself logged to the audit trail, so it can also be reverted.
+// The file record id of the audit entry is returned.
+func Revert(ctx context.Context, db *sql.DB, id int64) (int64, error) {
+	const msg = "revert audit"
+	const format = msg + " %s: %w"
+	if err := nils.Check(ctx, db); err != nil {
+		return 0, fmt.Errorf(format, "check", err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf(format, "begin tx", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	a, err := OneAudit(ctx, tx, id)
+	if err != nil {
+		return 0, fmt.Errorf(format, "one audit", err)
+	}
+	index, ok := revertField(a.Field)
+	if !ok || a.Created {
+		return a.FileID, fmt.Errorf("%s %d: %w: %s", msg, id, ErrRevert, a.Field)
+	}
+	f, err := OneFile(ctx, tx, a.FileID)
+	if err != nil {
+		return a.FileID, fmt.Errorf(format, "one file", err)
+	}
+	old := *f
+	if err = auditSet(reflect.ValueOf(f).Elem().Field(index), a.OldValue); err != nil {
+		return a.FileID, fmt.Errorf(format, a.Field, err)
+	}
+	kind := Updated
+	if a.Field == models.FileColumns.Deletedat {
+		kind = Restored
+		f.Deletedby = null.String{}
+		if f.Deletedat.Valid {
+			kind = SoftDeleted
+			f.Deletedby = null.StringFrom(strings.ToLower(uidPlaceholder))
+		}
+	}
+	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
+		return a.FileID, fmt.Errorf(format, "update", err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return a.FileID, fmt.Errorf(format, "record audit", err)
+	}
+	if err = RecordChange(ctx, tx, f.ID, kind); err != nil {
+		return a.FileID, fmt.Errorf(format, "record change", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return a.FileID, fmt.Errorf(fmttx, msg, err)
+	}
+	return a.FileID, nil
+}
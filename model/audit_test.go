@@ -0,0 +1,69 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/Defacto2/server/model"
+	"github.com/aarondl/null/v8"
+	"github.com/nalgeon/be"
+)
+
+func TestAssetValid(t *testing.T) {
+	t.Parallel()
+	be.True(t, model.Preview.Valid())
+	be.True(t, model.Thumbnail.Valid())
+	be.True(t, model.Related.Valid())
+	be.True(t, model.External.Valid())
+	be.True(t, model.Record.Valid())
+	be.True(t, !model.Asset("").Valid())
+	be.True(t, !model.Asset("download").Valid())
+}
+
+func TestEditorID(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	be.Equal(t, "", model.EditorID(ctx))
+	ctx = model.WithEditor(ctx, "1234567890")
+	be.Equal(t, "1234567890", model.EditorID(ctx))
+}
+
+func TestAuditText(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, null.String{}, model.AuditText(null.String{}))
+	be.Equal(t, null.StringFrom(""), model.AuditText(null.StringFrom("")))
+	be.Equal(t, null.StringFrom("abc"), model.AuditText(null.StringFrom("abc")))
+	be.Equal(t, null.StringFrom("1990"), model.AuditText(null.Int16From(1990)))
+	be.Equal(t, null.String{}, model.AuditText(null.Int64{}))
+	tm := time.Date(1990, 1, 2, 3, 4, 5, 0, time.UTC)
+	be.Equal(t, null.StringFrom("1990-01-02T03:04:05Z"), model.AuditText(null.TimeFrom(tm)))
+}
+
+func TestAuditRevertible(t *testing.T) {
+	t.Parallel()
+	be.True(t, model.Audit{Field: models.FileColumns.RecordTitle}.Revertible())
+	be.True(t, model.Audit{Field: models.FileColumns.Deletedat}.Revertible())
+	be.True(t, !model.Audit{Field: models.FileColumns.ID}.Revertible())
+	be.True(t, !model.Audit{Field: models.FileColumns.Updatedat}.Revertible())
+	be.True(t, !model.Audit{Field: string(model.Preview)}.Revertible())
+	be.True(t, !model.Audit{Field: models.FileColumns.RecordTitle, Created: true}.Revertible())
+	be.True(t, !model.Audit{Field: models.FileColumns.UUID}.Revertible())
+	be.True(t, !model.Audit{Field: models.FileColumns.Filename}.Revertible())
+	be.True(t, !model.Audit{Field: models.FileColumns.Filesize}.Revertible())
+	be.True(t, !model.Audit{Field: models.FileColumns.FileIntegrityStrong}.Revertible())
+	be.True(t, !model.Audit{Field: models.FileColumns.FileZipContent}.Revertible())
+}
+
+func TestRecordAudit(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	err := model.RecordAudit(ctx, nil, &models.File{}, &models.File{ID: 1})
+	be.Err(t, err)
+	err = model.RecordCreate(ctx, nil, &models.File{ID: 1})
+	be.Err(t, err)
+	err = model.RecordAsset(ctx, nil, "", model.Preview, "crop")
+	be.Err(t, err)
+	err = model.RecordEdit(ctx, nil, 1, model.External, null.String{}, null.StringFrom("website"))
+	be.Err(t, err)
+}
@@ -0,0 +1,268 @@
+package model
+
+// Package file bulk.go contains the bulk editor operations that modify a filtered set of artifacts
+// in a single transaction, such as a releaser rename or a reclassification of hundreds of text files.
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/null/v8"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+)
+
+var (
+	ErrBulkFilter = errors.New("bulk operation requires at least one filter")
+	ErrBulkLimit  = errors.New("bulk operation matches too many artifacts")
+	ErrBulkOp     = errors.New("bulk operation is invalid")
+)
+
+// BulkLimit is the maximum number of artifacts that can be modified by a single bulk operation.
+const BulkLimit = 1000
+
+// BulkOp is the operation applied to every artifact of a bulk selection.
+type BulkOp string
+
+const (
+	BulkReleasers      BulkOp = "releasers"      // BulkReleasers replaces the releasers, see [UpdateReleasers].
+	BulkClassification BulkOp = "classification" // BulkClassification sets the platform and section tags.
+	BulkDateIssued     BulkOp = "date"           // BulkDateIssued sets the year, month and day of publication.
+	BulkOnline         BulkOp = "online"         // BulkOnline makes the artifacts public.
+	BulkOffline        BulkOp = "offline"        // BulkOffline hides the artifacts from the public.
+	BulkCreators       BulkOp = "creators"       // BulkCreators sets the credits that are not empty.
+)
+
+// Valid returns true if the bulk operation is known.
+func (op BulkOp) Valid() bool {
+	switch op {
+	case BulkReleasers, BulkClassification, BulkDateIssued, BulkOnline, BulkOffline, BulkCreators:
+		return true
+	}
+	return false
+}
+
+// Bulk is the selection of artifacts and the operation to apply to each of them.
+// The selection uses the [Keyset] filters, and by default only includes the public artifacts.
+type Bulk struct {
+	Filter      Keyset // Filter selects the artifacts by the platform, section, releaser and years.
+	ForApproval bool   // ForApproval selects the new uploads that are waiting for approval.
+	MagicErr    bool   // MagicErr selects the artifacts that require new magic numbers, see [MagicErrExpr].
+	Hidden      bool   // Hidden also selects the artifacts that are offline and hidden from the public.
+
+	Op        BulkOp // Op is the operation to apply.
+	Releasers string // Releasers are the new releasers, where two are separated by a + (plus).
+	Platform  string // Platform is the new platform tag URI.
+	Section   string // Section is the new section tag URI.
+	Year      string // Year is the new year of publication.
+	Month     string // Month is the new month of publication.
+	Day       string // Day is the new day of publication.
+	Text      string // Text is the new writer credits.
+	Ill       string // Ill is the new artist credits.
+	Prog      string // Prog is the new programmer credits.
+	Audio     string // Audio is the new composer credits.
+}
+
+// BulkResult is the changes made to a single artifact by the bulk operation.
+type BulkResult struct {
+	ID    int64  // ID is the database id of the artifact.
+	Name  string // Name is the title or filename of the artifact.
+	Diffs []Diff // Diffs are the modified columns.
+}
+
+// BulkReport is the outcome of a bulk operation.
+type BulkReport struct {
+	Op      BulkOp       // Op is the applied operation.
+	DryRun  bool         // DryRun is true when the changes were previewed but not saved.
+	Matched int          // Matched is the number of selected artifacts.
+	Results []BulkResult // Results are the artifacts that were, or would be, changed.
+}
+
+// Changed returns the number of artifacts that were, or would be, changed.
+func (r BulkReport) Changed() int {
+	return len(r.Results)
+}
+
+// Validate the selection and the values of the bulk operation.
+func (b Bulk) Validate() error {
+	if !b.Op.Valid() {
+		return fmt.Errorf("%w: %q", ErrBulkOp, b.Op)
+	}
+	if err := b.Filter.Validate(); err != nil {
+		return err
+	}
+	k := b.Filter
+	if k.Platform == "" && k.Section == "" && k.Releaser == "" && k.YearFrom == 0 && k.YearTo == 0 &&
+		!b.ForApproval && !b.MagicErr {
+		return ErrBulkFilter
+	}
+	switch b.Op { //nolint:exhaustive
+	case BulkReleasers:
+		s, err := splitReleasers(b.Releasers)
+		if err != nil {
+			return err
+		}
+		if s[0] == "" {
+			return fmt.Errorf("%w: the releaser cannot be empty", ErrBulkOp)
+		}
+	case BulkDateIssued:
+		return b.validDate()
+	case BulkClassification:
+		return validClassification(b.Platform, b.Section)
+	case BulkCreators:
+		if strings.TrimSpace(b.Text+b.Ill+b.Prog+b.Audio) == "" {
+			return fmt.Errorf("%w: no credits to apply", ErrBulkOp)
+		}
+	}
+	return nil
+}
+
+// validDate returns an error if the date of publication uses the values that are out of range,
+// using the same rules as the editor of a single artifact. The year is required,
+// while an empty month or day is not in use.
+func (b Bulk) validDate() error {
+	y := strings.TrimSpace(b.Year)
+	m := cmp.Or(strings.TrimSpace(b.Month), "0")
+	d := cmp.Or(strings.TrimSpace(b.Day), "0")
+	yok, mok, dok := ValidDate(y, m, d)
+	switch {
+	case !yok || y == "0":
+		return fmt.Errorf("%w: %q", ErrYear, b.Year)
+	case !mok:
+		return fmt.Errorf("%w: %q", ErrMonth, b.Month)
+	case !dok:
+		return fmt.Errorf("%w: %q", ErrDay, b.Day)
+	}
+	return nil
+}
+
+// Mods returns the query mods of the bulk selection.
+func (b Bulk) Mods() []qm.QueryMod {
+	mods := b.Filter.Mods()
+	switch {
+	case b.ForApproval:
+		mods = append(mods, qm.WithDeleted(),
+			models.FileWhere.Deletedat.IsNotNull(),
+			models.FileWhere.Deletedby.IsNull())
+	case b.Hidden:
+		mods = append(mods, qm.WithDeleted())
+	}
+	if b.MagicErr {
+		mods = append(mods, MagicErrExpr(false))
+	}
+	return mods
+}
+
+// Apply the bulk operation to every selected artifact in a single transaction.
+// When dryRun is true the changes are rolled back and the report is a preview of the changes.
+// The Google account ID of the editor is taken from the context, see [WithEditor].
+func (b Bulk) Apply(ctx context.Context, db *sql.DB, dryRun bool) (BulkReport, error) {
+	const msg = "bulk apply"
+	const format = msg + " %s: %w"
+	report := BulkReport{Op: b.Op, DryRun: dryRun}
+	if err := nils.Check(ctx, db); err != nil {
+		return report, fmt.Errorf(format, "check", err)
+	}
+	if err := b.Validate(); err != nil {
+		return report, fmt.Errorf(format, "validate", err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return report, fmt.Errorf(format, "begin tx", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	mods := append(b.Mods(), qm.OrderBy("id ASC"), qm.Limit(BulkLimit+1))
+	fs, err := models.Files(mods...).All(ctx, tx)
+	if err != nil {
+		return report, fmt.Errorf(format, "select", err)
+	}
+	if len(fs) > BulkLimit {
+		return report, fmt.Errorf("%s: %w, the maximum is %d", msg, ErrBulkLimit, BulkLimit)
+	}
+	report.Matched = len(fs)
+	for _, f := range fs {
+		old := *f
+		kind, ok := b.set(f)
+		if !ok {
+			continue
+		}
+		diffs := Diffs(&old, f)
+		if len(diffs) == 0 {
+			continue
+		}
+		name := f.RecordTitle.String
+		if name == "" {
+			name = f.Filename.String
+		}
+		report.Results = append(report.Results, BulkResult{ID: f.ID, Name: name, Diffs: diffs})
+		if dryRun {
+			continue
+		}
+		if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
+			return report, fmt.Errorf("%s update %d: %w", msg, f.ID, err)
+		}
+		if err = RecordAudit(ctx, tx, &old, f); err != nil {
+			return report, fmt.Errorf("%s record audit: %w", msg, err)
+		}
+		if err = RecordChange(ctx, tx, f.ID, kind); err != nil {
+			return report, fmt.Errorf("%s record change: %w", msg, err)
+		}
+	}
+	if dryRun {
+		return report, nil
+	}
+	if err = tx.Commit(); err != nil {
+		return report, fmt.Errorf(fmttx, msg, err)
+	}
+	return report, nil
+}
+
+// set applies the bulk operation to the file record and returns the kind of change.
+// False is returned when the operation does not apply to the file record.
+func (b Bulk) set(f *models.File) (Change, bool) {
+	switch b.Op {
+	case BulkReleasers:
+		s, err := splitReleasers(b.Releasers)
+		if err != nil {
+			return "", false
+		}
+		setReleasers(f, s)
+	case BulkClassification:
+		setClassification(f, b.Platform, b.Section)
+	case BulkDateIssued:
+		setDateIssued(f, b.Year, b.Month, b.Day)
+	case BulkOnline:
+		if !f.Deletedat.Valid {
+			return "", false
+		}
+		setOnline(f)
+		return Restored, true
+	case BulkOffline:
+		if f.Deletedat.Valid {
+			return "", false
+		}
+		setOffline(f)
+		return SoftDeleted, true
+	case BulkCreators:
+		credit := func(col *null.String, val string) {
+			if s := strings.TrimSpace(val); s != "" {
+				*col = null.StringFrom(s)
+			}
+		}
+		credit(&f.CreditText, b.Text)
+		credit(&f.CreditIllustration, b.Ill)
+		credit(&f.CreditProgram, b.Prog)
+		credit(&f.CreditAudio, b.Audio)
+	default:
+		return "", false
+	}
+	return Updated, true
+}
@@ -0,0 +1,85 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/Defacto2/server/internal/tags"
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestBulkOpValid(t *testing.T) {
+	t.Parallel()
+	be.True(t, model.BulkReleasers.Valid())
+	be.True(t, model.BulkOffline.Valid())
+	be.True(t, !model.BulkOp("").Valid())
+	be.True(t, !model.BulkOp("delete").Valid())
+}
+
+func TestBulkValidate(t *testing.T) {
+	t.Parallel()
+	b := model.Bulk{Op: "delete"}
+	be.Err(t, b.Validate(), model.ErrBulkOp)
+	b = model.Bulk{Op: model.BulkOffline}
+	be.Err(t, b.Validate(), model.ErrBulkFilter)
+	b = model.Bulk{Op: model.BulkOffline, Hidden: true}
+	be.Err(t, b.Validate(), model.ErrBulkFilter)
+	b = model.Bulk{Op: model.BulkOffline, Filter: model.Keyset{Platform: "demo"}}
+	be.Err(t, b.Validate(), model.ErrPlatform)
+	b = model.Bulk{Op: model.BulkOffline, Filter: model.Keyset{Releaser: "razor-1911"}}
+	be.Err(t, b.Validate(), nil)
+	b = model.Bulk{Op: model.BulkOnline, ForApproval: true}
+	be.Err(t, b.Validate(), nil)
+	b = model.Bulk{Op: model.BulkReleasers, MagicErr: true, Releasers: "a+b+c"}
+	be.Err(t, b.Validate(), model.ErrRels)
+	b = model.Bulk{Op: model.BulkReleasers, MagicErr: true, Releasers: "razor 1911+skid row"}
+	be.Err(t, b.Validate(), nil)
+	b.Releasers = " "
+	be.Err(t, b.Validate(), model.ErrBulkOp)
+	b.Releasers = "+skid row"
+	be.Err(t, b.Validate(), model.ErrBulkOp)
+	b = model.Bulk{Op: model.BulkDateIssued, MagicErr: true}
+	be.Err(t, b.Validate(), model.ErrYear)
+	b.Year = "1979"
+	be.Err(t, b.Validate(), model.ErrYear)
+	b.Year, b.Month = "1994", "13"
+	be.Err(t, b.Validate(), model.ErrMonth)
+	b.Month, b.Day = "", "5"
+	be.Err(t, b.Validate(), model.ErrMonth)
+	b.Month, b.Day = "2", "32"
+	be.Err(t, b.Validate(), model.ErrDay)
+	b.Day = ""
+	be.Err(t, b.Validate(), nil)
+	b = model.Bulk{
+		Op: model.BulkClassification, Filter: model.Keyset{Section: "demo"},
+		Platform: "dos", Section: "not-a-tag",
+	}
+	be.Err(t, b.Validate(), tags.ErrTag)
+	b.Section = "demo"
+	be.Err(t, b.Validate(), nil)
+	b = model.Bulk{Op: model.BulkCreators, Filter: model.Keyset{YearFrom: 1990}, Text: " "}
+	be.Err(t, b.Validate(), model.ErrBulkOp)
+	b.Text = "writer"
+	be.Err(t, b.Validate(), nil)
+}
+
+func TestBulkMods(t *testing.T) {
+	t.Parallel()
+	b := model.Bulk{Filter: model.Keyset{Platform: "dos"}}
+	be.Equal(t, len(b.Mods()), 1)
+	b.Hidden = true
+	be.Equal(t, len(b.Mods()), 2)
+	b.ForApproval = true
+	be.Equal(t, len(b.Mods()), 4)
+	b.MagicErr = true
+	be.Equal(t, len(b.Mods()), 5)
+}
+
+func TestBulkApply(t *testing.T) {
+	t.Parallel()
+	b := model.Bulk{Op: model.BulkOffline, ForApproval: true}
+	r, err := b.Apply(t.Context(), nil, true)
+	be.Err(t, err)
+	be.Equal(t, r.Op, model.BulkOffline)
+	be.Equal(t, r.Changed(), 0)
+}
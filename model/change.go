@@ -0,0 +1,118 @@
+package model
+
+// Package file change.go contains the database queries for the log of changes made to the file records.
+// The log is used by the change feed, so mirrors and other downstream indexes can sync the deltas
+// without the need to crawl every artifact.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/aarondl/null/v8"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+)
+
+var ErrChange = errors.New("change kind is invalid")
+
+// Change is the kind of change made to a file record.
+type Change string
+
+const (
+	Created     Change = "create"      // Created is a new file record.
+	Updated     Change = "update"      // Updated is an edit of an existing file record.
+	SoftDeleted Change = "soft-delete" // SoftDeleted is a file record that was taken offline.
+	Restored    Change = "restore"     // Restored is a file record that was put online and made public.
+	HardDeleted Change = "hard-delete" // HardDeleted is a file record that was permanently deleted.
+)
+
+// changeLock is the key of the transaction level advisory lock used when recording a change.
+// The lock serializes the writers of the log, so the sequence numbers are visible in their commit order
+// and a consumer that reads the log after a sequence number will never miss a change.
+const changeLock = 0x0d2c
+
+// Valid returns true if the change is a known kind.
+func (c Change) Valid() bool {
+	switch c {
+	case Created, Updated, SoftDeleted, Restored, HardDeleted:
+		return true
+	}
+	return false
+}
+
+// FileChange is a logged change of a file record.
+type FileChange struct {
+	Seq     int64       `boil:"seq"`     // Seq is the monotonic sequence number of the change.
+	FileID  int64       `boil:"file_id"` // FileID is the id of the file record.
+	UUID    null.String `boil:"uuid"`    // UUID of the file record.
+	Kind    string      `boil:"kind"`    // Kind of change.
+	Changed time.Time   `boil:"changed"` // Changed is the time of the change.
+	Title   null.String `boil:"title"`   // Title is the record title or filename of the file record.
+}
+
+// Changes is a collection of logged changes.
+type Changes []*FileChange
+
+// RecordChange logs the kind of change made to the file record id.
+// It should be called with the transaction that modifies the record, prior to the commit,
+// and for a permanent deletion it must be called before the record is deleted.
+func RecordChange(ctx context.Context, exec boil.ContextExecutor, id int64, kind Change) error {
+	const msg = "record change"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if id < 1 {
+		return fmt.Errorf("%s: %w: %d", msg, ErrKey, id)
+	}
+	if !kind.Valid() {
+		return fmt.Errorf("%s: %w: %q", msg, ErrChange, kind)
+	}
+	const lock = "SELECT pg_advisory_xact_lock($1)"
+	if _, err := exec.ExecContext(ctx, lock, changeLock); err != nil {
+		return fmt.Errorf("%s lock: %w", msg, err)
+	}
+	const insert = "INSERT INTO file_changes (file_id, uuid, kind) " +
+		"SELECT id, uuid, $2 FROM files WHERE id = $1"
+	if _, err := exec.ExecContext(ctx, insert, id, string(kind)); err != nil {
+		return fmt.Errorf("%s %d %s: %w", msg, id, kind, err)
+	}
+	return nil
+}
+
+// changesSelect is the query of the logged changes that excludes the changes to the records
+// that are not public, except for the deletions.
+const changesSelect = "SELECT file_changes.seq, file_changes.file_id, file_changes.uuid, " +
+	"file_changes.kind, file_changes.changed, " +
+	"COALESCE(NULLIF(files.record_title, ''), files.filename) AS title " +
+	"FROM file_changes " +
+	"LEFT JOIN files ON files.id = file_changes.file_id " +
+	"WHERE (file_changes.kind IN ('soft-delete', 'hard-delete') OR files.deletedat IS NULL) " +
+	"AND (file_changes.kind = 'hard-delete' OR files.id IS NOT NULL) "
+
+// After saves the changes that follow the sequence number, in the order they were made.
+// Changes to the records that are not public are excluded, except for the deletions.
+func (c *Changes) After(ctx context.Context, exec boil.ContextExecutor, seq int64, limit int) error {
+	nils.BoilExecCrash(exec)
+	if seq < 0 {
+		seq = 0
+	}
+	if limit < 1 {
+		limit = Maximum
+	}
+	const query = changesSelect + "AND file_changes.seq > $1 ORDER BY file_changes.seq ASC LIMIT $2"
+	return queries.Raw(query, seq, limit).Bind(ctx, exec, c)
+}
+
+// Latest saves the most recent changes, in the reverse order they were made.
+// Changes to the records that are not public are excluded, except for the deletions.
+func (c *Changes) Latest(ctx context.Context, exec boil.ContextExecutor, limit int) error {
+	nils.BoilExecCrash(exec)
+	if limit < 1 {
+		limit = Maximum
+	}
+	const query = changesSelect + "ORDER BY file_changes.seq DESC LIMIT $1"
+	return queries.Raw(query, limit).Bind(ctx, exec, c)
+}
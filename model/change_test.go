@@ -0,0 +1,25 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestChangeValid(t *testing.T) {
+	t.Parallel()
+	be.True(t, model.Created.Valid())
+	be.True(t, model.Updated.Valid())
+	be.True(t, model.SoftDeleted.Valid())
+	be.True(t, model.Restored.Valid())
+	be.True(t, model.HardDeleted.Valid())
+	be.True(t, !model.Change("").Valid())
+	be.True(t, !model.Change("delete").Valid())
+}
+
+func TestRecordChange(t *testing.T) {
+	t.Parallel()
+	err := model.RecordChange(t.Context(), nil, 1, model.Updated)
+	be.Err(t, err)
+}
@@ -6,12 +6,14 @@ import (
 
 	"github.com/Defacto2/server/internal/nils"
 	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/null/v8"
 	"github.com/aarondl/sqlboiler/v4/boil"
 	"github.com/aarondl/sqlboiler/v4/queries/qm"
 )
 
 // DeleteOne retrieves a single file record from the database using the record key.
 // This function can return records that have been marked as deleted.
+// The deletion is logged to the audit trail using the editor carried by the context, see [WithEditor].
 func DeleteOne(ctx context.Context, exec boil.ContextExecutor, key int64) error {
 	const msg = "delete one record"
 	const format = msg + " %d: %w"
@@ -21,6 +23,12 @@ func DeleteOne(ctx context.Context, exec boil.ContextExecutor, key int64) error
 	if key < 1 {
 		return fmt.Errorf(format, key, ErrKey)
 	}
+	if err := RecordChange(ctx, exec, key, HardDeleted); err != nil {
+		return fmt.Errorf(format, key, err)
+	}
+	if err := RecordEdit(ctx, exec, key, Record, null.String{}, null.StringFrom("delete forever")); err != nil {
+		return fmt.Errorf(format, key, err)
+	}
 	mods := models.FileWhere.ID.EQ(key)
 	_, err := models.Files(mods, qm.WithDeleted()).DeleteAll(ctx, exec, true)
 	if err != nil {
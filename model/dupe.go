@@ -0,0 +1,266 @@
+package model
+
+// Package file dupe.go contains the database queries for the fingerprints of the artifacts,
+// which are used to find the clusters of duplicate and near duplicate artifacts.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/Defacto2/server/internal/dupe"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+)
+
+var ErrHash = errors.New("fingerprint hash is empty")
+
+// Common is the maximum number of artifacts that can share a fingerprint, before the fingerprint
+// is ignored as too common to suggest a duplicate, such as a BBS advert or a popular file packer.
+const Common = 10
+
+// Duplicate is a cluster of public artifacts that are candidate duplicates of each other.
+type Duplicate struct {
+	Files models.FileSlice // Files are the artifacts of the cluster ordered by their id.
+	Kinds []dupe.Kind      // Kinds are the types of fingerprints shared by the artifacts.
+}
+
+// HashStamps returns the last modified times of the fingerprints, keyed by the artifact id.
+func HashStamps(ctx context.Context, exec boil.ContextExecutor) (map[int64]time.Time, error) {
+	nils.BoilExecCrash(exec)
+	const query = "SELECT file_id, MAX(modified) AS modified FROM file_hashes GROUP BY file_id"
+	var stamps []TextStamp
+	if err := queries.Raw(query).Bind(ctx, exec, &stamps); err != nil {
+		return nil, fmt.Errorf("hash stamps: %w", err)
+	}
+	m := make(map[int64]time.Time, len(stamps))
+	for _, s := range stamps {
+		m[s.FileID] = s.Modified
+	}
+	return m, nil
+}
+
+// noHashes is the kind of the empty fingerprint that stamps an artifact without any fingerprints,
+// so the artifact is not fingerprinted again until it is modified.
+const noHashes = ""
+
+// ReplaceHashes removes any existing fingerprints of the artifact id and saves the hashes.
+// An artifact without any hashes is saved with an empty fingerprint that is ignored by the dupe queries.
+// The modified time should be the last modified time of the source files.
+func ReplaceHashes(ctx context.Context, db *sql.DB,
+	id int64, modified time.Time, hashes ...dupe.Hash,
+) error {
+	const msg = "replace hashes"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if id < 1 {
+		return fmt.Errorf("%s: %w: %d", msg, ErrID, id)
+	}
+	for _, h := range hashes {
+		if h.Value == "" {
+			return fmt.Errorf("%s: %w", msg, ErrHash)
+		}
+	}
+	if len(hashes) == 0 {
+		hashes = []dupe.Hash{{Kind: noHashes, Value: "", Name: ""}}
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	const remove = "DELETE FROM file_hashes WHERE file_id = $1"
+	if _, err := tx.ExecContext(ctx, remove, id); err != nil {
+		return fmt.Errorf("%s delete: %w", msg, err)
+	}
+	const insert = "INSERT INTO file_hashes (file_id, kind, hash, name, modified) VALUES ($1, $2, $3, $4, $5) " +
+		"ON CONFLICT DO NOTHING"
+	for _, h := range hashes {
+		if _, err := tx.ExecContext(ctx, insert, id, string(h.Kind), h.Value, h.Name, modified); err != nil {
+			return fmt.Errorf("%s insert %q: %w", msg, h.Name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
+	return nil
+}
+
+// IndexHashes walks the artifacts and saves the fingerprints of those that are new or have been
+// modified since they were last fingerprinted. The artifacts without any fingerprints are stamped,
+// and any previous fingerprints of a modified artifact are removed.
+// It returns the number of artifacts indexed and the number skipped as unmodified.
+func IndexHashes(ctx context.Context, db *sql.DB, src dupe.Sources) (int, int, error) {
+	const msg = "index hashes"
+	if err := nils.Check(ctx, db); err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	indexed, skipped := 0, 0
+	stamps, err := HashStamps(ctx, db)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	arts, err := TextSources(ctx, db)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	for _, art := range arts {
+		if err := ctx.Err(); err != nil {
+			return indexed, skipped, fmt.Errorf("%s: %w", msg, err)
+		}
+		modified := src.Modified(art)
+		if modified.IsZero() {
+			continue
+		}
+		if last, ok := stamps[art.ID]; ok && !modified.After(last) {
+			skipped++
+			continue
+		}
+		hashes := src.Hashes(art)
+		if err := ReplaceHashes(ctx, db, art.ID, modified, hashes...); err != nil {
+			return indexed, skipped, fmt.Errorf("%s: %w", msg, err)
+		}
+		indexed++
+	}
+	return indexed, skipped, nil
+}
+
+// unrelated is the SQL condition that excludes the pairs of artifacts that already have a relation.
+const unrelated = "NOT EXISTS (SELECT 1 FROM artifact_relations r WHERE " +
+	"(r.file_id = a.file_id AND r.target_id = b.file_id) OR (r.file_id = b.file_id AND r.target_id = a.file_id))"
+
+// DupePairs returns the pairs of public artifacts that share either the same archived files or the same texts.
+// The pair must share at least half the fingerprints of the artifact with the fewest fingerprints,
+// and the fingerprints shared by more than the [Common] number of artifacts are ignored.
+// Pairs of artifacts that already have a relation are not returned.
+func DupePairs(ctx context.Context, exec boil.ContextExecutor) ([]dupe.Pair, error) {
+	const msg = "dupe pairs"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "WITH h AS (" +
+		"SELECT fh.file_id, fh.kind, fh.hash FROM file_hashes fh JOIN files f ON f.id = fh.file_id " +
+		"WHERE f.deletedat IS NULL AND fh.kind IN ($1, $2)), " +
+		"common AS (SELECT kind, hash FROM h GROUP BY kind, hash HAVING COUNT(*) BETWEEN 2 AND $3), " +
+		"totals AS (SELECT file_id, kind, COUNT(*) AS total FROM h GROUP BY file_id, kind) " +
+		"SELECT a.file_id AS a, b.file_id AS b, a.kind, COUNT(*) AS shared FROM h a " +
+		"JOIN h b ON b.kind = a.kind AND b.hash = a.hash AND b.file_id > a.file_id " +
+		"JOIN common c ON c.kind = a.kind AND c.hash = a.hash " +
+		"JOIN totals ta ON ta.file_id = a.file_id AND ta.kind = a.kind " +
+		"JOIN totals tb ON tb.file_id = b.file_id AND tb.kind = b.kind " +
+		"WHERE " + unrelated + " " +
+		"GROUP BY a.file_id, b.file_id, a.kind, ta.total, tb.total " +
+		"HAVING COUNT(*) * 2 >= LEAST(ta.total, tb.total) " +
+		"ORDER BY a.file_id, b.file_id"
+	var pairs []dupe.Pair
+	if err := queries.Raw(query, string(dupe.Member), string(dupe.Text), Common).Bind(ctx, exec, &pairs); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	return pairs, nil
+}
+
+// SimilarPairs returns the pairs of public artifacts with visually similar thumbnails.
+// Pairs of artifacts that already have a relation are not returned.
+func SimilarPairs(ctx context.Context, exec boil.ContextExecutor) ([]dupe.Pair, error) {
+	const msg = "similar pairs"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT fh.file_id, fh.hash FROM file_hashes fh JOIN files f ON f.id = fh.file_id " +
+		"WHERE f.deletedat IS NULL AND fh.kind = $1"
+	var rows []struct {
+		FileID int64  `boil:"file_id"`
+		Hash   string `boil:"hash"`
+	}
+	if err := queries.Raw(query, string(dupe.Image)).Bind(ctx, exec, &rows); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	hashes := make(map[int64]uint64, len(rows))
+	for _, row := range rows {
+		hash, err := dupe.ParseHash(row.Hash)
+		if err != nil || hash == 0 {
+			continue
+		}
+		hashes[row.FileID] = hash
+	}
+	pairs := dupe.Similar(hashes)
+	if len(pairs) == 0 {
+		return pairs, nil
+	}
+	var related []struct {
+		A int64 `boil:"a"`
+		B int64 `boil:"b"`
+	}
+	const relations = "SELECT LEAST(file_id, target_id) AS a, GREATEST(file_id, target_id) AS b " +
+		"FROM artifact_relations"
+	if err := queries.Raw(relations).Bind(ctx, exec, &related); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	skip := make(map[[2]int64]bool, len(related))
+	for _, r := range related {
+		skip[[2]int64{r.A, r.B}] = true
+	}
+	return slices.DeleteFunc(pairs, func(p dupe.Pair) bool {
+		return skip[[2]int64{p.A, p.B}]
+	}), nil
+}
+
+// Duplicates returns the clusters of the public artifacts that are candidate duplicates,
+// and the total number of clusters. The limit is the maximum number of clusters to return.
+func Duplicates(ctx context.Context, exec boil.ContextExecutor, limit int) ([]Duplicate, int, error) {
+	const msg = "duplicates"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	exact, err := DupePairs(ctx, exec)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	similar, err := SimilarPairs(ctx, exec)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	pairs := slices.Concat(exact, similar)
+	clusters := dupe.Clusters(pairs...)
+	total := len(clusters)
+	if limit > 0 && len(clusters) > limit {
+		clusters = clusters[:limit]
+	}
+	if len(clusters) == 0 {
+		return []Duplicate{}, total, nil
+	}
+	ids := []int64{}
+	cluster := make(map[int64]int)
+	for i, group := range clusters {
+		for _, id := range group {
+			ids = append(ids, id)
+			cluster[id] = i
+		}
+	}
+	fs, err := models.Files(
+		qm.Where("id = ANY (?::bigint[])", ids),
+		qm.OrderBy(models.FileColumns.ID)).All(ctx, exec)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	dupes := make([]Duplicate, len(clusters))
+	for _, f := range fs {
+		i := cluster[f.ID]
+		dupes[i].Files = append(dupes[i].Files, f)
+	}
+	for _, p := range pairs {
+		i, ok := cluster[p.A]
+		if !ok || slices.Contains(dupes[i].Kinds, p.Kind) {
+			continue
+		}
+		dupes[i].Kinds = append(dupes[i].Kinds, p.Kind)
+	}
+	return dupes, total, nil
+}
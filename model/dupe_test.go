@@ -0,0 +1,25 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/internal/dupe"
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestDuplicatesNil(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	_, err := model.DupePairs(ctx, nil)
+	be.Err(t, err)
+	_, err = model.SimilarPairs(ctx, nil)
+	be.Err(t, err)
+	_, _, err = model.Duplicates(ctx, nil, 10)
+	be.Err(t, err)
+	err = model.ReplaceHashes(ctx, nil, 1, time.Now())
+	be.Err(t, err)
+	_, _, err = model.IndexHashes(ctx, nil, dupe.Sources{})
+	be.Err(t, err)
+}
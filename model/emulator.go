@@ -0,0 +1,81 @@
+package model
+
+// Package file emulator.go contains the database queries for the emulator settings of the artifacts.
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Defacto2/server/handler/jsdos"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/aarondl/sqlboiler/v4/boil"
+)
+
+// EmulatorSettings returns the emulator settings chosen by an editor for the artifact id,
+// or empty settings when the artifact uses the emulator defaults.
+func EmulatorSettings(ctx context.Context, exec boil.ContextExecutor, id int64) (jsdos.Settings, error) {
+	const msg = "emulator settings"
+	if err := nils.Check(ctx, exec); err != nil {
+		return jsdos.Settings{}, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT settings FROM file_emulators WHERE file_id = $1"
+	var b []byte
+	err := exec.QueryRowContext(ctx, query, id).Scan(&b)
+	if errors.Is(err, sql.ErrNoRows) {
+		return jsdos.Settings{}, nil
+	}
+	if err != nil {
+		return jsdos.Settings{}, fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	var s jsdos.Settings
+	if err := json.Unmarshal(b, &s); err != nil {
+		return jsdos.Settings{}, fmt.Errorf("%s %d unmarshal: %w", msg, id, err)
+	}
+	return s, nil
+}
+
+// UpdateEmulatorSettings replaces the emulator settings of the artifact id.
+// The settings are normalized and must be valid, while empty settings restore the emulator defaults.
+func UpdateEmulatorSettings(ctx context.Context, db *sql.DB, id int64, s jsdos.Settings) error {
+	const msg = "update emulator settings"
+	const format = msg + " %s: %w"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf(format, "check", err)
+	}
+	s = s.Normalize()
+	if err := s.Validate(); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf(format, "marshal", err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf(format, "begin tx", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	const remove = "DELETE FROM file_emulators WHERE file_id = $1"
+	const upsert = "INSERT INTO file_emulators (file_id, settings) VALUES ($1, $2) " +
+		"ON CONFLICT (file_id) DO UPDATE SET settings = EXCLUDED.settings"
+	if s.Empty() {
+		_, err = tx.ExecContext(ctx, remove, id)
+	} else {
+		_, err = tx.ExecContext(ctx, upsert, id, b)
+	}
+	if err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf(format, "upsert", err)
+	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf(format, "tx commit", err)
+	}
+	return nil
+}
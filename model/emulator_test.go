@@ -0,0 +1,17 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/Defacto2/server/handler/jsdos"
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestEmulatorNil(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	_, err := model.EmulatorSettings(ctx, nil, 1)
+	be.Err(t, err)
+	be.Err(t, model.UpdateEmulatorSettings(ctx, nil, 1, jsdos.Settings{}))
+}
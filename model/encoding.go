@@ -0,0 +1,342 @@
+package model
+
+// Package file encoding.go contains the database queries for the text encodings of the artifacts,
+// both the overrides chosen by the editors and the encodings detected from the texts.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/Defacto2/server/handler/render"
+	"github.com/Defacto2/server/internal/encodings"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/null/v8"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+)
+
+// EncodingReview is an artifact text with a low confidence detection of its encoding.
+type EncodingReview struct {
+	ID          int64       `boil:"id"`           // ID of the artifact.
+	UUID        null.String `boil:"uuid"`         // UUID of the artifact.
+	Filename    null.String `boil:"filename"`     // Filename of the artifact download.
+	RecordTitle null.String `boil:"record_title"` // RecordTitle of the artifact.
+	Platform    null.String `boil:"platform"`     // Platform of the artifact.
+	Detected    string      `boil:"detected"`     // Detected is the key of the detected text encoding.
+	Confidence  int         `boil:"confidence"`   // Confidence percentage of the detection.
+}
+
+// Encoding returns the key of the text encoding chosen by an editor for the artifact id,
+// or an empty string when the encoding is detected.
+func Encoding(ctx context.Context, exec boil.ContextExecutor, id int64) (string, error) {
+	const msg = "encoding"
+	if err := nils.Check(ctx, exec); err != nil {
+		return "", fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT encoding FROM file_encodings WHERE file_id = $1"
+	var key string
+	err := exec.QueryRowContext(ctx, query, id).Scan(&key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	return key, nil
+}
+
+// Detected returns the text encoding detected for the artifact id,
+// or an empty detection when the encoding has not been detected.
+func Detected(ctx context.Context, exec boil.ContextExecutor, id int64) (encodings.Detection, error) {
+	const msg = "detected"
+	if err := nils.Check(ctx, exec); err != nil {
+		return encodings.Detection{}, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT detected, confidence FROM file_encodings WHERE file_id = $1"
+	var d encodings.Detection
+	err := exec.QueryRowContext(ctx, query, id).Scan(&d.Key, &d.Confidence)
+	if errors.Is(err, sql.ErrNoRows) {
+		return encodings.Detection{}, nil
+	}
+	if err != nil {
+		return encodings.Detection{}, fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	return d, nil
+}
+
+// EncodingByUUID returns the key of the text encoding chosen by an editor for the artifact unid,
+// or an empty string when the encoding is detected.
+func EncodingByUUID(ctx context.Context, exec boil.ContextExecutor, unid string) (string, error) {
+	const msg = "encoding by uuid"
+	if err := nils.Check(ctx, exec); err != nil {
+		return "", fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT file_encodings.encoding FROM file_encodings " +
+		"INNER JOIN files ON files.id = file_encodings.file_id WHERE files.uuid = $1"
+	var key string
+	err := exec.QueryRowContext(ctx, query, unid).Scan(&key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("%s %q: %w", msg, unid, err)
+	}
+	return key, nil
+}
+
+// Encodings returns the keys of the text encodings chosen by the editors, keyed by the artifact id.
+func Encodings(ctx context.Context, exec boil.ContextExecutor) (map[int64]string, error) {
+	nils.BoilExecCrash(exec)
+	const query = "SELECT file_id, encoding FROM file_encodings WHERE encoding <> ''"
+	var rows []struct {
+		FileID   int64  `boil:"file_id"`
+		Encoding string `boil:"encoding"`
+	}
+	if err := queries.Raw(query).Bind(ctx, exec, &rows); err != nil {
+		return nil, fmt.Errorf("encodings: %w", err)
+	}
+	m := make(map[int64]string, len(rows))
+	for _, r := range rows {
+		m[r.FileID] = r.Encoding
+	}
+	return m, nil
+}
+
+// UpdateEncoding sets the key of the text encoding of the artifact id chosen by an editor,
+// where an empty key reverts to the detected encoding. The indexed texts of the artifact are
+// removed, so they are indexed again using the new encoding.
+func UpdateEncoding(ctx context.Context, db *sql.DB, id int64, key string) error {
+	const msg = "update encoding"
+	const format = msg + " %s: %w"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf(format, "check", err)
+	}
+	if !encodings.Valid(key) {
+		return fmt.Errorf("%s: %w: %q", msg, encodings.ErrCharset, key)
+	}
+	if c, ok := encodings.Find(key); ok {
+		key = c.Key
+	} else {
+		key = ""
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf(format, "begin tx", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	const upsert = "INSERT INTO file_encodings (file_id, encoding) VALUES ($1, $2) " +
+		"ON CONFLICT (file_id) DO UPDATE SET encoding = EXCLUDED.encoding"
+	if _, err := tx.ExecContext(ctx, upsert, id, key); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf(format, "upsert", err)
+	}
+	const reindex = "DELETE FROM file_texts WHERE file_id = $1"
+	if _, err := tx.ExecContext(ctx, reindex, id); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf(format, "texts", err)
+	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf(format, "tx commit", err)
+	}
+	return nil
+}
+
+// SaveDetection saves the detected text encoding of the artifact id, without modifying any
+// encoding chosen by an editor. The stamp should be the last modified time of the detected text.
+func SaveDetection(ctx context.Context, exec boil.ContextExecutor,
+	id int64, stamp time.Time, d encodings.Detection,
+) error {
+	const msg = "save detection"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if id < 1 {
+		return fmt.Errorf("%s: %w: %d", msg, ErrID, id)
+	}
+	const upsert = "INSERT INTO file_encodings (file_id, detected, confidence, stamp) VALUES ($1, $2, $3, $4) " +
+		"ON CONFLICT (file_id) DO UPDATE SET detected = EXCLUDED.detected, " +
+		"confidence = EXCLUDED.confidence, stamp = EXCLUDED.stamp"
+	if _, err := exec.ExecContext(ctx, upsert, id, d.Key, d.Confidence, stamp); err != nil {
+		return fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	return nil
+}
+
+// DetectionStamps returns the last modified times of the texts when their encodings were detected,
+// keyed by the artifact id.
+func DetectionStamps(ctx context.Context, exec boil.ContextExecutor) (map[int64]time.Time, error) {
+	nils.BoilExecCrash(exec)
+	const query = "SELECT file_id, stamp AS modified FROM file_encodings WHERE stamp IS NOT NULL"
+	var stamps []TextStamp
+	if err := queries.Raw(query).Bind(ctx, exec, &stamps); err != nil {
+		return nil, fmt.Errorf("detection stamps: %w", err)
+	}
+	m := make(map[int64]time.Time, len(stamps))
+	for _, s := range stamps {
+		m[s.FileID] = s.Modified
+	}
+	return m, nil
+}
+
+// IndexEncodings walks the artifacts and detects the text encodings of the texts that are new or
+// have been modified since they were last detected. The text is either the readme copy in the
+// extra directory or the artifact download when it is displayed in the text viewer.
+// It returns the number of texts detected and the number skipped as unmodified.
+func IndexEncodings(ctx context.Context, exec boil.ContextExecutor, download, extra string) (int, int, error) {
+	nils.BoilExecCrash(exec)
+	const msg = "index encodings"
+	indexed, skipped := 0, 0
+	stamps, err := DetectionStamps(ctx, exec)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	arts, err := models.Files(
+		encodingSource(),
+		qm.Where(models.FileColumns.UUID+" IS NOT NULL"),
+		qm.WithDeleted(),
+		qm.OrderBy(models.FileColumns.ID)).All(ctx, exec)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	for _, art := range arts {
+		if err := ctx.Err(); err != nil {
+			return indexed, skipped, fmt.Errorf("%s: %w", msg, err)
+		}
+		src, stamp, ok := encodingText(art, download, extra)
+		if !ok {
+			continue
+		}
+		if last, ok := stamps[art.ID]; ok && !stamp.After(last) {
+			skipped++
+			continue
+		}
+		d, err := detect(src, art)
+		if err != nil {
+			continue
+		}
+		if err := SaveDetection(ctx, exec, art.ID, stamp, d); err != nil {
+			return indexed, skipped, fmt.Errorf("%s: %w", msg, err)
+		}
+		indexed++
+	}
+	return indexed, skipped, nil
+}
+
+// DetectEncoding detects and saves the text encoding of the text of the artifact id,
+// such as after a new or a replacement upload. The text is either the readme copy in the
+// extra directory or the artifact download when it is displayed in the text viewer.
+// Artifacts without a text are ignored.
+func DetectEncoding(ctx context.Context, exec boil.ContextExecutor, id int64, download, extra string) error {
+	const msg = "detect encoding"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	art, err := models.Files(
+		encodingSource(),
+		models.FileWhere.ID.EQ(id),
+		qm.WithDeleted()).One(ctx, exec)
+	if err != nil {
+		return fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	src, stamp, ok := encodingText(art, download, extra)
+	if !ok {
+		return nil
+	}
+	d, err := detect(src, art)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if err := SaveDetection(ctx, exec, id, stamp, d); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return nil
+}
+
+func encodingSource() qm.QueryMod {
+	return qm.Select(
+		models.FileColumns.ID,
+		models.FileColumns.UUID,
+		models.FileColumns.Filename,
+		models.FileColumns.Platform,
+		models.FileColumns.Section)
+}
+
+// encodingText returns the named text of the artifact that is used to detect the encoding,
+// and its last modified time, or false when the artifact has no text.
+func encodingText(art *models.File, download, extra string) (string, time.Time, bool) {
+	src := filepath.Join(extra, art.UUID.String+".txt")
+	st, err := os.Stat(src)
+	if extra == "" || err != nil {
+		if !render.Viewer(art) {
+			return "", time.Time{}, false
+		}
+		src = filepath.Join(download, art.UUID.String)
+		if st, err = os.Stat(src); err != nil {
+			return "", time.Time{}, false
+		}
+	}
+	if st.Size() == 0 {
+		return "", time.Time{}, false
+	}
+	// the database stores timestamps to the microsecond
+	return src, st.ModTime().Truncate(time.Microsecond), true
+}
+
+// detect returns the text encoding of the named text of the artifact.
+// The encoding used by the platform or section of the artifact has a full confidence.
+func detect(name string, art *models.File) (encodings.Detection, error) {
+	if key, ok := encodings.Rule(art.Platform.String, art.Section.String); ok {
+		const full = 100
+		return encodings.Detection{Key: key, Confidence: full}, nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return encodings.Detection{}, fmt.Errorf("detect: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	b, err := io.ReadAll(io.LimitReader(f, encodings.Sample))
+	if err != nil {
+		return encodings.Detection{}, fmt.Errorf("detect %q: %w", name, err)
+	}
+	return encodings.Detect(b), nil
+}
+
+// LowEncodings returns the public artifacts with a low confidence detection of their text encoding
+// that have not been given an encoding by an editor, ordered by the least confident.
+// The total number of these artifacts is also returned.
+func LowEncodings(ctx context.Context, exec boil.ContextExecutor, limit int) ([]EncodingReview, int, error) {
+	const msg = "low encodings"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	if limit < 1 || limit > Maximum {
+		limit = Maximum
+	}
+	const where = "FROM file_encodings INNER JOIN files ON files.id = file_encodings.file_id " +
+		"WHERE file_encodings.encoding = '' AND file_encodings.detected <> '' " +
+		"AND file_encodings.confidence < $1 AND files." + ClauseNoSoftDel
+	var total int
+	if err := exec.QueryRowContext(ctx, "SELECT COUNT(*) "+where, encodings.Low).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("%s count: %w", msg, err)
+	}
+	const query = "SELECT files.id, files.uuid, files.filename, files.record_title, files.platform, " +
+		"file_encodings.detected, file_encodings.confidence " + where +
+		" ORDER BY file_encodings.confidence ASC, files.id ASC LIMIT $2"
+	reviews := []EncodingReview{}
+	if err := queries.Raw(query, encodings.Low, limit).Bind(ctx, exec, &reviews); err != nil {
+		return nil, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	return reviews, total, nil
+}
@@ -0,0 +1,26 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/internal/encodings"
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestEncodingNil(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	_, err := model.Encoding(ctx, nil, 1)
+	be.Err(t, err)
+	_, err = model.Detected(ctx, nil, 1)
+	be.Err(t, err)
+	_, err = model.EncodingByUUID(ctx, nil, "")
+	be.Err(t, err)
+	be.Err(t, model.UpdateEncoding(ctx, nil, 1, encodings.CP437))
+	be.Err(t, model.SaveDetection(ctx, nil, 1, time.Now(), encodings.Detection{}))
+	be.Err(t, model.DetectEncoding(ctx, nil, 1, "", ""))
+	_, _, err = model.LowEncodings(ctx, nil, 1)
+	be.Err(t, err)
+}
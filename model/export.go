@@ -0,0 +1,58 @@
+package model
+
+// Package file export.go contains the database query for the catalogue export of the public artifacts.
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+)
+
+// Export streams the public artifacts that match the query mods to the yield function,
+// ordered by the id key, and returns the number of yielded artifacts.
+// The rows are scanned one at a time, so the file records of the catalogue are never all held in memory.
+func Export(ctx context.Context, exec boil.ContextExecutor, yield func(*models.File) error,
+	mods ...qm.QueryMod,
+) (int, error) {
+	const msg = "export"
+	if err := nils.Check(ctx, exec, yield); err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	mods = append(mods, qm.OrderBy(models.FileColumns.ID+" ASC"))
+	rows, err := models.Files(mods...).QueryContext(ctx, exec)
+	if err != nil {
+		return 0, fmt.Errorf("%s query: %w", msg, err)
+	}
+	defer func() { _ = rows.Close() }()
+	cols, err := rows.Columns()
+	if err != nil {
+		return 0, fmt.Errorf("%s columns: %w", msg, err)
+	}
+	typ := reflect.TypeFor[models.File]()
+	mapping, err := queries.BindMapping(typ, queries.MakeStructMapping(typ), cols)
+	if err != nil {
+		return 0, fmt.Errorf("%s mapping: %w", msg, err)
+	}
+	count := 0
+	for rows.Next() {
+		var f models.File
+		ptrs := queries.PtrsFromMapping(reflect.ValueOf(&f).Elem(), mapping)
+		if err := rows.Scan(ptrs...); err != nil {
+			return count, fmt.Errorf("%s scan: %w", msg, err)
+		}
+		if err := yield(&f); err != nil {
+			return count, fmt.Errorf("%s %d: %w", msg, f.ID, err)
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return count, fmt.Errorf("%s rows: %w", msg, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,61 @@
+package model
+
+// Package file feed.go contains the database queries for the syndication feeds of the artifacts.
+
+import (
+	"context"
+	"strings"
+
+	namer "github.com/Defacto2/server/handler/releaser/name"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/null/v8"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+)
+
+// FeedLimit is the maximum number of artifacts in a syndication feed.
+const FeedLimit = 50
+
+// Feed returns up to the limit of the public artifacts that match the query mods,
+// with the most recently added artifacts first. When updated is true the most
+// recently updated artifacts are listed first.
+func Feed(ctx context.Context, exec boil.ContextExecutor, limit int, updated bool, mods ...qm.QueryMod) (
+	models.FileSlice, error,
+) {
+	nils.BoilExecCrash(exec)
+	if limit < 1 || limit > FeedLimit {
+		limit = FeedLimit
+	}
+	order := models.FileColumns.ID + " DESC"
+	if updated {
+		order = models.FileColumns.Updatedat + " DESC NULLS LAST, " + order
+	}
+	mods = append(mods, qm.OrderBy(order), qm.Limit(limit))
+	return models.Files(mods...).All(ctx, exec)
+}
+
+// FeedReleaser returns the most recently added public artifacts of the named releaser.
+// The name can be a releaser URI, such as "razor-1911".
+func FeedReleaser(ctx context.Context, exec boil.ContextExecutor, name string, limit int) (
+	models.FileSlice, error,
+) {
+	nils.BoilExecCrash(exec)
+	s, _ := namer.Humanize(namer.Path(name))
+	if s == "" {
+		return nil, nil
+	}
+	x := null.StringFrom(strings.ToUpper(s))
+	return Feed(ctx, exec, limit, false,
+		qm.Where("upper(group_brand_for) = ? OR upper(group_brand_by) = ?", x, x))
+}
+
+// FeedScener returns the most recently added public artifacts that have been credited to the named scener.
+func FeedScener(ctx context.Context, exec boil.ContextExecutor, name string, limit int) (
+	models.FileSlice, error,
+) {
+	nils.BoilExecCrash(exec)
+	query, params := postgres.ScenerSQL(name)
+	return Feed(ctx, exec, limit, false, qm.Where(query, params...))
+}
@@ -44,18 +44,6 @@ func (a *Advert) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, a)
 }
 
-func (a *Advert) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.AdvertExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // Announcement is the model for the public and community announcements.
 type Announcement struct {
 	Bytes   int `boil:"size_total"`
@@ -74,18 +62,6 @@ func (a *Announcement) Stat(ctx context.Context, exec boil.ContextExecutor) erro
 	).Bind(ctx, exec, a)
 }
 
-func (a *Announcement) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.AnnouncementExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // Ansi is the model for the ANSI formatted text and art files.
 type Ansi struct {
 	Bytes   int `boil:"size_total"`
@@ -108,18 +84,6 @@ func (a *Ansi) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, a)
 }
 
-func (a *Ansi) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.AnsiExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // AnsiBrand is the model for the brand logos created in ANSI text.
 type AnsiBrand struct {
 	Bytes   int `boil:"size_total"`
@@ -138,18 +102,6 @@ func (a *AnsiBrand) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, a)
 }
 
-func (a *AnsiBrand) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.AnsiBrandExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // AnsiBBS is the model for the BBS advertisements created in ANSI text.
 type AnsiBBS struct {
 	Bytes   int `boil:"size_total"`
@@ -172,18 +124,6 @@ func (a *AnsiBBS) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, a)
 }
 
-func (a *AnsiBBS) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.AnsiBBSExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // AnsiFTP is the model for the FTP advertisements created in ANSI text.
 type AnsiFTP struct {
 	Bytes   int `boil:"size_total"`
@@ -202,18 +142,6 @@ func (a *AnsiFTP) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, a)
 }
 
-func (a *AnsiFTP) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.AnsiFTPExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // AnsiNfo is the model for the NFO files created in ANSI text.
 type AnsiNfo struct {
 	Bytes   int `boil:"size_total"`
@@ -232,18 +160,6 @@ func (a *AnsiNfo) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, a)
 }
 
-func (a *AnsiNfo) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.AnsiNfoExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // AnsiPack is the model for the ANSI file packs.
 type AnsiPack struct {
 	Bytes   int `boil:"size_total"`
@@ -262,18 +178,6 @@ func (a *AnsiPack) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, a)
 }
 
-func (a *AnsiPack) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.AnsiPackExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // BBS is the model for the Bulletin Board System files.
 type BBS struct {
 	Bytes   int `boil:"size_total"`
@@ -296,18 +200,6 @@ func (b *BBS) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, b)
 }
 
-func (b *BBS) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.BBSExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // BBStro is the model for the Bulletin Board System intro files.
 type BBStro struct {
 	Bytes   int `boil:"size_total"`
@@ -330,12 +222,14 @@ func (b *BBStro) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, b)
 }
 
-func (b *BBStro) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
+// CategoryFiles returns the public artifacts that match the category query mod expression,
+// ordered by the oldest date.
+func CategoryFiles(ctx context.Context, exec boil.ContextExecutor, offset, limit int, expr qm.QueryMod) (
 	models.FileSlice, error,
 ) {
 	nils.BoilExecCrash(exec)
 	return models.Files(
-		querymod.BBStroExpr(),
+		expr,
 		qm.Offset(calc(offset, limit)),
 		qm.Limit(limit),
 		qm.OrderBy(ClauseOldDate),
@@ -373,18 +267,6 @@ func (b *BBSImage) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, b)
 }
 
-func (b *BBSImage) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.BBSImageExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // BBSText is the model for the Bulletin Board System text files.
 type BBSText struct {
 	Bytes   int `boil:"size_total"`
@@ -407,18 +289,6 @@ func (b *BBSText) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, b)
 }
 
-func (b *BBSText) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.BBSTextExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Console is the model for console releases.
 type Console struct {
 	Bytes   int `boil:"size_total"`
@@ -441,18 +311,6 @@ func (c *Console) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, c)
 }
 
-func (c *Console) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.ConsoleExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Database is the model for the database releases.
 type Database struct {
 	Bytes   int `boil:"size_total"`
@@ -471,18 +329,6 @@ func (d *Database) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, d)
 }
 
-func (d *Database) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.DatabaseExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Demoscene is the model for the demoscene releases.
 type Demoscene struct {
 	Bytes   int `boil:"size_total"`
@@ -505,18 +351,6 @@ func (d *Demoscene) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, d)
 }
 
-func (d *Demoscene) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.DemoExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Drama is the model for community drama.
 type Drama struct {
 	Bytes   int `boil:"size_total"`
@@ -535,18 +369,6 @@ func (d *Drama) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, d)
 }
 
-func (d *Drama) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.DramaExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // FTP is the model for the FTP files.
 type FTP struct {
 	Bytes   int `boil:"size_total"`
@@ -565,18 +387,6 @@ func (f *FTP) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, f)
 }
 
-func (f *FTP) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.FTPExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Hack is the model for the game hacks.
 type Hack struct {
 	Bytes   int `boil:"size_total"`
@@ -595,18 +405,6 @@ func (h *Hack) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, h)
 }
 
-func (h *Hack) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.HackExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // HowTo is the model for the guides and how-to texts.
 type HowTo struct {
 	Bytes   int `boil:"size_total"`
@@ -625,18 +423,6 @@ func (h *HowTo) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, h)
 }
 
-func (h *HowTo) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.HowToExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // HTML is the model for the HTML and markdown files.
 type HTML struct {
 	Bytes   int `boil:"size_total"`
@@ -655,18 +441,6 @@ func (h *HTML) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, h)
 }
 
-func (h *HTML) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.HTMLExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Image is the model for the images.
 type Image struct {
 	Bytes   int `boil:"size_total"`
@@ -685,18 +459,6 @@ func (i *Image) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, i)
 }
 
-func (i *Image) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.ImageExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // ImagePack is the model for the image file packs.
 type ImagePack struct {
 	Bytes   int `boil:"size_total"`
@@ -715,18 +477,6 @@ func (i *ImagePack) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, i)
 }
 
-func (i *ImagePack) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.ImagePackExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Intro contain statistics for releases that could be considered intros or cracktros.
 type Intro struct {
 	Bytes   int `boil:"size_total"`
@@ -749,18 +499,6 @@ func (i *Intro) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, i)
 }
 
-func (i *Intro) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.IntroExpr(),
-		qm.OrderBy(ClauseOldDate),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // IntroMsDos contain statistics for releases that could be considered DOS intros or cracktros.
 type IntroMsDos struct {
 	Bytes   int `boil:"size_total"`
@@ -783,18 +521,6 @@ func (i *IntroMsDos) Stat(ctx context.Context, exec boil.ContextExecutor) error
 	).Bind(ctx, exec, i)
 }
 
-func (i *IntroMsDos) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.IntroDOSExpr(),
-		qm.OrderBy(ClauseOldDate),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // IntroWindows contain statistics for releases that could be considered Windows intros or cracktros.
 type IntroWindows struct {
 	Cache   time.Time
@@ -818,18 +544,6 @@ func (i *IntroWindows) Stat(ctx context.Context, exec boil.ContextExecutor) erro
 	).Bind(ctx, exec, i)
 }
 
-func (i *IntroWindows) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.IntroWindowsExpr(),
-		qm.OrderBy(ClauseOldDate),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // Installer contain statistics for releases that could be considered installers.
 type Installer struct {
 	Bytes   int `boil:"size_total"`
@@ -852,18 +566,6 @@ func (i *Installer) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, i)
 }
 
-func (i *Installer) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.InstallExpr(),
-		qm.OrderBy(ClauseOldDate),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // Java is the model for the Java operating system.
 type Java struct {
 	Bytes   int `boil:"size_total"`
@@ -886,18 +588,6 @@ func (j *Java) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, j)
 }
 
-func (j *Java) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.JavaExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // JobAdvert is the model for group job advertisements.
 type JobAdvert struct {
 	Bytes   int `boil:"size_total"`
@@ -916,18 +606,6 @@ func (j *JobAdvert) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, j)
 }
 
-func (j *JobAdvert) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.JobAdvertExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // Linux is the model for the Linux operating system.
 type Linux struct {
 	Bytes   int `boil:"size_total"`
@@ -950,18 +628,6 @@ func (l *Linux) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, l)
 }
 
-func (l *Linux) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.LinuxExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Magazine is the model for the magazine files.
 type Magazine struct {
 	Bytes   int `boil:"size_total"`
@@ -984,18 +650,6 @@ func (m *Magazine) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, m)
 }
 
-func (m *Magazine) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.MagExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Macos is the model for the Macintosh operating system.
 type Macos struct {
 	Bytes   int `boil:"size_total"`
@@ -1018,18 +672,6 @@ func (m *Macos) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, m)
 }
 
-func (m *Macos) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.MacExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // MsDos is the model for the MS-DOS operating system.
 type MsDos struct {
 	Bytes   int `boil:"size_total"`
@@ -1052,18 +694,6 @@ func (d *MsDos) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, d)
 }
 
-func (d *MsDos) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.DOSExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // MsDosPack is the model for the DOS file packs.
 type MsDosPack struct {
 	Bytes   int `boil:"size_total"`
@@ -1082,18 +712,6 @@ func (d *MsDosPack) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, d)
 }
 
-func (d *MsDosPack) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.DosPackExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Music is the model for the music.
 type Music struct {
 	Bytes   int `boil:"size_total"`
@@ -1112,18 +730,6 @@ func (m *Music) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, m)
 }
 
-func (m *Music) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.MusicExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // NewsArticle is the model for mainstream news articles.
 type NewsArticle struct {
 	Bytes   int `boil:"size_total"`
@@ -1142,18 +748,6 @@ func (n *NewsArticle) Stat(ctx context.Context, exec boil.ContextExecutor) error
 	).Bind(ctx, exec, n)
 }
 
-func (n *NewsArticle) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.NewsArticleExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // Nfo is the model for the NFO files.
 type Nfo struct {
 	Bytes   int `boil:"size_total"`
@@ -1176,18 +770,6 @@ func (n *Nfo) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, n)
 }
 
-func (n *Nfo) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.NfoExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // NfoTool is the model for the NFO tools.
 type NfoTool struct {
 	Bytes   int `boil:"size_total"`
@@ -1210,18 +792,6 @@ func (n *NfoTool) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, n)
 }
 
-func (n *NfoTool) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.NfoToolExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // PCBoard is the model for PCBoard platform which can include text files and applications.
 type PCBoard struct {
 	Bytes   int `boil:"size_total"`
@@ -1240,18 +810,6 @@ func (p *PCBoard) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, p)
 }
 
-func (p *PCBoard) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.PCBoardExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // PCBoardPPE is the model for PCBoard applications.
 type PCBoardPPE struct {
 	Bytes   int `boil:"size_total"`
@@ -1270,18 +828,6 @@ func (p *PCBoardPPE) Stat(ctx context.Context, exec boil.ContextExecutor) error
 	).Bind(ctx, exec, p)
 }
 
-func (p *PCBoardPPE) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.PCBoardPPEExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // PCBoardText is the model for PCBoard applications.
 type PCBoardText struct {
 	Bytes   int `boil:"size_total"`
@@ -1300,18 +846,6 @@ func (p *PCBoardText) Stat(ctx context.Context, exec boil.ContextExecutor) error
 	).Bind(ctx, exec, p)
 }
 
-func (p *PCBoardText) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.PCBoardTextExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // PDF is the model for the documents in PDF format.
 type PDF struct {
 	Bytes   int `boil:"size_total"`
@@ -1330,18 +864,6 @@ func (p *PDF) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, p)
 }
 
-func (p *PDF) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.PDFExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Proof is the model for the file proofs.
 type Proof struct {
 	Bytes   int `boil:"size_total"`
@@ -1364,18 +886,6 @@ func (p *Proof) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, p)
 }
 
-func (p *Proof) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.ProofExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 type Restrict struct {
 	Bytes   int `boil:"size_total"`
 	Count   int `boil:"count_total"`
@@ -1393,18 +903,6 @@ func (r *Restrict) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, r)
 }
 
-func (r *Restrict) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.RestrictExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // Script is the model for the script and interpreted languages.
 type Script struct {
 	Bytes   int `boil:"size_total"`
@@ -1427,18 +925,6 @@ func (s *Script) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, s)
 }
 
-func (s *Script) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.ScriptExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Standard is the model for community standards.
 type Standard struct {
 	Bytes   int `boil:"size_total"`
@@ -1457,18 +943,6 @@ func (s *Standard) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, s)
 }
 
-func (s *Standard) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.StandardExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // Takedown is the model for the bust and take downs.
 type Takedown struct {
 	Bytes   int `boil:"size_total"`
@@ -1487,18 +961,6 @@ func (t *Takedown) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, t)
 }
 
-func (t *Takedown) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.TakedownExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // Text is the model for the text files.
 type Text struct {
 	Bytes   int `boil:"size_total"`
@@ -1521,18 +983,6 @@ func (t *Text) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, t)
 }
 
-func (t *Text) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.TextExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // TextAmiga is the model for the text files for the Amiga operating system.
 type TextAmiga struct {
 	Bytes   int `boil:"size_total"`
@@ -1551,18 +1001,6 @@ func (t *TextAmiga) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, t)
 }
 
-func (t *TextAmiga) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.TextAmigaExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // TextApple2 is the model for the text files about the Apple II microcomputer.
 type TextApple2 struct {
 	Bytes   int `boil:"size_total"`
@@ -1581,18 +1019,6 @@ func (t *TextApple2) Stat(ctx context.Context, exec boil.ContextExecutor) error
 	).Bind(ctx, exec, t)
 }
 
-func (t *TextApple2) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.AppleIIExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // TextAtariST is the model for the text files about the Atari ST microcomputer.
 type TextAtariST struct {
 	Bytes   int `boil:"size_total"`
@@ -1611,18 +1037,6 @@ func (t *TextAtariST) Stat(ctx context.Context, exec boil.ContextExecutor) error
 	).Bind(ctx, exec, t)
 }
 
-func (t *TextAtariST) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.AtariSTExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // TextPack is the model for the text file packs.
 type TextPack struct {
 	Bytes   int `boil:"size_total"`
@@ -1641,18 +1055,6 @@ func (t *TextPack) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, t)
 }
 
-func (t *TextPack) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.TextPackExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // Tool is the model for the computer tools.
 type Tool struct {
 	Bytes   int `boil:"size_total"`
@@ -1671,18 +1073,6 @@ func (t *Tool) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, t)
 }
 
-func (t *Tool) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.ToolExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // TrialCrackme is the model for group job trial "crackme" releases.
 type TrialCrackme struct {
 	Bytes   int `boil:"size_total"`
@@ -1701,18 +1091,6 @@ func (t *TrialCrackme) Stat(ctx context.Context, exec boil.ContextExecutor) erro
 	).Bind(ctx, exec, t)
 }
 
-func (t *TrialCrackme) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.TrialCrackmeExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // Video is the model for the videos.
 type Video struct {
 	Bytes   int `boil:"size_total"`
@@ -1731,18 +1109,6 @@ func (v *Video) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, v)
 }
 
-func (v *Video) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.VideoExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.OrderBy(ClauseOldDate),
-		qm.Limit(limit),
-	).All(ctx, exec)
-}
-
 // Windows is the model for the Windows operating system.
 type Windows struct {
 	Bytes   int `boil:"size_total"`
@@ -1765,18 +1131,6 @@ func (w *Windows) Stat(ctx context.Context, exec boil.ContextExecutor) error {
 	).Bind(ctx, exec, w)
 }
 
-func (w *Windows) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		querymod.WindowsExpr(),
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
-
 // WindowsPack is the model for the Windows file packs.
 type WindowsPack struct {
 	Bytes   int `boil:"size_total"`
@@ -1794,15 +1148,3 @@ func (w *WindowsPack) Stat(ctx context.Context, exec boil.ContextExecutor) error
 		qm.From(From),
 	).Bind(ctx, exec, w)
 }
-
-func (w *WindowsPack) List(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
-	models.FileSlice, error,
-) {
-	nils.BoilExecCrash(exec)
-	return models.Files(
-		qm.Offset(calc(offset, limit)),
-		qm.Limit(limit),
-		querymod.WindowsPackExpr(),
-		qm.OrderBy(ClauseOldDate),
-	).All(ctx, exec)
-}
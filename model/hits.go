@@ -0,0 +1,141 @@
+package model
+
+// Package file hits.go contains the database queries for the daily totals of the
+// artifact downloads, emulations and page views.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Defacto2/server/internal/hits"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+)
+
+// PopularDays is the number of days of hits used to order the artifacts by their popularity.
+const PopularDays = 30
+
+// Trend is the total hits of all the artifacts for a day.
+type Trend struct {
+	Day        time.Time `boil:"day"`        // Day of the hits.
+	Downloads  int64     `boil:"downloads"`  // Downloads of the artifacts.
+	Emulations int64     `boil:"emulations"` // Emulations of the artifacts in the browser.
+	Views      int64     `boil:"views"`      // Views of the artifact pages.
+}
+
+// Total returns the sum of the hits.
+func (t Trend) Total() int64 {
+	return t.Downloads + t.Emulations + t.Views
+}
+
+// Popularity is the total hits of an artifact over a number of days.
+type Popularity struct {
+	ID         int64  `boil:"id"`         // ID of the artifact.
+	Filename   string `boil:"filename"`   // Filename of the artifact.
+	Title      string `boil:"title"`      // Title of the artifact.
+	Downloads  int64  `boil:"downloads"`  // Downloads of the artifact.
+	Emulations int64  `boil:"emulations"` // Emulations of the artifact in the browser.
+	Views      int64  `boil:"views"`      // Views of the artifact page.
+	Total      int64  `boil:"total"`      // Total of all the hits.
+}
+
+// SaveHits adds the counts to the daily totals of the day.
+// The counts of the artifacts that no longer exist are ignored.
+// All the counts are saved in a single transaction, so on an error none of the counts are saved.
+// It is used as the flush function of the hits counter.
+func SaveHits(ctx context.Context, db *sql.DB, day time.Time, counts map[hits.Key]int64) error {
+	const msg = "save hits"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	const upsert = "INSERT INTO file_hits (file_id, day, kind, count) " +
+		"SELECT id, $2, $3, $4 FROM files WHERE id = $1 " +
+		"ON CONFLICT (file_id, day, kind) DO UPDATE SET count = file_hits.count + EXCLUDED.count"
+	date := day.UTC().Format(time.DateOnly)
+	for key, n := range counts {
+		if n < 1 || !key.Kind.Valid() {
+			continue
+		}
+		if _, err := tx.ExecContext(ctx, upsert, key.ID, date, string(key.Kind), n); err != nil {
+			return fmt.Errorf("%s %d: %w", msg, key.ID, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
+	return nil
+}
+
+// ByPopular returns the public files ordered by their hits over the last PopularDays,
+// with the files without any hits ordered by the ID, key column.
+func (f *Artifacts) ByPopular(ctx context.Context, exec boil.ContextExecutor, offset, limit int) (
+	models.FileSlice, error,
+) {
+	nils.BoilExecCrash(exec)
+	if err := f.Public(ctx, exec); err != nil {
+		return nil, fmt.Errorf("by popular artifacts.public: %w", err)
+	}
+	join := fmt.Sprintf("(SELECT file_id, SUM(count) AS hits FROM file_hits "+
+		"WHERE day > CURRENT_DATE - %d GROUP BY file_id) AS popular ON popular.file_id = files.id", PopularDays)
+	const clause = "COALESCE(popular.hits, 0) DESC, files.id DESC"
+	return models.Files(
+		qm.LeftOuterJoin(join),
+		qm.Where(ClauseNoSoftDel),
+		qm.OrderBy(clause),
+		qm.Offset(calc(offset, limit)),
+		qm.Limit(limit),
+	).All(ctx, exec)
+}
+
+// Trends returns the daily totals of the hits of all the artifacts over the number of days,
+// ordered by the most recent day.
+func Trends(ctx context.Context, exec boil.ContextExecutor, days int) ([]Trend, error) {
+	const msg = "trends"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT day, " +
+		"COALESCE(SUM(count) FILTER (WHERE kind = 'download'), 0) AS downloads, " +
+		"COALESCE(SUM(count) FILTER (WHERE kind = 'emulate'), 0) AS emulations, " +
+		"COALESCE(SUM(count) FILTER (WHERE kind = 'view'), 0) AS views " +
+		"FROM file_hits WHERE day > CURRENT_DATE - $1::int GROUP BY day ORDER BY day DESC"
+	var trends []Trend
+	if err := queries.Raw(query, days).Bind(ctx, exec, &trends); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	return trends, nil
+}
+
+// Popular returns the public artifacts with the most hits over the number of days.
+func Popular(ctx context.Context, exec boil.ContextExecutor, days, limit int) ([]Popularity, error) {
+	const msg = "popular"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT files.id, COALESCE(files.filename, '') AS filename, " +
+		"COALESCE(files.record_title, '') AS title, " +
+		"COALESCE(SUM(h.count) FILTER (WHERE h.kind = 'download'), 0) AS downloads, " +
+		"COALESCE(SUM(h.count) FILTER (WHERE h.kind = 'emulate'), 0) AS emulations, " +
+		"COALESCE(SUM(h.count) FILTER (WHERE h.kind = 'view'), 0) AS views, " +
+		"SUM(h.count) AS total " +
+		"FROM file_hits h JOIN files ON files.id = h.file_id " +
+		"WHERE h.day > CURRENT_DATE - $1::int AND files.deletedat IS NULL " +
+		"GROUP BY files.id ORDER BY total DESC, files.id DESC LIMIT $2"
+	var pop []Popularity
+	if err := queries.Raw(query, days, limit).Bind(ctx, exec, &pop); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	return pop, nil
+}
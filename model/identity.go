@@ -0,0 +1,429 @@
+package model
+
+// Package file identity.go contains the database queries for the scener identities.
+// The credit columns of the files table are comma separated strings of names, so a scener
+// identity links the names, or aliases, of a person to the artifacts they were credited for.
+// Two people using the same handle are told apart by using two identities with the same alias.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Defacto2/helper"
+	"github.com/Defacto2/server/handler/releaser"
+	namer "github.com/Defacto2/server/handler/releaser/name"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+)
+
+var ErrIdentity = errors.New("scener identity is invalid")
+
+// ScenerRole is the role of a scener credited for an artifact.
+type ScenerRole string
+
+const (
+	ScenerWriter   ScenerRole = "writer"   // ScenerWriter is credited in the credit_text column.
+	ScenerArtist   ScenerRole = "artist"   // ScenerArtist is credited in the credit_illustration column.
+	ScenerCoder    ScenerRole = "coder"    // ScenerCoder is credited in the credit_program column.
+	ScenerMusician ScenerRole = "musician" // ScenerMusician is credited in the credit_audio column.
+)
+
+// creditSelect is a SQL statement of the credited names of the files table, with their file id and role.
+// The alias column is the trimmed and uppercased name.
+const creditSelect = "SELECT f.id AS file_id, r.role, upper(trim(n)) AS alias, trim(n) AS name " +
+	"FROM files f CROSS JOIN LATERAL (VALUES " +
+	"('writer', f.credit_text), ('artist', f.credit_illustration), " +
+	"('coder', f.credit_program), ('musician', f.credit_audio)) AS r(role, val) " +
+	"CROSS JOIN LATERAL unnest(string_to_array(r.val, ',')) AS n " +
+	"WHERE r.val IS NOT NULL AND trim(n) <> ''"
+
+// creditNames is a SQL subquery of the credited names of all the files.
+const creditNames = "(" + creditSelect + ")"
+
+// creditNamesOf is a SQL subquery of the credited names of the file ids of the $1 argument.
+const creditNamesOf = "(" + creditSelect + " AND f.id = ANY ($1::bigint[]))"
+
+// Identity is a scener, a person who is credited for artifacts using one or more aliases.
+type Identity struct {
+	ID      int64    `boil:"id"`      // ID is the unique id of the scener.
+	Slug    string   `boil:"slug"`    // Slug is the partial URL path of the scener.
+	Name    string   `boil:"name"`    // Name is the display name of the scener.
+	Demozoo int      `boil:"demozoo"` // Demozoo is the Demozoo scener id, or 0.
+	Aliases []string `boil:"-"`       // Aliases are the uppercased names used in the credits.
+	Members []string `boil:"-"`       // Members are the releaser slugs of the groups the scener was a member of.
+}
+
+// OneIdentity returns the scener identity of the slug, including the aliases and group memberships.
+func OneIdentity(ctx context.Context, exec boil.ContextExecutor, slug string) (*Identity, error) {
+	const msg = "one identity"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	var s Identity
+	const query = "SELECT id, slug, name, demozoo FROM sceners WHERE slug = $1"
+	if err := queries.Raw(query, slug).Bind(ctx, exec, &s); err != nil {
+		return nil, fmt.Errorf("%s %q: %w", msg, slug, err)
+	}
+	var err error
+	s.Aliases, err = identityList(ctx, exec,
+		"SELECT alias AS value FROM scener_aliases WHERE scener_id = $1 ORDER BY alias", s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q aliases: %w", msg, slug, err)
+	}
+	s.Members, err = identityList(ctx, exec,
+		"SELECT releaser AS value FROM scener_members WHERE scener_id = $1 ORDER BY releaser", s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q members: %w", msg, slug, err)
+	}
+	return &s, nil
+}
+
+// identityList returns the string values of the query.
+func identityList(ctx context.Context, exec boil.ContextExecutor, query string, args ...any) ([]string, error) {
+	var rows []struct {
+		Value string `boil:"value"`
+	}
+	if err := queries.Raw(query, args...).Bind(ctx, exec, &rows); err != nil {
+		return nil, err
+	}
+	values := make([]string, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, row.Value)
+	}
+	return values, nil
+}
+
+// IdentitySlugs returns the slugs of the scener identities that use the alias of the URL path,
+// such as "mr-x" for the alias "MR X". More than one slug means the alias is shared by different people.
+func IdentitySlugs(ctx context.Context, exec boil.ContextExecutor, path string) ([]string, error) {
+	const msg = "identity slugs"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	alias := strings.ToUpper(releaser.Humanize(path))
+	if alias == "" {
+		return nil, nil
+	}
+	slugs, err := identityList(ctx, exec, "SELECT s.slug AS value FROM sceners s "+
+		"JOIN scener_aliases a ON a.scener_id = s.id WHERE a.alias = $1 ORDER BY s.slug", alias)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: %w", msg, path, err)
+	}
+	return slugs, nil
+}
+
+// Files returns the public artifacts that are credited to the scener.
+func (s Identity) Files(ctx context.Context, exec boil.ContextExecutor) (models.FileSlice, error) {
+	nils.BoilExecCrash(exec)
+	return models.Files(
+		qm.Where("id IN (SELECT file_id FROM scener_credits WHERE scener_id = ?)", s.ID),
+		qm.OrderBy(ClauseOldDate),
+	).All(ctx, exec)
+}
+
+// Releasers returns the uppercased names of the groups that the scener released public artifacts with.
+func (s Identity) Releasers(ctx context.Context, exec boil.ContextExecutor) ([]string, error) {
+	const msg = "identity releasers"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	names, err := identityList(ctx, exec, "SELECT DISTINCT upper(g) AS value FROM files "+
+		"CROSS JOIN LATERAL (VALUES (group_brand_for), (group_brand_by)) AS v(g) "+
+		"WHERE id IN (SELECT file_id FROM scener_credits WHERE scener_id = $1) "+
+		"AND deletedat IS NULL AND NULLIF(g, '') IS NOT NULL ORDER BY value", s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: %w", msg, s.Slug, err)
+	}
+	return names, nil
+}
+
+// Roles returns the roles of the scener that are credited in the public artifacts.
+func (s Identity) Roles(ctx context.Context, exec boil.ContextExecutor) ([]ScenerRole, error) {
+	const msg = "identity roles"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	values, err := identityList(ctx, exec, "SELECT DISTINCT c.role AS value FROM scener_credits c "+
+		"JOIN files f ON f.id = c.file_id WHERE c.scener_id = $1 AND f.deletedat IS NULL ORDER BY value", s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("%s %q: %w", msg, s.Slug, err)
+	}
+	roles := make([]ScenerRole, 0, len(values))
+	for _, value := range values {
+		roles = append(roles, ScenerRole(value))
+	}
+	return roles, nil
+}
+
+// Summary saves the summary statistics of the public artifacts that are credited to the scener.
+func (s Identity) Summary(ctx context.Context, exec boil.ContextExecutor, sum *Summary) error {
+	const msg = "identity summary"
+	if err := nils.Check(ctx, exec, sum); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT COUNT(*) AS count_total, SUM(filesize) AS size_total, " +
+		"MIN(date_issued_year) AS min_year, MAX(date_issued_year) AS max_year FROM files " +
+		"WHERE id IN (SELECT file_id FROM scener_credits WHERE scener_id = $1) AND deletedat IS NULL"
+	if err := queries.Raw(query, s.ID).Bind(ctx, exec, sum); err != nil {
+		return fmt.Errorf("%s %q: %w", msg, s.Slug, err)
+	}
+	return nil
+}
+
+// Valid returns an error if the slug, name or aliases of the scener identity are invalid.
+func (s Identity) Valid() error {
+	if !namer.Path(s.Slug).Valid() {
+		return fmt.Errorf("%w: %q", ErrSlug, s.Slug)
+	}
+	if strings.TrimSpace(s.Name) == "" {
+		return fmt.Errorf("%w: %q requires a name", ErrIdentity, s.Slug)
+	}
+	if len(s.Aliases) == 0 {
+		return fmt.Errorf("%w: %q requires an alias", ErrIdentity, s.Slug)
+	}
+	for _, member := range s.Members {
+		if !namer.Path(member).Valid() {
+			return fmt.Errorf("%w: %q", ErrSlug, member)
+		}
+	}
+	return nil
+}
+
+// SaveIdentity creates or replaces the scener identity using the slug, including the aliases and memberships.
+// The credits of the artifacts are linked to the identity using the [SyncIdentities] function.
+func SaveIdentity(ctx context.Context, db *sql.DB, s Identity) (int64, error) {
+	const msg = "save identity"
+	if err := nils.Check(ctx, db); err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	aliases := make([]string, 0, len(s.Aliases))
+	for _, alias := range s.Aliases {
+		if alias = strings.ToUpper(strings.TrimSpace(alias)); alias != "" {
+			aliases = append(aliases, alias)
+		}
+	}
+	slices.Sort(aliases)
+	s.Aliases = slices.Compact(aliases)
+	if err := s.Valid(); err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	const upsert = "INSERT INTO sceners (slug, name, demozoo) VALUES ($1, $2, $3) " +
+		"ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name, demozoo = EXCLUDED.demozoo, updated = now() " +
+		"RETURNING id"
+	var id int64
+	if err := tx.QueryRowContext(ctx, upsert, s.Slug, strings.TrimSpace(s.Name), s.Demozoo).Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s %q: %w", msg, s.Slug, err)
+	}
+	for _, stmt := range []struct {
+		query string
+		args  []any
+	}{
+		{"DELETE FROM scener_aliases WHERE scener_id = $1 AND NOT (alias = ANY ($2::text[]))", []any{id, s.Aliases}},
+		{"INSERT INTO scener_aliases (alias, scener_id) SELECT unnest($2::text[]), $1 ON CONFLICT DO NOTHING",
+			[]any{id, s.Aliases}},
+		{"DELETE FROM scener_members WHERE scener_id = $1", []any{id}},
+		{"INSERT INTO scener_members (scener_id, releaser) SELECT $1, unnest($2::text[]) ON CONFLICT DO NOTHING",
+			[]any{id, s.Members}},
+	} {
+		if _, err := tx.ExecContext(ctx, stmt.query, stmt.args...); err != nil {
+			return 0, fmt.Errorf("%s %q: %w", msg, s.Slug, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf(fmttx, msg, err)
+	}
+	return id, nil
+}
+
+// DeleteIdentity removes the scener identity of the slug, including the aliases, memberships and credits.
+// The aliases that are not used by another scener get a new identity on the next [SyncIdentities].
+func DeleteIdentity(ctx context.Context, exec boil.ContextExecutor, slug string) error {
+	const msg = "delete identity"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if _, err := exec.ExecContext(ctx, "DELETE FROM sceners WHERE slug = $1", slug); err != nil {
+		return fmt.Errorf("%s %q: %w", msg, slug, err)
+	}
+	return nil
+}
+
+// ClaimCredits links the credits of the artifact ids that use an alias of the scener to the scener,
+// replacing the links to any other scener that shares the alias.
+// It is used to tell apart the artifacts of two people that use the same handle.
+func ClaimCredits(ctx context.Context, db *sql.DB, scenerID int64, fileIDs ...int64) (int64, error) {
+	const msg = "claim credits"
+	if err := nils.Check(ctx, db); err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	if scenerID < 1 || len(fileIDs) == 0 {
+		return 0, fmt.Errorf("%s: %w", msg, ErrKey)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	const drop = "DELETE FROM scener_credits x USING " + creditNames + " c, scener_aliases a " +
+		"WHERE a.scener_id = $1 AND a.alias = c.alias AND c.file_id = ANY ($2::bigint[]) " +
+		"AND x.file_id = c.file_id AND x.role = c.role AND x.scener_id <> $1 " +
+		"AND EXISTS (SELECT 1 FROM scener_aliases b WHERE b.scener_id = x.scener_id AND b.alias = c.alias)"
+	if _, err := tx.ExecContext(ctx, drop, scenerID, fileIDs); err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	const claim = "INSERT INTO scener_credits (scener_id, file_id, role) " +
+		"SELECT DISTINCT $1::bigint, c.file_id, c.role FROM " + creditNames + " c " +
+		"JOIN scener_aliases a ON a.alias = c.alias AND a.scener_id = $1 " +
+		"WHERE c.file_id = ANY ($2::bigint[]) ON CONFLICT DO NOTHING"
+	res, err := tx.ExecContext(ctx, claim, scenerID, fileIDs)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf(fmttx, msg, err)
+	}
+	i, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	return i, nil
+}
+
+// IdentitySync is the outcome of the [SyncIdentities] function.
+type IdentitySync struct {
+	Sceners int64 // Sceners is the number of new scener identities.
+	Linked  int64 // Linked is the number of new credits linked to the sceners.
+	Removed int64 // Removed is the number of credits that are no longer in the credit columns.
+	Clashes int64 // Clashes is the number of new names with a slug used by another scener, which must be claimed.
+}
+
+// SyncIdentities creates the scener identities for the credited names that are unknown and
+// links the credits of the artifacts to the sceners. A name that is used as an alias by more than
+// one scener is not linked, as the artifact must be claimed by an editor, see [ClaimCredits].
+// A new name with a slug that is already used by a scener is not given to that scener, as it could be
+// a different person, so it is counted as a clash and left for an editor to add as an alias or claim.
+// The credits that no longer match the credit columns of the artifacts are removed.
+func SyncIdentities(ctx context.Context, db *sql.DB) (IdentitySync, error) {
+	return syncIdentities(ctx, db, nil)
+}
+
+// SyncArtifactIdentities is the same as [SyncIdentities] but only uses the credits of the artifact ids,
+// so it can be used after the credits of an artifact are edited or a new artifact is uploaded.
+func SyncArtifactIdentities(ctx context.Context, db *sql.DB, ids ...int64) (IdentitySync, error) {
+	if len(ids) == 0 {
+		return IdentitySync{}, nil
+	}
+	return syncIdentities(ctx, db, ids)
+}
+
+func syncIdentities(ctx context.Context, db *sql.DB, ids []int64) (IdentitySync, error) {
+	const msg = "sync identities"
+	var sync IdentitySync
+	if err := nils.Check(ctx, db); err != nil {
+		return sync, fmt.Errorf("%s: %w", msg, err)
+	}
+	// the credits of all the artifacts, or only the credits of the ids using the $1 argument
+	credits, stale, args := creditNames, "", []any{}
+	if ids != nil {
+		credits = creditNamesOf
+		stale, args = "x.file_id = ANY ($1::bigint[]) AND ", []any{ids}
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return sync, fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	names, err := identityList(ctx, tx, "SELECT DISTINCT ON (c.alias) c.name AS value FROM "+credits+" c "+
+		"WHERE NOT EXISTS (SELECT 1 FROM scener_aliases a WHERE a.alias = c.alias) ORDER BY c.alias, c.name", args...)
+	if err != nil {
+		return sync, fmt.Errorf("%s names: %w", msg, err)
+	}
+	for slug, group := range IdentityNames(names...) {
+		const insert = "INSERT INTO sceners (slug, name) VALUES ($1, $2) ON CONFLICT (slug) DO NOTHING RETURNING id"
+		var id int64
+		err := tx.QueryRowContext(ctx, insert, slug, group[0]).Scan(&id)
+		if errors.Is(err, sql.ErrNoRows) {
+			sync.Clashes += int64(len(group))
+			continue
+		}
+		if err != nil {
+			return sync, fmt.Errorf("%s %q: %w", msg, slug, err)
+		}
+		sync.Sceners++
+		aliases := make([]string, 0, len(group))
+		for _, name := range group {
+			aliases = append(aliases, strings.ToUpper(name))
+		}
+		const alias = "INSERT INTO scener_aliases (alias, scener_id) SELECT unnest($2::text[]), $1 " +
+			"ON CONFLICT DO NOTHING"
+		if _, err := tx.ExecContext(ctx, alias, id, aliases); err != nil {
+			return sync, fmt.Errorf("%s %q aliases: %w", msg, slug, err)
+		}
+	}
+	link := "INSERT INTO scener_credits (scener_id, file_id, role) " +
+		"SELECT DISTINCT a.scener_id, c.file_id, c.role FROM " + credits + " c " +
+		"JOIN scener_aliases a ON a.alias = c.alias " +
+		"WHERE (SELECT COUNT(*) FROM scener_aliases b WHERE b.alias = c.alias) = 1 " +
+		"ON CONFLICT DO NOTHING"
+	if sync.Linked, err = identityExec(ctx, tx, link, args...); err != nil {
+		return sync, fmt.Errorf("%s link: %w", msg, err)
+	}
+	remove := "DELETE FROM scener_credits x WHERE " + stale + "NOT EXISTS (SELECT 1 FROM " + credits + " c " +
+		"JOIN scener_aliases a ON a.alias = c.alias AND a.scener_id = x.scener_id " +
+		"WHERE c.file_id = x.file_id AND c.role = x.role)"
+	if sync.Removed, err = identityExec(ctx, tx, remove, args...); err != nil {
+		return sync, fmt.Errorf("%s remove: %w", msg, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return sync, fmt.Errorf(fmttx, msg, err)
+	}
+	return sync, nil
+}
+
+// identityExec runs the query and returns the number of rows affected.
+func identityExec(ctx context.Context, exec boil.ContextExecutor, query string, args ...any) (int64, error) {
+	res, err := exec.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// IdentityNames groups the credited names by their scener slugs, which are the same slugs used
+// by the links to the scener pages. The names that cannot be used in a URL path are skipped.
+// Names that only differ by their punctuation or casing share the same slug and so belong to the same scener.
+func IdentityNames(names ...string) map[string][]string {
+	groups := make(map[string][]string)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		slug := helper.Slug(name)
+		if name == "" || !namer.Path(slug).Valid() {
+			continue
+		}
+		if slices.ContainsFunc(groups[slug], func(s string) bool {
+			return strings.EqualFold(s, name)
+		}) {
+			continue
+		}
+		groups[slug] = append(groups[slug], name)
+	}
+	return groups
+}
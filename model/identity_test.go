@@ -0,0 +1,51 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestIdentityValid(t *testing.T) {
+	t.Parallel()
+	be.Err(t, model.Identity{}.Valid(), model.ErrSlug)
+	be.Err(t, model.Identity{Slug: "jed"}.Valid(), model.ErrIdentity)
+	be.Err(t, model.Identity{Slug: "jed", Name: "Jed"}.Valid(), model.ErrIdentity)
+	s := model.Identity{Slug: "jed", Name: "Jed", Aliases: []string{"JED"}, Members: []string{"acid productions"}}
+	be.Err(t, s.Valid(), model.ErrSlug)
+	s.Members = []string{"acid-productions"}
+	be.Err(t, s.Valid(), nil)
+}
+
+func TestIdentityNil(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	_, err := model.OneIdentity(ctx, nil, "jed")
+	be.Err(t, err)
+	_, err = model.IdentitySlugs(ctx, nil, "jed")
+	be.Err(t, err)
+	_, err = model.SaveIdentity(ctx, nil, model.Identity{})
+	be.Err(t, err)
+	be.Err(t, model.DeleteIdentity(ctx, nil, "jed"))
+	_, err = model.ClaimCredits(ctx, nil, 1, 1)
+	be.Err(t, err)
+	_, err = model.SyncIdentities(ctx, nil)
+	be.Err(t, err)
+	_, err = model.SyncArtifactIdentities(ctx, nil, 1)
+	be.Err(t, err)
+	_, err = model.SyncArtifactIdentities(ctx, nil)
+	be.Err(t, err, nil)
+	s := model.Identity{ID: 1, Slug: "jed"}
+	_, err = s.Releasers(ctx, nil)
+	be.Err(t, err)
+	_, err = s.Roles(ctx, nil)
+	be.Err(t, err)
+	be.Err(t, s.Summary(ctx, nil, nil))
+}
+
+func TestIdentityNames(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, len(model.IdentityNames()), 0)
+	be.Equal(t, len(model.IdentityNames("", "  ", "!!!")), 0)
+}
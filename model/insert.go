@@ -46,6 +46,9 @@ func InsertDemozoo(ctx context.Context, exec boil.ContextExecutor, id int) (int6
 	if err = f.Insert(ctx, exec, boil.Infer()); err != nil {
 		return 0, "", fmt.Errorf("insert demozoo infer: %w", err)
 	}
+	if err = RecordChange(ctx, exec, f.ID, Created); err != nil {
+		return 0, "", fmt.Errorf("insert demozoo: %w", err)
+	}
 	return f.ID, uid.String(), nil
 }
 
@@ -70,6 +73,9 @@ func InsertPouet(ctx context.Context, exec boil.ContextExecutor, id int) (int64,
 	if err = f.Insert(ctx, exec, boil.Infer()); err != nil {
 		return 0, "", fmt.Errorf("insert pouet infer: %w", err)
 	}
+	if err = RecordChange(ctx, exec, f.ID, Created); err != nil {
+		return 0, "", fmt.Errorf("insert pouet: %w", err)
+	}
 	return f.ID, uid.String(), nil
 }
 
@@ -117,6 +123,13 @@ func InsertUpload(ctx context.Context, tx *sql.Tx, values url.Values, key string
 	if err = f.Insert(ctx, tx, boil.Infer()); err != nil {
 		return 0, noID, fmt.Errorf("%s key %q: %w", msg, key, err)
 	}
+	if err = RecordChange(ctx, tx, f.ID, Created); err != nil {
+		return 0, noID, fmt.Errorf("%s key %q: %w", msg, key, err)
+	}
+	// the audit trail of a new record starts with every column that was saved by the upload
+	if err = RecordCreate(ctx, tx, &f); err != nil {
+		return 0, noID, fmt.Errorf("%s key %q: %w", msg, key, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return 0, noID, fmt.Errorf("%s key %q tx.commit: %w", msg, key, err)
 	}
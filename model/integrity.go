@@ -0,0 +1,35 @@
+package model
+
+// Package file integrity.go contains the database query for the checksums of the public artifact downloads.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+)
+
+// Integrity is the strong checksum of an artifact download.
+type Integrity struct {
+	ID       int64  `boil:"id"`       // ID is the artifact id.
+	Filename string `boil:"filename"` // Filename is the original filename of the download.
+	SHA384   string `boil:"sha384"`   // SHA384 is the hexadecimal, SHA-384 checksum of the download.
+}
+
+// Integrities returns the checksums of the public artifact downloads, ordered by the id key.
+// Artifacts without a strong checksum are skipped.
+func Integrities(ctx context.Context, exec boil.ContextExecutor) ([]Integrity, error) {
+	const msg = "integrities"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT id, COALESCE(filename, '') AS filename, TRIM(file_integrity_strong) AS sha384 " +
+		"FROM files WHERE deleted_at IS NULL AND TRIM(COALESCE(file_integrity_strong, '')) <> '' ORDER BY id"
+	var sums []Integrity
+	if err := queries.Raw(query).Bind(ctx, exec, &sums); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	return sums, nil
+}
@@ -0,0 +1,222 @@
+package model
+
+// Package file job.go contains the database queries for the queue of background jobs,
+// such as the creation of the preview images and the repackaged archives of the artifacts.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/aarondl/null/v8"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+)
+
+var ErrJob = errors.New("job cannot be changed in its current status")
+
+// JobStatus is the state of a queued job.
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"   // JobPending is waiting for a worker, including a retry.
+	JobRunning   JobStatus = "running"   // JobRunning is being processed by a worker.
+	JobDone      JobStatus = "done"      // JobDone completed successfully.
+	JobFailed    JobStatus = "failed"    // JobFailed has used all the attempts.
+	JobCancelled JobStatus = "cancelled" // JobCancelled was cancelled by an editor.
+)
+
+// Finished returns true if the job will not be run again.
+func (s JobStatus) Finished() bool {
+	switch s {
+	case JobDone, JobFailed, JobCancelled:
+		return true
+	}
+	return false
+}
+
+// JobLimit is the maximum number of jobs returned by the listing of the queue.
+const JobLimit = 200
+
+// Job is a queued task that creates or replaces an asset of an artifact.
+type Job struct {
+	ID        int64       `boil:"id"`         // ID is the unique id of the job.
+	Kind      string      `boil:"kind"`       // Kind is the name of the task to run.
+	UUID      string      `boil:"uuid"`       // UUID is the universal unique id of the artifact.
+	Src       string      `boil:"src"`        // Src is the named file to process, which is optional.
+	Status    JobStatus   `boil:"status"`     // Status is the state of the job.
+	Attempts  int         `boil:"attempts"`   // Attempts is the number of times the job has run.
+	LastError null.String `boil:"last_error"` // LastError is the error of the most recent attempt.
+	RunAfter  time.Time   `boil:"run_after"`  // RunAfter is the earliest time to run the job.
+	Created   time.Time   `boil:"created"`    // Created is the time the job was queued.
+	Updated   time.Time   `boil:"updated"`    // Updated is the time of the most recent change of status.
+}
+
+// Jobs is a collection of jobs.
+type Jobs []*Job
+
+const jobSelect = "SELECT id, kind, uuid, src, status, attempts, last_error, run_after, created, updated FROM file_jobs "
+
+// EnqueueJob adds a job to the queue and returns its id.
+// A job that has the same kind and uuid as a pending or running job is not queued,
+// instead the id of the existing job is returned.
+func EnqueueJob(ctx context.Context, exec boil.ContextExecutor, kind, unid, src string) (int64, error) {
+	const msg = "enqueue job"
+	if err := nils.Check(ctx, exec); err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	if kind == "" || unid == "" {
+		return 0, fmt.Errorf("%s: %w", msg, ErrKey)
+	}
+	const insert = "INSERT INTO file_jobs (kind, uuid, src) VALUES ($1, $2, $3) " +
+		"ON CONFLICT (kind, uuid) WHERE status IN ('pending', 'running') DO NOTHING RETURNING id"
+	var id int64
+	err := exec.QueryRowContext(ctx, insert, kind, unid, src).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("%s %s %s: %w", msg, kind, unid, err)
+	}
+	const query = "SELECT id FROM file_jobs WHERE kind = $1 AND uuid = $2 AND status IN ('pending', 'running')"
+	if err := exec.QueryRowContext(ctx, query, kind, unid).Scan(&id); err != nil {
+		return 0, fmt.Errorf("%s %s %s: %w", msg, kind, unid, err)
+	}
+	return id, nil
+}
+
+// ClaimJob marks the next pending job that is ready to run as running and returns it.
+// The row lock is skipped by other workers, so the same job is never claimed twice.
+// When there are no jobs ready to run, a nil job and nil error are returned.
+func ClaimJob(ctx context.Context, exec boil.ContextExecutor) (*Job, error) {
+	const msg = "claim job"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "UPDATE file_jobs SET status = 'running', attempts = attempts + 1, updated = now() " +
+		"WHERE id = (SELECT id FROM file_jobs WHERE status = 'pending' AND run_after <= now() " +
+		"ORDER BY run_after, id LIMIT 1 FOR UPDATE SKIP LOCKED) " +
+		"RETURNING id, kind, uuid, src, status, attempts, last_error, run_after, created, updated"
+	var j Job
+	err := queries.Raw(query).Bind(ctx, exec, &j)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	return &j, nil
+}
+
+// FinishJob marks the running job id as done.
+func FinishJob(ctx context.Context, exec boil.ContextExecutor, id int64) error {
+	const query = "UPDATE file_jobs SET status = 'done', last_error = NULL, updated = now() " +
+		"WHERE id = $1 AND status = 'running'"
+	return jobExec(ctx, exec, "finish job", query, id)
+}
+
+// RetryJob returns the running job id to the queue to run again after the given time.
+func RetryJob(ctx context.Context, exec boil.ContextExecutor, id int64, cause error, after time.Time) error {
+	const query = "UPDATE file_jobs SET status = 'pending', last_error = $2, run_after = $3, updated = now() " +
+		"WHERE id = $1 AND status = 'running'"
+	return jobExec(ctx, exec, "retry job", query, id, jobError(cause), after)
+}
+
+// FailJob marks the running job id as failed, it will not be run again unless it is requeued.
+func FailJob(ctx context.Context, exec boil.ContextExecutor, id int64, cause error) error {
+	const query = "UPDATE file_jobs SET status = 'failed', last_error = $2, updated = now() " +
+		"WHERE id = $1 AND status = 'running'"
+	return jobExec(ctx, exec, "fail job", query, id, jobError(cause))
+}
+
+// CancelJob marks the pending or running job id as cancelled.
+// A running job is not interrupted, as the worker process is only notified by the jobs queue.
+func CancelJob(ctx context.Context, exec boil.ContextExecutor, id int64) error {
+	const query = "UPDATE file_jobs SET status = 'cancelled', updated = now() " +
+		"WHERE id = $1 AND status IN ('pending', 'running')"
+	return jobExec(ctx, exec, "cancel job", query, id)
+}
+
+// RequeueJob returns the failed or cancelled job id to the queue to run immediately with new attempts.
+func RequeueJob(ctx context.Context, exec boil.ContextExecutor, id int64) error {
+	const query = "UPDATE file_jobs SET status = 'pending', attempts = 0, run_after = now(), updated = now() " +
+		"WHERE id = $1 AND status IN ('failed', 'cancelled')"
+	return jobExec(ctx, exec, "requeue job", query, id)
+}
+
+// ResetJobs returns the running jobs to the queue and returns the number of jobs that were reset.
+// It should be used on startup, as any running jobs were interrupted by the previous shutdown.
+func ResetJobs(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	const msg = "reset jobs"
+	if err := nils.Check(ctx, exec); err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "UPDATE file_jobs SET status = 'pending', updated = now() WHERE status = 'running'"
+	res, err := exec.ExecContext(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	i, err := res.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	return i, nil
+}
+
+// OneJob returns the job of the id.
+func OneJob(ctx context.Context, exec boil.ContextExecutor, id int64) (*Job, error) {
+	nils.BoilExecCrash(exec)
+	var j Job
+	const query = jobSelect + "WHERE id = $1"
+	if err := queries.Raw(query, id).Bind(ctx, exec, &j); err != nil {
+		return nil, fmt.Errorf("one job %d: %w", id, err)
+	}
+	return &j, nil
+}
+
+// Queue saves the jobs that have the status, with the most recently queued jobs first.
+// When no status is given, the pending, running and failed jobs are saved.
+func (j *Jobs) Queue(ctx context.Context, exec boil.ContextExecutor, status ...JobStatus) error {
+	nils.BoilExecCrash(exec)
+	if len(status) == 0 {
+		status = []JobStatus{JobPending, JobRunning, JobFailed}
+	}
+	args := make([]any, 0, len(status)+1)
+	args = append(args, JobLimit)
+	in := ""
+	for i, s := range status {
+		if i > 0 {
+			in += ", "
+		}
+		in += fmt.Sprintf("$%d", i+2)
+		args = append(args, string(s))
+	}
+	query := jobSelect + "WHERE status IN (" + in + ") ORDER BY id DESC LIMIT $1"
+	return queries.Raw(query, args...).Bind(ctx, exec, j)
+}
+
+func jobExec(ctx context.Context, exec boil.ContextExecutor, msg, query string, id int64, args ...any) error {
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	res, err := exec.ExecContext(ctx, query, append([]any{id}, args...)...)
+	if err != nil {
+		return fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	if i, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("%s %d: %w", msg, id, err)
+	} else if i == 0 {
+		return fmt.Errorf("%s %d: %w", msg, id, ErrJob)
+	}
+	return nil
+}
+
+func jobError(cause error) null.String {
+	if cause == nil {
+		return null.String{}
+	}
+	return null.StringFrom(cause.Error())
+}
@@ -0,0 +1,35 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestJobStatusFinished(t *testing.T) {
+	t.Parallel()
+	be.True(t, !model.JobPending.Finished())
+	be.True(t, !model.JobRunning.Finished())
+	be.True(t, model.JobDone.Finished())
+	be.True(t, model.JobFailed.Finished())
+	be.True(t, model.JobCancelled.Finished())
+}
+
+func TestJobNilExec(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	_, err := model.EnqueueJob(ctx, nil, "kind", "uuid", "")
+	be.Err(t, err)
+	j, err := model.ClaimJob(ctx, nil)
+	be.Err(t, err)
+	be.Equal(t, j, nil)
+	be.Err(t, model.FinishJob(ctx, nil, 1))
+	be.Err(t, model.RetryJob(ctx, nil, 1, nil, time.Now()))
+	be.Err(t, model.FailJob(ctx, nil, 1, nil))
+	be.Err(t, model.CancelJob(ctx, nil, 1))
+	be.Err(t, model.RequeueJob(ctx, nil, 1))
+	_, err = model.ResetJobs(ctx, nil)
+	be.Err(t, err)
+}
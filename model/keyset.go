@@ -0,0 +1,129 @@
+package model
+
+// Package file keyset.go handles the keyset, or cursor, paginated queries of the public artifacts.
+// Unlike the offset pagination used by the website, the pages remain stable when new records are inserted.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	namer "github.com/Defacto2/server/handler/releaser/name"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/Defacto2/server/internal/tags"
+	"github.com/Defacto2/server/model/querymod"
+	"github.com/aarondl/null/v8"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+)
+
+// Keyset is the filter and the order of a cursor paginated list of public artifacts.
+// The zero value lists every public artifact in ascending order of their id.
+type Keyset struct {
+	Platform   string    // Platform is the optional platform tag URI, such as "dos".
+	Section    string    // Section is the optional section or category tag URI, such as "demo".
+	Releaser   string    // Releaser is the optional releaser URI or name, such as "razor-1911".
+	Since      time.Time // Since only includes artifacts updated after this time.
+	YearFrom   int16     // YearFrom is the optional earliest year of publication.
+	YearTo     int16     // YearTo is the optional latest year of publication.
+	Keywords   []string  // Keywords are the optional keyword slugs that every artifact must be tagged with.
+	Descending bool      // Descending lists the newest artifacts first.
+}
+
+// Validate the tags, keywords and the year range of the keyset.
+func (k Keyset) Validate() error {
+	if k.Platform != "" && !tags.IsPlatform(k.Platform) {
+		return fmt.Errorf("%w: %q", ErrPlatform, k.Platform)
+	}
+	if k.Section != "" && !tags.IsCategory(k.Section) {
+		return fmt.Errorf("%w: %q", ErrSection, k.Section)
+	}
+	for _, slug := range k.Keywords {
+		if slug == "" || KeywordSlug(slug) != slug {
+			return fmt.Errorf("%w: %q", ErrKeyword, slug)
+		}
+	}
+	if k.YearFrom < 0 || k.YearTo < 0 {
+		return fmt.Errorf("%w: %d-%d", ErrYear, k.YearFrom, k.YearTo)
+	}
+	if k.YearFrom > 0 && k.YearTo > 0 && k.YearFrom > k.YearTo {
+		return fmt.Errorf("%w: %d-%d", ErrYear, k.YearFrom, k.YearTo)
+	}
+	return nil
+}
+
+// Mods returns the query mods of the keyset filters.
+func (k Keyset) Mods() []qm.QueryMod {
+	mods := []qm.QueryMod{}
+	if k.Platform != "" {
+		mods = append(mods, querymod.PlatformExpr(tags.TagByURI(k.Platform)))
+	}
+	if k.Section != "" {
+		mods = append(mods, querymod.SectionExpr(tags.TagByURI(k.Section)))
+	}
+	if k.YearFrom > 0 {
+		mods = append(mods, models.FileWhere.DateIssuedYear.GTE(null.Int16From(k.YearFrom)))
+	}
+	if k.YearTo > 0 {
+		mods = append(mods, models.FileWhere.DateIssuedYear.LTE(null.Int16From(k.YearTo)))
+	}
+	if len(k.Keywords) > 0 {
+		mods = append(mods, KeywordExpr(k.Keywords...))
+	}
+	if !k.Since.IsZero() {
+		mods = append(mods, models.FileWhere.Updatedat.GT(null.TimeFrom(k.Since)))
+	}
+	if k.Releaser != "" {
+		name, _ := namer.Humanize(namer.Path(k.Releaser))
+		if name == "" {
+			name = k.Releaser
+		}
+		x := null.StringFrom(strings.ToUpper(name))
+		mods = append(mods, qm.Where("upper(group_brand_for) = ? OR upper(group_brand_by) = ?", x, x))
+	}
+	return mods
+}
+
+// Count returns the total number of public artifacts that match the keyset filters.
+func (k Keyset) Count(ctx context.Context, exec boil.ContextExecutor) (int64, error) {
+	nils.BoilExecCrash(exec)
+	return models.Files(k.Mods()...).Count(ctx, exec)
+}
+
+// Page returns up to the limit of public artifacts that match the keyset filters and follow
+// the after id cursor. An after value of zero returns the first page.
+//
+// The next cursor is the id of the last artifact of the page and is zero when there are no more pages.
+func (k Keyset) Page(ctx context.Context, exec boil.ContextExecutor, after int64, limit int) (
+	models.FileSlice, int64, error,
+) {
+	nils.BoilExecCrash(exec)
+	if limit < 1 {
+		limit = 1
+	}
+	mods := k.Mods()
+	order := "id ASC"
+	if k.Descending {
+		order = "id DESC"
+	}
+	if after > 0 {
+		cursor := models.FileWhere.ID.GT(after)
+		if k.Descending {
+			cursor = models.FileWhere.ID.LT(after)
+		}
+		mods = append(mods, cursor)
+	}
+	// fetch one additional record to determine if there is a next page
+	mods = append(mods, qm.OrderBy(order), qm.Limit(limit+1))
+	fs, err := models.Files(mods...).All(ctx, exec)
+	if err != nil {
+		return nil, 0, fmt.Errorf("keyset page: %w", err)
+	}
+	if len(fs) <= limit {
+		return fs, 0, nil
+	}
+	fs = fs[:limit]
+	return fs, fs[len(fs)-1].ID, nil
+}
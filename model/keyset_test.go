@@ -0,0 +1,38 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestKeysetValidate(t *testing.T) {
+	t.Parallel()
+	k := model.Keyset{}
+	be.Err(t, k.Validate(), nil)
+	k = model.Keyset{Platform: "dos", Section: "demo", YearFrom: 1990, YearTo: 1999}
+	be.Err(t, k.Validate(), nil)
+	k = model.Keyset{Platform: "demo"}
+	be.Err(t, k.Validate(), model.ErrPlatform)
+	k = model.Keyset{Section: "dos"}
+	be.Err(t, k.Validate(), model.ErrSection)
+	k = model.Keyset{YearFrom: 1999, YearTo: 1990}
+	be.Err(t, k.Validate(), model.ErrYear)
+	k = model.Keyset{Keywords: []string{"sound-blaster", "96k-intro"}}
+	be.Err(t, k.Validate(), nil)
+	k = model.Keyset{Keywords: []string{"Sound Blaster"}}
+	be.Err(t, k.Validate(), model.ErrKeyword)
+}
+
+func TestKeysetMods(t *testing.T) {
+	t.Parallel()
+	k := model.Keyset{}
+	be.Equal(t, len(k.Mods()), 0)
+	k = model.Keyset{
+		Platform: "dos", Section: "demo", Releaser: "razor-1911",
+		Since: time.Now(), YearFrom: 1990, YearTo: 1999, Keywords: []string{"sound-blaster"},
+	}
+	be.Equal(t, len(k.Mods()), 7)
+}
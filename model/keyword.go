@@ -0,0 +1,246 @@
+package model
+
+// Package file keyword.go contains the database queries for the keywords, which are the editor curated
+// secondary tags of the artifacts. Unlike the single platform and section tags, an artifact can be
+// tagged with any number of keywords, such as "bbs-advert", "sound-blaster" and "96k-intro".
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+)
+
+var ErrKeyword = errors.New("keyword is not in the vocabulary")
+
+// Keyword is a secondary tag of the artifacts from the editor curated vocabulary.
+type Keyword struct {
+	Slug  string `boil:"slug"`  // Slug is the partial URL path of the keyword, such as "sound-blaster".
+	Name  string `boil:"name"`  // Name is the displayed title of the keyword, such as "Sound Blaster".
+	Info  string `boil:"info"`  // Info is an optional short description of the keyword.
+	Count int64  `boil:"count"` // Count is the number of public artifacts tagged with the keyword.
+}
+
+// KeywordSlug returns the keyword slug of the name, which is lowercased and uses hyphens
+// in place of the spaces and punctuation, such as "sound-blaster" for "Sound Blaster".
+func KeywordSlug(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(strings.TrimSpace(name)) {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if hyphen && b.Len() > 0 {
+				b.WriteRune('-')
+			}
+			hyphen = false
+			b.WriteRune(r)
+		default:
+			hyphen = true
+		}
+	}
+	return b.String()
+}
+
+// Valid returns an error if the keyword slug or name is invalid.
+func (k Keyword) Valid() error {
+	if k.Slug == "" || KeywordSlug(k.Slug) != k.Slug {
+		return fmt.Errorf("%w: the slug %q must use lowercase letters, numbers and hyphens", ErrKeyword, k.Slug)
+	}
+	if strings.TrimSpace(k.Name) == "" {
+		return fmt.Errorf("%w: %q requires a name", ErrKeyword, k.Slug)
+	}
+	return nil
+}
+
+// keywordCount is the query that selects the keywords with the number of public artifacts using them.
+const keywordCount = "SELECT k.slug, k.name, k.info, COUNT(f.id) AS count FROM keywords k " +
+	"LEFT JOIN file_keywords fk ON fk.keyword = k.slug " +
+	"LEFT JOIN files f ON f.id = fk.file_id AND f.deletedat IS NULL "
+
+// Keywords returns the vocabulary of the keywords with the number of public artifacts using them.
+func Keywords(ctx context.Context, exec boil.ContextExecutor) ([]Keyword, error) {
+	const msg = "keywords"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = keywordCount + "GROUP BY k.slug ORDER BY k.name"
+	var ks []Keyword
+	if err := queries.Raw(query).Bind(ctx, exec, &ks); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	return ks, nil
+}
+
+// OneKeyword returns the keyword of the slug.
+// A sql.ErrNoRows error is returned when the keyword is not in the vocabulary.
+func OneKeyword(ctx context.Context, exec boil.ContextExecutor, slug string) (*Keyword, error) {
+	const msg = "one keyword"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = keywordCount + "WHERE k.slug = $1 GROUP BY k.slug"
+	var k Keyword
+	if err := queries.Raw(query, slug).Bind(ctx, exec, &k); err != nil {
+		return nil, fmt.Errorf("%s %q: %w", msg, slug, err)
+	}
+	return &k, nil
+}
+
+// SaveKeyword inserts or updates the keyword in the vocabulary.
+func SaveKeyword(ctx context.Context, exec boil.ContextExecutor, k Keyword) error {
+	const msg = "save keyword"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if err := k.Valid(); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "INSERT INTO keywords (slug, name, info) VALUES ($1, $2, $3) " +
+		"ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name, info = EXCLUDED.info"
+	name, info := strings.TrimSpace(k.Name), strings.TrimSpace(k.Info)
+	if _, err := exec.ExecContext(ctx, query, k.Slug, name, info); err != nil {
+		return fmt.Errorf("%s %q: %w", msg, k.Slug, err)
+	}
+	return nil
+}
+
+// DeleteKeyword removes the keyword from the vocabulary and from all the tagged artifacts.
+func DeleteKeyword(ctx context.Context, exec boil.ContextExecutor, slug string) error {
+	const msg = "delete keyword"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if _, err := exec.ExecContext(ctx, "DELETE FROM keywords WHERE slug = $1", slug); err != nil {
+		return fmt.Errorf("%s %q: %w", msg, slug, err)
+	}
+	return nil
+}
+
+// FileKeywords returns the keywords of the artifact id.
+func FileKeywords(ctx context.Context, exec boil.ContextExecutor, id int64) ([]Keyword, error) {
+	const msg = "file keywords"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT k.slug, k.name, k.info, 0 AS count FROM keywords k " +
+		"JOIN file_keywords fk ON fk.keyword = k.slug WHERE fk.file_id = $1 ORDER BY k.name"
+	var ks []Keyword
+	if err := queries.Raw(query, id).Bind(ctx, exec, &ks); err != nil {
+		return nil, fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	return ks, nil
+}
+
+// AddFileKeyword tags the artifact id with the keyword slug, which must be in the vocabulary.
+func AddFileKeyword(ctx context.Context, db *sql.DB, id int64, slug string) error {
+	const msg = "add file keyword"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if id < 1 {
+		return fmt.Errorf("%s: %w", msg, ErrKey)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	const query = "INSERT INTO file_keywords (file_id, keyword) SELECT $1, slug FROM keywords WHERE slug = $2 " +
+		"ON CONFLICT DO NOTHING"
+	res, err := tx.ExecContext(ctx, query, id, slug)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	i, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if i == 0 {
+		// the artifact is already tagged or the keyword is not in the vocabulary
+		_, err := OneKeyword(ctx, tx, slug)
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w: %q", msg, ErrKeyword, slug)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", msg, err)
+		}
+		return nil
+	}
+	if err := RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
+	return nil
+}
+
+// RemoveFileKeyword removes the keyword slug from the artifact id.
+func RemoveFileKeyword(ctx context.Context, db *sql.DB, id int64, slug string) error {
+	const msg = "remove file keyword"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	const query = "DELETE FROM file_keywords WHERE file_id = $1 AND keyword = $2"
+	if _, err := tx.ExecContext(ctx, query, id, slug); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if err := RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
+	return nil
+}
+
+// KeywordExpr returns the query mod to select the artifacts that are tagged with all of the keyword slugs.
+func KeywordExpr(slugs ...string) qm.QueryMod {
+	mods := make([]qm.QueryMod, 0, len(slugs))
+	for _, slug := range slugs {
+		mods = append(mods, qm.Where("id IN (SELECT file_id FROM file_keywords WHERE keyword = ?)", slug))
+	}
+	return qm.Expr(mods...)
+}
+
+// KeywordFiles returns the public artifacts that match the keyword query mods, such as [KeywordExpr],
+// ordered by the date of publication.
+func KeywordFiles(ctx context.Context, exec boil.ContextExecutor, offset, limit int, mods ...qm.QueryMod) (
+	models.FileSlice, error,
+) {
+	nils.BoilExecCrash(exec)
+	mods = append(mods,
+		qm.Offset(calc(offset, limit)),
+		qm.Limit(limit),
+		qm.OrderBy(ClauseOldDate))
+	return models.Files(mods...).All(ctx, exec)
+}
+
+// ByKeyword selects the summary statistics of the public artifacts that match the keyword query mods.
+func (s *Summary) ByKeyword(ctx context.Context, exec boil.ContextExecutor, mods ...qm.QueryMod) error {
+	nils.BoilExecCrash(exec)
+	mods = append([]qm.QueryMod{
+		qm.Select(postgres.Columns()...),
+		qm.Where(ClauseNoSoftDel),
+		qm.From(From),
+	}, mods...)
+	return models.NewQuery(mods...).Bind(ctx, exec, s)
+}
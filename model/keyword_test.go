@@ -0,0 +1,40 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestKeywordSlug(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, model.KeywordSlug(""), "")
+	be.Equal(t, model.KeywordSlug("  Sound Blaster "), "sound-blaster")
+	be.Equal(t, model.KeywordSlug("96 KB intro!"), "96-kb-intro")
+	be.Equal(t, model.KeywordSlug("--AdLib--music--"), "adlib-music")
+	be.Equal(t, model.KeywordSlug("Café"), "caf")
+}
+
+func TestKeywordValid(t *testing.T) {
+	t.Parallel()
+	be.Err(t, model.Keyword{}.Valid(), model.ErrKeyword)
+	be.Err(t, model.Keyword{Slug: "Sound Blaster", Name: "Sound Blaster"}.Valid(), model.ErrKeyword)
+	be.Err(t, model.Keyword{Slug: "sound-blaster"}.Valid(), model.ErrKeyword)
+	be.Err(t, model.Keyword{Slug: "sound-blaster", Name: "Sound Blaster"}.Valid(), nil)
+}
+
+func TestKeywordNil(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	_, err := model.Keywords(ctx, nil)
+	be.Err(t, err)
+	_, err = model.OneKeyword(ctx, nil, "sound-blaster")
+	be.Err(t, err)
+	be.Err(t, model.SaveKeyword(ctx, nil, model.Keyword{}))
+	be.Err(t, model.DeleteKeyword(ctx, nil, "sound-blaster"))
+	_, err = model.FileKeywords(ctx, nil, 1)
+	be.Err(t, err)
+	be.Err(t, model.AddFileKeyword(ctx, nil, 1, "sound-blaster"))
+	be.Err(t, model.RemoveFileKeyword(ctx, nil, 1, "sound-blaster"))
+}
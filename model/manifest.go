@@ -0,0 +1,201 @@
+package model
+
+// Package file manifest.go contains the database queries for the archive manifests,
+// the structured lists of the files contained in the artifact downloads.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Defacto2/server/internal/manifest"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/sauces"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+)
+
+// Manifest returns the files contained in the archive of the artifact id, ordered by their path.
+// An empty manifest is returned when the artifact is not an archive or is yet to be read.
+func Manifest(ctx context.Context, exec boil.ContextExecutor, id int64) ([]manifest.Member, error) {
+	const msg = "manifest"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT path, size, crc32, sha256, modified, signature, encoding " +
+		"FROM file_members WHERE file_id = $1 AND path <> '' ORDER BY path"
+	var members []manifest.Member
+	if err := queries.Raw(query, id).Bind(ctx, exec, &members); err != nil {
+		return nil, fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	return members, nil
+}
+
+// ManifestStamps returns the last modified times of the artifact downloads when their manifests were read,
+// keyed by the artifact id. The manifests that were read without the SAUCE metadata of the members are excluded,
+// so those archives are read again.
+func ManifestStamps(ctx context.Context, exec boil.ContextExecutor) (map[int64]time.Time, error) {
+	nils.BoilExecCrash(exec)
+	const query = "SELECT file_id, MAX(stamp) AS modified FROM file_members WHERE sauced GROUP BY file_id"
+	var stamps []TextStamp
+	if err := queries.Raw(query).Bind(ctx, exec, &stamps); err != nil {
+		return nil, fmt.Errorf("manifest stamps: %w", err)
+	}
+	m := make(map[int64]time.Time, len(stamps))
+	for _, s := range stamps {
+		m[s.FileID] = s.Modified
+	}
+	return m, nil
+}
+
+// ReplaceManifest removes any existing manifest of the artifact id and saves the members.
+// An artifact without any members is saved with an empty member that stamps the manifest as read.
+// The stamp should be the last modified time of the artifact download.
+func ReplaceManifest(ctx context.Context, db *sql.DB,
+	id int64, stamp time.Time, members ...manifest.Member,
+) error {
+	const msg = "replace manifest"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if id < 1 {
+		return fmt.Errorf("%s: %w: %d", msg, ErrID, id)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := replaceManifest(ctx, tx, id, stamp, members...); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
+	return nil
+}
+
+func replaceManifest(ctx context.Context, tx *sql.Tx, id int64, stamp time.Time, members ...manifest.Member) error {
+	const remove = "DELETE FROM file_members WHERE file_id = $1"
+	if _, err := tx.ExecContext(ctx, remove, id); err != nil {
+		return fmt.Errorf("delete: %w", err)
+	}
+	if len(members) == 0 {
+		const empty = "INSERT INTO file_members " +
+			"(file_id, path, size, crc32, sha256, modified, stamp, sauced) " +
+			"VALUES ($1, '', 0, '', '', $2, $2, true)"
+		if _, err := tx.ExecContext(ctx, empty, id, stamp); err != nil {
+			return fmt.Errorf("insert empty: %w", err)
+		}
+		return nil
+	}
+	const insert = "INSERT INTO file_members " +
+		"(file_id, path, size, crc32, sha256, modified, signature, encoding, stamp, sauced) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, true) ON CONFLICT DO NOTHING"
+	for _, m := range members {
+		if _, err := tx.ExecContext(ctx, insert, id, m.Path, m.Size, m.CRC32, m.SHA256,
+			m.Modified, m.Signature, m.Encoding, stamp); err != nil {
+			return fmt.Errorf("insert %q: %w", m.Path, err)
+		}
+	}
+	return nil
+}
+
+// SaveManifest reads the archive of the src download file and saves the manifest of the artifact id,
+// together with the SAUCE metadata of the files contained in the archive.
+// The filename is the original name of the archive that is used to determine the archive format.
+//
+// An archive that cannot be read is saved with an empty manifest, so it is not read again
+// until the download is modified, and the error of the read is returned.
+func SaveManifest(ctx context.Context, db *sql.DB, id int64, src, filename string) error {
+	const msg = "save manifest"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if id < 1 {
+		return fmt.Errorf("%s: %w: %d", msg, ErrID, id)
+	}
+	st, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	var members []manifest.Member
+	var recs []sauces.Record
+	extractErr := manifest.Extract(src, filename, func(root string) error {
+		var err error
+		if members, err = manifest.Dir(root); err != nil {
+			return err
+		}
+		recs, err = sauces.Dir(root)
+		return err
+	})
+	if extractErr != nil {
+		members, recs = nil, nil
+	}
+	// the database stores timestamps to the microsecond
+	stamp := st.ModTime().Truncate(time.Microsecond)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	if err := replaceManifest(ctx, tx, id, stamp, members...); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if err := ReplaceSauces(ctx, tx, id, stamp, recs...); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
+	if extractErr != nil {
+		return fmt.Errorf("%s: %w", msg, extractErr)
+	}
+	return nil
+}
+
+// IndexManifests walks the archived artifacts and saves the manifests of those that are new or
+// have been modified since their manifest was read. Artifacts that cannot be extracted are saved
+// with an empty manifest, so they are not extracted again until they are modified.
+// It returns the number of artifacts indexed and the number skipped as unmodified.
+func IndexManifests(ctx context.Context, db *sql.DB, download string) (int, int, error) {
+	const msg = "index manifests"
+	if err := nils.Check(ctx, db); err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	indexed, skipped := 0, 0
+	stamps, err := ManifestStamps(ctx, db)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	arts, err := TextSources(ctx, db)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	for _, art := range arts {
+		if err := ctx.Err(); err != nil {
+			return indexed, skipped, fmt.Errorf("%s: %w", msg, err)
+		}
+		if strings.TrimSpace(art.FileZipContent.String) == "" {
+			continue
+		}
+		src := filepath.Join(download, art.UUID.String)
+		st, err := os.Stat(src)
+		if err != nil {
+			continue
+		}
+		if last, ok := stamps[art.ID]; ok && !st.ModTime().Truncate(time.Microsecond).After(last) {
+			skipped++
+			continue
+		}
+		if err := SaveManifest(ctx, db, art.ID, src, art.Filename.String); err != nil {
+			continue
+		}
+		indexed++
+	}
+	return indexed, skipped, nil
+}
@@ -0,0 +1,18 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestManifestNil(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	_, err := model.Manifest(ctx, nil, 1)
+	be.Err(t, err)
+	be.Err(t, model.ReplaceManifest(ctx, nil, 1, time.Now()))
+	be.Err(t, model.SaveManifest(ctx, nil, 1, "", ""))
+}
@@ -30,6 +30,7 @@ var (
 	ErrSize     = errors.New("size value is invalid")
 	ErrRels     = errors.New("too many releasers, only two are allowed")
 	ErrPlatform = errors.New("invalid platform")
+	ErrSection  = errors.New("invalid section")
 	ErrSha384   = errors.New("sha384 value is invalid")
 	ErrTime     = errors.New("time value is invalid")
 	ErrURI      = errors.New("uri value is invalid")
@@ -120,11 +121,48 @@ func JsDosBinary(f *models.File) (string, error) {
 }
 
 // JsDosConfig creates a js-dos .ini configuration for the emulator.
-func JsDosConfig(f *models.File) (string, error) {
+// The emulator settings of the artifact are applied to the hardware configuration.
+func JsDosConfig(f *models.File, s jsdos.Settings) (string, error) {
 	const msg = "jsdos config"
 	if f == nil {
 		return "", fmt.Errorf("%s: %w", msg, ErrModel)
 	}
+	j := jsDosHardware(f)
+	j.Apply(s)
+	b, err := ini.Marshal(j)
+	if err != nil {
+		return "", fmt.Errorf("%s ini marshal: %w", msg, err)
+	}
+	return string(b), nil
+}
+
+// JsDosConf creates the dosbox.conf configuration of a js-dos v8 bundle for the emulator.
+// The emulator settings of the artifact are applied to the hardware configuration,
+// and the autoexec section mounts the CD images and runs the program.
+func JsDosConf(f *models.File, s jsdos.Settings) ([]byte, error) {
+	const msg = "jsdos conf"
+	if f == nil {
+		return nil, fmt.Errorf("%s: %w", msg, ErrModel)
+	}
+	run, err := JsDosCommand(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	j := jsDosHardware(f)
+	j.Apply(s)
+	lines, err := jsdos.Autoexec(s, run)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	b, err := jsdos.Conf(j, lines...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	return b, nil
+}
+
+// jsDosHardware returns the js-dos configuration of the hardware settings of the artifact.
+func jsDosHardware(f *models.File) jsdos.Jsdos {
 	j := jsdos.Jsdos{} //nolint:exhaustruct // External library with many optional configuration fields
 	cpu := f.DoseeHardwareCPU.String
 	if f.DoseeHardwareCPU.Valid && cpu != "" {
@@ -150,11 +188,7 @@ func JsDosConfig(f *models.File) (string, error) {
 	if f.DoseeNoUmb.Valid && mem == 1 {
 		j.NoUMB(true)
 	}
-	b, err := ini.Marshal(j)
-	if err != nil {
-		return "", fmt.Errorf("%s ini marshal: %w", msg, err)
-	}
-	return string(b), nil
+	return j
 }
 
 // UUID returns a slice of all the UUIDs in the database.
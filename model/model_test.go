@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Defacto2/server/handler/jsdos"
 	"github.com/Defacto2/server/model"
 	"github.com/aarondl/null/v8"
 	"github.com/google/uuid"
@@ -269,12 +270,43 @@ func TestDelete(t *testing.T) {
 	be.Err(t, err)
 }
 
+func TestExport(t *testing.T) {
+	t.Parallel()
+	n, err := model.Export(t.Context(), nil, nil)
+	be.Err(t, err)
+	be.Equal(t, n, 0)
+}
+
 func TestModel(t *testing.T) {
 	t.Parallel()
 	_, err := model.JsDosBinary(nil)
 	be.Err(t, err)
-	_, err = model.JsDosConfig(nil)
+	_, err = model.JsDosConfig(nil, jsdos.Settings{})
+	be.Err(t, err)
+	_, err = model.JsDosConf(nil, jsdos.Settings{})
 	be.Err(t, err)
 	_, err = model.JsDosCommand(nil)
 	be.Err(t, err)
 }
+
+func TestIntegrities(t *testing.T) {
+	t.Parallel()
+	sums, err := model.Integrities(t.Context(), nil)
+	be.Err(t, err)
+	be.Equal(t, len(sums), 0)
+}
+
+func TestHits(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	err := model.SaveHits(ctx, nil, time.Now(), nil)
+	be.Err(t, err)
+	trends, err := model.Trends(ctx, nil, model.PopularDays)
+	be.Err(t, err)
+	be.Equal(t, len(trends), 0)
+	pop, err := model.Popular(ctx, nil, model.PopularDays, 10)
+	be.Err(t, err)
+	be.Equal(t, len(pop), 0)
+	trend := model.Trend{Downloads: 1, Emulations: 2, Views: 3}
+	be.Equal(t, trend.Total(), int64(6))
+}
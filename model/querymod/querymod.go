@@ -3,6 +3,7 @@ package querymod
 
 import (
 	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/Defacto2/server/internal/tags"
 	"github.com/aarondl/sqlboiler/v4/queries/qm"
 )
 
@@ -412,3 +413,17 @@ func WindowsPackExpr() qm.QueryMod {
 		models.FileWhere.Section.EQ(SPack()),
 	)
 }
+
+// PlatformExpr is a query mod expression for releases of the platform tag.
+func PlatformExpr(t tags.Tag) qm.QueryMod {
+	return qm.Expr(
+		models.FileWhere.Platform.EQ(Tag(t)),
+	)
+}
+
+// SectionExpr is a query mod expression for releases of the section or category tag.
+func SectionExpr(t tags.Tag) qm.QueryMod {
+	return qm.Expr(
+		models.FileWhere.Section.EQ(Tag(t)),
+	)
+}
@@ -3,6 +3,7 @@ package querymod_test
 import (
 	"testing"
 
+	"github.com/Defacto2/server/internal/tags"
 	"github.com/Defacto2/server/model/querymod"
 	"github.com/nalgeon/be"
 )
@@ -272,3 +273,13 @@ func TestWindowsPackExpr(t *testing.T) {
 	expr := querymod.WindowsPackExpr()
 	be.True(t, expr != nil)
 }
+
+func TestPlatformExpr(t *testing.T) {
+	expr := querymod.PlatformExpr(tags.DOS)
+	be.True(t, expr != nil)
+}
+
+func TestSectionExpr(t *testing.T) {
+	expr := querymod.SectionExpr(tags.Demo)
+	be.True(t, expr != nil)
+}
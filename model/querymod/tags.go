@@ -19,6 +19,13 @@ func getURIs() map[tags.Tag]string {
 	return uris
 }
 
+// Tag returns the URI of any tag for use with either the platform or section columns.
+// An unknown tag returns an invalid null.String.
+func Tag(t tags.Tag) null.String {
+	s, ok := getURIs()[t]
+	return null.String{String: s, Valid: ok}
+}
+
 // funcs that begin with S are for the section column.
 
 func SAdvert() null.String {
@@ -245,3 +245,11 @@ func TestPWindowsReturnsValidTag(t *testing.T) {
 	be.True(t, result.Valid)
 	be.Equal(t, result.String, tags.URIs()[tags.Windows])
 }
+
+func TestTagReturnsValidTag(t *testing.T) {
+	result := querymod.Tag(tags.DOS)
+	be.True(t, result.Valid)
+	be.Equal(t, result.String, tags.URIs()[tags.DOS])
+	result = querymod.Tag(-1)
+	be.True(t, !result.Valid)
+}
@@ -0,0 +1,636 @@
+package model
+
+// Package file relation.go contains the database queries for the relations between the artifacts,
+// which are typed edges from an artifact to a target artifact, and the typed external links of the artifacts.
+// These replace the pipe-delimited list_relations and list_links columns of the files table.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/Defacto2/helper"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/aarondl/null/v8"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+)
+
+var (
+	ErrRelation = errors.New("artifact relation is invalid")
+	ErrLink     = errors.New("artifact link is invalid")
+)
+
+// RelationKind is the type of a relation from an artifact to the target artifact.
+type RelationKind string
+
+const (
+	RelationPack     RelationKind = "pack"     // RelationPack is an artifact that is part of the target pack.
+	RelationPlatform RelationKind = "platform" // RelationPlatform is the same release for a different platform.
+	RelationNFO      RelationKind = "nfo"      // RelationNFO is an NFO text for the target release.
+	RelationFix      RelationKind = "fix"      // RelationFix is a fix, patch or crack for the target release.
+	RelationSequel   RelationKind = "sequel"   // RelationSequel is a sequel or a later issue of the target.
+	RelationOther    RelationKind = "related"  // RelationOther is any other relation, it uses the label of the relation.
+)
+
+// RelationKinds returns all the kinds of the artifact relations.
+func RelationKinds() []RelationKind {
+	return []RelationKind{RelationPack, RelationPlatform, RelationNFO, RelationFix, RelationSequel, RelationOther}
+}
+
+// Valid returns true if the relation kind is known.
+func (k RelationKind) Valid() bool {
+	for _, kind := range RelationKinds() {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Forward returns the description of the relation as shown on the page of the artifact.
+func (k RelationKind) Forward() string {
+	switch k {
+	case RelationPack:
+		return "Part of the pack"
+	case RelationPlatform:
+		return "Also released for"
+	case RelationNFO:
+		return "NFO for"
+	case RelationFix:
+		return "Fix for"
+	case RelationSequel:
+		return "Sequel to"
+	case RelationOther:
+		return "Related"
+	}
+	return ""
+}
+
+// Reverse returns the description of the relation as shown on the page of the target artifact.
+func (k RelationKind) Reverse() string {
+	switch k {
+	case RelationPack:
+		return "Pack contains"
+	case RelationPlatform:
+		return "Also released for"
+	case RelationNFO:
+		return "NFO text"
+	case RelationFix:
+		return "Fixed by"
+	case RelationSequel:
+		return "Followed by"
+	case RelationOther:
+		return "Related"
+	}
+	return ""
+}
+
+// Relation is a typed edge from an artifact to a target artifact.
+type Relation struct {
+	ID       int64        `boil:"id"`        // ID is the unique id of the relation.
+	FileID   int64        `boil:"file_id"`   // FileID is the id of the artifact.
+	TargetID int64        `boil:"target_id"` // TargetID is the id of the target artifact.
+	Kind     RelationKind `boil:"kind"`      // Kind is the type of the relation.
+	Label    string       `boil:"label"`     // Label is an optional description that replaces the kind description.
+	Reverse  bool         `boil:"reverse"`   // Reverse is true when the relation was found using the target artifact.
+	Title    string       `boil:"title"`     // Title is the title or filename of the other artifact.
+}
+
+// Other returns the id of the other artifact of the relation.
+func (r Relation) Other() int64 {
+	if r.Reverse {
+		return r.FileID
+	}
+	return r.TargetID
+}
+
+// Description returns the label of the relation or the description of the kind,
+// which depends on the direction of the relation.
+func (r Relation) Description() string {
+	if r.Label != "" {
+		return r.Label
+	}
+	if r.Reverse {
+		return r.Kind.Reverse()
+	}
+	return r.Kind.Forward()
+}
+
+// Relations returns the relations of the artifact id in both directions.
+// The relations to the artifacts that are hidden or waiting for approval are only included when hidden is true.
+func Relations(ctx context.Context, exec boil.ContextExecutor, id int64, hidden bool) ([]Relation, error) {
+	const msg = "relations"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const title = "COALESCE(NULLIF(f.record_title, ''), f.filename, '') AS title"
+	const query = "SELECT r.id, r.file_id, r.target_id, r.kind, r.label, false AS reverse, " + title +
+		" FROM artifact_relations r JOIN files f ON f.id = r.target_id" +
+		" WHERE r.file_id = $1 AND ($2 OR f.deletedat IS NULL)" +
+		" UNION ALL " +
+		"SELECT r.id, r.file_id, r.target_id, r.kind, r.label, true AS reverse, " + title +
+		" FROM artifact_relations r JOIN files f ON f.id = r.file_id" +
+		" WHERE r.target_id = $1 AND ($2 OR f.deletedat IS NULL)" +
+		" ORDER BY reverse, kind, id"
+	var rels []Relation
+	if err := queries.Raw(query, id, hidden).Bind(ctx, exec, &rels); err != nil {
+		return nil, fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	return rels, nil
+}
+
+// AddRelation saves the relation from the artifact to the target artifact.
+// Both artifacts must exist, and the same kind of relation cannot be saved twice.
+func AddRelation(ctx context.Context, db *sql.DB, r Relation) error {
+	const msg = "add relation"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if r.FileID < 1 || r.TargetID < 1 {
+		return fmt.Errorf("%s: %w", msg, ErrKey)
+	}
+	if r.FileID == r.TargetID {
+		return fmt.Errorf("%s: %w: an artifact cannot relate to itself", msg, ErrRelation)
+	}
+	if !r.Kind.Valid() {
+		return fmt.Errorf("%s: %w: unknown kind %q", msg, ErrRelation, r.Kind)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var found int
+	const exists = "SELECT COUNT(*) FROM files WHERE id = ANY ($1::bigint[])"
+	if err := tx.QueryRowContext(ctx, exists, []int64{r.FileID, r.TargetID}).Scan(&found); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	const both = 2
+	if found != both {
+		return fmt.Errorf("%s: %w: the artifact %d or %d does not exist", msg, ErrRelation, r.FileID, r.TargetID)
+	}
+	var before null.String
+	const prior = "SELECT label FROM artifact_relations WHERE file_id = $1 AND target_id = $2 AND kind = $3"
+	var oldLabel string
+	err = tx.QueryRowContext(ctx, prior, r.FileID, r.TargetID, string(r.Kind)).Scan(&oldLabel)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return fmt.Errorf("%s: %w", msg, err)
+	default:
+		before = relationText(r.Kind, r.TargetID, oldLabel)
+	}
+	const insert = "INSERT INTO artifact_relations (file_id, target_id, kind, label) VALUES ($1, $2, $3, $4) " +
+		"ON CONFLICT (file_id, target_id, kind) DO UPDATE SET label = EXCLUDED.label"
+	label := strings.TrimSpace(r.Label)
+	if _, err := tx.ExecContext(ctx, insert, r.FileID, r.TargetID, string(r.Kind), label); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if err := RecordChange(ctx, tx, r.FileID, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	after := relationText(r.Kind, r.TargetID, label)
+	if err := RecordEdit(ctx, tx, r.FileID, Related, before, after); err != nil {
+		return fmt.Errorf("%s record edit: %w", msg, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
+	return nil
+}
+
+// DeleteRelation removes the relation id, that is either from or to the artifact id.
+// The removal is logged as a change of the artifact that owns the relation.
+func DeleteRelation(ctx context.Context, db *sql.DB, fileID, id int64) error {
+	const msg = "delete relation"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	const query = "DELETE FROM artifact_relations WHERE id = $1 AND (file_id = $2 OR target_id = $2) " +
+		"RETURNING file_id, target_id, kind, label"
+	var r Relation
+	err = tx.QueryRowContext(ctx, query, id, fileID).Scan(&r.FileID, &r.TargetID, &r.Kind, &r.Label)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	if err := RecordChange(ctx, tx, r.FileID, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	before := relationText(r.Kind, r.TargetID, r.Label)
+	if err := RecordEdit(ctx, tx, r.FileID, Related, before, null.String{}); err != nil {
+		return fmt.Errorf("%s record edit: %w", msg, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
+	return nil
+}
+
+// relationText returns the audit trail description of the relation to the target artifact.
+func relationText(kind RelationKind, targetID int64, label string) null.String {
+	s := fmt.Sprintf("%s %s", kind, helper.ObfuscateID(targetID))
+	if label != "" {
+		s += " " + label
+	}
+	return null.StringFrom(s)
+}
+
+// LinkKind is the type of an external link of an artifact.
+type LinkKind string
+
+const (
+	LinkWebsite LinkKind = "website" // LinkWebsite is the website of the release, group or product.
+	LinkArticle LinkKind = "article" // LinkArticle is an article, review or documentation about the artifact.
+	LinkVideo   LinkKind = "video"   // LinkVideo is a video recording of the artifact.
+	LinkArchive LinkKind = "archive" // LinkArchive is an archived copy of a website or a download mirror.
+)
+
+// LinkKinds returns all the kinds of the external links.
+func LinkKinds() []LinkKind {
+	return []LinkKind{LinkWebsite, LinkArticle, LinkVideo, LinkArchive}
+}
+
+// Valid returns true if the link kind is known.
+func (k LinkKind) Valid() bool {
+	for _, kind := range LinkKinds() {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Link is a typed external link of an artifact.
+type Link struct {
+	ID     int64    `boil:"id"`      // ID is the unique id of the link.
+	FileID int64    `boil:"file_id"` // FileID is the id of the artifact.
+	Kind   LinkKind `boil:"kind"`    // Kind is the type of the link.
+	Label  string   `boil:"label"`   // Label is the name of the link.
+	URL    string   `boil:"url"`     // URL is the absolute URL of the link.
+}
+
+// Links returns the external links of the artifact id.
+func Links(ctx context.Context, exec boil.ContextExecutor, id int64) ([]Link, error) {
+	const msg = "links"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT id, file_id, kind, label, url FROM artifact_links WHERE file_id = $1 ORDER BY kind, id"
+	var links []Link
+	if err := queries.Raw(query, id).Bind(ctx, exec, &links); err != nil {
+		return nil, fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	return links, nil
+}
+
+// LinkURL returns the absolute URL of the link, a link without a protocol uses HTTPS.
+// An error is returned if the link is not a HTTP or HTTPS URL with a host.
+func LinkURL(link string) (string, error) {
+	link = strings.TrimSpace(link)
+	if !strings.HasPrefix(link, "https://") && !strings.HasPrefix(link, "http://") {
+		if strings.Contains(link, "://") {
+			return "", fmt.Errorf("%w: %q uses an unknown protocol", ErrLink, link)
+		}
+		link = "https://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("%w: %q is not a URL", ErrLink, link)
+	}
+	return u.String(), nil
+}
+
+// AddLink saves the external link of the artifact.
+func AddLink(ctx context.Context, db *sql.DB, l Link) error {
+	const msg = "add link"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if l.FileID < 1 {
+		return fmt.Errorf("%s: %w", msg, ErrKey)
+	}
+	if !l.Kind.Valid() {
+		return fmt.Errorf("%s: %w: unknown kind %q", msg, ErrLink, l.Kind)
+	}
+	href, err := LinkURL(l.URL)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	label := strings.TrimSpace(l.Label)
+	if label == "" {
+		return fmt.Errorf("%s: %w: %q requires a label", msg, ErrLink, href)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var before null.String
+	const prior = "SELECT kind, label FROM artifact_links WHERE file_id = $1 AND url = $2"
+	var old Link
+	err = tx.QueryRowContext(ctx, prior, l.FileID, href).Scan(&old.Kind, &old.Label)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+	case err != nil:
+		return fmt.Errorf("%s: %w", msg, err)
+	default:
+		before = linkText(old.Kind, old.Label, href)
+	}
+	const insert = "INSERT INTO artifact_links (file_id, kind, label, url) VALUES ($1, $2, $3, $4) " +
+		"ON CONFLICT (file_id, url) DO UPDATE SET kind = EXCLUDED.kind, label = EXCLUDED.label"
+	if _, err := tx.ExecContext(ctx, insert, l.FileID, string(l.Kind), label, href); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if err := RecordChange(ctx, tx, l.FileID, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	after := linkText(l.Kind, label, href)
+	if err := RecordEdit(ctx, tx, l.FileID, External, before, after); err != nil {
+		return fmt.Errorf("%s record edit: %w", msg, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
+	return nil
+}
+
+// DeleteLink removes the external link id of the artifact id.
+func DeleteLink(ctx context.Context, db *sql.DB, fileID, id int64) error {
+	const msg = "delete link"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	const query = "DELETE FROM artifact_links WHERE id = $1 AND file_id = $2 RETURNING kind, label, url"
+	var l Link
+	err = tx.QueryRowContext(ctx, query, id, fileID).Scan(&l.Kind, &l.Label, &l.URL)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	if err := RecordChange(ctx, tx, fileID, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	before := linkText(l.Kind, l.Label, l.URL)
+	if err := RecordEdit(ctx, tx, fileID, External, before, null.String{}); err != nil {
+		return fmt.Errorf("%s record edit: %w", msg, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
+	return nil
+}
+
+// linkText returns the audit trail description of the external link.
+func linkText(kind LinkKind, label, href string) null.String {
+	return null.StringFrom(fmt.Sprintf("%s %s %s", kind, label, href))
+}
+
+// ParseRelations returns the relations of the pipe-delimited list_relations column value of the artifact id.
+// Each relation is a label and an artifact key separated by a semicolon, such as "NFO;9f1c2".
+// As the direction of these relations is unknown, they all use the [RelationOther] kind with the label.
+func ParseRelations(id int64, list string) []Relation {
+	var rels []Relation
+	for item := range strings.SplitSeq(list, "|") {
+		label, key, found := strings.Cut(item, ";")
+		if !found {
+			continue
+		}
+		target := RelationKey(key)
+		if target < 1 || target == id {
+			continue
+		}
+		rels = append(rels, Relation{
+			FileID:   id,
+			TargetID: target,
+			Kind:     RelationOther,
+			Label:    strings.TrimSpace(label),
+		})
+	}
+	return rels
+}
+
+// ParseLinks returns the external links of the pipe-delimited list_links column value of the artifact id.
+// Each link is a label and a URL separated by a semicolon, such as "Website;example.com".
+// The kind of the link is guessed from the host of the URL.
+func ParseLinks(id int64, list string) []Link {
+	var links []Link
+	for item := range strings.SplitSeq(list, "|") {
+		label, href, found := strings.Cut(item, ";")
+		if !found {
+			continue
+		}
+		href, err := LinkURL(href)
+		if err != nil {
+			continue
+		}
+		label = strings.TrimSpace(label)
+		if label == "" {
+			label = href
+		}
+		links = append(links, Link{FileID: id, Kind: guessLink(href), Label: label, URL: href})
+	}
+	return links
+}
+
+// guessLink returns the kind of link using the host of the absolute URL.
+func guessLink(href string) LinkKind {
+	u, err := url.Parse(href)
+	if err != nil {
+		return LinkWebsite
+	}
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	switch {
+	case host == "archive.org", strings.HasSuffix(host, ".archive.org"), host == "textfiles.com",
+		strings.HasSuffix(host, ".textfiles.com"):
+		return LinkArchive
+	case host == "youtube.com", host == "youtu.be", host == "vimeo.com", strings.HasSuffix(host, ".youtube.com"):
+		return LinkVideo
+	}
+	return LinkWebsite
+}
+
+// MigrateRelations moves the pipe-delimited list_relations and list_links column values of the files table
+// into the artifact relations and links tables, and then clears the moved values from the columns.
+// Any relation to an artifact that does not exist and any value that cannot be parsed is kept in the column,
+// so no data is lost and a relation is moved once its target artifact exists.
+// It returns the number of artifacts migrated.
+func MigrateRelations(ctx context.Context, db *sql.DB) (int64, error) {
+	const msg = "migrate relations"
+	if err := nils.Check(ctx, db); err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var rows []struct {
+		ID        int64  `boil:"id"`
+		Relations string `boil:"relations"`
+		Links     string `boil:"links"`
+	}
+	const query = "SELECT id, COALESCE(list_relations, '') AS relations, COALESCE(list_links, '') AS links " +
+		"FROM files WHERE NULLIF(trim(list_relations), '') IS NOT NULL OR NULLIF(trim(list_links), '') IS NOT NULL"
+	if err := queries.Raw(query).Bind(ctx, tx, &rows); err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	var sum int64
+	for _, row := range rows {
+		relations, err := migrateRelations(ctx, tx, row.ID, row.Relations)
+		if err != nil {
+			return 0, fmt.Errorf("%s %d relation: %w", msg, row.ID, err)
+		}
+		links, err := migrateLinks(ctx, tx, row.ID, row.Links)
+		if err != nil {
+			return 0, fmt.Errorf("%s %d link: %w", msg, row.ID, err)
+		}
+		if relations == row.Relations && links == row.Links {
+			continue
+		}
+		const reset = "UPDATE files SET list_relations = NULLIF($2, ''), list_links = NULLIF($3, '') WHERE id = $1"
+		if _, err := tx.ExecContext(ctx, reset, row.ID, relations, links); err != nil {
+			return 0, fmt.Errorf("%s %d: %w", msg, row.ID, err)
+		}
+		sum++
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf(fmttx, msg, err)
+	}
+	return sum, nil
+}
+
+// migrateRelations saves the relations of the pipe-delimited list of the artifact id,
+// and returns the list of items that were not saved.
+func migrateRelations(ctx context.Context, tx *sql.Tx, id int64, list string) (string, error) {
+	var kept []string
+	for item := range strings.SplitSeq(list, "|") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		rels := ParseRelations(id, item)
+		if len(rels) == 0 {
+			kept = append(kept, item)
+			continue
+		}
+		r := rels[0]
+		var exists bool
+		const query = "SELECT EXISTS (SELECT 1 FROM files WHERE id = $1)"
+		if err := tx.QueryRowContext(ctx, query, r.TargetID).Scan(&exists); err != nil {
+			return "", err
+		}
+		if !exists {
+			kept = append(kept, item)
+			continue
+		}
+		const insert = "INSERT INTO artifact_relations (file_id, target_id, kind, label) VALUES ($1, $2, $3, $4) " +
+			"ON CONFLICT DO NOTHING"
+		if _, err := tx.ExecContext(ctx, insert, r.FileID, r.TargetID, string(r.Kind), r.Label); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(kept, "|"), nil
+}
+
+// migrateLinks saves the external links of the pipe-delimited list of the artifact id,
+// and returns the list of items that were not saved.
+func migrateLinks(ctx context.Context, tx *sql.Tx, id int64, list string) (string, error) {
+	var kept []string
+	for item := range strings.SplitSeq(list, "|") {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		links := ParseLinks(id, item)
+		if len(links) == 0 {
+			kept = append(kept, item)
+			continue
+		}
+		l := links[0]
+		const insert = "INSERT INTO artifact_links (file_id, kind, label, url) VALUES ($1, $2, $3, $4) " +
+			"ON CONFLICT DO NOTHING"
+		if _, err := tx.ExecContext(ctx, insert, l.FileID, string(l.Kind), l.Label, l.URL); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(kept, "|"), nil
+}
+
+// RelationMap returns the relations of all the artifacts to the public artifacts, keyed by the artifact id.
+func RelationMap(ctx context.Context, exec boil.ContextExecutor) (map[int64][]Relation, error) {
+	const msg = "relation map"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT r.id, r.file_id, r.target_id, r.kind, r.label, false AS reverse, " +
+		"COALESCE(NULLIF(f.record_title, ''), f.filename, '') AS title " +
+		"FROM artifact_relations r JOIN files f ON f.id = r.target_id " +
+		"WHERE f.deletedat IS NULL ORDER BY r.file_id, r.kind, r.id"
+	var rels []Relation
+	if err := queries.Raw(query).Bind(ctx, exec, &rels); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	m := make(map[int64][]Relation)
+	for _, r := range rels {
+		m[r.FileID] = append(m[r.FileID], r)
+	}
+	return m, nil
+}
+
+// LinkMap returns the external links of all the artifacts, keyed by the artifact id.
+func LinkMap(ctx context.Context, exec boil.ContextExecutor) (map[int64][]Link, error) {
+	const msg = "link map"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT id, file_id, kind, label, url FROM artifact_links ORDER BY file_id, kind, id"
+	var links []Link
+	if err := queries.Raw(query).Bind(ctx, exec, &links); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	m := make(map[int64][]Link)
+	for _, l := range links {
+		m[l.FileID] = append(m[l.FileID], l)
+	}
+	return m, nil
+}
+
+// RelationKey returns the artifact id of the value, which is either an obfuscated artifact key,
+// such as "9f1c2", or the URL of an artifact page, such as "https://defacto2.net/f/9f1c2".
+// Zero is returned if the value is not an artifact key.
+func RelationKey(s string) int64 {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "https://defacto2.net")
+	s = strings.Trim(strings.TrimPrefix(s, "/f/"), "/")
+	if s == "" {
+		return 0
+	}
+	return int64(helper.DeobfuscateID(s))
+}
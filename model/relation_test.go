@@ -0,0 +1,84 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestRelationKind(t *testing.T) {
+	t.Parallel()
+	for _, kind := range model.RelationKinds() {
+		be.True(t, kind.Valid())
+		be.True(t, kind.Forward() != "")
+		be.True(t, kind.Reverse() != "")
+	}
+	be.True(t, !model.RelationKind("").Valid())
+	be.True(t, !model.RelationKind("cousin").Valid())
+	be.Equal(t, model.RelationKind("cousin").Forward(), "")
+}
+
+func TestRelation(t *testing.T) {
+	t.Parallel()
+	r := model.Relation{FileID: 1, TargetID: 2, Kind: model.RelationPack}
+	be.Equal(t, r.Other(), int64(2))
+	be.Equal(t, r.Description(), "Part of the pack")
+	r.Reverse = true
+	be.Equal(t, r.Other(), int64(1))
+	be.Equal(t, r.Description(), "Pack contains")
+	r.Label = "Disk one"
+	be.Equal(t, r.Description(), "Disk one")
+}
+
+func TestLinkURL(t *testing.T) {
+	t.Parallel()
+	s, err := model.LinkURL("example.com/doc")
+	be.Err(t, err, nil)
+	be.Equal(t, s, "https://example.com/doc")
+	s, err = model.LinkURL(" http://textfiles.com ")
+	be.Err(t, err, nil)
+	be.Equal(t, s, "http://textfiles.com")
+	_, err = model.LinkURL("ftp://example.com")
+	be.Err(t, err, model.ErrLink)
+	_, err = model.LinkURL("")
+	be.Err(t, err, model.ErrLink)
+}
+
+func TestParseLinks(t *testing.T) {
+	t.Parallel()
+	be.Equal(t, len(model.ParseLinks(1, "")), 0)
+	be.Equal(t, len(model.ParseLinks(1, "example.com")), 0)
+	links := model.ParseLinks(1, "Site;example.com|Video;www.youtube.com/watch?v=1|Mirror;web.archive.org/web/1|;x.net")
+	be.Equal(t, len(links), 4)
+	be.Equal(t, links[0].Kind, model.LinkWebsite)
+	be.Equal(t, links[0].URL, "https://example.com")
+	be.Equal(t, links[1].Kind, model.LinkVideo)
+	be.Equal(t, links[2].Kind, model.LinkArchive)
+	be.Equal(t, links[3].Label, "https://x.net")
+	for _, link := range links {
+		be.Equal(t, link.FileID, int64(1))
+		be.True(t, link.Kind.Valid())
+	}
+}
+
+func TestRelationsNil(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	_, err := model.Relations(ctx, nil, 1, false)
+	be.Err(t, err)
+	be.Err(t, model.AddRelation(ctx, nil, model.Relation{}))
+	be.Err(t, model.DeleteRelation(ctx, nil, 1, 1))
+	_, err = model.Links(ctx, nil, 1)
+	be.Err(t, err)
+	be.Err(t, model.AddLink(ctx, nil, model.Link{}))
+	be.Err(t, model.DeleteLink(ctx, nil, 1, 1))
+	_, err = model.MigrateRelations(ctx, nil)
+	be.Err(t, err)
+	_, err = model.RelationMap(ctx, nil)
+	be.Err(t, err)
+	_, err = model.LinkMap(ctx, nil)
+	be.Err(t, err)
+	be.Equal(t, model.RelationKey(""), int64(0))
+	be.Equal(t, len(model.ParseRelations(1, "")), 0)
+}
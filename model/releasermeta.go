@@ -0,0 +1,216 @@
+package model
+
+// Package file releasermeta.go contains the database queries for the editor managed metadata
+// of the releasers, such as the styled names, initialisms, websites and the ids of the releasers
+// on other Scene websites. The metadata replaces the lists that were compiled into the application.
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/Defacto2/server/handler/releaser/meta"
+	namer "github.com/Defacto2/server/handler/releaser/name"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+)
+
+var ErrSlug = errors.New("releaser slug is invalid")
+
+// releaserMeta is a row of the releasers table, the lists are JSON arrays.
+type releaserMeta struct {
+	Slug        string `boil:"slug"`
+	Name        string `boil:"name"`
+	Initialisms []byte `boil:"initialisms"`
+	CSDb        int    `boil:"csdb"`
+	Demozoo     int    `boil:"demozoo"`
+	Janeway     int    `boil:"janeway"`
+	Sixteen     string `boil:"sixteen"`
+	Websites    []byte `boil:"websites"`
+	Tidbits     []byte `boil:"tidbits"`
+}
+
+// releaser returns the metadata of the row.
+func (row releaserMeta) releaser() (meta.Releaser, error) {
+	r := meta.Releaser{
+		Slug:    row.Slug,
+		Name:    row.Name,
+		CSDb:    row.CSDb,
+		Demozoo: row.Demozoo,
+		Janeway: row.Janeway,
+		Sixteen: row.Sixteen,
+	}
+	for _, list := range []struct {
+		b []byte
+		v any
+	}{
+		{row.Initialisms, &r.Initialisms},
+		{row.Websites, &r.Websites},
+		{row.Tidbits, &r.Tidbits},
+	} {
+		if len(list.b) == 0 {
+			continue
+		}
+		if err := json.Unmarshal(list.b, list.v); err != nil {
+			return meta.Releaser{}, fmt.Errorf("releaser %s: %w", row.Slug, err)
+		}
+	}
+	return r, nil
+}
+
+const releaserMetaSelect = "SELECT slug, name, initialisms, csdb, demozoo, janeway, sixteen, websites, tidbits " +
+	"FROM releasers "
+
+// ReleaserMetas returns the metadata of all the releasers sorted by their slugs.
+func ReleaserMetas(ctx context.Context, exec boil.ContextExecutor) ([]meta.Releaser, error) {
+	const msg = "releaser metas"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	var rows []releaserMeta
+	if err := queries.Raw(releaserMetaSelect+"ORDER BY slug").Bind(ctx, exec, &rows); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	rels := make([]meta.Releaser, 0, len(rows))
+	for _, row := range rows {
+		r, err := row.releaser()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", msg, err)
+		}
+		rels = append(rels, r)
+	}
+	return rels, nil
+}
+
+// OneReleaserMeta returns the metadata of the releaser slug.
+func OneReleaserMeta(ctx context.Context, exec boil.ContextExecutor, slug string) (meta.Releaser, error) {
+	const msg = "one releaser meta"
+	if err := nils.Check(ctx, exec); err != nil {
+		return meta.Releaser{}, fmt.Errorf("%s: %w", msg, err)
+	}
+	var row releaserMeta
+	if err := queries.Raw(releaserMetaSelect+"WHERE slug = $1", slug).Bind(ctx, exec, &row); err != nil {
+		return meta.Releaser{}, fmt.Errorf("%s %q: %w", msg, slug, err)
+	}
+	return row.releaser()
+}
+
+// UpsertReleaserMeta saves the metadata of the releaser, replacing any existing metadata of the slug.
+func UpsertReleaserMeta(ctx context.Context, exec boil.ContextExecutor, r meta.Releaser) error {
+	const insert = "INSERT INTO releasers " +
+		"(slug, name, initialisms, csdb, demozoo, janeway, sixteen, websites, tidbits, updated) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, now()) " +
+		"ON CONFLICT (slug) DO UPDATE SET name = EXCLUDED.name, initialisms = EXCLUDED.initialisms, " +
+		"csdb = EXCLUDED.csdb, demozoo = EXCLUDED.demozoo, janeway = EXCLUDED.janeway, " +
+		"sixteen = EXCLUDED.sixteen, websites = EXCLUDED.websites, tidbits = EXCLUDED.tidbits, updated = now()"
+	return releaserMetaExec(ctx, exec, "upsert releaser meta", insert, r)
+}
+
+// SeedReleaserMeta saves the metadata of the releasers to an empty releasers table
+// and returns the number of releasers that were added. A table that already contains
+// any metadata is never changed, so the edits and deletions made by the editors are kept.
+// The releasers are saved in a single transaction, so a failed seed can be retried.
+func SeedReleaserMeta(ctx context.Context, db *sql.DB, rels ...meta.Releaser) (int64, error) {
+	const msg = "seed releaser meta"
+	if err := nils.Check(ctx, db); err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s begin tx: %w", msg, err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM releasers)").Scan(&exists); err != nil {
+		return 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	if exists {
+		return 0, nil
+	}
+	const insert = "INSERT INTO releasers " +
+		"(slug, name, initialisms, csdb, demozoo, janeway, sixteen, websites, tidbits) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT (slug) DO NOTHING"
+	var sum int64
+	for _, r := range rels {
+		args, err := releaserMetaArgs(r)
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", msg, err)
+		}
+		res, err := tx.ExecContext(ctx, insert, args...)
+		if err != nil {
+			return 0, fmt.Errorf("%s %q: %w", msg, r.Slug, err)
+		}
+		i, err := res.RowsAffected()
+		if err != nil {
+			return 0, fmt.Errorf("%s %q: %w", msg, r.Slug, err)
+		}
+		sum += i
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("%s commit: %w", msg, err)
+	}
+	return sum, nil
+}
+
+// DeleteReleaserMeta removes the metadata of the releaser slug.
+func DeleteReleaserMeta(ctx context.Context, exec boil.ContextExecutor, slug string) error {
+	const msg = "delete releaser meta"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if _, err := exec.ExecContext(ctx, "DELETE FROM releasers WHERE slug = $1", slug); err != nil {
+		return fmt.Errorf("%s %q: %w", msg, slug, err)
+	}
+	return nil
+}
+
+// releaserMetaExec runs the query statement using the metadata of the releaser as the arguments.
+func releaserMetaExec(ctx context.Context, exec boil.ContextExecutor, msg, query string, r meta.Releaser) error {
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	args, err := releaserMetaArgs(r)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if _, err := exec.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("%s %q: %w", msg, r.Slug, err)
+	}
+	return nil
+}
+
+// releaserMetaArgs returns the query arguments of the releaser metadata,
+// with the lists encoded as JSON arrays.
+func releaserMetaArgs(r meta.Releaser) ([]any, error) {
+	if !namer.Path(r.Slug).Valid() {
+		return nil, fmt.Errorf("%w: %q", ErrSlug, r.Slug)
+	}
+	jsonArray := func(v any, size int) (string, error) {
+		if size == 0 {
+			return "[]", nil
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("releaser %s: %w", r.Slug, err)
+		}
+		return string(b), nil
+	}
+	inits, err := jsonArray(r.Initialisms, len(r.Initialisms))
+	if err != nil {
+		return nil, err
+	}
+	sites, err := jsonArray(r.Websites, len(r.Websites))
+	if err != nil {
+		return nil, err
+	}
+	tidbits, err := jsonArray(r.Tidbits, len(r.Tidbits))
+	if err != nil {
+		return nil, err
+	}
+	return []any{r.Slug, r.Name, inits, r.CSDb, r.Demozoo, r.Janeway, r.Sixteen, sites, tidbits}, nil
+}
@@ -0,0 +1,23 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/Defacto2/server/handler/releaser/meta"
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestReleaserMetaNilExec(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	_, err := model.ReleaserMetas(ctx, nil)
+	be.Err(t, err)
+	_, err = model.OneReleaserMeta(ctx, nil, "defacto2")
+	be.Err(t, err)
+	be.Err(t, model.UpsertReleaserMeta(ctx, nil, meta.Releaser{Slug: "defacto2"}))
+	be.Err(t, model.DeleteReleaserMeta(ctx, nil, "defacto2"))
+	i, err := model.SeedReleaserMeta(ctx, nil, meta.Releaser{Slug: "defacto2"})
+	be.Err(t, err)
+	be.Equal(t, i, int64(0))
+}
@@ -0,0 +1,191 @@
+package model
+
+// Package file rename.go contains the releaser rename and merge operation, which replaces a releaser
+// in the group_brand_for and group_brand_by columns of every artifact in a single transaction, and
+// keeps the former releaser as an alias so the old URLs are redirected to the new releaser.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Defacto2/server/handler/releaser"
+	namer "github.com/Defacto2/server/handler/releaser/name"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/null/v8"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+)
+
+var ErrRename = errors.New("releaser rename is invalid")
+
+// ReleaserRename renames the From releaser to the To releaser.
+// When the To releaser already has artifacts, the two releasers are merged.
+type ReleaserRename struct {
+	From string // From is the URL path slug of the releaser to rename.
+	To   string // To is the URL path slug of the new or existing releaser.
+}
+
+// Validate the slugs of the releasers.
+func (r ReleaserRename) Validate() error {
+	if !namer.Path(r.From).Valid() || !namer.Path(r.To).Valid() {
+		return fmt.Errorf("%w: %q to %q", ErrSlug, r.From, r.To)
+	}
+	if r.From == r.To || releaser.Index(r.From) == releaser.Index(r.To) {
+		return fmt.Errorf("%w: %q and %q are the same releaser", ErrRename, r.From, r.To)
+	}
+	return nil
+}
+
+// Apply the rename to every artifact of the From releaser in a single transaction,
+// including the artifacts that are hidden or waiting for approval.
+// When dryRun is true the changes are rolled back and the report is a preview of the changes.
+// Otherwise the From releaser is saved as an alias of the To releaser, and the releaser metadata
+// of the From releaser is moved to the To releaser, unless the To releaser has its own metadata.
+// The Google account ID of the editor is taken from the context, see [WithEditor].
+func (r ReleaserRename) Apply(ctx context.Context, db *sql.DB, dryRun bool) (BulkReport, error) {
+	const msg = "releaser rename"
+	const format = msg + " %s: %w"
+	report := BulkReport{Op: BulkReleasers, DryRun: dryRun}
+	if err := nils.Check(ctx, db); err != nil {
+		return report, fmt.Errorf(format, "check", err)
+	}
+	if err := r.Validate(); err != nil {
+		return report, fmt.Errorf(format, "validate", err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return report, fmt.Errorf(format, "begin tx", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	from, to := releaser.Index(r.From), releaser.Index(r.To)
+	fs, err := models.Files(
+		qm.WithDeleted(),
+		qm.Where("upper(group_brand_for) = ? OR upper(group_brand_by) = ?", from, from),
+		qm.OrderBy("id ASC"),
+	).All(ctx, tx)
+	if err != nil {
+		return report, fmt.Errorf(format, "select", err)
+	}
+	report.Matched = len(fs)
+	for _, f := range fs {
+		old := *f
+		renameReleasers(f, from, to)
+		diffs := Diffs(&old, f)
+		if len(diffs) == 0 {
+			continue
+		}
+		name := f.RecordTitle.String
+		if name == "" {
+			name = f.Filename.String
+		}
+		report.Results = append(report.Results, BulkResult{ID: f.ID, Name: name, Diffs: diffs})
+		if dryRun {
+			continue
+		}
+		if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
+			return report, fmt.Errorf("%s update %d: %w", msg, f.ID, err)
+		}
+		if err = RecordAudit(ctx, tx, &old, f); err != nil {
+			return report, fmt.Errorf("%s record audit: %w", msg, err)
+		}
+		if err = RecordChange(ctx, tx, f.ID, Updated); err != nil {
+			return report, fmt.Errorf("%s record change: %w", msg, err)
+		}
+	}
+	if dryRun {
+		return report, nil
+	}
+	if err = SaveReleaserAlias(ctx, tx, r.From, r.To); err != nil {
+		return report, fmt.Errorf(format, "alias", err)
+	}
+	const move = "UPDATE releasers SET slug = $2, updated = now() WHERE slug = $1 " +
+		"AND NOT EXISTS (SELECT 1 FROM releasers WHERE slug = $2)"
+	if _, err = tx.ExecContext(ctx, move, r.From, r.To); err != nil {
+		return report, fmt.Errorf(format, "metadata", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return report, fmt.Errorf(fmttx, msg, err)
+	}
+	return report, nil
+}
+
+// renameReleasers replaces the from releaser with the to releaser in the releaser columns of the file record.
+// A merge that results in the same releaser in both columns keeps only the group_brand_for column.
+func renameReleasers(f *models.File, from, to string) {
+	if strings.EqualFold(f.GroupBrandFor.String, from) {
+		f.GroupBrandFor = null.StringFrom(to)
+	}
+	if strings.EqualFold(f.GroupBrandBy.String, from) {
+		f.GroupBrandBy = null.StringFrom(to)
+	}
+	if strings.EqualFold(f.GroupBrandFor.String, f.GroupBrandBy.String) {
+		f.GroupBrandBy = null.StringFrom("")
+	}
+}
+
+// SaveReleaserAlias saves the alias slug of a renamed or merged releaser that redirects to the target slug.
+// Any existing aliases of the alias slug are redirected to the target slug,
+// and the target slug stops being an alias, so the aliases never chain or loop.
+func SaveReleaserAlias(ctx context.Context, exec boil.ContextExecutor, alias, target string) error {
+	const msg = "save releaser alias"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if !namer.Path(alias).Valid() || !namer.Path(target).Valid() || alias == target {
+		return fmt.Errorf("%s: %w: %q to %q", msg, ErrSlug, alias, target)
+	}
+	for _, stmt := range []string{
+		"UPDATE releaser_aliases SET target = $2 WHERE target = $1",
+		"DELETE FROM releaser_aliases WHERE alias = $2",
+		"INSERT INTO releaser_aliases (alias, target) VALUES ($1, $2) " +
+			"ON CONFLICT (alias) DO UPDATE SET target = EXCLUDED.target, created = now()",
+	} {
+		if _, err := exec.ExecContext(ctx, stmt, alias, target); err != nil {
+			return fmt.Errorf("%s %q: %w", msg, alias, err)
+		}
+	}
+	return nil
+}
+
+// releaserAlias is a former releaser slug that redirects to the target slug.
+type releaserAlias struct {
+	Alias  string `boil:"alias"`  // Alias is the slug of the renamed or merged releaser.
+	Target string `boil:"target"` // Target is the slug of the releaser to redirect to.
+}
+
+// ReleaserAliases returns the aliases of the renamed and merged releasers,
+// as a map of the former releaser slugs to their target slugs.
+func ReleaserAliases(ctx context.Context, exec boil.ContextExecutor) (map[string]string, error) {
+	const msg = "releaser aliases"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	var rows []releaserAlias
+	if err := queries.Raw("SELECT alias, target FROM releaser_aliases").Bind(ctx, exec, &rows); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	aliases := make(map[string]string, len(rows))
+	for _, row := range rows {
+		aliases[row.Alias] = row.Target
+	}
+	return aliases, nil
+}
+
+// DeleteReleaserAlias removes the alias slug, so it no longer redirects.
+func DeleteReleaserAlias(ctx context.Context, exec boil.ContextExecutor, alias string) error {
+	const msg = "delete releaser alias"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if _, err := exec.ExecContext(ctx, "DELETE FROM releaser_aliases WHERE alias = $1", alias); err != nil {
+		return fmt.Errorf("%s %q: %w", msg, alias, err)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestReleaserRenameValidate(t *testing.T) {
+	t.Parallel()
+	be.Err(t, model.ReleaserRename{}.Validate(), model.ErrSlug)
+	be.Err(t, model.ReleaserRename{From: "acid", To: "acid#"}.Validate(), model.ErrSlug)
+	be.Err(t, model.ReleaserRename{From: "acid", To: "acid"}.Validate(), model.ErrRename)
+	be.Err(t, model.ReleaserRename{From: "acid", To: "acid-productions"}.Validate(), nil)
+}
+
+func TestReleaserRenameNil(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	r := model.ReleaserRename{From: "acid", To: "acid-productions"}
+	_, err := r.Apply(ctx, nil, true)
+	be.Err(t, err)
+	be.Err(t, model.SaveReleaserAlias(ctx, nil, "acid", "acid-productions"))
+	_, err = model.ReleaserAliases(ctx, nil)
+	be.Err(t, err)
+	be.Err(t, model.DeleteReleaserAlias(ctx, nil, "acid"))
+}
@@ -0,0 +1,265 @@
+package model
+
+// Package file sauce.go contains the database queries for the SAUCE metadata
+// of the artifact downloads and of the files contained in their archives.
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Defacto2/server/handler/releaser"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/sauces"
+	"github.com/aarondl/null/v8"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+)
+
+// ClauseSauce is the where clause of the artifacts with the SAUCE metadata of an author or group
+// that matches the case-insensitive pattern of both arguments.
+const ClauseSauce = "id IN (SELECT file_id FROM file_sauces WHERE author ILIKE ? OR grp ILIKE ?)"
+
+// Sauces returns the SAUCE metadata of the artifact id, the record of the download is first
+// and is followed by the records of the archive members ordered by their path.
+func Sauces(ctx context.Context, exec boil.ContextExecutor, id int64) ([]sauces.Record, error) {
+	const msg = "sauces"
+	if err := nils.Check(ctx, exec); err != nil {
+		return nil, fmt.Errorf("%s: %w", msg, err)
+	}
+	const query = "SELECT member, title, author, grp, date, data_type, file_type, type_name, " +
+		"width, lines, flags, font, comments FROM file_sauces WHERE file_id = $1 ORDER BY member"
+	var recs []sauces.Record
+	if err := queries.Raw(query, id).Bind(ctx, exec, &recs); err != nil {
+		return nil, fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	return recs, nil
+}
+
+// SauceStamps returns the last modified times of the artifact downloads when their SAUCE metadata was read,
+// keyed by the artifact id.
+func SauceStamps(ctx context.Context, exec boil.ContextExecutor) (map[int64]time.Time, error) {
+	nils.BoilExecCrash(exec)
+	const query = "SELECT file_id, MAX(stamp) AS modified FROM file_sauces WHERE member = '' GROUP BY file_id"
+	var stamps []TextStamp
+	if err := queries.Raw(query).Bind(ctx, exec, &stamps); err != nil {
+		return nil, fmt.Errorf("sauce stamps: %w", err)
+	}
+	m := make(map[int64]time.Time, len(stamps))
+	for _, s := range stamps {
+		m[s.FileID] = s.Modified
+	}
+	return m, nil
+}
+
+// ReplaceSauce removes any existing SAUCE metadata of the download of the artifact id and saves the record.
+// A nil record only removes the existing metadata.
+// The stamp should be the last modified time of the artifact download.
+func ReplaceSauce(ctx context.Context, exec boil.ContextExecutor,
+	id int64, stamp time.Time, rec *sauces.Record,
+) error {
+	const msg = "replace sauce"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if id < 1 {
+		return fmt.Errorf("%s: %w: %d", msg, ErrID, id)
+	}
+	const remove = "DELETE FROM file_sauces WHERE file_id = $1 AND member = ''"
+	if _, err := exec.ExecContext(ctx, remove, id); err != nil {
+		return fmt.Errorf("%s delete: %w", msg, err)
+	}
+	if rec == nil {
+		return nil
+	}
+	r := *rec
+	r.Member = ""
+	if err := insertSauce(ctx, exec, id, stamp, r); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return nil
+}
+
+// ReplaceSauces removes any existing SAUCE metadata of the archive members of the artifact id
+// and saves the records. The stamp should be the last modified time of the artifact download.
+func ReplaceSauces(ctx context.Context, exec boil.ContextExecutor,
+	id int64, stamp time.Time, recs ...sauces.Record,
+) error {
+	const msg = "replace sauces"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if id < 1 {
+		return fmt.Errorf("%s: %w: %d", msg, ErrID, id)
+	}
+	const remove = "DELETE FROM file_sauces WHERE file_id = $1 AND member <> ''"
+	if _, err := exec.ExecContext(ctx, remove, id); err != nil {
+		return fmt.Errorf("%s delete: %w", msg, err)
+	}
+	for _, rec := range recs {
+		if rec.Member == "" {
+			continue
+		}
+		if err := insertSauce(ctx, exec, id, stamp, rec); err != nil {
+			return fmt.Errorf("%s: %w", msg, err)
+		}
+	}
+	return nil
+}
+
+// insertSauce saves the SAUCE record of the artifact id.
+func insertSauce(ctx context.Context, exec boil.ContextExecutor, id int64, stamp time.Time, rec sauces.Record) error {
+	const insert = "INSERT INTO file_sauces " +
+		"(file_id, member, title, author, grp, date, data_type, file_type, type_name, " +
+		"width, lines, flags, font, comments, stamp) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15) ON CONFLICT DO NOTHING"
+	if _, err := exec.ExecContext(ctx, insert, id, rec.Member, rec.Title, rec.Author, rec.Group, rec.Date,
+		rec.DataType, rec.FileType, rec.TypeName, rec.Width, rec.Lines, rec.Flags, rec.Font, rec.Comments,
+		stamp); err != nil {
+		return fmt.Errorf("insert %q: %w", rec.Member, err)
+	}
+	return nil
+}
+
+// SaveSauce reads the SAUCE metadata of the src download file and saves it for the artifact id.
+// Any existing metadata of the download is removed when the file has no SAUCE.
+func SaveSauce(ctx context.Context, exec boil.ContextExecutor, id int64, src string) error {
+	const msg = "save sauce"
+	if err := nils.Check(ctx, exec); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	st, err := os.Stat(src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	rec, ok, err := sauces.File(src)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	// the database stores timestamps to the microsecond
+	stamp := st.ModTime().Truncate(time.Microsecond)
+	if !ok {
+		return ReplaceSauce(ctx, exec, id, stamp, nil)
+	}
+	return ReplaceSauce(ctx, exec, id, stamp, &rec)
+}
+
+// IndexSauces walks the artifacts and saves the SAUCE metadata of the downloads that are new or
+// have been modified since their metadata was read. The SAUCE metadata of the archive members
+// is saved with the manifests. As only the end of each file is read, the downloads without
+// any SAUCE metadata are checked again on every walk.
+// It returns the number of downloads with SAUCE metadata and the number skipped as unmodified.
+func IndexSauces(ctx context.Context, exec boil.ContextExecutor, download string) (int, int, error) {
+	nils.BoilExecCrash(exec)
+	const msg = "index sauces"
+	indexed, skipped := 0, 0
+	stamps, err := SauceStamps(ctx, exec)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	arts, err := TextSources(ctx, exec)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	for _, art := range arts {
+		if err := ctx.Err(); err != nil {
+			return indexed, skipped, fmt.Errorf("%s: %w", msg, err)
+		}
+		src := filepath.Join(download, art.UUID.String)
+		st, err := os.Stat(src)
+		if err != nil {
+			continue
+		}
+		last, ok := stamps[art.ID]
+		if ok && !st.ModTime().Truncate(time.Microsecond).After(last) {
+			skipped++
+			continue
+		}
+		rec, found, err := sauces.File(src)
+		if err != nil || (!found && !ok) {
+			continue
+		}
+		stamp := st.ModTime().Truncate(time.Microsecond)
+		if !found {
+			// the modified download no longer has any SAUCE metadata
+			if err := ReplaceSauce(ctx, exec, art.ID, stamp, nil); err != nil {
+				return indexed, skipped, fmt.Errorf("%s: %w", msg, err)
+			}
+			continue
+		}
+		if err := ReplaceSauce(ctx, exec, art.ID, stamp, &rec); err != nil {
+			return indexed, skipped, fmt.Errorf("%s: %w", msg, err)
+		}
+		indexed++
+	}
+	return indexed, skipped, nil
+}
+
+// UpdateSauce updates the title, the creators and the date issued of the artifact id using the
+// metadata suggested by its SAUCE records. The empty values of the suggestion are ignored,
+// and the creator is added to either the writers or the artists when they are not already credited.
+func UpdateSauce(ctx context.Context, db *sql.DB, id int64, s sauces.Suggestion) error {
+	const msg = "update sauce"
+	const format = msg + " %s: %w"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf(format, "check", err)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf(format, "begin tx", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	f, err := OneFile(ctx, tx, id)
+	if err != nil {
+		return fmt.Errorf(format, "find file", err)
+	}
+	old := *f
+	if title := strings.TrimSpace(s.Title); title != "" {
+		f.RecordTitle = null.StringFrom(title)
+	}
+	if name := releaser.Clean(s.Creator); name != "" {
+		if s.Writer {
+			f.CreditText = credit(f.CreditText, name)
+		} else {
+			f.CreditIllustration = credit(f.CreditIllustration, name)
+		}
+	}
+	if s.Year > 0 {
+		y, m, d := strconv.Itoa(int(s.Year)), strconv.Itoa(int(s.Month)), strconv.Itoa(int(s.Day))
+		setDateIssued(f, y, m, d)
+	}
+	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
+		return fmt.Errorf(format, "update", err)
+	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf(format, "tx commit", err)
+	}
+	return nil
+}
+
+// credit returns the comma separated names with the name appended,
+// unless the name is already included.
+func credit(names null.String, name string) null.String {
+	list := []string{}
+	for n := range strings.SplitSeq(names.String, ",") {
+		if n = strings.TrimSpace(n); n != "" {
+			list = append(list, n)
+		}
+	}
+	if slices.ContainsFunc(list, func(n string) bool { return strings.EqualFold(n, name) }) {
+		return names
+	}
+	return null.StringFrom(strings.Join(append(list, name), ","))
+}
@@ -0,0 +1,21 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/internal/sauces"
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestSaucesNil(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	_, err := model.Sauces(ctx, nil, 1)
+	be.Err(t, err)
+	be.Err(t, model.ReplaceSauce(ctx, nil, 1, time.Now(), nil))
+	be.Err(t, model.ReplaceSauces(ctx, nil, 1, time.Now()))
+	be.Err(t, model.SaveSauce(ctx, nil, 1, ""))
+	be.Err(t, model.UpdateSauce(ctx, nil, 1, sauces.Suggestion{}))
+}
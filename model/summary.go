@@ -31,7 +31,8 @@ type Summary struct {
 	MaxYear  sql.NullInt16 `boil:"max_year"`    // Maximum or latest year of the files.
 }
 
-// ByDescription saves the summary statistics for the file description search.
+// ByDescription saves the summary statistics for the file description search,
+// which includes the artifacts with a SAUCE author or group that matches a term.
 func (s *Summary) ByDescription(ctx context.Context, exec boil.ContextExecutor, terms []string) error {
 	nils.BoilExecCrash(exec)
 	sum := string(postgres.Summary())
@@ -50,6 +51,11 @@ func (s *Summary) ByDescription(ctx context.Context, exec boil.ContextExecutor,
 				"to_tsvector('english', concat_ws(' ', files.record_title, files.comment)) @@ plainto_tsquery('english', $%d)",
 				i+1,
 			),
+			fmt.Sprintf(
+				"files.id IN (SELECT file_id FROM file_sauces "+
+					"WHERE author ILIKE '%%' || $%d || '%%' OR grp ILIKE '%%' || $%d || '%%')",
+				i+1, i+1,
+			),
 		)
 	}
 	if len(orConditions) == 0 {
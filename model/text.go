@@ -0,0 +1,219 @@
+package model
+
+// Package text.go contains the database queries for the plain texts,
+// such as NFO, DIZ and README files, that are extracted from the artifacts.
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Defacto2/server/handler/fulltext"
+	"github.com/Defacto2/server/internal/nils"
+	"github.com/Defacto2/server/internal/postgres/models"
+	"github.com/aarondl/null/v8"
+	"github.com/aarondl/sqlboiler/v4/boil"
+	"github.com/aarondl/sqlboiler/v4/queries"
+	"github.com/aarondl/sqlboiler/v4/queries/qm"
+)
+
+var ErrTextName = errors.New("text name is empty")
+
+// TextMatch is a plain text that matches a full-text search with its artifact details.
+type TextMatch struct {
+	ID          int64       `boil:"id"`           // ID of the artifact.
+	UUID        null.String `boil:"uuid"`         // UUID of the artifact.
+	Filename    null.String `boil:"filename"`     // Filename of the artifact download.
+	RecordTitle null.String `boil:"record_title"` // RecordTitle of the artifact.
+	Name        string      `boil:"name"`         // Name of the text file.
+	Body        string      `boil:"body"`         // Body or content of the text file.
+	Rank        float64     `boil:"rank"`         // Rank is the relevancy of the match.
+}
+
+// Texts is a collection of plain texts that match a full-text search.
+type Texts []*TextMatch
+
+// TextStamp is the last modified time of the indexed texts of an artifact.
+type TextStamp struct {
+	FileID   int64     `boil:"file_id"`
+	Modified time.Time `boil:"modified"`
+}
+
+// Search the plain texts for the terms and save the public artifacts that match,
+// ordered by their relevancy. Multiple terms will match any of the terms.
+func (t *Texts) Search(ctx context.Context, exec boil.ContextExecutor, limit int, terms ...string) error {
+	nils.BoilExecCrash(exec)
+	lookups := make([]string, 0, len(terms))
+	for _, term := range terms {
+		if s := strings.TrimSpace(term); s != "" {
+			lookups = append(lookups, s)
+		}
+	}
+	if len(lookups) == 0 {
+		return ErrTrimmedTerms
+	}
+	if limit < 1 || limit > Maximum {
+		limit = Maximum
+	}
+	const query = "SELECT files.id, files.uuid, files.filename, files.record_title, " +
+		"file_texts.name, file_texts.body, ts_rank(file_texts.body_tsv, q) AS rank " +
+		"FROM file_texts " +
+		"INNER JOIN files ON files.id = file_texts.file_id " +
+		"CROSS JOIN websearch_to_tsquery('simple', $1) AS q " +
+		"WHERE file_texts.body_tsv @@ q AND file_texts.name <> '' AND files." + ClauseNoSoftDel + " " +
+		"ORDER BY rank DESC, files.id ASC LIMIT $2"
+	websearch := strings.Join(lookups, " or ")
+	return queries.Raw(query, websearch, limit).Bind(ctx, exec, t)
+}
+
+// TextStamps returns the last modified times of the indexed texts, keyed by the artifact id.
+func TextStamps(ctx context.Context, exec boil.ContextExecutor) (map[int64]time.Time, error) {
+	nils.BoilExecCrash(exec)
+	const query = "SELECT file_id, MAX(modified) AS modified FROM file_texts GROUP BY file_id"
+	var stamps []TextStamp
+	if err := queries.Raw(query).Bind(ctx, exec, &stamps); err != nil {
+		return nil, fmt.Errorf("text stamps: %w", err)
+	}
+	m := make(map[int64]time.Time, len(stamps))
+	for _, s := range stamps {
+		m[s.FileID] = s.Modified
+	}
+	return m, nil
+}
+
+// TextSources returns the id, uuid, filename and archive content of every artifact,
+// including those that are not public, to locate the texts that can be indexed.
+func TextSources(ctx context.Context, exec boil.ContextExecutor) (models.FileSlice, error) {
+	nils.BoilExecCrash(exec)
+	return models.Files(
+		textSource(),
+		qm.Where(models.FileColumns.UUID+" IS NOT NULL"),
+		qm.WithDeleted(),
+		qm.OrderBy(models.FileColumns.ID)).All(ctx, exec)
+}
+
+func textSource() qm.QueryMod {
+	return qm.Select(
+		models.FileColumns.ID,
+		models.FileColumns.UUID,
+		models.FileColumns.Filename,
+		models.FileColumns.FileZipContent)
+}
+
+// noTexts is the name of the empty text that stamps an artifact without any texts,
+// so the artifact is not read again until it is modified.
+const noTexts = ""
+
+// ReplaceTexts removes any existing texts of the artifact id and saves the named bodies.
+// An artifact without any bodies is saved with an empty text that is ignored by the searches.
+// The modified time should be the last modified time of the source files.
+func ReplaceTexts(ctx context.Context, db *sql.DB,
+	id int64, modified time.Time, bodies map[string]string,
+) error {
+	const msg = "replace texts"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	if id < 1 {
+		return fmt.Errorf("%s: %w: %d", msg, ErrID, id)
+	}
+	for name := range bodies {
+		if name == "" {
+			return fmt.Errorf("%s: %w", msg, ErrTextName)
+		}
+	}
+	if len(bodies) == 0 {
+		bodies = map[string]string{noTexts: ""}
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	const remove = "DELETE FROM file_texts WHERE file_id = $1"
+	if _, err := tx.ExecContext(ctx, remove, id); err != nil {
+		return fmt.Errorf("%s delete: %w", msg, err)
+	}
+	const insert = "INSERT INTO file_texts (file_id, name, body, modified) VALUES ($1, $2, $3, $4)"
+	for name, body := range bodies {
+		if _, err := tx.ExecContext(ctx, insert, id, name, body, modified); err != nil {
+			return fmt.Errorf("%s insert %q: %w", msg, name, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
+	return nil
+}
+
+// IndexText saves the plain texts of the artifact id, such as after a new or a replacement upload,
+// replacing any texts that were previously indexed. The indexed texts are replaced by
+// the empty stamp when the artifact no longer has any texts.
+func IndexText(ctx context.Context, db *sql.DB, src fulltext.Texts, id int64) error {
+	const msg = "index text"
+	if err := nils.Check(ctx, db); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	art, err := models.Files(
+		textSource(),
+		models.FileWhere.ID.EQ(id),
+		qm.WithDeleted()).One(ctx, db)
+	if err != nil {
+		return fmt.Errorf("%s %d: %w", msg, id, err)
+	}
+	charset, err := Encoding(ctx, db, id)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	bodies := src.Bodies(art, charset)
+	if err := ReplaceTexts(ctx, db, id, src.Modified(art), bodies); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	return nil
+}
+
+// IndexTexts walks the artifacts and saves the plain texts of those that are new or have been
+// modified since they were last indexed. The artifacts without any texts are stamped,
+// and any previous texts of a modified artifact are removed.
+// It returns the number of artifacts indexed and the number skipped as unmodified.
+func IndexTexts(ctx context.Context, db *sql.DB, src fulltext.Texts) (int, int, error) {
+	const msg = "index texts"
+	if err := nils.Check(ctx, db); err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	indexed, skipped := 0, 0
+	stamps, err := TextStamps(ctx, db)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	arts, err := TextSources(ctx, db)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	overrides, err := Encodings(ctx, db)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s: %w", msg, err)
+	}
+	for _, art := range arts {
+		if err := ctx.Err(); err != nil {
+			return indexed, skipped, fmt.Errorf("%s: %w", msg, err)
+		}
+		modified := src.Modified(art)
+		if modified.IsZero() {
+			continue
+		}
+		if last, ok := stamps[art.ID]; ok && !modified.After(last) {
+			skipped++
+			continue
+		}
+		bodies := src.Bodies(art, overrides[art.ID])
+		if err := ReplaceTexts(ctx, db, art.ID, modified, bodies); err != nil {
+			return indexed, skipped, fmt.Errorf("%s: %w", msg, err)
+		}
+		indexed++
+	}
+	return indexed, skipped, nil
+}
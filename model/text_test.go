@@ -0,0 +1,19 @@
+package model_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Defacto2/server/handler/fulltext"
+	"github.com/Defacto2/server/model"
+	"github.com/nalgeon/be"
+)
+
+func TestTextNil(t *testing.T) {
+	t.Parallel()
+	ctx := t.Context()
+	be.Err(t, model.ReplaceTexts(ctx, nil, 1, time.Now(), nil))
+	be.Err(t, model.IndexText(ctx, nil, fulltext.Texts{}, 1))
+	_, _, err := model.IndexTexts(ctx, nil, fulltext.Texts{})
+	be.Err(t, err)
+}
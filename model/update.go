@@ -79,10 +79,12 @@ func UpdateBoolFrom(ctx context.Context, db *sql.DB, column boolFrom, id int64,
 	if err != nil {
 		return fmt.Errorf(format, "begin tx", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(format, "one file", err)
 	}
+	old := *f
 	const yes, no = int16(1), int16(0)
 	i := yes
 	if val {
@@ -103,6 +105,12 @@ func UpdateBoolFrom(ctx context.Context, db *sql.DB, column boolFrom, id int64,
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf("%s %v %v: %w", msg, column, val, err)
 	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(format, "tx commit", err)
 	}
@@ -120,14 +128,22 @@ func UpdateEmulateRunProgram(ctx context.Context, db *sql.DB, id int64, val stri
 	if err != nil {
 		return fmt.Errorf(format, "begin tx", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(format, "one file", err)
 	}
+	old := *f
 	f.DoseeRunProgram = null.StringFrom(s)
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf(format, s, err)
 	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(format, "tx commit", err)
 	}
@@ -153,14 +169,22 @@ func UpdateEmulateMachine(ctx context.Context, db *sql.DB, id int64, val string)
 	if err != nil {
 		return fmt.Errorf(format, "begin tx", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(format, "one file", err)
 	}
+	old := *f
 	f.DoseeHardwareGraphic = null.StringFrom(validate)
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf(format, validate, err)
 	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(format, "tx commit", err)
 	}
@@ -186,14 +210,22 @@ func UpdateEmulateCPU(ctx context.Context, db *sql.DB, id int64, val string) err
 	if err != nil {
 		return fmt.Errorf(format, "begin tx", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(format, "one file", err)
 	}
+	old := *f
 	f.DoseeHardwareCPU = null.StringFrom(validate)
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf(format, validate, err)
 	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(format, "tx commit", err)
 	}
@@ -219,14 +251,22 @@ func UpdateEmulateSfx(ctx context.Context, db *sql.DB, id int64, val string) err
 	if err != nil {
 		return fmt.Errorf(format, "begin tx", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(format, "one file", err)
 	}
+	old := *f
 	f.DoseeHardwareAudio = null.StringFrom(validate)
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf(format, validate, err)
 	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(format, "tx commit", err)
 	}
@@ -296,16 +336,6 @@ func UpdatePouet(ctx context.Context, db *sql.DB, id int64, val string) error {
 	return UpdateInt64From(ctx, db, pouetProd, id, val)
 }
 
-// UpdateRelations updates the ListRelations column value with val.
-func UpdateRelations(ctx context.Context, db *sql.DB, id int64, val string) error {
-	return UpdateStringFrom(ctx, db, relations, id, val)
-}
-
-// UpdateSites updates the ListLinks column with val.
-func UpdateSites(ctx context.Context, db *sql.DB, id int64, val string) error {
-	return UpdateStringFrom(ctx, db, sites, id, val)
-}
-
 // UpdateTag updates the Section column with val.
 func UpdateTag(ctx context.Context, db *sql.DB, id int64, val string) error {
 	return UpdateStringFrom(ctx, db, section, id, val)
@@ -341,10 +371,12 @@ func UpdateInt64From(ctx context.Context, db *sql.DB, column int64From, id int64
 	if err != nil {
 		return fmt.Errorf(format, err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(fmtVal, column, err)
 	}
+	old := *f
 	if strings.TrimSpace(val) == "" {
 		val = "0"
 	}
@@ -373,6 +405,12 @@ func UpdateInt64From(ctx context.Context, db *sql.DB, column int64From, id int64
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf("%s %v %s: %w", msg, column, val, err)
 	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(format, err)
 	}
@@ -416,16 +454,24 @@ func UpdateStringFrom(ctx context.Context, db *sql.DB, column stringFrom, id int
 	if err != nil {
 		return fmt.Errorf(format, err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(fmtVal, column, err)
 	}
+	old := *f
 	if err = updateStringCases(f, column, val); err != nil {
 		return fmt.Errorf(format, err)
 	}
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf("%v %s: %w", column, val, err)
 	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(format, err)
 	}
@@ -494,10 +540,12 @@ func UpdateCreators(ctx context.Context, db *sql.DB, id int64, text, ill, prog,
 	if err != nil {
 		return fmt.Errorf(format, "begin tx", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(format, "find file", err)
 	}
+	old := *f
 	f.CreditText = null.StringFrom(text)
 	f.CreditIllustration = null.StringFrom(ill)
 	f.CreditProgram = null.StringFrom(prog)
@@ -505,15 +553,22 @@ func UpdateCreators(ctx context.Context, db *sql.DB, id int64, text, ill, prog,
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf(format, "update", err)
 	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(format, "tx commit", err)
 	}
 	return nil
 }
 
-// UpdateLinks updates the youtube, 16colors, relations, sites, demozoo, and pouet columns with the values provided.
+// UpdateLinks updates the youtube, 16colors, github, demozoo, and pouet columns with the values provided.
+// The relations and external links of the artifact are edited using [AddRelation] and [AddLink].
 func UpdateLinks(ctx context.Context, db *sql.DB, id int64,
-	youtube, colors16, github, relations, sites string,
+	youtube, colors16, github string,
 	demozoo, pouet int64,
 ) error {
 	const msg = "update links"
@@ -525,20 +580,26 @@ func UpdateLinks(ctx context.Context, db *sql.DB, id int64,
 	if err != nil {
 		return fmt.Errorf(format, "begin tx", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(format, "find file", err)
 	}
+	old := *f
 	f.WebIDYoutube = null.StringFrom(youtube)
 	f.WebID16colors = null.StringFrom(colors16)
 	f.WebIDGithub = null.StringFrom(github)
-	f.ListRelations = null.StringFrom(relations)
-	f.ListLinks = null.StringFrom(sites)
 	f.WebIDDemozoo = null.Int64From(demozoo)
 	f.WebIDPouet = null.Int64From(pouet)
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf(format, "update", err)
 	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(format, "tx commit", err)
 	}
@@ -556,38 +617,55 @@ func UpdateClassification(ctx context.Context, db *sql.DB, id int64, platform, t
 	if err := nils.Check(ctx, db); err != nil {
 		return fmt.Errorf("%s check: %w", msg, err)
 	}
-	p, t := tags.TagByURI(platform), tags.TagByURI(tag)
-	if p == -1 {
-		return fmt.Errorf(format, platform, ErrPlatform)
-	}
-	if !tags.IsPlatform(platform) {
-		return fmt.Errorf(format, platform, ErrPlatform)
-	}
-	if t == -1 {
-		return fmt.Errorf(format, tag, tags.ErrTag)
-	}
-	if !tags.IsTag(tag) {
-		return fmt.Errorf(format, tag, tags.ErrTag)
+	if err := validClassification(platform, tag); err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
 	}
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf(format, "begin tx", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(format, "find file", err)
 	}
-	f.Platform = null.StringFrom(p.String())
-	f.Section = null.StringFrom(t.String())
+	old := *f
+	setClassification(f, platform, tag)
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf(format, "update", err)
 	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(fmttx, msg, err)
 	}
 	return nil
 }
 
+// validClassification returns an error if either the platform or the tag are not valid values.
+func validClassification(platform, tag string) error {
+	const format = "%s: %w"
+	p, t := tags.TagByURI(platform), tags.TagByURI(tag)
+	if p == -1 || !tags.IsPlatform(platform) {
+		return fmt.Errorf(format, platform, ErrPlatform)
+	}
+	if t == -1 || !tags.IsTag(tag) {
+		return fmt.Errorf(format, tag, tags.ErrTag)
+	}
+	return nil
+}
+
+// setClassification sets the platform and section columns of the file record,
+// both the platform and tag must be valid values.
+func setClassification(f *models.File, platform, tag string) {
+	f.Platform = null.StringFrom(tags.TagByURI(platform).String())
+	f.Section = null.StringFrom(tags.TagByURI(tag).String())
+}
+
 // UpdateDateIssued updates the date issued year, month and day columns with the values provided.
 // Columns updated are DateIssuedYear, DateIssuedMonth, and DateIssuedDay.
 func UpdateDateIssued(ctx context.Context, db *sql.DB, id int64, y, m, d string) error {
@@ -600,23 +678,34 @@ func UpdateDateIssued(ctx context.Context, db *sql.DB, id int64, y, m, d string)
 	if err != nil {
 		return fmt.Errorf(format, "begin tx", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(format, "find file", err)
 	}
-	year, month, day := ValidDateIssue(y, m, d)
-	f.DateIssuedYear = year
-	f.DateIssuedMonth = month
-	f.DateIssuedDay = day
+	old := *f
+	setDateIssued(f, y, m, d)
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf("%s %q %q %q: %w", msg, y, m, d, err)
 	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(fmttx, msg, err)
 	}
 	return nil
 }
 
+// setDateIssued sets the date issued year, month and day columns of the file record,
+// any invalid values are set to null.
+func setDateIssued(f *models.File, y, m, d string) {
+	f.DateIssuedYear, f.DateIssuedMonth, f.DateIssuedDay = ValidDateIssue(y, m, d)
+}
+
 // UpdateOffline updates the record to be offline and inaccessible to the public.
 func UpdateOffline(ctx context.Context, db *sql.DB, id int64) error {
 	const msg = "update offline"
@@ -628,16 +717,22 @@ func UpdateOffline(ctx context.Context, db *sql.DB, id int64) error {
 	if err != nil {
 		return fmt.Errorf(format, "offline", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(format, "find file", err)
 	}
-	now := time.Now()
-	f.Deletedat = null.TimeFromPtr(&now)
-	f.Deletedby = null.StringFrom(strings.ToLower(uidPlaceholder))
+	old := *f
+	setOffline(f)
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf(format, "update", err)
 	}
+	if err = RecordChange(ctx, tx, id, SoftDeleted); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(fmttx, msg, err)
 	}
@@ -655,21 +750,41 @@ func UpdateOnline(ctx context.Context, db *sql.DB, id int64) error {
 	if err != nil {
 		return fmt.Errorf(format, "online", err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(format, "find file", err)
 	}
-	f.Deletedat = null.TimeFromPtr(nil)
-	f.Deletedby = null.String{String: "", Valid: false}
+	old := *f
+	setOnline(f)
 	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf(format, "update", err)
 	}
+	if err = RecordChange(ctx, tx, id, Restored); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	if err = tx.Commit(); err != nil {
 		return fmt.Errorf(fmttx, msg, err)
 	}
 	return nil
 }
 
+// setOffline sets the file record to be offline and inaccessible to the public.
+func setOffline(f *models.File) {
+	now := time.Now()
+	f.Deletedat = null.TimeFromPtr(&now)
+	f.Deletedby = null.StringFrom(strings.ToLower(uidPlaceholder))
+}
+
+// setOnline sets the file record to be online and public.
+func setOnline(f *models.File) {
+	f.Deletedat = null.TimeFromPtr(nil)
+	f.Deletedby = null.String{String: "", Valid: false}
+}
+
 // UpdateReleasers updates the releasers values with val.
 // Two releases can be separated by a + (plus) character.
 // The columns updated are GroupBrandFor and GroupBrandBy.
@@ -678,23 +793,54 @@ func UpdateReleasers(ctx context.Context, db *sql.DB, id int64, val string) erro
 	if err := nils.Check(ctx, db); err != nil {
 		return fmt.Errorf("%s check: %w", msg, err)
 	}
-	const maxReleasers = 2
-	val = strings.TrimSpace(val)
-	s := strings.Split(val, "+")
-	if len(s) > maxReleasers {
-		return fmt.Errorf("%s: %w", s, ErrRels)
-	}
-	for i, v := range s {
-		s[i] = releaser.Cell(v)
+	s, err := splitReleasers(val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", msg, err)
 	}
 	tx, err := db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("%s: %w", msg, err)
 	}
+	defer func() { _ = tx.Rollback() }()
 	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf("%s find file: %w", msg, err)
 	}
+	old := *f
+	setReleasers(f, s)
+	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
+		return fmt.Errorf("%s %q: %w", msg, val, err)
+	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
+	return nil
+}
+
+const maxReleasers = 2
+
+// splitReleasers returns the formatted releasers of val,
+// where two releases can be separated by a + (plus) character.
+func splitReleasers(val string) ([]string, error) {
+	val = strings.TrimSpace(val)
+	s := strings.Split(val, "+")
+	if len(s) > maxReleasers {
+		return nil, fmt.Errorf("%s: %w", s, ErrRels)
+	}
+	for i, v := range s {
+		s[i] = releaser.Cell(v)
+	}
+	return s, nil
+}
+
+// setReleasers sets the releaser columns of the file record using the split releasers.
+func setReleasers(f *models.File, s []string) {
 	switch len(s) {
 	case maxReleasers:
 		f.GroupBrandFor = null.StringFrom(s[0])
@@ -706,13 +852,6 @@ func UpdateReleasers(ctx context.Context, db *sql.DB, id int64, val string) erro
 		f.GroupBrandFor = null.StringFrom("")
 		f.GroupBrandBy = null.StringFrom("")
 	}
-	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
-		return fmt.Errorf("%s %q: %w", msg, val, err)
-	}
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf(fmttx, msg, err)
-	}
-	return nil
 }
 
 // UpdateYMD updates the date issued year, month and day columns with the values provided.
@@ -739,33 +878,55 @@ func UpdateYMD(ctx context.Context, exec boil.ContextExecutor, id int64, y, m, d
 	if err != nil {
 		return fmt.Errorf(format, "one file", id, err)
 	}
+	old := *f
 	f.DateIssuedYear = y
 	f.DateIssuedMonth = m
 	f.DateIssuedDay = d
 	if _, err = f.Update(ctx, exec, boil.Infer()); err != nil {
 		return fmt.Errorf(format, "update", id, err)
 	}
+	if err = RecordChange(ctx, exec, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, exec, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	return nil
 }
 
 // UpdateMagic updates the file magictype (magic number) column with the magic value provided.
-func UpdateMagic(ctx context.Context, exec boil.ContextExecutor, id int64, magic string) error {
+func UpdateMagic(ctx context.Context, db *sql.DB, id int64, magic string) error {
 	const msg = "update magic"
 	const format = msg + " %s id %d: %w"
-	if err := nils.Check(ctx, exec); err != nil {
+	if err := nils.Check(ctx, db); err != nil {
 		return fmt.Errorf("%s: %w", msg, err)
 	}
 	if id <= 0 {
 		return fmt.Errorf(format, "", id, ErrKey)
 	}
-	f, err := OneFile(ctx, exec, id)
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf(format, "begin tx", id, err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	f, err := OneFile(ctx, tx, id)
 	if err != nil {
 		return fmt.Errorf(format, "find file", id, err)
 	}
+	old := *f
 	f.FileMagicType = null.StringFrom(magic)
-	if _, err = f.Update(ctx, exec, boil.Infer()); err != nil {
+	if _, err = f.Update(ctx, tx, boil.Infer()); err != nil {
 		return fmt.Errorf(format, "update", id, err)
 	}
+	if err = RecordChange(ctx, tx, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, tx, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf(fmttx, msg, err)
+	}
 	return nil
 }
 
@@ -796,6 +957,7 @@ func (fu FileUpload) Update(ctx context.Context, exec boil.ContextExecutor, id i
 	if err != nil {
 		return fmt.Errorf(fmtID, "one file", err, id)
 	}
+	old := *f
 	if err = updateStringCases(f, filename, fu.Filename); err != nil {
 		return fmt.Errorf(format, "filename", err)
 	}
@@ -813,5 +975,11 @@ func (fu FileUpload) Update(ctx context.Context, exec boil.ContextExecutor, id i
 	if _, err = f.Update(ctx, exec, boil.Infer()); err != nil {
 		return fmt.Errorf(fmtID, "infer update record", err, id)
 	}
+	if err = RecordChange(ctx, exec, id, Updated); err != nil {
+		return fmt.Errorf("%s record change: %w", msg, err)
+	}
+	if err = RecordAudit(ctx, exec, &old, f); err != nil {
+		return fmt.Errorf("%s record audit: %w", msg, err)
+	}
 	return nil
 }
@@ -82,6 +82,59 @@ func ValidDateIssue(y, m, d string) (null.Int16, null.Int16, null.Int16) {
 	return year, month, day
 }
 
+// ValidDate returns three boolean values that indicate if the year, month, and day are valid.
+// If any of the bool values are false, the date syntax is invalid and should not be used.
+//
+// The year must be between 1980 and the current year.
+// If the year is not in use, the month and day must not be in use.
+// And if the month is not in use, the day must not in use.
+//
+// A not in use value is either "0" or an empty string.
+func ValidDate(y, m, d string) (bool, bool, bool) { //nolint:cyclop
+	yok, mok, dok := true, true, true
+	current := time.Now().Year()
+
+	year, err := strconv.Atoi(y)
+	if err != nil {
+		yok = false
+	}
+	useYear := year != 0 && y != ""
+	validYear := year >= EpochYear && year <= current
+	if useYear && !validYear {
+		yok = false
+	}
+
+	month, err := strconv.Atoi(m)
+	if err != nil {
+		mok = false
+	}
+	useMonth := month != 0 && m != ""
+	const jan, dec = 1, 12
+	validMonth := month >= jan && month <= dec
+	if useMonth && !validMonth {
+		mok = false
+	}
+
+	day, err := strconv.Atoi(d)
+	if err != nil {
+		dok = false
+	}
+	useDay := day != 0 && d != ""
+	const first, last = 1, 31
+	validDay := day >= first && day <= last
+	if useDay && !validDay {
+		dok = false
+	}
+
+	if !useYear && (validMonth || validDay) {
+		yok = false
+	}
+	if !useMonth && validDay {
+		mok = false
+	}
+	return yok, mok, dok
+}
+
 // ValidD returns a valid day or a null value.
 func ValidD(d int16) null.Int16 {
 	const first, last = 1, 31
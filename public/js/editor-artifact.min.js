@@ -1,2 +1,2 @@
 /* editor-artifact.min.js © Defacto2 2026 */
-(()=>{function z(e,i,r,l){if(e==null)throw new Error("The year input element is null.");if(i==null)throw new Error("The month input element is null.");if(r==null)throw new Error("The day input element is null.");e.classList.remove("is-invalid","is-valid"),i.classList.remove("is-invalid","is-valid"),r.classList.remove("is-invalid","is-valid");let s=parseInt(e.value,10);isNaN(s)?e.value="0":e.value=s;let a=parseInt(i.value,10);isNaN(a)?i.value="0":i.value=a;let o=parseInt(r.value,10);isNaN(o)?r.value="0":r.value=o;let u=0,v=new Date().getFullYear(),E=s>=1980&&s<=v;s>u&&!E&&e.classList.add("is-invalid");let C=a>=1&&a<=12;a>u&&!C&&i.classList.add("is-invalid");let L=o>=1&&o<=31;if(o>u&&!L&&r.classList.add("is-invalid"),isNaN(s)&&(C||L)&&e.classList.add("is-invalid"),(a==u||isNaN(a))&&L&&i.classList.add("is-invalid"),l==!1||E==!1||C==!1||L==!1)return;let _=document.getElementById("artifact-editor-date-update");_!==null&&_.click()}function $e(e){return Ae(e,!0)}function Ce(e){return Ae(e,!1)}function Ae(e,i){if(e==null)throw new Error("The repository URL element is null.");e.classList.remove("is-valid","is-invalid");let r=e.value.trim();if(r.length===0)return;if(i==!0&&r.startsWith("refs/")){e.classList.add("is-invalid");return}if(r.includes("://")){e.classList.add("is-invalid");return}let s=/[^A-Za-z0-9-._/]/g;r=r.replace(s,""),r=r.replaceAll("//","/");let a=/^\//;r=r.replace(a,""),e.value=r;let o=e.getAttribute("maxlength");if(o===null)throw new Error(`The maxlength attribute is required for ${e.id}.`);if(r.length>o){e.classList.add("is-invalid");return}}function R(e){if(e==null)throw new Error("The element of the releaser validator is null.");e.classList.remove("is-valid","is-invalid");let i=e.selectionStart,r=e.selectionEnd,l=e.value.toUpperCase();l=l.replace("+",", "),l=l.replace(/[^ A-ZÀ-ÖØ-Þ0-9\-,&]/g,""),l=l.replace(/[ ]{2,}/g," "),e.value=l,e.setSelectionRange(i,r);let s=e.getAttribute("minlength"),a=e.getAttribute("maxlength"),o=e.getAttribute("required");if(s===null)throw new Error(`The minlength attribute is required for ${e.id}.`);if(a===null)throw new Error(`The maxlength attribute is required for ${e.id}.`);let u=document.getElementById("artifact-editor-releasers-error");if(u===null)throw new Error("The releasers error element is null.");let v=l.length<s||l.length>a;if(o!=null&&v){e.classList.add("is-invalid"),e.id==="-1"&&u.classList.add("d-block");return}let E=l.length>0&&(l.length<s||l.length>a);if(o==null&&E){e.classList.add("is-invalid");return}e.classList.remove("is-invalid"),u.classList.remove("d-block")}function De(e){if(e==null)throw new Error("The element of the releaser validator is null.");e.classList.remove("is-valid","is-invalid");let i=e.value.trim();i.length>0&&i.length!=11&&e.classList.add("is-invalid")}function ee(e,i){if(e==null)throw new Error("The element of the number validator is null.");e.classList.remove("is-valid","is-invalid");let r=parseInt(e.value,10);isNaN(r)&&e.classList.add("is-invalid"),(r>i||r<0)&&e.classList.add("is-invalid")}function Ne(e){e.preventDefault();let i=(e.clipboardData||window.Clipboard).getData("text"),r=this,l=r.selectionStart,s=r.selectionEnd;r.value=r.value.slice(0,l)+i+r.value.slice(s),r.setSelectionRange(l+i.length,l+i.length);let a=te(r.value);r.value!=a&&(console.log('Formatted input text "%s" is formatted to "%s".',r.value,a),r.value=a)}function te(e){let i=["a","addon","aka","an","and","as","at","beta","betas","but","by","compatibility","crack","cracks","demo","demos","doc","docs","documentation","fix","fixes","for","final","from","if","in","installer","installers","is","hint","hints","map","maps","mod","mods","of","on","or","patch","patches","part","prerelease","prereleases","preview","previews","proper","release","releases","repack","repacks","rip","rips","so","solve","solves","the","trainer","trainers","to","update","updates","unprotect","unprotects","vs","with"],r=[".com",".exe","2d","3d","4d","abc","ad&d","api","bbs","bios","bsa","cd","cga","dos","dox","dps","dvd","ega","ehq","f1","fbi","ftp","hd","hq","ibm","id","iso","la","lego","ls","masm","mbl","ms","mtv","nascar","nba","ncaa","nfl","nsa","nfo","nhl","nt","oem","os","pc","pcb","pga","ppe","pfs","pkarc","pkzip","psx","rac","rom","sdk","sfx","spa","tv","ufo","usa","usb","ushq","uss","vga","vr","whq","ww1","ww2","ww3","wwf","xp","ys"];e=e.trim(),e=e.replace(/_/g," "),e.match(/\./g)&&e.match(/\./g).length>4&&(e=e.replace(/\./g," ")),e=e.replace(/['"`]/g,""),e=e.replace(/ \([0-9a-z]\)/g,""),e=e.replace(/([a-zA-Z0-9]): /g,"$1 : "),e=e.replace(/([xX][-| ][Mm]en)/g,"X-Men"),e=e.replace(/\(/g,"( "),e=e.replace(/\)/g," )");let l=e.split(" ").length;return e=e.split(" ").map((s,a)=>{var o=s;if(Ve(o)===!0)return o.toLowerCase();if(r.includes(o.toLowerCase()))return o.toUpperCase();let u=Ye(o);if(u!=="")return u;let v=xe(o);a>0&&Number.isInteger(v)&&(o=`${v}`);let E=_e(o,a,l);return E!==o&&(o=E),s!==o?o:a===0||!i.includes(o.toLowerCase())?o.charAt(0).toUpperCase()+o.slice(1).toLowerCase():s.toLowerCase()}).join(" ").trim(),e=e.replace(/(Lotus 123)/g,"Lotus 1-2-3"),e=e.replace(/(Falcon at )/g,"Falcon AT "),e=e.replace(/(the Games)/g,"The Games"),e=e.replace(/^(Unprotect for|Unprotecting|Unprotect)\s(.+)/,"$2 unprotect"),e=e.replace(/( : a)/g," : A"),e=e.replace(/( - a)/g," - A"),e=e.replace(/( : t)/g," : T"),e=e.replace(/( - t)/g," - T"),e=e.replace(/(f-)/g,"F-"),e=e.replace(/(3-d)/g,"3D"),e=e.replace(/(Pfs-)/g,"PFS-"),e=e.replace(/(Mean-18)/g,"Mean 18"),e=ze(e),e=e.replace(/\b([vV]?)(\d+)\.00\b/g,"$1$2"),e=e.replace(/[\s]*(WORKING\s+READ\s+NFO|fix\s+only|repack|working|fixed)\s*$/i," fix"),e=e.replace(/[\s]*(inc|incl|including)\s(keygen|keymaker|keyfilemaker|serial)\s*$/i,""),e=e.replace(/\bincluding\s(?:patch|patched)\s+and\s+key(?:gen|maker)\b/gi,""),e=e.replace(/\b(keymaker\s+only|read\s+nfo|mac\s+osx)\b/gi,""),e=e.replace(/\b(?:macosx|x86|x64|winnt2kxp|win9xme|multilang|winall|readnfo|retail|proper|standalone)\b/gi,""),e=e.replace(/\b(?:rc[1-9])\b/gi," beta"),e=e.replace(/\( /g,"("),e=e.replace(/ \)/g,")"),e=e.replace(/\[([^)]+)\]/g,function(s){return s.toUpperCase()}),e=e.replace(/(\s\s)/g," "),e=e.trim(),e}function ze(e){return String(e).replace(/(?<sep>[\s\-_–—]|^)[V](?=\d)/g,()=>" v").replace(/([\s\-_–—]|^)(v?1(?:\.0(?:\.\d+)?)?)(?=([\s\-_–—]|$))/gi,(i,r)=>"").replace(/(?<=^|[\s\-_–—])v?(\d+)\.(\d+)\.(\d+)(?=$|[\s\-_–—.,])/g,i=>i.replace(/v?/i,r=>r.toLowerCase()).replace(/(\d+\.\d+)\.\d+/,"$1")).replace(/(?<=^|[\s\-_–—])v?(\d+)\.0(?:\.0)?(?=$|[\s\-_–—.,])/g,i=>i.replace(/v?/i,r=>r?"v":"").replace(/(\d+)\.0(?:\.0)?/,"$1")).replace(/(?<=^|[\s\-_–—])V(?=\d)/g,"v").replace(/[\s\-_–—]{2,}/g," ").replace(/\s{2,}/g," ").trim()}function Ve(e){return/^[vV]\d+(\.\d+)?[a-z]?$/i.test(e)}function _e(e,i,r){return i!=r-1?e:["cheat","cheater","cracktro","loader","installer","trainer","version"].includes(e.toLowerCase())?"":e}function xe(e){let i={I:1,V:5,X:10},r=0,l=0;for(let s=e.length-1;s>=0;s--){let a=i[e[s].toUpperCase()];a<l?r-=a:r+=a,l=a}return r==0?e:r}function Ye(e){switch(e.toLowerCase()){case"dr.":return"Dr";case"jr.":return"Jr";case"ms.":return"Ms";case"u.s.a.":return"USA";case"abcs":return"ABCs";case"cdrip":case"cd-rip":return"CD RIP";case"pre-release":return"prerelease";case"&":return"and";case"ad+d":return"AD&D";case"][":case"||":return"2";case"]|[":case"]I[":return"3";case"]||[":case"]II[":return"4";case" I:":return" 1 :";case" II:":return" 2 :";case" III:":return" 3 :";case"at&t":return"AT&T";case"dbase":return"dBase";case"doubledos":return"DoubleDOS";case"fastback":return"FastBack";case"loadit":return"LoadIt";case"memoryshift":return"Memory Shift";case"multilink":return"MultiLink";case"paperboy":return"PaperBoy";case"pc-draw":return"PC-Draw";case"pcjr":return"PCjr";case"prokey":return"ProKey";case"rbase":case"r:base":return"R:Base";case"sidekick":return"SideKick";case"visicalc":return"VisiCalc";case"war-craft":return"Warcraft";case"wordstar":return"WordStar";default:return""}}async function V(e){let i=c(e);i.focus(),await navigator.clipboard.writeText(`${i.textContent}`).then(function(){console.log(`Copied ${qe(i.textContent.length)} to the clipboard`)},function(r){console.error(`could not save any text to the clipboard: ${r}`)})}async function $(e){let i=c(e);i.focus(),await navigator.clipboard.writeText(`${i.value}`).then(function(){console.log(`Copied ${qe(i.value.length)} to the clipboard`)},function(r){console.error(`could not save any text to the clipboard: ${r}`)})}function c(e){let i=document.getElementById(e);if(i==null){console.error(`The ${e} for getElmById() element is null.`);return}return i}function qe(e=0){let s=Math.pow(1e3,2),a=Math.pow(1e3,3);return e>a?`${(Math.round(e*100/a)/100).toFixed(2)} GB`:e>s?`${(Math.round(e*100/s)/100).toFixed(1)} MB`:e>1e3?`${(Math.round(e*100/1e3)/100).toFixed()} kB`:`${Math.round(e).toFixed()} bytes`}(()=>{"use strict";function e(t){return function(){t.forEach(n=>{n.disabled=!0,n.classList.remove("btn-outline-primary"),n.classList.add("btn-light")})}}function i(t){return function(){t.forEach(n=>{n.disabled=!1,n.classList.remove("btn-light"),n.classList.add("btn-outline-primary")})}}let r=document.getElementById("artifact-editor-modal"),l=document.getElementsByName("artifact-editor-dataeditor");r.addEventListener("shown.bs.modal",()=>{e(l)()}),r.addEventListener("hidden.bs.modal",()=>{i(l)()});let s=document.getElementById("asset-editor-modal"),a=document.getElementsByName("artifact-editor-fileeditor");s.addEventListener("shown.bs.modal",()=>{e(a)()}),s.addEventListener("hidden.bs.modal",()=>{i(a)()});let o=document.getElementById("emulate-editor-modal"),u=document.getElementsByName("artifact-editor-emueditor");o.addEventListener("shown.bs.modal",()=>{e(u)()}),o.addEventListener("hidden.bs.modal",()=>{i(u)()});let v=document.getElementById("emulate-run-program");if(v!==null){let t=document.getElementById("emulate-guess-program");if(t===null)throw new Error("The guess program input is missing.");v.addEventListener("input",()=>{v.value=v.value.toUpperCase().replace(/\s{2,}/g," ");let n=v.value;if(n!==""&&n!==" "){t.disabled=!0;return}t.disabled=!1})}let E=document.getElementById("artifact-embed-title");E?.addEventListener("click",()=>V("artifact-embed-title-value"));let C=document.getElementById("artifact-embed-author");C?.addEventListener("click",()=>V("artifact-embed-author-value"));let L=document.getElementById("artifact-embed-group");if(L?.addEventListener("click",()=>V("artifact-embed-group-value")),c("artifact-dataeditor-key-value")===null)throw new Error("The key value is missing.");let x=c("artifact-dataeditor-key-label");if(x===null)throw new Error("The key label is missing.");if(x.addEventListener("click",()=>$("artifact-dataeditor-key-value")),c("artifact-fileeditor-key-value")===null)throw new Error("The key value is missing.");let Re=c("artifact-fileeditor-key-label");if(x===null)throw new Error("The key label is missing.");if(Re.addEventListener("click",()=>$("artifact-fileeditor-key-value")),c("artifact-dataeditor-unique-id-value")===null)throw new Error("The unique id value is missing.");let re=c("artifact-dataeditor-unique-id-label");if(re===null)throw new Error("The unique id label is missing.");if(re.addEventListener("click",()=>$("artifact-dataeditor-unique-id-value")),c("artifact-fileeditor-unique-id-value")===null)throw new Error("The unique id value is missing.");let ie=c("artifact-fileeditor-unique-id-label");if(ie===null)throw new Error("The unique id label is missing.");if(ie.addEventListener("click",()=>$("artifact-fileeditor-unique-id-value")),c("artifact-editor-location-value")===null)throw new Error("The location value is missing.");let ne=c("artifact-editor-location-label");if(ne===null)throw new Error("The location label is missing.");ne.addEventListener("click",()=>$("artifact-editor-location-value"));let ae=c("artifact-editor-templocation");if(ae!=null){let t=c("artifact-editor-templocation-label");t!==null&&t.addEventListener("click",()=>$("artifact-editor-templocation"))}if(document.getElementById("artifact-editor-os-label")===null)throw new Error("The operating system label is missing.");let b=document.getElementById("artifact-editor-operating-system");if(b===null)throw new Error("The operating system input is missing.");b.addEventListener("input",Y);let y=document.getElementById("artifact-editor-category");if(y===null)throw new Error("The category input is missing.");y.addEventListener("input",G),Y(),G();function Y(){b.selectedIndex==0&&(b.classList.remove("is-valid"),b.classList.add("is-invalid"))}function G(){y.selectedIndex==0&&(y.classList.remove("is-valid"),y.classList.add("is-invalid"))}let j=document.getElementsByName("prereset-classifications");if(j.length===0)throw new Error("The preset classifications are missing.");for(let t=0;t<j.length;t++)Me(t);function Me(t){let n=j[t],p=n.getAttribute("data-preset-os");if(p===null)throw new Error("data-preset-os attribute is required for ${elm.id}.");let m=n.getAttribute("data-preset-tag");if(m===null)throw new Error("data-preset-tag attribute is required for ${elm.id}.");n.addEventListener("click",I=>{I.preventDefault(),b.value=p,b.classList.remove("is-invalid"),y.value=m,y.classList.remove("is-invalid"),Y(),G()})}let w=document.getElementById("artifact-editor-filename");if(w===null)throw new Error("The filename input is missing.");w.addEventListener("input",t=>{t.target.classList.remove("is-valid"),t.target.classList.remove("is-invalid"),t.target.value.trim().length===0&&t.target.classList.add("is-invalid")});let se=document.getElementById("artifact-editor-filename-reset");if(se===null)throw new Error("The filename reset is missing.");let O=document.getElementsByName("artifact-editor-filename-undo");if(O.length===0)throw new Error("The filename resetter is missing.");se.addEventListener("click",()=>{if(w.classList.remove("is-valid"),O.length===0)throw new Error("The filename resetter is missing.");w.value=O[0].value,w.classList.add("is-valid"),w.classList.remove("is-invalid"),w.value.trim().length===0&&w.classList.add("is-invalid")});let N=document.getElementById("artifact-editor-releaser-1");if(N===null)throw new Error("The releaser 1 input is missing.");N.addEventListener("input",t=>R(t.target));let q=document.getElementById("artifact-editor-releaser-2");if(q===null)throw new Error("The releaser 2 input is missing.");q.addEventListener("input",t=>R(t.target));let oe=document.getElementById("artifact-editor-releaser-undo");if(oe===null)throw new Error("The releasers reset is missing.");oe.addEventListener("click",Se);function Se(){let t=N.getAttribute("data-reset-rel1");if(t===null)throw new Error("data-reset-rel1 attribute is required for artifact-editor-releaser-1.");N.value=t,R(N);let n=q.getAttribute("data-reset-rel2");if(n===null)throw new Error("data-reset-rel2 attribute is required for artifact-editor-releaser-2.");q.value=n,R(q)}document.addEventListener("change",t=>{let n=t.target;if(n.matches("#artifact-editor-releaser-1, #artifact-editor-releaser-2")){let p=n.getAttribute("list"),m=document.querySelectorAll(`#${p} option`);if(Array.from(m).some(D=>D.value===n.value)){let D=document.getElementById("artifact-editor-releaser-update");D&&htmx.trigger(D,"submit-releasers")}}});let h=document.getElementById("artifact-editor-title");if(h===null)throw new Error("The title input is missing.");h.addEventListener("paste",Ne),h.addEventListener("input",t=>{t.target.classList.remove("is-valid")});let le=document.getElementById("artifact-editor-title-undo");if(le===null)throw new Error("The title reset is missing.");let W=document.getElementsByName("artifact-editor-titleundo");if(W.length===0)throw new Error("The title resetter is missing.");le.addEventListener("click",()=>{if(h.classList.remove("is-valid"),W.length===0)throw new Error("The title resetter is missing.");h.value=W[0].value,h.classList.add("is-valid")});let de=document.getElementById("artifact-editor-titleize");if(de.length===0)throw new Error("The titleize button is missing.");de.addEventListener("click",()=>{h.value=te(h.value);let t=new Event("keyup");h.dispatchEvent(t)});let ce=document.getElementById("artifact-editor-title-delete");if(ce.length===0)throw new Error("The title delete button is missing.");ce.addEventListener("click",()=>{h.value="";let t=new Event("keyup");h.dispatchEvent(t)});let K=document.getElementById("artifact-editor-credit-text");if(K===null)throw new Error("The creator text input is missing.");K.addEventListener("input",t=>{t.target.classList.remove("is-valid")});let X=document.getElementById("artifact-editor-credit-ill");if(X===null)throw new Error("The creator illustrator input is missing.");X.addEventListener("input",t=>{t.target.classList.remove("is-valid")});let Z=document.getElementById("artifact-editor-credit-prog");if(Z===null)throw new Error("The creator programmer input is missing.");Z.addEventListener("input",t=>{t.target.classList.remove("is-valid")});let H=document.getElementById("artifact-editor-credit-audio");if(H===null)throw new Error("The creator audio input is missing.");H.addEventListener("input",t=>{t.target.classList.remove("is-valid")});let J=document.getElementById("artifact-editor-credits-undo");if(J===null)throw new Error("The creator resetter is missing.");let ue=document.getElementById("artifact-editor-credit-undo");if(ue===null)throw new Error("The creator reset is missing.");ue.addEventListener("click",()=>{if(J.length===0)throw new Error("The creator resetter is missing.");let t=J.value.split(";");if(t.length!=4)throw new Error("The creator resetter values are invalid.");let n=t[0],p=t[1],m=t[2],I=t[3];K.value=n,X.value=p,Z.value=m,H.value=I});let me=document.getElementById("artifact-editor-virustotal");if(me===null)throw new Error("The virustotal input is missing.");me.addEventListener("input",t=>{t.target.classList.remove("is-valid","is-invalid");let n=t.target.value.trim();n.length!=0&&(n.startsWith("https://www.virustotal.com/")||t.target.classList.add("is-invalid"))});let d=document.getElementById("artifact-editor-year");if(d===null)throw new Error("The year input is missing.");d.addEventListener("input",()=>{let t=parseInt(d.value,10);t>=79&&t<=99&&(d.value=1900+t),z(d,f,g,M)});let f=document.getElementById("artifact-editor-month");if(f===null)throw new Error("The month input is missing.");f.addEventListener("input",()=>{z(d,f,g,M)});let g=document.getElementById("artifact-editor-day");if(g===null)throw new Error("The day input is missing.");g.addEventListener("input",()=>{z(d,f,g,M)});let M=!1;d.value==0&&f.value==0&&g.value==0&&(M=!0);let ve=document.getElementById("artifact-editor-date-reset");if(ve===null)throw new Error("The date reset is missing.");let fe=document.getElementById("artifact-editor-date-resetter");if(fe===null)throw new Error("The date resetter is missing.");ve.addEventListener("click",()=>{d.classList.remove("is-invalid","is-valid"),f.classList.remove("is-invalid","is-valid"),g.classList.remove("is-invalid","is-valid");let n=fe.value.split("-");if(n.length!=3)throw new Error("The date resetter values are invalid.");d.value=n[0],f.value=n[1],g.value=n[2]});let S=document.getElementById("artifact-editor-comment");if(S===null)throw new Error("The comment input is missing.");S.addEventListener("input",t=>{if(t.target.classList.remove("is-valid"),(d.value==0&&f.value==0&&g.value==0)==!1)return;let m=/(0[1-9]|1[0-2])\/(0[1-9]|[12][0-9]|3[01])\/(\d{2})/.exec(t.target.value);if(m||(m=/(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])-(\d{2})/.exec(t.target.value)),m){let I=m[1],D=m[2],Pe=m[3],P=parseInt(Pe,10);d.value=2e3+P,P>=79&&P<=99&&(d.value=1900+P),f.value=I,g.value=D;let Ie=document.getElementById("artifact-editor-date-update");Ie!==null&&Ie.click()}});let ge=document.getElementById("artifact-editor-comment-undo");if(ge===null)throw new Error("The comment reset is missing.");let pe=document.getElementById("artifact-editor-comment-resetter");if(pe===null)throw new Error("The comment resetter is missing.");ge.addEventListener("click",()=>{S.classList.remove("is-valid"),S.value=pe.value});let he=document.getElementById("artifact-editor-date-lastmod");if(he!==null){let t=document.getElementById("artifact-editor-date-lastmodder");if(t===null)throw new Error("The date last modder input is missing.");he.addEventListener("click",()=>{d.classList.remove("is-invalid","is-valid"),f.classList.remove("is-invalid","is-valid"),g.classList.remove("is-invalid","is-valid");let p=t.value.split("-");if(p.length!=3)throw new Error("The date last modder values are invalid.");d.value=p[0],f.value=p[1],g.value=p[2]})}let Ee=document.getElementById("artifact-editor-links-reset");if(Ee===null)throw new Error("The links reset is missing.");let B=document.getElementById("artifact-editor-youtube"),we=document.getElementById("artifact-editor-youtube-reset");if(B===null||we===null)throw new Error("A YouTube input is missing.");let F=document.getElementById("artifact-editor-demozoo"),Le=document.getElementById("artifact-editor-demozoo-reset");if(F===null||Le===null)throw new Error("A Demozoo input is missing.");let U=document.getElementById("artifact-editor-pouet"),be=document.getElementById("artifact-editor-pouet-reset");if(U===null||be===null)throw new Error("A Pouet input is missing.");let T=document.getElementById("artifact-editor-16colors"),ye=document.getElementById("artifact-editor-16colors-reset");if(T===null||ye===null)throw new Error("A 16colors input is missing.");let k=document.getElementById("artifact-editor-github"),Be=document.getElementById("artifact-editor-github-reset");if(k===null||Be===null)throw new Error("A GitHub input is missing.");let Q=document.getElementById("artifact-editor-relations"),Te=document.getElementById("artifact-editor-relations-reset");if(Q===null||Te===null)throw new Error("A relations input is missing.");let A=document.getElementById("artifact-editor-websites"),ke=document.getElementById("artifact-editor-websites-reset");if(A===null||ke===null)throw new Error("A websites input is missing.");A.addEventListener("paste",()=>{setTimeout(()=>{A.value=A.value.replace(/https?:\/\//,"")},0)}),Ee.addEventListener("click",()=>{B.classList.remove("is-invalid","is-valid"),F.classList.remove("is-invalid","is-valid"),U.classList.remove("is-invalid","is-valid"),T.classList.remove("is-invalid","is-valid"),k.classList.remove("is-invalid","is-valid"),Q.classList.remove("is-invalid","is-valid"),A.classList.remove("is-invalid","is-valid"),B.value=we.value,F.value=Le.value,U.value=be.value,T.value=ye.value,k.value=Be.value,Q.value=Te.value,A.value=ke.value});let Fe=45e4,Ue=2e5;B.addEventListener("paste",()=>{setTimeout(()=>{B.value=B.value.replace(/https?:\/\/www\.youtube\.com\/watch\?v=/,"")},0)}),B.addEventListener("input",t=>De(t.target)),F.addEventListener("input",t=>ee(t.target,Fe)),U.addEventListener("input",t=>ee(t.target,Ue)),T.addEventListener("paste",()=>{setTimeout(()=>{T.value=T.value.replace(/https?:\/\/16colo\.rs\//,"")},0)}),T.addEventListener("input",t=>Ce(t.target)),k.addEventListener("paste",()=>{setTimeout(()=>{k.value=k.value.replace(/https?:\/\/github\.com\//,"")},0)}),k.addEventListener("input",t=>$e(t.target))})();})();
+(()=>{function z(e,i,r,l){if(e==null)throw new Error("The year input element is null.");if(i==null)throw new Error("The month input element is null.");if(r==null)throw new Error("The day input element is null.");e.classList.remove("is-invalid","is-valid"),i.classList.remove("is-invalid","is-valid"),r.classList.remove("is-invalid","is-valid");let s=parseInt(e.value,10);isNaN(s)?e.value="0":e.value=s;let a=parseInt(i.value,10);isNaN(a)?i.value="0":i.value=a;let o=parseInt(r.value,10);isNaN(o)?r.value="0":r.value=o;let u=0,v=new Date().getFullYear(),E=s>=1980&&s<=v;s>u&&!E&&e.classList.add("is-invalid");let C=a>=1&&a<=12;a>u&&!C&&i.classList.add("is-invalid");let L=o>=1&&o<=31;if(o>u&&!L&&r.classList.add("is-invalid"),isNaN(s)&&(C||L)&&e.classList.add("is-invalid"),(a==u||isNaN(a))&&L&&i.classList.add("is-invalid"),l==!1||E==!1||C==!1||L==!1)return;let _=document.getElementById("artifact-editor-date-update");_!==null&&_.click()}function $e(e){return Ae(e,!0)}function Ce(e){return Ae(e,!1)}function Ae(e,i){if(e==null)throw new Error("The repository URL element is null.");e.classList.remove("is-valid","is-invalid");let r=e.value.trim();if(r.length===0)return;if(i==!0&&r.startsWith("refs/")){e.classList.add("is-invalid");return}if(r.includes("://")){e.classList.add("is-invalid");return}let s=/[^A-Za-z0-9-._/]/g;r=r.replace(s,""),r=r.replaceAll("//","/");let a=/^\//;r=r.replace(a,""),e.value=r;let o=e.getAttribute("maxlength");if(o===null)throw new Error(`The maxlength attribute is required for ${e.id}.`);if(r.length>o){e.classList.add("is-invalid");return}}function R(e){if(e==null)throw new Error("The element of the releaser validator is null.");e.classList.remove("is-valid","is-invalid");let i=e.selectionStart,r=e.selectionEnd,l=e.value.toUpperCase();l=l.replace("+",", "),l=l.replace(/[^ A-ZÀ-ÖØ-Þ0-9\-,&]/g,""),l=l.replace(/[ ]{2,}/g," "),e.value=l,e.setSelectionRange(i,r);let s=e.getAttribute("minlength"),a=e.getAttribute("maxlength"),o=e.getAttribute("required");if(s===null)throw new Error(`The minlength attribute is required for ${e.id}.`);if(a===null)throw new Error(`The maxlength attribute is required for ${e.id}.`);let u=document.getElementById("artifact-editor-releasers-error");if(u===null)throw new Error("The releasers error element is null.");let v=l.length<s||l.length>a;if(o!=null&&v){e.classList.add("is-invalid"),e.id==="-1"&&u.classList.add("d-block");return}let E=l.length>0&&(l.length<s||l.length>a);if(o==null&&E){e.classList.add("is-invalid");return}e.classList.remove("is-invalid"),u.classList.remove("d-block")}function De(e){if(e==null)throw new Error("The element of the releaser validator is null.");e.classList.remove("is-valid","is-invalid");let i=e.value.trim();i.length>0&&i.length!=11&&e.classList.add("is-invalid")}function ee(e,i){if(e==null)throw new Error("The element of the number validator is null.");e.classList.remove("is-valid","is-invalid");let r=parseInt(e.value,10);isNaN(r)&&e.classList.add("is-invalid"),(r>i||r<0)&&e.classList.add("is-invalid")}function Ne(e){e.preventDefault();let i=(e.clipboardData||window.Clipboard).getData("text"),r=this,l=r.selectionStart,s=r.selectionEnd;r.value=r.value.slice(0,l)+i+r.value.slice(s),r.setSelectionRange(l+i.length,l+i.length);let a=te(r.value);r.value!=a&&(console.log('Formatted input text "%s" is formatted to "%s".',r.value,a),r.value=a)}function te(e){let i=["a","addon","aka","an","and","as","at","beta","betas","but","by","compatibility","crack","cracks","demo","demos","doc","docs","documentation","fix","fixes","for","final","from","if","in","installer","installers","is","hint","hints","map","maps","mod","mods","of","on","or","patch","patches","part","prerelease","prereleases","preview","previews","proper","release","releases","repack","repacks","rip","rips","so","solve","solves","the","trainer","trainers","to","update","updates","unprotect","unprotects","vs","with"],r=[".com",".exe","2d","3d","4d","abc","ad&d","api","bbs","bios","bsa","cd","cga","dos","dox","dps","dvd","ega","ehq","f1","fbi","ftp","hd","hq","ibm","id","iso","la","lego","ls","masm","mbl","ms","mtv","nascar","nba","ncaa","nfl","nsa","nfo","nhl","nt","oem","os","pc","pcb","pga","ppe","pfs","pkarc","pkzip","psx","rac","rom","sdk","sfx","spa","tv","ufo","usa","usb","ushq","uss","vga","vr","whq","ww1","ww2","ww3","wwf","xp","ys"];e=e.trim(),e=e.replace(/_/g," "),e.match(/\./g)&&e.match(/\./g).length>4&&(e=e.replace(/\./g," ")),e=e.replace(/['"`]/g,""),e=e.replace(/ \([0-9a-z]\)/g,""),e=e.replace(/([a-zA-Z0-9]): /g,"$1 : "),e=e.replace(/([xX][-| ][Mm]en)/g,"X-Men"),e=e.replace(/\(/g,"( "),e=e.replace(/\)/g," )");let l=e.split(" ").length;return e=e.split(" ").map((s,a)=>{var o=s;if(Ve(o)===!0)return o.toLowerCase();if(r.includes(o.toLowerCase()))return o.toUpperCase();let u=Ye(o);if(u!=="")return u;let v=xe(o);a>0&&Number.isInteger(v)&&(o=`${v}`);let E=_e(o,a,l);return E!==o&&(o=E),s!==o?o:a===0||!i.includes(o.toLowerCase())?o.charAt(0).toUpperCase()+o.slice(1).toLowerCase():s.toLowerCase()}).join(" ").trim(),e=e.replace(/(Lotus 123)/g,"Lotus 1-2-3"),e=e.replace(/(Falcon at )/g,"Falcon AT "),e=e.replace(/(the Games)/g,"The Games"),e=e.replace(/^(Unprotect for|Unprotecting|Unprotect)\s(.+)/,"$2 unprotect"),e=e.replace(/( : a)/g," : A"),e=e.replace(/( - a)/g," - A"),e=e.replace(/( : t)/g," : T"),e=e.replace(/( - t)/g," - T"),e=e.replace(/(f-)/g,"F-"),e=e.replace(/(3-d)/g,"3D"),e=e.replace(/(Pfs-)/g,"PFS-"),e=e.replace(/(Mean-18)/g,"Mean 18"),e=ze(e),e=e.replace(/\b([vV]?)(\d+)\.00\b/g,"$1$2"),e=e.replace(/[\s]*(WORKING\s+READ\s+NFO|fix\s+only|repack|working|fixed)\s*$/i," fix"),e=e.replace(/[\s]*(inc|incl|including)\s(keygen|keymaker|keyfilemaker|serial)\s*$/i,""),e=e.replace(/\bincluding\s(?:patch|patched)\s+and\s+key(?:gen|maker)\b/gi,""),e=e.replace(/\b(keymaker\s+only|read\s+nfo|mac\s+osx)\b/gi,""),e=e.replace(/\b(?:macosx|x86|x64|winnt2kxp|win9xme|multilang|winall|readnfo|retail|proper|standalone)\b/gi,""),e=e.replace(/\b(?:rc[1-9])\b/gi," beta"),e=e.replace(/\( /g,"("),e=e.replace(/ \)/g,")"),e=e.replace(/\[([^)]+)\]/g,function(s){return s.toUpperCase()}),e=e.replace(/(\s\s)/g," "),e=e.trim(),e}function ze(e){return String(e).replace(/(?<sep>[\s\-_–—]|^)[V](?=\d)/g,()=>" v").replace(/([\s\-_–—]|^)(v?1(?:\.0(?:\.\d+)?)?)(?=([\s\-_–—]|$))/gi,(i,r)=>"").replace(/(?<=^|[\s\-_–—])v?(\d+)\.(\d+)\.(\d+)(?=$|[\s\-_–—.,])/g,i=>i.replace(/v?/i,r=>r.toLowerCase()).replace(/(\d+\.\d+)\.\d+/,"$1")).replace(/(?<=^|[\s\-_–—])v?(\d+)\.0(?:\.0)?(?=$|[\s\-_–—.,])/g,i=>i.replace(/v?/i,r=>r?"v":"").replace(/(\d+)\.0(?:\.0)?/,"$1")).replace(/(?<=^|[\s\-_–—])V(?=\d)/g,"v").replace(/[\s\-_–—]{2,}/g," ").replace(/\s{2,}/g," ").trim()}function Ve(e){return/^[vV]\d+(\.\d+)?[a-z]?$/i.test(e)}function _e(e,i,r){return i!=r-1?e:["cheat","cheater","cracktro","loader","installer","trainer","version"].includes(e.toLowerCase())?"":e}function xe(e){let i={I:1,V:5,X:10},r=0,l=0;for(let s=e.length-1;s>=0;s--){let a=i[e[s].toUpperCase()];a<l?r-=a:r+=a,l=a}return r==0?e:r}function Ye(e){switch(e.toLowerCase()){case"dr.":return"Dr";case"jr.":return"Jr";case"ms.":return"Ms";case"u.s.a.":return"USA";case"abcs":return"ABCs";case"cdrip":case"cd-rip":return"CD RIP";case"pre-release":return"prerelease";case"&":return"and";case"ad+d":return"AD&D";case"][":case"||":return"2";case"]|[":case"]I[":return"3";case"]||[":case"]II[":return"4";case" I:":return" 1 :";case" II:":return" 2 :";case" III:":return" 3 :";case"at&t":return"AT&T";case"dbase":return"dBase";case"doubledos":return"DoubleDOS";case"fastback":return"FastBack";case"loadit":return"LoadIt";case"memoryshift":return"Memory Shift";case"multilink":return"MultiLink";case"paperboy":return"PaperBoy";case"pc-draw":return"PC-Draw";case"pcjr":return"PCjr";case"prokey":return"ProKey";case"rbase":case"r:base":return"R:Base";case"sidekick":return"SideKick";case"visicalc":return"VisiCalc";case"war-craft":return"Warcraft";case"wordstar":return"WordStar";default:return""}}async function V(e){let i=c(e);i.focus(),await navigator.clipboard.writeText(`${i.textContent}`).then(function(){console.log(`Copied ${qe(i.textContent.length)} to the clipboard`)},function(r){console.error(`could not save any text to the clipboard: ${r}`)})}async function $(e){let i=c(e);i.focus(),await navigator.clipboard.writeText(`${i.value}`).then(function(){console.log(`Copied ${qe(i.value.length)} to the clipboard`)},function(r){console.error(`could not save any text to the clipboard: ${r}`)})}function c(e){let i=document.getElementById(e);if(i==null){console.error(`The ${e} for getElmById() element is null.`);return}return i}function qe(e=0){let s=Math.pow(1e3,2),a=Math.pow(1e3,3);return e>a?`${(Math.round(e*100/a)/100).toFixed(2)} GB`:e>s?`${(Math.round(e*100/s)/100).toFixed(1)} MB`:e>1e3?`${(Math.round(e*100/1e3)/100).toFixed()} kB`:`${Math.round(e).toFixed()} bytes`}(()=>{"use strict";function e(t){return function(){t.forEach(n=>{n.disabled=!0,n.classList.remove("btn-outline-primary"),n.classList.add("btn-light")})}}function i(t){return function(){t.forEach(n=>{n.disabled=!1,n.classList.remove("btn-light"),n.classList.add("btn-outline-primary")})}}let r=document.getElementById("artifact-editor-modal"),l=document.getElementsByName("artifact-editor-dataeditor");r.addEventListener("shown.bs.modal",()=>{e(l)()}),r.addEventListener("hidden.bs.modal",()=>{i(l)()});let s=document.getElementById("asset-editor-modal"),a=document.getElementsByName("artifact-editor-fileeditor");s.addEventListener("shown.bs.modal",()=>{e(a)()}),s.addEventListener("hidden.bs.modal",()=>{i(a)()});let o=document.getElementById("emulate-editor-modal"),u=document.getElementsByName("artifact-editor-emueditor");o.addEventListener("shown.bs.modal",()=>{e(u)()}),o.addEventListener("hidden.bs.modal",()=>{i(u)()});let v=document.getElementById("emulate-run-program");if(v!==null){let t=document.getElementById("emulate-guess-program");if(t===null)throw new Error("The guess program input is missing.");v.addEventListener("input",()=>{v.value=v.value.toUpperCase().replace(/\s{2,}/g," ");let n=v.value;if(n!==""&&n!==" "){t.disabled=!0;return}t.disabled=!1})}let E=document.getElementById("artifact-embed-title");E?.addEventListener("click",()=>V("artifact-embed-title-value"));let C=document.getElementById("artifact-embed-author");C?.addEventListener("click",()=>V("artifact-embed-author-value"));let L=document.getElementById("artifact-embed-group");if(L?.addEventListener("click",()=>V("artifact-embed-group-value")),c("artifact-dataeditor-key-value")===null)throw new Error("The key value is missing.");let x=c("artifact-dataeditor-key-label");if(x===null)throw new Error("The key label is missing.");if(x.addEventListener("click",()=>$("artifact-dataeditor-key-value")),c("artifact-fileeditor-key-value")===null)throw new Error("The key value is missing.");let Re=c("artifact-fileeditor-key-label");if(x===null)throw new Error("The key label is missing.");if(Re.addEventListener("click",()=>$("artifact-fileeditor-key-value")),c("artifact-dataeditor-unique-id-value")===null)throw new Error("The unique id value is missing.");let re=c("artifact-dataeditor-unique-id-label");if(re===null)throw new Error("The unique id label is missing.");if(re.addEventListener("click",()=>$("artifact-dataeditor-unique-id-value")),c("artifact-fileeditor-unique-id-value")===null)throw new Error("The unique id value is missing.");let ie=c("artifact-fileeditor-unique-id-label");if(ie===null)throw new Error("The unique id label is missing.");if(ie.addEventListener("click",()=>$("artifact-fileeditor-unique-id-value")),c("artifact-editor-location-value")===null)throw new Error("The location value is missing.");let ne=c("artifact-editor-location-label");if(ne===null)throw new Error("The location label is missing.");ne.addEventListener("click",()=>$("artifact-editor-location-value"));let ae=c("artifact-editor-templocation");if(ae!=null){let t=c("artifact-editor-templocation-label");t!==null&&t.addEventListener("click",()=>$("artifact-editor-templocation"))}if(document.getElementById("artifact-editor-os-label")===null)throw new Error("The operating system label is missing.");let b=document.getElementById("artifact-editor-operating-system");if(b===null)throw new Error("The operating system input is missing.");b.addEventListener("input",Y);let y=document.getElementById("artifact-editor-category");if(y===null)throw new Error("The category input is missing.");y.addEventListener("input",G),Y(),G();function Y(){b.selectedIndex==0&&(b.classList.remove("is-valid"),b.classList.add("is-invalid"))}function G(){y.selectedIndex==0&&(y.classList.remove("is-valid"),y.classList.add("is-invalid"))}let j=document.getElementsByName("prereset-classifications");if(j.length===0)throw new Error("The preset classifications are missing.");for(let t=0;t<j.length;t++)Me(t);function Me(t){let n=j[t],p=n.getAttribute("data-preset-os");if(p===null)throw new Error("data-preset-os attribute is required for ${elm.id}.");let m=n.getAttribute("data-preset-tag");if(m===null)throw new Error("data-preset-tag attribute is required for ${elm.id}.");n.addEventListener("click",I=>{I.preventDefault(),b.value=p,b.classList.remove("is-invalid"),y.value=m,y.classList.remove("is-invalid"),Y(),G()})}let w=document.getElementById("artifact-editor-filename");if(w===null)throw new Error("The filename input is missing.");w.addEventListener("input",t=>{t.target.classList.remove("is-valid"),t.target.classList.remove("is-invalid"),t.target.value.trim().length===0&&t.target.classList.add("is-invalid")});let se=document.getElementById("artifact-editor-filename-reset");if(se===null)throw new Error("The filename reset is missing.");let O=document.getElementsByName("artifact-editor-filename-undo");if(O.length===0)throw new Error("The filename resetter is missing.");se.addEventListener("click",()=>{if(w.classList.remove("is-valid"),O.length===0)throw new Error("The filename resetter is missing.");w.value=O[0].value,w.classList.add("is-valid"),w.classList.remove("is-invalid"),w.value.trim().length===0&&w.classList.add("is-invalid")});let N=document.getElementById("artifact-editor-releaser-1");if(N===null)throw new Error("The releaser 1 input is missing.");N.addEventListener("input",t=>R(t.target));let q=document.getElementById("artifact-editor-releaser-2");if(q===null)throw new Error("The releaser 2 input is missing.");q.addEventListener("input",t=>R(t.target));let oe=document.getElementById("artifact-editor-releaser-undo");if(oe===null)throw new Error("The releasers reset is missing.");oe.addEventListener("click",Se);function Se(){let t=N.getAttribute("data-reset-rel1");if(t===null)throw new Error("data-reset-rel1 attribute is required for artifact-editor-releaser-1.");N.value=t,R(N);let n=q.getAttribute("data-reset-rel2");if(n===null)throw new Error("data-reset-rel2 attribute is required for artifact-editor-releaser-2.");q.value=n,R(q)}document.addEventListener("change",t=>{let n=t.target;if(n.matches("#artifact-editor-releaser-1, #artifact-editor-releaser-2")){let p=n.getAttribute("list"),m=document.querySelectorAll(`#${p} option`);if(Array.from(m).some(D=>D.value===n.value)){let D=document.getElementById("artifact-editor-releaser-update");D&&htmx.trigger(D,"submit-releasers")}}});let h=document.getElementById("artifact-editor-title");if(h===null)throw new Error("The title input is missing.");h.addEventListener("paste",Ne),h.addEventListener("input",t=>{t.target.classList.remove("is-valid")});let le=document.getElementById("artifact-editor-title-undo");if(le===null)throw new Error("The title reset is missing.");let W=document.getElementsByName("artifact-editor-titleundo");if(W.length===0)throw new Error("The title resetter is missing.");le.addEventListener("click",()=>{if(h.classList.remove("is-valid"),W.length===0)throw new Error("The title resetter is missing.");h.value=W[0].value,h.classList.add("is-valid")});let de=document.getElementById("artifact-editor-titleize");if(de.length===0)throw new Error("The titleize button is missing.");de.addEventListener("click",()=>{h.value=te(h.value);let t=new Event("keyup");h.dispatchEvent(t)});let ce=document.getElementById("artifact-editor-title-delete");if(ce.length===0)throw new Error("The title delete button is missing.");ce.addEventListener("click",()=>{h.value="";let t=new Event("keyup");h.dispatchEvent(t)});let K=document.getElementById("artifact-editor-credit-text");if(K===null)throw new Error("The creator text input is missing.");K.addEventListener("input",t=>{t.target.classList.remove("is-valid")});let X=document.getElementById("artifact-editor-credit-ill");if(X===null)throw new Error("The creator illustrator input is missing.");X.addEventListener("input",t=>{t.target.classList.remove("is-valid")});let Z=document.getElementById("artifact-editor-credit-prog");if(Z===null)throw new Error("The creator programmer input is missing.");Z.addEventListener("input",t=>{t.target.classList.remove("is-valid")});let H=document.getElementById("artifact-editor-credit-audio");if(H===null)throw new Error("The creator audio input is missing.");H.addEventListener("input",t=>{t.target.classList.remove("is-valid")});let J=document.getElementById("artifact-editor-credits-undo");if(J===null)throw new Error("The creator resetter is missing.");let ue=document.getElementById("artifact-editor-credit-undo");if(ue===null)throw new Error("The creator reset is missing.");ue.addEventListener("click",()=>{if(J.length===0)throw new Error("The creator resetter is missing.");let t=J.value.split(";");if(t.length!=4)throw new Error("The creator resetter values are invalid.");let n=t[0],p=t[1],m=t[2],I=t[3];K.value=n,X.value=p,Z.value=m,H.value=I});let me=document.getElementById("artifact-editor-virustotal");if(me===null)throw new Error("The virustotal input is missing.");me.addEventListener("input",t=>{t.target.classList.remove("is-valid","is-invalid");let n=t.target.value.trim();n.length!=0&&(n.startsWith("https://www.virustotal.com/")||t.target.classList.add("is-invalid"))});let d=document.getElementById("artifact-editor-year");if(d===null)throw new Error("The year input is missing.");d.addEventListener("input",()=>{let t=parseInt(d.value,10);t>=79&&t<=99&&(d.value=1900+t),z(d,f,g,M)});let f=document.getElementById("artifact-editor-month");if(f===null)throw new Error("The month input is missing.");f.addEventListener("input",()=>{z(d,f,g,M)});let g=document.getElementById("artifact-editor-day");if(g===null)throw new Error("The day input is missing.");g.addEventListener("input",()=>{z(d,f,g,M)});let M=!1;d.value==0&&f.value==0&&g.value==0&&(M=!0);let ve=document.getElementById("artifact-editor-date-reset");if(ve===null)throw new Error("The date reset is missing.");let fe=document.getElementById("artifact-editor-date-resetter");if(fe===null)throw new Error("The date resetter is missing.");ve.addEventListener("click",()=>{d.classList.remove("is-invalid","is-valid"),f.classList.remove("is-invalid","is-valid"),g.classList.remove("is-invalid","is-valid");let n=fe.value.split("-");if(n.length!=3)throw new Error("The date resetter values are invalid.");d.value=n[0],f.value=n[1],g.value=n[2]});let S=document.getElementById("artifact-editor-comment");if(S===null)throw new Error("The comment input is missing.");S.addEventListener("input",t=>{if(t.target.classList.remove("is-valid"),(d.value==0&&f.value==0&&g.value==0)==!1)return;let m=/(0[1-9]|1[0-2])\/(0[1-9]|[12][0-9]|3[01])\/(\d{2})/.exec(t.target.value);if(m||(m=/(0[1-9]|1[0-2])-(0[1-9]|[12][0-9]|3[01])-(\d{2})/.exec(t.target.value)),m){let I=m[1],D=m[2],Pe=m[3],P=parseInt(Pe,10);d.value=2e3+P,P>=79&&P<=99&&(d.value=1900+P),f.value=I,g.value=D;let Ie=document.getElementById("artifact-editor-date-update");Ie!==null&&Ie.click()}});let ge=document.getElementById("artifact-editor-comment-undo");if(ge===null)throw new Error("The comment reset is missing.");let pe=document.getElementById("artifact-editor-comment-resetter");if(pe===null)throw new Error("The comment resetter is missing.");ge.addEventListener("click",()=>{S.classList.remove("is-valid"),S.value=pe.value});let he=document.getElementById("artifact-editor-date-lastmod");if(he!==null){let t=document.getElementById("artifact-editor-date-lastmodder");if(t===null)throw new Error("The date last modder input is missing.");he.addEventListener("click",()=>{d.classList.remove("is-invalid","is-valid"),f.classList.remove("is-invalid","is-valid"),g.classList.remove("is-invalid","is-valid");let p=t.value.split("-");if(p.length!=3)throw new Error("The date last modder values are invalid.");d.value=p[0],f.value=p[1],g.value=p[2]})}let Ee=document.getElementById("artifact-editor-links-reset");if(Ee===null)throw new Error("The links reset is missing.");let B=document.getElementById("artifact-editor-youtube"),we=document.getElementById("artifact-editor-youtube-reset");if(B===null||we===null)throw new Error("A YouTube input is missing.");let F=document.getElementById("artifact-editor-demozoo"),Le=document.getElementById("artifact-editor-demozoo-reset");if(F===null||Le===null)throw new Error("A Demozoo input is missing.");let U=document.getElementById("artifact-editor-pouet"),be=document.getElementById("artifact-editor-pouet-reset");if(U===null||be===null)throw new Error("A Pouet input is missing.");let T=document.getElementById("artifact-editor-16colors"),ye=document.getElementById("artifact-editor-16colors-reset");if(T===null||ye===null)throw new Error("A 16colors input is missing.");let k=document.getElementById("artifact-editor-github"),Be=document.getElementById("artifact-editor-github-reset");if(k===null||Be===null)throw new Error("A GitHub input is missing.");Ee.addEventListener("click",()=>{B.classList.remove("is-invalid","is-valid"),F.classList.remove("is-invalid","is-valid"),U.classList.remove("is-invalid","is-valid"),T.classList.remove("is-invalid","is-valid"),k.classList.remove("is-invalid","is-valid"),B.value=we.value,F.value=Le.value,U.value=be.value,T.value=ye.value,k.value=Be.value});let Fe=45e4,Ue=2e5;B.addEventListener("paste",()=>{setTimeout(()=>{B.value=B.value.replace(/https?:\/\/www\.youtube\.com\/watch\?v=/,"")},0)}),B.addEventListener("input",t=>De(t.target)),F.addEventListener("input",t=>ee(t.target,Fe)),U.addEventListener("input",t=>ee(t.target,Ue)),T.addEventListener("paste",()=>{setTimeout(()=>{T.value=T.value.replace(/https?:\/\/16colo\.rs\//,"")},0)}),T.addEventListener("input",t=>Ce(t.target)),k.addEventListener("paste",()=>{setTimeout(()=>{k.value=k.value.replace(/https?:\/\/github\.com\//,"")},0)}),k.addEventListener("input",t=>$e(t.target))})();})();
@@ -1,2 +1,2 @@
 /* layout.min.js © Defacto2 2026 */
-(()=>{var g="ArrowRight",p="ArrowLeft",D=document.getElementById("paginationStart"),S=document.getElementById("paginationPrev"),R=document.getElementById("paginationPrev2"),A=document.getElementById("paginationNext"),C=document.getElementById("paginationNext2"),N=document.getElementById("paginationEnd"),q=document.getElementById("layout-search-program"),K=document.getElementById("layout-search-filename"),P=document.getElementById("layout-search-groups");function z(){document.addEventListener("keydown",function(t){if(t.ctrlKey&&t.key==p){D?.click();return}if(t.ctrlKey&&t.key==g){N?.click();return}if(t.shiftKey&&t.key==p){R?.click();return}if(t.shiftKey&&t.key==g){C?.click();return}if(t.key==p){S?.click();return}if(t.key==g){A?.click();return}if(t.altKey&&t.shiftKey){let i=document.getElementById("search-result-1"),r=document.getElementById("search-result-2"),e=document.getElementById("search-result-3"),a=document.getElementById("search-result-4"),o=document.getElementById("search-result-5"),u=document.getElementById("search-result-6"),h=document.getElementById("search-result-7"),b=document.getElementById("search-result-8"),I=document.getElementById("search-result-9"),B=document.getElementById("search-result-0"),k=document.getElementById("search-result--"),L=document.getElementById("search-result-="),T=document.getElementById("search-result-["),$=document.getElementById("search-result-]");switch(t.key){case"P":case"p":q&&(t.preventDefault(),q.click());break;case"G":case"g":P&&(t.preventDefault(),P.click());break;case"N":case"n":K&&(t.preventDefault(),K.click());break;case"1":case"!":i&&(t.preventDefault(),window.open(i.href,"srch1"));break;case"2":case"@":r&&(t.preventDefault(),window.open(r.href,"srch2"));break;case"3":case"#":e&&(t.preventDefault(),window.open(e.href,"srch3"));break;case"4":case"$":a&&(t.preventDefault(),window.open(a.href,"srch4"));break;case"5":case"%":o&&(t.preventDefault(),window.open(o.href,"srch5"));break;case"6":case"^":u&&(t.preventDefault(),window.open(u.href,"srch6"));break;case"7":case"&":h&&(t.preventDefault(),window.open(h.href,"srch7"));break;case"8":case"*":b&&(t.preventDefault(),window.open(b.href,"srch8"));break;case"9":case"(":I&&(t.preventDefault(),window.open(I.href,"srch9"));break;case"0":case")":B&&(t.preventDefault(),window.open(B.href,"srch10"));break;case"-":case"_":k&&(t.preventDefault(),window.open(k.href,"srch11"));break;case"+":case"=":L&&(t.preventDefault(),window.open(L.href,"srch12"));break;case"[":case"{":T&&(t.preventDefault(),window.open(T.href,"srch13"));break;case"]":case"}":$&&(t.preventDefault(),window.open($.href,"srch14"));break}}})}function U(t){let i=document.getElementById(t);if(typeof i>"u"||i===null)return;i.addEventListener("change",function(){O(i.value)});let r="paginationRangeLabel",e=document.getElementById(r);if(e===null)throw new Error(`The ${r} for pagination() element is null.`);i.addEventListener("input",function(){e.textContent="Jump to page "+i.value})}function O(t){let i=new URL(window.location.href),e=i.pathname.split("/"),a=e[e.length-1];!isNaN(a)&&typeof Number(a)=="number"?e[e.length-1]=t:e.push(t),i.pathname=e.join("/"),window.location.href=i.href}var y="search-htmx-alert",X="search-htmx-clear",x="search-htmx-indicator",w="search-htmx-input";function F(){let t=document.getElementById(X);t!==null&&t.addEventListener("click",function(){j(y,w,x)})}function j(){let t=document.getElementById(w);if(t===null)throw new Error(`The ${w} for clearer() element is null`);let i=document.getElementById(y);if(i===null)throw new Error(`The htmx alert element ${y} is null`);let r=document.getElementById(x);if(r===null)throw new Error(`The htmx search indicator element ${x} is null`);let e=document.getElementById("search-htmx-results");if(e===null)throw new Error("The htmx search indicator element is null");t.value="",t.focus(),i.setAttribute("hidden","true"),r.style.opacity=0,e.innerHTML=""}var M=J;function J(){F(),document.body.addEventListener("htmx:beforeRequest",function(t){n(t,"artifact-editor-classifications-undo"),n(t,"artifact-editor-text-for-dos"),n(t,"artifact-editor-text-for-amiga"),n(t,"artifact-editor-proof-of-release"),n(t,"artifact-editor-intro-for-dos"),n(t,"artifact-editor-intro-for-win"),n(t,"artifact-editor-intro-for-bbs"),n(t,"artifact-editor-trainer-for-dos"),n(t,"artifact-editor-trainer-for-win"),n(t,"artifact-editor-ansi-for-bbs"),n(t,"artifact-editor-ansi-for-text"),n(t,"artifact-editor-magazine-for-text"),n(t,"artifact-editor-magazine-for-dos"),n(t,"artifact-editor-dox"),n(t,"artifact-editor-inst-for-dos"),n(t,"artifact-editor-inst-for-win")}),document.body.addEventListener("htmx:afterRequest",function(t){s(t,"search-htmx-input","search-htmx-alert");let i="uploader-image-alert";s(t,"uploader-image-form",i),s(t,"uploader-image-releaser-1",i),s(t,"uploader-image-releaser-2",i);let r="uploader-intro-alert";s(t,"uploader-intro-form",r),s(t,"uploader-intro-releaser-1",r),s(t,"uploader-intro-releaser-2",r);let e="uploader-text-alert";s(t,"uploader-text-form",e),s(t,"uploader-text-releaser-1",e),s(t,"uploader-text-releaser-2",e);let a="uploader-trainer-alert";s(t,"uploader-trainer-form",a),s(t,"uploader-trainer-releaser-1",a),s(t,"uploader-trainer-releaser-2",a),H(t,"artifact-editor-hidden","artifact-editor-public"),H(t,"artifact-editor-public","artifact-editor-hidden"),l(t,"artifact-editor-operating-system"),l(t,"artifact-editor-category"),c(t,"artifact-editor-text-for-dos"),c(t,"artifact-editor-text-for-amiga"),c(t,"artifact-editor-proof-of-release"),c(t,"artifact-editor-intro-for-dos"),c(t,"artifact-editor-intro-for-win"),c(t,"artifact-editor-intro-for-bbs"),c(t,"artifact-editor-trainer-for-dos"),c(t,"artifact-editor-trainer-for-win"),c(t,"artifact-editor-ansi-for-bbs"),c(t,"artifact-editor-ansi-for-text"),c(t,"artifact-editor-magazine-for-text"),c(t,"artifact-editor-magazine-for-dos"),c(t,"artifact-editor-dox"),c(t,"artifact-editor-inst-for-dos"),c(t,"artifact-editor-inst-for-win"),l(t,"artifact-editor-releaser-undo"),W(t,"artifact-editor-releaser-update"),l(t,"artifact-editor-title"),E(t,"artifact-editor-title-reset","artifact-editor-title"),l(t,"artifact-editor-filename"),E(t,"artifact-editor-filename-reset","artifact-editor-filename"),l(t,"artifact-editor-virustotal"),l(t,"artifact-editor-date-reset"),l(t,"artifact-editor-date-lastmod"),Q(t,"artifact-editor-date-update"),l(t,"artifact-editor-credit-text"),l(t,"artifact-editor-credit-ill"),l(t,"artifact-editor-credit-prog"),l(t,"artifact-editor-credit-audio"),_(t,'artifact-editor-credit-undo"'),l(t,"artifact-editor-comment"),E(t,"artifact-editor-comment-undo","artifact-editor-comment"),l(t,"artifact-editor-youtube"),l(t,"artifact-editor-demozoo"),l(t,"artifact-editor-pouet"),l(t,"artifact-editor-16colors"),l(t,"artifact-editor-github"),l(t,"artifact-editor-relations"),l(t,"artifact-editor-websites"),V(t,"artifact-editor-links-reset")})}function V(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-alert",e=document.getElementById(r);if(typeof e>"u"||e===null)throw new Error(`The htmx alert element ${r} is null`);t.detail.successful&&console.log("okay"),t.detail.failed&&t.detail.xhr&&console.log("error"),m(e)}function _(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-alert",e=document.getElementById(r);if(typeof e>"u"||e===null)throw new Error(`The htmx alert element ${r} is null`);if(t.detail.successful)return d(e,"artifact-editor-credit-text"),d(e,"artifact-editor-credit-ill"),d(e,"artifact-editor-credit-prog"),d(e,"artifact-editor-credit-audio");if(t.detail.failed&&t.detail.xhr)return f(t,"artifact-editor-credit-text",e),f(t,"artifact-editor-credit-ill",e),f(t,"artifact-editor-credit-prog",e),f(t,"artifact-editor-credit-audio",e);m(e)}function Q(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-alert",e=document.getElementById(r);if(typeof e>"u"||e===null)throw new Error(`The htmx alert element ${r} is null`);let a="artifact-editor-year",o="artifact-editor-month",u="artifact-editor-day";if(t.detail.successful)return d(e,a),d(e,o),d(e,u);if(t.detail.failed&&t.detail.xhr)return f(t,a,e),f(t,o,e),f(t,u,e);m(e)}function W(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-alert",e=document.getElementById(r);if(typeof e>"u"||e===null)throw new Error(`The htmx alert element ${r} is null`);let a="artifact-editor-releaser-1",o="artifact-editor-releaser-2";if(t.detail.successful)return d(e,a),d(e,o);if(t.detail.failed&&t.detail.xhr)return f(t,a,e),f(t,o,e);m(e)}function n(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-operating-system",e="artifact-editor-category",a=document.getElementById(r);if(typeof a>"u"||a===null)return;a.classList.remove("is-valid");let o=document.getElementById(e);typeof o>"u"||o===null||o.classList.remove("is-valid")}function l(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-alert",e=document.getElementById(r);if(typeof e>"u"||e===null)throw new Error(`The htmx alert element ${r} is null`);if(t.detail.successful)return d(e,i);if(t.detail.failed&&t.detail.xhr)return f(t,i,e);m(e)}function c(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-alert",e="artifact-editor-operating-system",a="artifact-editor-category",o=document.getElementById(r);if(typeof o>"u"||o===null)throw new Error(`The htmx alert element ${r} is null`);if(t.detail.successful){d(o,e),d(o,a);return}if(t.detail.failed&&t.detail.xhr)return f(t,null,o);m(o)}function E(t,i,r){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let e="artifact-editor-alert",a=document.getElementById(e);if(typeof a>"u"||a===null)throw new Error(`The htmx alert element ${e} is null`);if(console.log(`afterReset ${i} ${r}`,t.detail),t.detail.successful)return console.log(t.detail.successful,"sucessful"),d(a,r);if(t.detail.failed&&t.detail.xhr)return console.log(t.detail.failed,"failed"),f(t,r,a);m(a)}function d(t,i){if(console.log(`updateSuccess ${i}`),t.innerText="",t.classList.add("d-none"),typeof i>"u"||i===null)return;let r=document.getElementById(i);typeof r>"u"||r===null||(r.classList.remove("is-invalid"),r.classList.add("is-valid"))}function f(t,i,r){let e=t.detail.xhr;r.innerText=`${G()} Could not update the database record, ${e.responseText}.`,r.classList.remove("d-none"),i!==null&&document.getElementById(i).classList.remove("is-valid")}function H(t,i,r){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let e="artifact-editor-alert",a=document.getElementById(e);if(typeof a>"u"||a===null)throw new Error(`The htmx alert element ${e} is null`);if(t.detail.successful)return Y(t,i,a);if(t.detail.failed&&t.detail.xhr)return Z(t,r,a);m(a)}function Y(t,i,r){r.classList.add("d-none"),r.innerText="";let e=document.getElementById("artifact-editor-modal-header");switch(i){case"artifact-editor-hidden":e.classList.remove("bg-success-subtle"),e.classList.add("bg-danger-subtle");break;case"artifact-editor-public":e.classList.add("bg-success-subtle"),e.classList.remove("bg-danger-subtle");break;default:console.error(`The record success ${i} is not supported.`)}}function Z(t,i,r){let e=t.detail.xhr;r.innerText=`${G()} Could not update the database record, ${e.responseText}.`,r.classList.remove("d-none"),document.getElementById(i).checked=!0}function G(){let t=new Date,i=t.getHours(),r=t.getMinutes(),e=t.getSeconds();return r=(r<10?"0":"")+r,e=(e<10?"0":"")+e,i+":"+r+":"+e}function s(t,i,r){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let e=document.getElementById(r);if(typeof e>"u"||e===null)throw new Error(`The htmx alert element ${r} is null`);if(t.detail.successful)return v(t,e);if(t.detail.failed&&t.detail.xhr)return et(e,t);m(e)}function v(t,i){i.classList.add("d-none"),i.innerText="";let r="-form",e=t.target.id;if(e.slice(-r.length)==r){let o=e.replace(r,"-file");tt(t,`#${o}`);let u=e.replace(r,"-results"),h=document.getElementById(u);h&&h.classList.remove("d-none")}}function tt(t,i){let r=t.target.querySelector(i);if(r){r.value="",r.innerText="";return}console.error(`The reset file ${i} element is null`)}function et(t,i){let r=i.detail.xhr;t.innerText="Our server is not working at the moment.",console.log(`XHR request did not work, ${r.status} status: ${r.responseText}.`),t.classList.remove("d-none")}function m(t){t.innerText="Something with the browser is not working, please try again or refresh the page.",t.classList.remove("d-none")}(()=>{"use strict";M(),z(),U("paginationRange"),rt(),it()})();function rt(){let t=document.querySelectorAll('[data-bs-toggle="tooltip"]');if(t===null)throw new Error("Tooltip trigger list not found");if(typeof bootstrap.Tooltip>"u")throw new Error("Bootstrap Tooltip is undefined");let i=[...t].map(r=>new bootstrap.Tooltip(r))}function it(){let t="fluid-button",i="fluid",r=document.getElementById(t),e="box-container";document.addEventListener("DOMContentLoaded",()=>{if(document.querySelector("#fluid-artifacts")){let o=localStorage.getItem(i),u=document.getElementById(e);o==="1"&&e&&(u.classList.toggle("container-xxl"),u.classList.toggle("container-fluid"),r.textContent="Fix columns")}}),r&&r.addEventListener("click",()=>{document.getElementById(e).classList.toggle("container-xxl"),document.getElementById(e).classList.toggle("container-fluid"),localStorage.getItem(i)==="1"?(localStorage.removeItem(i),document.getElementById(t).textContent="Unlock columns"):(localStorage.setItem(i,"1"),document.getElementById(t).textContent="Fix columns")})}})();
+(()=>{var g="ArrowRight",p="ArrowLeft",D=document.getElementById("paginationStart"),S=document.getElementById("paginationPrev"),R=document.getElementById("paginationPrev2"),A=document.getElementById("paginationNext"),C=document.getElementById("paginationNext2"),N=document.getElementById("paginationEnd"),q=document.getElementById("layout-search-program"),K=document.getElementById("layout-search-filename"),P=document.getElementById("layout-search-groups");function z(){document.addEventListener("keydown",function(t){if(t.ctrlKey&&t.key==p){D?.click();return}if(t.ctrlKey&&t.key==g){N?.click();return}if(t.shiftKey&&t.key==p){R?.click();return}if(t.shiftKey&&t.key==g){C?.click();return}if(t.key==p){S?.click();return}if(t.key==g){A?.click();return}if(t.altKey&&t.shiftKey){let i=document.getElementById("search-result-1"),r=document.getElementById("search-result-2"),e=document.getElementById("search-result-3"),a=document.getElementById("search-result-4"),o=document.getElementById("search-result-5"),u=document.getElementById("search-result-6"),h=document.getElementById("search-result-7"),b=document.getElementById("search-result-8"),I=document.getElementById("search-result-9"),B=document.getElementById("search-result-0"),k=document.getElementById("search-result--"),L=document.getElementById("search-result-="),T=document.getElementById("search-result-["),$=document.getElementById("search-result-]");switch(t.key){case"P":case"p":q&&(t.preventDefault(),q.click());break;case"G":case"g":P&&(t.preventDefault(),P.click());break;case"N":case"n":K&&(t.preventDefault(),K.click());break;case"1":case"!":i&&(t.preventDefault(),window.open(i.href,"srch1"));break;case"2":case"@":r&&(t.preventDefault(),window.open(r.href,"srch2"));break;case"3":case"#":e&&(t.preventDefault(),window.open(e.href,"srch3"));break;case"4":case"$":a&&(t.preventDefault(),window.open(a.href,"srch4"));break;case"5":case"%":o&&(t.preventDefault(),window.open(o.href,"srch5"));break;case"6":case"^":u&&(t.preventDefault(),window.open(u.href,"srch6"));break;case"7":case"&":h&&(t.preventDefault(),window.open(h.href,"srch7"));break;case"8":case"*":b&&(t.preventDefault(),window.open(b.href,"srch8"));break;case"9":case"(":I&&(t.preventDefault(),window.open(I.href,"srch9"));break;case"0":case")":B&&(t.preventDefault(),window.open(B.href,"srch10"));break;case"-":case"_":k&&(t.preventDefault(),window.open(k.href,"srch11"));break;case"+":case"=":L&&(t.preventDefault(),window.open(L.href,"srch12"));break;case"[":case"{":T&&(t.preventDefault(),window.open(T.href,"srch13"));break;case"]":case"}":$&&(t.preventDefault(),window.open($.href,"srch14"));break}}})}function U(t){let i=document.getElementById(t);if(typeof i>"u"||i===null)return;i.addEventListener("change",function(){O(i.value)});let r="paginationRangeLabel",e=document.getElementById(r);if(e===null)throw new Error(`The ${r} for pagination() element is null.`);i.addEventListener("input",function(){e.textContent="Jump to page "+i.value})}function O(t){let i=new URL(window.location.href),e=i.pathname.split("/"),a=e[e.length-1];!isNaN(a)&&typeof Number(a)=="number"?e[e.length-1]=t:e.push(t),i.pathname=e.join("/"),window.location.href=i.href}var y="search-htmx-alert",X="search-htmx-clear",x="search-htmx-indicator",w="search-htmx-input";function F(){let t=document.getElementById(X);t!==null&&t.addEventListener("click",function(){j(y,w,x)})}function j(){let t=document.getElementById(w);if(t===null)throw new Error(`The ${w} for clearer() element is null`);let i=document.getElementById(y);if(i===null)throw new Error(`The htmx alert element ${y} is null`);let r=document.getElementById(x);if(r===null)throw new Error(`The htmx search indicator element ${x} is null`);let e=document.getElementById("search-htmx-results");if(e===null)throw new Error("The htmx search indicator element is null");t.value="",t.focus(),i.setAttribute("hidden","true"),r.style.opacity=0,e.innerHTML=""}var M=J;function J(){F(),document.body.addEventListener("htmx:beforeRequest",function(t){n(t,"artifact-editor-classifications-undo"),n(t,"artifact-editor-text-for-dos"),n(t,"artifact-editor-text-for-amiga"),n(t,"artifact-editor-proof-of-release"),n(t,"artifact-editor-intro-for-dos"),n(t,"artifact-editor-intro-for-win"),n(t,"artifact-editor-intro-for-bbs"),n(t,"artifact-editor-trainer-for-dos"),n(t,"artifact-editor-trainer-for-win"),n(t,"artifact-editor-ansi-for-bbs"),n(t,"artifact-editor-ansi-for-text"),n(t,"artifact-editor-magazine-for-text"),n(t,"artifact-editor-magazine-for-dos"),n(t,"artifact-editor-dox"),n(t,"artifact-editor-inst-for-dos"),n(t,"artifact-editor-inst-for-win")}),document.body.addEventListener("htmx:afterRequest",function(t){s(t,"search-htmx-input","search-htmx-alert");let i="uploader-image-alert";s(t,"uploader-image-form",i),s(t,"uploader-image-releaser-1",i),s(t,"uploader-image-releaser-2",i);let r="uploader-intro-alert";s(t,"uploader-intro-form",r),s(t,"uploader-intro-releaser-1",r),s(t,"uploader-intro-releaser-2",r);let e="uploader-text-alert";s(t,"uploader-text-form",e),s(t,"uploader-text-releaser-1",e),s(t,"uploader-text-releaser-2",e);let a="uploader-trainer-alert";s(t,"uploader-trainer-form",a),s(t,"uploader-trainer-releaser-1",a),s(t,"uploader-trainer-releaser-2",a),H(t,"artifact-editor-hidden","artifact-editor-public"),H(t,"artifact-editor-public","artifact-editor-hidden"),l(t,"artifact-editor-operating-system"),l(t,"artifact-editor-category"),c(t,"artifact-editor-text-for-dos"),c(t,"artifact-editor-text-for-amiga"),c(t,"artifact-editor-proof-of-release"),c(t,"artifact-editor-intro-for-dos"),c(t,"artifact-editor-intro-for-win"),c(t,"artifact-editor-intro-for-bbs"),c(t,"artifact-editor-trainer-for-dos"),c(t,"artifact-editor-trainer-for-win"),c(t,"artifact-editor-ansi-for-bbs"),c(t,"artifact-editor-ansi-for-text"),c(t,"artifact-editor-magazine-for-text"),c(t,"artifact-editor-magazine-for-dos"),c(t,"artifact-editor-dox"),c(t,"artifact-editor-inst-for-dos"),c(t,"artifact-editor-inst-for-win"),l(t,"artifact-editor-releaser-undo"),W(t,"artifact-editor-releaser-update"),l(t,"artifact-editor-title"),E(t,"artifact-editor-title-reset","artifact-editor-title"),l(t,"artifact-editor-filename"),E(t,"artifact-editor-filename-reset","artifact-editor-filename"),l(t,"artifact-editor-virustotal"),l(t,"artifact-editor-date-reset"),l(t,"artifact-editor-date-lastmod"),Q(t,"artifact-editor-date-update"),l(t,"artifact-editor-credit-text"),l(t,"artifact-editor-credit-ill"),l(t,"artifact-editor-credit-prog"),l(t,"artifact-editor-credit-audio"),_(t,'artifact-editor-credit-undo"'),l(t,"artifact-editor-comment"),E(t,"artifact-editor-comment-undo","artifact-editor-comment"),l(t,"artifact-editor-youtube"),l(t,"artifact-editor-demozoo"),l(t,"artifact-editor-pouet"),l(t,"artifact-editor-16colors"),l(t,"artifact-editor-github"),V(t,"artifact-editor-links-reset")})}function V(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-alert",e=document.getElementById(r);if(typeof e>"u"||e===null)throw new Error(`The htmx alert element ${r} is null`);t.detail.successful&&console.log("okay"),t.detail.failed&&t.detail.xhr&&console.log("error"),m(e)}function _(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-alert",e=document.getElementById(r);if(typeof e>"u"||e===null)throw new Error(`The htmx alert element ${r} is null`);if(t.detail.successful)return d(e,"artifact-editor-credit-text"),d(e,"artifact-editor-credit-ill"),d(e,"artifact-editor-credit-prog"),d(e,"artifact-editor-credit-audio");if(t.detail.failed&&t.detail.xhr)return f(t,"artifact-editor-credit-text",e),f(t,"artifact-editor-credit-ill",e),f(t,"artifact-editor-credit-prog",e),f(t,"artifact-editor-credit-audio",e);m(e)}function Q(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-alert",e=document.getElementById(r);if(typeof e>"u"||e===null)throw new Error(`The htmx alert element ${r} is null`);let a="artifact-editor-year",o="artifact-editor-month",u="artifact-editor-day";if(t.detail.successful)return d(e,a),d(e,o),d(e,u);if(t.detail.failed&&t.detail.xhr)return f(t,a,e),f(t,o,e),f(t,u,e);m(e)}function W(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-alert",e=document.getElementById(r);if(typeof e>"u"||e===null)throw new Error(`The htmx alert element ${r} is null`);let a="artifact-editor-releaser-1",o="artifact-editor-releaser-2";if(t.detail.successful)return d(e,a),d(e,o);if(t.detail.failed&&t.detail.xhr)return f(t,a,e),f(t,o,e);m(e)}function n(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-operating-system",e="artifact-editor-category",a=document.getElementById(r);if(typeof a>"u"||a===null)return;a.classList.remove("is-valid");let o=document.getElementById(e);typeof o>"u"||o===null||o.classList.remove("is-valid")}function l(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-alert",e=document.getElementById(r);if(typeof e>"u"||e===null)throw new Error(`The htmx alert element ${r} is null`);if(t.detail.successful)return d(e,i);if(t.detail.failed&&t.detail.xhr)return f(t,i,e);m(e)}function c(t,i){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let r="artifact-editor-alert",e="artifact-editor-operating-system",a="artifact-editor-category",o=document.getElementById(r);if(typeof o>"u"||o===null)throw new Error(`The htmx alert element ${r} is null`);if(t.detail.successful){d(o,e),d(o,a);return}if(t.detail.failed&&t.detail.xhr)return f(t,null,o);m(o)}function E(t,i,r){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let e="artifact-editor-alert",a=document.getElementById(e);if(typeof a>"u"||a===null)throw new Error(`The htmx alert element ${e} is null`);if(console.log(`afterReset ${i} ${r}`,t.detail),t.detail.successful)return console.log(t.detail.successful,"sucessful"),d(a,r);if(t.detail.failed&&t.detail.xhr)return console.log(t.detail.failed,"failed"),f(t,r,a);m(a)}function d(t,i){if(console.log(`updateSuccess ${i}`),t.innerText="",t.classList.add("d-none"),typeof i>"u"||i===null)return;let r=document.getElementById(i);typeof r>"u"||r===null||(r.classList.remove("is-invalid"),r.classList.add("is-valid"))}function f(t,i,r){let e=t.detail.xhr;r.innerText=`${G()} Could not update the database record, ${e.responseText}.`,r.classList.remove("d-none"),i!==null&&document.getElementById(i).classList.remove("is-valid")}function H(t,i,r){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let e="artifact-editor-alert",a=document.getElementById(e);if(typeof a>"u"||a===null)throw new Error(`The htmx alert element ${e} is null`);if(t.detail.successful)return Y(t,i,a);if(t.detail.failed&&t.detail.xhr)return Z(t,r,a);m(a)}function Y(t,i,r){r.classList.add("d-none"),r.innerText="";let e=document.getElementById("artifact-editor-modal-header");switch(i){case"artifact-editor-hidden":e.classList.remove("bg-success-subtle"),e.classList.add("bg-danger-subtle");break;case"artifact-editor-public":e.classList.add("bg-success-subtle"),e.classList.remove("bg-danger-subtle");break;default:console.error(`The record success ${i} is not supported.`)}}function Z(t,i,r){let e=t.detail.xhr;r.innerText=`${G()} Could not update the database record, ${e.responseText}.`,r.classList.remove("d-none"),document.getElementById(i).checked=!0}function G(){let t=new Date,i=t.getHours(),r=t.getMinutes(),e=t.getSeconds();return r=(r<10?"0":"")+r,e=(e<10?"0":"")+e,i+":"+r+":"+e}function s(t,i,r){if(t.detail.elt===null||t.detail.elt.id!==`${i}`)return;let e=document.getElementById(r);if(typeof e>"u"||e===null)throw new Error(`The htmx alert element ${r} is null`);if(t.detail.successful)return v(t,e);if(t.detail.failed&&t.detail.xhr)return et(e,t);m(e)}function v(t,i){i.classList.add("d-none"),i.innerText="";let r="-form",e=t.target.id;if(e.slice(-r.length)==r){let o=e.replace(r,"-file");tt(t,`#${o}`);let u=e.replace(r,"-results"),h=document.getElementById(u);h&&h.classList.remove("d-none")}}function tt(t,i){let r=t.target.querySelector(i);if(r){r.value="",r.innerText="";return}console.error(`The reset file ${i} element is null`)}function et(t,i){let r=i.detail.xhr;t.innerText="Our server is not working at the moment.",console.log(`XHR request did not work, ${r.status} status: ${r.responseText}.`),t.classList.remove("d-none")}function m(t){t.innerText="Something with the browser is not working, please try again or refresh the page.",t.classList.remove("d-none")}(()=>{"use strict";M(),z(),U("paginationRange"),rt(),it()})();function rt(){let t=document.querySelectorAll('[data-bs-toggle="tooltip"]');if(t===null)throw new Error("Tooltip trigger list not found");if(typeof bootstrap.Tooltip>"u")throw new Error("Bootstrap Tooltip is undefined");let i=[...t].map(r=>new bootstrap.Tooltip(r))}function it(){let t="fluid-button",i="fluid",r=document.getElementById(t),e="box-container";document.addEventListener("DOMContentLoaded",()=>{if(document.querySelector("#fluid-artifacts")){let o=localStorage.getItem(i),u=document.getElementById(e);o==="1"&&e&&(u.classList.toggle("container-xxl"),u.classList.toggle("container-fluid"),r.textContent="Fix columns")}}),r&&r.addEventListener("click",()=>{document.getElementById(e).classList.toggle("container-xxl"),document.getElementById(e).classList.toggle("container-fluid"),localStorage.getItem(i)==="1"?(localStorage.removeItem(i),document.getElementById(t).textContent="Unlock columns"):(localStorage.setItem(i,"1"),document.getElementById(t).textContent="Fix columns")})}})();
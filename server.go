@@ -28,14 +28,21 @@ import (
 	"slices"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/Defacto2/helper"
 	"github.com/Defacto2/server/flags"
 	"github.com/Defacto2/server/handler"
+	"github.com/Defacto2/server/handler/download"
+	"github.com/Defacto2/server/handler/fulltext"
 	"github.com/Defacto2/server/handler/tidbit"
+	"github.com/Defacto2/server/internal/command"
 	"github.com/Defacto2/server/internal/config"
+	"github.com/Defacto2/server/internal/dupe"
+	"github.com/Defacto2/server/internal/hits"
 	"github.com/Defacto2/server/internal/logs"
 	"github.com/Defacto2/server/internal/postgres"
+	"github.com/Defacto2/server/model"
 	"github.com/caarlos0/env/v11"
 	_ "github.com/jackc/pgx/v5"
 )
@@ -117,6 +124,10 @@ func main() { //nolint:funlen
 		sl.Error(msg, slog.String("postgres", "could not run the version query"),
 			slog.Any("error", err))
 	}
+	if err := postgres.Create(context.Background(), db); err != nil {
+		sl.Error(msg, slog.String("postgres", "could not create the supplementary tables"),
+			slog.Any("error", err))
+	}
 
 	// Cleanup any previous temporary directories created by this application.
 	config.TmpCleaner(sl)
@@ -142,6 +153,68 @@ func main() { //nolint:funlen
 		)
 	}()
 
+	go func() {
+		if db == nil {
+			return
+		}
+		texts := fulltext.Texts{
+			Download: string(envConfig.AbsDownload),
+			Extra:    string(envConfig.AbsExtra),
+		}
+		indexed, skipped, err := model.IndexTexts(ctx, db, texts)
+		if err != nil {
+			sl.Error(msg, slog.String("fulltext", "could not index the artifact texts"),
+				slog.Any("error", err))
+			return
+		}
+		slog.Info(
+			"Indexed Texts",
+			slog.Int("Artifacts", indexed),
+			slog.Int("Unmodified", skipped),
+		)
+	}()
+
+	go func() {
+		if db == nil {
+			return
+		}
+		src := dupe.Sources{
+			Download:  string(envConfig.AbsDownload),
+			Extra:     string(envConfig.AbsExtra),
+			Thumbnail: string(envConfig.AbsThumbnail),
+		}
+		indexed, skipped, err := model.IndexHashes(ctx, db, src)
+		if err != nil {
+			sl.Error(msg, slog.String("duplicates", "could not fingerprint the artifacts"),
+				slog.Any("error", err))
+			return
+		}
+		slog.Info(
+			"Indexed Fingerprints",
+			slog.Int("Artifacts", indexed),
+			slog.Int("Unmodified", skipped),
+		)
+	}()
+
+	// the archive members extracted for the downloads are removed once expired or the cache is too large
+	go download.PruneCache(ctx, "", download.CacheInterval)
+
+	hitsDone := make(chan struct{})
+	go func() {
+		defer close(hitsDone)
+		if db == nil {
+			return
+		}
+		// the hits of the artifacts are held in memory and saved as daily totals
+		const interval = time.Minute
+		hits.Default.Run(ctx, interval, func(ctx context.Context, day time.Time, counts map[hits.Key]int64) error {
+			return model.SaveHits(ctx, db, day, counts)
+		}, func(err error) {
+			sl.Error(msg, slog.String("hits", "could not save the artifact hits"),
+				slog.Any("error", err))
+		})
+	}()
+
 	writeLn(logo)
 	printOpening(sl, serv.RecordCount)
 	h := serv.Handler(ctx, sl, db)
@@ -156,6 +229,9 @@ func main() { //nolint:funlen
 	}()
 
 	slog.Info("Shutdown", slog.String("Triggered", "Signal received"))
+	// wait for the final flush of the artifact hits before the database is closed
+	stop()
+	<-hitsDone
 }
 
 func setupWriters(envConfig config.Config, lf logs.Files) (*slog.Logger, *slog.Logger, io.Writer) {
@@ -236,6 +312,7 @@ func environmentVars(ctx context.Context, tmpLog *slog.Logger) *config.Config {
 	if i := configs.MaxProcs; i > 0 {
 		runtime.GOMAXPROCS(int(i))
 	}
+	command.UseNative(configs.NativeImages.Bool())
 	return &configs
 }
 